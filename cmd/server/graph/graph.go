@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/i18n"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"golang.org/x/text/language"
+)
+
+const (
+	TooDeep       = "graphql.too_deep"
+	TooComplex    = "graphql.too_complex"
+	InternalError = "internal_error"
+)
+
+type contextKey struct{}
+
+// requestState is what the resolvers of a request share.
+type requestState struct {
+	loaders *loaders
+	locale  language.Tag
+}
+
+// Request is a query sent to the GraphQL endpoint.
+type Request struct {
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+}
+
+// Graph runs the queries of the GraphQL endpoint, rejecting those that go
+// deeper or cost more than the limits.
+type Graph struct {
+	schema        graphql.Schema
+	services      Services
+	maxDepth      int
+	maxComplexity int
+}
+
+func NewGraph(services Services, maxDepth int, maxComplexity int) (*Graph, error) {
+	schema, err := NewSchema(services)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Graph{
+		schema:        schema,
+		services:      services,
+		maxDepth:      maxDepth,
+		maxComplexity: maxComplexity,
+	}, nil
+}
+
+// Execute runs the request once it passes the validation rules of the
+// specification and the limits. Every request gets its own loaders, so that
+// nothing is cached from one to the other.
+func (g *Graph) Execute(ctx context.Context, locale language.Tag, request Request) *graphql.Result {
+	document, err := parser.Parse(parser.ParseParams{Source: request.Query})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&g.schema, document, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	depth, complexity := Measure(g.schema, document)
+	if depth > g.maxDepth {
+		return failed(i18n.Translate(locale, TooDeep, depth, g.maxDepth))
+	}
+	if complexity > g.maxComplexity {
+		return failed(i18n.Translate(locale, TooComplex, complexity, g.maxComplexity))
+	}
+
+	state := &requestState{loaders: newLoaders(g.services), locale: locale}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        g.schema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       context.WithValue(ctx, contextKey{}, state),
+	})
+}
+
+func failed(message string) *graphql.Result {
+	return &graphql.Result{Errors: gqlerrors.FormatErrors(errors.New(message))}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(contextKey{}).(*requestState).loaders
+}
+
+// safely turns the panics of the services, raised when the database fails,
+// into the error of the field, without the details of what failed. Thunks
+// returned by the loaders are guarded the same way.
+func safely(resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (result interface{}, err error) {
+		defer recoverInternal(p.Context, &result, &err)
+
+		result, err = resolve(p)
+		if thunk, ok := result.(func() (interface{}, error)); ok {
+			return func() (value interface{}, err error) {
+				defer recoverInternal(p.Context, &value, &err)
+				return thunk()
+			}, nil
+		}
+		return result, err
+	}
+}
+
+func recoverInternal(ctx context.Context, result *interface{}, err *error) {
+	if r := recover(); r != nil {
+		*result = nil
+		*err = errors.New(i18n.Translate(ctx.Value(contextKey{}).(*requestState).locale, InternalError))
+	}
+}
@@ -0,0 +1,199 @@
+package graph_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/graph"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	locality_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/locality/mocks"
+	product_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product/mocks"
+	product_batch_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch/mocks"
+	section_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section/mocks"
+	seller_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/seller/mocks"
+	warehouse_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/text/language"
+)
+
+type serviceMocks struct {
+	warehouses *warehouse_mocks.Service
+	localities *locality_mocks.Service
+	sections   *section_mocks.Service
+	batches    *product_batch_mocks.Service
+	products   *product_mocks.Service
+	sellers    *seller_mocks.Service
+}
+
+func InitGraph(t *testing.T, maxDepth int, maxComplexity int) (*graph.Graph, serviceMocks) {
+	t.Helper()
+	m := serviceMocks{
+		warehouses: new(warehouse_mocks.Service),
+		localities: new(locality_mocks.Service),
+		sections:   new(section_mocks.Service),
+		batches:    new(product_batch_mocks.Service),
+		products:   new(product_mocks.Service),
+		sellers:    new(seller_mocks.Service),
+	}
+	g, err := graph.NewGraph(graph.Services{
+		Warehouses: m.warehouses,
+		Localities: m.localities,
+		Sections:   m.sections,
+		Batches:    m.batches,
+		Products:   m.products,
+		Sellers:    m.sellers,
+	}, maxDepth, maxComplexity)
+	assert.NoError(t, err)
+	return g, m
+}
+
+func TestLoader(t *testing.T) {
+	t.Run("Should fetch the keys loaded before the first thunk runs in a single call", func(t *testing.T) {
+		calls := make([][]int, 0)
+		loader := graph.NewLoader(func(keys []int) map[int]string {
+			calls = append(calls, keys)
+			return map[int]string{1: "one", 2: "two"}
+		})
+
+		first, second, repeated, missing := loader.Load(1), loader.Load(2), loader.Load(1), loader.Load(3)
+		one, _ := first()
+		two, _ := second()
+		again, _ := repeated()
+		none, _ := missing()
+
+		assert.Equal(t, [][]int{{1, 2, 3}}, calls)
+		assert.Equal(t, "one", one)
+		assert.Equal(t, "two", two)
+		assert.Equal(t, "one", again)
+		assert.Nil(t, none)
+	})
+	t.Run("Should not fetch the keys it already has again", func(t *testing.T) {
+		calls := make([][]int, 0)
+		loader := graph.NewLoader(func(keys []int) map[int]int {
+			calls = append(calls, keys)
+			values := make(map[int]int)
+			for _, k := range keys {
+				values[k] = k * 10
+			}
+			return values
+		})
+
+		loader.Load(1)()
+		loader.Load(1)()
+		value, _ := loader.Load(2)()
+
+		assert.Equal(t, [][]int{{1}, {2}}, calls)
+		assert.Equal(t, 20, value)
+	})
+}
+
+func TestMeasure(t *testing.T) {
+	schema, err := graph.NewSchema(graph.Services{})
+	assert.NoError(t, err)
+
+	t.Run("Should count the depth and multiply the fields under lists", func(t *testing.T) {
+		document, _ := parser.Parse(parser.ParseParams{Source: `{ warehouse(id: 1) { id sections { id batches { id } } } }`})
+
+		depth, complexity := graph.Measure(schema, document)
+
+		assert.Equal(t, 4, depth)
+		// warehouse(1) + id(1) + sections(1 + 10 * (id(1) + batches(1 + 10 * id(1))))
+		assert.Equal(t, 1+1+1+10*(1+1+10*1), complexity)
+	})
+	t.Run("Should measure the fragments where they are spread and skip introspection", func(t *testing.T) {
+		document, _ := parser.Parse(parser.ParseParams{Source: `
+			{ __schema { types { name } } product(id: 1) { ...withSeller } }
+			fragment withSeller on Product { id seller { ... on Seller { id } } }`})
+
+		depth, complexity := graph.Measure(schema, document)
+
+		assert.Equal(t, 3, depth)
+		assert.Equal(t, 4, complexity)
+	})
+}
+
+func TestExecute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should resolve the relations of every level with one call per relation", func(t *testing.T) {
+		g, m := InitGraph(t, 10, 10000)
+		m.warehouses.On("GetAll").Return([]domain.Warehouse{{ID: 1, LocalityID: 5}, {ID: 2, LocalityID: 5}})
+		m.localities.On("GetMany", []int{5}).Return([]domain.Locality{{ID: 5, LocalityName: "Palermo"}}).Once()
+		m.sections.On("GetByWarehouses", []int{1, 2}).Return([]domain.Section{{ID: 10, WarehouseID: 1}, {ID: 11, WarehouseID: 1}}).Once()
+		m.batches.On("GetBySections", []int{10, 11}).Return([]domain.ProductBatch{{ID: 100, SectionID: 10, ProductID: 7}, {ID: 101, SectionID: 11, ProductID: 7}}).Once()
+		m.products.On("GetMany", []int{7}).Return([]domain.Product{{ID: 7, SellerID: 3}}).Once()
+		m.sellers.On("GetMany", []int{3}).Return([]domain.Seller{{ID: 3, CompanyName: "Meli"}}).Once()
+
+		result := g.Execute(ctx, language.English, graph.Request{Query: `{
+			warehouses {
+				id
+				locality { locality_name }
+				sections { id batches { id product { id seller { company_name } } } }
+			}
+		}`})
+
+		assert.Empty(t, result.Errors)
+		warehouses := result.Data.(map[string]interface{})["warehouses"].([]interface{})
+		assert.Len(t, warehouses, 2)
+		assert.Equal(t, map[string]interface{}{"locality_name": "Palermo"}, warehouses[0].(map[string]interface{})["locality"])
+		assert.Len(t, warehouses[0].(map[string]interface{})["sections"], 2)
+		assert.Empty(t, warehouses[1].(map[string]interface{})["sections"])
+		m.localities.AssertExpectations(t)
+		m.sections.AssertExpectations(t)
+		m.batches.AssertExpectations(t)
+		m.products.AssertExpectations(t)
+		m.sellers.AssertExpectations(t)
+	})
+	t.Run("Should resolve an entity that does not exist to null", func(t *testing.T) {
+		g, m := InitGraph(t, 10, 10000)
+		m.products.On("Get", 9).Return((*domain.Product)(nil), apperr.NewResourceNotFound("product"))
+
+		result := g.Execute(ctx, language.English, graph.Request{
+			Query:     `query($id: Int!) { product(id: $id) { id } }`,
+			Variables: map[string]interface{}{"id": 9},
+		})
+
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, map[string]interface{}{"product": nil}, result.Data)
+	})
+	t.Run("Should reject a query deeper than the limit without running it", func(t *testing.T) {
+		g, m := InitGraph(t, 2, 10000)
+
+		result := g.Execute(ctx, language.English, graph.Request{Query: `{ warehouses { sections { id } } }`})
+
+		assert.Nil(t, result.Data)
+		assert.Equal(t, "query depth 3 exceeds the maximum of 2", result.Errors[0].Message)
+		m.warehouses.AssertNotCalled(t, "GetAll")
+	})
+	t.Run("Should reject a query more complex than the limit without running it", func(t *testing.T) {
+		g, m := InitGraph(t, 10, 20)
+
+		result := g.Execute(ctx, language.English, graph.Request{Query: `{ warehouses { id sections { id } } }`})
+
+		assert.Nil(t, result.Data)
+		assert.Contains(t, result.Errors[0].Message, "exceeds the maximum of 20")
+		m.warehouses.AssertNotCalled(t, "GetAll")
+	})
+	t.Run("Should return the errors of an invalid query", func(t *testing.T) {
+		g, _ := InitGraph(t, 10, 10000)
+
+		result := g.Execute(ctx, language.English, graph.Request{Query: `{ warehouses { unknown } }`})
+
+		assert.Nil(t, result.Data)
+		assert.NotEmpty(t, result.Errors)
+	})
+	t.Run("Should turn a failing service into an internal error of the field", func(t *testing.T) {
+		g, m := InitGraph(t, 10, 10000)
+		m.sellers.On("GetAll").Return([]domain.Seller{{ID: 3, LocalityID: 5}})
+		m.localities.On("GetMany", mock.Anything).Panic("connection refused")
+
+		result := g.Execute(ctx, language.English, graph.Request{Query: `{ sellers { id locality { id } } }`})
+
+		assert.Len(t, result.Errors, 1)
+		assert.NotContains(t, result.Errors[0].Message, "connection refused")
+		assert.Equal(t, []interface{}{map[string]interface{}{"id": 3, "locality": nil}}, result.Data.(map[string]interface{})["sellers"])
+	})
+}
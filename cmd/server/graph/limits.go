@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// ListFactor is the number of items a list is expected to hold. The cost of
+// the fields selected under a list is multiplied by it.
+const ListFactor = 10
+
+// Measure returns the depth of the deepest operation of the document, and the
+// complexity of the costliest one, where each field costs one. Introspection
+// fields are left out, so that tools can always read the schema.
+func Measure(schema graphql.Schema, document *ast.Document) (depth int, complexity int) {
+	m := measurer{schema: schema, fragments: make(map[string]*ast.FragmentDefinition)}
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			m.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		d, c := m.selections(operation.SelectionSet, schema.QueryType(), make(map[string]bool))
+		depth, complexity = max(depth, d), max(complexity, c)
+	}
+	return depth, complexity
+}
+
+type measurer struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+func (m measurer) selections(set *ast.SelectionSet, parent graphql.Type, spread map[string]bool) (depth int, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			fieldType, list := m.fieldType(parent, s.Name.Value)
+			d, c = m.selections(s.SelectionSet, fieldType, spread)
+			if list {
+				c *= ListFactor
+			}
+			d, c = d+1, c+1
+		case *ast.InlineFragment:
+			fragmentType := parent
+			if s.TypeCondition != nil {
+				fragmentType = m.schema.Type(s.TypeCondition.Name.Value)
+			}
+			d, c = m.selections(s.SelectionSet, fragmentType, spread)
+		case *ast.FragmentSpread:
+			fragment, ok := m.fragments[s.Name.Value]
+			if !ok || spread[s.Name.Value] {
+				continue
+			}
+			spread[s.Name.Value] = true
+			d, c = m.selections(fragment.SelectionSet, m.schema.Type(fragment.TypeCondition.Name.Value), spread)
+			delete(spread, s.Name.Value)
+		}
+		depth, complexity = max(depth, d), complexity+c
+	}
+	return depth, complexity
+}
+
+// fieldType returns the type of the field of parent, unwrapped from its
+// non-null and list modifiers, and whether it is a list.
+func (m measurer) fieldType(parent graphql.Type, name string) (graphql.Type, bool) {
+	object, ok := parent.(*graphql.Object)
+	if !ok {
+		return nil, false
+	}
+	field, ok := object.Fields()[name]
+	if !ok {
+		return nil, false
+	}
+
+	fieldType, list := field.Type, false
+	for {
+		switch wrapper := fieldType.(type) {
+		case *graphql.NonNull:
+			fieldType = wrapper.OfType
+		case *graphql.List:
+			fieldType, list = wrapper.OfType, true
+		default:
+			return fieldType, list
+		}
+	}
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,66 @@
+package graph
+
+import "sync"
+
+// Loader batches the lookups of a request. The keys asked for while the
+// executor resolves one level of the query are fetched together, with a
+// single call to fetch, when the first of their values is needed. Values are
+// kept for the rest of the request, so a loader must not outlive it.
+type Loader[V any] struct {
+	mu        sync.Mutex
+	fetch     func(keys []int) map[int]V
+	pending   []int
+	requested map[int]bool
+	values    map[int]V
+}
+
+func NewLoader[V any](fetch func(keys []int) map[int]V) *Loader[V] {
+	return &Loader[V]{
+		fetch:     fetch,
+		requested: make(map[int]bool),
+		values:    make(map[int]V),
+	}
+}
+
+// Load queues the key and returns the thunk the executor calls to get its
+// value, which is nil when fetch did not return the key.
+func (l *Loader[V]) Load(key int) func() (interface{}, error) {
+	l.mu.Lock()
+	if !l.requested[key] {
+		l.requested[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+			for k, value := range l.fetch(keys) {
+				l.values[k] = value
+			}
+		}
+
+		value, ok := l.values[key]
+		if !ok {
+			return nil, nil
+		}
+		return value, nil
+	}
+}
+
+// groupBy groups the items found by a batch lookup by the key they were
+// looked up with, every key holding a list even when nothing was found.
+func groupBy[T any](keys []int, items []T, key func(T) int) map[int][]T {
+	grouped := make(map[int][]T, len(keys))
+	for _, k := range keys {
+		grouped[k] = make([]T, 0)
+	}
+	for _, item := range items {
+		grouped[key(item)] = append(grouped[key(item)], item)
+	}
+	return grouped
+}
@@ -0,0 +1,266 @@
+package graph
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/locality"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+	"github.com/graphql-go/graphql"
+)
+
+// Services are the services the schema resolves its fields with.
+type Services struct {
+	Warehouses warehouse.Service
+	Localities locality.Service
+	Sections   section.Service
+	Batches    product_batch.Service
+	Products   product.Service
+	Sellers    seller.Service
+}
+
+// loaders batch the lookups of the relations between the types of a request.
+type loaders struct {
+	warehouses          *Loader[domain.Warehouse]
+	localities          *Loader[domain.Locality]
+	sectionsByWarehouse *Loader[[]domain.Section]
+	batchesBySection    *Loader[[]domain.ProductBatch]
+	products            *Loader[domain.Product]
+	sellers             *Loader[domain.Seller]
+}
+
+func newLoaders(s Services) *loaders {
+	return &loaders{
+		warehouses: NewLoader(func(ids []int) map[int]domain.Warehouse {
+			return helpers.ByID(s.Warehouses.GetMany(ids), func(w domain.Warehouse) int { return w.ID })
+		}),
+		localities: NewLoader(func(ids []int) map[int]domain.Locality {
+			return helpers.ByID(s.Localities.GetMany(ids), func(l domain.Locality) int { return l.ID })
+		}),
+		sectionsByWarehouse: NewLoader(func(ids []int) map[int][]domain.Section {
+			return groupBy(ids, s.Sections.GetByWarehouses(ids), func(sc domain.Section) int { return sc.WarehouseID })
+		}),
+		batchesBySection: NewLoader(func(ids []int) map[int][]domain.ProductBatch {
+			return groupBy(ids, s.Batches.GetBySections(ids), func(pb domain.ProductBatch) int { return pb.SectionID })
+		}),
+		products: NewLoader(func(ids []int) map[int]domain.Product {
+			return helpers.ByID(s.Products.GetMany(ids), func(p domain.Product) int { return p.ID })
+		}),
+		sellers: NewLoader(func(ids []int) map[int]domain.Seller {
+			return helpers.ByID(s.Sellers.GetMany(ids), func(sl domain.Seller) int { return sl.ID })
+		}),
+	}
+}
+
+// NewSchema builds the schema over the domain types. Their fields keep the
+// names of the REST API, and their relations are resolved through the
+// loaders of the request.
+func NewSchema(s Services) (graphql.Schema, error) {
+	localityType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Locality",
+		Fields: graphql.Fields{
+			"id":            &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"locality_name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"province_id":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	sellerType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Seller",
+		Fields: graphql.Fields{
+			"id":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"cid":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"company_name": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"address":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"telephone":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"locality_id":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"locality": &graphql.Field{
+				Type: localityType,
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).localities.Load(p.Source.(domain.Seller).LocalityID), nil
+				}),
+			},
+		},
+	})
+
+	productType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Product",
+		Fields: graphql.Fields{
+			"id":                               &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"description":                      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"expiration_rate":                  &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"freezing_rate":                    &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"height":                           &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"length":                           &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"netweight":                        &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"product_code":                     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"recommended_freezing_temperature": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"width":                            &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"product_type_id":                  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"seller_id":                        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"seller": &graphql.Field{
+				Type: sellerType,
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).sellers.Load(p.Source.(domain.Product).SellerID), nil
+				}),
+			},
+		},
+	})
+
+	batchType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProductBatch",
+		Fields: graphql.Fields{
+			"id":                  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"batch_number":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"current_quantity":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"current_temperature": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"due_date":            &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"initial_quantity":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"manufacturing_date":  &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"manufacturing_hour":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"minimum_temperature": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"product_id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"section_id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"status":              &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"product": &graphql.Field{
+				Type: productType,
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).products.Load(p.Source.(domain.ProductBatch).ProductID), nil
+				}),
+			},
+		},
+	})
+
+	sectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Section",
+		Fields: graphql.Fields{
+			"id":                  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"section_number":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"current_temperature": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"minimum_temperature": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"current_capacity":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"minimum_capacity":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"maximum_capacity":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"warehouse_id":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"product_type_id":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"batches": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(batchType))),
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).batchesBySection.Load(p.Source.(domain.Section).ID), nil
+				}),
+			},
+		},
+	})
+
+	warehouseType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Warehouse",
+		Fields: graphql.Fields{
+			"id":                  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"address":             &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"telephone":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"warehouse_code":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"minimum_capacity":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"minimum_temperature": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"locality_id":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"locality": &graphql.Field{
+				Type: localityType,
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).localities.Load(p.Source.(domain.Warehouse).LocalityID), nil
+				}),
+			},
+			"sections": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(sectionType))),
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).sectionsByWarehouse.Load(p.Source.(domain.Warehouse).ID), nil
+				}),
+			},
+		},
+	})
+
+	sectionType.AddFieldConfig("warehouse", &graphql.Field{
+		Type: warehouseType,
+		Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+			return loadersFrom(p.Context).warehouses.Load(p.Source.(domain.Section).WarehouseID), nil
+		}),
+	})
+
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+	}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"warehouses": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(warehouseType))),
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return s.Warehouses.GetAll(), nil
+				}),
+			},
+			"warehouse": &graphql.Field{
+				Type: warehouseType,
+				Args: idArgs,
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return found(s.Warehouses.Get(p.Args["id"].(int)))
+				}),
+			},
+			"sections": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(sectionType))),
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return s.Sections.GetAll(), nil
+				}),
+			},
+			"section": &graphql.Field{
+				Type: sectionType,
+				Args: idArgs,
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return found(s.Sections.Get(p.Args["id"].(int)))
+				}),
+			},
+			"products": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productType))),
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return s.Products.GetAll(), nil
+				}),
+			},
+			"product": &graphql.Field{
+				Type: productType,
+				Args: idArgs,
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return found(s.Products.Get(p.Args["id"].(int)))
+				}),
+			},
+			"sellers": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(sellerType))),
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return s.Sellers.GetAll(), nil
+				}),
+			},
+			"seller": &graphql.Field{
+				Type: sellerType,
+				Args: idArgs,
+				Resolve: safely(func(p graphql.ResolveParams) (interface{}, error) {
+					return found(s.Sellers.Get(p.Args["id"].(int)))
+				}),
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// found dereferences the entity a service looked up by id, resolving the
+// ones that do not exist to null rather than to an error.
+func found[T any](entity *T, err error) (interface{}, error) {
+	if err != nil {
+		if apperr.Is[*apperr.ResourceNotFound](err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return *entity, nil
+}
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
@@ -11,10 +10,6 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-const (
-	invalidId = "o id '%s' é inválido"
-)
-
 type CreateBuyerRequest struct {
 	CardNumberID *string `json:"card_number_id" binding:"required"`
 	FirstName    *string `json:"first_name" binding:"required"`
@@ -29,20 +24,6 @@ func (r CreateBuyerRequest) ToBuyer() domain.Buyer {
 	}
 }
 
-type UpdateBuyerRequest struct {
-	CardNumberID *string `json:"card_number_id"`
-	FirstName    *string `json:"first_name"`
-	LastName     *string `json:"last_name"`
-}
-
-func (r UpdateBuyerRequest) ToUpdateBuyer() domain.UpdateBuyer {
-	return domain.UpdateBuyer{
-		CardNumberID: r.CardNumberID,
-		FirstName:    r.FirstName,
-		LastName:     r.LastName,
-	}
-}
-
 type Buyer struct {
 	buyerService buyer.Service
 }
@@ -60,9 +41,9 @@ func NewBuyer(b buyer.Service) *Buyer {
 // @Produce json
 // @Param id path int true "Buyer id"
 // @Success 200 {object} domain.Buyer "Obtained buyer"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /buyers/{id} [get]
 func (b *Buyer) Get() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -71,7 +52,7 @@ func (b *Buyer) Get() gin.HandlerFunc {
 		buyer, err := b.buyerService.Get(id)
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -85,11 +66,18 @@ func (b *Buyer) Get() gin.HandlerFunc {
 // @Description Returns a collection of existing buyers.
 // @Tags Buyers
 // @Produce json
+// @Param ids query string false "Comma separated ids to get, instead of the whole collection"
 // @Success 200 {object} domain.Buyer "List of all buyers"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /buyers [get]
 func (b *Buyer) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if ids, ok := requestedIDs(c); ok {
+			web.Success(c, http.StatusOK, b.buyerService.GetMany(ids))
+			return
+		}
+
 		buyers := b.buyerService.GetAll()
 		web.Success(c, http.StatusOK, buyers)
 	}
@@ -103,9 +91,9 @@ func (b *Buyer) GetAll() gin.HandlerFunc {
 // @Produce json
 // @Param request body domain.Buyer true "Buyer data"
 // @Success 201 {object} domain.Buyer "Created buyer"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /buyers [post]
 func (b *Buyer) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -115,7 +103,7 @@ func (b *Buyer) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -124,36 +112,61 @@ func (b *Buyer) Create() gin.HandlerFunc {
 	}
 }
 
+// Import godoc
+// @Summary Import buyers
+// @Description Create buyers in bulk from a CSV file, whose header names the fields of the buyer, or from NDJSON, one buyer per line.
+// @Description Each row is validated as on creation, and a card number repeated in the file is rejected.
+// @Description By default nothing is saved if any row is rejected; with mode best_effort the valid rows are saved.
+// @Description With dry_run the rows are only checked.
+// @Tags Buyers
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param dry_run query bool false "Only check the rows"
+// @Param mode query string false "Commit mode" Enums(all_or_nothing, best_effort)
+// @Success 200 {object} domain.ImportReport "Report of the dry run"
+// @Success 201 {object} domain.ImportReport "Report of the import"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported file format"
+// @Failure 422 {object} domain.ImportReport "Report of the rejected import"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /buyers/import [post]
+func (b *Buyer) Import() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondImport(c, CreateBuyerRequest.ToBuyer, b.buyerService.Import)
+	}
+}
+
 // Update godoc
 // @Summary Update a buyer
-// @Description Update an existent buyer based on the provided id and JSON payload.
+// @Description Update an existent buyer by applying a JSON Merge Patch or a JSON Patch to it.
 // @Tags Buyers
-// @Accept json
+// @Accept json,application/merge-patch+json,application/json-patch+json
 // @Produce json
 // @Param id path int true "Buyer ID"
-// @Param buyer body domain.UpdateBuyer true "Buyer data to update"
+// @Param buyer body object true "Merge patch or JSON patch"
 // @Success 200 {object} domain.Buyer "Updated buyer"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported patch format"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /buyers/{id} [patch]
 func (b *Buyer) Update() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.GetInt("Id")
-		request := c.MustGet(RequestParamContext).(UpdateBuyerRequest)
+		current := c.MustGet(CurrentParamContext).(*domain.Buyer)
+		request := c.MustGet(RequestParamContext).(CreateBuyerRequest)
 
-		updated, err := b.buyerService.Update(id, request.ToUpdateBuyer())
+		updated, err := b.buyerService.Update(*current, request.ToBuyer())
 
 		if err != nil {
-			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -168,9 +181,9 @@ func (b *Buyer) Update() gin.HandlerFunc {
 // @Tags Buyers
 // @Param id path int true "Buyer ID"
 // @Success 204 "No content"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /buyers/{id} [delete]
 func (b *Buyer) Delete() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -180,7 +193,7 @@ func (b *Buyer) Delete() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -194,36 +207,52 @@ func (b *Buyer) Delete() gin.HandlerFunc {
 // @Description Purchase Orders count by buyer.
 // @Description If no query param is given, bring the report of all purchase orders for all buyers.
 // @Description If a buyer id is specified, bring the amount of purchase orders for this buyer.
+// @Description The from and to dates filter the purchase orders by order date.
+// @Description If group_by is given, it brings the number of purchase orders by day, week or month instead.
+// @Description If top is given, it brings only the buyers with the most purchase orders.
 // @Tags Buyers
 // @Produce json
 // @Param id query int false "Buyer ID"
+// @Param from query string false "First day of the purchase orders, yyyy-mm-dd"
+// @Param to query string false "Last day of the purchase orders, yyyy-mm-dd"
+// @Param group_by query string false "Period of the time series" Enums(day, week, month)
+// @Param top query int false "Number of buyers to rank"
 // @Success 200 {object} []domain.PurchasesByBuyerReport "List of purchase Orders by Buyer"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Success 200 {object} []domain.ReportPeriod "Report of purchase orders by period"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /buyers/report-purchase-orders [get]
 func (b *Buyer) ReportPurchases() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		idParam := c.Request.URL.Query().Get("id")
+		query := c.MustGet(QueryParamContext).(ReportQuery)
+		dates := query.ToDateRange()
 
-		if idParam == "" {
-			result := b.buyerService.CountPurchasesByAllBuyers()
-			web.Success(c, http.StatusOK, result)
+		if query.GroupBy != nil {
+			periods, err := b.buyerService.CountPurchasesByPeriod(query.ID, *query.GroupBy, dates)
+
+			if err != nil {
+				if apperr.Is[*apperr.ResourceNotFound](err) {
+					web.ErrorFrom(c, http.StatusNotFound, err)
+					return
+				}
+			}
+
+			web.Success(c, http.StatusOK, periods)
 			return
 		}
 
-		id, err := strconv.Atoi(idParam)
-
-		if err != nil {
-			web.Error(c, http.StatusBadRequest, InvalidId, idParam)
+		if query.ID == nil {
+			result := b.buyerService.CountPurchasesByAllBuyers(dates, topOrAll(query.Top))
+			web.Success(c, http.StatusOK, result)
 			return
 		}
 
-		purchases, err := b.buyerService.CountPurchasesByBuyer(id)
+		purchases, err := b.buyerService.CountPurchasesByBuyer(*query.ID, dates)
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -76,6 +76,20 @@ func TestGetBuyer(t *testing.T) {
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
 
+	t.Run("Should return the buyers with the given ids", func(t *testing.T) {
+		server, service, controller := InitBuyerServer(t)
+
+		server.GET(DefinePath(ResourceBuyerUri), middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
+		request, response := MakeRequest("GET", DefinePath(ResourceBuyerUri)+"?ids=1,2", "")
+
+		service.On("GetMany", []int{1, 2}).Return([]domain.Buyer{{ID: 1}, {ID: 2}})
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		service.AssertNotCalled(t, "GetAll")
+	})
+
 	t.Run("Should return bad request error when id is invalid", func(t *testing.T) {
 		server, _, controller := InitBuyerServer(t)
 
@@ -120,41 +134,43 @@ func TestGetBuyer(t *testing.T) {
 }
 
 func TestUpdateBuyer(t *testing.T) {
-	requestObject := handler.UpdateBuyerRequest{
+	requestObject := handler.CreateBuyerRequest{
 		CardNumberID: &mockedBuyer.CardNumberID,
 		FirstName:    &mockedBuyer.FirstName,
 		LastName:     &mockedBuyer.LastName,
 	}
 
-	t.Run("Should return not found error", func(t *testing.T) {
+	t.Run("Should return conflict error when the buyer was modified", func(t *testing.T) {
 		server, service, controller := InitBuyerServer(t)
 
 		id := 1
+		current := domain.Buyer{ID: id}
 
-		server.PATCH(DefinePath(ResourceBuyerUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceBuyerUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceBuyerUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Buyer
 		service.On(
-			"Update", id, requestObject.ToUpdateBuyer()).
-			Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+			"Update", current, requestObject.ToBuyer()).
+			Return(serviceReturn, apperr.NewIncompatibleResource(ResourceModified))
 
 		server.ServeHTTP(response, request)
 
-		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Equal(t, http.StatusConflict, response.Code)
 	})
 
 	t.Run("Should return conflict error", func(t *testing.T) {
 		server, service, controller := InitBuyerServer(t)
 
 		id := 1
+		current := domain.Buyer{ID: id}
 
-		server.PATCH(DefinePath(ResourceBuyerUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceBuyerUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceBuyerUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Buyer
 		service.On(
-			"Update", id, requestObject.ToUpdateBuyer()).
+			"Update", current, requestObject.ToBuyer()).
 			Return(serviceReturn, apperr.NewResourceAlreadyExists(ResourceAlreadyExists))
 
 		server.ServeHTTP(response, request)
@@ -166,12 +182,13 @@ func TestUpdateBuyer(t *testing.T) {
 		server, service, controller := InitBuyerServer(t)
 
 		id := 1
+		current := domain.Buyer{ID: id}
 
-		server.PATCH(DefinePath(ResourceBuyerUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceBuyerUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceBuyerUri, id), CreateBody(requestObject))
 
 		service.On(
-			"Update", id, requestObject.ToUpdateBuyer()).
+			"Update", current, requestObject.ToBuyer()).
 			Return(&mockedBuyer, nil)
 
 		server.ServeHTTP(response, request)
@@ -216,10 +233,10 @@ func TestReportPurchasesByBuyer(t *testing.T) {
 	t.Run("Should return purchases count report of all buyers", func(t *testing.T) {
 		server, service, controller := InitBuyerServer(t)
 
-		server.GET(DefinePath(ReportPurchasesUri), controller.ReportPurchases())
+		server.GET(DefinePath(ReportPurchasesUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportPurchases())
 		request, response := MakeRequest("GET", DefinePath(ReportPurchasesUri), "")
 
-		service.On("CountPurchasesByAllBuyers").Return([]domain.PurchasesByBuyerReport{}, nil)
+		service.On("CountPurchasesByAllBuyers", domain.DateRange{}, 0).Return([]domain.PurchasesByBuyerReport{}, nil)
 
 		server.ServeHTTP(response, request)
 
@@ -229,7 +246,7 @@ func TestReportPurchasesByBuyer(t *testing.T) {
 	t.Run("Should return invalid id error", func(t *testing.T) {
 		server, _, controller := InitBuyerServer(t)
 
-		server.GET(DefinePath(ReportPurchasesUri), controller.ReportPurchases())
+		server.GET(DefinePath(ReportPurchasesUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportPurchases())
 		request, response := MakeRequest("GET", DefinePath(ReportPurchasesUri)+"?id=abc", "")
 
 		server.ServeHTTP(response, request)
@@ -240,12 +257,12 @@ func TestReportPurchasesByBuyer(t *testing.T) {
 	t.Run("Should return not found error", func(t *testing.T) {
 		server, service, controller := InitBuyerServer(t)
 
-		server.GET(DefinePath(ReportPurchasesUri), controller.ReportPurchases())
+		server.GET(DefinePath(ReportPurchasesUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportPurchases())
 		request, response := MakeRequest("GET", DefinePath(ReportPurchasesUri)+"?id=1", "")
 
 		buyerID := 1
 		var serviceReturn *domain.PurchasesByBuyerReport
-		service.On("CountPurchasesByBuyer", buyerID).Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+		service.On("CountPurchasesByBuyer", buyerID, domain.DateRange{}).Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
 
 		server.ServeHTTP(response, request)
 
@@ -255,7 +272,7 @@ func TestReportPurchasesByBuyer(t *testing.T) {
 	t.Run("Should return purchases report by buyer", func(t *testing.T) {
 		server, service, controller := InitBuyerServer(t)
 
-		server.GET(DefinePath(ReportPurchasesUri), controller.ReportPurchases())
+		server.GET(DefinePath(ReportPurchasesUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportPurchases())
 		request, response := MakeRequest("GET", DefinePath(ReportPurchasesUri)+"?id=1", "")
 
 		buyerID := 1
@@ -266,12 +283,27 @@ func TestReportPurchasesByBuyer(t *testing.T) {
 			LastName: "Sobrenome",
 			PurchasesCount: 1,
 		}
-		service.On("CountPurchasesByBuyer", buyerID).Return(&serviceReturn, nil)
+		service.On("CountPurchasesByBuyer", buyerID, domain.DateRange{}).Return(&serviceReturn, nil)
 
 		server.ServeHTTP(response, request)
 
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
+
+	t.Run("Should return not found error when the id of the time series does not exist", func(t *testing.T) {
+		server, service, controller := InitBuyerServer(t)
+
+		server.GET(DefinePath(ReportPurchasesUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportPurchases())
+		request, response := MakeRequest("GET", DefinePath(ReportPurchasesUri)+"?group_by=day&id=1", "")
+
+		id := 1
+		var periods []domain.ReportPeriod
+		service.On("CountPurchasesByPeriod", &id, domain.PeriodDay, domain.DateRange{}).Return(periods, apperr.NewResourceNotFound(ResourceNotFound))
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
 }
 
 func InitBuyerServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.Buyer) {
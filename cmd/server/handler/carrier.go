@@ -46,9 +46,9 @@ func NewCarrier(c carrier.Service) *Carrier {
 // @Produce json
 // @Param request body CreateCarrierRequest true "Carrier to be created"
 // @Success 201 {object} domain.Carrier "Created carrier"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /carriers [post]
 func (c *Carrier) Create() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -58,11 +58,11 @@ func (c *Carrier) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(ctx, http.StatusConflict, err.Error())
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(ctx, http.StatusConflict, err.Error())
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 		}
@@ -14,6 +14,7 @@ const (
 	BaseUri               = "/api/v1"
 	ResourceAlreadyExists = "resource already exists"
 	ResourceNotFound      = "resource not found"
+	ResourceModified      = "resource modified"
 )
 
 func CreateServer() *gin.Engine {
@@ -47,3 +48,10 @@ func ValidationMiddleware(requestObject interface{}) gin.HandlerFunc {
 		ctx.Set("Request", requestObject)
 	}
 }
+
+func PatchMiddleware(current interface{}, requestObject interface{}) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set("Current", current)
+		ctx.Set("Request", requestObject)
+	}
+}
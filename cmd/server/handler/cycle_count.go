@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/cycle_count"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type CycleCount struct {
+	service cycle_count.Service
+}
+
+type CreateCycleCountRequest struct {
+	EmployeeID *int `json:"employee_id"`
+}
+
+type CycleCountEmployeeRequest struct {
+	EmployeeID *int `json:"employee_id" binding:"required"`
+}
+
+type CycleCountEntryRequest struct {
+	ProductBatchID  *int `json:"product_batch_id" binding:"required"`
+	CountedQuantity *int `json:"counted_quantity" binding:"required,gte=0"`
+}
+
+type SubmitCycleCountRequest struct {
+	EmployeeID *int                     `json:"employee_id" binding:"required"`
+	Counts     []CycleCountEntryRequest `json:"counts" binding:"required,min=1,dive"`
+}
+
+func (r SubmitCycleCountRequest) ToCycleCountEntries() []domain.CycleCountEntry {
+	entries := make([]domain.CycleCountEntry, 0, len(r.Counts))
+	for _, c := range r.Counts {
+		entries = append(entries, domain.CycleCountEntry{
+			ProductBatchID:  *c.ProductBatchID,
+			CountedQuantity: *c.CountedQuantity,
+		})
+	}
+	return entries
+}
+
+type CycleCountVarianceQuery struct {
+	WarehouseID *int `form:"warehouse_id"`
+}
+
+func NewCycleCount(s cycle_count.Service) *CycleCount {
+	return &CycleCount{
+		service: s,
+	}
+}
+
+// Create godoc
+// @Summary Generate a cycle count of a section
+// @Description Create a pending count with one line per batch of the section, expecting its current quantity.
+// @Description The count can be assigned right away to an employee of the section warehouse.
+// @Tags Cycle Counts
+// @Accept json
+// @Produce json
+// @Param id path int true "Section ID"
+// @Param request body CreateCycleCountRequest true "Assigned employee"
+// @Success 201 {object} domain.CycleCount "Created cycle count"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /sections/{id}/cycle-counts [post]
+func (cc *CycleCount) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+		request := ctx.MustGet(RequestParamContext).(CreateCycleCountRequest)
+
+		count, err := cc.service.Create(id, request.EmployeeID)
+		respondCycleCount(ctx, http.StatusCreated, count, err)
+	}
+}
+
+// Get godoc
+// @Summary Get a cycle count
+// @Description Return the count with its lines and, once submitted, their variance.
+// @Tags Cycle Counts
+// @Produce json
+// @Param id path int true "Cycle count ID"
+// @Success 200 {object} domain.CycleCount "Cycle count"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /cycle-counts/{id} [get]
+func (cc *CycleCount) Get() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+
+		count, err := cc.service.Get(id)
+		respondCycleCount(ctx, http.StatusOK, count, err)
+	}
+}
+
+// Assign godoc
+// @Summary Assign a cycle count
+// @Description Give a pending count to an employee of the section warehouse.
+// @Tags Cycle Counts
+// @Accept json
+// @Produce json
+// @Param id path int true "Cycle count ID"
+// @Param request body CycleCountEmployeeRequest true "Assigned employee"
+// @Success 200 {object} domain.CycleCount "Assigned cycle count"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /cycle-counts/{id}/assign [post]
+func (cc *CycleCount) Assign() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+		request := ctx.MustGet(RequestParamContext).(CycleCountEmployeeRequest)
+
+		count, err := cc.service.Assign(id, *request.EmployeeID)
+		respondCycleCount(ctx, http.StatusOK, count, err)
+	}
+}
+
+// Submit godoc
+// @Summary Submit the counted quantities
+// @Description Store the quantity counted for every batch of a pending count, expecting the quantities the batches hold on submission. Only the assigned employee can submit it.
+// @Tags Cycle Counts
+// @Accept json
+// @Produce json
+// @Param id path int true "Cycle count ID"
+// @Param request body SubmitCycleCountRequest true "Counted quantities"
+// @Success 200 {object} domain.CycleCount "Submitted cycle count"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /cycle-counts/{id}/submit [post]
+func (cc *CycleCount) Submit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+		request := ctx.MustGet(RequestParamContext).(SubmitCycleCountRequest)
+
+		count, err := cc.service.Submit(id, *request.EmployeeID, request.ToCycleCountEntries())
+		respondCycleCount(ctx, http.StatusOK, count, err)
+	}
+}
+
+// Approve godoc
+// @Summary Approve a cycle count
+// @Description Post the variance of every counted batch to the stock ledger as an adjustment. Units found by the count must fit in the section.
+// @Description The reviewer must be a supervisor of the warehouse of the section and another employee than the one who counted.
+// @Tags Cycle Counts
+// @Accept json
+// @Produce json
+// @Param id path int true "Cycle count ID"
+// @Param request body CycleCountEmployeeRequest true "Reviewing employee"
+// @Success 200 {object} domain.CycleCount "Approved cycle count"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /cycle-counts/{id}/approve [post]
+func (cc *CycleCount) Approve() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+		request := ctx.MustGet(RequestParamContext).(CycleCountEmployeeRequest)
+
+		count, err := cc.service.Approve(id, *request.EmployeeID)
+		respondCycleCount(ctx, http.StatusOK, count, err)
+	}
+}
+
+// Reject godoc
+// @Summary Reject a cycle count
+// @Description Close a submitted count without adjusting the stock. The reviewer must be a supervisor of the warehouse of the section.
+// @Tags Cycle Counts
+// @Accept json
+// @Produce json
+// @Param id path int true "Cycle count ID"
+// @Param request body CycleCountEmployeeRequest true "Reviewing employee"
+// @Success 200 {object} domain.CycleCount "Rejected cycle count"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /cycle-counts/{id}/reject [post]
+func (cc *CycleCount) Reject() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+		request := ctx.MustGet(RequestParamContext).(CycleCountEmployeeRequest)
+
+		count, err := cc.service.Reject(id, *request.EmployeeID)
+		respondCycleCount(ctx, http.StatusOK, count, err)
+	}
+}
+
+// VarianceReport godoc
+// @Summary Report the cycle count variance by warehouse
+// @Description Sum the expected and counted quantities of the approved counts of each warehouse,
+// @Description with their net and absolute variance and the percentage of lines counted without variance.
+// @Tags Reports
+// @Produce json
+// @Param warehouse_id query int false "Warehouse ID"
+// @Success 200 {object} []domain.CycleCountVariance "Variance by warehouse"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /reports/cycle-count-variance [get]
+func (cc *CycleCount) VarianceReport() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query := ctx.MustGet(QueryParamContext).(CycleCountVarianceQuery)
+
+		web.Success(ctx, http.StatusOK, cc.service.VarianceReport(query.WarehouseID))
+	}
+}
+
+func respondCycleCount(ctx *gin.Context, status int, count *domain.CycleCount, err error) {
+	if err != nil {
+		if apperr.Is[*apperr.ResourceNotFound](err) {
+			web.ErrorFrom(ctx, http.StatusNotFound, err)
+			return
+		}
+		if apperr.Is[*apperr.DependentResourceNotFound](err) {
+			web.ErrorFrom(ctx, http.StatusConflict, err)
+			return
+		}
+		if apperr.Is[*apperr.IncompatibleResource](err) {
+			web.ErrorFrom(ctx, http.StatusConflict, err)
+			return
+		}
+	}
+
+	web.Success(ctx, status, count)
+}
@@ -0,0 +1,191 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/cycle_count/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ResourceCycleCountsUri = "/cycle-counts"
+)
+
+var (
+	mockedCycleCount = &domain.CycleCount{
+		ID:        3,
+		SectionID: 1,
+		Status:    domain.CycleCountPending,
+		Lines:     []domain.CycleCountLine{{ID: 1, ProductBatchID: 1, ExpectedQuantity: 10}},
+	}
+)
+
+func TestCreateCycleCount(t *testing.T) {
+	route := DefinePath(ResourceSectionsUri) + "/:id/cycle-counts"
+	path := DefinePathWithId(ResourceSectionsUri, 1) + "/cycle-counts"
+	employeeID := 7
+	requestObject := handler.CreateCycleCountRequest{EmployeeID: &employeeID}
+
+	t.Run("Should return the created cycle count", func(t *testing.T) {
+		server, service, controller := InitCycleCountServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Create())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		service.On("Create", 1, &employeeID).Return(mockedCycleCount, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+	t.Run("Should return not found error when the section does not exist", func(t *testing.T) {
+		server, service, controller := InitCycleCountServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Create())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		var count *domain.CycleCount
+		service.On("Create", 1, &employeeID).Return(count, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+	t.Run("Should return conflict error when the employee works in another warehouse", func(t *testing.T) {
+		server, service, controller := InitCycleCountServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Create())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		var count *domain.CycleCount
+		service.On("Create", 1, &employeeID).Return(count, apperr.NewIncompatibleResource(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestGetCycleCount(t *testing.T) {
+	t.Run("Should return the cycle count", func(t *testing.T) {
+		server, service, controller := InitCycleCountServer(t)
+
+		server.GET(DefinePath(ResourceCycleCountsUri)+"/:id", controller.Get())
+		request, response := MakeRequest("GET", DefinePathWithId(ResourceCycleCountsUri, 3), "")
+
+		service.On("Get", 3).Return(mockedCycleCount, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":{"id":3,"section_id":1,"employee_id":null,"status":"pending","reviewed_by":null,
+			"created_at":"0001-01-01T00:00:00Z","submitted_at":null,"reviewed_at":null,
+			"lines":[{"id":1,"product_batch_id":1,"expected_quantity":10,"counted_quantity":null,"variance":null}]}}`, response.Body.String())
+	})
+	t.Run("Should return not found error when the count does not exist", func(t *testing.T) {
+		server, service, controller := InitCycleCountServer(t)
+
+		server.GET(DefinePath(ResourceCycleCountsUri)+"/:id", controller.Get())
+		request, response := MakeRequest("GET", DefinePathWithId(ResourceCycleCountsUri, 3), "")
+
+		var count *domain.CycleCount
+		service.On("Get", 3).Return(count, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestSubmitCycleCount(t *testing.T) {
+	route := DefinePath(ResourceCycleCountsUri) + "/:id/submit"
+	path := DefinePathWithId(ResourceCycleCountsUri, 3) + "/submit"
+	employeeID, batchID, quantity := 7, 1, 8
+	requestObject := handler.SubmitCycleCountRequest{
+		EmployeeID: &employeeID,
+		Counts:     []handler.CycleCountEntryRequest{{ProductBatchID: &batchID, CountedQuantity: &quantity}},
+	}
+
+	t.Run("Should return the submitted cycle count", func(t *testing.T) {
+		server, service, controller := InitCycleCountServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Submit())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		service.On("Submit", 3, 7, requestObject.ToCycleCountEntries()).Return(mockedCycleCount, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should return conflict error when the count is incomplete", func(t *testing.T) {
+		server, service, controller := InitCycleCountServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Submit())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		var count *domain.CycleCount
+		service.On("Submit", 3, 7, requestObject.ToCycleCountEntries()).Return(count, apperr.NewIncompatibleResource(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestApproveCycleCount(t *testing.T) {
+	route := DefinePath(ResourceCycleCountsUri) + "/:id/approve"
+	path := DefinePathWithId(ResourceCycleCountsUri, 3) + "/approve"
+	reviewerID := 9
+	requestObject := handler.CycleCountEmployeeRequest{EmployeeID: &reviewerID}
+
+	t.Run("Should return the approved cycle count", func(t *testing.T) {
+		server, service, controller := InitCycleCountServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Approve())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		service.On("Approve", 3, 9).Return(mockedCycleCount, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should return conflict error when the reviewer does not exist", func(t *testing.T) {
+		server, service, controller := InitCycleCountServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Approve())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		var count *domain.CycleCount
+		service.On("Approve", 3, 9).Return(count, apperr.NewDependentResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestCycleCountVarianceReport(t *testing.T) {
+	t.Run("Should return the variance of the warehouse", func(t *testing.T) {
+		server, service, controller := InitCycleCountServer(t)
+
+		server.GET(DefinePath("/reports/cycle-count-variance"), middleware.QueryValidation[handler.CycleCountVarianceQuery](), controller.VarianceReport())
+		request, response := MakeRequest("GET", DefinePath("/reports/cycle-count-variance")+"?warehouse_id=2", "")
+
+		warehouseID := 2
+		report := []domain.CycleCountVariance{{WarehouseID: 2, Counts: 1, Lines: 2, ExpectedQuantity: 15, CountedQuantity: 13, NetVariance: -2, AbsoluteVariance: 2, Accuracy: 50}}
+		service.On("VarianceReport", &warehouseID).Return(report)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":[{"warehouse_id":2,"counts":1,"lines":2,"expected_quantity":15,"counted_quantity":13,
+			"net_variance":-2,"absolute_variance":2,"accuracy":50}]}`, response.Body.String())
+	})
+}
+
+func InitCycleCountServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.CycleCount) {
+	t.Helper()
+	server := CreateServer()
+	server.Use(middleware.IdValidation())
+	service := new(mocks.Service)
+	controller := handler.NewCycleCount(service)
+	return server, service, controller
+}
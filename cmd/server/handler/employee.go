@@ -2,7 +2,6 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee"
@@ -20,31 +19,22 @@ type CreateEmployeeRequest struct {
 	FirstName    *string `json:"first_name" binding:"required"`
 	LastName     *string `json:"last_name" binding:"required"`
 	WarehouseID  *int    `json:"warehouse_id" binding:"required"`
+	IsSupervisor *bool   `json:"is_supervisor"`
 }
 
 func (r CreateEmployeeRequest) ToEmployee() domain.Employee {
+	isSupervisor := false
+	if r.IsSupervisor != nil {
+		isSupervisor = *r.IsSupervisor
+	}
+
 	return domain.Employee{
 		ID:           0,
 		CardNumberID: *r.CardNumberID,
 		FirstName:    *r.FirstName,
 		LastName:     *r.LastName,
 		WarehouseID:  *r.WarehouseID,
-	}
-}
-
-type UpdateEmployeeRequest struct {
-	CardNumberID *string `json:"card_number_id"`
-	FirstName    *string `json:"first_name"`
-	LastName     *string `json:"last_name"`
-	WarehouseID  *int    `json:"warehouse_id"`
-}
-
-func (r UpdateEmployeeRequest) ToUpdateEmployee() domain.UpdateEmployee {
-	return domain.UpdateEmployee{
-		CardNumberID: r.CardNumberID,
-		FirstName:    r.FirstName,
-		LastName:     r.LastName,
-		WarehouseID:  r.WarehouseID,
+		IsSupervisor: isSupervisor,
 	}
 }
 
@@ -59,11 +49,18 @@ func NewEmployee(e employee.Service) *Employee {
 // @Description Return a collection of employees
 // @Tags Employees
 // @Produce json
+// @Param ids query string false "Comma separated ids to get, instead of the whole collection"
 // @Success 200 {object} []domain.Employee "Employee"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /employees [get]
 func (e *Employee) GetAll() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if ids, ok := requestedIDs(ctx); ok {
+			web.Success(ctx, http.StatusOK, e.service.GetMany(ids))
+			return
+		}
+
 		employees := e.service.GetAll()
 		web.Success(ctx, http.StatusOK, employees)
 	}
@@ -76,9 +73,9 @@ func (e *Employee) GetAll() gin.HandlerFunc {
 // @Produce json
 // @Param id path int true "Employee id"
 // @Success 200 {object} domain.Employee "Obtained Employee"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /employees/{id} [get]
 func (e *Employee) Get() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -88,7 +85,7 @@ func (e *Employee) Get() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(ctx, http.StatusNotFound, err.Error())
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -105,9 +102,9 @@ func (e *Employee) Get() gin.HandlerFunc {
 // @Produce json
 // @Param request body domain.Employee true "Employee data"
 // @Success 201 {object} domain.Employee "Created employee"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /employees [post]
 func (e *Employee) Create() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -117,11 +114,11 @@ func (e *Employee) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(ctx, http.StatusConflict, err.Error())
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(ctx, http.StatusConflict, err.Error())
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 		}
@@ -130,41 +127,66 @@ func (e *Employee) Create() gin.HandlerFunc {
 	}
 }
 
+// Import godoc
+// @Summary Import employees
+// @Description Create employees in bulk from a CSV file, whose header names the fields of the employee, or from NDJSON, one employee per line.
+// @Description Each row is validated as on creation, and a card number repeated in the file is rejected.
+// @Description By default nothing is saved if any row is rejected; with mode best_effort the valid rows are saved.
+// @Description With dry_run the rows are only checked.
+// @Tags Employees
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param dry_run query bool false "Only check the rows"
+// @Param mode query string false "Commit mode" Enums(all_or_nothing, best_effort)
+// @Success 200 {object} domain.ImportReport "Report of the dry run"
+// @Success 201 {object} domain.ImportReport "Report of the import"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported file format"
+// @Failure 422 {object} domain.ImportReport "Report of the rejected import"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /employees/import [post]
+func (e *Employee) Import() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		respondImport(ctx, CreateEmployeeRequest.ToEmployee, e.service.Import)
+	}
+}
+
 // Update godoc
 // @Summary Update a employee
-// @Description Update an existent employee based on the provided id and JSON payload.
+// @Description Update an existent employee by applying a JSON Merge Patch or a JSON Patch to it.
 // @Tags Employees
-// @Accept json
+// @Accept json,application/merge-patch+json,application/json-patch+json
 // @Produce json
 // @Param id path int true "Employee ID"
-// @Param request body UpdateEmployeeRequest true "Employee data to update"
+// @Param request body object true "Merge patch or JSON patch"
 // @Success 200 {object} domain.Employee "Updated employee"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resourse not found error"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resourse not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported patch format"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /employees/{id} [patch]
 func (e *Employee) Update() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		id := ctx.GetInt("Id")
-		request := ctx.MustGet(RequestParamContext).(UpdateEmployeeRequest)
+		current := ctx.MustGet(CurrentParamContext).(*domain.Employee)
+		request := ctx.MustGet(RequestParamContext).(CreateEmployeeRequest)
 
-		response, err := e.service.Update(id, request.ToUpdateEmployee())
+		response, err := e.service.Update(*current, request.ToEmployee())
 
 		if err != nil {
-			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(ctx, http.StatusNotFound, err.Error())
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(ctx, http.StatusConflict, err.Error())
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(ctx, http.StatusConflict, err.Error())
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 		}
@@ -179,8 +201,8 @@ func (e *Employee) Update() gin.HandlerFunc {
 // @Tags Employees
 // @Param id path int true "Employee ID"
 // @Success 204 "No content"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
 // @Router /employees/{id} [delete]
 func (e *Employee) Delete() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -190,7 +212,7 @@ func (e *Employee) Delete() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(ctx, http.StatusNotFound, err.Error())
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -204,36 +226,52 @@ func (e *Employee) Delete() gin.HandlerFunc {
 // @Description Inbound Order count by employee.
 // @Description If no query param is given, bring the report to all employees.
 // @Description If a employee id is specified, bring the number of inbound orders for this employee.
+// @Description The from and to dates filter the inbound orders by order date.
+// @Description If group_by is given, it brings the number of inbound orders by day, week or month instead.
+// @Description If top is given, it brings only the employees with the most inbound orders.
 // @Tags Employees
 // @Produce json
 // @Param id query int false "Employee ID"
+// @Param from query string false "First day of the inbound orders, yyyy-mm-dd"
+// @Param to query string false "Last day of the inbound orders, yyyy-mm-dd"
+// @Param group_by query string false "Period of the time series" Enums(day, week, month)
+// @Param top query int false "Number of employees to rank"
 // @Success 200 {object} []domain.InboundOrdersByEmployee "Get of employees"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Success 200 {object} []domain.ReportPeriod "Report of inbound orders by period"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /employees/report-inbound-orders [get]
 func (e *Employee) ReportInboundOrders() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		idParam := c.Request.URL.Query().Get("id")
+		query := c.MustGet(QueryParamContext).(ReportQuery)
+		dates := query.ToDateRange()
 
-		if idParam == "" {
-			result := e.service.CountInboundOrdersByAllEmployees()
-			web.Success(c, http.StatusOK, result)
+		if query.GroupBy != nil {
+			periods, err := e.service.CountInboundOrdersByPeriod(query.ID, *query.GroupBy, dates)
+
+			if err != nil {
+				if apperr.Is[*apperr.ResourceNotFound](err) {
+					web.ErrorFrom(c, http.StatusNotFound, err)
+					return
+				}
+			}
+
+			web.Success(c, http.StatusOK, periods)
 			return
 		}
 
-		id, err := strconv.Atoi(idParam)
-
-		if err != nil {
-			web.Error(c, http.StatusBadRequest, InvalidId, idParam)
+		if query.ID == nil {
+			result := e.service.CountInboundOrdersByAllEmployees(dates, topOrAll(query.Top))
+			web.Success(c, http.StatusOK, result)
 			return
 		}
 
-		employee, err := e.service.CountInboundOrdersByEmployee(id)
+		employee, err := e.service.CountInboundOrdersByEmployee(*query.ID, dates)
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
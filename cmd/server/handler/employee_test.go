@@ -101,6 +101,20 @@ func TestGetEmployee(t *testing.T) {
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
 
+	t.Run("Should return the employees with the given ids", func(t *testing.T) {
+		server, service, controller := InitEmployeeServer(t)
+
+		server.GET(DefinePath(ResourceEmployeesUri), middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
+		request, response := MakeRequest("GET", DefinePath(ResourceEmployeesUri)+"?ids=1,2", "")
+
+		service.On("GetMany", []int{1, 2}).Return([]domain.Employee{{ID: 1}, {ID: 2}})
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		service.AssertNotCalled(t, "GetAll")
+	})
+
 	t.Run("Should return employee not found error", func(t *testing.T) {
 		server, service, controller := InitEmployeeServer(t)
 
@@ -134,39 +148,41 @@ func TestGetEmployee(t *testing.T) {
 }
 
 func TestUpdateEmployee(t *testing.T) {
-	requestObject := handler.UpdateEmployeeRequest{
+	requestObject := handler.CreateEmployeeRequest{
 		CardNumberID: &mockedEmployee.CardNumberID,
 		FirstName:    &mockedEmployee.FirstName,
 		LastName:     &mockedEmployee.LastName,
 		WarehouseID:  &mockedEmployee.WarehouseID,
 	}
 
-	t.Run("Should return employee not found error", func(t *testing.T) {
+	t.Run("Should return conflict error when the employee was modified", func(t *testing.T) {
 		server, service, controller := InitEmployeeServer(t)
 
 		id := 99
+		current := domain.Employee{ID: id}
 		var serviceReturn *domain.Employee
 
-		server.PATCH(DefinePath(ResourceEmployeesUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceEmployeesUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceEmployeesUri, id), CreateBody(requestObject))
 
-		service.On("Update", id, requestObject.ToUpdateEmployee()).Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+		service.On("Update", current, requestObject.ToEmployee()).Return(serviceReturn, apperr.NewIncompatibleResource(ResourceModified))
 
 		server.ServeHTTP(response, request)
 
-		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Equal(t, http.StatusConflict, response.Code)
 	})
 
 	t.Run("Should return conflict error", func(t *testing.T) {
 		server, service, controller := InitEmployeeServer(t)
 
 		id := 1
+		current := domain.Employee{ID: id}
 		var serviceReturn *domain.Employee
 
-		server.PATCH(DefinePath(ResourceEmployeesUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceEmployeesUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceEmployeesUri, id), CreateBody(requestObject))
 
-		service.On("Update", id, requestObject.ToUpdateEmployee()).Return(serviceReturn, apperr.NewResourceAlreadyExists(ResourceAlreadyExists))
+		service.On("Update", current, requestObject.ToEmployee()).Return(serviceReturn, apperr.NewResourceAlreadyExists(ResourceAlreadyExists))
 
 		server.ServeHTTP(response, request)
 
@@ -177,12 +193,13 @@ func TestUpdateEmployee(t *testing.T) {
 		server, service, controller := InitEmployeeServer(t)
 
 		id := 1
+		current := domain.Employee{ID: id}
 		var serviceReturn *domain.Employee
 
-		server.PATCH(DefinePath(ResourceEmployeesUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceEmployeesUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceEmployeesUri, id), CreateBody(requestObject))
 
-		service.On("Update", id, requestObject.ToUpdateEmployee()).Return(serviceReturn, apperr.NewDependentResourceNotFound(ResourceNotFound))
+		service.On("Update", current, requestObject.ToEmployee()).Return(serviceReturn, apperr.NewDependentResourceNotFound(ResourceNotFound))
 
 		server.ServeHTTP(response, request)
 
@@ -193,11 +210,12 @@ func TestUpdateEmployee(t *testing.T) {
 		server, service, controller := InitEmployeeServer(t)
 
 		id := 1
+		current := domain.Employee{ID: id}
 
-		server.PATCH(DefinePath(ResourceEmployeesUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceEmployeesUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceEmployeesUri, id), CreateBody(requestObject))
 
-		service.On("Update", id, requestObject.ToUpdateEmployee()).Return(&mockedEmployee, nil)
+		service.On("Update", current, requestObject.ToEmployee()).Return(&mockedEmployee, nil)
 
 		server.ServeHTTP(response, request)
 
@@ -241,10 +259,10 @@ func TestReportInboundOrders(t *testing.T) {
 	t.Run("Should return sucess with all inbound orders by employee if no id was found", func(t *testing.T) {
 		server, service, controller := InitEmployeeServer(t)
 
-		server.GET(DefinePath(ResourceReportInboundOrdersUri), controller.ReportInboundOrders())
+		server.GET(DefinePath(ResourceReportInboundOrdersUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportInboundOrders())
 		request, response := MakeRequest("GET", DefinePath(ResourceReportInboundOrdersUri), "")
 		
-		service.On("CountInboundOrdersByAllEmployees").Return([]domain.InboundOrdersByEmployee{})
+		service.On("CountInboundOrdersByAllEmployees", domain.DateRange{}, 0).Return([]domain.InboundOrdersByEmployee{})
 
 		server.ServeHTTP(response, request)
 
@@ -257,10 +275,10 @@ func TestReportInboundOrders(t *testing.T) {
 		id := 10
 		serviceReturn := &domain.InboundOrdersByEmployee{}
 
-		server.GET(DefinePath(ResourceReportInboundOrdersUri), controller.ReportInboundOrders())
+		server.GET(DefinePath(ResourceReportInboundOrdersUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportInboundOrders())
 		request, response := MakeRequest("GET", DefinePath(ResourceReportInboundOrdersUri)+queryId, "")
 
-		service.On("CountInboundOrdersByEmployee", id).Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+		service.On("CountInboundOrdersByEmployee", id, domain.DateRange{}).Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
 
 		server.ServeHTTP(response, request)
 
@@ -271,7 +289,7 @@ func TestReportInboundOrders(t *testing.T) {
 
 		queryId := "?id=asdasd"
 
-		server.GET(DefinePath(ResourceReportInboundOrdersUri), controller.ReportInboundOrders())
+		server.GET(DefinePath(ResourceReportInboundOrdersUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportInboundOrders())
 		request, response := MakeRequest("GET", DefinePath(ResourceReportInboundOrdersUri)+queryId, "")
 		server.ServeHTTP(response, request)
 		
@@ -284,15 +302,30 @@ func TestReportInboundOrders(t *testing.T) {
 		id := 1
 		serviceReturn := &domain.InboundOrdersByEmployee{}
 
-		server.GET(DefinePath(ResourceReportInboundOrdersUri), controller.ReportInboundOrders())
+		server.GET(DefinePath(ResourceReportInboundOrdersUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportInboundOrders())
 		request, response := MakeRequest("GET", DefinePath(ResourceReportInboundOrdersUri)+queryId, "")
-		service.On("CountInboundOrdersByEmployee", id).Return(serviceReturn, nil)
+		service.On("CountInboundOrdersByEmployee", id, domain.DateRange{}).Return(serviceReturn, nil)
 		
 		server.ServeHTTP(response, request)
 
 
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
+
+	t.Run("Should return not found error when the id of the time series does not exist", func(t *testing.T) {
+		server, service, controller := InitEmployeeServer(t)
+
+		server.GET(DefinePath(ResourceReportInboundOrdersUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportInboundOrders())
+		request, response := MakeRequest("GET", DefinePath(ResourceReportInboundOrdersUri)+"?group_by=day&id=1", "")
+
+		id := 1
+		var periods []domain.ReportPeriod
+		service.On("CountInboundOrdersByPeriod", &id, domain.PeriodDay, domain.DateRange{}).Return(periods, apperr.NewResourceNotFound(ResourceNotFound))
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
 }
 
 func InitEmployeeServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.Employee) {
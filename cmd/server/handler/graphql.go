@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/graph"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLRequest struct {
+	Query         *string                `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type GraphQL struct {
+	graph *graph.Graph
+}
+
+func NewGraphQL(g *graph.Graph) *GraphQL {
+	return &GraphQL{
+		graph: g,
+	}
+}
+
+// Query godoc
+// @Summary Query the domain model with GraphQL
+// @Description Run a GraphQL query over warehouses, localities, sections, batches, products and sellers, following their relations.
+// @Description Queries deeper or more complex than the limits are rejected before running. The response follows the GraphQL
+// @Description specification, with the errors of the query in its errors field.
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Param request body GraphQLRequest true "GraphQL query"
+// @Success 200 {object} object "Data and errors of the query"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /graphql [post]
+func (g *GraphQL) Query() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := c.MustGet(RequestParamContext).(GraphQLRequest)
+
+		result := g.graph.Execute(c.Request.Context(), web.Locale(c), graph.Request{
+			Query:         *request.Query,
+			OperationName: request.OperationName,
+			Variables:     request.Variables,
+		})
+		c.JSON(http.StatusOK, result)
+	}
+}
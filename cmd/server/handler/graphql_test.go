@@ -0,0 +1,63 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/graph"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	warehouse_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ResourceGraphQLUri = "/graphql"
+)
+
+func TestGraphQLQuery(t *testing.T) {
+	route := DefinePath(ResourceGraphQLUri)
+
+	t.Run("Should return the data of the query", func(t *testing.T) {
+		server, service := InitGraphQLServer(t, 8)
+		request, response := MakeRequest("POST", route, `{"query":"query($id: Int!) { warehouse(id: $id) { warehouse_code } }","variables":{"id":1}}`)
+
+		service.On("Get", 1).Return(&domain.Warehouse{ID: 1, WarehouseCode: "WH-1"}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":{"warehouse":{"warehouse_code":"WH-1"}}}`, response.Body.String())
+	})
+	t.Run("Should return the errors of a query over the limits", func(t *testing.T) {
+		server, service := InitGraphQLServer(t, 1)
+		request, response := MakeRequest("POST", route, `{"query":"{ warehouses { id } }"}`)
+		request.Header.Set("Accept-Language", "en")
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), "query depth 2 exceeds the maximum of 1")
+		service.AssertNotCalled(t, "GetAll")
+	})
+	t.Run("Should return unprocessable entity error when the query is missing", func(t *testing.T) {
+		server, _ := InitGraphQLServer(t, 8)
+		request, response := MakeRequest("POST", route, `{"variables":{}}`)
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func InitGraphQLServer(t *testing.T, maxDepth int) (*gin.Engine, *warehouse_mocks.Service) {
+	t.Helper()
+	server := CreateServer()
+	service := new(warehouse_mocks.Service)
+	g, err := graph.NewGraph(graph.Services{Warehouses: service}, maxDepth, 1000)
+	assert.NoError(t, err)
+	controller := handler.NewGraphQL(g)
+	server.POST(DefinePath(ResourceGraphQLUri), middleware.RequestValidation[handler.GraphQLRequest](true), controller.Query())
+	return server, service
+}
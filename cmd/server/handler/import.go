@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+const (
+	ImportParamContext = "Import"
+)
+
+// ImportQuery tells whether a bulk import only checks its rows and whether it
+// saves them all or nothing, the default, or the valid ones only.
+type ImportQuery struct {
+	DryRun bool    `form:"dry_run"`
+	Mode   *string `form:"mode" binding:"omitempty,oneof=all_or_nothing best_effort"`
+}
+
+func (q ImportQuery) ToImportOptions() domain.ImportOptions {
+	options := domain.ImportOptions{DryRun: q.DryRun, Mode: domain.ImportAllOrNothing}
+	if q.Mode != nil {
+		options.Mode = *q.Mode
+	}
+	return options
+}
+
+// respondImport imports the rows read from the request and sends the report,
+// with 422 when nothing could be imported.
+func respondImport[R any, D any](c *gin.Context, toDomain func(R) D, importAll func([]D, domain.ImportOptions) ([]int, []error)) {
+	rows := c.MustGet(ImportParamContext).([]web.ImportRow[R])
+	options := c.MustGet(QueryParamContext).(ImportQuery).ToImportOptions()
+
+	report := importReport(web.Locale(c), rows, options, toDomain, importAll)
+	switch {
+	case report.Imported == 0:
+		web.Success(c, http.StatusUnprocessableEntity, report)
+	case report.DryRun:
+		web.Success(c, http.StatusOK, report)
+	default:
+		web.Success(c, http.StatusCreated, report)
+	}
+}
+
+// importReport sends the rows that passed validation to importAll and
+// reports, by line, the ones rejected either by validation or by the service.
+// An all or nothing import with invalid rows is run as a dry run so that the
+// report still lists every error.
+func importReport[R any, D any](locale language.Tag, rows []web.ImportRow[R], options domain.ImportOptions, toDomain func(R) D, importAll func([]D, domain.ImportOptions) ([]int, []error)) domain.ImportReport {
+	atomic := options.Mode == domain.ImportAllOrNothing
+
+	report := domain.ImportReport{
+		Mode:   options.Mode,
+		DryRun: options.DryRun,
+		Rows:   len(rows),
+		IDs:    make([]int, 0),
+		Errors: make([]domain.ImportRowError, 0),
+	}
+
+	valid := make([]D, 0, len(rows))
+	lines := make([]int, 0, len(rows))
+	for _, row := range rows {
+		if !row.Valid() {
+			report.Failed++
+			for _, fe := range row.Errors {
+				report.Errors = append(report.Errors, domain.ImportRowError{Line: row.Line, Field: fe.Field, Message: fe.Message})
+			}
+			continue
+		}
+		valid = append(valid, toDomain(row.Value))
+		lines = append(lines, row.Line)
+	}
+
+	if atomic && report.Failed > 0 {
+		options.DryRun = true
+	}
+
+	if len(valid) > 0 {
+		ids, errs := importAll(valid, options)
+		for i, err := range errs {
+			if err != nil {
+				report.Failed++
+				report.Errors = append(report.Errors, domain.ImportRowError{Line: lines[i], Message: web.Localize(locale, err)})
+				continue
+			}
+			if ids[i] != 0 {
+				report.IDs = append(report.IDs, ids[i])
+			}
+		}
+	}
+
+	if !atomic || report.Failed == 0 {
+		report.Imported = report.Rows - report.Failed
+	}
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+	return report
+}
@@ -0,0 +1,137 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	productsImportCSV = "description,expiration_rate,freezing_rate,height,length,netweight,product_code,recommended_freezing_temperature,width,product_type_id,seller_id\n" +
+		"Milk,1,2,3,4,5,MILK1,-5,6,1,1\n" +
+		"Cheese,1,2,3,4,5,AB,-5,6,1,1\n" +
+		"Butter,1,2,3,4,5,MILK1,-5,6,1,1\n"
+)
+
+var (
+	importedMilk   = domain.Product{Description: "Milk", ExpirationRate: 1, FreezingRate: 2, Height: 3, Length: 4, Netweight: 5, ProductCode: "MILK1", RecomFreezTemp: -5, Width: 6, ProductTypeID: 1, SellerID: 1}
+	importedButter = domain.Product{Description: "Butter", ExpirationRate: 1, FreezingRate: 2, Height: 3, Length: 4, Netweight: 5, ProductCode: "MILK1", RecomFreezTemp: -5, Width: 6, ProductTypeID: 1, SellerID: 1}
+)
+
+func TestImportProducts(t *testing.T) {
+	route := DefinePath(ResourceProductsUri) + "/import"
+
+	t.Run("Should save the valid rows and report the others by line on a best effort import", func(t *testing.T) {
+		server, service, controller := InitProductServer(t)
+
+		server.POST(route, middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateProductRequest](), controller.Import())
+		request, response := MakeRequest("POST", route+"?mode=best_effort", productsImportCSV)
+		request.Header.Set("Content-Type", "text/csv")
+
+		options := domain.ImportOptions{Mode: domain.ImportBestEffort}
+		service.On("Import", []domain.Product{importedMilk, importedButter}, options).
+			Return([]int{11, 0}, []error{nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists)})
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		report := decodeImportReport(t, response.Body.Bytes())
+		assert.Equal(t, 3, report.Rows)
+		assert.Equal(t, 1, report.Imported)
+		assert.Equal(t, 2, report.Failed)
+		assert.Equal(t, []int{11}, report.IDs)
+		assert.Equal(t, []domain.ImportRowError{
+			{Line: 3, Field: "product_code", Message: "'product_code' precisa ter mais de 3 caracteres"},
+			{Line: 4, Message: ResourceAlreadyExists},
+		}, report.Errors)
+	})
+
+	t.Run("Should save nothing and check the remaining rows when an all or nothing import has invalid rows", func(t *testing.T) {
+		server, service, controller := InitProductServer(t)
+
+		server.POST(route, middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateProductRequest](), controller.Import())
+		request, response := MakeRequest("POST", route, productsImportCSV)
+		request.Header.Set("Content-Type", "text/csv")
+
+		options := domain.ImportOptions{DryRun: true, Mode: domain.ImportAllOrNothing}
+		service.On("Import", []domain.Product{importedMilk, importedButter}, options).
+			Return([]int{0, 0}, []error{nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists)})
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+		report := decodeImportReport(t, response.Body.Bytes())
+		assert.Equal(t, domain.ImportAllOrNothing, report.Mode)
+		assert.False(t, report.DryRun)
+		assert.Equal(t, 0, report.Imported)
+		assert.Equal(t, 2, report.Failed)
+		assert.Empty(t, report.IDs)
+	})
+
+	t.Run("Should only check the rows on a dry run", func(t *testing.T) {
+		server, service, controller := InitProductServer(t)
+
+		server.POST(route, middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateProductRequest](), controller.Import())
+		request, response := MakeRequest("POST", route+"?dry_run=true", CreateBody(importedMilk))
+		request.Header.Set("Content-Type", "application/x-ndjson")
+
+		options := domain.ImportOptions{DryRun: true, Mode: domain.ImportAllOrNothing}
+		service.On("Import", []domain.Product{importedMilk}, options).Return([]int{0}, []error{nil})
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		report := decodeImportReport(t, response.Body.Bytes())
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 1, report.Imported)
+		assert.Empty(t, report.Errors)
+	})
+
+	t.Run("Should return bad request error when the mode is unknown", func(t *testing.T) {
+		server, _, controller := InitProductServer(t)
+
+		server.POST(route, middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateProductRequest](), controller.Import())
+		request, response := MakeRequest("POST", route+"?mode=some", productsImportCSV)
+		request.Header.Set("Content-Type", "text/csv")
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestImportBuyers(t *testing.T) {
+	t.Run("Should import the buyers sent as NDJSON", func(t *testing.T) {
+		server, service, controller := InitBuyerServer(t)
+		route := DefinePath(ResourceBuyerUri) + "/import"
+
+		server.POST(route, middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateBuyerRequest](), controller.Import())
+		body := `{"card_number_id":"B-1","first_name":"Ana","last_name":"Silva"}` + "\n" + `{"card_number_id":"B-2","first_name":"Rui","last_name":"Costa"}` + "\n"
+		request, response := MakeRequest("POST", route, body)
+		request.Header.Set("Content-Type", "application/x-ndjson")
+
+		buyers := []domain.Buyer{
+			{CardNumberID: "B-1", FirstName: "Ana", LastName: "Silva"},
+			{CardNumberID: "B-2", FirstName: "Rui", LastName: "Costa"},
+		}
+		service.On("Import", buyers, domain.ImportOptions{Mode: domain.ImportAllOrNothing}).Return([]int{5, 6}, []error{nil, nil})
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		report := decodeImportReport(t, response.Body.Bytes())
+		assert.Equal(t, []int{5, 6}, report.IDs)
+		assert.Equal(t, 2, report.Imported)
+	})
+}
+
+func decodeImportReport(t *testing.T, body []byte) domain.ImportReport {
+	t.Helper()
+	var response struct {
+		Data domain.ImportReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &response))
+	return response.Data
+}
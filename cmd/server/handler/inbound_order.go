@@ -46,9 +46,9 @@ func NewInboundOrder(service inbound_order.Service) *InboundOrder {
 // @Produce json
 // @Param request body CreateInboundOrderRequest true "Inbound Order data"
 // @Success 201 {object} domain.InboundOrder "Created inbound order"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /inbound-orders [post]
 func (i *InboundOrder) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -58,11 +58,11 @@ func (i *InboundOrder) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/job"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/i18n"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	JobLocation       = "/api/v1/jobs/%d"
+	JobResultLocation = "/api/v1/jobs/%d/result"
+)
+
+var (
+	// jobFormats maps the formats a report job can be asked for to their
+	// media type.
+	jobFormats = map[string]string{
+		"json":   binding.MIMEJSON,
+		"csv":    web.CSVMediaType,
+		"ndjson": web.NDJSONMediaType,
+	}
+	// jobResultExtensions names the files the job results are downloaded as.
+	jobResultExtensions = map[string]string{
+		binding.MIMEJSON:    "json",
+		web.CSVMediaType:    "csv",
+		web.NDJSONMediaType: "ndjson",
+	}
+)
+
+type Job struct {
+	service job.Service
+}
+
+// ReportJobQuery filters a report run in the background, which is written as
+// JSON, the default, CSV or NDJSON.
+type ReportJobQuery struct {
+	From   *string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To     *string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	Top    *int    `form:"top" binding:"omitempty,gt=0"`
+	Format *string `form:"format" binding:"omitempty,oneof=json csv ndjson"`
+}
+
+func (q ReportJobQuery) ToDateRange() domain.DateRange {
+	return ReportQuery{From: q.From, To: q.To}.ToDateRange()
+}
+
+type importJob[R any] struct {
+	Locale  string               `json:"locale"`
+	Options domain.ImportOptions `json:"options"`
+	Rows    R                    `json:"rows"`
+}
+
+type reportJob struct {
+	From   *string `json:"from"`
+	To     *string `json:"to"`
+	Top    int     `json:"top"`
+	Format string  `json:"format"`
+}
+
+func NewJob(s job.Service) *Job {
+	return &Job{
+		service: s,
+	}
+}
+
+// Get godoc
+// @Summary Get a job
+// @Description Return the status and progress of a job, with the link to download its result once it succeeded.
+// @Tags Jobs
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} domain.Job "Job"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /jobs/{id} [get]
+func (j *Job) Get() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+
+		jobFound, err := j.service.Get(id)
+		respondJob(ctx, http.StatusOK, jobFound, err)
+	}
+}
+
+// Result godoc
+// @Summary Download the result of a job
+// @Description Return the file produced by a succeeded job: the import report or the report asked for.
+// @Tags Jobs
+// @Produce json,text/csv,application/x-ndjson
+// @Param id path int true "Job ID"
+// @Success 200 {file} file "Job result"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /jobs/{id}/result [get]
+func (j *Job) Result() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+
+		result, err := j.service.Result(id)
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
+				return
+			}
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(ctx, http.StatusConflict, err)
+				return
+			}
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(result.ContentType)
+		extension, ok := jobResultExtensions[mediaType]
+		if !ok {
+			extension = "bin"
+		}
+
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("job-%d.%s", id, extension)))
+		ctx.Data(http.StatusOK, result.ContentType, result.Content)
+	}
+}
+
+// Cancel godoc
+// @Summary Cancel a job
+// @Description Drop a queued job at once, or ask a running one to stop, which happens at its next progress report.
+// @Tags Jobs
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} domain.Job "Canceled job"
+// @Success 202 {object} domain.Job "Job asked to stop"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /jobs/{id}/cancel [post]
+func (j *Job) Cancel() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+
+		jobFound, err := j.service.Cancel(id)
+		if err == nil && !jobFound.Finished() {
+			respondJob(ctx, http.StatusAccepted, jobFound, nil)
+			return
+		}
+		respondJob(ctx, http.StatusOK, jobFound, err)
+	}
+}
+
+// EnqueueImport godoc
+// @Summary Import in the background
+// @Description Queue the import of a CSV or NDJSON file, taking the same rows and options as the import endpoint.
+// @Description The job result is the import report.
+// @Tags Jobs
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param dry_run query bool false "Only check the rows"
+// @Param mode query string false "Import mode" Enums(all_or_nothing, best_effort)
+// @Success 202 {object} domain.Job "Queued job"
+// @Header 202 {string} Location "Job URL"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported media type error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /products/import/jobs [post]
+// @Router /sellers/import/jobs [post]
+// @Router /buyers/import/jobs [post]
+// @Router /employees/import/jobs [post]
+func (j *Job) EnqueueImport(kind string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := importJob[interface{}]{
+			Locale:  web.Locale(ctx).String(),
+			Options: ctx.MustGet(QueryParamContext).(ImportQuery).ToImportOptions(),
+			Rows:    ctx.MustGet(ImportParamContext),
+		}
+
+		respondEnqueued(ctx, j.service.Enqueue(kind, payload))
+	}
+}
+
+// EnqueueReport godoc
+// @Summary Report in the background
+// @Description Queue the report of every entity, filtered by date and ranked as the report endpoint does.
+// @Description The job result is the report in the format asked for.
+// @Tags Jobs
+// @Produce json
+// @Param from query string false "First day, yyyy-mm-dd"
+// @Param to query string false "Last day, yyyy-mm-dd"
+// @Param top query int false "Number of entities to rank"
+// @Param format query string false "Format of the result" Enums(json, csv, ndjson)
+// @Success 202 {object} domain.Job "Queued job"
+// @Header 202 {string} Location "Job URL"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /products/report-records/jobs [post]
+// @Router /sections/report-products/jobs [post]
+func (j *Job) EnqueueReport(kind string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query := ctx.MustGet(QueryParamContext).(ReportJobQuery)
+
+		payload := reportJob{
+			From:   query.From,
+			To:     query.To,
+			Top:    topOrAll(query.Top),
+			Format: binding.MIMEJSON,
+		}
+		if query.Format != nil {
+			payload.Format = jobFormats[*query.Format]
+		}
+
+		respondEnqueued(ctx, j.service.Enqueue(kind, payload))
+	}
+}
+
+// ImportRunner runs the import jobs queued by EnqueueImport. The rows are
+// checked once, and the job can be canceled until they all are, in which case
+// none is saved. Saving them is not interrupted.
+func ImportRunner[R any, D any](toDomain func(R) D, importAll func([]D, domain.ImportOptions) ([]int, []error)) job.Runner {
+	return func(ctx context.Context, payload []byte, progress func(done, total int)) (*domain.JobResult, error) {
+		var queued importJob[[]web.ImportRow[R]]
+		if err := json.Unmarshal(payload, &queued); err != nil {
+			return nil, err
+		}
+		options := queued.Options
+		options.Progress = func(checked int) bool {
+			progress(checked, len(queued.Rows))
+			return ctx.Err() == nil
+		}
+
+		report := importReport(i18n.LocaleFrom(queued.Locale), queued.Rows, options, toDomain, importAll)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return &domain.JobResult{
+			ContentType: web.ContentType(binding.MIMEJSON),
+			Content:     web.Marshal(binding.MIMEJSON, report),
+		}, nil
+	}
+}
+
+// ReportRunner runs the report jobs queued by EnqueueReport.
+func ReportRunner[T any](report func(dates domain.DateRange, top int) []T) job.Runner {
+	return func(ctx context.Context, payload []byte, progress func(done, total int)) (*domain.JobResult, error) {
+		var queued reportJob
+		if err := json.Unmarshal(payload, &queued); err != nil {
+			return nil, err
+		}
+
+		progress(0, 1)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		dates := ReportQuery{From: queued.From, To: queued.To}.ToDateRange()
+		return &domain.JobResult{
+			ContentType: web.ContentType(queued.Format),
+			Content:     web.Marshal(queued.Format, report(dates, queued.Top)),
+		}, nil
+	}
+}
+
+func respondEnqueued(ctx *gin.Context, queued *domain.Job) {
+	ctx.Header("Location", fmt.Sprintf(JobLocation, queued.ID))
+	web.Success(ctx, http.StatusAccepted, queued)
+}
+
+func respondJob(ctx *gin.Context, status int, jobFound *domain.Job, err error) {
+	if err != nil {
+		if apperr.Is[*apperr.ResourceNotFound](err) {
+			web.ErrorFrom(ctx, http.StatusNotFound, err)
+			return
+		}
+		if apperr.Is[*apperr.IncompatibleResource](err) {
+			web.ErrorFrom(ctx, http.StatusConflict, err)
+			return
+		}
+	}
+
+	if jobFound.Status == domain.JobSucceeded {
+		resultURL := fmt.Sprintf(JobResultLocation, jobFound.ID)
+		jobFound.ResultURL = &resultURL
+	}
+	web.Success(ctx, status, jobFound)
+}
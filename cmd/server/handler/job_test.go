@@ -0,0 +1,225 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/job"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/job/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	ResourceJobsUri = "/jobs"
+)
+
+func TestGetJob(t *testing.T) {
+	route := DefinePath(ResourceJobsUri) + "/:id"
+
+	t.Run("Should return the job with the link to its result once it succeeded", func(t *testing.T) {
+		server, service, controller := InitJobServer(t)
+
+		server.GET(route, controller.Get())
+		request, response := MakeRequest("GET", DefinePathWithId(ResourceJobsUri, 4), "")
+
+		service.On("Get", 4).Return(&domain.Job{ID: 4, Status: domain.JobSucceeded, Progress: 100}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"result_url":"/api/v1/jobs/4/result"`)
+	})
+	t.Run("Should return not found error when the job does not exist", func(t *testing.T) {
+		server, service, controller := InitJobServer(t)
+
+		server.GET(route, controller.Get())
+		request, response := MakeRequest("GET", DefinePathWithId(ResourceJobsUri, 4), "")
+
+		var jobFound *domain.Job
+		service.On("Get", 4).Return(jobFound, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestJobResult(t *testing.T) {
+	route := DefinePath(ResourceJobsUri) + "/:id/result"
+	path := DefinePathWithId(ResourceJobsUri, 4) + "/result"
+
+	t.Run("Should download the result as a file", func(t *testing.T) {
+		server, service, controller := InitJobServer(t)
+
+		server.GET(route, controller.Result())
+		request, response := MakeRequest("GET", path, "")
+
+		service.On("Result", 4).Return(&domain.JobResult{ContentType: "text/csv; charset=utf-8", Content: []byte("id\n1\n")}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `attachment; filename="job-4.csv"`, response.Header().Get("Content-Disposition"))
+		assert.Equal(t, "id\n1\n", response.Body.String())
+	})
+	t.Run("Should return conflict error when the job has not succeeded", func(t *testing.T) {
+		server, service, controller := InitJobServer(t)
+
+		server.GET(route, controller.Result())
+		request, response := MakeRequest("GET", path, "")
+
+		var result *domain.JobResult
+		service.On("Result", 4).Return(result, apperr.NewIncompatibleResource(ResourceAlreadyExists))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestCancelJob(t *testing.T) {
+	route := DefinePath(ResourceJobsUri) + "/:id/cancel"
+	path := DefinePathWithId(ResourceJobsUri, 4) + "/cancel"
+
+	t.Run("Should accept the cancellation of a running job", func(t *testing.T) {
+		server, service, controller := InitJobServer(t)
+
+		server.POST(route, controller.Cancel())
+		request, response := MakeRequest("POST", path, "")
+
+		service.On("Cancel", 4).Return(&domain.Job{ID: 4, Status: domain.JobRunning, CancelRequested: true}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusAccepted, response.Code)
+	})
+	t.Run("Should return the canceled job when it was queued", func(t *testing.T) {
+		server, service, controller := InitJobServer(t)
+
+		server.POST(route, controller.Cancel())
+		request, response := MakeRequest("POST", path, "")
+
+		service.On("Cancel", 4).Return(&domain.Job{ID: 4, Status: domain.JobCanceled}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestEnqueueJobs(t *testing.T) {
+	t.Run("Should queue the import and return its location", func(t *testing.T) {
+		server, service, controller := InitJobServer(t)
+		route := DefinePath(ResourceProductsUri) + "/import/jobs"
+
+		server.POST(route, middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateProductRequest](), controller.EnqueueImport(job.KindProductImport))
+		request, response := MakeRequest("POST", route+"?mode=best_effort", productsImportCSV)
+		request.Header.Set("Content-Type", "text/csv")
+
+		service.On("Enqueue", job.KindProductImport, mock.Anything).Return(&domain.Job{ID: 4, Kind: job.KindProductImport, Status: domain.JobQueued})
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusAccepted, response.Code)
+		assert.Equal(t, "/api/v1/jobs/4", response.Header().Get("Location"))
+	})
+	t.Run("Should queue the report in the format asked for", func(t *testing.T) {
+		server, service, controller := InitJobServer(t)
+		route := DefinePath(ResourceProductsUri) + "/report-records/jobs"
+
+		server.POST(route, middleware.QueryValidation[handler.ReportJobQuery](), controller.EnqueueReport(job.KindProductReport))
+		request, response := MakeRequest("POST", route+"?format=csv&top=3", "")
+
+		service.On("Enqueue", job.KindProductReport, mock.Anything).Return(&domain.Job{ID: 5, Kind: job.KindProductReport, Status: domain.JobQueued})
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusAccepted, response.Code)
+		payload, _ := json.Marshal(service.Calls[0].Arguments.Get(1))
+		assert.JSONEq(t, `{"from":null,"to":null,"top":3,"format":"text/csv"}`, string(payload))
+	})
+	t.Run("Should return bad request error when the format is unknown", func(t *testing.T) {
+		server, _, controller := InitJobServer(t)
+		route := DefinePath(ResourceProductsUri) + "/report-records/jobs"
+
+		server.POST(route, middleware.QueryValidation[handler.ReportJobQuery](), controller.EnqueueReport(job.KindProductReport))
+		request, response := MakeRequest("POST", route+"?format=xml", "")
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestJobRunners(t *testing.T) {
+	t.Run("Should check the rows once while importing them and return the report", func(t *testing.T) {
+		rows := []map[string]interface{}{
+			{"Line": 2, "Value": map[string]interface{}{"card_number_id": "B-1", "first_name": "Ana", "last_name": "Silva"}},
+			{"Line": 3, "Value": map[string]interface{}{"card_number_id": "B-2", "first_name": "Rui", "last_name": "Costa"}},
+		}
+		payload, _ := json.Marshal(map[string]interface{}{"locale": "en", "options": domain.ImportOptions{Mode: domain.ImportAllOrNothing}, "rows": rows})
+
+		var modes []bool
+		importAll := func(buyers []domain.Buyer, options domain.ImportOptions) ([]int, []error) {
+			modes = append(modes, options.DryRun)
+			assert.True(t, options.Progress(len(buyers)))
+			return []int{5, 6}, []error{nil, nil}
+		}
+		var progressed []int
+		runner := handler.ImportRunner(handler.CreateBuyerRequest.ToBuyer, importAll)
+
+		result, err := runner(context.Background(), payload, func(done, total int) { progressed = append(progressed, done, total) })
+
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false}, modes)
+		assert.Equal(t, []int{2, 2}, progressed)
+		var report domain.ImportReport
+		assert.NoError(t, json.Unmarshal(result.Content, &report))
+		assert.Equal(t, []int{5, 6}, report.IDs)
+	})
+	t.Run("Should not import the rows once the job is canceled", func(t *testing.T) {
+		payload, _ := json.Marshal(map[string]interface{}{"options": domain.ImportOptions{Mode: domain.ImportBestEffort}, "rows": []map[string]interface{}{
+			{"Line": 2, "Value": map[string]interface{}{"card_number_id": "B-1", "first_name": "Ana", "last_name": "Silva"}},
+		}})
+		ctx, cancel := context.WithCancel(context.Background())
+
+		calls := 0
+		importAll := func(buyers []domain.Buyer, options domain.ImportOptions) ([]int, []error) {
+			calls++
+			cancel()
+			assert.False(t, options.Progress(len(buyers)))
+			return []int{0}, []error{nil}
+		}
+		runner := handler.ImportRunner(handler.CreateBuyerRequest.ToBuyer, importAll)
+
+		_, err := runner(ctx, payload, func(done, total int) {})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("Should write the report in the format of the job", func(t *testing.T) {
+		payload := []byte(`{"from":"2023-07-01","to":null,"top":1,"format":"application/x-ndjson"}`)
+
+		report := func(dates domain.DateRange, top int) []domain.RecordsByProductReport {
+			assert.NotNil(t, dates.From)
+			assert.Equal(t, 1, top)
+			return []domain.RecordsByProductReport{{ProductID: 1, Description: "Milk", RecordsCount: 2}}
+		}
+		runner := handler.ReportRunner(report)
+
+		result, err := runner(context.Background(), payload, func(done, total int) {})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "application/x-ndjson; charset=utf-8", result.ContentType)
+		assert.Equal(t, `{"product_id":1,"description":"Milk","records_count":2}`+"\n", string(result.Content))
+	})
+}
+
+func InitJobServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.Job) {
+	t.Helper()
+	server := CreateServer()
+	server.Use(middleware.IdValidation())
+	service := new(mocks.Service)
+	controller := handler.NewJob(service)
+	return server, service, controller
+}
@@ -0,0 +1,16 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+// ListQuery narrows a collection down to the entities with the given ids,
+// as in ?ids=1,2,3, which are fetched together instead of one request each.
+type ListQuery struct {
+	IDs []int `form:"ids" binding:"omitempty,max=100,dive,gt=0"`
+}
+
+// requestedIDs returns the ids the collection was narrowed down to, and false
+// when the whole collection was asked for.
+func requestedIDs(c *gin.Context) ([]int, bool) {
+	query, _ := c.Value(QueryParamContext).(ListQuery)
+	return query.IDs, query.IDs != nil
+}
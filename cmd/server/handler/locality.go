@@ -2,7 +2,6 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/locality"
@@ -12,10 +11,6 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-const (
-	InvalidId = "o id '%s' é inválido"
-)
-
 type Locality struct {
 	service locality.Service
 }
@@ -45,9 +40,9 @@ func NewLocality(service locality.Service) *Locality {
 // @Produce json
 // @Param request body CreateLocalityRequest true "Locality to be created"
 // @Success 201 {object} domain.Locality "Created locality"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /localities [post]
 func (l *Locality) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -57,11 +52,11 @@ func (l *Locality) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -75,36 +70,31 @@ func (l *Locality) Create() gin.HandlerFunc {
 // @Description Seller count by locality.
 // @Description If no query param is given, it brings the report to all localities.
 // @Description If a location id is specified, it brings the number of sellers for this locality.
+// @Description If top is given, it brings only the localities with the most sellers.
 // @Tags Localities
 // @Produce json
 // @Param id query int false "Locality ID"
+// @Param top query int false "Number of localities to rank"
 // @Success 200 {object} []domain.SellersByLocalityReport "Report of sellers by locality"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /localities/report-sellers [get]
 func (l *Locality) ReportSellers() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		idParam := c.Request.URL.Query().Get("id")
+		query := c.MustGet(QueryParamContext).(RankingQuery)
 
-		if idParam == "" {
-			result := l.service.CountSellersByAllLocalities()
+		if query.ID == nil {
+			result := l.service.CountSellersByAllLocalities(topOrAll(query.Top))
 			web.Success(c, http.StatusOK, result)
 			return
 		}
 
-		id, err := strconv.Atoi(idParam)
-
-		if err != nil {
-			web.Error(c, http.StatusBadRequest, InvalidId, idParam)
-			return
-		}
-
-		localities, err := l.service.CountSellersByLocality(id)
+		localities, err := l.service.CountSellersByLocality(*query.ID)
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -117,35 +107,30 @@ func (l *Locality) ReportSellers() gin.HandlerFunc {
 // @Description Carrier count by location.
 // @Description If no query param is given, it brings the report to all localities.
 // @Description If a location id is specified, it brings the number of carriers for this locality.
+// @Description If top is given, it brings only the localities with the most carriers.
 // @Tags Localities
 // @Produce json
 // @Param id query int false "Locality ID"
+// @Param top query int false "Number of localities to rank"
 // @Success 200 {object} []domain.CarriersByLocalityReport "List of localities"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /localities/report-carriers [get]
 func (l Locality) ReportCarriers() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		idParam := ctx.Request.URL.Query().Get("id")
+		query := ctx.MustGet(QueryParamContext).(RankingQuery)
 
-		if idParam == "" {
-			result := l.service.CountCarriersByAllLocalities()
+		if query.ID == nil {
+			result := l.service.CountCarriersByAllLocalities(topOrAll(query.Top))
 			web.Success(ctx, http.StatusOK, result)
 			return
 		}
 
-		id, err := strconv.Atoi(idParam)
-
-		if err != nil {
-			web.Error(ctx, http.StatusBadRequest, InvalidId, idParam)
-			return
-		}
-
-		reportCarriers, err := l.service.CountCarriersByLocality(id)
+		reportCarriers, err := l.service.CountCarriersByLocality(*query.ID)
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(ctx, http.StatusNotFound, err.Error())
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -77,10 +77,10 @@ func TestReportSellers(t *testing.T) {
 	t.Run("Should return sellers count report of all localities", func(t *testing.T) {
 		server, service, controller := InitLocalityServer(t)
 
-		server.GET(DefinePath(ResourceLocalitiesUri), controller.ReportSellers())
+		server.GET(DefinePath(ResourceLocalitiesUri), middleware.QueryValidation[handler.RankingQuery](), controller.ReportSellers())
 		request, response := MakeRequest("GET", DefinePath(ResourceLocalitiesUri), "")
 
-		service.On("CountSellersByAllLocalities").Return([]domain.SellersByLocalityReport{}, nil)
+		service.On("CountSellersByAllLocalities", 0).Return([]domain.SellersByLocalityReport{}, nil)
 
 		server.ServeHTTP(response, request)
 
@@ -90,7 +90,7 @@ func TestReportSellers(t *testing.T) {
 	t.Run("Should return invalid id error", func(t *testing.T) {
 		server, _, controller := InitLocalityServer(t)
 
-		server.GET(DefinePath(ResourceLocalitiesUri), controller.ReportSellers())
+		server.GET(DefinePath(ResourceLocalitiesUri), middleware.QueryValidation[handler.RankingQuery](), controller.ReportSellers())
 		request, response := MakeRequest("GET", DefinePath(ResourceLocalitiesUri)+"?id=abc", "")
 
 		server.ServeHTTP(response, request)
@@ -101,7 +101,7 @@ func TestReportSellers(t *testing.T) {
 	t.Run("Should return not found error", func(t *testing.T) {
 		server, service, controller := InitLocalityServer(t)
 
-		server.GET(DefinePath(ResourceLocalitiesUri), controller.ReportSellers())
+		server.GET(DefinePath(ResourceLocalitiesUri), middleware.QueryValidation[handler.RankingQuery](), controller.ReportSellers())
 		request, response := MakeRequest("GET", DefinePath(ResourceLocalitiesUri)+"?id=1", "")
 
 		localityId := 1
@@ -116,7 +116,7 @@ func TestReportSellers(t *testing.T) {
 	t.Run("Should return sellers count report by locality", func(t *testing.T) {
 		server, service, controller := InitLocalityServer(t)
 
-		server.GET(DefinePath(ResourceLocalitiesUri), controller.ReportSellers())
+		server.GET(DefinePath(ResourceLocalitiesUri), middleware.QueryValidation[handler.RankingQuery](), controller.ReportSellers())
 		request, response := MakeRequest("GET", DefinePath(ResourceLocalitiesUri)+"?id=1", "")
 
 		localityId := 1
@@ -137,10 +137,10 @@ func TestReportCarriers(t *testing.T) {
 	t.Run("Should return carriers count report of all localities", func(t *testing.T) {
 		server, service, controller := InitLocalityServer(t)
 
-		server.GET(DefinePath(ResourceLocalitiesUri)+"report-carriers", controller.ReportCarriers())
+		server.GET(DefinePath(ResourceLocalitiesUri)+"report-carriers", middleware.QueryValidation[handler.RankingQuery](), controller.ReportCarriers())
 		request, response := MakeRequest("GET", DefinePath(ResourceLocalitiesUri)+"report-carriers", "")
 
-		service.On("CountCarriersByAllLocalities").Return([]domain.CarriersByLocalityReport{}, nil)
+		service.On("CountCarriersByAllLocalities", 0).Return([]domain.CarriersByLocalityReport{}, nil)
 
 		server.ServeHTTP(response, request)
 
@@ -150,7 +150,7 @@ func TestReportCarriers(t *testing.T) {
 	t.Run("Should return invalid id error", func(t *testing.T) {
 		server, _, controller := InitLocalityServer(t)
 
-		server.GET(DefinePath(ResourceLocalitiesUri)+"report-carriers", controller.ReportCarriers())
+		server.GET(DefinePath(ResourceLocalitiesUri)+"report-carriers", middleware.QueryValidation[handler.RankingQuery](), controller.ReportCarriers())
 		request, response := MakeRequest("GET", DefinePath(ResourceLocalitiesUri)+"report-carriers?id=abc", "")
 
 		server.ServeHTTP(response, request)
@@ -161,7 +161,7 @@ func TestReportCarriers(t *testing.T) {
 	t.Run("Should return not found error", func(t *testing.T) {
 		server, service, controller := InitLocalityServer(t)
 
-		server.GET(DefinePath(ResourceLocalitiesUri)+"report-carriers", controller.ReportCarriers())
+		server.GET(DefinePath(ResourceLocalitiesUri)+"report-carriers", middleware.QueryValidation[handler.RankingQuery](), controller.ReportCarriers())
 		request, response := MakeRequest("GET", DefinePath(ResourceLocalitiesUri)+"report-carriers?id=1", "")
 
 		localityId := 1
@@ -176,7 +176,7 @@ func TestReportCarriers(t *testing.T) {
 	t.Run("Should return carriers count report by locality", func(t *testing.T) {
 		server, service, controller := InitLocalityServer(t)
 
-		server.GET(DefinePath(ResourceLocalitiesUri)+"report-carriers", controller.ReportCarriers())
+		server.GET(DefinePath(ResourceLocalitiesUri)+"report-carriers", middleware.QueryValidation[handler.RankingQuery](), controller.ReportCarriers())
 		request, response := MakeRequest("GET", DefinePath(ResourceLocalitiesUri)+"report-carriers?id=1", "")
 
 		localityId := 1
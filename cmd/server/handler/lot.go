@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/lot"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type Lot struct {
+	service lot.Service
+}
+
+type RecallRequest struct {
+	ProductBatchIDs []int   `json:"product_batch_ids" binding:"required,min=1,dive,gt=0"`
+	Reason          *string `json:"reason" binding:"required,max=255"`
+	EmployeeID      *int    `json:"employee_id" binding:"required"`
+}
+
+func (r RecallRequest) ToRecall() domain.Recall {
+	return domain.Recall{
+		ProductBatchIDs: r.ProductBatchIDs,
+		Reason:          *r.Reason,
+		EmployeeID:      *r.EmployeeID,
+	}
+}
+
+func NewLot(s lot.Service) *Lot {
+	return &Lot{
+		service: s,
+	}
+}
+
+// Trace godoc
+// @Summary Trace the lot of a product batch
+// @Description Return the upstream chain of the lot (product, seller, inbound orders and receiving employees)
+// @Description and its downstream chain (purchase orders, buyers, carriers and tracking codes) built from the picks of the lot.
+// @Description Stock reserved and not picked yet is listed in reservations, and orders of the product that reserve no stock,
+// @Description as the ones placed before stock was reserved, in unallocated.
+// @Description The lot holds the origin batch the batch was split from and every batch split from it by partial transfers.
+// @Tags Product Batches
+// @Produce json
+// @Param id path int true "Product batch ID"
+// @Success 200 {object} domain.LotTrace "Lot trace"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /product-batches/{id}/trace [get]
+func (l *Lot) Trace() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+
+		trace, err := l.service.Trace(id)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(ctx, http.StatusOK, trace)
+	}
+}
+
+// CreateRecall godoc
+// @Summary Recall product batches
+// @Description Record a recall and quarantine every batch of the recalled lots that is not quarantined or disposed yet.
+// @Description The response lists the buyers with orders holding stock of those batches.
+// @Tags Recalls
+// @Accept json
+// @Produce json
+// @Param request body RecallRequest true "Recalled batches, reason and acting employee"
+// @Success 201 {object} domain.Recall "Created recall"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /recalls [post]
+func (l *Lot) CreateRecall() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		request := ctx.MustGet(RequestParamContext).(RecallRequest)
+
+		recall, err := l.service.Recall(request.ToRecall())
+
+		if err != nil {
+			if apperr.Is[*apperr.DependentResourceNotFound](err) {
+				web.ErrorFrom(ctx, http.StatusConflict, err)
+				return
+			}
+		}
+
+		web.Success(ctx, http.StatusCreated, recall)
+	}
+}
+
+// GetRecall godoc
+// @Summary Get a recall
+// @Description Return the recall with its batches and affected buyers.
+// @Tags Recalls
+// @Produce json
+// @Param id path int true "Recall ID"
+// @Success 200 {object} domain.Recall "Recall"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /recalls/{id} [get]
+func (l *Lot) GetRecall() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+
+		recall, err := l.service.GetRecall(id)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(ctx, http.StatusOK, recall)
+	}
+}
+
+// AffectedBuyers godoc
+// @Summary Download the buyers affected by a recall
+// @Description Return a CSV file with one line per buyer order holding stock of a recalled batch.
+// @Tags Recalls
+// @Produce text/csv
+// @Param id path int true "Recall ID"
+// @Success 200 {file} file "Affected buyers"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /recalls/{id}/affected-buyers [get]
+func (l *Lot) AffectedBuyers() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+
+		recall, err := l.service.GetRecall(id)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		records := [][]string{{"buyer_id", "card_number_id", "first_name", "last_name", "purchase_order_id", "order_number", "tracking_code", "product_batch_id", "quantity"}}
+		for _, b := range recall.AffectedBuyers {
+			records = append(records, []string{
+				strconv.Itoa(b.BuyerID),
+				b.CardNumberID,
+				b.FirstName,
+				b.LastName,
+				strconv.Itoa(b.PurchaseOrderID),
+				b.OrderNumber,
+				b.TrackingCode,
+				strconv.Itoa(b.ProductBatchID),
+				strconv.Itoa(b.Quantity),
+			})
+		}
+
+		web.CSV(ctx, http.StatusOK, fmt.Sprintf("recall-%d-buyers.csv", recall.ID), records)
+	}
+}
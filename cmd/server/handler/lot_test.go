@@ -0,0 +1,155 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/lot/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ResourceRecallsUri = "/recalls"
+)
+
+var (
+	mockedRecall = &domain.Recall{
+		ID:              3,
+		Reason:          "supplier recall",
+		EmployeeID:      7,
+		ProductBatchIDs: []int{1, 4},
+		AffectedBuyers: []domain.RecallBuyer{
+			{BuyerID: 2, CardNumberID: "B-2", FirstName: "Joao", LastName: "Silva", PurchaseOrderID: 1, OrderNumber: "order#1", TrackingCode: "TRACK1", ProductBatchID: 4, Quantity: 3},
+		},
+	}
+)
+
+func TestLotTrace(t *testing.T) {
+	route := DefinePath(resourceProductsBatchesUri) + "/:id/trace"
+	path := DefinePathWithId(resourceProductsBatchesUri, 1) + "/trace"
+
+	t.Run("Should return the trace of the lot", func(t *testing.T) {
+		server, service, controller := InitLotServer(t)
+
+		server.GET(route, controller.Trace())
+		request, response := MakeRequest("GET", path, "")
+
+		trace := &domain.LotTrace{ProductBatchID: 1, LotBatchIDs: []int{1}, Upstream: domain.LotUpstream{Receipts: []domain.LotReceipt{}}, Downstream: []domain.LotShipment{}}
+		service.On("Trace", 1).Return(trace, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should return not found error when the batch does not exist", func(t *testing.T) {
+		server, service, controller := InitLotServer(t)
+
+		server.GET(route, controller.Trace())
+		request, response := MakeRequest("GET", path, "")
+
+		var trace *domain.LotTrace
+		service.On("Trace", 1).Return(trace, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestCreateRecall(t *testing.T) {
+	reason, employeeID := "supplier recall", 7
+	requestObject := handler.RecallRequest{ProductBatchIDs: []int{1}, Reason: &reason, EmployeeID: &employeeID}
+
+	t.Run("Should return the created recall", func(t *testing.T) {
+		server, service, controller := InitLotServer(t)
+
+		server.POST(DefinePath(ResourceRecallsUri), ValidationMiddleware(requestObject), controller.CreateRecall())
+		request, response := MakeRequest("POST", DefinePath(ResourceRecallsUri), CreateBody(requestObject))
+
+		service.On("Recall", requestObject.ToRecall()).Return(mockedRecall, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+	t.Run("Should return conflict error when a batch does not exist", func(t *testing.T) {
+		server, service, controller := InitLotServer(t)
+
+		server.POST(DefinePath(ResourceRecallsUri), ValidationMiddleware(requestObject), controller.CreateRecall())
+		request, response := MakeRequest("POST", DefinePath(ResourceRecallsUri), CreateBody(requestObject))
+
+		var recall *domain.Recall
+		service.On("Recall", requestObject.ToRecall()).Return(recall, apperr.NewDependentResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestGetRecall(t *testing.T) {
+	t.Run("Should return the recall", func(t *testing.T) {
+		server, service, controller := InitLotServer(t)
+
+		server.GET(DefinePath(ResourceRecallsUri)+"/:id", controller.GetRecall())
+		request, response := MakeRequest("GET", DefinePathWithId(ResourceRecallsUri, 3), "")
+
+		service.On("GetRecall", 3).Return(mockedRecall, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should return not found error when the recall does not exist", func(t *testing.T) {
+		server, service, controller := InitLotServer(t)
+
+		server.GET(DefinePath(ResourceRecallsUri)+"/:id", controller.GetRecall())
+		request, response := MakeRequest("GET", DefinePathWithId(ResourceRecallsUri, 3), "")
+
+		var recall *domain.Recall
+		service.On("GetRecall", 3).Return(recall, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestRecallAffectedBuyers(t *testing.T) {
+	route := DefinePath(ResourceRecallsUri) + "/:id/affected-buyers"
+	path := DefinePathWithId(ResourceRecallsUri, 3) + "/affected-buyers"
+
+	t.Run("Should download the affected buyers as CSV", func(t *testing.T) {
+		server, service, controller := InitLotServer(t)
+
+		server.GET(route, controller.AffectedBuyers())
+		request, response := MakeRequest("GET", path, "")
+
+		service.On("GetRecall", 3).Return(mockedRecall, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `attachment; filename="recall-3-buyers.csv"`, response.Header().Get("Content-Disposition"))
+		assert.Equal(t, "buyer_id,card_number_id,first_name,last_name,purchase_order_id,order_number,tracking_code,product_batch_id,quantity\n2,B-2,Joao,Silva,1,order#1,TRACK1,4,3\n", response.Body.String())
+	})
+	t.Run("Should return not found error when the recall does not exist", func(t *testing.T) {
+		server, service, controller := InitLotServer(t)
+
+		server.GET(route, controller.AffectedBuyers())
+		request, response := MakeRequest("GET", path, "")
+
+		var recall *domain.Recall
+		service.On("GetRecall", 3).Return(recall, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func InitLotServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.Lot) {
+	t.Helper()
+	server := CreateServer()
+	server.Use(middleware.IdValidation())
+	service := new(mocks.Service)
+	controller := handler.NewLot(service)
+	return server, service, controller
+}
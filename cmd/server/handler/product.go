@@ -2,7 +2,6 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product"
@@ -13,6 +12,7 @@ import (
 
 const (
 	RequestParamContext = "Request"
+	CurrentParamContext = "Current"
 )
 
 type Product struct {
@@ -30,7 +30,7 @@ type CreateProductRequest struct {
 	RecomFreezTemp *float32 `json:"recommended_freezing_temperature" binding:"required"`
 	Width          *float32 `json:"width" binding:"required"`
 	ProductTypeID  *int     `json:"product_type_id" binding:"required"`
-	SellerID       *int     `json:"seller_id"`
+	SellerID       *int     `json:"seller_id" binding:"required"`
 }
 
 func (r CreateProductRequest) ToProduct() domain.Product {
@@ -50,36 +50,6 @@ func (r CreateProductRequest) ToProduct() domain.Product {
 	}
 }
 
-type UpdateProductRequest struct {
-	Description    *string  `json:"description"`
-	ExpirationRate *float32 `json:"expiration_rate"`
-	FreezingRate   *float32 `json:"freezing_rate"`
-	Height         *float32 `json:"height"`
-	Length         *float32 `json:"length"`
-	Netweight      *float32 `json:"netweight"`
-	ProductCode    *string  `json:"product_code"`
-	RecomFreezTemp *float32 `json:"recommended_freezing_temperature"`
-	Width          *float32 `json:"width"`
-	ProductTypeID  *int     `json:"product_type_id"`
-	SellerID       *int     `json:"seller_id"`
-}
-
-func (r UpdateProductRequest) ToUpdateProduct() domain.UpdateProduct {
-	return domain.UpdateProduct{
-		Description:    r.Description,
-		ExpirationRate: r.ExpirationRate,
-		FreezingRate:   r.FreezingRate,
-		Height:         r.Height,
-		Length:         r.Length,
-		Netweight:      r.Netweight,
-		ProductCode:    r.ProductCode,
-		RecomFreezTemp: r.RecomFreezTemp,
-		Width:          r.Width,
-		ProductTypeID:  r.ProductTypeID,
-		SellerID:       r.SellerID,
-	}
-}
-
 func NewProduct(service product.Service) *Product {
 	return &Product{service}
 }
@@ -87,13 +57,26 @@ func NewProduct(service product.Service) *Product {
 // Get All products godoc
 // @Summary List all products
 // @Description Returns a collection of existing products.
+// @Description Send Accept text/csv or application/x-ndjson to export the products row by row.
 // @Tags Products
-// @Produce json
+// @Produce json,text/csv,application/x-ndjson
+// @Param ids query string false "Comma separated ids to get, instead of the whole collection"
 // @Success 200 {object} []domain.Product "List of all products"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /products [get]
 func (p *Product) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if ids, ok := requestedIDs(c); ok {
+			web.Success(c, http.StatusOK, p.service.GetMany(ids))
+			return
+		}
+
+		if web.Streaming(c) {
+			web.Stream(c, http.StatusOK, p.service.StreamAll)
+			return
+		}
+
 		products := p.service.GetAll()
 		web.Success(c, http.StatusOK, products)
 	}
@@ -106,9 +89,9 @@ func (p *Product) GetAll() gin.HandlerFunc {
 // @Produce json
 // @Param id path int true "Product Id"
 // @Success 200 {object} []domain.Product "Created product"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /products/{id} [get]
 func (p *Product) Get() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -118,7 +101,7 @@ func (p *Product) Get() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -135,9 +118,9 @@ func (p *Product) Get() gin.HandlerFunc {
 // @Produce json
 // @Param request body CreateProductRequest true "Product to be created"
 // @Success 201 {object} domain.Product "Created product"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /products [post]
 func (p *Product) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -147,11 +130,11 @@ func (p *Product) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -160,41 +143,66 @@ func (p *Product) Create() gin.HandlerFunc {
 	}
 }
 
+// Import godoc
+// @Summary Import products
+// @Description Create products in bulk from a CSV file, whose header names the fields of the product, or from NDJSON, one product per line.
+// @Description Each row is validated as on creation, and a product code repeated in the file is rejected.
+// @Description By default nothing is saved if any row is rejected; with mode best_effort the valid rows are saved.
+// @Description With dry_run the rows are only checked.
+// @Tags Products
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param dry_run query bool false "Only check the rows"
+// @Param mode query string false "Commit mode" Enums(all_or_nothing, best_effort)
+// @Success 200 {object} domain.ImportReport "Report of the dry run"
+// @Success 201 {object} domain.ImportReport "Report of the import"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported file format"
+// @Failure 422 {object} domain.ImportReport "Report of the rejected import"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /products/import [post]
+func (p *Product) Import() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondImport(c, CreateProductRequest.ToProduct, p.service.Import)
+	}
+}
+
 // Update godoc
 // @Summary Update a product
-// @Description Update an existent product based on the provided id and JSON payload.
+// @Description Update an existent product by applying a JSON Merge Patch or a JSON Patch to it.
 // @Tags Products
-// @Accept json
+// @Accept json,application/merge-patch+json,application/json-patch+json
 // @Produce json
 // @Param id path int true "Product id"
-// @Param request body UpdateProductRequest true "Product data"
+// @Param request body object true "Merge patch or JSON patch"
 // @Success 200 {object} domain.Product "Updated product"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported patch format"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /products/{id} [patch]
 func (p *Product) Update() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.GetInt("Id")
-		request := c.MustGet(RequestParamContext).(UpdateProductRequest)
+		current := c.MustGet(CurrentParamContext).(*domain.Product)
+		request := c.MustGet(RequestParamContext).(CreateProductRequest)
 
-		response, err := p.service.Update(id, request.ToUpdateProduct())
+		response, err := p.service.Update(*current, request.ToProduct())
 
 		if err != nil {
-			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -209,9 +217,9 @@ func (p *Product) Update() gin.HandlerFunc {
 // @Tags Products
 // @Param id path int true "Product ID"
 // @Success 204 "No content"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /products/{id} [delete]
 func (p *Product) Delete() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -221,7 +229,7 @@ func (p *Product) Delete() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -235,36 +243,53 @@ func (p *Product) Delete() gin.HandlerFunc {
 // @Description Record count by product.
 // @Description If no query param is given, it brings the report to all product records.
 // @Description If a product id is specified, it brings the number of records for this product.
+// @Description The from and to dates filter the records by last update date.
+// @Description If group_by is given, it brings the number of records by day, week or month instead.
+// @Description If top is given, it brings only the products with the most records.
+// @Description Send Accept text/csv or application/x-ndjson to export the report.
 // @Tags Products
-// @Produce json
+// @Produce json,text/csv,application/x-ndjson
 // @Param id query int false "Product ID"
+// @Param from query string false "First day of the records, yyyy-mm-dd"
+// @Param to query string false "Last day of the records, yyyy-mm-dd"
+// @Param group_by query string false "Period of the time series" Enums(day, week, month)
+// @Param top query int false "Number of products to rank"
 // @Success 200 {object} []domain.RecordsByProductReport "Report of records by product"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Success 200 {object} []domain.ReportPeriod "Report of records by period"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /products/report-records [get]
 func (p *Product) ReportRecords() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		idParam := c.Request.URL.Query().Get("id")
+		query := c.MustGet(QueryParamContext).(ReportQuery)
+		dates := query.ToDateRange()
 
-		if idParam == "" {
-			result := p.service.CountRecordsByAllProducts()
-			web.Success(c, http.StatusOK, result)
+		if query.GroupBy != nil {
+			periods, err := p.service.CountRecordsByPeriod(query.ID, *query.GroupBy, dates)
+
+			if err != nil {
+				if apperr.Is[*apperr.ResourceNotFound](err) {
+					web.ErrorFrom(c, http.StatusNotFound, err)
+					return
+				}
+			}
+
+			web.Success(c, http.StatusOK, periods)
 			return
 		}
 
-		id, err := strconv.Atoi(idParam)
-
-		if err != nil {
-			web.Error(c, http.StatusBadRequest, InvalidId, idParam)
+		if query.ID == nil {
+			result := p.service.CountRecordsByAllProducts(dates, topOrAll(query.Top))
+			web.Success(c, http.StatusOK, result)
 			return
 		}
 
-		productRecords, err := p.service.CountRecordsByProduct(id)
+		productRecords, err := p.service.CountRecordsByProduct(*query.ID, dates)
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -43,6 +43,41 @@ func (pb *CreateProductBatchRequest) ToProductBatches() domain.ProductBatch {
 	}
 }
 
+type PutawaySuggestionRequest struct {
+	ProductID   *int `json:"product_id" binding:"required"`
+	Quantity    *int `json:"quantity" binding:"required,gt=0"`
+	WarehouseID *int `json:"warehouse_id" binding:"required"`
+}
+
+type TransferRequest struct {
+	SectionID  *int `json:"section_id" binding:"required"`
+	Quantity   *int `json:"quantity" binding:"required,gt=0"`
+	EmployeeID *int `json:"employee_id" binding:"required"`
+}
+
+func (r TransferRequest) ToStockTransfer(batchID int) domain.StockTransfer {
+	return domain.StockTransfer{
+		ProductBatchID: batchID,
+		ToSectionID:    *r.SectionID,
+		Quantity:       *r.Quantity,
+		EmployeeID:     *r.EmployeeID,
+	}
+}
+
+type BatchStatusRequest struct {
+	Reason     *string `json:"reason" binding:"required,max=255"`
+	EmployeeID *int    `json:"employee_id" binding:"required"`
+}
+
+func (r BatchStatusRequest) ToBatchStatusChange(batchID int, status string) domain.BatchStatusChange {
+	return domain.BatchStatusChange{
+		ProductBatchID: batchID,
+		ToStatus:       status,
+		Reason:         *r.Reason,
+		EmployeeID:     *r.EmployeeID,
+	}
+}
+
 func NewProductBatches(service product_batch.Service) *ProductBatch {
 	return &ProductBatch{
 		productBatchService: service,
@@ -51,15 +86,16 @@ func NewProductBatches(service product_batch.Service) *ProductBatch {
 
 // Create godoc
 // @Summary Create a new product batch
-// @Description Create a new product batch based on the provided JSON payload
+// @Description Create a new product batch based on the provided JSON payload.
+// @Description The product type must match the section and the batch minimum temperature must fit the section temperature range.
 // @Tags Product Batches
 // @Accept json
 // @Produce json
 // @Param request body CreateProductBatchRequest true "Product Batch data"
 // @Success 201 {object} domain.ProductBatch "Created product batch"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /product-batches [post]
 func (pb *ProductBatch) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -69,11 +105,15 @@ func (pb *ProductBatch) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
+				return
+			}
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -81,3 +121,216 @@ func (pb *ProductBatch) Create() gin.HandlerFunc {
 		web.Success(c, http.StatusCreated, created)
 	}
 }
+
+// SuggestPutaway godoc
+// @Summary Suggest sections for an incoming batch
+// @Description Rank the sections of a warehouse that can receive the given quantity of a product.
+// @Description Sections must store the product type, fit the product recommended freezing temperature and have enough free capacity.
+// @Description Fuller sections and sections that already hold the product come first.
+// @Tags Product Batches
+// @Accept json
+// @Produce json
+// @Param request body PutawaySuggestionRequest true "Incoming batch"
+// @Success 200 {object} []domain.PutawaySuggestion "Ranked sections"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /product-batches/putaway-suggestions [post]
+func (pb *ProductBatch) SuggestPutaway() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := c.MustGet(RequestParamContext).(PutawaySuggestionRequest)
+
+		suggestions, err := pb.productBatchService.SuggestPutaway(*request.ProductID, *request.Quantity, *request.WarehouseID)
+
+		if err != nil {
+			if apperr.Is[*apperr.DependentResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusOK, suggestions)
+	}
+}
+
+// Transfer godoc
+// @Summary Transfer a product batch to another section
+// @Description Move quantity of a batch to another section, in the same or another warehouse.
+// @Description Moving the whole quantity relocates the batch, while a partial move splits it into a new batch in the destination section.
+// @Description The transfer is recorded in the stock movement ledger.
+// @Tags Product Batches
+// @Accept json
+// @Produce json
+// @Param id path int true "Product batch ID"
+// @Param request body TransferRequest true "Transfer data"
+// @Success 201 {object} domain.StockMovement "Recorded movement"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /product-batches/{id}/transfers [post]
+func (pb *ProductBatch) Transfer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+		request := c.MustGet(RequestParamContext).(TransferRequest)
+
+		movement, err := pb.productBatchService.Transfer(request.ToStockTransfer(id))
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+			if apperr.Is[*apperr.DependentResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
+				return
+			}
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusCreated, movement)
+	}
+}
+
+// Movements godoc
+// @Summary List the stock movements of a product batch
+// @Description Return the ledger entries of a batch, oldest first.
+// @Tags Product Batches
+// @Produce json
+// @Param id path int true "Product batch ID"
+// @Success 200 {object} []domain.StockMovement "Stock movements"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /product-batches/{id}/movements [get]
+func (pb *ProductBatch) Movements() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+
+		movements, err := pb.productBatchService.Movements(id)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusOK, movements)
+	}
+}
+
+// Quarantine godoc
+// @Summary Quarantine a product batch
+// @Description Flag an available or released batch as quarantined. Quarantined batches are left out of allocation and stock totals.
+// @Tags Product Batches
+// @Accept json
+// @Produce json
+// @Param id path int true "Product batch ID"
+// @Param request body BatchStatusRequest true "Reason and acting employee"
+// @Success 200 {object} domain.BatchStatusChange "Recorded status change"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /product-batches/{id}/quarantine [post]
+func (pb *ProductBatch) Quarantine() gin.HandlerFunc {
+	return pb.changeStatus(domain.BatchQuarantined)
+}
+
+// Release godoc
+// @Summary Release a quarantined product batch
+// @Description Make a quarantined batch available again for allocation and stock totals.
+// @Tags Product Batches
+// @Accept json
+// @Produce json
+// @Param id path int true "Product batch ID"
+// @Param request body BatchStatusRequest true "Reason and acting employee"
+// @Success 200 {object} domain.BatchStatusChange "Recorded status change"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /product-batches/{id}/release [post]
+func (pb *ProductBatch) Release() gin.HandlerFunc {
+	return pb.changeStatus(domain.BatchReleased)
+}
+
+// Dispose godoc
+// @Summary Dispose of a product batch
+// @Description Write off the current quantity of the batch through the stock ledger and drop its reservations not picked yet. Disposed batches can not change status anymore.
+// @Tags Product Batches
+// @Accept json
+// @Produce json
+// @Param id path int true "Product batch ID"
+// @Param request body BatchStatusRequest true "Reason and acting employee"
+// @Success 200 {object} domain.BatchStatusChange "Recorded status change"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /product-batches/{id}/dispose [post]
+func (pb *ProductBatch) Dispose() gin.HandlerFunc {
+	return pb.changeStatus(domain.BatchDisposed)
+}
+
+// StatusChanges godoc
+// @Summary List the status changes of a product batch
+// @Description Return the quarantines, releases and disposal of a batch with their reason, oldest first.
+// @Tags Product Batches
+// @Produce json
+// @Param id path int true "Product batch ID"
+// @Success 200 {object} []domain.BatchStatusChange "Status changes"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /product-batches/{id}/status-changes [get]
+func (pb *ProductBatch) StatusChanges() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+
+		changes, err := pb.productBatchService.StatusChanges(id)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusOK, changes)
+	}
+}
+
+func (pb *ProductBatch) changeStatus(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+		request := c.MustGet(RequestParamContext).(BatchStatusRequest)
+
+		change, err := pb.productBatchService.ChangeStatus(request.ToBatchStatusChange(id, status))
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+			if apperr.Is[*apperr.DependentResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
+				return
+			}
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusOK, change)
+	}
+}
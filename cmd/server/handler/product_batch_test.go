@@ -76,6 +76,19 @@ func TestCreateProductBatches(t *testing.T) {
 
 		assert.Equal(t, http.StatusConflict, response.Code)
 	})
+	t.Run("Should return conflict error when section is incompatible", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.POST(DefinePath(resourceProductsBatchesUri), ValidationMiddleware(requestObject), controller.Create())
+		request, response := MakeRequest("POST", DefinePath(resourceProductsBatchesUri), CreateBody(requestObject))
+
+		var productBatchReturn *domain.ProductBatch
+		service.On("Create", requestObject.ToProductBatches()).Return(productBatchReturn, apperr.NewIncompatibleResource(ResourceNotFound))
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
 	t.Run("Should return created product batch", func(t *testing.T) {
 		server, service, controller := InitProductBatchesServer(t)
 
@@ -90,6 +103,212 @@ func TestCreateProductBatches(t *testing.T) {
 	})
 }
 
+func TestSuggestPutaway(t *testing.T) {
+	productID, quantity, warehouseID := 1, 10, 1
+	requestObject := handler.PutawaySuggestionRequest{
+		ProductID:   &productID,
+		Quantity:    &quantity,
+		WarehouseID: &warehouseID,
+	}
+	path := DefinePath(resourceProductsBatchesUri) + "/putaway-suggestions"
+
+	t.Run("Should return the ranked sections", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.POST(path, ValidationMiddleware(requestObject), controller.SuggestPutaway())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		suggestions := []domain.PutawaySuggestion{{SectionID: 1, SectionNumber: 1, FreeCapacity: 80, FillPercent: 30, Score: 21}}
+		service.On("SuggestPutaway", productID, quantity, warehouseID).Return(suggestions, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":[{"section_id":1,"section_number":1,"free_capacity":80,"product_quantity":0,"fill_percent":30,"score":21}]}`, response.Body.String())
+	})
+	t.Run("Should return conflict error when the product does not exist", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.POST(path, ValidationMiddleware(requestObject), controller.SuggestPutaway())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		var suggestions []domain.PutawaySuggestion
+		service.On("SuggestPutaway", productID, quantity, warehouseID).Return(suggestions, apperr.NewDependentResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestTransferProductBatch(t *testing.T) {
+	sectionID, quantity, employeeID := 2, 1, 3
+	requestObject := handler.TransferRequest{
+		SectionID:  &sectionID,
+		Quantity:   &quantity,
+		EmployeeID: &employeeID,
+	}
+	route := DefinePath(resourceProductsBatchesUri) + "/:id/transfers"
+	path := DefinePathWithId(resourceProductsBatchesUri, productBatch.ID) + "/transfers"
+
+	t.Run("Should return the recorded movement", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Transfer())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		movement := &domain.StockMovement{ID: 1, ProductBatchID: productBatch.ID, Type: domain.MovementTransfer, Quantity: quantity}
+		service.On("Transfer", requestObject.ToStockTransfer(productBatch.ID)).Return(movement, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+	t.Run("Should return not found error when the batch does not exist", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Transfer())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		var movement *domain.StockMovement
+		service.On("Transfer", requestObject.ToStockTransfer(productBatch.ID)).Return(movement, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+	t.Run("Should return conflict error when the destination does not accept the batch", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Transfer())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		var movement *domain.StockMovement
+		service.On("Transfer", requestObject.ToStockTransfer(productBatch.ID)).Return(movement, apperr.NewIncompatibleResource(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestProductBatchMovements(t *testing.T) {
+	route := DefinePath(resourceProductsBatchesUri) + "/:id/movements"
+	path := DefinePathWithId(resourceProductsBatchesUri, productBatch.ID) + "/movements"
+
+	t.Run("Should return the movements of the batch", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.GET(route, controller.Movements())
+		request, response := MakeRequest("GET", path, "")
+
+		service.On("Movements", productBatch.ID).Return([]domain.StockMovement{}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":[]}`, response.Body.String())
+	})
+	t.Run("Should return not found error when the batch does not exist", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.GET(route, controller.Movements())
+		request, response := MakeRequest("GET", path, "")
+
+		var movements []domain.StockMovement
+		service.On("Movements", productBatch.ID).Return(movements, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestChangeProductBatchStatus(t *testing.T) {
+	reason, employeeID := "temperature breach", 3
+	requestObject := handler.BatchStatusRequest{
+		Reason:     &reason,
+		EmployeeID: &employeeID,
+	}
+
+	t.Run("Should return the recorded status change", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.POST(DefinePath(resourceProductsBatchesUri)+"/:id/quarantine", ValidationMiddleware(requestObject), controller.Quarantine())
+		request, response := MakeRequest("POST", DefinePathWithId(resourceProductsBatchesUri, productBatch.ID)+"/quarantine", CreateBody(requestObject))
+
+		change := requestObject.ToBatchStatusChange(productBatch.ID, domain.BatchQuarantined)
+		recorded := &domain.BatchStatusChange{ID: 1, ProductBatchID: productBatch.ID, FromStatus: domain.BatchAvailable, ToStatus: domain.BatchQuarantined, Reason: reason, EmployeeID: employeeID, CreatedAt: time.Date(2021, 01, 01, 10, 0, 0, 0, time.UTC)}
+		service.On("ChangeStatus", change).Return(recorded, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":{"id":1,"product_batch_id":0,"from_status":"available","to_status":"quarantined","reason":"temperature breach","employee_id":3,"created_at":"2021-01-01T10:00:00Z"}}`, response.Body.String())
+	})
+	t.Run("Should ask to release the batch", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.POST(DefinePath(resourceProductsBatchesUri)+"/:id/release", ValidationMiddleware(requestObject), controller.Release())
+		request, response := MakeRequest("POST", DefinePathWithId(resourceProductsBatchesUri, productBatch.ID)+"/release", CreateBody(requestObject))
+
+		change := requestObject.ToBatchStatusChange(productBatch.ID, domain.BatchReleased)
+		service.On("ChangeStatus", change).Return(&domain.BatchStatusChange{ID: 2}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		service.AssertExpectations(t)
+	})
+	t.Run("Should return conflict error when the batch is already disposed", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.POST(DefinePath(resourceProductsBatchesUri)+"/:id/dispose", ValidationMiddleware(requestObject), controller.Dispose())
+		request, response := MakeRequest("POST", DefinePathWithId(resourceProductsBatchesUri, productBatch.ID)+"/dispose", CreateBody(requestObject))
+
+		var recorded *domain.BatchStatusChange
+		change := requestObject.ToBatchStatusChange(productBatch.ID, domain.BatchDisposed)
+		service.On("ChangeStatus", change).Return(recorded, apperr.NewIncompatibleResource(ResourceAlreadyExists))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+	t.Run("Should return not found error when the batch does not exist", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.POST(DefinePath(resourceProductsBatchesUri)+"/:id/dispose", ValidationMiddleware(requestObject), controller.Dispose())
+		request, response := MakeRequest("POST", DefinePathWithId(resourceProductsBatchesUri, productBatch.ID)+"/dispose", CreateBody(requestObject))
+
+		var recorded *domain.BatchStatusChange
+		change := requestObject.ToBatchStatusChange(productBatch.ID, domain.BatchDisposed)
+		service.On("ChangeStatus", change).Return(recorded, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestProductBatchStatusChanges(t *testing.T) {
+	route := DefinePath(resourceProductsBatchesUri) + "/:id/status-changes"
+	path := DefinePathWithId(resourceProductsBatchesUri, productBatch.ID) + "/status-changes"
+
+	t.Run("Should return the status changes of the batch", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.GET(route, controller.StatusChanges())
+		request, response := MakeRequest("GET", path, "")
+
+		service.On("StatusChanges", productBatch.ID).Return([]domain.BatchStatusChange{}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":[]}`, response.Body.String())
+	})
+	t.Run("Should return not found error when the batch does not exist", func(t *testing.T) {
+		server, service, controller := InitProductBatchesServer(t)
+
+		server.GET(route, controller.StatusChanges())
+		request, response := MakeRequest("GET", path, "")
+
+		var changes []domain.BatchStatusChange
+		service.On("StatusChanges", productBatch.ID).Return(changes, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
 func InitProductBatchesServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.ProductBatch) {
 	t.Helper()
 	server := CreateServer()
@@ -44,9 +44,9 @@ func NewProductRecord(service record.Service) *ProductRecord {
 // @Produce json
 // @Param request body CreateProductRecordRequest true "Product Record to be created"
 // @Success 201 {object} domain.ProductRecord "Created product record"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /product-records [post]
 func (pr *ProductRecord) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -56,11 +56,11 @@ func (pr *ProductRecord) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -2,13 +2,16 @@ package handler_test
 
 import (
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product/mocks"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -105,6 +108,55 @@ func TestGetProduct(t *testing.T) {
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
 
+	t.Run("Should return the products with the given ids", func(t *testing.T) {
+		server, service, controller := InitProductServer(t)
+
+		server.GET(DefinePath(ResourceProductsUri), middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
+		request, response := MakeRequest("GET", DefinePath(ResourceProductsUri)+"?ids=1,2", "")
+
+		service.On("GetMany", []int{1, 2}).Return([]domain.Product{{ID: 1}, {ID: 2}})
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		service.AssertNotCalled(t, "GetAll")
+	})
+
+	t.Run("Should export all products as CSV", func(t *testing.T) {
+		server, service, controller := InitProductServer(t)
+
+		server.GET(DefinePath(ResourceProductsUri), controller.GetAll())
+		request, response := MakeRequest("GET", DefinePath(ResourceProductsUri), "")
+		request.Header.Set("Accept", "text/csv")
+
+		service.On("StreamAll").Return([]domain.Product{{ID: 1, Description: "milk, whole", Height: 1.5, ProductCode: "ABC", ProductTypeID: 2, SellerID: 3}})
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, web.CSVContentType, response.Header().Get("Content-Type"))
+		assert.Equal(t, "id,description,expiration_rate,freezing_rate,height,length,netweight,product_code,recommended_freezing_temperature,width,product_type_id,seller_id\n1,\"milk, whole\",0,0,1.5,0,0,ABC,0,0,2,3\n", response.Body.String())
+		service.AssertNotCalled(t, "GetAll")
+	})
+
+	t.Run("Should export all products as NDJSON", func(t *testing.T) {
+		server, service, controller := InitProductServer(t)
+
+		server.GET(DefinePath(ResourceProductsUri), controller.GetAll())
+		request, response := MakeRequest("GET", DefinePath(ResourceProductsUri), "")
+		request.Header.Set("Accept", "application/x-ndjson")
+
+		service.On("StreamAll").Return([]domain.Product{{ID: 1, ProductCode: "ABC"}, {ID: 2, ProductCode: "DEF"}})
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, web.NDJSONContentType, response.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSuffix(response.Body.String(), "\n"), "\n")
+		assert.Len(t, lines, 2)
+		assert.JSONEq(t, `{"id":2,"description":"","expiration_rate":0,"freezing_rate":0,"height":0,"length":0,"netweight":0,"product_code":"DEF","recommended_freezing_temperature":0,"width":0,"product_type_id":0,"seller_id":0}`, lines[1])
+	})
+
 	t.Run("Should return not found error", func(t *testing.T) {
 		server, service, controller := InitProductServer(t)
 
@@ -138,7 +190,7 @@ func TestGetProduct(t *testing.T) {
 }
 
 func TestUpdateProduct(t *testing.T) {
-	requestObject := handler.UpdateProductRequest{
+	requestObject := handler.CreateProductRequest{
 		Description:    &mockedProduct.Description,
 		ExpirationRate: &mockedProduct.ExpirationRate,
 		FreezingRate:   &mockedProduct.FreezingRate,
@@ -152,35 +204,37 @@ func TestUpdateProduct(t *testing.T) {
 		SellerID:       &mockedProduct.SellerID,
 	}
 
-	t.Run("Should return not found error", func(t *testing.T) {
+	t.Run("Should return conflict error when the product was modified", func(t *testing.T) {
 		server, service, controller := InitProductServer(t)
 
 		id := 1
+		current := domain.Product{ID: id}
 
-		server.PATCH(DefinePath(ResourceProductsUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceProductsUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceProductsUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Product
 		service.On(
-			"Update", id, requestObject.ToUpdateProduct()).
-			Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+			"Update", current, requestObject.ToProduct()).
+			Return(serviceReturn, apperr.NewIncompatibleResource(ResourceModified))
 
 		server.ServeHTTP(response, request)
 
-		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Equal(t, http.StatusConflict, response.Code)
 	})
 
 	t.Run("Should return conflict error", func(t *testing.T) {
 		server, service, controller := InitProductServer(t)
 
 		id := 1
+		current := domain.Product{ID: id}
 
-		server.PATCH(DefinePath(ResourceProductsUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceProductsUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceProductsUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Product
 		service.On(
-			"Update", id, requestObject.ToUpdateProduct()).
+			"Update", current, requestObject.ToProduct()).
 			Return(serviceReturn, apperr.NewResourceAlreadyExists(ResourceAlreadyExists))
 
 		server.ServeHTTP(response, request)
@@ -192,13 +246,14 @@ func TestUpdateProduct(t *testing.T) {
 		server, service, controller := InitProductServer(t)
 
 		id := 1
+		current := domain.Product{ID: id}
 
-		server.PATCH(DefinePath(ResourceProductsUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceProductsUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceProductsUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Product
 		service.On(
-			"Update", id, requestObject.ToUpdateProduct()).
+			"Update", current, requestObject.ToProduct()).
 			Return(serviceReturn, apperr.NewDependentResourceNotFound(ResourceNotFound))
 
 		server.ServeHTTP(response, request)
@@ -210,12 +265,13 @@ func TestUpdateProduct(t *testing.T) {
 		server, service, controller := InitProductServer(t)
 
 		id := 1
+		current := domain.Product{ID: id}
 
-		server.PATCH(DefinePath(ResourceProductsUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceProductsUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceProductsUri, id), CreateBody(requestObject))
 
 		service.On(
-			"Update", id, requestObject.ToUpdateProduct()).
+			"Update", current, requestObject.ToProduct()).
 			Return(&mockedProduct, nil)
 
 		server.ServeHTTP(response, request)
@@ -260,10 +316,10 @@ func TestReportProductRecords(t *testing.T) {
 	t.Run("Should return records count report of all products", func(t *testing.T) {
 		server, service, controller := InitProductServer(t)
 
-		server.GET(DefinePath(ResourceProductRecordsUri), controller.ReportRecords())
+		server.GET(DefinePath(ResourceProductRecordsUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportRecords())
 		request, response := MakeRequest("GET", DefinePath(ResourceProductRecordsUri), "")
 
-		service.On("CountRecordsByAllProducts").Return([]domain.RecordsByProductReport{}, nil)
+		service.On("CountRecordsByAllProducts", domain.DateRange{}, 0).Return([]domain.RecordsByProductReport{}, nil)
 
 		server.ServeHTTP(response, request)
 
@@ -273,7 +329,7 @@ func TestReportProductRecords(t *testing.T) {
 	t.Run("Should return invalid id error", func(t *testing.T) {
 		server, _, controller := InitProductServer(t)
 
-		server.GET(DefinePath(ResourceProductRecordsUri), controller.ReportRecords())
+		server.GET(DefinePath(ResourceProductRecordsUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportRecords())
 		request, response := MakeRequest("GET", DefinePath(ResourceProductRecordsUri)+"?id=abc", "")
 
 		server.ServeHTTP(response, request)
@@ -284,12 +340,12 @@ func TestReportProductRecords(t *testing.T) {
 	t.Run("Should return not found error", func(t *testing.T) {
 		server, service, controller := InitProductServer(t)
 
-		server.GET(DefinePath(ResourceProductRecordsUri), controller.ReportRecords())
+		server.GET(DefinePath(ResourceProductRecordsUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportRecords())
 		request, response := MakeRequest("GET", DefinePath(ResourceProductRecordsUri)+"?id=1", "")
 
 		recordId := 1
 		var serviceReturn *domain.RecordsByProductReport
-		service.On("CountRecordsByProduct", recordId).Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+		service.On("CountRecordsByProduct", recordId, domain.DateRange{}).Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
 
 		server.ServeHTTP(response, request)
 
@@ -299,7 +355,7 @@ func TestReportProductRecords(t *testing.T) {
 	t.Run("Should return records count report by product", func(t *testing.T) {
 		server, service, controller := InitProductServer(t)
 
-		server.GET(DefinePath(ResourceProductRecordsUri), controller.ReportRecords())
+		server.GET(DefinePath(ResourceProductRecordsUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportRecords())
 		request, response := MakeRequest("GET", DefinePath(ResourceProductRecordsUri)+"?id=1", "")
 
 		recordId := 1
@@ -308,12 +364,64 @@ func TestReportProductRecords(t *testing.T) {
 			Description:  "Description",
 			RecordsCount: 1,
 		}
-		service.On("CountRecordsByProduct", recordId).Return(&serviceReturn, nil)
+		service.On("CountRecordsByProduct", recordId, domain.DateRange{}).Return(&serviceReturn, nil)
 
 		server.ServeHTTP(response, request)
 
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
+
+	t.Run("Should return records count report by period", func(t *testing.T) {
+		server, service, controller := InitProductServer(t)
+
+		server.GET(DefinePath(ResourceProductRecordsUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportRecords())
+		request, response := MakeRequest("GET", DefinePath(ResourceProductRecordsUri)+"?group_by=month&from=2023-07-01&to=2023-07-31", "")
+
+		from := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+		periods := []domain.ReportPeriod{{Period: from, Count: 4}}
+		service.On("CountRecordsByPeriod", (*int)(nil), domain.PeriodMonth, domain.DateRange{From: &from, To: &to}).Return(periods, nil)
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":[{"period":"2023-07-01T00:00:00Z","count":4}]}`, response.Body.String())
+	})
+
+	t.Run("Should return the products with the most records", func(t *testing.T) {
+		server, service, controller := InitProductServer(t)
+
+		server.GET(DefinePath(ResourceProductRecordsUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportRecords())
+		request, response := MakeRequest("GET", DefinePath(ResourceProductRecordsUri)+"?top=3", "")
+
+		service.On("CountRecordsByAllProducts", domain.DateRange{}, 3).Return([]domain.RecordsByProductReport{}, nil)
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("Should return validation error when top is used with an id", func(t *testing.T) {
+		server, _, controller := InitProductServer(t)
+
+		server.GET(DefinePath(ResourceProductRecordsUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportRecords())
+		request, response := MakeRequest("GET", DefinePath(ResourceProductRecordsUri)+"?top=3&id=1", "")
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Should return validation error when the period is unknown", func(t *testing.T) {
+		server, _, controller := InitProductServer(t)
+
+		server.GET(DefinePath(ResourceProductRecordsUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportRecords())
+		request, response := MakeRequest("GET", DefinePath(ResourceProductRecordsUri)+"?group_by=year", "")
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
 }
 
 func InitProductServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.Product) {
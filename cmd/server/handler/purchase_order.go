@@ -24,9 +24,14 @@ type CreatePurchaseOrderRequest struct {
 	ProductRecordID *int    `json:"product_record_id" binding:"required"`
 	OrderStatusID   *int    `json:"order_status_id" binding:"required"`
 	WarehouseID     *int    `json:"warehouse_id" binding:"required"`
+	Quantity        *int    `json:"quantity" binding:"omitempty,gt=0"`
 }
 
 func (r CreatePurchaseOrderRequest) ToPurchaseOrder() domain.PurchaseOrder {
+	quantity := 1
+	if r.Quantity != nil {
+		quantity = *r.Quantity
+	}
 
 	return domain.PurchaseOrder{
 		ID:              0,
@@ -38,9 +43,15 @@ func (r CreatePurchaseOrderRequest) ToPurchaseOrder() domain.PurchaseOrder {
 		ProductRecordID: *r.ProductRecordID,
 		OrderStatusID:   *r.OrderStatusID,
 		WarehouseID:     *r.WarehouseID,
+		Quantity:        quantity,
 	}
 }
 
+type PickRequest struct {
+	ProductBatchID *int `json:"product_batch_id" binding:"required"`
+	EmployeeID     *int `json:"employee_id" binding:"required"`
+}
+
 func NewPurchaseOrder(service purchase_order.Service) *PurchaseOrder {
 	return &PurchaseOrder{service}
 }
@@ -48,14 +59,16 @@ func NewPurchaseOrder(service purchase_order.Service) *PurchaseOrder {
 // Create godoc
 // @Summary Create a new purchase order
 // @Description Create a new purchase order based on the provided JSON payload
+// @Description The quantity defaults to 1 and is reserved from the warehouse batches, first expired first out.
+// @Description An order can not be created with the cancelled status.
 // @Tags Purchase Orders
 // @Accept json
 // @Produce json
 // @Param request body CreatePurchaseOrderRequest true "Purchase order data"
 // @Success 201 {object} domain.PurchaseOrder "Created purchase order"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /purchase-orders [post]
 func (po *PurchaseOrder) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -65,11 +78,15 @@ func (po *PurchaseOrder) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
+				return
+			}
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -77,3 +94,106 @@ func (po *PurchaseOrder) Create() gin.HandlerFunc {
 		web.Success(c, http.StatusCreated, created)
 	}
 }
+
+// PickList godoc
+// @Summary Get the pick list of a purchase order
+// @Description Return the batches reserved for the order, first expired first out, and the quantity that could not be reserved.
+// @Tags Purchase Orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} domain.PickList "Pick list"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /purchase-orders/{id}/pick-list [get]
+func (po *PurchaseOrder) PickList() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+
+		pickList, err := po.service.PickList(id)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusOK, pickList)
+	}
+}
+
+// Cancel godoc
+// @Summary Cancel a purchase order
+// @Description Cancel the order and release the stock reserved for it.
+// @Tags Purchase Orders
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} domain.PurchaseOrder "Cancelled purchase order"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /purchase-orders/{id}/cancel [post]
+func (po *PurchaseOrder) Cancel() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+
+		cancelled, err := po.service.Cancel(id)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusOK, cancelled)
+	}
+}
+
+// Pick godoc
+// @Summary Confirm the pick of a purchase order line
+// @Description Take the quantity of the batch reserved for the order out of the batch and mark the pick list line as picked.
+// @Description The pick is recorded in the stock movement ledger.
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param request body PickRequest true "Pick data"
+// @Success 201 {object} domain.StockMovement "Recorded movement"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /purchase-orders/{id}/picks [post]
+func (po *PurchaseOrder) Pick() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+		request := c.MustGet(RequestParamContext).(PickRequest)
+
+		movement, err := po.service.Pick(id, *request.ProductBatchID, *request.EmployeeID)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+			if apperr.Is[*apperr.DependentResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
+				return
+			}
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusCreated, movement)
+	}
+}
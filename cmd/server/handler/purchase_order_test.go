@@ -8,6 +8,7 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/purchase_order"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/purchase_order/mocks"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
@@ -30,6 +31,7 @@ var (
 		ProductRecordID: 1,
 		OrderStatusID:   1,
 		WarehouseID:     1,
+		Quantity:        1,
 	}
 )
 
@@ -75,6 +77,20 @@ func TestCreatePurchaseOrder(t *testing.T) {
 		assert.Equal(t, http.StatusConflict, response.Code)
 	})
 
+	t.Run("Should return conflict error when the order is created cancelled", func(t *testing.T) {
+		server, service, controller := InitPurchaseOrderServer(t)
+
+		server.POST(DefinePath(ResourcePurchaseOrdersUri), ValidationMiddleware(requestObject), controller.Create())
+		request, response := MakeRequest("POST", DefinePath(ResourcePurchaseOrdersUri), CreateBody(requestObject))
+
+		var serviceReturn *domain.PurchaseOrder
+		service.On("Create", requestObject.ToPurchaseOrder()).Return(serviceReturn, apperr.NewIncompatibleResource(purchase_order.CreatedCancelled, *requestObject.OrderNumber))
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
 	t.Run("Should return a created purchase order", func(t *testing.T) {
 		server, service, controller := InitPurchaseOrderServer(t)
 
@@ -89,6 +105,125 @@ func TestCreatePurchaseOrder(t *testing.T) {
 	})
 }
 
+func TestPurchaseOrderPickList(t *testing.T) {
+	route := DefinePath(ResourcePurchaseOrdersUri) + "/:id/pick-list"
+	path := DefinePathWithId(ResourcePurchaseOrdersUri, mockedPurchaseOrder.ID) + "/pick-list"
+
+	t.Run("Should return the pick list", func(t *testing.T) {
+		server, service, controller := InitPurchaseOrderServer(t)
+
+		server.GET(route, controller.PickList())
+		request, response := MakeRequest("GET", path, "")
+
+		pickList := &domain.PickList{PurchaseOrderID: 1, Requested: 5, Reserved: 3, Short: 2, Lines: []domain.PickListLine{}}
+		service.On("PickList", mockedPurchaseOrder.ID).Return(pickList, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":{"purchase_order_id":1,"requested":5,"reserved":3,"short":2,"lines":[]}}`, response.Body.String())
+	})
+	t.Run("Should return not found error when the order does not exist", func(t *testing.T) {
+		server, service, controller := InitPurchaseOrderServer(t)
+
+		server.GET(route, controller.PickList())
+		request, response := MakeRequest("GET", path, "")
+
+		var pickList *domain.PickList
+		service.On("PickList", mockedPurchaseOrder.ID).Return(pickList, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestCancelPurchaseOrder(t *testing.T) {
+	route := DefinePath(ResourcePurchaseOrdersUri) + "/:id/cancel"
+	path := DefinePathWithId(ResourcePurchaseOrdersUri, mockedPurchaseOrder.ID) + "/cancel"
+
+	t.Run("Should return the cancelled order", func(t *testing.T) {
+		server, service, controller := InitPurchaseOrderServer(t)
+
+		server.POST(route, controller.Cancel())
+		request, response := MakeRequest("POST", path, "")
+
+		service.On("Cancel", mockedPurchaseOrder.ID).Return(&mockedPurchaseOrder, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should return not found error when the order does not exist", func(t *testing.T) {
+		server, service, controller := InitPurchaseOrderServer(t)
+
+		server.POST(route, controller.Cancel())
+		request, response := MakeRequest("POST", path, "")
+
+		var cancelled *domain.PurchaseOrder
+		service.On("Cancel", mockedPurchaseOrder.ID).Return(cancelled, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+	t.Run("Should return conflict error when the order is already cancelled", func(t *testing.T) {
+		server, service, controller := InitPurchaseOrderServer(t)
+
+		server.POST(route, controller.Cancel())
+		request, response := MakeRequest("POST", path, "")
+
+		var cancelled *domain.PurchaseOrder
+		service.On("Cancel", mockedPurchaseOrder.ID).Return(cancelled, apperr.NewIncompatibleResource(ResourceAlreadyExists))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestPickPurchaseOrder(t *testing.T) {
+	batchID, employeeID := 3, 4
+	requestObject := handler.PickRequest{
+		ProductBatchID: &batchID,
+		EmployeeID:     &employeeID,
+	}
+	route := DefinePath(ResourcePurchaseOrdersUri) + "/:id/picks"
+	path := DefinePathWithId(ResourcePurchaseOrdersUri, mockedPurchaseOrder.ID) + "/picks"
+
+	t.Run("Should return the recorded movement", func(t *testing.T) {
+		server, service, controller := InitPurchaseOrderServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Pick())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		movement := &domain.StockMovement{ID: 1, ProductBatchID: batchID, Type: domain.MovementPick, Quantity: 1}
+		service.On("Pick", mockedPurchaseOrder.ID, batchID, employeeID).Return(movement, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+	t.Run("Should return not found error when the order does not exist", func(t *testing.T) {
+		server, service, controller := InitPurchaseOrderServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Pick())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		var movement *domain.StockMovement
+		service.On("Pick", mockedPurchaseOrder.ID, batchID, employeeID).Return(movement, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+	t.Run("Should return conflict error when the batch is not reserved for the order", func(t *testing.T) {
+		server, service, controller := InitPurchaseOrderServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Pick())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		var movement *domain.StockMovement
+		service.On("Pick", mockedPurchaseOrder.ID, batchID, employeeID).Return(movement, apperr.NewIncompatibleResource(ResourceAlreadyExists))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
 func InitPurchaseOrderServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.PurchaseOrder) {
 	t.Helper()
 	server := CreateServer()
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+)
+
+const (
+	QueryParamContext = "Query"
+	ReportDateLayout  = "2006-01-02"
+)
+
+// ReportQuery filters the count reports. The from and to dates are both
+// inclusive. group_by returns the counts as a time series instead of by
+// entity, and top keeps only the entities with the highest counts.
+type ReportQuery struct {
+	ID      *int    `form:"id"`
+	From    *string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To      *string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	GroupBy *string `form:"group_by" binding:"omitempty,oneof=day week month"`
+	Top     *int    `form:"top" binding:"omitempty,gt=0,excluded_with=ID GroupBy"`
+}
+
+// RankingQuery filters the count reports of entities without a date column,
+// which can only be ranked.
+type RankingQuery struct {
+	ID  *int `form:"id"`
+	Top *int `form:"top" binding:"omitempty,gt=0,excluded_with=ID"`
+}
+
+// ToDateRange returns the dates asked for, with to moved to the start of the
+// next day so that the whole day is counted.
+func (q ReportQuery) ToDateRange() domain.DateRange {
+	dates := domain.DateRange{}
+	if q.From != nil {
+		from, _ := time.Parse(ReportDateLayout, *q.From)
+		dates.From = &from
+	}
+	if q.To != nil {
+		to, _ := time.Parse(ReportDateLayout, *q.To)
+		to = to.AddDate(0, 0, 1)
+		dates.To = &to
+	}
+	return dates
+}
+
+// topOrAll returns the number of entities to rank, zero meaning all of them.
+func topOrAll(top *int) int {
+	if top == nil {
+		return 0
+	}
+	return *top
+}
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section"
@@ -19,7 +18,6 @@ type CreateSectionRequest struct {
 	SectionNumber      *int     `json:"section_number" binding:"required"`
 	CurrentTemperature *float32 `json:"current_temperature" binding:"required"`
 	MinimumTemperature *float32 `json:"minimum_temperature" binding:"required"`
-	CurrentCapacity    *int     `json:"current_capacity" binding:"required"`
 	MinimumCapacity    *int     `json:"minimum_capacity" binding:"required"`
 	MaximumCapacity    *int     `json:"maximum_capacity" binding:"required"`
 	WarehouseID        *int     `json:"warehouse_id" binding:"required"`
@@ -32,7 +30,6 @@ func (r CreateSectionRequest) ToSection() domain.Section {
 		SectionNumber:      *r.SectionNumber,
 		CurrentTemperature: *r.CurrentTemperature,
 		MinimumTemperature: *r.MinimumTemperature,
-		CurrentCapacity:    *r.CurrentCapacity,
 		MinimumCapacity:    *r.MinimumCapacity,
 		MaximumCapacity:    *r.MaximumCapacity,
 		WarehouseID:        *r.WarehouseID,
@@ -40,30 +37,6 @@ func (r CreateSectionRequest) ToSection() domain.Section {
 	}
 }
 
-type UpdateSectionRequest struct {
-	SectionNumber      *int     `json:"section_number"`
-	CurrentTemperature *float32 `json:"current_temperature"`
-	MinimumTemperature *float32 `json:"minimum_temperature"`
-	CurrentCapacity    *int     `json:"current_capacity"`
-	MinimumCapacity    *int     `json:"minimum_capacity"`
-	MaximumCapacity    *int     `json:"maximum_capacity"`
-	WarehouseID        *int     `json:"warehouse_id"`
-	ProductTypeID      *int     `json:"product_type_id"`
-}
-
-func (r UpdateSectionRequest) ToUpdateSection() domain.UpdateSection {
-	return domain.UpdateSection{
-		SectionNumber:      r.SectionNumber,
-		CurrentTemperature: r.CurrentTemperature,
-		MinimumTemperature: r.MinimumTemperature,
-		CurrentCapacity:    r.CurrentCapacity,
-		MinimumCapacity:    r.MinimumCapacity,
-		MaximumCapacity:    r.MaximumCapacity,
-		WarehouseID:        r.WarehouseID,
-		ProductTypeID:      r.ProductTypeID,
-	}
-}
-
 func NewSection(s section.Service) *Section {
 	return &Section{
 		service: s,
@@ -75,11 +48,18 @@ func NewSection(s section.Service) *Section {
 // @Description Returns a collection of existing sections.
 // @Tags Sections
 // @Produce json
+// @Param ids query string false "Comma separated ids to get, instead of the whole collection"
 // @Success 200 {object} []domain.Section "Section"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sections [get]
 func (s *Section) GetAll() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if ids, ok := requestedIDs(ctx); ok {
+			web.Success(ctx, http.StatusOK, s.service.GetMany(ids))
+			return
+		}
+
 		sections := s.service.GetAll()
 		web.Success(ctx, http.StatusOK, sections)
 	}
@@ -92,9 +72,9 @@ func (s *Section) GetAll() gin.HandlerFunc {
 // @Produce json
 // @Param id path int true "Section ID"
 // @Success 200 {object} domain.Section "Section"
-// @Failure 400 {object} web.ErrorResponse"Validation error"
-// @Failure 404 {object} web.ErrorResponse "NotFound error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails"Validation error"
+// @Failure 404 {object} web.ProblemDetails "NotFound error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sections/{id} [get]
 func (s *Section) Get() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -104,7 +84,7 @@ func (s *Section) Get() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(ctx, http.StatusNotFound, err.Error())
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -120,9 +100,9 @@ func (s *Section) Get() gin.HandlerFunc {
 // @Produce json
 // @Param request body CreateSectionRequest true "Section data"
 // @Success 201 {object} domain.Section "Created section"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sections [post]
 func (s *Section) Create() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -132,11 +112,11 @@ func (s *Section) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(ctx, http.StatusConflict, err.Error())
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(ctx, http.StatusConflict, err.Error())
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 		}
@@ -147,37 +127,38 @@ func (s *Section) Create() gin.HandlerFunc {
 
 // Update godoc
 // @Summary Update a section
-// @Description Update section based on the provided JSON payload
+// @Description Update an existent section by applying a JSON Merge Patch or a JSON Patch to it.
 // @Tags Sections
-// @Accept json
+// @Accept json,application/merge-patch+json,application/json-patch+json
 // @Produce json
 // @Param id path int true "Section ID"
-// @Param request body UpdateSectionRequest true "Section data"
+// @Param request body object true "Merge patch or JSON patch"
 // @Success 200 {object} domain.Section "Updated section"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported patch format"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sections/{id} [patch]
 func (s *Section) Update() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		id := ctx.GetInt("Id")
-		request := ctx.MustGet(RequestParamContext).(UpdateSectionRequest)
+		current := ctx.MustGet(CurrentParamContext).(*domain.Section)
+		request := ctx.MustGet(RequestParamContext).(CreateSectionRequest)
 
-		response, err := s.service.Update(id, request.ToUpdateSection())
+		response, err := s.service.Update(*current, request.ToSection())
 
 		if err != nil {
-			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(ctx, http.StatusNotFound, err.Error())
-				return
-			}
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(ctx, http.StatusConflict, err.Error())
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(ctx, http.StatusConflict, err.Error())
+				web.ErrorFrom(ctx, http.StatusConflict, err)
+				return
+			}
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(ctx, http.StatusConflict, err)
 				return
 			}
 		}
@@ -191,9 +172,9 @@ func (s *Section) Update() gin.HandlerFunc {
 // @Description Delete section based on the provided JSON payload
 // @Tags Sections
 // @Success 204 "No content"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sections/{id} [delete]
 func (s *Section) Delete() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -203,7 +184,7 @@ func (s *Section) Delete() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(ctx, http.StatusNotFound, err.Error())
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -217,32 +198,60 @@ func (s *Section) Delete() gin.HandlerFunc {
 // @Description Return the report of products by section
 // @Description If no query param is given, it brings the report of all products by section
 // @Description If a section id is specified, it brings the number of products for this section.
+// @Description The from and to dates filter the product batches by manufacturing date.
+// @Description Disposed batches are left out, and batches split from the same batch by transfers count once.
+// @Description If group_by is given, it brings the number of product batches by day, week or month instead.
+// @Description If top is given, it brings only the sections with the most product batches.
+// @Description Send Accept text/csv or application/x-ndjson to export the report, streamed row by row when no filter other than the dates is given.
 // @Tags Sections
-// @Produce json
+// @Produce json,text/csv,application/x-ndjson
 // @Param id query int false "Section ID"
+// @Param from query string false "First day of the product batches, yyyy-mm-dd"
+// @Param to query string false "Last day of the product batches, yyyy-mm-dd"
+// @Param group_by query string false "Period of the time series" Enums(day, week, month)
+// @Param top query int false "Number of sections to rank"
 // @Success 200 {object} []domain.ProductsBySectionReport "Report of products by section"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Success 200 {object} []domain.ReportPeriod "Report of product batches by period"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sections/report-products [get]
 func (s *Section) ReportProducts() gin.HandlerFunc {
 	return func(c *gin.Context) {
 
-		idParam := c.Request.URL.Query().Get("id")
-		if idParam == "" {
-			result := s.service.CountProductsByAllSections()
-			web.Success(c, http.StatusOK, result)
+		query := c.MustGet(QueryParamContext).(ReportQuery)
+		dates := query.ToDateRange()
+
+		if query.GroupBy != nil {
+			periods, err := s.service.CountProductsByPeriod(query.ID, *query.GroupBy, dates)
+
+			if err != nil {
+				if apperr.Is[*apperr.ResourceNotFound](err) {
+					web.ErrorFrom(c, http.StatusNotFound, err)
+					return
+				}
+			}
+
+			web.Success(c, http.StatusOK, periods)
 			return
 		}
-		id, err := strconv.Atoi(idParam)
-		if err != nil {
-			web.Error(c, http.StatusBadRequest, InvalidId, idParam)
+
+		if query.ID == nil && query.Top == nil && web.Streaming(c) {
+			web.Stream(c, http.StatusOK, func(yield func(domain.ProductsBySectionReport)) {
+				s.service.StreamProductsByAllSections(dates, yield)
+			})
+			return
+		}
+
+		if query.ID == nil {
+			result := s.service.CountProductsByAllSections(dates, topOrAll(query.Top))
+			web.Success(c, http.StatusOK, result)
 			return
 		}
-		result, err := s.service.CountProductsBySection(id)
+		result, err := s.service.CountProductsBySection(*query.ID, dates)
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -250,3 +259,31 @@ func (s *Section) ReportProducts() gin.HandlerFunc {
 		web.Success(c, http.StatusOK, result)
 	}
 }
+
+// Occupancy godoc
+// @Summary Get the occupancy of a section
+// @Description Return the used and free capacity of a section, derived from the current quantity of its batches.
+// @Tags Sections
+// @Produce json
+// @Param id path int true "Section ID"
+// @Success 200 {object} domain.SectionOccupancy "Section occupancy"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /sections/{id}/occupancy [get]
+func (s *Section) Occupancy() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+
+		occupancy, err := s.service.Occupancy(id)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(ctx, http.StatusOK, occupancy)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section/mocks"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -36,7 +37,6 @@ func TestCreateSection(t *testing.T) {
 		SectionNumber:      &s.SectionNumber,
 		CurrentTemperature: &s.CurrentTemperature,
 		MinimumTemperature: &s.MinimumTemperature,
-		CurrentCapacity:    &s.CurrentCapacity,
 		MinimumCapacity:    &s.MinimumCapacity,
 		MaximumCapacity:    &s.MaximumCapacity,
 		WarehouseID:        &s.WarehouseID,
@@ -99,6 +99,20 @@ func TestGetSection(t *testing.T) {
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
 
+	t.Run("Should return the sections with the given ids", func(t *testing.T) {
+		server, service, controller := initSectionServer(t)
+
+		server.GET(DefinePath(resourceSectionUri), middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
+		request, response := MakeRequest("GET", DefinePath(resourceSectionUri)+"?ids=1,2", "")
+
+		service.On("GetMany", []int{1, 2}).Return([]domain.Section{{ID: 1}, {ID: 2}})
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		service.AssertNotCalled(t, "GetAll")
+	})
+
 	t.Run("Should return not found error", func(t *testing.T) {
 		server, service, controller := initSectionServer(t)
 
@@ -132,46 +146,47 @@ func TestGetSection(t *testing.T) {
 }
 
 func TestUpdateSection(t *testing.T) {
-	requestObject := handler.UpdateSectionRequest{
+	requestObject := handler.CreateSectionRequest{
 		SectionNumber:      &s.SectionNumber,
 		CurrentTemperature: &s.CurrentTemperature,
 		MinimumTemperature: &s.MinimumTemperature,
-		CurrentCapacity:    &s.CurrentCapacity,
 		MinimumCapacity:    &s.MinimumCapacity,
 		MaximumCapacity:    &s.MaximumCapacity,
 		WarehouseID:        &s.WarehouseID,
 		ProductTypeID:      &s.ProductTypeID,
 	}
 
-	t.Run("Should return not found error", func(t *testing.T) {
+	t.Run("Should return conflict error when the section was modified", func(t *testing.T) {
 		server, service, controller := initSectionServer(t)
 
 		id := 2
+		current := domain.Section{ID: id}
 
-		server.PATCH(DefinePath(resourceSectionUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(resourceSectionUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(resourceSectionUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Section
 		service.On(
-			"Update", id, requestObject.ToUpdateSection()).
-			Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+			"Update", current, requestObject.ToSection()).
+			Return(serviceReturn, apperr.NewIncompatibleResource(ResourceModified))
 
 		server.ServeHTTP(response, request)
 
-		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Equal(t, http.StatusConflict, response.Code)
 	})
 
 	t.Run("Should return conflict error", func(t *testing.T) {
 		server, service, controller := initSectionServer(t)
 
 		id := 1
+		current := domain.Section{ID: id}
 
-		server.PATCH(DefinePath(resourceSectionUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(resourceSectionUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(resourceSectionUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Section
 		service.On(
-			"Update", id, requestObject.ToUpdateSection()).
+			"Update", current, requestObject.ToSection()).
 			Return(serviceReturn, apperr.NewResourceAlreadyExists(ResourceAlreadyExists))
 
 		server.ServeHTTP(response, request)
@@ -183,13 +198,14 @@ func TestUpdateSection(t *testing.T) {
 		server, service, controller := initSectionServer(t)
 
 		id := 1
+		current := domain.Section{ID: id}
 
-		server.PATCH(DefinePath(resourceSectionUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(resourceSectionUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(resourceSectionUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Section
 		service.On(
-			"Update", id, requestObject.ToUpdateSection()).
+			"Update", current, requestObject.ToSection()).
 			Return(serviceReturn, apperr.NewDependentResourceNotFound(ResourceNotFound))
 
 		server.ServeHTTP(response, request)
@@ -197,16 +213,36 @@ func TestUpdateSection(t *testing.T) {
 		assert.Equal(t, http.StatusConflict, response.Code)
 	})
 
+	t.Run("Should return conflict error when capacity is below usage", func(t *testing.T) {
+		server, service, controller := initSectionServer(t)
+
+		id := 1
+		current := domain.Section{ID: id}
+
+		server.PATCH(DefinePath(resourceSectionUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
+		request, response := MakeRequest("PATCH", DefinePathWithId(resourceSectionUri, id), CreateBody(requestObject))
+
+		var serviceReturn *domain.Section
+		service.On(
+			"Update", current, requestObject.ToSection()).
+			Return(serviceReturn, apperr.NewIncompatibleResource(ResourceAlreadyExists))
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
 	t.Run("Should return updated section", func(t *testing.T) {
 		server, service, controller := initSectionServer(t)
 
 		id := 1
+		current := domain.Section{ID: id}
 
-		server.PATCH(DefinePath(resourceSectionUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(resourceSectionUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(resourceSectionUri, id), CreateBody(requestObject))
 
 		service.On(
-			"Update", id, requestObject.ToUpdateSection()).
+			"Update", current, requestObject.ToSection()).
 			Return(&s, nil)
 
 		server.ServeHTTP(response, request)
@@ -251,19 +287,48 @@ func TestReportProducts(t *testing.T) {
 	t.Run("Should return products count by all sections", func(t *testing.T) {
 		server, service, controller := initSectionServer(t)
 
-		server.GET(DefinePath(resourceSectionUri), controller.ReportProducts())
+		server.GET(DefinePath(resourceSectionUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportProducts())
 		request, response := MakeRequest("GET", DefinePath(resourceSectionUri), "")
 
-		service.On("CountProductsByAllSections").Return([]domain.ProductsBySectionReport{}, nil)
+		service.On("CountProductsByAllSections", domain.DateRange{}, 0).Return([]domain.ProductsBySectionReport{}, nil)
 
 		server.ServeHTTP(response, request)
 
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
+	t.Run("Should stream products count by all sections as CSV", func(t *testing.T) {
+		server, service, controller := initSectionServer(t)
+
+		server.GET(DefinePath(resourceSectionUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportProducts())
+		request, response := MakeRequest("GET", DefinePath(resourceSectionUri), "")
+		request.Header.Set("Accept", "text/csv")
+
+		service.On("StreamProductsByAllSections", domain.DateRange{}).Return([]domain.ProductsBySectionReport{{SectionID: 1, SectionNumber: 10, ProductsCount: 4}})
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "section_id,section_number,products_count\n1,10,4\n", response.Body.String())
+	})
+	t.Run("Should export the top sections as NDJSON", func(t *testing.T) {
+		server, service, controller := initSectionServer(t)
+
+		server.GET(DefinePath(resourceSectionUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportProducts())
+		request, response := MakeRequest("GET", DefinePath(resourceSectionUri)+"?top=1", "")
+		request.Header.Set("Accept", "application/x-ndjson")
+
+		service.On("CountProductsByAllSections", domain.DateRange{}, 1).Return([]domain.ProductsBySectionReport{{SectionID: 1, SectionNumber: 10, ProductsCount: 4}})
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, web.NDJSONContentType, response.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"section_id":1,"section_number":10,"products_count":4}`, response.Body.String())
+	})
 	t.Run("Should return invalid id error", func(t *testing.T) {
 		server, _, controller := initSectionServer(t)
 
-		server.GET(DefinePath(resourceSectionUri), controller.ReportProducts())
+		server.GET(DefinePath(resourceSectionUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportProducts())
 		request, response := MakeRequest("GET", DefinePath(resourceSectionUri)+"?id=abc", "")
 
 		server.ServeHTTP(response, request)
@@ -273,12 +338,12 @@ func TestReportProducts(t *testing.T) {
 	t.Run("Should return not found error", func(t *testing.T) {
 		server, service, controller := initSectionServer(t)
 
-		server.GET(DefinePath(resourceSectionUri), controller.ReportProducts())
+		server.GET(DefinePath(resourceSectionUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportProducts())
 		request, response := MakeRequest("GET", DefinePath(resourceSectionUri)+"?id=1", "")
 
 		productId := 1
 		var serviceReturn *domain.ProductsBySectionReport
-		service.On("CountProductsBySection", productId).Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+		service.On("CountProductsBySection", productId, domain.DateRange{}).Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
 
 		server.ServeHTTP(response, request)
 
@@ -287,7 +352,7 @@ func TestReportProducts(t *testing.T) {
 	t.Run("Should return products count by section", func(t *testing.T) {
 		server, service, controller := initSectionServer(t)
 
-		server.GET(DefinePath(resourceSectionUri), controller.ReportProducts())
+		server.GET(DefinePath(resourceSectionUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportProducts())
 		request, response := MakeRequest("GET", DefinePath(resourceSectionUri)+"?id=1", "")
 
 		productId := 1
@@ -296,13 +361,62 @@ func TestReportProducts(t *testing.T) {
 			SectionNumber: 1,
 			ProductsCount: 1,
 		}
-		service.On("CountProductsBySection", productId).Return(&serviceReturn, nil)
+		service.On("CountProductsBySection", productId, domain.DateRange{}).Return(&serviceReturn, nil)
 
 		server.ServeHTTP(response, request)
 
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
 
+	t.Run("Should return not found error when the id of the time series does not exist", func(t *testing.T) {
+		server, service, controller := initSectionServer(t)
+
+		server.GET(DefinePath(resourceSectionUri), middleware.QueryValidation[handler.ReportQuery](), controller.ReportProducts())
+		request, response := MakeRequest("GET", DefinePath(resourceSectionUri)+"?group_by=day&id=1", "")
+
+		id := 1
+		var periods []domain.ReportPeriod
+		service.On("CountProductsByPeriod", &id, domain.PeriodDay, domain.DateRange{}).Return(periods, apperr.NewResourceNotFound(ResourceNotFound))
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestSectionOccupancy(t *testing.T) {
+	t.Run("Should return not found error", func(t *testing.T) {
+		server, service, controller := initSectionServer(t)
+
+		id := 2
+
+		server.GET(DefinePath(resourceSectionUri)+"/:id/occupancy", controller.Occupancy())
+		request, response := MakeRequest("GET", DefinePathWithId(resourceSectionUri, id)+"/occupancy", "")
+
+		var serviceReturn *domain.SectionOccupancy
+		service.On("Occupancy", id).Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Should return the section occupancy", func(t *testing.T) {
+		server, service, controller := initSectionServer(t)
+
+		id := 1
+
+		server.GET(DefinePath(resourceSectionUri)+"/:id/occupancy", controller.Occupancy())
+		request, response := MakeRequest("GET", DefinePathWithId(resourceSectionUri, id)+"/occupancy", "")
+
+		occupancy := domain.SectionOccupancy{SectionID: id, MaximumCapacity: 10, Used: 5, Free: 5, Percent: 50}
+		service.On("Occupancy", id).Return(&occupancy, nil)
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":{"section_id":1,"maximum_capacity":10,"used":5,"free":5,"percent":50}}`, response.Body.String())
+	})
 }
 
 func initSectionServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.Section) {
@@ -34,26 +34,6 @@ func (s CreateSellerRequest) ToSeller() domain.Seller {
 	}
 }
 
-type UpdateSellerRequest struct {
-	CID         *int    `json:"cid"`
-	CompanyName *string `json:"company_name"`
-	Address     *string `json:"address"`
-	Telephone   *string `json:"telephone" binding:"omitempty,e164"`
-	LocalityID  *int    `json:"locality_id" binding:"omitempty"`
-}
-
-func (s UpdateSellerRequest) ToUpdateSeller() domain.UpdateSeller {
-	*s.Address = helpers.ToFormattedAddress(*s.Address)
-
-	return domain.UpdateSeller{
-		CID:         s.CID,
-		CompanyName: s.CompanyName,
-		Address:     s.Address,
-		Telephone:   s.Telephone,
-		LocalityID:  s.LocalityID,
-	}
-}
-
 func NewSeller(service seller.Service) *Seller {
 	return &Seller{service}
 }
@@ -63,11 +43,18 @@ func NewSeller(service seller.Service) *Seller {
 // @Description Returns a collection of existing sellers.
 // @Tags Sellers
 // @Produce json
+// @Param ids query string false "Comma separated ids to get, instead of the whole collection"
 // @Success 200 {object} []domain.Seller "List of all sellers"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sellers [get]
 func (s *Seller) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if ids, ok := requestedIDs(c); ok {
+			web.Success(c, http.StatusOK, s.service.GetMany(ids))
+			return
+		}
+
 		sellers := s.service.GetAll()
 		web.Success(c, http.StatusOK, sellers)
 	}
@@ -80,9 +67,9 @@ func (s *Seller) GetAll() gin.HandlerFunc {
 // @Produce json
 // @Param id path int true "Seller Id"
 // @Success 200 {object} []domain.Seller "Obtained seller"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sellers/{id} [get]
 func (s *Seller) Get() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -92,7 +79,7 @@ func (s *Seller) Get() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -109,9 +96,9 @@ func (s *Seller) Get() gin.HandlerFunc {
 // @Produce json
 // @Param request body CreateSellerRequest true "Seller to be created"
 // @Success 201 {object} domain.Seller "Created seller"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sellers [post]
 func (s *Seller) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -121,11 +108,11 @@ func (s *Seller) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -134,41 +121,66 @@ func (s *Seller) Create() gin.HandlerFunc {
 	}
 }
 
+// Import godoc
+// @Summary Import sellers
+// @Description Create sellers in bulk from a CSV file, whose header names the fields of the seller, or from NDJSON, one seller per line.
+// @Description Each row is validated as on creation, and a cid repeated in the file is rejected.
+// @Description By default nothing is saved if any row is rejected; with mode best_effort the valid rows are saved.
+// @Description With dry_run the rows are only checked.
+// @Tags Sellers
+// @Accept text/csv,application/x-ndjson
+// @Produce json
+// @Param dry_run query bool false "Only check the rows"
+// @Param mode query string false "Commit mode" Enums(all_or_nothing, best_effort)
+// @Success 200 {object} domain.ImportReport "Report of the dry run"
+// @Success 201 {object} domain.ImportReport "Report of the import"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported file format"
+// @Failure 422 {object} domain.ImportReport "Report of the rejected import"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /sellers/import [post]
+func (s *Seller) Import() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondImport(c, CreateSellerRequest.ToSeller, s.service.Import)
+	}
+}
+
 // Update godoc
 // @Summary Update a seller
-// @Description Update an existent seller based on the provided id and JSON payload.
+// @Description Update an existent seller by applying a JSON Merge Patch or a JSON Patch to it.
 // @Tags Sellers
-// @Accept json
+// @Accept json,application/merge-patch+json,application/json-patch+json
 // @Produce json
 // @Param id path int true "Seller id"
-// @Param seller body UpdateSellerRequest true "Seller data to be updated"
+// @Param seller body object true "Merge patch or JSON patch"
 // @Success 200 {object} domain.Seller "Updated seller"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported patch format"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sellers/{id} [patch]
 func (s *Seller) Update() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.GetInt("Id")
-		request := c.MustGet(RequestParamContext).(UpdateSellerRequest)
+		current := c.MustGet(CurrentParamContext).(*domain.Seller)
+		request := c.MustGet(RequestParamContext).(CreateSellerRequest)
 
-		response, err := s.service.Update(id, request.ToUpdateSeller())
+		response, err := s.service.Update(*current, request.ToSeller())
 
 		if err != nil {
-			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -183,9 +195,9 @@ func (s *Seller) Update() gin.HandlerFunc {
 // @Tags Sellers
 // @Param id path int true "Seller id"
 // @Success 204 "No content"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /sellers/{id} [delete]
 func (s *Seller) Delete() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -195,7 +207,7 @@ func (s *Seller) Delete() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -93,6 +93,20 @@ func TestGetSeller(t *testing.T) {
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
 
+	t.Run("Should return the sellers with the given ids", func(t *testing.T) {
+		server, service, controller := InitSellerServer(t)
+
+		server.GET(DefinePath(ResourceSellersUri), middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
+		request, response := MakeRequest("GET", DefinePath(ResourceSellersUri)+"?ids=1,2", "")
+
+		service.On("GetMany", []int{1, 2}).Return([]domain.Seller{{ID: 1}, {ID: 2}})
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		service.AssertNotCalled(t, "GetAll")
+	})
+
 	t.Run("Should return not found error", func(t *testing.T) {
 		server, service, controller := InitSellerServer(t)
 
@@ -126,42 +140,45 @@ func TestGetSeller(t *testing.T) {
 }
 
 func TestUpdateSeller(t *testing.T) {
-	requestObject := handler.UpdateSellerRequest{
+	requestObject := handler.CreateSellerRequest{
 		CID:         &mockedSeller.CID,
 		CompanyName: &mockedSeller.CompanyName,
 		Address:     &mockedSeller.Address,
 		Telephone:   &mockedSeller.Telephone,
+		LocalityID:  &mockedSeller.LocalityID,
 	}
 
-	t.Run("Should return not found error", func(t *testing.T) {
+	t.Run("Should return conflict error when the seller was modified", func(t *testing.T) {
 		server, service, controller := InitSellerServer(t)
 
 		id := 1
+		current := domain.Seller{ID: id}
 
-		server.PATCH(DefinePath(ResourceSellersUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceSellersUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceSellersUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Seller
 		service.On(
-			"Update", id, requestObject.ToUpdateSeller()).
-			Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+			"Update", current, requestObject.ToSeller()).
+			Return(serviceReturn, apperr.NewIncompatibleResource(ResourceModified))
 
 		server.ServeHTTP(response, request)
 
-		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Equal(t, http.StatusConflict, response.Code)
 	})
 
 	t.Run("Should return conflict error when cid already exists", func(t *testing.T) {
 		server, service, controller := InitSellerServer(t)
 
 		id := 1
+		current := domain.Seller{ID: id}
 
-		server.PATCH(DefinePath(ResourceSellersUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceSellersUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceSellersUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Seller
 		service.On(
-			"Update", id, requestObject.ToUpdateSeller()).
+			"Update", current, requestObject.ToSeller()).
 			Return(serviceReturn, apperr.NewResourceAlreadyExists(ResourceAlreadyExists))
 
 		server.ServeHTTP(response, request)
@@ -173,13 +190,14 @@ func TestUpdateSeller(t *testing.T) {
 		server, service, controller := InitSellerServer(t)
 
 		id := 1
+		current := domain.Seller{ID: id}
 
-		server.PATCH(DefinePath(ResourceSellersUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceSellersUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceSellersUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Seller
 		service.On(
-			"Update", id, requestObject.ToUpdateSeller()).
+			"Update", current, requestObject.ToSeller()).
 			Return(serviceReturn, apperr.NewDependentResourceNotFound(ResourceNotFound))
 
 		server.ServeHTTP(response, request)
@@ -191,12 +209,13 @@ func TestUpdateSeller(t *testing.T) {
 		server, service, controller := InitSellerServer(t)
 
 		id := 1
+		current := domain.Seller{ID: id}
 
-		server.PATCH(DefinePath(ResourceSellersUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceSellersUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceSellersUri, id), CreateBody(requestObject))
 
 		service.On(
-			"Update", id, requestObject.ToUpdateSeller()).
+			"Update", current, requestObject.ToSeller()).
 			Return(&mockedSeller, nil)
 
 		server.ServeHTTP(response, request)
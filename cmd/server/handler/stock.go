@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/stock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type Stock struct {
+	service stock.Service
+}
+
+type StockQuery struct {
+	ProductID     *int `form:"product_id"`
+	SellerID      *int `form:"seller_id"`
+	ProductTypeID *int `form:"product_type_id"`
+	WarehouseID   *int `form:"warehouse_id"`
+}
+
+func (q StockQuery) ToStockFilter() domain.StockFilter {
+	return domain.StockFilter{
+		ProductID:     q.ProductID,
+		SellerID:      q.SellerID,
+		ProductTypeID: q.ProductTypeID,
+		WarehouseID:   q.WarehouseID,
+	}
+}
+
+func NewStock(s stock.Service) *Stock {
+	return &Stock{
+		service: s,
+	}
+}
+
+// Report godoc
+// @Summary Report the stock on hand
+// @Description Sum the current quantity of the batches by product, warehouse and section.
+// @Description The quantity is broken down into expired, due in 7 days, due in 30 days and due later.
+// @Tags Reports
+// @Produce json
+// @Param product_id query int false "Product ID"
+// @Param seller_id query int false "Seller ID"
+// @Param product_type_id query int false "Product type ID"
+// @Param warehouse_id query int false "Warehouse ID"
+// @Success 200 {object} []domain.StockReport "Stock on hand"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /reports/stock [get]
+func (s *Stock) Report() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query := ctx.MustGet(QueryParamContext).(StockQuery)
+
+		web.Success(ctx, http.StatusOK, s.service.Report(query.ToStockFilter()))
+	}
+}
+
+// ProductStock godoc
+// @Summary Report the stock on hand of a product
+// @Description Sum the current quantity of the batches of the product by warehouse and section.
+// @Description The quantity is broken down into expired, due in 7 days, due in 30 days and due later.
+// @Tags Products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} []domain.StockReport "Stock on hand"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /products/{id}/stock [get]
+func (s *Stock) ProductStock() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+
+		reports, err := s.service.ReportByProduct(id)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(ctx, http.StatusOK, reports)
+	}
+}
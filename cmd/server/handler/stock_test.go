@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/stock/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	stockReports = []domain.StockReport{
+		{ProductID: 1, ProductCode: "P001", WarehouseID: 1, SectionID: 1, SectionNumber: 10, Quantity: 200, DueLater: 200},
+	}
+)
+
+func TestStockReport(t *testing.T) {
+	t.Run("Should return the stock for the filter", func(t *testing.T) {
+		server, service, controller := InitStockServer(t)
+
+		sellerID := 1
+		query := handler.StockQuery{SellerID: &sellerID}
+		server.GET(DefinePath("/reports/stock"), middleware.QueryValidation[handler.StockQuery](), controller.Report())
+		request, response := MakeRequest("GET", DefinePath("/reports/stock")+"?seller_id=1", "")
+
+		service.On("Report", query.ToStockFilter()).Return(stockReports)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":[{"product_id":1,"product_code":"P001","warehouse_id":1,"section_id":1,"section_number":10,"quantity":200,"expired":0,"due_in_7_days":0,"due_in_30_days":0,"due_later":200}]}`, response.Body.String())
+	})
+}
+
+func TestProductStock(t *testing.T) {
+	route := DefinePath("/products/:id/stock")
+	path := DefinePathWithId("/products", 1) + "/stock"
+
+	t.Run("Should return the stock of the product", func(t *testing.T) {
+		server, service, controller := InitStockServer(t)
+
+		server.GET(route, controller.ProductStock())
+		request, response := MakeRequest("GET", path, "")
+
+		service.On("ReportByProduct", 1).Return(stockReports, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should return not found error when the product does not exist", func(t *testing.T) {
+		server, service, controller := InitStockServer(t)
+
+		server.GET(route, controller.ProductStock())
+		request, response := MakeRequest("GET", path, "")
+
+		var reports []domain.StockReport
+		service.On("ReportByProduct", 1).Return(reports, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func InitStockServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.Stock) {
+	t.Helper()
+	server := CreateServer()
+	server.Use(middleware.IdValidation())
+	service := new(mocks.Service)
+	controller := handler.NewStock(service)
+	return server, service, controller
+}
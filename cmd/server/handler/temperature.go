@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/temperature"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultReadingsWindow is the period returned when no range is given.
+const DefaultReadingsWindow = 24 * time.Hour
+
+type Temperature struct {
+	service temperature.Service
+}
+
+type TemperatureReadingRequest struct {
+	Temperature *float32 `json:"temperature" binding:"required"`
+	RecordedAt  *string  `json:"recorded_at" binding:"omitempty,datetime=2006-01-02 15:04:05"`
+}
+
+func (r TemperatureReadingRequest) ToTemperatureReading(sectionID int) domain.TemperatureReading {
+	recordedAt := time.Now().UTC().Truncate(time.Second)
+	if r.RecordedAt != nil {
+		recordedAt = helpers.ToDateTime(*r.RecordedAt)
+	}
+
+	return domain.TemperatureReading{
+		SectionID:   sectionID,
+		Temperature: *r.Temperature,
+		RecordedAt:  recordedAt,
+	}
+}
+
+type TemperatureReadingBatchRequest struct {
+	Readings []TemperatureReadingRequest `json:"readings" binding:"required,min=1,max=1000,dive"`
+}
+
+type TemperatureReadingQuery struct {
+	From     *string `form:"from" binding:"omitempty,datetime=2006-01-02 15:04:05"`
+	To       *string `form:"to" binding:"omitempty,datetime=2006-01-02 15:04:05"`
+	Interval *int    `form:"interval" binding:"omitempty,gt=0"`
+}
+
+// Range returns the period asked for, defaulting to the last day.
+func (q TemperatureReadingQuery) Range() (time.Time, time.Time) {
+	to := time.Now().UTC().Truncate(time.Second)
+	if q.To != nil {
+		to = helpers.ToDateTime(*q.To)
+	}
+
+	from := to.Add(-DefaultReadingsWindow)
+	if q.From != nil {
+		from = helpers.ToDateTime(*q.From)
+	}
+
+	return from, to
+}
+
+func NewTemperature(s temperature.Service) *Temperature {
+	return &Temperature{
+		service: s,
+	}
+}
+
+// Record godoc
+// @Summary Record a temperature reading of a section
+// @Description Store the reading and set it as the last temperature of the section and the current temperature of its batches.
+// @Description A breach is raised when the reading is below the minimum temperature of the section or of one of its batches with stock left.
+// @Description The recorded_at field defaults to the current time.
+// @Tags Sections
+// @Accept json
+// @Produce json
+// @Param id path int true "Section ID"
+// @Param request body TemperatureReadingRequest true "Temperature reading"
+// @Success 201 {object} domain.TemperatureIngestion "Recorded readings and raised breaches"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /sections/{id}/temperature-readings [post]
+func (t *Temperature) Record() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+		request := ctx.MustGet(RequestParamContext).(TemperatureReadingRequest)
+
+		t.record(ctx, id, []domain.TemperatureReading{request.ToTemperatureReading(id)})
+	}
+}
+
+// RecordBatch godoc
+// @Summary Record many temperature readings of a section
+// @Description Store up to 1000 readings at once. The latest one becomes the last temperature of the section and the current temperature of its batches.
+// @Description A breach is raised for each reading below the minimum temperature of the section or of one of its batches with stock left.
+// @Tags Sections
+// @Accept json
+// @Produce json
+// @Param id path int true "Section ID"
+// @Param request body TemperatureReadingBatchRequest true "Temperature readings"
+// @Success 201 {object} domain.TemperatureIngestion "Recorded readings and raised breaches"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /sections/{id}/temperature-readings/batch [post]
+func (t *Temperature) RecordBatch() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+		request := ctx.MustGet(RequestParamContext).(TemperatureReadingBatchRequest)
+
+		readings := make([]domain.TemperatureReading, 0, len(request.Readings))
+		for _, r := range request.Readings {
+			readings = append(readings, r.ToTemperatureReading(id))
+		}
+
+		t.record(ctx, id, readings)
+	}
+}
+
+// Readings godoc
+// @Summary List the temperature readings of a section
+// @Description Return the readings recorded between from (inclusive) and to (exclusive), by default the last 24 hours.
+// @Description When an interval in seconds is given, readings are downsampled into one sample per interval with their average, minimum and maximum.
+// @Tags Sections
+// @Produce json
+// @Param id path int true "Section ID"
+// @Param from query string false "Start of the period (yyyy-mm-dd hh:mm:ss)"
+// @Param to query string false "End of the period (yyyy-mm-dd hh:mm:ss)"
+// @Param interval query int false "Downsampling interval in seconds"
+// @Success 200 {object} []domain.TemperatureSample "Temperature samples"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /sections/{id}/temperature-readings [get]
+func (t *Temperature) Readings() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+		query := ctx.MustGet(QueryParamContext).(TemperatureReadingQuery)
+
+		var interval time.Duration
+		if query.Interval != nil {
+			interval = time.Duration(*query.Interval) * time.Second
+		}
+		from, to := query.Range()
+
+		samples, err := t.service.Samples(id, from, to, interval)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(ctx, http.StatusOK, samples)
+	}
+}
+
+// Breaches godoc
+// @Summary List the temperature breaches of a section
+// @Description Return the readings that were below the minimum temperature of the section or of one of its batches with stock left.
+// @Tags Sections
+// @Produce json
+// @Param id path int true "Section ID"
+// @Success 200 {object} []domain.TemperatureBreach "Temperature breaches"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /sections/{id}/temperature-breaches [get]
+func (t *Temperature) Breaches() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetInt("Id")
+
+		breaches, err := t.service.Breaches(id)
+
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(ctx, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(ctx, http.StatusOK, breaches)
+	}
+}
+
+func (t *Temperature) record(ctx *gin.Context, sectionID int, readings []domain.TemperatureReading) {
+	ingestion, err := t.service.Record(sectionID, readings)
+
+	if err != nil {
+		if apperr.Is[*apperr.ResourceNotFound](err) {
+			web.ErrorFrom(ctx, http.StatusNotFound, err)
+			return
+		}
+	}
+
+	web.Success(ctx, http.StatusCreated, ingestion)
+}
@@ -0,0 +1,169 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/temperature/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ResourceSectionsUri = "/sections"
+)
+
+var (
+	readingRecordedAt = time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC)
+)
+
+func TestRecordTemperatureReading(t *testing.T) {
+	route := DefinePath(ResourceSectionsUri) + "/:id/temperature-readings"
+	path := DefinePathWithId(ResourceSectionsUri, 1) + "/temperature-readings"
+
+	value := float32(-22)
+	recordedAt := "2023-07-10 08:00:00"
+	requestObject := handler.TemperatureReadingRequest{Temperature: &value, RecordedAt: &recordedAt}
+	readings := []domain.TemperatureReading{{SectionID: 1, Temperature: value, RecordedAt: readingRecordedAt}}
+
+	t.Run("Should return the recorded reading and its breaches", func(t *testing.T) {
+		server, service, controller := InitTemperatureServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Record())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		ingestion := &domain.TemperatureIngestion{
+			Recorded: 1,
+			Breaches: []domain.TemperatureBreach{{ID: 1, SectionID: 1, Temperature: value, MinimumTemperature: -20, RecordedAt: readingRecordedAt}},
+		}
+		service.On("Record", 1, readings).Return(ingestion, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, `{"data":{"recorded":1,"breaches":[{"id":1,"section_id":1,"product_batch_id":null,"temperature":-22,"minimum_temperature":-20,"recorded_at":"2023-07-10T08:00:00Z"}]}}`, response.Body.String())
+	})
+	t.Run("Should return not found error when the section does not exist", func(t *testing.T) {
+		server, service, controller := InitTemperatureServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.Record())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		var ingestion *domain.TemperatureIngestion
+		service.On("Record", 1, readings).Return(ingestion, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestRecordTemperatureReadingBatch(t *testing.T) {
+	route := DefinePath(ResourceSectionsUri) + "/:id/temperature-readings/batch"
+	path := DefinePathWithId(ResourceSectionsUri, 1) + "/temperature-readings/batch"
+
+	first, second := float32(-18), float32(-19)
+	firstAt, secondAt := "2023-07-10 08:00:00", "2023-07-10 08:01:00"
+	requestObject := handler.TemperatureReadingBatchRequest{Readings: []handler.TemperatureReadingRequest{
+		{Temperature: &first, RecordedAt: &firstAt},
+		{Temperature: &second, RecordedAt: &secondAt},
+	}}
+	readings := []domain.TemperatureReading{
+		{SectionID: 1, Temperature: first, RecordedAt: readingRecordedAt},
+		{SectionID: 1, Temperature: second, RecordedAt: readingRecordedAt.Add(time.Minute)},
+	}
+
+	t.Run("Should record every reading", func(t *testing.T) {
+		server, service, controller := InitTemperatureServer(t)
+
+		server.POST(route, ValidationMiddleware(requestObject), controller.RecordBatch())
+		request, response := MakeRequest("POST", path, CreateBody(requestObject))
+
+		service.On("Record", 1, readings).Return(&domain.TemperatureIngestion{Recorded: 2, Breaches: []domain.TemperatureBreach{}}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, `{"data":{"recorded":2,"breaches":[]}}`, response.Body.String())
+	})
+}
+
+func TestTemperatureReadings(t *testing.T) {
+	route := DefinePath(ResourceSectionsUri) + "/:id/temperature-readings"
+	path := DefinePathWithId(ResourceSectionsUri, 1) + "/temperature-readings"
+
+	t.Run("Should return the samples of the period", func(t *testing.T) {
+		server, service, controller := InitTemperatureServer(t)
+
+		server.GET(route, middleware.QueryValidation[handler.TemperatureReadingQuery](), controller.Readings())
+		request, response := MakeRequest("GET", path+"?from=2023-07-10+08:00:00&to=2023-07-10+09:00:00&interval=900", "")
+
+		samples := []domain.TemperatureSample{{From: readingRecordedAt, Average: -11, Minimum: -12, Maximum: -10, Count: 3}}
+		service.On("Samples", 1, readingRecordedAt, readingRecordedAt.Add(time.Hour), 15*time.Minute).Return(samples, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"data":[{"from":"2023-07-10T08:00:00Z","average":-11,"minimum":-12,"maximum":-10,"count":3}]}`, response.Body.String())
+	})
+	t.Run("Should return bad request error when the interval is not positive", func(t *testing.T) {
+		server, _, controller := InitTemperatureServer(t)
+
+		server.GET(route, middleware.QueryValidation[handler.TemperatureReadingQuery](), controller.Readings())
+		request, response := MakeRequest("GET", path+"?interval=0", "")
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+	t.Run("Should return not found error when the section does not exist", func(t *testing.T) {
+		server, service, controller := InitTemperatureServer(t)
+
+		server.GET(route, middleware.QueryValidation[handler.TemperatureReadingQuery](), controller.Readings())
+		request, response := MakeRequest("GET", path+"?from=2023-07-10+08:00:00&to=2023-07-10+09:00:00", "")
+
+		var samples []domain.TemperatureSample
+		service.On("Samples", 1, readingRecordedAt, readingRecordedAt.Add(time.Hour), time.Duration(0)).Return(samples, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestTemperatureBreaches(t *testing.T) {
+	route := DefinePath(ResourceSectionsUri) + "/:id/temperature-breaches"
+	path := DefinePathWithId(ResourceSectionsUri, 1) + "/temperature-breaches"
+
+	t.Run("Should return the breaches of the section", func(t *testing.T) {
+		server, service, controller := InitTemperatureServer(t)
+
+		server.GET(route, controller.Breaches())
+		request, response := MakeRequest("GET", path, "")
+
+		service.On("Breaches", 1).Return([]domain.TemperatureBreach{}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should return not found error when the section does not exist", func(t *testing.T) {
+		server, service, controller := InitTemperatureServer(t)
+
+		server.GET(route, controller.Breaches())
+		request, response := MakeRequest("GET", path, "")
+
+		var breaches []domain.TemperatureBreach
+		service.On("Breaches", 1).Return(breaches, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func InitTemperatureServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.Temperature) {
+	t.Helper()
+	server := CreateServer()
+	server.Use(middleware.IdValidation())
+	service := new(mocks.Service)
+	controller := handler.NewTemperature(service)
+	return server, service, controller
+}
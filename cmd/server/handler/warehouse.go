@@ -32,28 +32,6 @@ func (w CreateWarehouseRequest) ToWarehouse() domain.Warehouse {
 	}
 }
 
-type UpdateWarehouseRequest struct {
-	Address            *string `json:"address"`
-	Telephone          *string `json:"telephone"`
-	WarehouseCode      *string `json:"warehouse_code"`
-	MinimumCapacity    *int    `json:"minimum_capacity"`
-	MinimumTemperature *int    `json:"minimum_temperature"`
-	LocalityID         *int    `json:"locality_id"`
-}
-
-func (w UpdateWarehouseRequest) ToUpdateWarehouse() domain.UpdateWarehouse {
-	*w.Address = helpers.ToFormattedAddress(*w.Address)
-
-	return domain.UpdateWarehouse{
-		Address:            w.Address,
-		Telephone:          w.Telephone,
-		WarehouseCode:      w.WarehouseCode,
-		MinimumCapacity:    w.MinimumCapacity,
-		MinimumTemperature: w.MinimumTemperature,
-		LocalityID:         w.LocalityID,
-	}
-}
-
 type Warehouse struct {
 	service warehouse.Service
 }
@@ -71,9 +49,9 @@ func NewWarehouse(w warehouse.Service) *Warehouse {
 // @Produce json
 // @Param id path string true "Warehouse id"
 // @Success 200 {object} domain.Warehouse "Obtained warehouse"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /warehouses/{id} [get]
 func (w *Warehouse) Get() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -83,7 +61,7 @@ func (w *Warehouse) Get() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -97,11 +75,18 @@ func (w *Warehouse) Get() gin.HandlerFunc {
 // @Description Returns a collection of existing warehouses.
 // @Tags Warehouses
 // @Produce json
+// @Param ids query string false "Comma separated ids to get, instead of the whole collection"
 // @Success 200 {array} domain.Warehouse "List of all warehouses"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /warehouses [get]
 func (w *Warehouse) GetAll() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if ids, ok := requestedIDs(c); ok {
+			web.Success(c, http.StatusOK, w.service.GetMany(ids))
+			return
+		}
+
 		warehouses := w.service.GetAll()
 		web.Success(c, http.StatusOK, warehouses)
 	}
@@ -115,9 +100,9 @@ func (w *Warehouse) GetAll() gin.HandlerFunc {
 // @Produce json
 // @Param request body CreateWarehouseRequest true "Warehouse to be created"
 // @Success 201 {object} domain.Warehouse "Created warehouse"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /warehouses [post]
 func (w *Warehouse) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -127,11 +112,11 @@ func (w *Warehouse) Create() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -142,37 +127,38 @@ func (w *Warehouse) Create() gin.HandlerFunc {
 
 // Update godoc
 // @Summary Update a warehouse
-// @Description Update an existent warehouse based on the provided id and JSON payload.
+// @Description Update an existent warehouse by applying a JSON Merge Patch or a JSON Patch to it.
 // @Tags Warehouses
-// @Accept json
+// @Accept json,application/merge-patch+json,application/json-patch+json
 // @Produce json
 // @Param id path int true "Warehouse id"
-// @Param request body UpdateWarehouseRequest true "Warehouse data"
+// @Param request body object true "Merge patch or JSON patch"
 // @Success 200 {object} domain.Warehouse "Updated warehouse"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 409 {object} web.ErrorResponse "Conflict error"
-// @Failure 422 {object} web.ErrorResponse "Validation error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Conflict error"
+// @Failure 415 {object} web.ProblemDetails "Unsupported patch format"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /warehouses [patch]
 func (w *Warehouse) Update() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.GetInt("Id")
-		request := c.MustGet(RequestParamContext).(UpdateWarehouseRequest)
+		current := c.MustGet(CurrentParamContext).(*domain.Warehouse)
+		request := c.MustGet(RequestParamContext).(CreateWarehouseRequest)
 
-		updated, err := w.service.Update(id, request.ToUpdateWarehouse())
+		updated, err := w.service.Update(*current, request.ToWarehouse())
 
 		if err != nil {
-			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+			if apperr.Is[*apperr.IncompatibleResource](err) {
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.ResourceAlreadyExists](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 			if apperr.Is[*apperr.DependentResourceNotFound](err) {
-				web.Error(c, http.StatusConflict, err.Error())
+				web.ErrorFrom(c, http.StatusConflict, err)
 				return
 			}
 		}
@@ -187,9 +173,9 @@ func (w *Warehouse) Update() gin.HandlerFunc {
 // @Tags Warehouses
 // @Param id path string true "Warehouse id"
 // @Success 204 "No content"
-// @Failure 400 {object} web.ErrorResponse "Validation error"
-// @Failure 404 {object} web.ErrorResponse "Resource not found error"
-// @Failure 500 {object} web.ErrorResponse "Internal server error"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
 // @Router /warehouses/{id} [delete]
 func (w *Warehouse) Delete() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -199,7 +185,7 @@ func (w *Warehouse) Delete() gin.HandlerFunc {
 
 		if err != nil {
 			if apperr.Is[*apperr.ResourceNotFound](err) {
-				web.Error(c, http.StatusNotFound, err.Error())
+				web.ErrorFrom(c, http.StatusNotFound, err)
 				return
 			}
 		}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/activity"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	LastEventIDHeader = "Last-Event-ID"
+	HeartbeatEvent    = "heartbeat"
+)
+
+type WarehouseActivity struct {
+	service   warehouse.Service
+	broker    *activity.Broker
+	heartbeat time.Duration
+}
+
+func NewWarehouseActivity(s warehouse.Service, b *activity.Broker, heartbeat time.Duration) *WarehouseActivity {
+	return &WarehouseActivity{
+		service:   s,
+		broker:    b,
+		heartbeat: heartbeat,
+	}
+}
+
+// Events godoc
+// @Summary Stream the activity of a warehouse
+// @Description Push the inbound receipts, batch creations, capacity changes and temperature breaches of the warehouse as
+// @Description Server-Sent Events named inbound_receipt, batch_created, capacity_changed and temperature_breach, whose data is
+// @Description the entity saved and whose id is the one of the domain event. A heartbeat event is sent while there is no activity.
+// @Description Reconnecting with the Last-Event-ID header first replays the activity that followed, as long as it is still buffered.
+// @Tags Warehouses
+// @Produce text/event-stream
+// @Param id path int true "Warehouse ID"
+// @Param Last-Event-ID header int false "Id of the last event received"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /warehouses/{id}/events [get]
+func (w *WarehouseActivity) Events() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+
+		if _, err := w.service.Get(id); err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		lastEventID, _ := strconv.Atoi(c.GetHeader(LastEventIDHeader))
+		missed, events, cancel := w.broker.Subscribe(id, lastEventID)
+		defer cancel()
+
+		heartbeat := time.NewTicker(w.heartbeat)
+		defer heartbeat.Stop()
+
+		c.Header("Content-Type", sse.ContentType)
+		c.Header("Cache-Control", "no-cache")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		for _, pushed := range missed {
+			renderActivity(c, pushed)
+		}
+		c.Writer.Flush()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case pushed, ok := <-events:
+				// A closed channel means the client fell behind, it resumes
+				// from the buffer when it reconnects.
+				if !ok {
+					return
+				}
+				renderActivity(c, pushed)
+			case now := <-heartbeat.C:
+				c.Render(-1, sse.Event{Event: HeartbeatEvent, Data: now.UTC().Format(time.RFC3339)})
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+func renderActivity(c *gin.Context, pushed domain.WarehouseActivity) {
+	c.Render(-1, sse.Event{
+		Id:    strconv.Itoa(pushed.ID),
+		Event: pushed.Type,
+		Data:  pushed.Data,
+	})
+}
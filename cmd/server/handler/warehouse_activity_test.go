@@ -0,0 +1,106 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/activity"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	sectionMocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func inboundReceivedEvent(id int) domain.Event {
+	payload, _ := json.Marshal(domain.InboundOrder{ID: id, OrderNumber: "IN-1", WarehouseId: 1})
+	return domain.Event{ID: id, Type: domain.EventInboundOrderReceived, AggregateID: id, Payload: payload}
+}
+
+func TestWarehouseActivityEvents(t *testing.T) {
+	route := DefinePath(ResourceWarehouseUri) + "/:id/events"
+	path := DefinePathWithId(ResourceWarehouseUri, 1) + "/events"
+
+	t.Run("Should replay the buffered events that followed the Last-Event-ID", func(t *testing.T) {
+		server, service, broker, controller := InitWarehouseActivityServer(t, time.Hour)
+
+		server.GET(route, controller.Events())
+		request, response := MakeRequest("GET", path, "")
+		request.Header.Set(handler.LastEventIDHeader, "1")
+		ctx, cancel := context.WithCancel(request.Context())
+		cancel()
+
+		service.On("Get", 1).Return(&domain.Warehouse{ID: 1}, nil)
+		assert.NoError(t, broker.Publish(inboundReceivedEvent(1)))
+		assert.NoError(t, broker.Publish(inboundReceivedEvent(2)))
+		server.ServeHTTP(response, request.WithContext(ctx))
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "text/event-stream", response.Header().Get("Content-Type"))
+		body := response.Body.String()
+		assert.NotContains(t, body, "id:1\n")
+		assert.Contains(t, body, "id:2\nevent:"+activity.InboundReceipt+"\ndata:")
+		assert.Contains(t, body, `"order_number":"IN-1"`)
+	})
+	t.Run("Should push the activity as it happens and send heartbeats", func(t *testing.T) {
+		server, service, broker, controller := InitWarehouseActivityServer(t, 20*time.Millisecond)
+
+		server.GET(route, controller.Events())
+		service.On("Get", 1).Return(&domain.Warehouse{ID: 1}, nil)
+		httpServer := httptest.NewServer(server)
+		defer httpServer.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		request, _ := http.NewRequestWithContext(ctx, "GET", httpServer.URL+path, nil)
+		response, err := http.DefaultClient.Do(request)
+		assert.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.NoError(t, broker.Publish(inboundReceivedEvent(3)))
+
+		received, heartbeat := false, false
+		scanner := bufio.NewScanner(response.Body)
+		for (!received || !heartbeat) && scanner.Scan() {
+			switch {
+			case scanner.Text() == "id:3":
+				received = true
+			case strings.HasPrefix(scanner.Text(), "event:"+handler.HeartbeatEvent):
+				heartbeat = true
+			}
+		}
+		assert.True(t, received)
+		assert.True(t, heartbeat)
+	})
+	t.Run("Should return not found error when the warehouse does not exist", func(t *testing.T) {
+		server, service, _, controller := InitWarehouseActivityServer(t, time.Hour)
+
+		server.GET(route, controller.Events())
+		request, response := MakeRequest("GET", path, "")
+
+		service.On("Get", 1).Return((*domain.Warehouse)(nil), apperr.NewResourceNotFound(warehouse.ResourceNotFound, 1))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func InitWarehouseActivityServer(t *testing.T, heartbeat time.Duration) (*gin.Engine, *mocks.Service, *activity.Broker, *handler.WarehouseActivity) {
+	t.Helper()
+	server := CreateServer()
+	server.Use(middleware.IdValidation())
+	service := new(mocks.Service)
+	broker := activity.NewBroker(new(sectionMocks.Repository), activity.DefaultBufferSize)
+	controller := handler.NewWarehouseActivity(service, broker, heartbeat)
+	return server, service, broker, controller
+}
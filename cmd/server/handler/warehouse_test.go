@@ -94,6 +94,32 @@ func TestGetWarehouse(t *testing.T) {
 		assert.Equal(t, http.StatusOK, response.Code)
 	})
 
+	t.Run("Should return the warehouses with the given ids", func(t *testing.T) {
+		server, service, controller := InitWarehouseServer(t)
+
+		server.GET(DefinePath(ResourceWarehouseUri), middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
+		request, response := MakeRequest("GET", DefinePath(ResourceWarehouseUri)+"?ids=1,2", "")
+
+		service.On("GetMany", []int{1, 2}).Return([]domain.Warehouse{{ID: 1}, {ID: 2}})
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		service.AssertNotCalled(t, "GetAll")
+	})
+
+	t.Run("Should return bad request error when an id is invalid", func(t *testing.T) {
+		server, service, controller := InitWarehouseServer(t)
+
+		server.GET(DefinePath(ResourceWarehouseUri), middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
+		request, response := MakeRequest("GET", DefinePath(ResourceWarehouseUri)+"?ids=1,abc", "")
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		service.AssertNotCalled(t, "GetMany")
+	})
+
 	t.Run("Should return not found error", func(t *testing.T) {
 		server, service, controller := InitWarehouseServer(t)
 
@@ -127,43 +153,46 @@ func TestGetWarehouse(t *testing.T) {
 }
 
 func TestUpdateWarehouse(t *testing.T) {
-	requestObject := handler.UpdateWarehouseRequest{
+	requestObject := handler.CreateWarehouseRequest{
 		Address:            &mockedWarehouse.Address,
 		Telephone:          &mockedWarehouse.Telephone,
 		WarehouseCode:      &mockedWarehouse.WarehouseCode,
 		MinimumCapacity:    &mockedWarehouse.MinimumCapacity,
 		MinimumTemperature: &mockedWarehouse.MinimumTemperature,
+		LocalityID:         &mockedWarehouse.LocalityID,
 	}
 
-	t.Run("Should return not found error", func(t *testing.T) {
+	t.Run("Should return conflict error when the warehouse was modified", func(t *testing.T) {
 		server, service, controller := InitWarehouseServer(t)
 
 		id := 1
+		current := domain.Warehouse{ID: id}
 
-		server.PATCH(DefinePath(ResourceWarehouseUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceWarehouseUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceWarehouseUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Warehouse
 		service.On(
-			"Update", id, requestObject.ToUpdateWarehouse()).
-			Return(serviceReturn, apperr.NewResourceNotFound(ResourceNotFound))
+			"Update", current, requestObject.ToWarehouse()).
+			Return(serviceReturn, apperr.NewIncompatibleResource(ResourceModified))
 
 		server.ServeHTTP(response, request)
 
-		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Equal(t, http.StatusConflict, response.Code)
 	})
 
 	t.Run("Should return conflict error", func(t *testing.T) {
 		server, service, controller := InitWarehouseServer(t)
 
 		id := 1
+		current := domain.Warehouse{ID: id}
 
-		server.PATCH(DefinePath(ResourceWarehouseUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceWarehouseUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceWarehouseUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Warehouse
 		service.On(
-			"Update", id, requestObject.ToUpdateWarehouse()).
+			"Update", current, requestObject.ToWarehouse()).
 			Return(serviceReturn, apperr.NewResourceAlreadyExists(ResourceAlreadyExists))
 
 		server.ServeHTTP(response, request)
@@ -175,13 +204,14 @@ func TestUpdateWarehouse(t *testing.T) {
 		server, service, controller := InitWarehouseServer(t)
 
 		id := 1
+		current := domain.Warehouse{ID: id}
 
-		server.PATCH(DefinePath(ResourceWarehouseUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceWarehouseUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceWarehouseUri, id), CreateBody(requestObject))
 
 		var serviceReturn *domain.Warehouse
 		service.On(
-			"Update", id, requestObject.ToUpdateWarehouse()).
+			"Update", current, requestObject.ToWarehouse()).
 			Return(serviceReturn, apperr.NewDependentResourceNotFound(ResourceNotFound))
 
 		server.ServeHTTP(response, request)
@@ -193,12 +223,13 @@ func TestUpdateWarehouse(t *testing.T) {
 		server, service, controller := InitWarehouseServer(t)
 
 		id := 1
+		current := domain.Warehouse{ID: id}
 
-		server.PATCH(DefinePath(ResourceWarehouseUri)+"/:id", ValidationMiddleware(requestObject), controller.Update())
+		server.PATCH(DefinePath(ResourceWarehouseUri)+"/:id", PatchMiddleware(&current, requestObject), controller.Update())
 		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceWarehouseUri, id), CreateBody(requestObject))
 
 		service.On(
-			"Update", id, requestObject.ToUpdateWarehouse()).
+			"Update", current, requestObject.ToWarehouse()).
 			Return(&mockedWarehouse, nil)
 
 		server.ServeHTTP(response, request)
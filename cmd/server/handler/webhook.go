@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/webhook"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateWebhookRequest struct {
+	URL        *string  `json:"url" binding:"required,url"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=PurchaseOrderCreated PurchaseOrderStatusChanged InboundOrderReceived ProductBatchCreated SectionTemperatureBreached SectionCapacityChanged"`
+	Secret     *string  `json:"secret" binding:"required,min=16"`
+}
+
+func (r CreateWebhookRequest) ToWebhook() domain.Webhook {
+	return domain.Webhook{
+		URL:        *r.URL,
+		EventTypes: r.EventTypes,
+		Secret:     *r.Secret,
+	}
+}
+
+type Webhook struct {
+	service webhook.Service
+}
+
+func NewWebhook(s webhook.Service) *Webhook {
+	return &Webhook{
+		service: s,
+	}
+}
+
+// WebhookPatch is the webhook as a request, secret included, for the patches
+// to be applied on. The webhook it was read from is kept for the update.
+type WebhookPatch struct {
+	CreateWebhookRequest
+	Webhook domain.Webhook `json:"-"`
+}
+
+// Patchable returns the webhook for the patches to be applied on.
+func (w *Webhook) Patchable(id int) (WebhookPatch, error) {
+	webhookFound, err := w.service.Get(id)
+	if err != nil {
+		return WebhookPatch{}, err
+	}
+
+	return WebhookPatch{
+		CreateWebhookRequest: CreateWebhookRequest{
+			URL:        &webhookFound.URL,
+			EventTypes: webhookFound.EventTypes,
+			Secret:     &webhookFound.Secret,
+		},
+		Webhook: *webhookFound,
+	}, nil
+}
+
+// GetAll godoc
+// @Summary List all webhooks
+// @Description Return every webhook subscription, without their secrets.
+// @Tags Webhooks
+// @Produce json
+// @Success 200 {array} domain.Webhook "List of all webhooks"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /webhooks [get]
+func (w *Webhook) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		web.Success(c, http.StatusOK, w.service.GetAll())
+	}
+}
+
+// Get godoc
+// @Summary Get a webhook by id
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} domain.Webhook "Obtained webhook"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /webhooks/{id} [get]
+func (w *Webhook) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+
+		webhookFound, err := w.service.Get(id)
+		respondWebhook(c, http.StatusOK, webhookFound, err)
+	}
+}
+
+// Create godoc
+// @Summary Subscribe a webhook
+// @Description Subscribe a URL to some types of domain events. Each event is posted as JSON, with the unix time it was sent in the
+// @Description X-Webhook-Timestamp header, signed in the X-Webhook-Signature header with sha256= followed by the hex encoded
+// @Description HMAC-SHA256 of the timestamp, a dot and the body, keyed with the secret.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param request body CreateWebhookRequest true "Webhook to be created"
+// @Success 201 {object} domain.Webhook "Created webhook"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /webhooks [post]
+func (w *Webhook) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := c.MustGet(RequestParamContext).(CreateWebhookRequest)
+
+		web.Success(c, http.StatusCreated, w.service.Create(request.ToWebhook()))
+	}
+}
+
+// Update godoc
+// @Summary Update a webhook
+// @Description Update the URL, event types or secret of a webhook with a JSON Merge Patch or a JSON Patch.
+// @Tags Webhooks
+// @Accept json,application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param request body CreateWebhookRequest true "Fields to update"
+// @Success 200 {object} domain.Webhook "Updated webhook"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 409 {object} web.ProblemDetails "Webhook modified since it was read"
+// @Failure 422 {object} web.ProblemDetails "Validation error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /webhooks/{id} [patch]
+func (w *Webhook) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		current := c.MustGet(CurrentParamContext).(WebhookPatch)
+		request := c.MustGet(RequestParamContext).(CreateWebhookRequest)
+
+		updated, err := w.service.Update(current.Webhook, request.ToWebhook())
+		respondWebhook(c, http.StatusOK, updated, err)
+	}
+}
+
+// Delete godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook along with its delivery log.
+// @Tags Webhooks
+// @Param id path int true "Webhook ID"
+// @Success 204 "Webhook deleted"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /webhooks/{id} [delete]
+func (w *Webhook) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+
+		if err := w.service.Delete(id); err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusNoContent, nil)
+	}
+}
+
+// Deliveries godoc
+// @Summary List the deliveries of a webhook
+// @Description Return the deliveries of events to the webhook, newest first, with their status, the response status code and latency of their last attempt and when they are tried next.
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {array} domain.WebhookDelivery "Delivery log"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /webhooks/{id}/deliveries [get]
+func (w *Webhook) Deliveries() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+
+		deliveries, err := w.service.Deliveries(id)
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusOK, deliveries)
+	}
+}
+
+// Redeliver godoc
+// @Summary Redeliver an event
+// @Description Queue a delivery to be posted to its webhook once more and return it, pending. A delivery still pending is left as it is.
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Success 200 {object} domain.WebhookDelivery "Queued delivery"
+// @Failure 400 {object} web.ProblemDetails "Validation error"
+// @Failure 404 {object} web.ProblemDetails "Resource not found error"
+// @Failure 500 {object} web.ProblemDetails "Internal server error"
+// @Router /webhooks/deliveries/{id}/redeliver [post]
+func (w *Webhook) Redeliver() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetInt("Id")
+
+		delivery, err := w.service.Redeliver(id)
+		if err != nil {
+			if apperr.Is[*apperr.ResourceNotFound](err) {
+				web.ErrorFrom(c, http.StatusNotFound, err)
+				return
+			}
+		}
+
+		web.Success(c, http.StatusOK, delivery)
+	}
+}
+
+func respondWebhook(c *gin.Context, status int, webhookFound *domain.Webhook, err error) {
+	if err != nil {
+		if apperr.Is[*apperr.ResourceNotFound](err) {
+			web.ErrorFrom(c, http.StatusNotFound, err)
+			return
+		}
+		if apperr.Is[*apperr.IncompatibleResource](err) {
+			web.ErrorFrom(c, http.StatusConflict, err)
+			return
+		}
+	}
+
+	web.Success(c, status, webhookFound)
+}
@@ -0,0 +1,127 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/webhook/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	ResourceWebhooksUri = "/webhooks"
+)
+
+var (
+	mockedWebhook = domain.Webhook{
+		ID:         1,
+		URL:        "https://partner.example/hooks",
+		EventTypes: []string{domain.EventPurchaseOrderStatusChanged},
+		Secret:     "0123456789abcdef",
+	}
+)
+
+func TestCreateWebhook(t *testing.T) {
+	route := DefinePath(ResourceWebhooksUri)
+
+	t.Run("Should return the created webhook without its secret", func(t *testing.T) {
+		server, service, controller := InitWebhookServer(t)
+
+		server.POST(route, middleware.RequestValidation[handler.CreateWebhookRequest](true), controller.Create())
+		body := `{"url":"https://partner.example/hooks","event_types":["PurchaseOrderStatusChanged"],"secret":"0123456789abcdef"}`
+		request, response := MakeRequest("POST", route, body)
+
+		service.On("Create", domain.Webhook{URL: mockedWebhook.URL, EventTypes: mockedWebhook.EventTypes, Secret: mockedWebhook.Secret}).Return(&mockedWebhook)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.NotContains(t, response.Body.String(), mockedWebhook.Secret)
+	})
+	t.Run("Should return unprocessable entity error when an event type is unknown", func(t *testing.T) {
+		server, _, controller := InitWebhookServer(t)
+
+		server.POST(route, middleware.RequestValidation[handler.CreateWebhookRequest](true), controller.Create())
+		body := `{"url":"https://partner.example/hooks","event_types":["OrderShipped"],"secret":"0123456789abcdef"}`
+		request, response := MakeRequest("POST", route, body)
+
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	t.Run("Should keep the secret when the patch leaves it out", func(t *testing.T) {
+		server, service, controller := InitWebhookServer(t)
+		route := DefinePath(ResourceWebhooksUri) + "/:id"
+
+		server.PATCH(route, middleware.PatchValidation[handler.CreateWebhookRequest](controller.Patchable), controller.Update())
+		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceWebhooksUri, 1), `{"url":"https://partner.example/v2/hooks"}`)
+
+		updated := mockedWebhook
+		updated.URL = "https://partner.example/v2/hooks"
+		service.On("Get", 1).Return(&mockedWebhook, nil)
+		service.On("Update", mockedWebhook, domain.Webhook{URL: updated.URL, EventTypes: updated.EventTypes, Secret: updated.Secret}).Return(&updated, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("Should return conflict error when the webhook was modified", func(t *testing.T) {
+		server, service, controller := InitWebhookServer(t)
+		route := DefinePath(ResourceWebhooksUri) + "/:id"
+
+		server.PATCH(route, middleware.PatchValidation[handler.CreateWebhookRequest](controller.Patchable), controller.Update())
+		request, response := MakeRequest("PATCH", DefinePathWithId(ResourceWebhooksUri, 1), `{"url":"https://partner.example/v2/hooks"}`)
+
+		var updated *domain.Webhook
+		service.On("Get", 1).Return(&mockedWebhook, nil)
+		service.On("Update", mockedWebhook, domain.Webhook{URL: "https://partner.example/v2/hooks", EventTypes: mockedWebhook.EventTypes, Secret: mockedWebhook.Secret}).Return(updated, apperr.NewIncompatibleResource(ResourceModified))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	route := DefinePath(ResourceWebhooksUri) + "/deliveries/:id/redeliver"
+	path := DefinePath(ResourceWebhooksUri) + "/deliveries/3/redeliver"
+
+	t.Run("Should return the queued delivery", func(t *testing.T) {
+		server, service, controller := InitWebhookServer(t)
+
+		server.POST(route, controller.Redeliver())
+		request, response := MakeRequest("POST", path, "")
+
+		service.On("Redeliver", 3).Return(&domain.WebhookDelivery{ID: 3, WebhookID: 1, EventID: 9, Status: domain.WebhookDeliveryPending}, nil)
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+	t.Run("Should return not found error when the delivery does not exist", func(t *testing.T) {
+		server, service, controller := InitWebhookServer(t)
+
+		server.POST(route, controller.Redeliver())
+		request, response := MakeRequest("POST", path, "")
+
+		var delivery *domain.WebhookDelivery
+		service.On("Redeliver", 3).Return(delivery, apperr.NewResourceNotFound(ResourceNotFound))
+		server.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func InitWebhookServer(t *testing.T) (*gin.Engine, *mocks.Service, *handler.Webhook) {
+	t.Helper()
+	server := CreateServer()
+	server.Use(middleware.IdValidation())
+	service := new(mocks.Service)
+	controller := handler.NewWebhook(service)
+	return server, service, controller
+}
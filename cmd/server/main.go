@@ -1,7 +1,15 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/routes"
 	"github.com/gin-gonic/gin"
@@ -32,12 +40,39 @@ func main() {
 		panic(err)
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	eng := gin.Default()
 
 	router := routes.NewRouter(eng, db)
 	router.MapRoutes()
+	router.Start(ctx)
 
-	if err := eng.Run(); err != nil {
-		panic(err)
+	server := &http.Server{Addr: address(), Handler: eng}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	}()
+
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("server: shutdown: %v", err)
+	}
+	router.Stop()
+}
+
+// shutdownTimeout is how long the requests in progress have to end once the
+// server is asked to stop.
+const shutdownTimeout = 10 * time.Second
+
+// address returns the address the API listens on: the port in PORT, or 8080.
+func address() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
 	}
+	return ":8080"
 }
@@ -9,7 +9,7 @@ import (
 )
 
 const (
-	InvalidId = "o id '%s' é inválido"
+	InvalidId = "invalid_id"
 )
 
 func IdValidation() gin.HandlerFunc {
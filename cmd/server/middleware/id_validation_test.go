@@ -34,12 +34,11 @@ func TestIdValidationMiddleware(t *testing.T) {
 
 		router.ServeHTTP(recorder, request)
 
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Equal(t, response.Messages[0], "o id 'abc' é inválido")
+		assert.Equal(t, "o id 'abc' é inválido", response.Detail)
 	})
 
 	t.Run("Should have success", func(t *testing.T) {
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/idempotency"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	IdempotencyKeyReused     = "idempotency.key_reused"
+	IdempotencyKeyPending    = "idempotency.key_pending"
+)
+
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key header, so clients can safely repeat create requests.
+// The key is reserved before the request is handled, and a retry that comes
+// while it is still pending is rejected with a conflict. The reservation only
+// lasts for lease, so a key left pending by a crashed process can be retried
+// soon after, and the stored response is kept for ttl. Requests without the
+// header are handled normally.
+func Idempotency(repository idempotency.Repository, ttl time.Duration, lease time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+
+		body, _ := io.ReadAll(ctx.Request.Body)
+		ctx.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+		requestHash := hashRequest(ctx.Request.Method, ctx.FullPath(), body)
+
+		stored := repository.Get(key)
+		if stored != nil && stored.ExpiresAt.After(time.Now()) {
+			replayIdempotent(ctx, key, requestHash, *stored)
+			return
+		}
+
+		reserved := repository.Reserve(domain.IdempotencyKey{
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiresAt:   time.Now().UTC().Add(lease),
+		})
+		if !reserved {
+			web.Error(ctx, http.StatusConflict, IdempotencyKeyPending, key)
+			ctx.Abort()
+			return
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				repository.Release(key)
+				panic(r)
+			}
+		}()
+
+		writer := idempotencyWriter{ResponseWriter: ctx.Writer, body: &bytes.Buffer{}}
+		ctx.Writer = writer
+
+		ctx.Next()
+
+		if writer.Status() >= http.StatusInternalServerError {
+			repository.Release(key)
+			return
+		}
+
+		repository.Save(domain.IdempotencyKey{
+			Key:                 key,
+			RequestHash:         requestHash,
+			ResponseStatus:      writer.Status(),
+			ResponseContentType: writer.Header().Get("Content-Type"),
+			ResponseBody:        writer.body.String(),
+			ExpiresAt:           time.Now().UTC().Add(ttl),
+		})
+	}
+}
+
+// replayIdempotent answers a request whose key is already stored: with the
+// stored response when it is the same request and has been handled, and with
+// an error otherwise.
+func replayIdempotent(ctx *gin.Context, key string, requestHash string, stored domain.IdempotencyKey) {
+	defer ctx.Abort()
+
+	if stored.RequestHash != requestHash {
+		web.Error(ctx, http.StatusUnprocessableEntity, IdempotencyKeyReused, key)
+		return
+	}
+
+	if stored.Pending() {
+		web.Error(ctx, http.StatusConflict, IdempotencyKeyPending, key)
+		return
+	}
+
+	ctx.Header(IdempotentReplayedHeader, "true")
+	ctx.Data(stored.ResponseStatus, stored.ResponseContentType, []byte(stored.ResponseBody))
+}
+
+func hashRequest(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte(path))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
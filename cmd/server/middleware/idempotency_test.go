@@ -0,0 +1,247 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/idempotency/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	idempotencyKey  = "c6a7b2f0"
+	idempotencyBody = `{"order_number":"PO001"}`
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	t.Run("Should execute the handler when no key is given", func(t *testing.T) {
+		router, repository, calls := createIdempotencyRouter()
+		recorder := httptest.NewRecorder()
+		request, _ := http.NewRequest("POST", "/", strings.NewReader(idempotencyBody))
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, 1, *calls)
+		repository.AssertNotCalled(t, "Get", mock.Anything)
+	})
+
+	t.Run("Should store the response when the key is new", func(t *testing.T) {
+		router, repository, calls := createIdempotencyRouter()
+		recorder := httptest.NewRecorder()
+		request := createIdempotentRequest(idempotencyBody)
+
+		var notFound *domain.IdempotencyKey
+		repository.On("Get", idempotencyKey).Return(notFound)
+		repository.On("Reserve", mock.MatchedBy(func(i domain.IdempotencyKey) bool {
+			return i.Key == idempotencyKey && i.Pending() && i.ExpiresAt.Before(time.Now().Add(2*time.Minute))
+		})).Return(true)
+		repository.On("Save", mock.MatchedBy(func(i domain.IdempotencyKey) bool {
+			return i.Key == idempotencyKey && i.ResponseStatus == http.StatusCreated && i.ResponseContentType == "application/json" &&
+				i.ResponseBody == idempotencyBody && i.ExpiresAt.After(time.Now().Add(30*time.Minute))
+		})).Return()
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, idempotencyBody, recorder.Body.String())
+		assert.Equal(t, 1, *calls)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should replay the stored response when the key is reused with the same body", func(t *testing.T) {
+		router, repository, calls := createIdempotencyRouter()
+		stored := storeIdempotentResponse(idempotencyBody)
+		recorder := httptest.NewRecorder()
+		request := createIdempotentRequest(idempotencyBody)
+
+		repository.On("Get", idempotencyKey).Return(stored)
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, idempotencyBody, recorder.Body.String())
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "true", recorder.Header().Get(middleware.IdempotentReplayedHeader))
+		assert.Equal(t, 0, *calls)
+		repository.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("Should have error when the key is reused with a different body", func(t *testing.T) {
+		router, repository, calls := createIdempotencyRouter()
+		stored := storeIdempotentResponse(idempotencyBody)
+		recorder := httptest.NewRecorder()
+		request := createIdempotentRequest(`{"order_number":"PO002"}`)
+
+		repository.On("Get", idempotencyKey).Return(stored)
+
+		router.ServeHTTP(recorder, request)
+
+		var response ProblemResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Equal(t, "a chave de idempotência 'c6a7b2f0' já foi utilizada com uma requisição diferente", response.Detail)
+		assert.Equal(t, 0, *calls)
+	})
+
+	t.Run("Should execute the handler again when the stored key is expired", func(t *testing.T) {
+		router, repository, calls := createIdempotencyRouter()
+		stored := storeIdempotentResponse(idempotencyBody)
+		stored.ExpiresAt = time.Now().Add(-time.Minute)
+		recorder := httptest.NewRecorder()
+		request := createIdempotentRequest(idempotencyBody)
+
+		repository.On("Get", idempotencyKey).Return(stored)
+		repository.On("Reserve", mock.Anything).Return(true)
+		repository.On("Save", mock.Anything).Return()
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, 1, *calls)
+		repository.AssertExpectations(t)
+	})
+
+	t.Run("Should execute the handler again when the pending key outlived its lease", func(t *testing.T) {
+		router, repository, calls := createIdempotencyRouter()
+		pending := storeIdempotentResponse(idempotencyBody)
+		pending.ResponseStatus, pending.ResponseContentType, pending.ResponseBody = 0, "", ""
+		pending.ExpiresAt = time.Now().Add(-time.Second)
+		recorder := httptest.NewRecorder()
+		request := createIdempotentRequest(idempotencyBody)
+
+		repository.On("Get", idempotencyKey).Return(pending)
+		repository.On("Reserve", mock.Anything).Return(true)
+		repository.On("Save", mock.Anything).Return()
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, 1, *calls)
+		repository.AssertExpectations(t)
+	})
+}
+
+func TestIdempotencyMiddlewareConcurrency(t *testing.T) {
+	t.Run("Should have conflict error while the request of the key is pending", func(t *testing.T) {
+		router, repository, calls := createIdempotencyRouter()
+		recorder := httptest.NewRecorder()
+		request := createIdempotentRequest(idempotencyBody)
+
+		pending := storeIdempotentResponse(idempotencyBody)
+		pending.ResponseStatus, pending.ResponseContentType, pending.ResponseBody = 0, "", ""
+		repository.On("Get", idempotencyKey).Return(pending)
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, 0, *calls)
+		repository.AssertNotCalled(t, "Reserve", mock.Anything)
+	})
+
+	t.Run("Should have conflict error when another request reserves the key first", func(t *testing.T) {
+		router, repository, calls := createIdempotencyRouter()
+		recorder := httptest.NewRecorder()
+		request := createIdempotentRequest(idempotencyBody)
+
+		var notFound *domain.IdempotencyKey
+		repository.On("Get", idempotencyKey).Return(notFound)
+		repository.On("Reserve", mock.Anything).Return(false)
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, 0, *calls)
+		repository.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("Should release the key when the request fails", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		repository := new(mocks.Repository)
+		router.POST("/", middleware.Idempotency(repository, time.Hour, time.Minute), func(c *gin.Context) {
+			c.Status(http.StatusInternalServerError)
+		})
+		recorder := httptest.NewRecorder()
+
+		var notFound *domain.IdempotencyKey
+		repository.On("Get", idempotencyKey).Return(notFound)
+		repository.On("Reserve", mock.Anything).Return(true)
+		repository.On("Release", idempotencyKey).Return().Once()
+
+		router.ServeHTTP(recorder, createIdempotentRequest(idempotencyBody))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		repository.AssertExpectations(t)
+		repository.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("Should release the key when the handler panics", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		repository := new(mocks.Repository)
+		router.Use(middleware.InternalError())
+		router.POST("/", middleware.Idempotency(repository, time.Hour, time.Minute), func(c *gin.Context) {
+			panic("connection refused")
+		})
+		recorder := httptest.NewRecorder()
+
+		var notFound *domain.IdempotencyKey
+		repository.On("Get", idempotencyKey).Return(notFound)
+		repository.On("Reserve", mock.Anything).Return(true)
+		repository.On("Release", idempotencyKey).Return().Once()
+
+		router.ServeHTTP(recorder, createIdempotentRequest(idempotencyBody))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		repository.AssertExpectations(t)
+	})
+}
+
+func createIdempotencyRouter() (*gin.Engine, *mocks.Repository, *int) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	repository := new(mocks.Repository)
+	calls := 0
+	router.POST("/", middleware.Idempotency(repository, time.Hour, time.Minute), func(c *gin.Context) {
+		calls++
+		body, _ := c.GetRawData()
+		c.Data(http.StatusCreated, "application/json", body)
+	})
+	return router, repository, &calls
+}
+
+func createIdempotentRequest(body string) *http.Request {
+	request, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+	request.Header.Set(middleware.IdempotencyKeyHeader, idempotencyKey)
+	return request
+}
+
+func storeIdempotentResponse(body string) *domain.IdempotencyKey {
+	var stored domain.IdempotencyKey
+	repository := new(mocks.Repository)
+	var notFound *domain.IdempotencyKey
+	repository.On("Get", idempotencyKey).Return(notFound)
+	repository.On("Reserve", mock.Anything).Return(true)
+	repository.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(domain.IdempotencyKey)
+	}).Return()
+
+	storeRouter := gin.New()
+	storeRouter.POST("/", middleware.Idempotency(repository, time.Hour, time.Minute), func(c *gin.Context) {
+		data, _ := c.GetRawData()
+		c.Data(http.StatusCreated, "application/json", data)
+	})
+	storeRouter.ServeHTTP(httptest.NewRecorder(), createIdempotentRequest(body))
+
+	return &stored
+}
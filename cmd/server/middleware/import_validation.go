@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+)
+
+const (
+	ImportParamContext = "Import"
+	UnsupportedImport  = "request.unsupported_import"
+	InvalidImport      = "request.invalid_import"
+	EmptyImport        = "request.empty_import"
+)
+
+// ImportValidation reads the rows of a bulk import from a CSV file, whose
+// header names the JSON fields of T, or from NDJSON, one object per line. Each
+// row is validated with the same rules used on creation and stored, with the
+// line it was read from and its errors, under "Import".
+func ImportValidation[T any]() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		locale := web.Locale(ctx)
+
+		var rows []web.ImportRow[T]
+		var err error
+		switch ctx.ContentType() {
+		case web.CSVMediaType:
+			rows, err = readCSVRows[T](locale, ctx.Request.Body)
+		case web.NDJSONMediaType:
+			rows, err = readNDJSONRows[T](locale, ctx.Request.Body)
+		default:
+			web.Error(ctx, http.StatusUnsupportedMediaType, UnsupportedImport, ctx.ContentType(), web.CSVMediaType, web.NDJSONMediaType)
+			ctx.Abort()
+			return
+		}
+
+		if err != nil {
+			web.Error(ctx, http.StatusUnprocessableEntity, InvalidImport, err.Error())
+			ctx.Abort()
+			return
+		}
+		if len(rows) == 0 {
+			web.Error(ctx, http.StatusUnprocessableEntity, EmptyImport)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(ImportParamContext, rows)
+	}
+}
+
+func readCSVRows[T any](locale language.Tag, body io.Reader) ([]web.ImportRow[T], error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]web.ImportRow[T], 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		values := make(map[string][]string)
+		for i, column := range header {
+			if i < len(record) && record[i] != "" {
+				values[column] = []string{record[i]}
+			}
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := web.ImportRow[T]{Line: line}
+		row.Errors = bindValues(locale, &row.Value, values, "json")
+		if len(row.Errors) == 0 {
+			row.Errors = validateImportRow(locale, row.Value)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func readNDJSONRows[T any](locale language.Tag, body io.Reader) ([]web.ImportRow[T], error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 1024*1024)
+
+	rows := make([]web.ImportRow[T], 0)
+	for line := 1; scanner.Scan(); line++ {
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+
+		row := web.ImportRow[T]{Line: line}
+		if err := json.Unmarshal(content, &row.Value); err != nil {
+			detail, fields := DescribeBindingError(locale, row.Value, err)
+			if len(fields) == 0 {
+				fields = []web.FieldError{{Rule: "syntax", Message: detail}}
+			}
+			row.Errors = fields
+		} else {
+			row.Errors = validateImportRow(locale, row.Value)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, scanner.Err()
+}
+
+func validateImportRow(locale language.Tag, value interface{}) []web.FieldError {
+	err := binding.Validator.ValidateStruct(value)
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		return describeValidationErrors(locale, value, validationErrors)
+	}
+	return nil
+}
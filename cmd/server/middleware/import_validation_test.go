@@ -0,0 +1,103 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportValidationMiddleware(t *testing.T) {
+	t.Run("Should read the CSV rows with the lines they came from", func(t *testing.T) {
+		router, got := createImportRouter()
+		recorder := httptest.NewRecorder()
+		body := "name,capacity\nSection A,10\n,5\nSection C,many\n"
+
+		router.ServeHTTP(recorder, createImportRequest("text/csv", body))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Len(t, *got, 3)
+
+		rows := *got
+		assert.Equal(t, 2, rows[0].Line)
+		assert.True(t, rows[0].Valid())
+		assert.Equal(t, "Section A", *rows[0].Value.Name)
+		assert.Equal(t, 10, *rows[0].Value.Capacity)
+
+		assert.Equal(t, 3, rows[1].Line)
+		assert.Equal(t, []web.FieldError{{Field: "name", Rule: "required", Message: "'name' é obrigatório"}}, rows[1].Errors)
+
+		assert.Equal(t, 4, rows[2].Line)
+		assert.Equal(t, "capacity", rows[2].Errors[0].Field)
+		assert.Equal(t, "type", rows[2].Errors[0].Rule)
+	})
+
+	t.Run("Should read the NDJSON rows skipping blank lines", func(t *testing.T) {
+		router, got := createImportRouter()
+		recorder := httptest.NewRecorder()
+		body := "{\"name\":\"Section A\",\"capacity\":10}\n\n{\"name\":\"Section B\",\"capacity\":-1}\n{\"name\":\n"
+
+		router.ServeHTTP(recorder, createImportRequest("application/x-ndjson", body))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Len(t, *got, 3)
+
+		rows := *got
+		assert.True(t, rows[0].Valid())
+		assert.Equal(t, 3, rows[1].Line)
+		assert.Equal(t, "gte", rows[1].Errors[0].Rule)
+		assert.Equal(t, 4, rows[2].Line)
+		assert.Equal(t, "syntax", rows[2].Errors[0].Rule)
+	})
+
+	t.Run("Should return unsupported media type error for other content types", func(t *testing.T) {
+		router, _ := createImportRouter()
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createImportRequest("application/json", `[{"name":"Section A"}]`))
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+	})
+
+	t.Run("Should return unprocessable entity error when the file has no rows", func(t *testing.T) {
+		router, _ := createImportRouter()
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createImportRequest("text/csv", "name,capacity\n"))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "o arquivo não possui linhas para importar")
+	})
+
+	t.Run("Should return unprocessable entity error when the CSV is malformed", func(t *testing.T) {
+		router, _ := createImportRouter()
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createImportRequest("text/csv", "name,capacity\nSection A\n"))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	})
+}
+
+func createImportRouter() (*gin.Engine, *[]web.ImportRow[PatchResourceRequest]) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var got []web.ImportRow[PatchResourceRequest]
+
+	router.POST("/sections/import", middleware.ImportValidation[PatchResourceRequest](), func(c *gin.Context) {
+		got = c.MustGet(middleware.ImportParamContext).([]web.ImportRow[PatchResourceRequest])
+		c.Status(http.StatusOK)
+	})
+	return router, &got
+}
+
+func createImportRequest(contentType string, body string) *http.Request {
+	request, _ := http.NewRequest("POST", "/sections/import", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", contentType)
+	return request
+}
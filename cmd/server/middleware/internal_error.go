@@ -7,11 +7,15 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	InternalServerError = "internal_error"
+)
+
 func InternalError() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				web.Error(ctx, http.StatusInternalServerError, "an internal error ocurred")
+				web.Error(ctx, http.StatusInternalServerError, InternalServerError)
 				ctx.Abort()
 				return
 			}
@@ -28,14 +28,14 @@ func TestInternalErrorMiddleware(t *testing.T) {
 		router, lastMiddlewareWasCalled := createRouter(errorHandler)
 		recorder := httptest.NewRecorder()
 		request, _ := http.NewRequest("GET", "/", nil)
+		request.Header.Set("Accept-Language", "en-US,en;q=0.9")
 
 		router.ServeHTTP(recorder, request)
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Equal(t, "an internal error ocurred", response.Messages[0])
+		assert.Equal(t, "an internal error occurred", response.Detail)
 		assert.False(t, *lastMiddlewareWasCalled)
 	})
 }
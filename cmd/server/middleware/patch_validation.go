@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/patch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	UnsupportedPatch = "request.unsupported_patch"
+	InvalidPatch     = "request.invalid_patch"
+	PatchTestFailed  = "request.patch_test_failed"
+)
+
+// PatchValidation applies the request body to the resource returned by load,
+// as a JSON Merge Patch (RFC 7396) or as a JSON Patch (RFC 6902) depending on
+// the Content-Type. The patched document is decoded into T and validated with
+// the same rules used on creation before being stored under "Request". The
+// resource loaded is stored under "Current" so the update is applied over the
+// version that was patched instead of loading it again.
+func PatchValidation[T any, R any](load func(id int) (R, error)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		locale := web.Locale(ctx)
+
+		apply := patch.MergePatch
+		switch ctx.ContentType() {
+		case patch.MergePatchContentType, gin.MIMEJSON:
+		case patch.JSONPatchContentType:
+			apply = patch.JSONPatch
+		default:
+			web.Error(ctx, http.StatusUnsupportedMediaType, UnsupportedPatch, ctx.ContentType(), patch.MergePatchContentType, patch.JSONPatchContentType)
+			ctx.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil || len(body) == 0 {
+			web.Error(ctx, http.StatusUnprocessableEntity, EmptyBody)
+			ctx.Abort()
+			return
+		}
+
+		current, err := load(ctx.GetInt("Id"))
+		if err != nil {
+			web.ErrorFrom(ctx, http.StatusNotFound, err)
+			ctx.Abort()
+			return
+		}
+
+		document, err := json.Marshal(current)
+		if err != nil {
+			panic(err)
+		}
+
+		patched, err := apply(document, body)
+		if err != nil {
+			if errors.Is(err, patch.ErrTestFailed) {
+				web.Error(ctx, http.StatusConflict, PatchTestFailed, err.Error())
+			} else {
+				web.Error(ctx, http.StatusUnprocessableEntity, InvalidPatch, err.Error())
+			}
+			ctx.Abort()
+			return
+		}
+
+		var request T
+		err = json.Unmarshal(patched, &request)
+		if err == nil {
+			err = binding.Validator.ValidateStruct(request)
+		}
+		if err != nil {
+			detail, fields := DescribeBindingError(locale, request, err)
+			web.Errors(ctx, http.StatusUnprocessableEntity, detail, fields)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set("Current", current)
+		ctx.Set("Request", request)
+	}
+}
@@ -0,0 +1,157 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type PatchedResource struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+}
+
+type PatchResourceRequest struct {
+	Name     *string `json:"name" binding:"required"`
+	Capacity *int    `json:"capacity" binding:"required,gte=0"`
+}
+
+func TestPatchValidationMiddleware(t *testing.T) {
+	t.Run("Should apply a merge patch keeping the omitted fields", func(t *testing.T) {
+		router, got := createPatchRouter()
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createPatchRequest(1, "application/merge-patch+json", `{"capacity":0}`))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "Section", *got.Name)
+		assert.Equal(t, 0, *got.Capacity)
+	})
+
+	t.Run("Should apply a merge patch sent as plain JSON", func(t *testing.T) {
+		router, got := createPatchRouter()
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createPatchRequest(1, "application/json", `{"name":"Renamed"}`))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "Renamed", *got.Name)
+		assert.Equal(t, 10, *got.Capacity)
+	})
+
+	t.Run("Should apply a JSON patch", func(t *testing.T) {
+		router, got := createPatchRouter()
+		recorder := httptest.NewRecorder()
+		operations := `[{"op":"test","path":"/capacity","value":10},{"op":"replace","path":"/capacity","value":20}]`
+
+		router.ServeHTTP(recorder, createPatchRequest(1, "application/json-patch+json", operations))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, 20, *got.Capacity)
+	})
+
+	t.Run("Should validate the patched resource with the request rules", func(t *testing.T) {
+		router, _ := createPatchRouter()
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createPatchRequest(1, "application/merge-patch+json", `{"name":null}`))
+
+		var response ProblemResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Equal(t, []web.FieldError{{Field: "name", Rule: "required", Message: "'name' é obrigatório"}}, response.Errors)
+	})
+
+	t.Run("Should have conflict error when a test operation fails", func(t *testing.T) {
+		router, _ := createPatchRouter()
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createPatchRequest(1, "application/json-patch+json", `[{"op":"test","path":"/capacity","value":5}]`))
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("Should have error when the patch is malformed", func(t *testing.T) {
+		router, _ := createPatchRouter()
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createPatchRequest(1, "application/json-patch+json", `{"op":"remove"}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	})
+
+	t.Run("Should have error when the content type is not supported", func(t *testing.T) {
+		router, _ := createPatchRouter()
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createPatchRequest(1, "text/plain", `name=Renamed`))
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+	})
+
+	t.Run("Should have not found error when the resource does not exist", func(t *testing.T) {
+		router, _ := createPatchRouter()
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createPatchRequest(2, "application/merge-patch+json", `{"capacity":0}`))
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Should keep the loaded resource for the update", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		var current *PatchedResource
+
+		load := func(id int) (*PatchedResource, error) {
+			return &PatchedResource{ID: id, Name: "Section", Capacity: 10}, nil
+		}
+
+		router.PATCH("/sections/:id", middleware.IdValidation(), middleware.PatchValidation[PatchResourceRequest](load), func(c *gin.Context) {
+			current = c.MustGet("Current").(*PatchedResource)
+			c.Status(http.StatusOK)
+		})
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, createPatchRequest(1, "application/merge-patch+json", `{"capacity":0}`))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, &PatchedResource{ID: 1, Name: "Section", Capacity: 10}, current)
+	})
+}
+
+func createPatchRouter() (*gin.Engine, *PatchResourceRequest) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var got PatchResourceRequest
+
+	load := func(id int) (*PatchedResource, error) {
+		if id != 1 {
+			return nil, apperr.NewResourceNotFound("section.not_found", id)
+		}
+		return &PatchedResource{ID: 1, Name: "Section", Capacity: 10}, nil
+	}
+
+	router.PATCH("/sections/:id", middleware.IdValidation(), middleware.PatchValidation[PatchResourceRequest](load), func(c *gin.Context) {
+		got = c.MustGet("Request").(PatchResourceRequest)
+		c.Status(http.StatusOK)
+	})
+	return router, &got
+}
+
+func createPatchRequest(id int, contentType string, body string) *http.Request {
+	request, _ := http.NewRequest("PATCH", "/sections/"+strconv.Itoa(id), bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", contentType)
+	return request
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/i18n"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+)
+
+const (
+	QueryParamContext = "Query"
+	InvalidQuery      = "request.invalid_query"
+)
+
+// QueryValidation binds the path params tagged with `uri` and the query string
+// params tagged with `form` into T, validates it and stores it on the context
+// under "Query".
+func QueryValidation[T any]() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var query T
+		locale := web.Locale(ctx)
+
+		path := make(map[string][]string)
+		for _, param := range ctx.Params {
+			path[param.Key] = []string{param.Value}
+		}
+
+		if fields := bindValues(locale, &query, path, "uri"); len(fields) > 0 {
+			web.Errors(ctx, http.StatusBadRequest, i18n.Translate(locale, InvalidQuery), fields)
+			ctx.Abort()
+			return
+		}
+
+		if fields := bindValues(locale, &query, ctx.Request.URL.Query(), "form"); len(fields) > 0 {
+			web.Errors(ctx, http.StatusBadRequest, i18n.Translate(locale, InvalidQuery), fields)
+			ctx.Abort()
+			return
+		}
+
+		if err := binding.Validator.ValidateStruct(query); err != nil {
+			fields := make([]web.FieldError, 0)
+			if validationErrors, ok := err.(validator.ValidationErrors); ok {
+				fields = describeValidationErrors(locale, query, validationErrors)
+			}
+
+			web.Errors(ctx, http.StatusBadRequest, i18n.Translate(locale, InvalidQuery), fields)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(QueryParamContext, query)
+	}
+}
+
+// bindValues maps values into the fields of target carrying the given tag, one
+// field at a time, so that a conversion failure can be reported for the field
+// that caused it. Slice fields take both repeated params and comma separated
+// lists, as in ?ids=1,2,3.
+func bindValues(locale language.Tag, target interface{}, values map[string][]string, tag string) []web.FieldError {
+	fields := make([]web.FieldError, 0)
+	structType := reflect.TypeOf(target).Elem()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := tagName(field, tag)
+		fieldValues, ok := values[name]
+		if name == "" || !ok {
+			continue
+		}
+
+		if indirectType(field.Type).Kind() == reflect.Slice {
+			fieldValues = splitValues(fieldValues)
+		}
+
+		err := binding.MapFormWithTag(target, map[string][]string{name: fieldValues}, tag)
+		if err != nil {
+			typeName := indirectType(field.Type).String()
+			fields = append(fields, web.FieldError{
+				Field:   name,
+				Rule:    "type",
+				Param:   typeName,
+				Message: i18n.Translate(locale, WrongType, name, typeName),
+			})
+		}
+	}
+
+	return fields
+}
+
+func splitValues(values []string) []string {
+	split := make([]string, 0, len(values))
+	for _, value := range values {
+		split = append(split, strings.Split(value, ",")...)
+	}
+	return split
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,143 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type PageQuery struct {
+	SectionID int     `uri:"section_id" binding:"required"`
+	Page      *int    `form:"page" binding:"omitempty,gt=0"`
+	Status    *string `form:"status" binding:"omitempty,oneof=open closed"`
+	Top       *int    `form:"top" binding:"omitempty,excluded_with=Page Status"`
+	IDs       []int   `form:"ids" binding:"omitempty,dive,gt=0"`
+}
+
+func TestQueryValidationMiddleware(t *testing.T) {
+	t.Run("Should bind path and query params", func(t *testing.T) {
+		router, got := createQueryRouter()
+		recorder := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", "/sections/7?page=2&status=open", nil)
+
+		router.ServeHTTP(recorder, request)
+
+		page := 2
+		status := "open"
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, PageQuery{SectionID: 7, Page: &page, Status: &status}, *got)
+	})
+
+	t.Run("Should keep optional params empty when not given", func(t *testing.T) {
+		router, got := createQueryRouter()
+		recorder := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", "/sections/7", nil)
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Nil(t, got.Page)
+		assert.Nil(t, got.Status)
+	})
+
+	t.Run("Should bind a list param given comma separated or repeated", func(t *testing.T) {
+		router, got := createQueryRouter()
+		recorder := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", "/sections/7?ids=1,2&ids=3", nil)
+
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, []int{1, 2, 3}, got.IDs)
+	})
+
+	t.Run("Should have error when an item of a list param has a wrong type", func(t *testing.T) {
+		router, _ := createQueryRouter()
+		recorder := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", "/sections/7?ids=1,abc", nil)
+
+		router.ServeHTTP(recorder, request)
+
+		var response ProblemResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, []web.FieldError{{Field: "ids", Rule: "type", Param: "[]int", Message: "o campo 'ids' deve ser '[]int'"}}, response.Errors)
+	})
+
+	t.Run("Should have error when a param has a wrong type", func(t *testing.T) {
+		router, _ := createQueryRouter()
+		recorder := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", "/sections/7?page=abc", nil)
+
+		router.ServeHTTP(recorder, request)
+
+		var response ProblemResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, "os parâmetros da requisição são inválidos", response.Detail)
+		assert.Equal(t, []web.FieldError{{Field: "page", Rule: "type", Param: "int", Message: "o campo 'page' deve ser 'int'"}}, response.Errors)
+	})
+
+	t.Run("Should have error when a path param has a wrong type", func(t *testing.T) {
+		router, _ := createQueryRouter()
+		recorder := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", "/sections/abc", nil)
+
+		router.ServeHTTP(recorder, request)
+
+		var response ProblemResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, "section_id", response.Errors[0].Field)
+	})
+
+	t.Run("Should have error when a param breaks a validation rule", func(t *testing.T) {
+		router, _ := createQueryRouter()
+		recorder := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", "/sections/7?page=0&status=pending", nil)
+
+		router.ServeHTTP(recorder, request)
+
+		var response ProblemResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Len(t, response.Errors, 2)
+		assert.Equal(t, web.FieldError{Field: "page", Rule: "gt", Param: "0", Message: "'page' precisa ser maior que 0"}, response.Errors[0])
+		assert.Equal(t, web.FieldError{Field: "status", Rule: "oneof", Param: "open closed", Message: "'status' precisa ser um dos seguintes valores: open, closed"}, response.Errors[1])
+	})
+
+	t.Run("Should have error when a param is used together with an excluded one", func(t *testing.T) {
+		router, _ := createQueryRouter()
+		recorder := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", "/sections/7?top=3&status=open", nil)
+
+		router.ServeHTTP(recorder, request)
+
+		var response ProblemResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, []web.FieldError{{Field: "top", Rule: "excluded_with", Param: "Page Status", Message: "'top' não pode ser usado junto com page, status"}}, response.Errors)
+	})
+}
+
+func createQueryRouter() (*gin.Engine, *PageQuery) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var got PageQuery
+	router.GET("/sections/:section_id", middleware.QueryValidation[PageQuery](), func(c *gin.Context) {
+		got = c.MustGet(middleware.QueryParamContext).(PageQuery)
+		c.Status(http.StatusOK)
+	})
+	return router, &got
+}
@@ -3,53 +3,39 @@ package middleware
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
 	"reflect"
 	"strings"
 
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/i18n"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
 )
 
 const (
-	CannotBeBlank = "pelo menos um dos seguintes campos deve ser informado para modificações: %v"
+	CannotBeBlank     = "request.cannot_be_blank"
+	EmptyBody         = "request.empty_body"
+	SyntaxError       = "request.syntax_error"
+	WrongType         = "request.wrong_type"
+	InvalidFields     = "request.invalid_fields"
+	UnknownValidation = "validation.unknown"
 )
 
+// sizedTags are the validator tags whose message depends on whether the field
+// is a text, a number or a collection.
+var sizedTags = map[string]bool{"gt": true, "gte": true, "lt": true, "lte": true, "min": true, "max": true, "len": true}
+
+var datetimeLayout = strings.NewReplacer("2006", "yyyy", "01", "mm", "02", "dd", "15", "hh", "04", "mm", "05", "ss")
+
 func RequestValidation[T any](canBeBlank bool) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var request T
 		if err := ctx.ShouldBindJSON(&request); err != nil {
-			status := http.StatusUnprocessableEntity
-			errorMessages := make([]string, 0)
-
-			if errors.Is(err, io.EOF) {
-				errorMessages = append(errorMessages, "o corpo da requisição está vazio e precisa ser um objeto JSON válido")
-			}
-
-			if syntaxError, ok := err.(*json.SyntaxError); ok {
-				errorMessages = append(errorMessages, fmt.Sprintf("erro de sintaxe na posição %d: %v", syntaxError.Offset, syntaxError.Error()))
-			}
-
-			if marshallingError, ok := err.(*json.UnmarshalTypeError); ok {
-				errorMessages = append(errorMessages, fmt.Sprintf("o campo '%s' deve ser '%s'", marshallingError.Field, marshallingError.Type.String()))
-			}
-
-			if validationErrors, ok := err.(validator.ValidationErrors); ok {
-				for _, err := range validationErrors {
-					errorMessages = append(errorMessages, readableMessageFrom(request, err))
-				}
-			}
-
-			response := web.ErrorResponse{
-				Code:     strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"),
-				Messages: errorMessages,
-				Status:   status,
-			}
-
-			web.Response(ctx, status, response)
+			detail, fields := DescribeBindingError(web.Locale(ctx), request, err)
+			web.Errors(ctx, http.StatusUnprocessableEntity, detail, fields)
 			ctx.Abort()
 			return
 		}
@@ -66,32 +52,113 @@ func RequestValidation[T any](canBeBlank bool) gin.HandlerFunc {
 	}
 }
 
-func readableMessageFrom(structValue interface{}, fe validator.FieldError) string {
-	var message string
-	switch fe.Tag() {
-	case "required":
-		message = fmt.Sprintf("'%s' é obrigatório", getFieldNameOfFieldError(structValue, fe))
-	case "e164":
-		message = fmt.Sprintf("'%s' precisa estar no formato +<country_code><zone_code><phone_number> sem espaços ou caracteres especiais, por exemplo: +5500123456789", getFieldNameOfFieldError(structValue, fe))
-	case "datetime":
-		message = fmt.Sprintf("'%s' precisa estar no formato yyyy-mm-dd hh:mm:ss", getFieldNameOfFieldError(structValue, fe))
-	case "gt":
-		message = fmt.Sprintf("'%s' precisa ter mais de 3 caracteres", getFieldNameOfFieldError(structValue, fe))
-	default:
-		message = "erro desconhecido"
+// DescribeBindingError returns the localized detail of an error binding or
+// validating a request, and the fields it is about.
+func DescribeBindingError(locale language.Tag, request interface{}, err error) (string, []web.FieldError) {
+	if errors.Is(err, io.EOF) {
+		return i18n.Translate(locale, EmptyBody), nil
+	}
+
+	if syntaxError, ok := err.(*json.SyntaxError); ok {
+		return i18n.Translate(locale, SyntaxError, syntaxError.Offset, syntaxError.Error()), nil
+	}
+
+	if marshallingError, ok := err.(*json.UnmarshalTypeError); ok {
+		message := i18n.Translate(locale, WrongType, marshallingError.Field, marshallingError.Type.String())
+		return message, []web.FieldError{{
+			Field:   marshallingError.Field,
+			Rule:    "type",
+			Param:   marshallingError.Type.String(),
+			Message: message,
+		}}
+	}
+
+	fields := make([]web.FieldError, 0)
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		fields = describeValidationErrors(locale, request, validationErrors)
+	}
+
+	return i18n.Translate(locale, InvalidFields), fields
+}
+
+func describeValidationErrors(locale language.Tag, request interface{}, validationErrors validator.ValidationErrors) []web.FieldError {
+	fields := make([]web.FieldError, 0)
+	for _, fe := range validationErrors {
+		fields = append(fields, web.FieldError{
+			Field:   getFieldNameOfFieldError(request, fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: readableMessageFrom(locale, request, fe),
+		})
 	}
 
-	return strings.ToLower(message)
+	return fields
+}
+
+func readableMessageFrom(locale language.Tag, structValue interface{}, fe validator.FieldError) string {
+	field := getFieldNameOfFieldError(structValue, fe)
+	param := fe.Param()
+	key := "validation." + fe.Tag()
+
+	if sizedTags[fe.Tag()] {
+		key += "." + sizeKindOf(fe.Kind())
+	}
+	if fe.Tag() == "datetime" {
+		param = datetimeLayout.Replace(param)
+	}
+	if fe.Tag() == "oneof" {
+		param = strings.ReplaceAll(param, " ", ", ")
+	}
+	if fe.Tag() == "excluded_with" {
+		names := strings.Fields(param)
+		for i, name := range names {
+			names[i] = externalFieldName(reflect.TypeOf(structValue), name)
+		}
+		param = strings.Join(names, ", ")
+	}
+	if !i18n.Has(key) {
+		key = UnknownValidation
+	}
+
+	return i18n.Translate(locale, key, field, param, fe.Tag())
+}
+
+func sizeKindOf(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	default:
+		return "number"
+	}
 }
 
 func getFieldNameOfFieldError(structValue interface{}, err validator.FieldError) string {
-	structType := reflect.TypeOf(structValue)
 	fieldName := strings.SplitN(err.Namespace(), ".", 2)[1]
+	return externalFieldName(reflect.TypeOf(structValue), fieldName)
+}
+
+// externalFieldName returns the name a client uses for the field, taken from
+// its json, form or uri tag.
+func externalFieldName(structType reflect.Type, fieldName string) string {
 	field, _ := structType.FieldByName(fieldName)
 
-	jsonTag := field.Tag.Get("json")
-	jsonTag = strings.Split(jsonTag, ",")[0]
-	return jsonTag
+	for _, tag := range []string{"json", "form", "uri"} {
+		if name := tagName(field, tag); name != "" {
+			return name
+		}
+	}
+
+	return fieldName
+}
+
+func tagName(field reflect.StructField, tag string) string {
+	name := strings.Split(field.Tag.Get(tag), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
 }
 
 func getFieldNames(structValue interface{}) []string {
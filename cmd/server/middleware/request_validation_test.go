@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,7 +30,11 @@ type MissingRequiredFieldRequest struct {
 }
 
 type UnknownValidationTagRequest struct {
-	FieldA *string `json:"field_a" binding:"lte=3"`
+	FieldA *string `json:"field_a" binding:"uuid"`
+}
+
+type NumberLimitRequest struct {
+	FieldA *int `json:"field_a" binding:"gt=10"`
 }
 
 type ErrorResponse struct {
@@ -38,6 +43,14 @@ type ErrorResponse struct {
 	Status   int      `json:"status"`
 }
 
+type ProblemResponse struct {
+	Type   string           `json:"type"`
+	Title  string           `json:"title"`
+	Status int              `json:"status"`
+	Detail string           `json:"detail"`
+	Errors []web.FieldError `json:"errors"`
+}
+
 func TestValidationMiddleware(t *testing.T) {
 	fieldA := "Field A"
 	fieldB := "+5500123456789"
@@ -62,12 +75,12 @@ func TestValidationMiddleware(t *testing.T) {
 
 		middleware.RequestValidation[CorrectRequest](true)(context)
 
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Equal(t, "o corpo da requisição está vazio e precisa ser um objeto JSON válido", response.Messages[0])
+		assert.Empty(t, response.Errors)
+		assert.Equal(t, "o corpo da requisição está vazio e precisa ser um objeto JSON válido", response.Detail)
 		assert.True(t, context.IsAborted())
 	})
 
@@ -77,12 +90,12 @@ func TestValidationMiddleware(t *testing.T) {
 
 		middleware.RequestValidation[CorrectRequest](true)(context)
 
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Contains(t, response.Messages[0], "erro de sintaxe na posição")
+		assert.Empty(t, response.Errors)
+		assert.Contains(t, response.Detail, "erro de sintaxe na posição")
 		assert.True(t, context.IsAborted())
 	})
 
@@ -92,12 +105,12 @@ func TestValidationMiddleware(t *testing.T) {
 
 		middleware.RequestValidation[CorrectRequest](true)(context)
 
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Equal(t, "o campo 'field_a' deve ser 'string'", response.Messages[0])
+		assert.Len(t, response.Errors, 1)
+		assert.Equal(t, "o campo 'field_a' deve ser 'string'", response.Errors[0].Message)
 		assert.True(t, context.IsAborted())
 	})
 
@@ -107,12 +120,12 @@ func TestValidationMiddleware(t *testing.T) {
 
 		middleware.RequestValidation[CorrectRequest](true)(context)
 
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Equal(t, "'field_a' é obrigatório", response.Messages[0])
+		assert.Len(t, response.Errors, 1)
+		assert.Equal(t, "'field_a' é obrigatório", response.Errors[0].Message)
 		assert.True(t, context.IsAborted())
 	})
 
@@ -122,12 +135,12 @@ func TestValidationMiddleware(t *testing.T) {
 
 		middleware.RequestValidation[CorrectRequest](true)(context)
 
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Equal(t, "'field_b' precisa estar no formato +<country_code><zone_code><phone_number> sem espaços ou caracteres especiais, por exemplo: +5500123456789", response.Messages[0])
+		assert.Len(t, response.Errors, 1)
+		assert.Equal(t, "'field_b' precisa estar no formato +<country_code><zone_code><phone_number> sem espaços ou caracteres especiais, por exemplo: +5500123456789", response.Errors[0].Message)
 		assert.True(t, context.IsAborted())
 	})
 
@@ -137,12 +150,12 @@ func TestValidationMiddleware(t *testing.T) {
 
 		middleware.RequestValidation[CorrectRequest](true)(context)
 
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Equal(t, "'field_c' precisa estar no formato yyyy-mm-dd hh:mm:ss", response.Messages[0])
+		assert.Len(t, response.Errors, 1)
+		assert.Equal(t, "'field_c' precisa estar no formato yyyy-mm-dd hh:mm:ss", response.Errors[0].Message)
 		assert.True(t, context.IsAborted())
 	})
 
@@ -152,12 +165,12 @@ func TestValidationMiddleware(t *testing.T) {
 
 		middleware.RequestValidation[CorrectRequest](true)(context)
 
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Equal(t, "'field_d' precisa ter mais de 3 caracteres", response.Messages[0])
+		assert.Len(t, response.Errors, 1)
+		assert.Equal(t, "'field_d' precisa ter mais de 3 caracteres", response.Errors[0].Message)
 		assert.True(t, context.IsAborted())
 	})
 
@@ -167,27 +180,88 @@ func TestValidationMiddleware(t *testing.T) {
 
 		middleware.RequestValidation[UnknownValidationTagRequest](true)(context)
 
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Equal(t, "erro desconhecido", response.Messages[0])
+		assert.Len(t, response.Errors, 1)
+		assert.Equal(t, "'field_a' não atende à regra 'uuid'", response.Errors[0].Message)
 		assert.True(t, context.IsAborted())
 	})
 
+	t.Run("Should have error message with the real param of a number rule", func(t *testing.T) {
+		fieldA := 5
+		request := NumberLimitRequest{&fieldA}
+		context, recorder, _ := createValidationContext(request, getMarshaledRequestInBytes[NumberLimitRequest])
+
+		middleware.RequestValidation[NumberLimitRequest](true)(context)
+
+		var response ProblemResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Len(t, response.Errors, 1)
+		assert.Equal(t, "'field_a' precisa ser maior que 10", response.Errors[0].Message)
+	})
+
+	t.Run("Should have error messages in the language asked by the client", func(t *testing.T) {
+		request := createCorrectRequest(fieldA, fieldB, "Date", fieldD)
+		context, recorder, _ := createValidationContext(request, getMarshaledRequestInBytes[CorrectRequest])
+		context.Request.Header = http.Header{"Accept-Language": []string{"es-AR,es;q=0.9"}}
+
+		middleware.RequestValidation[CorrectRequest](true)(context)
+
+		var response ProblemResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Equal(t, "la solicitud tiene campos inválidos", response.Detail)
+		assert.Equal(t, "'field_c' debe estar en el formato yyyy-mm-dd hh:mm:ss", response.Errors[0].Message)
+	})
+
+	t.Run("Should describe the failed rule and its param for each field", func(t *testing.T) {
+		request := createCorrectRequest(fieldA, fieldB, fieldC, "a")
+		context, recorder, _ := createValidationContext(request, getMarshaledRequestInBytes[CorrectRequest])
+
+		middleware.RequestValidation[CorrectRequest](true)(context)
+
+		var response ProblemResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, web.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Status)
+		assert.Equal(t, "Unprocessable Entity", response.Title)
+		assert.Equal(t, "a requisição possui campos inválidos", response.Detail)
+		assert.Equal(t, web.FieldError{Field: "field_d", Rule: "gt", Param: "3", Message: "'field_d' precisa ter mais de 3 caracteres"}, response.Errors[0])
+	})
+
+	t.Run("Should have legacy error response when requested by header", func(t *testing.T) {
+		request := createMissingRequiredFieldRequest(fieldB, fieldC, fieldD)
+		context, recorder, _ := createValidationContext(request, getMarshaledRequestInBytes[MissingRequiredFieldRequest])
+		context.Request.Header = http.Header{web.ErrorFormatHeader: []string{web.LegacyErrorFormat}}
+
+		middleware.RequestValidation[CorrectRequest](true)(context)
+
+		var response ErrorResponse
+		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Equal(t, "unprocessable_entity", response.Code)
+		assert.Equal(t, []string{"'field_a' é obrigatório"}, response.Messages)
+	})
+
 	t.Run("Should have error when request that 'cannot be blank' is blank", func(t *testing.T) {
 		request := createEmptyRequest()
 		context, recorder, _ := createValidationContext(request, getStringRequestInBytes)
 
 		middleware.RequestValidation[MissingRequiredFieldRequest](false)(context)
 
-		var response ErrorResponse
+		var response ProblemResponse
 		_ = json.Unmarshal(recorder.Body.Bytes(), &response)
 
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
-		assert.Len(t, response.Messages, 1)
-		assert.Contains(t, response.Messages[0], "pelo menos um dos seguintes campos deve ser informado para modificações:")
+		assert.Empty(t, response.Errors)
+		assert.Contains(t, response.Detail, "pelo menos um dos seguintes campos deve ser informado para modificações:")
 		assert.True(t, context.IsAborted())
 	})
 
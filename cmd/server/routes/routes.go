@@ -1,18 +1,34 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
+	"log"
+	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/graph"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/rpc"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/rpc/pb"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/docs"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/activity"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/allocation"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/carrier"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/cycle_count"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/idempotency"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/inbound_order"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/job"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/locality"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/lot"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/order_status"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_record"
@@ -21,25 +37,54 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/purchase_order"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/stock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/temperature"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/webhook"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
 )
 
 const (
-	CreateCanBeBlank = true
-	UpdateCanBeBlank = false
+	CreateCanBeBlank      = true
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// DefaultIdempotencyLease is how long a key stays reserved while its
+	// request is handled, longer than any request should take.
+	DefaultIdempotencyLease = time.Minute
+	// DefaultMinimumShelfLife keeps batches due within a week from being
+	// reserved for purchase orders.
+	DefaultMinimumShelfLife  = 7 * 24 * time.Hour
+	DefaultJobWorkers        = 2
+	DefaultJobPollInterval   = time.Second
+	DefaultOutboxInterval    = time.Second
+	DefaultWebhookAttempts   = 5
+	DefaultWebhookBackoff    = time.Second
+	DefaultWebhookTimeout    = 10 * time.Second
+	DefaultWebhookInterval   = time.Second
+	DefaultActivityHeartbeat = 15 * time.Second
+	DefaultGraphQLDepth      = 8
+	DefaultGraphQLComplexity = 1000
+	DefaultGRPCAddress       = ":9090"
 )
 
 type IRouter interface {
 	MapRoutes()
+	Start(ctx context.Context)
+	Stop()
 }
 
 type router struct {
-	eng *gin.Engine
-	rg  *gin.RouterGroup
-	db  *sql.DB
+	eng        *gin.Engine
+	rg         *gin.RouterGroup
+	db         *sql.DB
+	jobs       *job.Pool
+	jobHandler *handler.Job
+	events     *outbox.Dispatcher
+	webhooks   *webhook.Deliverer
+	sinks      []outbox.Sink
+	rpc        *grpc.Server
 }
 
 func NewRouter(eng *gin.Engine, db *sql.DB) IRouter {
@@ -51,6 +96,8 @@ func (r *router) MapRoutes() {
 
 	r.buildDocumentationRoutes()
 	r.defineGlobalMiddlewares()
+	r.buildJobRoutes()
+	r.buildRPCServer()
 	r.buildSellerRoutes()
 	r.buildProductRoutes()
 	r.buildSectionRoutes()
@@ -63,6 +110,27 @@ func (r *router) MapRoutes() {
 	r.buildPurchaseOrderRoutes()
 	r.buildInboundOrderRoutes()
 	r.buildProductBatchRoutes()
+	r.buildStockRoutes()
+	r.buildTemperatureRoutes()
+	r.buildLotRoutes()
+	r.buildCycleCountRoutes()
+	r.buildWebhookRoutes()
+	r.buildGraphQLRoutes()
+	r.buildOutbox()
+}
+
+// Start runs the job workers, the outbox dispatcher and the webhook deliverer
+// until ctx is done, and serves the gRPC API. Routes must be mapped first.
+func (r *router) Start(ctx context.Context) {
+	r.jobs.Start(ctx)
+	r.events.Start(ctx)
+	r.webhooks.Start(ctx)
+	r.serveRPC()
+}
+
+// Stop stops the gRPC server once the calls in progress end.
+func (r *router) Stop() {
+	r.rpc.GracefulStop()
 }
 
 func (r *router) setGroup() {
@@ -74,6 +142,95 @@ func (r *router) defineGlobalMiddlewares() {
 	r.rg.Use(middleware.IdValidation())
 }
 
+func (r *router) idempotency() gin.HandlerFunc {
+	ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL"))
+	if err != nil {
+		ttl = DefaultIdempotencyTTL
+	}
+	lease, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_LEASE"))
+	if err != nil {
+		lease = DefaultIdempotencyLease
+	}
+
+	return middleware.Idempotency(idempotency.NewRepository(r.db), ttl, lease)
+}
+
+func (r *router) minimumShelfLife() time.Duration {
+	shelfLife, err := time.ParseDuration(os.Getenv("MINIMUM_SHELF_LIFE"))
+	if err != nil {
+		return DefaultMinimumShelfLife
+	}
+
+	return shelfLife
+}
+
+// buildJobRoutes creates the worker pool the other routes register the runners
+// of their background jobs in. It is started once every route is built.
+func (r *router) buildJobRoutes() {
+	workers, err := strconv.Atoi(os.Getenv("JOB_WORKERS"))
+	if err != nil || workers <= 0 {
+		workers = DefaultJobWorkers
+	}
+	interval, err := time.ParseDuration(os.Getenv("JOB_POLL_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = DefaultJobPollInterval
+	}
+
+	repo := job.NewRepository(r.db)
+	service := job.NewService(repo)
+	r.jobs = job.NewPool(repo, workers, interval)
+	r.jobHandler = handler.NewJob(service)
+	jobRoutes := r.rg.Group("/jobs")
+
+	jobRoutes.GET("/:id", r.jobHandler.Get())
+	jobRoutes.GET("/:id/result", r.jobHandler.Result())
+	jobRoutes.POST("/:id/cancel", r.jobHandler.Cancel())
+}
+
+// buildRPCServer creates the gRPC server the other routes register the
+// services of the gRPC API in, next to their REST routes. It is served once
+// every route is built.
+func (r *router) buildRPCServer() {
+	r.rpc = rpc.NewServer()
+}
+
+// serveRPC serves the gRPC API on its own address, GRPC_ADDRESS, in the
+// background.
+func (r *router) serveRPC() {
+	address := os.Getenv("GRPC_ADDRESS")
+	if address == "" {
+		address = DefaultGRPCAddress
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Printf("grpc: could not listen on %s: %v", address, err)
+		return
+	}
+
+	go func() {
+		if err := r.rpc.Serve(listener); err != nil {
+			log.Printf("grpc: server stopped: %v", err)
+		}
+	}()
+}
+
+// buildOutbox creates the dispatcher that delivers the domain events the
+// repositories record in the outbox to the sinks the other routes added. The
+// events are also logged when OUTBOX_LOG_EVENTS is set, for debugging.
+func (r *router) buildOutbox() {
+	interval, err := time.ParseDuration(os.Getenv("OUTBOX_POLL_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = DefaultOutboxInterval
+	}
+
+	sinks := r.sinks
+	if logEvents, _ := strconv.ParseBool(os.Getenv("OUTBOX_LOG_EVENTS")); logEvents {
+		sinks = append(sinks, outbox.LogSink{})
+	}
+	r.events = outbox.NewDispatcher(outbox.NewRepository(r.db), interval, sinks...)
+}
+
 func (r *router) buildDocumentationRoutes() {
 	docs.SwaggerInfo.BasePath = "/api/v1"
 	docs.SwaggerInfo.Host = os.Getenv("HOST")
@@ -85,12 +242,15 @@ func (r *router) buildSellerRoutes() {
 	localityRepo := locality.NewRepository(r.db)
 	service := seller.NewService(repo, localityRepo)
 	controller := handler.NewSeller(service)
+	r.jobs.Register(job.KindSellerImport, handler.ImportRunner(handler.CreateSellerRequest.ToSeller, service.Import))
 	sellerRoutes := r.rg.Group("/sellers")
 
-	sellerRoutes.GET("/", controller.GetAll())
+	sellerRoutes.GET("/", middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
 	sellerRoutes.GET("/:id", controller.Get())
 	sellerRoutes.POST("/", middleware.RequestValidation[handler.CreateSellerRequest](CreateCanBeBlank), controller.Create())
-	sellerRoutes.PATCH("/:id", middleware.RequestValidation[handler.UpdateSellerRequest](UpdateCanBeBlank), controller.Update())
+	sellerRoutes.POST("/import", middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateSellerRequest](), controller.Import())
+	sellerRoutes.POST("/import/jobs", middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateSellerRequest](), r.jobHandler.EnqueueImport(job.KindSellerImport))
+	sellerRoutes.PATCH("/:id", middleware.PatchValidation[handler.CreateSellerRequest](service.Get), controller.Update())
 	sellerRoutes.DELETE("/:id", controller.Delete())
 }
 
@@ -100,14 +260,20 @@ func (r *router) buildProductRoutes() {
 	sellerRepo := seller.NewRepository(r.db)
 	service := product.NewService(repo, productTypeRepo, sellerRepo)
 	controller := handler.NewProduct(service)
+	pb.RegisterProductServiceServer(r.rpc, rpc.NewProduct(service))
+	r.jobs.Register(job.KindProductImport, handler.ImportRunner(handler.CreateProductRequest.ToProduct, service.Import))
+	r.jobs.Register(job.KindProductReport, handler.ReportRunner(service.CountRecordsByAllProducts))
 	productRoutes := r.rg.Group("/products")
 
-	productRoutes.GET("/", controller.GetAll())
+	productRoutes.GET("/", middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
 	productRoutes.GET("/:id", controller.Get())
 	productRoutes.POST("/", middleware.RequestValidation[handler.CreateProductRequest](CreateCanBeBlank), controller.Create())
-	productRoutes.PATCH("/:id", middleware.RequestValidation[handler.UpdateProductRequest](UpdateCanBeBlank), controller.Update())
+	productRoutes.POST("/import", middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateProductRequest](), controller.Import())
+	productRoutes.POST("/import/jobs", middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateProductRequest](), r.jobHandler.EnqueueImport(job.KindProductImport))
+	productRoutes.PATCH("/:id", middleware.PatchValidation[handler.CreateProductRequest](service.Get), controller.Update())
 	productRoutes.DELETE("/:id", controller.Delete())
-	productRoutes.GET("/report-records", controller.ReportRecords())
+	productRoutes.GET("/report-records", middleware.QueryValidation[handler.ReportQuery](), controller.ReportRecords())
+	productRoutes.POST("/report-records/jobs", middleware.QueryValidation[handler.ReportJobQuery](), r.jobHandler.EnqueueReport(job.KindProductReport))
 }
 
 func (r *router) buildSectionRoutes() {
@@ -116,14 +282,18 @@ func (r *router) buildSectionRoutes() {
 	productTypeRepository := product_type.NewRepository(r.db)
 	service := section.NewService(repository, warehouseRepository, productTypeRepository)
 	controller := handler.NewSection(service)
+	pb.RegisterSectionServiceServer(r.rpc, rpc.NewSection(service))
+	r.jobs.Register(job.KindSectionReport, handler.ReportRunner(service.CountProductsByAllSections))
 	sectionRoutes := r.rg.Group("/sections")
 
-	sectionRoutes.GET("/", controller.GetAll())
+	sectionRoutes.GET("/", middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
 	sectionRoutes.POST("/", middleware.RequestValidation[handler.CreateSectionRequest](CreateCanBeBlank), controller.Create())
-	sectionRoutes.PATCH("/:id", middleware.RequestValidation[handler.UpdateSectionRequest](UpdateCanBeBlank), controller.Update())
+	sectionRoutes.PATCH("/:id", middleware.PatchValidation[handler.CreateSectionRequest](service.Get), controller.Update())
 	sectionRoutes.GET("/:id", controller.Get())
 	sectionRoutes.DELETE("/:id", controller.Delete())
-	sectionRoutes.GET("/report-products", controller.ReportProducts())
+	sectionRoutes.GET("/report-products", middleware.QueryValidation[handler.ReportQuery](), controller.ReportProducts())
+	sectionRoutes.POST("/report-products/jobs", middleware.QueryValidation[handler.ReportJobQuery](), r.jobHandler.EnqueueReport(job.KindSectionReport))
+	sectionRoutes.GET("/:id/occupancy", controller.Occupancy())
 }
 
 func (r *router) buildWarehouseRoutes() {
@@ -131,13 +301,37 @@ func (r *router) buildWarehouseRoutes() {
 	localityRepo := locality.NewRepository(r.db)
 	service := warehouse.NewService(repo, localityRepo)
 	controller := handler.NewWarehouse(service)
+	activityController := handler.NewWarehouseActivity(service, r.activityBroker(), r.activityHeartbeat())
 	warehouseRoutes := r.rg.Group("/warehouses")
 
-	warehouseRoutes.GET("/", controller.GetAll())
+	warehouseRoutes.GET("/", middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
 	warehouseRoutes.GET("/:id", controller.Get())
 	warehouseRoutes.POST("/", middleware.RequestValidation[handler.CreateWarehouseRequest](CreateCanBeBlank), controller.Create())
-	warehouseRoutes.PATCH("/:id", middleware.RequestValidation[handler.UpdateWarehouseRequest](UpdateCanBeBlank), controller.Update())
+	warehouseRoutes.PATCH("/:id", middleware.PatchValidation[handler.CreateWarehouseRequest](service.Get), controller.Update())
 	warehouseRoutes.DELETE("/:id", controller.Delete())
+	warehouseRoutes.GET("/:id/events", activityController.Events())
+}
+
+// activityBroker creates the broker that streams the activity of the
+// warehouses, fed by the outbox.
+func (r *router) activityBroker() *activity.Broker {
+	size, err := strconv.Atoi(os.Getenv("ACTIVITY_BUFFER_SIZE"))
+	if err != nil || size <= 0 {
+		size = activity.DefaultBufferSize
+	}
+
+	broker := activity.NewBroker(section.NewRepository(r.db), size)
+	r.sinks = append(r.sinks, broker)
+	return broker
+}
+
+func (r *router) activityHeartbeat() time.Duration {
+	heartbeat, err := time.ParseDuration(os.Getenv("ACTIVITY_HEARTBEAT"))
+	if err != nil || heartbeat <= 0 {
+		return DefaultActivityHeartbeat
+	}
+
+	return heartbeat
 }
 
 func (r *router) buildEmployeeRoutes() {
@@ -145,13 +339,16 @@ func (r *router) buildEmployeeRoutes() {
 	warehouseRepository := warehouse.NewRepository(r.db)
 	service := employee.NewService(repository, warehouseRepository)
 	controller := handler.NewEmployee(service)
+	r.jobs.Register(job.KindEmployeeImport, handler.ImportRunner(handler.CreateEmployeeRequest.ToEmployee, service.Import))
 	employeeRoutes := r.rg.Group("/employees")
 
-	employeeRoutes.GET("/", controller.GetAll())
+	employeeRoutes.GET("/", middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
 	employeeRoutes.GET("/:id", controller.Get())
-	employeeRoutes.GET("/report-inbound-orders", controller.ReportInboundOrders())
+	employeeRoutes.GET("/report-inbound-orders", middleware.QueryValidation[handler.ReportQuery](), controller.ReportInboundOrders())
 	employeeRoutes.POST("/", middleware.RequestValidation[handler.CreateEmployeeRequest](CreateCanBeBlank), controller.Create())
-	employeeRoutes.PATCH("/:id", middleware.RequestValidation[handler.UpdateEmployeeRequest](UpdateCanBeBlank), controller.Update())
+	employeeRoutes.POST("/import", middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateEmployeeRequest](), controller.Import())
+	employeeRoutes.POST("/import/jobs", middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateEmployeeRequest](), r.jobHandler.EnqueueImport(job.KindEmployeeImport))
+	employeeRoutes.PATCH("/:id", middleware.PatchValidation[handler.CreateEmployeeRequest](service.Get), controller.Update())
 	employeeRoutes.DELETE("/:id", controller.Delete())
 }
 
@@ -159,14 +356,17 @@ func (r *router) buildBuyerRoutes() {
 	repo := buyer.NewRepository(r.db)
 	service := buyer.NewService(repo)
 	controller := handler.NewBuyer(service)
+	r.jobs.Register(job.KindBuyerImport, handler.ImportRunner(handler.CreateBuyerRequest.ToBuyer, service.Import))
 	buyerRoutes := r.rg.Group("/buyers")
 
-	buyerRoutes.GET("/", controller.GetAll())
+	buyerRoutes.GET("/", middleware.QueryValidation[handler.ListQuery](), controller.GetAll())
 	buyerRoutes.GET("/:id", controller.Get())
 	buyerRoutes.POST("/", middleware.RequestValidation[handler.CreateBuyerRequest](CreateCanBeBlank), controller.Create())
-	buyerRoutes.PATCH("/:id", middleware.RequestValidation[handler.UpdateBuyerRequest](UpdateCanBeBlank), controller.Update())
+	buyerRoutes.POST("/import", middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateBuyerRequest](), controller.Import())
+	buyerRoutes.POST("/import/jobs", middleware.QueryValidation[handler.ImportQuery](), middleware.ImportValidation[handler.CreateBuyerRequest](), r.jobHandler.EnqueueImport(job.KindBuyerImport))
+	buyerRoutes.PATCH("/:id", middleware.PatchValidation[handler.CreateBuyerRequest](service.Get), controller.Update())
 	buyerRoutes.DELETE("/:id", controller.Delete())
-	buyerRoutes.GET("/report-purchase-orders", controller.ReportPurchases())
+	buyerRoutes.GET("/report-purchase-orders", middleware.QueryValidation[handler.ReportQuery](), controller.ReportPurchases())
 }
 
 func (r *router) buildLocalityRoutes() {
@@ -177,8 +377,8 @@ func (r *router) buildLocalityRoutes() {
 	localityRoutes := r.rg.Group("/localities")
 
 	localityRoutes.POST("/", middleware.RequestValidation[handler.CreateLocalityRequest](CreateCanBeBlank), controller.Create())
-	localityRoutes.GET("/report-sellers", controller.ReportSellers())
-	localityRoutes.GET("/report-carriers", controller.ReportCarriers())
+	localityRoutes.GET("/report-sellers", middleware.QueryValidation[handler.RankingQuery](), controller.ReportSellers())
+	localityRoutes.GET("/report-carriers", middleware.QueryValidation[handler.RankingQuery](), controller.ReportCarriers())
 }
 
 func (r *router) buildCarrierRoutes() {
@@ -208,11 +408,17 @@ func (r *router) buildPurchaseOrderRoutes() {
 	warehouseRepo := warehouse.NewRepository(r.db)
 	carrierRepo := carrier.NewRepository(r.db)
 	productRecordRepo := product_record.NewRepository(r.db)
-	service := purchase_order.NewService(repo, buyerRepo, orderStatusRepo, warehouseRepo, carrierRepo, productRecordRepo)
+	allocationService := allocation.NewService(allocation.NewRepository(r.db), r.minimumShelfLife())
+	employeeRepo := employee.NewRepository(r.db)
+	service := purchase_order.NewService(repo, buyerRepo, orderStatusRepo, warehouseRepo, carrierRepo, productRecordRepo, allocationService, employeeRepo)
 	controller := handler.NewPurchaseOrder(service)
+	pb.RegisterPurchaseOrderServiceServer(r.rpc, rpc.NewPurchaseOrder(service))
 	purchaseOrdersRoutes := r.rg.Group("/purchase-orders")
 
-	purchaseOrdersRoutes.POST("/", middleware.RequestValidation[handler.CreatePurchaseOrderRequest](CreateCanBeBlank), controller.Create())
+	purchaseOrdersRoutes.POST("/", r.idempotency(), middleware.RequestValidation[handler.CreatePurchaseOrderRequest](CreateCanBeBlank), controller.Create())
+	purchaseOrdersRoutes.GET("/:id/pick-list", controller.PickList())
+	purchaseOrdersRoutes.POST("/:id/cancel", controller.Cancel())
+	purchaseOrdersRoutes.POST("/:id/picks", middleware.RequestValidation[handler.PickRequest](CreateCanBeBlank), controller.Pick())
 }
 
 func (r *router) buildInboundOrderRoutes() {
@@ -222,9 +428,10 @@ func (r *router) buildInboundOrderRoutes() {
 	repoWarehouse := warehouse.NewRepository(r.db)
 	service := inbound_order.NewService(repo, repoEmployee, repoProductBatch, repoWarehouse)
 	controller := handler.NewInboundOrder(service)
+	pb.RegisterInboundOrderServiceServer(r.rpc, rpc.NewInboundOrder(service))
 	inboundOrdersRoutes := r.rg.Group("/inbound-orders")
 
-	inboundOrdersRoutes.POST("/", middleware.RequestValidation[handler.CreateInboundOrderRequest](CreateCanBeBlank), controller.Create())
+	inboundOrdersRoutes.POST("/", r.idempotency(), middleware.RequestValidation[handler.CreateInboundOrderRequest](CreateCanBeBlank), controller.Create())
 }
 
 func (r *router) buildProductBatchRoutes() {
@@ -232,9 +439,143 @@ func (r *router) buildProductBatchRoutes() {
 	repo := product_batch.NewRepository(r.db)
 	productRepo := product.NewRepository(r.db)
 	sectionRepo := section.NewRepository(r.db)
-	service := product_batch.NewService(repo, productRepo, sectionRepo)
+	warehouseRepo := warehouse.NewRepository(r.db)
+	employeeRepo := employee.NewRepository(r.db)
+	service := product_batch.NewService(repo, productRepo, sectionRepo, warehouseRepo, employeeRepo)
 	controller := handler.NewProductBatches(service)
+	pb.RegisterProductBatchServiceServer(r.rpc, rpc.NewProductBatch(service))
 	productBatchesRoutes := r.rg.Group("/product-batches")
 
 	productBatchesRoutes.POST("/", middleware.RequestValidation[handler.CreateProductBatchRequest](CreateCanBeBlank), controller.Create())
+	productBatchesRoutes.POST("/putaway-suggestions", middleware.RequestValidation[handler.PutawaySuggestionRequest](CreateCanBeBlank), controller.SuggestPutaway())
+	productBatchesRoutes.POST("/:id/transfers", middleware.RequestValidation[handler.TransferRequest](CreateCanBeBlank), controller.Transfer())
+	productBatchesRoutes.GET("/:id/movements", controller.Movements())
+	productBatchesRoutes.POST("/:id/quarantine", middleware.RequestValidation[handler.BatchStatusRequest](CreateCanBeBlank), controller.Quarantine())
+	productBatchesRoutes.POST("/:id/release", middleware.RequestValidation[handler.BatchStatusRequest](CreateCanBeBlank), controller.Release())
+	productBatchesRoutes.POST("/:id/dispose", middleware.RequestValidation[handler.BatchStatusRequest](CreateCanBeBlank), controller.Dispose())
+	productBatchesRoutes.GET("/:id/status-changes", controller.StatusChanges())
+}
+
+func (r *router) buildStockRoutes() {
+	repo := stock.NewRepository(r.db)
+	productRepo := product.NewRepository(r.db)
+	service := stock.NewService(repo, productRepo)
+	controller := handler.NewStock(service)
+
+	r.rg.GET("/products/:id/stock", controller.ProductStock())
+	reportRoutes := r.rg.Group("/reports")
+	reportRoutes.GET("/stock", middleware.QueryValidation[handler.StockQuery](), controller.Report())
+}
+
+func (r *router) buildTemperatureRoutes() {
+	repo := temperature.NewRepository(r.db)
+	sectionRepo := section.NewRepository(r.db)
+	service := temperature.NewService(repo, sectionRepo)
+	controller := handler.NewTemperature(service)
+	sectionRoutes := r.rg.Group("/sections/:id")
+
+	sectionRoutes.POST("/temperature-readings", middleware.RequestValidation[handler.TemperatureReadingRequest](CreateCanBeBlank), controller.Record())
+	sectionRoutes.POST("/temperature-readings/batch", middleware.RequestValidation[handler.TemperatureReadingBatchRequest](CreateCanBeBlank), controller.RecordBatch())
+	sectionRoutes.GET("/temperature-readings", middleware.QueryValidation[handler.TemperatureReadingQuery](), controller.Readings())
+	sectionRoutes.GET("/temperature-breaches", controller.Breaches())
+}
+
+func (r *router) buildLotRoutes() {
+	repo := lot.NewRepository(r.db)
+	productBatchRepo := product_batch.NewRepository(r.db)
+	employeeRepo := employee.NewRepository(r.db)
+	service := lot.NewService(repo, productBatchRepo, employeeRepo)
+	controller := handler.NewLot(service)
+
+	r.rg.GET("/product-batches/:id/trace", controller.Trace())
+	recallRoutes := r.rg.Group("/recalls")
+	recallRoutes.POST("/", middleware.RequestValidation[handler.RecallRequest](CreateCanBeBlank), controller.CreateRecall())
+	recallRoutes.GET("/:id", controller.GetRecall())
+	recallRoutes.GET("/:id/affected-buyers", controller.AffectedBuyers())
+}
+
+func (r *router) buildCycleCountRoutes() {
+	repo := cycle_count.NewRepository(r.db)
+	sectionRepo := section.NewRepository(r.db)
+	employeeRepo := employee.NewRepository(r.db)
+	service := cycle_count.NewService(repo, sectionRepo, employeeRepo)
+	controller := handler.NewCycleCount(service)
+
+	r.rg.POST("/sections/:id/cycle-counts", middleware.RequestValidation[handler.CreateCycleCountRequest](CreateCanBeBlank), controller.Create())
+	cycleCountRoutes := r.rg.Group("/cycle-counts")
+	cycleCountRoutes.GET("/:id", controller.Get())
+	cycleCountRoutes.POST("/:id/assign", middleware.RequestValidation[handler.CycleCountEmployeeRequest](CreateCanBeBlank), controller.Assign())
+	cycleCountRoutes.POST("/:id/submit", middleware.RequestValidation[handler.SubmitCycleCountRequest](CreateCanBeBlank), controller.Submit())
+	cycleCountRoutes.POST("/:id/approve", middleware.RequestValidation[handler.CycleCountEmployeeRequest](CreateCanBeBlank), controller.Approve())
+	cycleCountRoutes.POST("/:id/reject", middleware.RequestValidation[handler.CycleCountEmployeeRequest](CreateCanBeBlank), controller.Reject())
+	r.rg.GET("/reports/cycle-count-variance", middleware.QueryValidation[handler.CycleCountVarianceQuery](), controller.VarianceReport())
+}
+
+func (r *router) buildWebhookRoutes() {
+	attempts, err := strconv.Atoi(os.Getenv("WEBHOOK_ATTEMPTS"))
+	if err != nil || attempts <= 0 {
+		attempts = DefaultWebhookAttempts
+	}
+	backoff, err := time.ParseDuration(os.Getenv("WEBHOOK_BACKOFF"))
+	if err != nil || backoff <= 0 {
+		backoff = DefaultWebhookBackoff
+	}
+	timeout, err := time.ParseDuration(os.Getenv("WEBHOOK_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	interval, err := time.ParseDuration(os.Getenv("WEBHOOK_POLL_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = DefaultWebhookInterval
+	}
+
+	repo := webhook.NewRepository(r.db)
+	deliverer := webhook.NewDeliverer(repo, &http.Client{Timeout: timeout}, attempts, backoff, interval)
+	service := webhook.NewService(repo)
+	controller := handler.NewWebhook(service)
+	r.sinks = append(r.sinks, deliverer)
+	r.webhooks = deliverer
+	webhookRoutes := r.rg.Group("/webhooks")
+
+	webhookRoutes.GET("/", controller.GetAll())
+	webhookRoutes.GET("/:id", controller.Get())
+	webhookRoutes.POST("/", middleware.RequestValidation[handler.CreateWebhookRequest](CreateCanBeBlank), controller.Create())
+	webhookRoutes.PATCH("/:id", middleware.PatchValidation[handler.CreateWebhookRequest](controller.Patchable), controller.Update())
+	webhookRoutes.DELETE("/:id", controller.Delete())
+	webhookRoutes.GET("/:id/deliveries", controller.Deliveries())
+	webhookRoutes.POST("/deliveries/:id/redeliver", controller.Redeliver())
+}
+
+func (r *router) buildGraphQLRoutes() {
+	maxDepth, err := strconv.Atoi(os.Getenv("GRAPHQL_MAX_DEPTH"))
+	if err != nil || maxDepth <= 0 {
+		maxDepth = DefaultGraphQLDepth
+	}
+	maxComplexity, err := strconv.Atoi(os.Getenv("GRAPHQL_MAX_COMPLEXITY"))
+	if err != nil || maxComplexity <= 0 {
+		maxComplexity = DefaultGraphQLComplexity
+	}
+
+	warehouseRepo := warehouse.NewRepository(r.db)
+	localityRepo := locality.NewRepository(r.db)
+	sectionRepo := section.NewRepository(r.db)
+	productRepo := product.NewRepository(r.db)
+	sellerRepo := seller.NewRepository(r.db)
+	productTypeRepo := product_type.NewRepository(r.db)
+	services := graph.Services{
+		Warehouses: warehouse.NewService(warehouseRepo, localityRepo),
+		Localities: locality.NewService(localityRepo, province.NewRepository(r.db)),
+		Sections:   section.NewService(sectionRepo, warehouseRepo, productTypeRepo),
+		Batches:    product_batch.NewService(product_batch.NewRepository(r.db), productRepo, sectionRepo, warehouseRepo, employee.NewRepository(r.db)),
+		Products:   product.NewService(productRepo, productTypeRepo, sellerRepo),
+		Sellers:    seller.NewService(sellerRepo, localityRepo),
+	}
+
+	g, err := graph.NewGraph(services, maxDepth, maxComplexity)
+	if err != nil {
+		panic(err)
+	}
+	controller := handler.NewGraphQL(g)
+
+	r.rg.POST("/graphql", middleware.RequestValidation[handler.GraphQLRequest](CreateCanBeBlank), controller.Query())
 }
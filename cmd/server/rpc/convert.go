@@ -0,0 +1,39 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The create requests are checked through the REST request types, whose
+// fields are nil when left out, so that both APIs share their rules.
+
+func optionalInt(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	converted := int(*value)
+	return &converted
+}
+
+func optionalDateTime(value *timestamppb.Timestamp) *string {
+	if value == nil {
+		return nil
+	}
+	formatted := helpers.ToFormattedDateTime(value.AsTime())
+	return &formatted
+}
+
+func ints(values []int32) []int {
+	converted := make([]int, 0, len(values))
+	for _, value := range values {
+		converted = append(converted, int(value))
+	}
+	return converted
+}
+
+func timestamp(value time.Time) *timestamppb.Timestamp {
+	return timestamppb.New(value)
+}
@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/middleware"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/i18n"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/web"
+	"github.com/gin-gonic/gin/binding"
+	"golang.org/x/text/language"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// LanguageMetadata is the metadata key clients ask for the language of the
+// error messages with, as they do with the Accept-Language header in REST.
+const LanguageMetadata = "accept-language"
+
+func localeFrom(ctx context.Context) language.Tag {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(LanguageMetadata)
+	if len(values) == 0 {
+		return i18n.Default
+	}
+	return i18n.LocaleFrom(values[0])
+}
+
+// codeFrom maps the errors of the services to the status codes that mean the
+// same as the HTTP statuses the REST handlers answer them with.
+func codeFrom(err error) codes.Code {
+	switch {
+	case apperr.Is[*apperr.ResourceNotFound](err):
+		return codes.NotFound
+	case apperr.Is[*apperr.ResourceAlreadyExists](err):
+		return codes.AlreadyExists
+	case apperr.Is[*apperr.DependentResourceNotFound](err), apperr.Is[*apperr.IncompatibleResource](err):
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+// statusFrom returns the status of an error of the services, with its
+// message in the language of the client.
+func statusFrom(ctx context.Context, err error) error {
+	return status.Error(codeFrom(err), web.Localize(localeFrom(ctx), err))
+}
+
+// validate checks the request against the rules of its REST counterpart.
+// The fields that break them are sent as the BadRequest details of an
+// InvalidArgument status.
+func validate(ctx context.Context, request interface{}) error {
+	err := binding.Validator.ValidateStruct(request)
+	if err == nil {
+		return nil
+	}
+
+	detail, fields := middleware.DescribeBindingError(localeFrom(ctx), request, err)
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fields))
+	for _, field := range fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field.Field, Description: field.Message})
+	}
+
+	invalid, detailsErr := status.New(codes.InvalidArgument, detail).WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, detail)
+	}
+	return invalid.Err()
+}
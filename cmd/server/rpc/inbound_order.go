@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/rpc/pb"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/inbound_order"
+)
+
+type InboundOrder struct {
+	pb.UnimplementedInboundOrderServiceServer
+	service inbound_order.Service
+}
+
+func NewInboundOrder(service inbound_order.Service) *InboundOrder {
+	return &InboundOrder{service: service}
+}
+
+func (i *InboundOrder) CreateInboundOrder(ctx context.Context, request *pb.CreateInboundOrderRequest) (*pb.InboundOrder, error) {
+	create := handler.CreateInboundOrderRequest{
+		OrderDate:      optionalDateTime(request.OrderDate),
+		OrderNumber:    request.OrderNumber,
+		EmployeeId:     optionalInt(request.EmployeeId),
+		ProductBatchId: optionalInt(request.ProductBatchId),
+		WarehouseId:    optionalInt(request.WarehouseId),
+	}
+	if err := validate(ctx, create); err != nil {
+		return nil, err
+	}
+
+	created, err := i.service.Create(create.ToInboundOrder())
+	if err != nil {
+		return nil, statusFrom(ctx, err)
+	}
+
+	return toInboundOrderMessage(*created), nil
+}
+
+func toInboundOrderMessage(order domain.InboundOrder) *pb.InboundOrder {
+	return &pb.InboundOrder{
+		Id:             int32(order.ID),
+		OrderDate:      timestamp(order.OrderDate),
+		OrderNumber:    order.OrderNumber,
+		EmployeeId:     int32(order.EmployeeId),
+		ProductBatchId: int32(order.ProductBatchId),
+		WarehouseId:    int32(order.WarehouseId),
+	}
+}
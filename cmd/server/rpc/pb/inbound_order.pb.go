@@ -0,0 +1,328 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.23.4
+// source: fresh/v1/inbound_order.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type InboundOrder struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderDate      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=order_date,json=orderDate,proto3" json:"order_date,omitempty"`
+	OrderNumber    string                 `protobuf:"bytes,3,opt,name=order_number,json=orderNumber,proto3" json:"order_number,omitempty"`
+	EmployeeId     int32                  `protobuf:"varint,4,opt,name=employee_id,json=employeeId,proto3" json:"employee_id,omitempty"`
+	ProductBatchId int32                  `protobuf:"varint,5,opt,name=product_batch_id,json=productBatchId,proto3" json:"product_batch_id,omitempty"`
+	WarehouseId    int32                  `protobuf:"varint,6,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+}
+
+func (x *InboundOrder) Reset() {
+	*x = InboundOrder{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fresh_v1_inbound_order_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InboundOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboundOrder) ProtoMessage() {}
+
+func (x *InboundOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_fresh_v1_inbound_order_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboundOrder.ProtoReflect.Descriptor instead.
+func (*InboundOrder) Descriptor() ([]byte, []int) {
+	return file_fresh_v1_inbound_order_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *InboundOrder) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *InboundOrder) GetOrderDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OrderDate
+	}
+	return nil
+}
+
+func (x *InboundOrder) GetOrderNumber() string {
+	if x != nil {
+		return x.OrderNumber
+	}
+	return ""
+}
+
+func (x *InboundOrder) GetEmployeeId() int32 {
+	if x != nil {
+		return x.EmployeeId
+	}
+	return 0
+}
+
+func (x *InboundOrder) GetProductBatchId() int32 {
+	if x != nil {
+		return x.ProductBatchId
+	}
+	return 0
+}
+
+func (x *InboundOrder) GetWarehouseId() int32 {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return 0
+}
+
+// CreateInboundOrderRequest is validated as the body of POST
+// /inbound-orders, every field being required.
+type CreateInboundOrderRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OrderDate      *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=order_date,json=orderDate,proto3" json:"order_date,omitempty"`
+	OrderNumber    *string                `protobuf:"bytes,2,opt,name=order_number,json=orderNumber,proto3,oneof" json:"order_number,omitempty"`
+	EmployeeId     *int32                 `protobuf:"varint,3,opt,name=employee_id,json=employeeId,proto3,oneof" json:"employee_id,omitempty"`
+	ProductBatchId *int32                 `protobuf:"varint,4,opt,name=product_batch_id,json=productBatchId,proto3,oneof" json:"product_batch_id,omitempty"`
+	WarehouseId    *int32                 `protobuf:"varint,5,opt,name=warehouse_id,json=warehouseId,proto3,oneof" json:"warehouse_id,omitempty"`
+}
+
+func (x *CreateInboundOrderRequest) Reset() {
+	*x = CreateInboundOrderRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fresh_v1_inbound_order_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateInboundOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateInboundOrderRequest) ProtoMessage() {}
+
+func (x *CreateInboundOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fresh_v1_inbound_order_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateInboundOrderRequest.ProtoReflect.Descriptor instead.
+func (*CreateInboundOrderRequest) Descriptor() ([]byte, []int) {
+	return file_fresh_v1_inbound_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateInboundOrderRequest) GetOrderDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OrderDate
+	}
+	return nil
+}
+
+func (x *CreateInboundOrderRequest) GetOrderNumber() string {
+	if x != nil && x.OrderNumber != nil {
+		return *x.OrderNumber
+	}
+	return ""
+}
+
+func (x *CreateInboundOrderRequest) GetEmployeeId() int32 {
+	if x != nil && x.EmployeeId != nil {
+		return *x.EmployeeId
+	}
+	return 0
+}
+
+func (x *CreateInboundOrderRequest) GetProductBatchId() int32 {
+	if x != nil && x.ProductBatchId != nil {
+		return *x.ProductBatchId
+	}
+	return 0
+}
+
+func (x *CreateInboundOrderRequest) GetWarehouseId() int32 {
+	if x != nil && x.WarehouseId != nil {
+		return *x.WarehouseId
+	}
+	return 0
+}
+
+var File_fresh_v1_inbound_order_proto protoreflect.FileDescriptor
+
+var file_fresh_v1_inbound_order_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x66, 0x72, 0x65, 0x73, 0x68, 0x2f, 0x76, 0x31, 0x2f, 0x69, 0x6e, 0x62, 0x6f, 0x75,
+	0x6e, 0x64, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08,
+	0x66, 0x72, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xea, 0x01, 0x0a, 0x0c, 0x49, 0x6e,
+	0x62, 0x6f, 0x75, 0x6e, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x39, 0x0a, 0x0a, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x44, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x64,
+	0x65, 0x72, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6d, 0x70, 0x6c,
+	0x6f, 0x79, 0x65, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65,
+	0x6d, 0x70, 0x6c, 0x6f, 0x79, 0x65, 0x65, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x70, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x61, 0x72, 0x65, 0x68, 0x6f, 0x75, 0x73, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x77, 0x61, 0x72, 0x65, 0x68,
+	0x6f, 0x75, 0x73, 0x65, 0x49, 0x64, 0x22, 0xc2, 0x02, 0x0a, 0x19, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x44, 0x61, 0x74, 0x65, 0x12,
+	0x26, 0x0a, 0x0c, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x4e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x65, 0x6d, 0x70, 0x6c, 0x6f,
+	0x79, 0x65, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x0a,
+	0x65, 0x6d, 0x70, 0x6c, 0x6f, 0x79, 0x65, 0x65, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a,
+	0x10, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x69,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c,
+	0x77, 0x61, 0x72, 0x65, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x03, 0x52, 0x0b, 0x77, 0x61, 0x72, 0x65, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x49,
+	0x64, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x65, 0x6d, 0x70, 0x6c, 0x6f, 0x79,
+	0x65, 0x65, 0x5f, 0x69, 0x64, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x74, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x69, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x77,
+	0x61, 0x72, 0x65, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x32, 0x68, 0x0a, 0x13, 0x49,
+	0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x51, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x62, 0x6f,
+	0x75, 0x6e, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x23, 0x2e, 0x66, 0x72, 0x65, 0x73, 0x68,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e,
+	0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x66, 0x72, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x78, 0x74, 0x6d, 0x61, 0x74, 0x70, 0x65, 0x72, 0x65, 0x7a, 0x2f,
+	0x6d, 0x65, 0x6c, 0x69, 0x5f, 0x62, 0x6f, 0x6f, 0x74, 0x63, 0x61, 0x6d, 0x70, 0x5f, 0x67, 0x6f,
+	0x5f, 0x77, 0x32, 0x2d, 0x31, 0x2f, 0x63, 0x6d, 0x64, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2f, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_fresh_v1_inbound_order_proto_rawDescOnce sync.Once
+	file_fresh_v1_inbound_order_proto_rawDescData = file_fresh_v1_inbound_order_proto_rawDesc
+)
+
+func file_fresh_v1_inbound_order_proto_rawDescGZIP() []byte {
+	file_fresh_v1_inbound_order_proto_rawDescOnce.Do(func() {
+		file_fresh_v1_inbound_order_proto_rawDescData = protoimpl.X.CompressGZIP(file_fresh_v1_inbound_order_proto_rawDescData)
+	})
+	return file_fresh_v1_inbound_order_proto_rawDescData
+}
+
+var file_fresh_v1_inbound_order_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_fresh_v1_inbound_order_proto_goTypes = []interface{}{
+	(*InboundOrder)(nil),              // 0: fresh.v1.InboundOrder
+	(*CreateInboundOrderRequest)(nil), // 1: fresh.v1.CreateInboundOrderRequest
+	(*timestamppb.Timestamp)(nil),     // 2: google.protobuf.Timestamp
+}
+var file_fresh_v1_inbound_order_proto_depIdxs = []int32{
+	2, // 0: fresh.v1.InboundOrder.order_date:type_name -> google.protobuf.Timestamp
+	2, // 1: fresh.v1.CreateInboundOrderRequest.order_date:type_name -> google.protobuf.Timestamp
+	1, // 2: fresh.v1.InboundOrderService.CreateInboundOrder:input_type -> fresh.v1.CreateInboundOrderRequest
+	0, // 3: fresh.v1.InboundOrderService.CreateInboundOrder:output_type -> fresh.v1.InboundOrder
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_fresh_v1_inbound_order_proto_init() }
+func file_fresh_v1_inbound_order_proto_init() {
+	if File_fresh_v1_inbound_order_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_fresh_v1_inbound_order_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InboundOrder); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fresh_v1_inbound_order_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateInboundOrderRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_fresh_v1_inbound_order_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_fresh_v1_inbound_order_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_fresh_v1_inbound_order_proto_goTypes,
+		DependencyIndexes: file_fresh_v1_inbound_order_proto_depIdxs,
+		MessageInfos:      file_fresh_v1_inbound_order_proto_msgTypes,
+	}.Build()
+	File_fresh_v1_inbound_order_proto = out.File
+	file_fresh_v1_inbound_order_proto_rawDesc = nil
+	file_fresh_v1_inbound_order_proto_goTypes = nil
+	file_fresh_v1_inbound_order_proto_depIdxs = nil
+}
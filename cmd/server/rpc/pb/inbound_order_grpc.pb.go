@@ -0,0 +1,109 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: fresh/v1/inbound_order.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	InboundOrderService_CreateInboundOrder_FullMethodName = "/fresh.v1.InboundOrderService/CreateInboundOrder"
+)
+
+// InboundOrderServiceClient is the client API for InboundOrderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InboundOrderServiceClient interface {
+	CreateInboundOrder(ctx context.Context, in *CreateInboundOrderRequest, opts ...grpc.CallOption) (*InboundOrder, error)
+}
+
+type inboundOrderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInboundOrderServiceClient(cc grpc.ClientConnInterface) InboundOrderServiceClient {
+	return &inboundOrderServiceClient{cc}
+}
+
+func (c *inboundOrderServiceClient) CreateInboundOrder(ctx context.Context, in *CreateInboundOrderRequest, opts ...grpc.CallOption) (*InboundOrder, error) {
+	out := new(InboundOrder)
+	err := c.cc.Invoke(ctx, InboundOrderService_CreateInboundOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InboundOrderServiceServer is the server API for InboundOrderService service.
+// All implementations must embed UnimplementedInboundOrderServiceServer
+// for forward compatibility
+type InboundOrderServiceServer interface {
+	CreateInboundOrder(context.Context, *CreateInboundOrderRequest) (*InboundOrder, error)
+	mustEmbedUnimplementedInboundOrderServiceServer()
+}
+
+// UnimplementedInboundOrderServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedInboundOrderServiceServer struct {
+}
+
+func (UnimplementedInboundOrderServiceServer) CreateInboundOrder(context.Context, *CreateInboundOrderRequest) (*InboundOrder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInboundOrder not implemented")
+}
+func (UnimplementedInboundOrderServiceServer) mustEmbedUnimplementedInboundOrderServiceServer() {}
+
+// UnsafeInboundOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InboundOrderServiceServer will
+// result in compilation errors.
+type UnsafeInboundOrderServiceServer interface {
+	mustEmbedUnimplementedInboundOrderServiceServer()
+}
+
+func RegisterInboundOrderServiceServer(s grpc.ServiceRegistrar, srv InboundOrderServiceServer) {
+	s.RegisterService(&InboundOrderService_ServiceDesc, srv)
+}
+
+func _InboundOrderService_CreateInboundOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInboundOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboundOrderServiceServer).CreateInboundOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboundOrderService_CreateInboundOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboundOrderServiceServer).CreateInboundOrder(ctx, req.(*CreateInboundOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InboundOrderService_ServiceDesc is the grpc.ServiceDesc for InboundOrderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InboundOrderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fresh.v1.InboundOrderService",
+	HandlerType: (*InboundOrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateInboundOrder",
+			Handler:    _InboundOrderService_CreateInboundOrder_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "fresh/v1/inbound_order.proto",
+}
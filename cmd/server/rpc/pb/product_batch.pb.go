@@ -0,0 +1,598 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.23.4
+// source: fresh/v1/product_batch.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ProductBatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                 int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BatchNumber        int32                  `protobuf:"varint,2,opt,name=batch_number,json=batchNumber,proto3" json:"batch_number,omitempty"`
+	CurrentQuantity    int32                  `protobuf:"varint,3,opt,name=current_quantity,json=currentQuantity,proto3" json:"current_quantity,omitempty"`
+	CurrentTemperature float32                `protobuf:"fixed32,4,opt,name=current_temperature,json=currentTemperature,proto3" json:"current_temperature,omitempty"`
+	DueDate            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	InitialQuantity    int32                  `protobuf:"varint,6,opt,name=initial_quantity,json=initialQuantity,proto3" json:"initial_quantity,omitempty"`
+	ManufacturingDate  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=manufacturing_date,json=manufacturingDate,proto3" json:"manufacturing_date,omitempty"`
+	ManufacturingHour  int32                  `protobuf:"varint,8,opt,name=manufacturing_hour,json=manufacturingHour,proto3" json:"manufacturing_hour,omitempty"`
+	MinimumTemperature float32                `protobuf:"fixed32,9,opt,name=minimum_temperature,json=minimumTemperature,proto3" json:"minimum_temperature,omitempty"`
+	ProductId          int32                  `protobuf:"varint,10,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	SectionId          int32                  `protobuf:"varint,11,opt,name=section_id,json=sectionId,proto3" json:"section_id,omitempty"`
+	Status             string                 `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *ProductBatch) Reset() {
+	*x = ProductBatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fresh_v1_product_batch_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProductBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductBatch) ProtoMessage() {}
+
+func (x *ProductBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_fresh_v1_product_batch_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductBatch.ProtoReflect.Descriptor instead.
+func (*ProductBatch) Descriptor() ([]byte, []int) {
+	return file_fresh_v1_product_batch_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProductBatch) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProductBatch) GetBatchNumber() int32 {
+	if x != nil {
+		return x.BatchNumber
+	}
+	return 0
+}
+
+func (x *ProductBatch) GetCurrentQuantity() int32 {
+	if x != nil {
+		return x.CurrentQuantity
+	}
+	return 0
+}
+
+func (x *ProductBatch) GetCurrentTemperature() float32 {
+	if x != nil {
+		return x.CurrentTemperature
+	}
+	return 0
+}
+
+func (x *ProductBatch) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+func (x *ProductBatch) GetInitialQuantity() int32 {
+	if x != nil {
+		return x.InitialQuantity
+	}
+	return 0
+}
+
+func (x *ProductBatch) GetManufacturingDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ManufacturingDate
+	}
+	return nil
+}
+
+func (x *ProductBatch) GetManufacturingHour() int32 {
+	if x != nil {
+		return x.ManufacturingHour
+	}
+	return 0
+}
+
+func (x *ProductBatch) GetMinimumTemperature() float32 {
+	if x != nil {
+		return x.MinimumTemperature
+	}
+	return 0
+}
+
+func (x *ProductBatch) GetProductId() int32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ProductBatch) GetSectionId() int32 {
+	if x != nil {
+		return x.SectionId
+	}
+	return 0
+}
+
+func (x *ProductBatch) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// ListProductBatchesRequest lists the batches stored in the sections.
+type ListProductBatchesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SectionIds []int32 `protobuf:"varint,1,rep,packed,name=section_ids,json=sectionIds,proto3" json:"section_ids,omitempty"`
+}
+
+func (x *ListProductBatchesRequest) Reset() {
+	*x = ListProductBatchesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fresh_v1_product_batch_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProductBatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductBatchesRequest) ProtoMessage() {}
+
+func (x *ListProductBatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fresh_v1_product_batch_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductBatchesRequest.ProtoReflect.Descriptor instead.
+func (*ListProductBatchesRequest) Descriptor() ([]byte, []int) {
+	return file_fresh_v1_product_batch_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListProductBatchesRequest) GetSectionIds() []int32 {
+	if x != nil {
+		return x.SectionIds
+	}
+	return nil
+}
+
+type ListProductBatchesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProductBatches []*ProductBatch `protobuf:"bytes,1,rep,name=product_batches,json=productBatches,proto3" json:"product_batches,omitempty"`
+}
+
+func (x *ListProductBatchesResponse) Reset() {
+	*x = ListProductBatchesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fresh_v1_product_batch_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListProductBatchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductBatchesResponse) ProtoMessage() {}
+
+func (x *ListProductBatchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fresh_v1_product_batch_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductBatchesResponse.ProtoReflect.Descriptor instead.
+func (*ListProductBatchesResponse) Descriptor() ([]byte, []int) {
+	return file_fresh_v1_product_batch_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListProductBatchesResponse) GetProductBatches() []*ProductBatch {
+	if x != nil {
+		return x.ProductBatches
+	}
+	return nil
+}
+
+// CreateProductBatchRequest is validated as the body of POST
+// /product-batches, every field being required.
+type CreateProductBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchNumber        *int32                 `protobuf:"varint,1,opt,name=batch_number,json=batchNumber,proto3,oneof" json:"batch_number,omitempty"`
+	CurrentQuantity    *int32                 `protobuf:"varint,2,opt,name=current_quantity,json=currentQuantity,proto3,oneof" json:"current_quantity,omitempty"`
+	CurrentTemperature *float32               `protobuf:"fixed32,3,opt,name=current_temperature,json=currentTemperature,proto3,oneof" json:"current_temperature,omitempty"`
+	DueDate            *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	InitialQuantity    *int32                 `protobuf:"varint,5,opt,name=initial_quantity,json=initialQuantity,proto3,oneof" json:"initial_quantity,omitempty"`
+	ManufacturingDate  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=manufacturing_date,json=manufacturingDate,proto3" json:"manufacturing_date,omitempty"`
+	ManufacturingHour  *int32                 `protobuf:"varint,7,opt,name=manufacturing_hour,json=manufacturingHour,proto3,oneof" json:"manufacturing_hour,omitempty"`
+	MinimumTemperature *float32               `protobuf:"fixed32,8,opt,name=minimum_temperature,json=minimumTemperature,proto3,oneof" json:"minimum_temperature,omitempty"`
+	ProductId          *int32                 `protobuf:"varint,9,opt,name=product_id,json=productId,proto3,oneof" json:"product_id,omitempty"`
+	SectionId          *int32                 `protobuf:"varint,10,opt,name=section_id,json=sectionId,proto3,oneof" json:"section_id,omitempty"`
+}
+
+func (x *CreateProductBatchRequest) Reset() {
+	*x = CreateProductBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_fresh_v1_product_batch_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateProductBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProductBatchRequest) ProtoMessage() {}
+
+func (x *CreateProductBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fresh_v1_product_batch_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProductBatchRequest.ProtoReflect.Descriptor instead.
+func (*CreateProductBatchRequest) Descriptor() ([]byte, []int) {
+	return file_fresh_v1_product_batch_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateProductBatchRequest) GetBatchNumber() int32 {
+	if x != nil && x.BatchNumber != nil {
+		return *x.BatchNumber
+	}
+	return 0
+}
+
+func (x *CreateProductBatchRequest) GetCurrentQuantity() int32 {
+	if x != nil && x.CurrentQuantity != nil {
+		return *x.CurrentQuantity
+	}
+	return 0
+}
+
+func (x *CreateProductBatchRequest) GetCurrentTemperature() float32 {
+	if x != nil && x.CurrentTemperature != nil {
+		return *x.CurrentTemperature
+	}
+	return 0
+}
+
+func (x *CreateProductBatchRequest) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+func (x *CreateProductBatchRequest) GetInitialQuantity() int32 {
+	if x != nil && x.InitialQuantity != nil {
+		return *x.InitialQuantity
+	}
+	return 0
+}
+
+func (x *CreateProductBatchRequest) GetManufacturingDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ManufacturingDate
+	}
+	return nil
+}
+
+func (x *CreateProductBatchRequest) GetManufacturingHour() int32 {
+	if x != nil && x.ManufacturingHour != nil {
+		return *x.ManufacturingHour
+	}
+	return 0
+}
+
+func (x *CreateProductBatchRequest) GetMinimumTemperature() float32 {
+	if x != nil && x.MinimumTemperature != nil {
+		return *x.MinimumTemperature
+	}
+	return 0
+}
+
+func (x *CreateProductBatchRequest) GetProductId() int32 {
+	if x != nil && x.ProductId != nil {
+		return *x.ProductId
+	}
+	return 0
+}
+
+func (x *CreateProductBatchRequest) GetSectionId() int32 {
+	if x != nil && x.SectionId != nil {
+		return *x.SectionId
+	}
+	return 0
+}
+
+var File_fresh_v1_product_batch_proto protoreflect.FileDescriptor
+
+var file_fresh_v1_product_batch_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x66, 0x72, 0x65, 0x73, 0x68, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08,
+	0x66, 0x72, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x80, 0x04, 0x0a, 0x0c, 0x50, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x61,
+	0x74, 0x63, 0x68, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0b, 0x62, 0x61, 0x74, 0x63, 0x68, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x29, 0x0a,
+	0x10, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
+	0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x2f, 0x0a, 0x13, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x02, 0x52, 0x12, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x65,
+	0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x64, 0x75, 0x65,
+	0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x64, 0x75, 0x65, 0x44, 0x61, 0x74, 0x65,
+	0x12, 0x29, 0x0a, 0x10, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x71, 0x75, 0x61, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x6c, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x49, 0x0a, 0x12, 0x6d,
+	0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x11, 0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72, 0x69,
+	0x6e, 0x67, 0x44, 0x61, 0x74, 0x65, 0x12, 0x2d, 0x0a, 0x12, 0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61,
+	0x63, 0x74, 0x75, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x68, 0x6f, 0x75, 0x72, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x11, 0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72, 0x69, 0x6e,
+	0x67, 0x48, 0x6f, 0x75, 0x72, 0x12, 0x2f, 0x0a, 0x13, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d,
+	0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x02, 0x52, 0x12, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x54, 0x65, 0x6d, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x73, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x3c, 0x0a, 0x19,
+	0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x05, 0x52, 0x0a,
+	0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x73, 0x22, 0x5d, 0x0a, 0x1a, 0x4c, 0x69,
+	0x73, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0f, 0x70, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x66, 0x72, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f,
+	0x64, 0x75, 0x63, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x0e, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22, 0xad, 0x05, 0x0a, 0x19, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x0c, 0x62, 0x61, 0x74, 0x63, 0x68,
+	0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52,
+	0x0b, 0x62, 0x61, 0x74, 0x63, 0x68, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12,
+	0x2e, 0x0a, 0x10, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x71, 0x75, 0x61, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x0f, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12,
+	0x34, 0x0a, 0x13, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x48, 0x02, 0x52, 0x12,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a, 0x08, 0x64, 0x75, 0x65, 0x5f, 0x64, 0x61, 0x74,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x07, 0x64, 0x75, 0x65, 0x44, 0x61, 0x74, 0x65, 0x12, 0x2e, 0x0a, 0x10,
+	0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03, 0x52, 0x0f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x6c, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a, 0x12,
+	0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x11, 0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72,
+	0x69, 0x6e, 0x67, 0x44, 0x61, 0x74, 0x65, 0x12, 0x32, 0x0a, 0x12, 0x6d, 0x61, 0x6e, 0x75, 0x66,
+	0x61, 0x63, 0x74, 0x75, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x68, 0x6f, 0x75, 0x72, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x04, 0x52, 0x11, 0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75,
+	0x72, 0x69, 0x6e, 0x67, 0x48, 0x6f, 0x75, 0x72, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x13, 0x6d,
+	0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x02, 0x48, 0x05, 0x52, 0x12, 0x6d, 0x69, 0x6e, 0x69,
+	0x6d, 0x75, 0x6d, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x22, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x05, 0x48, 0x06, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
+	0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x48, 0x07, 0x52, 0x09, 0x73, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x62, 0x61,
+	0x74, 0x63, 0x68, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x42,
+	0x16, 0x0a, 0x14, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x65, 0x6d, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x61, 0x6c, 0x5f, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x42, 0x15, 0x0a, 0x13,
+	0x5f, 0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x68,
+	0x6f, 0x75, 0x72, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x5f,
+	0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f,
+	0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x73,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x32, 0xc9, 0x01, 0x0a, 0x13, 0x50, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x5f, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x23, 0x2e, 0x66, 0x72, 0x65, 0x73, 0x68, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x51, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x23, 0x2e, 0x66, 0x72, 0x65, 0x73, 0x68,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x66, 0x72, 0x65, 0x73, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x78, 0x74, 0x6d, 0x61, 0x74, 0x70, 0x65, 0x72, 0x65, 0x7a, 0x2f,
+	0x6d, 0x65, 0x6c, 0x69, 0x5f, 0x62, 0x6f, 0x6f, 0x74, 0x63, 0x61, 0x6d, 0x70, 0x5f, 0x67, 0x6f,
+	0x5f, 0x77, 0x32, 0x2d, 0x31, 0x2f, 0x63, 0x6d, 0x64, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x2f, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_fresh_v1_product_batch_proto_rawDescOnce sync.Once
+	file_fresh_v1_product_batch_proto_rawDescData = file_fresh_v1_product_batch_proto_rawDesc
+)
+
+func file_fresh_v1_product_batch_proto_rawDescGZIP() []byte {
+	file_fresh_v1_product_batch_proto_rawDescOnce.Do(func() {
+		file_fresh_v1_product_batch_proto_rawDescData = protoimpl.X.CompressGZIP(file_fresh_v1_product_batch_proto_rawDescData)
+	})
+	return file_fresh_v1_product_batch_proto_rawDescData
+}
+
+var file_fresh_v1_product_batch_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_fresh_v1_product_batch_proto_goTypes = []interface{}{
+	(*ProductBatch)(nil),               // 0: fresh.v1.ProductBatch
+	(*ListProductBatchesRequest)(nil),  // 1: fresh.v1.ListProductBatchesRequest
+	(*ListProductBatchesResponse)(nil), // 2: fresh.v1.ListProductBatchesResponse
+	(*CreateProductBatchRequest)(nil),  // 3: fresh.v1.CreateProductBatchRequest
+	(*timestamppb.Timestamp)(nil),      // 4: google.protobuf.Timestamp
+}
+var file_fresh_v1_product_batch_proto_depIdxs = []int32{
+	4, // 0: fresh.v1.ProductBatch.due_date:type_name -> google.protobuf.Timestamp
+	4, // 1: fresh.v1.ProductBatch.manufacturing_date:type_name -> google.protobuf.Timestamp
+	0, // 2: fresh.v1.ListProductBatchesResponse.product_batches:type_name -> fresh.v1.ProductBatch
+	4, // 3: fresh.v1.CreateProductBatchRequest.due_date:type_name -> google.protobuf.Timestamp
+	4, // 4: fresh.v1.CreateProductBatchRequest.manufacturing_date:type_name -> google.protobuf.Timestamp
+	1, // 5: fresh.v1.ProductBatchService.ListProductBatches:input_type -> fresh.v1.ListProductBatchesRequest
+	3, // 6: fresh.v1.ProductBatchService.CreateProductBatch:input_type -> fresh.v1.CreateProductBatchRequest
+	2, // 7: fresh.v1.ProductBatchService.ListProductBatches:output_type -> fresh.v1.ListProductBatchesResponse
+	0, // 8: fresh.v1.ProductBatchService.CreateProductBatch:output_type -> fresh.v1.ProductBatch
+	7, // [7:9] is the sub-list for method output_type
+	5, // [5:7] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_fresh_v1_product_batch_proto_init() }
+func file_fresh_v1_product_batch_proto_init() {
+	if File_fresh_v1_product_batch_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_fresh_v1_product_batch_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProductBatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fresh_v1_product_batch_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProductBatchesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fresh_v1_product_batch_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListProductBatchesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_fresh_v1_product_batch_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateProductBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_fresh_v1_product_batch_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_fresh_v1_product_batch_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_fresh_v1_product_batch_proto_goTypes,
+		DependencyIndexes: file_fresh_v1_product_batch_proto_depIdxs,
+		MessageInfos:      file_fresh_v1_product_batch_proto_msgTypes,
+	}.Build()
+	File_fresh_v1_product_batch_proto = out.File
+	file_fresh_v1_product_batch_proto_rawDesc = nil
+	file_fresh_v1_product_batch_proto_goTypes = nil
+	file_fresh_v1_product_batch_proto_depIdxs = nil
+}
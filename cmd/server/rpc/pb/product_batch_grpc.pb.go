@@ -0,0 +1,146 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: fresh/v1/product_batch.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ProductBatchService_ListProductBatches_FullMethodName = "/fresh.v1.ProductBatchService/ListProductBatches"
+	ProductBatchService_CreateProductBatch_FullMethodName = "/fresh.v1.ProductBatchService/CreateProductBatch"
+)
+
+// ProductBatchServiceClient is the client API for ProductBatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ProductBatchServiceClient interface {
+	ListProductBatches(ctx context.Context, in *ListProductBatchesRequest, opts ...grpc.CallOption) (*ListProductBatchesResponse, error)
+	CreateProductBatch(ctx context.Context, in *CreateProductBatchRequest, opts ...grpc.CallOption) (*ProductBatch, error)
+}
+
+type productBatchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProductBatchServiceClient(cc grpc.ClientConnInterface) ProductBatchServiceClient {
+	return &productBatchServiceClient{cc}
+}
+
+func (c *productBatchServiceClient) ListProductBatches(ctx context.Context, in *ListProductBatchesRequest, opts ...grpc.CallOption) (*ListProductBatchesResponse, error) {
+	out := new(ListProductBatchesResponse)
+	err := c.cc.Invoke(ctx, ProductBatchService_ListProductBatches_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productBatchServiceClient) CreateProductBatch(ctx context.Context, in *CreateProductBatchRequest, opts ...grpc.CallOption) (*ProductBatch, error) {
+	out := new(ProductBatch)
+	err := c.cc.Invoke(ctx, ProductBatchService_CreateProductBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProductBatchServiceServer is the server API for ProductBatchService service.
+// All implementations must embed UnimplementedProductBatchServiceServer
+// for forward compatibility
+type ProductBatchServiceServer interface {
+	ListProductBatches(context.Context, *ListProductBatchesRequest) (*ListProductBatchesResponse, error)
+	CreateProductBatch(context.Context, *CreateProductBatchRequest) (*ProductBatch, error)
+	mustEmbedUnimplementedProductBatchServiceServer()
+}
+
+// UnimplementedProductBatchServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedProductBatchServiceServer struct {
+}
+
+func (UnimplementedProductBatchServiceServer) ListProductBatches(context.Context, *ListProductBatchesRequest) (*ListProductBatchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProductBatches not implemented")
+}
+func (UnimplementedProductBatchServiceServer) CreateProductBatch(context.Context, *CreateProductBatchRequest) (*ProductBatch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProductBatch not implemented")
+}
+func (UnimplementedProductBatchServiceServer) mustEmbedUnimplementedProductBatchServiceServer() {}
+
+// UnsafeProductBatchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProductBatchServiceServer will
+// result in compilation errors.
+type UnsafeProductBatchServiceServer interface {
+	mustEmbedUnimplementedProductBatchServiceServer()
+}
+
+func RegisterProductBatchServiceServer(s grpc.ServiceRegistrar, srv ProductBatchServiceServer) {
+	s.RegisterService(&ProductBatchService_ServiceDesc, srv)
+}
+
+func _ProductBatchService_ListProductBatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductBatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductBatchServiceServer).ListProductBatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductBatchService_ListProductBatches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductBatchServiceServer).ListProductBatches(ctx, req.(*ListProductBatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductBatchService_CreateProductBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProductBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductBatchServiceServer).CreateProductBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductBatchService_CreateProductBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductBatchServiceServer).CreateProductBatch(ctx, req.(*CreateProductBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ProductBatchService_ServiceDesc is the grpc.ServiceDesc for ProductBatchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProductBatchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fresh.v1.ProductBatchService",
+	HandlerType: (*ProductBatchServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListProductBatches",
+			Handler:    _ProductBatchService_ListProductBatches_Handler,
+		},
+		{
+			MethodName: "CreateProductBatch",
+			Handler:    _ProductBatchService_CreateProductBatch_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "fresh/v1/product_batch.proto",
+}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/rpc/pb"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type Product struct {
+	pb.UnimplementedProductServiceServer
+	service product.Service
+}
+
+func NewProduct(service product.Service) *Product {
+	return &Product{service: service}
+}
+
+func (p *Product) ListProducts(ctx context.Context, request *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	products := p.service.GetAll()
+
+	response := &pb.ListProductsResponse{Products: make([]*pb.Product, 0, len(products))}
+	for _, product := range products {
+		response.Products = append(response.Products, toProductMessage(product))
+	}
+	return response, nil
+}
+
+func (p *Product) GetProduct(ctx context.Context, request *pb.GetProductRequest) (*pb.Product, error) {
+	product, err := p.service.Get(int(request.Id))
+	if err != nil {
+		return nil, statusFrom(ctx, err)
+	}
+
+	return toProductMessage(*product), nil
+}
+
+func (p *Product) CreateProduct(ctx context.Context, request *pb.CreateProductRequest) (*pb.Product, error) {
+	create := handler.CreateProductRequest{
+		Description:    request.Description,
+		ExpirationRate: request.ExpirationRate,
+		FreezingRate:   request.FreezingRate,
+		Height:         request.Height,
+		Length:         request.Length,
+		Netweight:      request.Netweight,
+		ProductCode:    request.ProductCode,
+		RecomFreezTemp: request.RecommendedFreezingTemperature,
+		Width:          request.Width,
+		ProductTypeID:  optionalInt(request.ProductTypeId),
+		SellerID:       optionalInt(request.SellerId),
+	}
+	if err := validate(ctx, create); err != nil {
+		return nil, err
+	}
+
+	created, err := p.service.Create(create.ToProduct())
+	if err != nil {
+		return nil, statusFrom(ctx, err)
+	}
+
+	return toProductMessage(*created), nil
+}
+
+func (p *Product) DeleteProduct(ctx context.Context, request *pb.DeleteProductRequest) (*emptypb.Empty, error) {
+	if err := p.service.Delete(int(request.Id)); err != nil {
+		return nil, statusFrom(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func toProductMessage(p domain.Product) *pb.Product {
+	return &pb.Product{
+		Id:                             int32(p.ID),
+		Description:                    p.Description,
+		ExpirationRate:                 p.ExpirationRate,
+		FreezingRate:                   p.FreezingRate,
+		Height:                         p.Height,
+		Length:                         p.Length,
+		Netweight:                      p.Netweight,
+		ProductCode:                    p.ProductCode,
+		RecommendedFreezingTemperature: p.RecomFreezTemp,
+		Width:                          p.Width,
+		ProductTypeId:                  int32(p.ProductTypeID),
+		SellerId:                       int32(p.SellerID),
+	}
+}
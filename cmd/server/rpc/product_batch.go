@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/rpc/pb"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
+)
+
+type ProductBatch struct {
+	pb.UnimplementedProductBatchServiceServer
+	service product_batch.Service
+}
+
+func NewProductBatch(service product_batch.Service) *ProductBatch {
+	return &ProductBatch{service: service}
+}
+
+func (b *ProductBatch) ListProductBatches(ctx context.Context, request *pb.ListProductBatchesRequest) (*pb.ListProductBatchesResponse, error) {
+	batches := b.service.GetBySections(ints(request.SectionIds))
+
+	response := &pb.ListProductBatchesResponse{ProductBatches: make([]*pb.ProductBatch, 0, len(batches))}
+	for _, batch := range batches {
+		response.ProductBatches = append(response.ProductBatches, toProductBatchMessage(batch))
+	}
+	return response, nil
+}
+
+func (b *ProductBatch) CreateProductBatch(ctx context.Context, request *pb.CreateProductBatchRequest) (*pb.ProductBatch, error) {
+	create := handler.CreateProductBatchRequest{
+		BatchNumber:        optionalInt(request.BatchNumber),
+		CurrentQuantity:    optionalInt(request.CurrentQuantity),
+		CurrentTemperature: request.CurrentTemperature,
+		DueDate:            optionalDateTime(request.DueDate),
+		InitialQuantity:    optionalInt(request.InitialQuantity),
+		ManufacturingDate:  optionalDateTime(request.ManufacturingDate),
+		ManufacturingHour:  optionalInt(request.ManufacturingHour),
+		MinimumTemperature: request.MinimumTemperature,
+		ProductID:          optionalInt(request.ProductId),
+		SectionID:          optionalInt(request.SectionId),
+	}
+	if err := validate(ctx, create); err != nil {
+		return nil, err
+	}
+
+	created, err := b.service.Create(create.ToProductBatches())
+	if err != nil {
+		return nil, statusFrom(ctx, err)
+	}
+
+	return toProductBatchMessage(*created), nil
+}
+
+func toProductBatchMessage(batch domain.ProductBatch) *pb.ProductBatch {
+	return &pb.ProductBatch{
+		Id:                 int32(batch.ID),
+		BatchNumber:        int32(batch.BatchNumber),
+		CurrentQuantity:    int32(batch.CurrentQuantity),
+		CurrentTemperature: batch.CurrentTemperature,
+		DueDate:            timestamp(batch.DueDate),
+		InitialQuantity:    int32(batch.InitialQuantity),
+		ManufacturingDate:  timestamp(batch.ManufacturingDate),
+		ManufacturingHour:  int32(batch.ManufacturingHour),
+		MinimumTemperature: batch.MinimumTemperature,
+		ProductId:          int32(batch.ProductID),
+		SectionId:          int32(batch.SectionID),
+		Status:             batch.Status,
+	}
+}
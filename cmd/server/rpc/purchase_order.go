@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/rpc/pb"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/purchase_order"
+)
+
+type PurchaseOrder struct {
+	pb.UnimplementedPurchaseOrderServiceServer
+	service purchase_order.Service
+}
+
+func NewPurchaseOrder(service purchase_order.Service) *PurchaseOrder {
+	return &PurchaseOrder{service: service}
+}
+
+func (p *PurchaseOrder) CreatePurchaseOrder(ctx context.Context, request *pb.CreatePurchaseOrderRequest) (*pb.PurchaseOrder, error) {
+	create := handler.CreatePurchaseOrderRequest{
+		OrderNumber:     request.OrderNumber,
+		OrderDate:       optionalDateTime(request.OrderDate),
+		TrackingCode:    request.TrackingCode,
+		BuyerID:         optionalInt(request.BuyerId),
+		CarrierID:       optionalInt(request.CarrierId),
+		ProductRecordID: optionalInt(request.ProductRecordId),
+		OrderStatusID:   optionalInt(request.OrderStatusId),
+		WarehouseID:     optionalInt(request.WarehouseId),
+		Quantity:        optionalInt(request.Quantity),
+	}
+	if err := validate(ctx, create); err != nil {
+		return nil, err
+	}
+
+	created, err := p.service.Create(create.ToPurchaseOrder())
+	if err != nil {
+		return nil, statusFrom(ctx, err)
+	}
+
+	return toPurchaseOrderMessage(*created), nil
+}
+
+func (p *PurchaseOrder) CancelPurchaseOrder(ctx context.Context, request *pb.CancelPurchaseOrderRequest) (*pb.PurchaseOrder, error) {
+	cancelled, err := p.service.Cancel(int(request.Id))
+	if err != nil {
+		return nil, statusFrom(ctx, err)
+	}
+
+	return toPurchaseOrderMessage(*cancelled), nil
+}
+
+func toPurchaseOrderMessage(po domain.PurchaseOrder) *pb.PurchaseOrder {
+	return &pb.PurchaseOrder{
+		Id:              int32(po.ID),
+		OrderNumber:     po.OrderNumber,
+		OrderDate:       timestamp(po.OrderDate),
+		TrackingCode:    po.TrackingCode,
+		BuyerId:         int32(po.BuyerID),
+		CarrierId:       int32(po.CarrierID),
+		ProductRecordId: int32(po.ProductRecordID),
+		OrderStatusId:   int32(po.OrderStatusID),
+		WarehouseId:     int32(po.WarehouseID),
+		Quantity:        int32(po.Quantity),
+	}
+}
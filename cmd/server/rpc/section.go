@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/handler"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/rpc/pb"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type Section struct {
+	pb.UnimplementedSectionServiceServer
+	service section.Service
+}
+
+func NewSection(service section.Service) *Section {
+	return &Section{service: service}
+}
+
+func (s *Section) ListSections(ctx context.Context, request *pb.ListSectionsRequest) (*pb.ListSectionsResponse, error) {
+	sections := s.service.GetAll()
+
+	response := &pb.ListSectionsResponse{Sections: make([]*pb.Section, 0, len(sections))}
+	for _, section := range sections {
+		response.Sections = append(response.Sections, toSectionMessage(section))
+	}
+	return response, nil
+}
+
+func (s *Section) GetSection(ctx context.Context, request *pb.GetSectionRequest) (*pb.Section, error) {
+	section, err := s.service.Get(int(request.Id))
+	if err != nil {
+		return nil, statusFrom(ctx, err)
+	}
+
+	return toSectionMessage(*section), nil
+}
+
+func (s *Section) CreateSection(ctx context.Context, request *pb.CreateSectionRequest) (*pb.Section, error) {
+	create := handler.CreateSectionRequest{
+		SectionNumber:      optionalInt(request.SectionNumber),
+		CurrentTemperature: request.CurrentTemperature,
+		MinimumTemperature: request.MinimumTemperature,
+		MinimumCapacity:    optionalInt(request.MinimumCapacity),
+		MaximumCapacity:    optionalInt(request.MaximumCapacity),
+		WarehouseID:        optionalInt(request.WarehouseId),
+		ProductTypeID:      optionalInt(request.ProductTypeId),
+	}
+	if err := validate(ctx, create); err != nil {
+		return nil, err
+	}
+
+	created, err := s.service.Create(create.ToSection())
+	if err != nil {
+		return nil, statusFrom(ctx, err)
+	}
+
+	return toSectionMessage(*created), nil
+}
+
+func (s *Section) DeleteSection(ctx context.Context, request *pb.DeleteSectionRequest) (*emptypb.Empty, error) {
+	if err := s.service.Delete(int(request.Id)); err != nil {
+		return nil, statusFrom(ctx, err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func toSectionMessage(sc domain.Section) *pb.Section {
+	return &pb.Section{
+		Id:                 int32(sc.ID),
+		SectionNumber:      int32(sc.SectionNumber),
+		CurrentTemperature: sc.CurrentTemperature,
+		MinimumTemperature: sc.MinimumTemperature,
+		CurrentCapacity:    int32(sc.CurrentCapacity),
+		MinimumCapacity:    int32(sc.MinimumCapacity),
+		MaximumCapacity:    int32(sc.MaximumCapacity),
+		WarehouseId:        int32(sc.WarehouseID),
+		ProductTypeId:      int32(sc.ProductTypeID),
+	}
+}
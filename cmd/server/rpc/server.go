@@ -0,0 +1,38 @@
+// Package rpc serves the gRPC API, a second transport over the services of
+// the REST one. The messages and services are defined in proto/fresh/v1.
+package rpc
+
+//go:generate protoc -I ../../../proto --go_out=../../.. --go_opt=module=github.com/extmatperez/meli_bootcamp_go_w2-1 --go-grpc_out=../../.. --go-grpc_opt=module=github.com/extmatperez/meli_bootcamp_go_w2-1 fresh/v1/product.proto fresh/v1/section.proto fresh/v1/product_batch.proto fresh/v1/inbound_order.proto fresh/v1/purchase_order.proto
+
+import (
+	"context"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/i18n"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	InternalError = "internal_error"
+)
+
+// NewServer creates the gRPC server the services of the API are registered
+// in.
+func NewServer(options ...grpc.ServerOption) *grpc.Server {
+	return grpc.NewServer(append(options, grpc.ChainUnaryInterceptor(Recovery()))...)
+}
+
+// Recovery turns the panics of the services, raised when the database fails,
+// into an Internal status, as the InternalError middleware does for REST.
+func Recovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				resp, err = nil, status.Error(codes.Internal, i18n.Translate(localeFrom(ctx), InternalError))
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,292 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/rpc"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/cmd/server/rpc/pb"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	inbound_order_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/inbound_order/mocks"
+	product_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product/mocks"
+	product_batch_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch/mocks"
+	purchase_order_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/purchase_order/mocks"
+	section_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type serviceMocks struct {
+	products       *product_mocks.Service
+	sections       *section_mocks.Service
+	batches        *product_batch_mocks.Service
+	inboundOrders  *inbound_order_mocks.Service
+	purchaseOrders *purchase_order_mocks.Service
+}
+
+type clients struct {
+	products       pb.ProductServiceClient
+	sections       pb.SectionServiceClient
+	batches        pb.ProductBatchServiceClient
+	inboundOrders  pb.InboundOrderServiceClient
+	purchaseOrders pb.PurchaseOrderServiceClient
+}
+
+// InitRPCServer serves the gRPC API over the mocks through an in-memory
+// listener, and returns the clients connected to it.
+func InitRPCServer(t *testing.T) (clients, serviceMocks) {
+	t.Helper()
+	m := serviceMocks{
+		products:       new(product_mocks.Service),
+		sections:       new(section_mocks.Service),
+		batches:        new(product_batch_mocks.Service),
+		inboundOrders:  new(inbound_order_mocks.Service),
+		purchaseOrders: new(purchase_order_mocks.Service),
+	}
+
+	server := rpc.NewServer()
+	pb.RegisterProductServiceServer(server, rpc.NewProduct(m.products))
+	pb.RegisterSectionServiceServer(server, rpc.NewSection(m.sections))
+	pb.RegisterProductBatchServiceServer(server, rpc.NewProductBatch(m.batches))
+	pb.RegisterInboundOrderServiceServer(server, rpc.NewInboundOrder(m.inboundOrders))
+	pb.RegisterPurchaseOrderServiceServer(server, rpc.NewPurchaseOrder(m.purchaseOrders))
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return clients{
+		products:       pb.NewProductServiceClient(conn),
+		sections:       pb.NewSectionServiceClient(conn),
+		batches:        pb.NewProductBatchServiceClient(conn),
+		inboundOrders:  pb.NewInboundOrderServiceClient(conn),
+		purchaseOrders: pb.NewPurchaseOrderServiceClient(conn),
+	}, m
+}
+
+var (
+	mockedRPCProduct = domain.Product{
+		ID:             1,
+		Description:    "Yogurt",
+		ExpirationRate: 1,
+		FreezingRate:   2,
+		Height:         6.4,
+		Length:         4.5,
+		Netweight:      3.4,
+		ProductCode:    "PROD01",
+		RecomFreezTemp: 1.3,
+		Width:          1.2,
+		ProductTypeID:  1,
+		SellerID:       1,
+	}
+	createRPCProductRequest = &pb.CreateProductRequest{
+		Description:                    proto.String("Yogurt"),
+		ExpirationRate:                 proto.Float32(1),
+		FreezingRate:                   proto.Float32(2),
+		Height:                         proto.Float32(6.4),
+		Length:                         proto.Float32(4.5),
+		Netweight:                      proto.Float32(3.4),
+		ProductCode:                    proto.String("PROD01"),
+		RecommendedFreezingTemperature: proto.Float32(1.3),
+		Width:                          proto.Float32(1.2),
+		ProductTypeId:                  proto.Int32(1),
+		SellerId:                       proto.Int32(1),
+	}
+)
+
+func TestProductService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should return the product", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		m.products.On("Get", 1).Return(&mockedRPCProduct, nil)
+
+		product, err := client.products.GetProduct(ctx, &pb.GetProductRequest{Id: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PROD01", product.ProductCode)
+		assert.Equal(t, int32(1), product.SellerId)
+	})
+	t.Run("Should return not found when the product does not exist", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		m.products.On("Get", 9).Return((*domain.Product)(nil), apperr.NewResourceNotFound("product.not_found", 9))
+
+		_, err := client.products.GetProduct(ctx, &pb.GetProductRequest{Id: 9})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+	t.Run("Should create the product", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		expected := mockedRPCProduct
+		expected.ID = 0
+		m.products.On("Create", expected).Return(&mockedRPCProduct, nil)
+
+		product, err := client.products.CreateProduct(ctx, createRPCProductRequest)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), product.Id)
+	})
+	t.Run("Should return already exists when the product code is taken", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		expected := mockedRPCProduct
+		expected.ID = 0
+		m.products.On("Create", expected).Return((*domain.Product)(nil), apperr.NewResourceAlreadyExists("product.already_exists", "PROD01"))
+
+		_, err := client.products.CreateProduct(ctx, createRPCProductRequest)
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+	t.Run("Should return the fields that break the rules of the REST API", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		request := proto.Clone(createRPCProductRequest).(*pb.CreateProductRequest)
+		request.SellerId = nil
+		request.ProductCode = proto.String("P1")
+
+		_, err := client.products.CreateProduct(ctx, request)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		details := status.Convert(err).Details()
+		assert.Len(t, details, 1)
+		fields := make([]string, 0)
+		for _, violation := range details[0].(*errdetails.BadRequest).FieldViolations {
+			fields = append(fields, violation.Field)
+		}
+		assert.ElementsMatch(t, []string{"product_code", "seller_id"}, fields)
+		m.products.AssertNotCalled(t, "Create")
+	})
+	t.Run("Should return internal when the service fails, in the language of the client", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		m.products.On("GetAll").Panic("connection refused")
+
+		ctx := metadata.AppendToOutgoingContext(ctx, rpc.LanguageMetadata, "en")
+		_, err := client.products.ListProducts(ctx, &pb.ListProductsRequest{})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.NotContains(t, status.Convert(err).Message(), "connection refused")
+		assert.Equal(t, "an internal error occurred", status.Convert(err).Message())
+	})
+}
+
+func TestSectionService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should return failed precondition when the warehouse does not exist", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		m.sections.On("Create", domain.Section{SectionNumber: 1, MaximumCapacity: 10, MinimumCapacity: 1, WarehouseID: 9, ProductTypeID: 1}).
+			Return((*domain.Section)(nil), apperr.NewDependentResourceNotFound("section.warehouse_not_found", 9))
+
+		_, err := client.sections.CreateSection(ctx, &pb.CreateSectionRequest{
+			SectionNumber:      proto.Int32(1),
+			CurrentTemperature: proto.Float32(0),
+			MinimumTemperature: proto.Float32(0),
+			MinimumCapacity:    proto.Int32(1),
+			MaximumCapacity:    proto.Int32(10),
+			WarehouseId:        proto.Int32(9),
+			ProductTypeId:      proto.Int32(1),
+		})
+
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+	t.Run("Should delete the section", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		m.sections.On("Delete", 1).Return(nil)
+
+		_, err := client.sections.DeleteSection(ctx, &pb.DeleteSectionRequest{Id: 1})
+
+		assert.NoError(t, err)
+		m.sections.AssertExpectations(t)
+	})
+}
+
+func TestProductBatchService(t *testing.T) {
+	ctx := context.Background()
+	due := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+	manufactured := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Should list the batches of the sections", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		m.batches.On("GetBySections", []int{1, 2}).Return([]domain.ProductBatch{{ID: 3, SectionID: 1, DueDate: due, Status: domain.BatchAvailable}})
+
+		response, err := client.batches.ListProductBatches(ctx, &pb.ListProductBatchesRequest{SectionIds: []int32{1, 2}})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.ProductBatches, 1)
+		assert.Equal(t, due, response.ProductBatches[0].DueDate.AsTime())
+	})
+	t.Run("Should create the batch with the dates of the request", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		expected := domain.ProductBatch{BatchNumber: 1, CurrentQuantity: 5, InitialQuantity: 5, DueDate: due, ManufacturingDate: manufactured, ManufacturingHour: 10, ProductID: 1, SectionID: 1}
+		created := expected
+		created.ID = 7
+		m.batches.On("Create", expected).Return(&created, nil)
+
+		batch, err := client.batches.CreateProductBatch(ctx, &pb.CreateProductBatchRequest{
+			BatchNumber:        proto.Int32(1),
+			CurrentQuantity:    proto.Int32(5),
+			CurrentTemperature: proto.Float32(0),
+			DueDate:            timestamppb.New(due),
+			InitialQuantity:    proto.Int32(5),
+			ManufacturingDate:  timestamppb.New(manufactured),
+			ManufacturingHour:  proto.Int32(10),
+			MinimumTemperature: proto.Float32(0),
+			ProductId:          proto.Int32(1),
+			SectionId:          proto.Int32(1),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(7), batch.Id)
+	})
+}
+
+func TestInboundOrderService(t *testing.T) {
+	t.Run("Should return invalid argument when the order date is missing", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+
+		_, err := client.inboundOrders.CreateInboundOrder(context.Background(), &pb.CreateInboundOrderRequest{
+			OrderNumber:    proto.String("ORD-1"),
+			EmployeeId:     proto.Int32(1),
+			ProductBatchId: proto.Int32(1),
+			WarehouseId:    proto.Int32(1),
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		m.inboundOrders.AssertNotCalled(t, "Create")
+	})
+}
+
+func TestPurchaseOrderService(t *testing.T) {
+	t.Run("Should return failed precondition when the order is already cancelled", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		m.purchaseOrders.On("Cancel", 1).Return((*domain.PurchaseOrder)(nil), apperr.NewIncompatibleResource("purchase_order.already_cancelled", 1))
+
+		_, err := client.purchaseOrders.CancelPurchaseOrder(context.Background(), &pb.CancelPurchaseOrderRequest{Id: 1})
+
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+	t.Run("Should return not found when the order does not exist", func(t *testing.T) {
+		client, m := InitRPCServer(t)
+		m.purchaseOrders.On("Cancel", 2).Return((*domain.PurchaseOrder)(nil), apperr.NewResourceNotFound("purchase_order.not_found", 2))
+
+		_, err := client.purchaseOrders.CancelPurchaseOrder(context.Background(), &pb.CancelPurchaseOrderRequest{Id: 2})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
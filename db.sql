@@ -10,7 +10,7 @@ CREATE TABLE products(
   lenght FLOAT NOT NULL, netweight FLOAT NOT NULL, 
   product_code TEXT NOT NULL, recommended_freezing_temperature FLOAT NOT NULL, 
   width FLOAT NOT NULL, id_product_type INT NOT NULL, 
-  id_seller INT NOT NULL
+  id_seller INT NOT NULL, version INT NOT NULL DEFAULT 0
 );
 
 DROP 
@@ -18,7 +18,9 @@ DROP
 CREATE TABLE employees(
   `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
   card_number_id TEXT NOT NULL, first_name TEXT NOT NULL, 
-  last_name TEXT NOT NULL, warehouse_id INT NOT NULL
+  last_name TEXT NOT NULL, warehouse_id INT NOT NULL, 
+  is_supervisor BOOLEAN NOT NULL DEFAULT FALSE, 
+  version INT NOT NULL DEFAULT 0
 );
 
 DROP 
@@ -56,6 +58,7 @@ CREATE TABLE warehouses(
   minimum_capacity INT NULL,
   minimum_temperature INT NULL,
   locality_id INT NOT NULL,
+  version INT NOT NULL DEFAULT 0,
   FOREIGN KEY(locality_id) REFERENCES `melisprint`.`localities` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
 
@@ -67,7 +70,8 @@ CREATE TABLE sections(
   minimum_temperature INT NOT NULL, 
   current_capacity INT NOT NULL, minimum_capacity INT NOT NULL, 
   maximum_capacity INT NOT NULL, warehouse_id INT NOT NULL, 
-  id_product_type INT NOT NULL
+  id_product_type INT NOT NULL, last_temperature DECIMAL(19, 2) NULL, 
+  version INT NOT NULL DEFAULT 0
 );
 
 DROP 
@@ -79,6 +83,7 @@ CREATE TABLE sellers(
   `address` TEXT NOT NULL, 
   telephone TEXT(15) NOT NULL,
   `locality_id` INT NOT NULL, 
+  version INT NOT NULL DEFAULT 0,
   FOREIGN KEY (`locality_id`) REFERENCES `melisprint`.`localities` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
 
@@ -87,7 +92,7 @@ DROP
 CREATE TABLE buyers(
   `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
   card_number_id TEXT NOT NULL, first_name TEXT NOT NULL, 
-  last_name TEXT NOT NULL
+  last_name TEXT NOT NULL, version INT NOT NULL DEFAULT 0
 );
 
 DROP 
@@ -121,7 +126,7 @@ DROP
   TABLE IF EXISTS product_batches;
 CREATE TABLE product_batches(
   `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
-  `batch_number` INT NOT NULL, 
+  `batch_number` INT NOT NULL UNIQUE, 
   `initial_quantity` INT NOT NULL, 
   `current_quantity` INT NOT NULL, 
   `current_temperature` DECIMAL(19, 2) NOT NULL, 
@@ -131,10 +136,98 @@ CREATE TABLE product_batches(
   `minimum_temperature` DECIMAL(19, 2) NOT NULL, 
   `product_id` INT NOT NULL, 
   `section_id` INT NOT NULL, 
+  `status` VARCHAR(16) NOT NULL DEFAULT 'available', 
+  `origin_batch_id` INT NULL, 
   FOREIGN KEY(product_id) REFERENCES products(id) ON DELETE NO ACTION ON UPDATE NO ACTION, 
   FOREIGN KEY(section_id) REFERENCES sections(id) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
 
+DROP 
+  TABLE IF EXISTS batch_status_changes;
+CREATE TABLE batch_status_changes(
+  `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
+  `product_batch_id` INT NOT NULL, 
+  `from_status` VARCHAR(16) NOT NULL, 
+  `to_status` VARCHAR(16) NOT NULL, 
+  `reason` VARCHAR(255) NOT NULL, 
+  `employee_id` INT NOT NULL, 
+  `created_at` DATETIME NOT NULL, 
+  INDEX `batch_status_changes_batch_idx` (`product_batch_id` ASC), 
+  FOREIGN KEY(product_batch_id) REFERENCES product_batches(id) ON DELETE CASCADE ON UPDATE NO ACTION, 
+  FOREIGN KEY(employee_id) REFERENCES employees(id) ON DELETE NO ACTION ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS cycle_counts;
+CREATE TABLE cycle_counts(
+  `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
+  `section_id` INT NOT NULL, 
+  `employee_id` INT NULL, 
+  `status` VARCHAR(16) NOT NULL, 
+  `reviewed_by` INT NULL, 
+  `created_at` DATETIME NOT NULL, 
+  `submitted_at` DATETIME NULL, 
+  `reviewed_at` DATETIME NULL, 
+  FOREIGN KEY(section_id) REFERENCES sections(id) ON DELETE CASCADE ON UPDATE NO ACTION, 
+  FOREIGN KEY(employee_id) REFERENCES employees(id) ON DELETE NO ACTION ON UPDATE NO ACTION, 
+  FOREIGN KEY(reviewed_by) REFERENCES employees(id) ON DELETE NO ACTION ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS cycle_count_lines;
+CREATE TABLE cycle_count_lines(
+  `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
+  `cycle_count_id` INT NOT NULL, 
+  `product_batch_id` INT NOT NULL, 
+  `expected_quantity` INT NOT NULL, 
+  `counted_quantity` INT NULL, 
+  UNIQUE INDEX `cycle_count_lines_batch_idx` (`cycle_count_id` ASC, `product_batch_id` ASC), 
+  FOREIGN KEY(cycle_count_id) REFERENCES cycle_counts(id) ON DELETE CASCADE ON UPDATE NO ACTION, 
+  FOREIGN KEY(product_batch_id) REFERENCES product_batches(id) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS recalls;
+CREATE TABLE recalls(
+  `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
+  `reason` VARCHAR(255) NOT NULL, 
+  `employee_id` INT NOT NULL, 
+  `created_at` DATETIME NOT NULL, 
+  FOREIGN KEY(employee_id) REFERENCES employees(id) ON DELETE NO ACTION ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS recall_batches;
+CREATE TABLE recall_batches(
+  `recall_id` INT NOT NULL, 
+  `product_batch_id` INT NOT NULL, 
+  PRIMARY KEY (`recall_id`, `product_batch_id`), 
+  FOREIGN KEY(recall_id) REFERENCES recalls(id) ON DELETE CASCADE ON UPDATE NO ACTION, 
+  FOREIGN KEY(product_batch_id) REFERENCES product_batches(id) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS stock_movements;
+CREATE TABLE stock_movements(
+  `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
+  `product_batch_id` INT NOT NULL, 
+  `movement_type` VARCHAR(20) NOT NULL, 
+  `from_section_id` INT NULL, 
+  `to_section_id` INT NULL, 
+  `quantity` INT NOT NULL, 
+  `employee_id` INT NULL, 
+  `target_batch_id` INT NULL, 
+  `purchase_order_id` INT NULL, 
+  `created_at` DATETIME NOT NULL, 
+  INDEX `stock_movements_batch_idx` (`product_batch_id` ASC), 
+  INDEX `stock_movements_order_idx` (`purchase_order_id` ASC), 
+  FOREIGN KEY(product_batch_id) REFERENCES product_batches(id) ON DELETE CASCADE ON UPDATE NO ACTION, 
+  FOREIGN KEY(from_section_id) REFERENCES sections(id) ON DELETE NO ACTION ON UPDATE NO ACTION, 
+  FOREIGN KEY(to_section_id) REFERENCES sections(id) ON DELETE NO ACTION ON UPDATE NO ACTION, 
+  FOREIGN KEY(employee_id) REFERENCES employees(id) ON DELETE NO ACTION ON UPDATE NO ACTION, 
+  FOREIGN KEY(target_batch_id) REFERENCES product_batches(id) ON DELETE NO ACTION ON UPDATE NO ACTION
+);
+
 DROP 
   TABLE IF EXISTS product_records;
 CREATE TABLE product_records(
@@ -177,6 +270,7 @@ CREATE TABLE IF NOT EXISTS melisprint.purchase_orders (
   `order_status_id` INT NOT NULL, 
   `warehouse_id` INT NULL, 
   `product_record_id` INT NOT NULL, 
+  `quantity` INT NOT NULL DEFAULT 1, 
   FOREIGN KEY(buyer_id) REFERENCES buyers(id) ON DELETE NO ACTION ON UPDATE NO ACTION, 
   FOREIGN KEY(carrier_id) REFERENCES carriers(id) ON DELETE NO ACTION ON UPDATE NO ACTION, 
   FOREIGN KEY(order_status_id) REFERENCES order_status(id) ON DELETE NO ACTION ON UPDATE NO ACTION, 
@@ -184,6 +278,44 @@ CREATE TABLE IF NOT EXISTS melisprint.purchase_orders (
   FOREIGN KEY(product_record_id) REFERENCES product_records(id) ON DELETE NO ACTION ON UPDATE NO ACTION
 );
 
+DROP 
+  TABLE IF EXISTS stock_reservations;
+CREATE TABLE stock_reservations(
+  `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
+  `purchase_order_id` INT NOT NULL, 
+  `product_batch_id` INT NOT NULL, 
+  `quantity` INT NOT NULL, 
+  `picked_at` DATETIME NULL, 
+  INDEX `stock_reservations_batch_idx` (`product_batch_id` ASC), 
+  FOREIGN KEY(purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE ON UPDATE NO ACTION, 
+  FOREIGN KEY(product_batch_id) REFERENCES product_batches(id) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS temperature_readings;
+CREATE TABLE temperature_readings(
+  `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
+  `section_id` INT NOT NULL, 
+  `temperature` DECIMAL(19, 2) NOT NULL, 
+  `recorded_at` DATETIME NOT NULL, 
+  INDEX `temperature_readings_section_idx` (`section_id` ASC, `recorded_at` ASC), 
+  FOREIGN KEY(section_id) REFERENCES sections(id) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+DROP 
+  TABLE IF EXISTS temperature_breaches;
+CREATE TABLE temperature_breaches(
+  `id` INT NOT NULL PRIMARY KEY AUTO_INCREMENT, 
+  `section_id` INT NOT NULL, 
+  `product_batch_id` INT NULL, 
+  `temperature` DECIMAL(19, 2) NOT NULL, 
+  `minimum_temperature` DECIMAL(19, 2) NOT NULL, 
+  `recorded_at` DATETIME NOT NULL, 
+  INDEX `temperature_breaches_section_idx` (`section_id` ASC, `recorded_at` ASC), 
+  FOREIGN KEY(section_id) REFERENCES sections(id) ON DELETE CASCADE ON UPDATE NO ACTION, 
+  FOREIGN KEY(product_batch_id) REFERENCES product_batches(id) ON DELETE SET NULL ON UPDATE NO ACTION
+);
+
 DROP 
   TABLE IF EXISTS order_details;
 CREATE TABLE IF NOT EXISTS melisprint.order_details (
@@ -215,6 +347,91 @@ CREATE TABLE IF NOT EXISTS `melisprint`.`inbound_orders` (
   CONSTRAINT `fk_warehouse_inbound_orders` FOREIGN KEY (`warehouse_id`) REFERENCES `melisprint`.`warehouses` (`id`) ON DELETE NO ACTION ON UPDATE NO ACTION
 ) ENGINE = InnoDB;
 
+DROP 
+  TABLE IF EXISTS idempotency_keys;
+CREATE TABLE IF NOT EXISTS `melisprint`.`idempotency_keys` (
+  `idempotency_key` VARCHAR(255) NOT NULL, 
+  `request_hash` VARCHAR(64) NOT NULL, 
+  `response_status` INT NOT NULL DEFAULT 0, 
+  `response_content_type` VARCHAR(255) NOT NULL DEFAULT '', 
+  `response_body` MEDIUMTEXT NOT NULL, 
+  `expires_at` DATETIME NOT NULL, 
+  PRIMARY KEY (`idempotency_key`)
+) ENGINE = InnoDB;
+
+DROP 
+  TABLE IF EXISTS jobs;
+CREATE TABLE IF NOT EXISTS `melisprint`.`jobs` (
+  `id` INT NOT NULL AUTO_INCREMENT, 
+  `kind` VARCHAR(50) NOT NULL, 
+  `status` VARCHAR(20) NOT NULL, 
+  `payload` LONGBLOB NOT NULL, 
+  `progress` INT NOT NULL DEFAULT 0, 
+  `cancel_requested` TINYINT(1) NOT NULL DEFAULT 0, 
+  `result` LONGBLOB NULL, 
+  `result_content_type` VARCHAR(100) NULL, 
+  `error` TEXT NULL, 
+  `created_at` DATETIME NOT NULL, 
+  `started_at` DATETIME NULL, 
+  `claimed_at` DATETIME NULL, 
+  `lease_until` DATETIME NULL, 
+  `finished_at` DATETIME NULL, 
+  PRIMARY KEY (`id`), 
+  INDEX `jobs_status_idx` (`status` ASC, `id` ASC)
+) ENGINE = InnoDB;
+
+DROP 
+  TABLE IF EXISTS outbox;
+CREATE TABLE IF NOT EXISTS `melisprint`.`outbox` (
+  `id` INT NOT NULL AUTO_INCREMENT, 
+  `event_type` VARCHAR(100) NOT NULL, 
+  `aggregate_id` INT NOT NULL, 
+  `payload` JSON NOT NULL, 
+  `attempts` INT NOT NULL DEFAULT 0, 
+  `last_error` TEXT NULL, 
+  `created_at` DATETIME NOT NULL, 
+  `dispatched_at` DATETIME NULL, 
+  `failed_at` DATETIME NULL, 
+  `next_attempt_at` DATETIME NULL, 
+  PRIMARY KEY (`id`), 
+  INDEX `outbox_pending_idx` (`dispatched_at` ASC, `failed_at` ASC, `id` ASC)
+) ENGINE = InnoDB;
+
+DROP 
+  TABLE IF EXISTS webhook_deliveries;
+DROP 
+  TABLE IF EXISTS webhooks;
+CREATE TABLE IF NOT EXISTS `melisprint`.`webhooks` (
+  `id` INT NOT NULL AUTO_INCREMENT, 
+  `url` VARCHAR(2048) NOT NULL, 
+  `event_types` VARCHAR(1024) NOT NULL, 
+  `secret` VARCHAR(255) NOT NULL, 
+  `created_at` DATETIME NOT NULL, 
+  `version` INT NOT NULL DEFAULT 0, 
+  PRIMARY KEY (`id`)
+) ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `melisprint`.`webhook_deliveries` (
+  `id` INT NOT NULL AUTO_INCREMENT, 
+  `webhook_id` INT NOT NULL, 
+  `event_id` INT NOT NULL, 
+  `event_type` VARCHAR(100) NOT NULL, 
+  `payload` LONGBLOB NOT NULL, 
+  `status` VARCHAR(20) NOT NULL, 
+  `attempt` INT NOT NULL DEFAULT 0, 
+  `status_code` INT NULL, 
+  `latency_ms` BIGINT NOT NULL DEFAULT 0, 
+  `error` TEXT NULL, 
+  `succeeded` TINYINT(1) NOT NULL DEFAULT 0, 
+  `next_attempt_at` DATETIME NULL, 
+  `created_at` DATETIME NOT NULL, 
+  PRIMARY KEY (`id`), 
+  UNIQUE INDEX `webhook_deliveries_event_idx` (`webhook_id` ASC, `event_id` ASC), 
+  INDEX `webhook_deliveries_webhook_idx` (`webhook_id` ASC, `id` ASC), 
+  INDEX `webhook_deliveries_due_idx` (`status` ASC, `next_attempt_at` ASC), 
+  CONSTRAINT `fk_webhook_deliveries_webhooks` FOREIGN KEY (`webhook_id`) REFERENCES `melisprint`.`webhooks` (`id`) ON DELETE CASCADE
+) ENGINE = InnoDB;
+
 DROP 
   TABLE IF EXISTS roles;
 CREATE TABLE IF NOT EXISTS `melisprint`.`roles` (
@@ -284,6 +501,10 @@ INSERT INTO `melisprint`.`sections` (`section_number`, `current_temperature`, `m
 INSERT INTO `melisprint`.`product_batches` (`batch_number`, `current_quantity`, `current_temperature`, `due_date`, `initial_quantity`, `manufacturing_date`, `manufacturing_hour`, `minimum_temperature`, `product_id`, `section_id`) VALUES (1, 200, -18, '2023-07-31 00:00:00', 300, '2023-07-01 00:00:00', 8, -20, 1, 1);
 INSERT INTO `melisprint`.`product_batches` (`batch_number`, `current_quantity`, `current_temperature`, `due_date`, `initial_quantity`, `manufacturing_date`, `manufacturing_hour`, `minimum_temperature`, `product_id`, `section_id`) VALUES (2, 150, -15, '2023-08-15 00:00:00', 200, '2023-07-10 00:00:00', 9, -18, 2, 2);
 
+INSERT INTO `melisprint`.`stock_movements` (`product_batch_id`, `movement_type`, `to_section_id`, `quantity`, `created_at`) SELECT `id`, 'receipt', `section_id`, `current_quantity`, `manufacturing_date` FROM `melisprint`.`product_batches`;
+
+UPDATE `melisprint`.`sections` s SET s.`current_capacity` = (SELECT COALESCE(SUM(pb.`current_quantity`), 0) FROM `melisprint`.`product_batches` pb WHERE pb.`section_id` = s.`id`);
+
 INSERT INTO `melisprint`.`product_records` (`last_update_date`, `purchase_price`, `sale_price`, `product_id`) VALUES ('2023-07-05 10:00:00', 10.50, 15.00, 1);
 INSERT INTO `melisprint`.`product_records` (`last_update_date`, `purchase_price`, `sale_price`, `product_id`) VALUES ('2023-07-05 10:00:00', 8.75, 12.50, 2);
 
@@ -295,6 +516,7 @@ INSERT INTO `melisprint`.`carriers` (`cid`, `company_name`, `address`, `telephon
 
 INSERT INTO `melisprint`.`order_status` (`description`) VALUES ('Pending');
 INSERT INTO `melisprint`.`order_status` (`description`) VALUES ('Processing');
+INSERT INTO `melisprint`.`order_status` (`description`) VALUES ('Cancelled');
 
 INSERT INTO `melisprint`.`purchase_orders` (`order_number`, `order_date`, `tracking_code`, `buyer_id`, `carrier_id`, `order_status_id`, `warehouse_id`, `product_record_id`) VALUES ('PO001', '2023-07-01 10:00:00', 'TRACK001', 1, 1, 1, 1, 1);
 INSERT INTO `melisprint`.`purchase_orders` (`order_number`, `order_date`, `tracking_code`, `buyer_id`, `carrier_id`, `order_status_id`, `warehouse_id`, `product_record_id`) VALUES ('PO002', '2023-07-02 11:00:00', 'TRACK002', 2, 2, 2, 2, 2);
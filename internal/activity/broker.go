@@ -0,0 +1,160 @@
+package activity
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section"
+)
+
+// DefaultBufferSize is the number of activities kept per warehouse for the
+// subscribers that reconnect.
+const DefaultBufferSize = 100
+
+// Types of the activity pushed to the subscribers of a warehouse.
+const (
+	InboundReceipt    = "inbound_receipt"
+	BatchCreated      = "batch_created"
+	CapacityChanged   = "capacity_changed"
+	TemperatureBreach = "temperature_breach"
+)
+
+// Broker is an outbox sink that fans the events about the floor of each
+// warehouse out to its subscribers. The latest events of every warehouse are
+// kept in a bounded buffer, for subscribers to resume from after the last one
+// they received. It only sees the events dispatched by this process.
+type Broker struct {
+	mu          sync.Mutex
+	sections    section.Repository
+	size        int
+	buffers     map[int][]domain.WarehouseActivity
+	subscribers map[int]map[chan domain.WarehouseActivity]struct{}
+}
+
+func NewBroker(sections section.Repository, size int) *Broker {
+	return &Broker{
+		sections:    sections,
+		size:        size,
+		buffers:     make(map[int][]domain.WarehouseActivity),
+		subscribers: make(map[int]map[chan domain.WarehouseActivity]struct{}),
+	}
+}
+
+// Publish pushes the event to the subscribers of its warehouse. Events of
+// other types, and events already pushed, are skipped. A subscriber too slow
+// to keep up is dropped, its channel closed, so that it resumes from the
+// buffer instead of holding the dispatcher.
+func (b *Broker) Publish(event domain.Event) error {
+	activity, ok, err := b.toActivity(event)
+	if err != nil || !ok {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	buffer := b.buffers[activity.WarehouseID]
+	if n := len(buffer); n > 0 && buffer[n-1].ID >= activity.ID {
+		return nil
+	}
+	buffer = append(buffer, activity)
+	if len(buffer) > b.size {
+		buffer = buffer[len(buffer)-b.size:]
+	}
+	b.buffers[activity.WarehouseID] = buffer
+
+	for events := range b.subscribers[activity.WarehouseID] {
+		select {
+		case events <- activity:
+		default:
+			b.unsubscribe(activity.WarehouseID, events)
+		}
+	}
+	return nil
+}
+
+// Subscribe returns the buffered activities of the warehouse that followed
+// lastEventID, none when it is zero, and the channel the next ones are pushed
+// to. The channel is closed by cancel, or when the subscriber falls behind.
+func (b *Broker) Subscribe(warehouseID int, lastEventID int) ([]domain.WarehouseActivity, <-chan domain.WarehouseActivity, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	missed := make([]domain.WarehouseActivity, 0)
+	if lastEventID > 0 {
+		for _, activity := range b.buffers[warehouseID] {
+			if activity.ID > lastEventID {
+				missed = append(missed, activity)
+			}
+		}
+	}
+
+	events := make(chan domain.WarehouseActivity, b.size)
+	if b.subscribers[warehouseID] == nil {
+		b.subscribers[warehouseID] = make(map[chan domain.WarehouseActivity]struct{})
+	}
+	b.subscribers[warehouseID][events] = struct{}{}
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.unsubscribe(warehouseID, events)
+	}
+	return missed, events, cancel
+}
+
+func (b *Broker) unsubscribe(warehouseID int, events chan domain.WarehouseActivity) {
+	if _, ok := b.subscribers[warehouseID][events]; !ok {
+		return
+	}
+	delete(b.subscribers[warehouseID], events)
+	if len(b.subscribers[warehouseID]) == 0 {
+		delete(b.subscribers, warehouseID)
+	}
+	close(events)
+}
+
+// toActivity finds the warehouse the event happened in. It returns false for
+// the events that are not warehouse activity, or whose section is gone.
+func (b *Broker) toActivity(event domain.Event) (domain.WarehouseActivity, bool, error) {
+	activity := domain.WarehouseActivity{ID: event.ID, Data: event.Payload, CreatedAt: event.CreatedAt}
+
+	switch event.Type {
+	case domain.EventInboundOrderReceived:
+		var order domain.InboundOrder
+		if err := json.Unmarshal(event.Payload, &order); err != nil {
+			return activity, false, err
+		}
+		activity.Type, activity.WarehouseID = InboundReceipt, order.WarehouseId
+	case domain.EventSectionCapacityChanged:
+		var change domain.SectionCapacityChange
+		if err := json.Unmarshal(event.Payload, &change); err != nil {
+			return activity, false, err
+		}
+		activity.Type, activity.WarehouseID = CapacityChanged, change.WarehouseID
+	case domain.EventProductBatchCreated:
+		var batch domain.ProductBatch
+		if err := json.Unmarshal(event.Payload, &batch); err != nil {
+			return activity, false, err
+		}
+		activity.Type = BatchCreated
+		return b.inSection(activity, batch.SectionID)
+	case domain.EventSectionTemperatureBreached:
+		activity.Type = TemperatureBreach
+		return b.inSection(activity, event.AggregateID)
+	default:
+		return activity, false, nil
+	}
+	return activity, true, nil
+}
+
+func (b *Broker) inSection(activity domain.WarehouseActivity, sectionID int) (domain.WarehouseActivity, bool, error) {
+	sectionFound := b.sections.Get(sectionID)
+	if sectionFound == nil {
+		return activity, false, nil
+	}
+	activity.WarehouseID = sectionFound.WarehouseID
+	return activity, true, nil
+}
@@ -0,0 +1,118 @@
+package activity_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/activity"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func inboundReceived(id int, warehouseID int) domain.Event {
+	payload, _ := json.Marshal(domain.InboundOrder{ID: id, WarehouseId: warehouseID})
+	return domain.Event{ID: id, Type: domain.EventInboundOrderReceived, AggregateID: id, Payload: payload}
+}
+
+func TestBrokerPublish(t *testing.T) {
+	t.Run("Should push the events to the subscribers of their warehouse", func(t *testing.T) {
+		sections := new(mocks.Repository)
+		broker := activity.NewBroker(sections, 10)
+		missed, events, cancel := broker.Subscribe(1, 0)
+		defer cancel()
+		_, others, cancelOthers := broker.Subscribe(2, 0)
+		defer cancelOthers()
+
+		payload, _ := json.Marshal(domain.SectionCapacityChange{SectionID: 3, WarehouseID: 1, CurrentCapacity: 20, MaximumCapacity: 50})
+		assert.NoError(t, broker.Publish(domain.Event{ID: 7, Type: domain.EventSectionCapacityChanged, AggregateID: 3, Payload: payload}))
+
+		assert.Empty(t, missed)
+		pushed := <-events
+		assert.Equal(t, 7, pushed.ID)
+		assert.Equal(t, activity.CapacityChanged, pushed.Type)
+		assert.Equal(t, 1, pushed.WarehouseID)
+		assert.JSONEq(t, string(payload), string(pushed.Data))
+		assert.Empty(t, others)
+	})
+	t.Run("Should find the warehouse of the events about a section", func(t *testing.T) {
+		sections := new(mocks.Repository)
+		broker := activity.NewBroker(sections, 10)
+		_, events, cancel := broker.Subscribe(2, 0)
+		defer cancel()
+
+		sections.On("Get", 4).Return(&domain.Section{ID: 4, WarehouseID: 2})
+		sections.On("Get", 5).Return((*domain.Section)(nil))
+		payload, _ := json.Marshal(domain.ProductBatch{ID: 9, SectionID: 4})
+		assert.NoError(t, broker.Publish(domain.Event{ID: 1, Type: domain.EventProductBatchCreated, AggregateID: 9, Payload: payload}))
+		assert.NoError(t, broker.Publish(domain.Event{ID: 2, Type: domain.EventSectionTemperatureBreached, AggregateID: 4, Payload: []byte(`{}`)}))
+		assert.NoError(t, broker.Publish(domain.Event{ID: 3, Type: domain.EventSectionTemperatureBreached, AggregateID: 5, Payload: []byte(`{}`)}))
+
+		assert.Equal(t, activity.BatchCreated, (<-events).Type)
+		assert.Equal(t, activity.TemperatureBreach, (<-events).Type)
+		assert.Empty(t, events)
+		sections.AssertExpectations(t)
+	})
+	t.Run("Should skip the events that are not warehouse activity or were already pushed", func(t *testing.T) {
+		broker := activity.NewBroker(new(mocks.Repository), 10)
+		_, events, cancel := broker.Subscribe(1, 0)
+		defer cancel()
+
+		assert.NoError(t, broker.Publish(domain.Event{ID: 1, Type: domain.EventPurchaseOrderCreated, Payload: []byte(`{}`)}))
+		assert.NoError(t, broker.Publish(inboundReceived(2, 1)))
+		assert.NoError(t, broker.Publish(inboundReceived(2, 1)))
+
+		assert.Len(t, events, 1)
+	})
+	t.Run("Should drop the subscribers that fall behind", func(t *testing.T) {
+		broker := activity.NewBroker(new(mocks.Repository), 1)
+		_, events, cancel := broker.Subscribe(1, 0)
+		defer cancel()
+
+		assert.NoError(t, broker.Publish(inboundReceived(1, 1)))
+		assert.NoError(t, broker.Publish(inboundReceived(2, 1)))
+
+		assert.Equal(t, 1, (<-events).ID)
+		_, open := <-events
+		assert.False(t, open)
+	})
+}
+
+func TestBrokerSubscribe(t *testing.T) {
+	t.Run("Should return the buffered events that followed the last one received", func(t *testing.T) {
+		broker := activity.NewBroker(new(mocks.Repository), 2)
+		for id := 1; id <= 4; id++ {
+			assert.NoError(t, broker.Publish(inboundReceived(id, 1)))
+		}
+
+		missed, _, cancel := broker.Subscribe(1, 2)
+		defer cancel()
+
+		assert.Len(t, missed, 2)
+		assert.Equal(t, 3, missed[0].ID)
+		assert.Equal(t, 4, missed[1].ID)
+	})
+	t.Run("Should only return the events still buffered", func(t *testing.T) {
+		broker := activity.NewBroker(new(mocks.Repository), 2)
+		for id := 1; id <= 4; id++ {
+			assert.NoError(t, broker.Publish(inboundReceived(id, 1)))
+		}
+
+		missed, _, cancel := broker.Subscribe(1, 1)
+		defer cancel()
+
+		assert.Len(t, missed, 2)
+		assert.Equal(t, 3, missed[0].ID)
+	})
+	t.Run("Should close the channel when canceled", func(t *testing.T) {
+		broker := activity.NewBroker(new(mocks.Repository), 2)
+		_, events, cancel := broker.Subscribe(1, 0)
+
+		cancel()
+		cancel()
+
+		_, open := <-events
+		assert.False(t, open)
+		assert.NoError(t, broker.Publish(inboundReceived(1, 1)))
+	})
+}
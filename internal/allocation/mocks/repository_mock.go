@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Repository struct {
+	mock.Mock
+}
+
+func (r *Repository) PickList(orderID int) []domain.PickListLine {
+	args := r.Called(orderID)
+	return args.Get(0).([]domain.PickListLine)
+}
+
+func (r *Repository) Pick(orderID int, batchID int, employeeID int) (*domain.StockMovement, error) {
+	args := r.Called(orderID, batchID, employeeID)
+	return args.Get(0).(*domain.StockMovement), args.Error(1)
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Service struct {
+	mock.Mock
+}
+
+func (s *Service) MinimumDueDate() time.Time {
+	args := s.Called()
+	return args.Get(0).(time.Time)
+}
+
+func (s *Service) PickList(po domain.PurchaseOrder) domain.PickList {
+	args := s.Called(po)
+	return args.Get(0).(domain.PickList)
+}
+
+func (s *Service) Pick(orderID int, batchID int, employeeID int) (*domain.StockMovement, error) {
+	args := s.Called(orderID, batchID, employeeID)
+	return args.Get(0).(*domain.StockMovement), args.Error(1)
+}
@@ -0,0 +1,208 @@
+package allocation
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+)
+
+const (
+	CandidatesQuery = `SELECT pb.id, pb.current_quantity - COALESCE((SELECT SUM(sr.quantity) FROM stock_reservations sr WHERE sr.product_batch_id = pb.id AND sr.picked_at IS NULL), 0) "available"
+	FROM product_batches pb
+	INNER JOIN sections s ON s.id = pb.section_id
+	WHERE pb.product_id = ? AND s.warehouse_id = ? AND pb.due_date >= ? AND pb.status IN ('available', 'released')
+	ORDER BY pb.due_date, pb.id
+	FOR UPDATE`
+	InsertReservationQuery = "INSERT INTO stock_reservations (purchase_order_id, product_batch_id, quantity) VALUES (?, ?, ?)"
+	PickListQuery          = `SELECT pb.id, pb.batch_number, s.id, s.section_number, pb.due_date, sr.quantity, sr.picked_at
+	FROM stock_reservations sr
+	INNER JOIN product_batches pb ON pb.id = sr.product_batch_id
+	INNER JOIN sections s ON s.id = pb.section_id
+	WHERE sr.purchase_order_id = ? AND (sr.picked_at IS NOT NULL OR pb.status IN ('available', 'released'))
+	ORDER BY pb.due_date, pb.id`
+	ReleaseQuery         = "DELETE FROM stock_reservations WHERE purchase_order_id = ? AND picked_at IS NULL"
+	LockReservationQuery = "SELECT id, quantity FROM stock_reservations WHERE purchase_order_id = ? AND product_batch_id = ? AND picked_at IS NULL FOR UPDATE"
+	PickQuery            = "UPDATE stock_reservations SET picked_at = ? WHERE id = ?"
+	PickMovementQuery    = "INSERT INTO stock_movements (product_batch_id, movement_type, from_section_id, quantity, employee_id, purchase_order_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+)
+
+var (
+	// ErrNotReserved is returned when the order has no quantity of the batch
+	// left to pick.
+	ErrNotReserved = errors.New("batch not reserved for the order")
+	// ErrBatchUnavailable is returned when the batch to pick from is
+	// quarantined or disposed.
+	ErrBatchUnavailable = errors.New("batch not available")
+)
+
+type Repository interface {
+	PickList(orderID int) []domain.PickListLine
+	Pick(orderID int, batchID int, employeeID int) (*domain.StockMovement, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// Reserve locks the batches of the product stored in the warehouse that are
+// not due before minimumDueDate and reserves up to quantity from them, first
+// expired first out. It runs in the transaction that stores the order, so an
+// order is never committed without its reservations. It returns the
+// reservations made, which may sum less than quantity when there is not
+// enough stock.
+func Reserve(tx *sql.Tx, orderID int, productID int, warehouseID int, quantity int, minimumDueDate time.Time) []domain.StockReservation {
+	rows, err := tx.Query(CandidatesQuery, productID, warehouseID, helpers.ToFormattedDateTime(minimumDueDate))
+	if err != nil {
+		panic(err)
+	}
+	candidates := make([]domain.AllocationCandidate, 0)
+	for rows.Next() {
+		c := domain.AllocationCandidate{}
+		_ = rows.Scan(&c.ProductBatchID, &c.Available)
+		candidates = append(candidates, c)
+	}
+	rows.Close()
+
+	reservations := allocate(candidates, quantity)
+	for i := range reservations {
+		reservations[i].PurchaseOrderID = orderID
+		res, err := tx.Exec(InsertReservationQuery, orderID, reservations[i].ProductBatchID, reservations[i].Quantity)
+		if err != nil {
+			panic(err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			panic(err)
+		}
+		reservations[i].ID = int(id)
+	}
+	return reservations
+}
+
+// Release deletes the reservations of the order not picked yet in the
+// transaction that cancels it.
+func Release(tx *sql.Tx, orderID int) {
+	if _, err := tx.Exec(ReleaseQuery, orderID); err != nil {
+		panic(err)
+	}
+}
+
+// PickList returns the reserved lines of the order. Lines not picked yet of
+// quarantined batches are left out until the batch is released.
+func (r *repository) PickList(orderID int) []domain.PickListLine {
+	rows, err := r.db.Query(PickListQuery, orderID)
+	if err != nil {
+		panic(err)
+	}
+	lines := make([]domain.PickListLine, 0)
+
+	for rows.Next() {
+		l := domain.PickListLine{}
+		var dueDate string
+		var pickedAt *string
+		_ = rows.Scan(&l.ProductBatchID, &l.BatchNumber, &l.SectionID, &l.SectionNumber, &dueDate, &l.Quantity, &pickedAt)
+		l.DueDate = helpers.ToDateTime(dueDate)
+		if pickedAt != nil {
+			picked := helpers.ToDateTime(*pickedAt)
+			l.PickedAt = &picked
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// Pick confirms the pick of the quantity of the batch reserved for the order.
+// The quantity leaves the batch, the reservation is marked as picked and the
+// pick is recorded in the stock ledger along with the order, all in the same
+// transaction.
+func (r *repository) Pick(orderID int, batchID int, employeeID int) (*domain.StockMovement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	var currentQuantity, sectionID int
+	if err := tx.QueryRow(product_batch.LockBatchStatusQuery, batchID).Scan(&status, &currentQuantity, &sectionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotReserved
+		}
+		panic(err)
+	}
+	if status == domain.BatchQuarantined || status == domain.BatchDisposed {
+		return nil, ErrBatchUnavailable
+	}
+
+	var reservationID, quantity int
+	if err := tx.QueryRow(LockReservationQuery, orderID, batchID).Scan(&reservationID, &quantity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotReserved
+		}
+		panic(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if _, err := tx.Exec(product_batch.DecreaseQuantityQuery, quantity, batchID); err != nil {
+		panic(err)
+	}
+	if _, err := tx.Exec(PickQuery, helpers.ToFormattedDateTime(now), reservationID); err != nil {
+		panic(err)
+	}
+
+	movement := &domain.StockMovement{
+		ProductBatchID: batchID,
+		Type:           domain.MovementPick,
+		FromSectionID:  &sectionID,
+		Quantity:       quantity,
+		EmployeeID:     &employeeID,
+		CreatedAt:      now,
+	}
+	res, err := tx.Exec(PickMovementQuery, batchID, domain.MovementPick, sectionID, quantity, employeeID, orderID, helpers.ToFormattedDateTime(now))
+	if err != nil {
+		panic(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		panic(err)
+	}
+	movement.ID = int(id)
+	product_batch.SyncSectionCapacity(tx, sectionID)
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return movement, nil
+}
+
+// allocate takes quantity from the candidates in the given order, skipping
+// the ones with nothing available.
+func allocate(candidates []domain.AllocationCandidate, quantity int) []domain.StockReservation {
+	reservations := make([]domain.StockReservation, 0)
+
+	for _, c := range candidates {
+		if quantity == 0 {
+			break
+		}
+		if c.Available <= 0 {
+			continue
+		}
+		taken := c.Available
+		if taken > quantity {
+			taken = quantity
+		}
+		reservations = append(reservations, domain.StockReservation{ProductBatchID: c.ProductBatchID, Quantity: taken})
+		quantity -= taken
+	}
+	return reservations
+}
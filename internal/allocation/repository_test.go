@@ -0,0 +1,224 @@
+package allocation_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/allocation"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	minimumDueDate = time.Date(2023, 7, 8, 0, 0, 0, 0, time.UTC)
+)
+
+func TestRepositoryReserve(t *testing.T) {
+	t.Run("Should reserve from the batches that expire first", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "available"}).
+			AddRow(3, 4).
+			AddRow(1, 0).
+			AddRow(2, 10).
+			AddRow(5, 10)
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(allocation.CandidatesQuery)).
+			WithArgs(1, 2, "2023-07-08 00:00:00").
+			WillReturnRows(rows)
+		mock.ExpectExec(regexp.QuoteMeta(allocation.InsertReservationQuery)).
+			WithArgs(7, 3, 4).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(allocation.InsertReservationQuery)).
+			WithArgs(7, 2, 6).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+
+		tx, _ := db.Begin()
+		result := allocation.Reserve(tx, 7, 1, 2, 10, minimumDueDate)
+
+		assert.Equal(t, []domain.StockReservation{
+			{ID: 1, PurchaseOrderID: 7, ProductBatchID: 3, Quantity: 4},
+			{ID: 2, PurchaseOrderID: 7, ProductBatchID: 2, Quantity: 6},
+		}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should reserve what is available when stock is short", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(allocation.CandidatesQuery)).
+			WithArgs(1, 2, "2023-07-08 00:00:00").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "available"}).AddRow(3, 4))
+		mock.ExpectExec(regexp.QuoteMeta(allocation.InsertReservationQuery)).
+			WithArgs(7, 3, 4).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		tx, _ := db.Begin()
+		result := allocation.Reserve(tx, 7, 1, 2, 10, minimumDueDate)
+
+		assert.Equal(t, []domain.StockReservation{{ID: 1, PurchaseOrderID: 7, ProductBatchID: 3, Quantity: 4}}, result)
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(allocation.CandidatesQuery)).WillReturnError(sql.ErrConnDone)
+
+		tx, _ := db.Begin()
+
+		assert.Panics(t, func() { allocation.Reserve(tx, 7, 1, 2, 10, minimumDueDate) })
+	})
+}
+
+func TestRepositoryPickList(t *testing.T) {
+	t.Run("Should return the reserved batches", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		pickedAt := time.Date(2023, 7, 20, 10, 0, 0, 0, time.UTC)
+		defer db.Close()
+
+		columns := []string{"id", "batch_number", "section_id", "section_number", "due_date", "quantity", "picked_at"}
+		rows := sqlmock.NewRows(columns).
+			AddRow(3, 30, 1, 10, "2023-07-31 00:00:00", 4, nil).
+			AddRow(5, 50, 1, 10, "2023-08-31 00:00:00", 2, "2023-07-20 10:00:00")
+		mock.ExpectQuery(regexp.QuoteMeta(allocation.PickListQuery)).
+			WithArgs(7).
+			WillReturnRows(rows)
+
+		repository := allocation.NewRepository(db)
+		result := repository.PickList(7)
+
+		assert.Equal(t, []domain.PickListLine{
+			{ProductBatchID: 3, BatchNumber: 30, SectionID: 1, SectionNumber: 10, DueDate: time.Date(2023, 7, 31, 0, 0, 0, 0, time.UTC), Quantity: 4},
+			{ProductBatchID: 5, BatchNumber: 50, SectionID: 1, SectionNumber: 10, DueDate: time.Date(2023, 8, 31, 0, 0, 0, 0, time.UTC), Quantity: 2, PickedAt: &pickedAt},
+		}, result)
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(allocation.PickListQuery)).WillReturnError(sql.ErrConnDone)
+
+		repository := allocation.NewRepository(db)
+
+		assert.Panics(t, func() { repository.PickList(7) })
+	})
+}
+
+func TestRepositoryPick(t *testing.T) {
+	t.Run("Should take the reserved quantity out of the batch and record the pick", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		sectionID := 1
+		mock.ExpectBegin()
+		expectLockBatchStatus(mock, 3, domain.BatchAvailable, 10, sectionID)
+		mock.ExpectQuery(regexp.QuoteMeta(allocation.LockReservationQuery)).
+			WithArgs(7, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "quantity"}).AddRow(2, 4))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.DecreaseQuantityQuery)).
+			WithArgs(4, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(allocation.PickQuery)).
+			WithArgs(sqlmock.AnyArg(), 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(allocation.PickMovementQuery)).
+			WithArgs(3, domain.MovementPick, sectionID, 4, 9, 7, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.SyncSectionCapacityQuery)).
+			WithArgs(sectionID, sectionID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		repository := allocation.NewRepository(db)
+		result, err := repository.Pick(7, 3, 9)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, result.ID)
+		assert.Equal(t, domain.MovementPick, result.Type)
+		assert.Equal(t, 4, result.Quantity)
+		assert.Equal(t, sectionID, *result.FromSectionID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return not reserved error when nothing is left to pick", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatchStatus(mock, 3, domain.BatchAvailable, 10, 1)
+		mock.ExpectQuery(regexp.QuoteMeta(allocation.LockReservationQuery)).
+			WithArgs(7, 3).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		repository := allocation.NewRepository(db)
+		result, err := repository.Pick(7, 3, 9)
+
+		assert.ErrorIs(t, err, allocation.ErrNotReserved)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return batch unavailable error when the batch is quarantined", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatchStatus(mock, 3, domain.BatchQuarantined, 10, 1)
+		mock.ExpectRollback()
+
+		repository := allocation.NewRepository(db)
+		result, err := repository.Pick(7, 3, 9)
+
+		assert.ErrorIs(t, err, allocation.ErrBatchUnavailable)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryRelease(t *testing.T) {
+	t.Run("Should delete the reservations of the order", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(allocation.ReleaseQuery)).
+			WithArgs(7).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		tx, _ := db.Begin()
+		allocation.Release(tx, 7)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when exec fails", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(allocation.ReleaseQuery)).WillReturnError(sql.ErrConnDone)
+
+		tx, _ := db.Begin()
+
+		assert.Panics(t, func() { allocation.Release(tx, 7) })
+	})
+}
+
+func expectLockBatchStatus(mock sqlmock.Sqlmock, batchID int, status string, currentQuantity int, sectionID int) {
+	mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchStatusQuery)).
+		WithArgs(batchID).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "current_quantity", "section_id"}).AddRow(status, currentQuantity, sectionID))
+}
+
+func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	return db, mock
+}
@@ -0,0 +1,54 @@
+package allocation
+
+import (
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+)
+
+type Service interface {
+	MinimumDueDate() time.Time
+	PickList(po domain.PurchaseOrder) domain.PickList
+	Pick(orderID int, batchID int, employeeID int) (*domain.StockMovement, error)
+}
+
+type service struct {
+	repository       Repository
+	minimumShelfLife time.Duration
+}
+
+// NewService creates the allocation service. Batches due within
+// minimumShelfLife from now are not reserved.
+func NewService(repository Repository, minimumShelfLife time.Duration) Service {
+	return &service{
+		repository,
+		minimumShelfLife,
+	}
+}
+
+// MinimumDueDate returns the earliest due date of the batches an order placed
+// now can reserve.
+func (s *service) MinimumDueDate() time.Time {
+	return time.Now().UTC().Add(s.minimumShelfLife)
+}
+
+func (s *service) PickList(po domain.PurchaseOrder) domain.PickList {
+	lines := s.repository.PickList(po.ID)
+	pickList := domain.PickList{
+		PurchaseOrderID: po.ID,
+		Requested:       po.Quantity,
+		Lines:           lines,
+	}
+
+	for _, l := range lines {
+		pickList.Reserved += l.Quantity
+	}
+	if pickList.Reserved < pickList.Requested {
+		pickList.Short = pickList.Requested - pickList.Reserved
+	}
+	return pickList
+}
+
+func (s *service) Pick(orderID int, batchID int, employeeID int) (*domain.StockMovement, error) {
+	return s.repository.Pick(orderID, batchID, employeeID)
+}
@@ -0,0 +1,71 @@
+package allocation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/allocation"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/allocation/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	purchaseOrder = domain.PurchaseOrder{ID: 7, WarehouseID: 2, Quantity: 10}
+)
+
+func TestServiceMinimumDueDate(t *testing.T) {
+	t.Run("Should skip the batches due within the minimum shelf life", func(t *testing.T) {
+		service, _ := CreateService(t, 72*time.Hour)
+
+		result := service.MinimumDueDate()
+
+		assert.True(t, result.After(time.Now().Add(71*time.Hour)))
+	})
+}
+
+func TestServicePickList(t *testing.T) {
+	t.Run("Should report the quantity that could not be reserved", func(t *testing.T) {
+		service, repository := CreateService(t, 0)
+
+		lines := []domain.PickListLine{{ProductBatchID: 3, Quantity: 4}, {ProductBatchID: 2, Quantity: 2}}
+		repository.On("PickList", purchaseOrder.ID).Return(lines)
+
+		result := service.PickList(purchaseOrder)
+
+		assert.Equal(t, domain.PickList{PurchaseOrderID: 7, Requested: 10, Reserved: 6, Short: 4, Lines: lines}, result)
+	})
+	t.Run("Should not report shortage when the order is fully reserved", func(t *testing.T) {
+		service, repository := CreateService(t, 0)
+
+		lines := []domain.PickListLine{{ProductBatchID: 3, Quantity: 10}}
+		repository.On("PickList", purchaseOrder.ID).Return(lines)
+
+		result := service.PickList(purchaseOrder)
+
+		assert.Zero(t, result.Short)
+		assert.Equal(t, 10, result.Reserved)
+	})
+}
+
+func TestServicePick(t *testing.T) {
+	t.Run("Should return the recorded pick", func(t *testing.T) {
+		service, repository := CreateService(t, 0)
+
+		movement := &domain.StockMovement{ID: 1, ProductBatchID: 3, Type: domain.MovementPick, Quantity: 4}
+		repository.On("Pick", purchaseOrder.ID, 3, 9).Return(movement, nil)
+
+		result, err := service.Pick(purchaseOrder.ID, 3, 9)
+
+		assert.NoError(t, err)
+		assert.Equal(t, movement, result)
+	})
+}
+
+func CreateService(t *testing.T, minimumShelfLife time.Duration) (allocation.Service, *mocks.Repository) {
+	t.Helper()
+	repository := new(mocks.Repository)
+	service := allocation.NewService(repository, minimumShelfLife)
+
+	return service, repository
+}
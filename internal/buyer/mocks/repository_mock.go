@@ -19,31 +19,51 @@ func (r *Repository) Get(id int) *domain.Buyer {
 	return args.Get(0).(*domain.Buyer)
 }
 
+func (r *Repository) GetMany(ids []int) []domain.Buyer {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.Buyer)
+}
+
 func (r *Repository) Exists(cardNumberID string) bool {
 	args := r.Called(cardNumberID)
 	return args.Get(0).(bool)
 }
 
+func (r *Repository) Existing(cardNumberIDs []string) []string {
+	args := r.Called(cardNumberIDs)
+	return args.Get(0).([]string)
+}
+
 func (r *Repository) Save(buyer domain.Buyer) int {
 	args := r.Called(buyer)
 	return args.Get(0).(int)
 }
 
-func (r *Repository) Update(buyer domain.Buyer) {
-	r.Called(buyer).Get(0)
+func (r *Repository) SaveAll(buyers []domain.Buyer) []int {
+	args := r.Called(buyers)
+	return args.Get(0).([]int)
+}
 
+func (r *Repository) Update(buyer domain.Buyer) error {
+	args := r.Called(buyer)
+	return args.Error(0)
 }
 
 func (r *Repository) Delete(id int) {
 	r.Called(id)
 }
 
-func (r *Repository) CountPurchasesByAllBuyers() []domain.PurchasesByBuyerReport {
-	args := r.Called()
+func (r *Repository) CountPurchasesByAllBuyers(dates domain.DateRange) []domain.PurchasesByBuyerReport {
+	args := r.Called(dates)
 	return args.Get(0).([]domain.PurchasesByBuyerReport)
 }
 
-func (r *Repository) CountPurchasesByBuyer(id int) *domain.PurchasesByBuyerReport {
-	args := r.Called(id)
+func (r *Repository) CountPurchasesByBuyer(id int, dates domain.DateRange) *domain.PurchasesByBuyerReport {
+	args := r.Called(id, dates)
 	return args.Get(0).(*domain.PurchasesByBuyerReport)
 }
+
+func (r *Repository) CountPurchasesByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod {
+	args := r.Called(id, period, dates)
+	return args.Get(0).([]domain.ReportPeriod)
+}
@@ -14,6 +14,11 @@ func (s *Service) GetAll() []domain.Buyer {
 	return args.Get(0).([]domain.Buyer)
 }
 
+func (s *Service) GetMany(ids []int) []domain.Buyer {
+	args := s.Called(ids)
+	return args.Get(0).([]domain.Buyer)
+}
+
 func (s *Service) Get(id int) (*domain.Buyer, error) {
 	args := s.Called(id)
 	return args.Get(0).(*domain.Buyer), args.Error(1)
@@ -24,8 +29,13 @@ func (s *Service) Create(p domain.Buyer) (*domain.Buyer, error) {
 	return args.Get(0).(*domain.Buyer), args.Error(1)
 }
 
-func (s *Service) Update(id int, p domain.UpdateBuyer) (*domain.Buyer, error) {
-	args := s.Called(id, p)
+func (s *Service) Import(buyers []domain.Buyer, options domain.ImportOptions) ([]int, []error) {
+	args := s.Called(buyers, options)
+	return args.Get(0).([]int), args.Get(1).([]error)
+}
+
+func (s *Service) Update(current domain.Buyer, p domain.Buyer) (*domain.Buyer, error) {
+	args := s.Called(current, p)
 	return args.Get(0).(*domain.Buyer), args.Error(1)
 }
 
@@ -34,12 +44,17 @@ func (s *Service) Delete(id int) error {
 	return args.Error(0)
 }
 
-func (s *Service) CountPurchasesByAllBuyers() []domain.PurchasesByBuyerReport {
-	args := s.Called()
+func (s *Service) CountPurchasesByAllBuyers(dates domain.DateRange, top int) []domain.PurchasesByBuyerReport {
+	args := s.Called(dates, top)
 	return args.Get(0).([]domain.PurchasesByBuyerReport)
 }
 
-func (s *Service) CountPurchasesByBuyer(id int) (*domain.PurchasesByBuyerReport, error) {
-	args := s.Called(id)
+func (s *Service) CountPurchasesByBuyer(id int, dates domain.DateRange) (*domain.PurchasesByBuyerReport, error) {
+	args := s.Called(id, dates)
 	return args.Get(0).(*domain.PurchasesByBuyerReport), args.Error(1)
 }
+
+func (s *Service) CountPurchasesByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error) {
+	args := s.Called(id, period, dates)
+	return args.Get(0).([]domain.ReportPeriod), args.Error(1)
+}
@@ -5,39 +5,61 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetAllQuery = "SELECT id, card_number_id, first_name, last_name FROM buyers"
-	GetQuery    = "SELECT id, card_number_id, first_name, last_name FROM buyers WHERE id = ?;"
-	ExistsQuery = "SELECT card_number_id FROM buyers WHERE card_number_id=?;"
-	InsertQuery = "INSERT INTO buyers(card_number_id,first_name,last_name) VALUES (?,?,?)"
-	UpdateQuery = "UPDATE buyers SET card_number_id=?, first_name=?, last_name=?  WHERE id=?"
-	DeleteQuery = "DELETE FROM buyers WHERE id = ?"
+	GetAllQuery   = "SELECT id, card_number_id, first_name, last_name FROM buyers"
+	GetQuery      = "SELECT id, card_number_id, first_name, last_name, version FROM buyers WHERE id = ?;"
+	GetManyQuery  = "SELECT id, card_number_id, first_name, last_name FROM buyers WHERE id IN (%s);"
+	ExistsQuery   = "SELECT card_number_id FROM buyers WHERE card_number_id=?;"
+	ExistingQuery = "SELECT card_number_id FROM buyers WHERE card_number_id IN (%s);"
+	InsertQuery   = "INSERT INTO buyers(card_number_id,first_name,last_name) VALUES (?,?,?)"
+	UpdateQuery   = "UPDATE buyers SET card_number_id=?, first_name=?, last_name=?, version=version+1 WHERE id=? AND version=?"
+	DeleteQuery   = "DELETE FROM buyers WHERE id = ?"
 
 	CountPurchasesByAllBuyers = `SELECT b.id, b.card_number_id, b.first_name, b.last_name, count(po.id) "purchase_orders_count"
 		FROM buyers b
 		LEFT JOIN purchase_orders po ON b.id = po.buyer_id
+		AND (? IS NULL OR po.order_date >= ?) AND (? IS NULL OR po.order_date < ?)
 		GROUP BY b.id`
 
 	CountPurchasesByBuyer = `SELECT b.id, b.card_number_id, b.first_name, b.last_name, count(po.id) "purchase_orders_count"
 		FROM buyers b
 		LEFT JOIN purchase_orders po ON b.id = po.buyer_id
+		AND (? IS NULL OR po.order_date >= ?) AND (? IS NULL OR po.order_date < ?)
 		WHERE b.id=?
 		GROUP BY b.id`
+
+	CountPurchasesByPeriodQuery = `SELECT CASE ?
+		WHEN 'month' THEN DATE_FORMAT(po.order_date, '%Y-%m-01 00:00:00')
+		WHEN 'week' THEN DATE_FORMAT(po.order_date - INTERVAL WEEKDAY(po.order_date) DAY, '%Y-%m-%d 00:00:00')
+		ELSE DATE_FORMAT(po.order_date, '%Y-%m-%d 00:00:00') END "period", count(po.id) "purchase_orders_count"
+		FROM purchase_orders po
+		WHERE (? IS NULL OR po.buyer_id = ?) AND (? IS NULL OR po.order_date >= ?) AND (? IS NULL OR po.order_date < ?)
+		GROUP BY 1
+		ORDER BY 1`
 )
 
 type Repository interface {
 	GetAll() []domain.Buyer
 	Get(id int) *domain.Buyer
+	GetMany(ids []int) []domain.Buyer
 	Exists(cardNumberID string) bool
+	Existing(cardNumberIDs []string) []string
 	Save(b domain.Buyer) int
-	Update(b domain.Buyer)
+	SaveAll(buyers []domain.Buyer) []int
+	Update(b domain.Buyer) error
 	Delete(id int)
-	CountPurchasesByAllBuyers() []domain.PurchasesByBuyerReport
-	CountPurchasesByBuyer(id int) *domain.PurchasesByBuyerReport
+	CountPurchasesByAllBuyers(dates domain.DateRange) []domain.PurchasesByBuyerReport
+	CountPurchasesByBuyer(id int, dates domain.DateRange) *domain.PurchasesByBuyerReport
+	CountPurchasesByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod
 }
 
+// ErrModified is returned by Update when the buyer was changed or deleted
+// after the version being updated was read.
+var ErrModified = errors.New("buyer modified since read")
+
 type repository struct {
 	db *sql.DB
 }
@@ -68,7 +90,7 @@ func (r *repository) GetAll() []domain.Buyer {
 func (r *repository) Get(id int) *domain.Buyer {
 	row := r.db.QueryRow(GetQuery, id)
 	b := domain.Buyer{}
-	err := row.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName)
+	err := row.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName, &b.Version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil
@@ -79,12 +101,61 @@ func (r *repository) Get(id int) *domain.Buyer {
 	return &b
 }
 
+// GetMany returns the buyers found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.Buyer {
+	buyers := make([]domain.Buyer, 0, len(ids))
+	if len(ids) == 0 {
+		return buyers
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		b := domain.Buyer{}
+		if err := rows.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName); err != nil {
+			panic(err)
+		}
+		buyers = append(buyers, b)
+	}
+	return buyers
+}
+
 func (r *repository) Exists(cardNumberID string) bool {
 	row := r.db.QueryRow(ExistsQuery, cardNumberID)
 	err := row.Scan(&cardNumberID)
 	return err == nil
 }
 
+// Existing returns which of the given card numbers are already taken, looking
+// them up with a single query.
+func (r *repository) Existing(cardNumberIDs []string) []string {
+	existing := make([]string, 0, len(cardNumberIDs))
+	if len(cardNumberIDs) == 0 {
+		return existing
+	}
+
+	query, args := helpers.ExpandIn(ExistingQuery, cardNumberIDs)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cardNumberID string
+		if err := rows.Scan(&cardNumberID); err != nil {
+			panic(err)
+		}
+		existing = append(existing, cardNumberID)
+	}
+	return existing
+}
+
 func (r *repository) Save(b domain.Buyer) int {
 	stmt, err := r.db.Prepare(InsertQuery)
 	if err != nil {
@@ -104,16 +175,60 @@ func (r *repository) Save(b domain.Buyer) int {
 	return int(id)
 }
 
-func (r *repository) Update(b domain.Buyer) {
+// SaveAll inserts the buyers in a single transaction and returns their ids
+// in the same order.
+func (r *repository) SaveAll(buyers []domain.Buyer) []int {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(InsertQuery)
+	if err != nil {
+		panic(err)
+	}
+	defer stmt.Close()
+
+	ids := make([]int, 0, len(buyers))
+	for _, b := range buyers {
+		res, err := stmt.Exec(b.CardNumberID, b.FirstName, b.LastName)
+		if err != nil {
+			panic(err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			panic(err)
+		}
+		ids = append(ids, int(id))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+func (r *repository) Update(b domain.Buyer) error {
 	stmt, err := r.db.Prepare(UpdateQuery)
 	if err != nil {
 		panic(err)
 	}
 
-	_, err = stmt.Exec(&b.CardNumberID, &b.FirstName, &b.LastName, &b.ID)
+	res, err := stmt.Exec(&b.CardNumberID, &b.FirstName, &b.LastName, &b.ID, &b.Version)
 	if err != nil {
 		panic(err)
 	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	if affected == 0 {
+		return ErrModified
+	}
+	return nil
 }
 
 func (r *repository) Delete(id int) {
@@ -128,8 +243,9 @@ func (r *repository) Delete(id int) {
 	}
 }
 
-func (r *repository) CountPurchasesByAllBuyers() []domain.PurchasesByBuyerReport {
-	rows, err := r.db.Query(CountPurchasesByAllBuyers)
+func (r *repository) CountPurchasesByAllBuyers(dates domain.DateRange) []domain.PurchasesByBuyerReport {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows, err := r.db.Query(CountPurchasesByAllBuyers, from, from, to, to)
 	if err != nil {
 		panic(err)
 	}
@@ -145,8 +261,9 @@ func (r *repository) CountPurchasesByAllBuyers() []domain.PurchasesByBuyerReport
 	return purchasesByBuyer
 }
 
-func (r *repository) CountPurchasesByBuyer(id int) *domain.PurchasesByBuyerReport {
-	rows := r.db.QueryRow(CountPurchasesByBuyer, id)
+func (r *repository) CountPurchasesByBuyer(id int, dates domain.DateRange) *domain.PurchasesByBuyerReport {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows := r.db.QueryRow(CountPurchasesByBuyer, from, from, to, to, id)
 	b := domain.PurchasesByBuyerReport{}
 	err := rows.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName, &b.PurchasesCount)
 	if err != nil {
@@ -158,3 +275,25 @@ func (r *repository) CountPurchasesByBuyer(id int) *domain.PurchasesByBuyerRepor
 
 	return &b
 }
+
+// CountPurchasesByPeriod counts the purchase orders of the buyer, or of every
+// buyer when id is nil, by day, week or month of their order date.
+func (r *repository) CountPurchasesByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows, err := r.db.Query(CountPurchasesByPeriodQuery, period, id, id, from, from, to, to)
+	if err != nil {
+		panic(err)
+	}
+
+	periods := make([]domain.ReportPeriod, 0)
+
+	for rows.Next() {
+		rp := domain.ReportPeriod{}
+		var start string
+		_ = rows.Scan(&start, &rp.Count)
+		rp.Period = helpers.ToDateTime(start)
+		periods = append(periods, rp)
+	}
+
+	return periods
+}
@@ -4,10 +4,12 @@ import (
 	"database/sql"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -55,10 +57,10 @@ func TestRepositoryGet(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"id", "card_number_id", "first_name", "last_name"}
+		columns := []string{"id", "card_number_id", "first_name", "last_name", "version"}
 		rows := sqlmock.NewRows(columns)
 		buyerID := 1
-		rows.AddRow(buyerID, "", "", "")
+		rows.AddRow(buyerID, "", "", "", 2)
 
 		mock.ExpectQuery(regexp.QuoteMeta(buyer.GetQuery)).WithArgs(buyerID).WillReturnRows(rows)
 
@@ -67,6 +69,7 @@ func TestRepositoryGet(t *testing.T) {
 		result := repository.Get(buyerID)
 
 		assert.NotNil(t, result)
+		assert.Equal(t, 2, result.Version)
 	})
 
 	t.Run("Should not return a buyer", func(t *testing.T) {
@@ -234,11 +237,32 @@ func TestRepositoryUpdate(t *testing.T) {
 				mockedBuyer.FirstName,
 				mockedBuyer.LastName,
 				mockedBuyer.ID,
+				mockedBuyer.Version,
 			).WillReturnResult(sqlmock.NewResult(int64(mockedBuyer.ID), 1))
 
 		repository := buyer.NewRepository(db)
 
-		assert.NotPanics(t, func() { repository.Update(mockedBuyer) })
+		assert.NoError(t, repository.Update(mockedBuyer))
+	})
+
+	t.Run("Should not update the buyer when its version changed", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedBuyer := mockedBuyerTemplate
+		mock.ExpectPrepare(regexp.QuoteMeta(buyer.UpdateQuery))
+		mock.ExpectExec(regexp.QuoteMeta(buyer.UpdateQuery)).
+			WithArgs(
+				mockedBuyer.CardNumberID,
+				mockedBuyer.FirstName,
+				mockedBuyer.LastName,
+				mockedBuyer.ID,
+				mockedBuyer.Version,
+			).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := buyer.NewRepository(db)
+
+		assert.ErrorIs(t, repository.Update(mockedBuyer), buyer.ErrModified)
 	})
 
 	t.Run("Should throw a panic when ExpectPrepare fails", func(t *testing.T) {
@@ -330,7 +354,7 @@ func TestRepositoryCountPurchasesByAllBuyers(t *testing.T) {
 
 		repository := buyer.NewRepository(db)
 
-		result := repository.CountPurchasesByAllBuyers()
+		result := repository.CountPurchasesByAllBuyers(domain.DateRange{})
 
 		assert.Equal(t, len(result), 1)
 	})
@@ -343,7 +367,7 @@ func TestRepositoryCountPurchasesByAllBuyers(t *testing.T) {
 
 		repository := buyer.NewRepository(db)
 
-		assert.Panics(t, func() { repository.CountPurchasesByAllBuyers() })
+		assert.Panics(t, func() { repository.CountPurchasesByAllBuyers(domain.DateRange{}) })
 	})
 }
 
@@ -358,12 +382,12 @@ func TestRepositoryCountPurchasesByBuyer(t *testing.T) {
 		rows.AddRow(buyerID, "", "", "", 1)
 
 		mock.ExpectQuery(regexp.QuoteMeta(buyer.CountPurchasesByBuyer)).
-			WithArgs(buyerID).
+			WithArgs(nil, nil, nil, nil, buyerID).
 			WillReturnRows(rows)
 
 		repository := buyer.NewRepository(db)
 
-		result := repository.CountPurchasesByBuyer(buyerID)
+		result := repository.CountPurchasesByBuyer(buyerID, domain.DateRange{})
 
 		assert.NotNil(t, result)
 	})
@@ -377,7 +401,7 @@ func TestRepositoryCountPurchasesByBuyer(t *testing.T) {
 
 		repository := buyer.NewRepository(db)
 
-		result := repository.CountPurchasesByBuyer(buyerID)
+		result := repository.CountPurchasesByBuyer(buyerID, domain.DateRange{})
 
 		assert.Nil(t, result)
 	})
@@ -391,7 +415,111 @@ func TestRepositoryCountPurchasesByBuyer(t *testing.T) {
 
 		repository := buyer.NewRepository(db)
 
-		assert.Panics(t, func() { repository.CountPurchasesByBuyer(localityId) })
+		assert.Panics(t, func() { repository.CountPurchasesByBuyer(localityId, domain.DateRange{}) })
+	})
+}
+
+func TestRepositoryCountPurchasesByPeriod(t *testing.T) {
+	t.Run("Should return the count of each period within the dates", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		id := 1
+		from := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows([]string{"period", "purchase_orders_count"}).
+			AddRow("2023-07-03 00:00:00", 2).
+			AddRow("2023-07-10 00:00:00", 1)
+
+		mock.ExpectQuery(regexp.QuoteMeta(buyer.CountPurchasesByPeriodQuery)).
+			WithArgs(domain.PeriodWeek, &id, &id, "2023-07-01 00:00:00", "2023-07-01 00:00:00", nil, nil).
+			WillReturnRows(rows)
+
+		repository := buyer.NewRepository(db)
+
+		result := repository.CountPurchasesByPeriod(&id, domain.PeriodWeek, domain.DateRange{From: &from})
+
+		assert.Equal(t, []domain.ReportPeriod{
+			{Period: time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC), Count: 2},
+			{Period: time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC), Count: 1},
+		}, result)
+	})
+}
+
+func TestRepositoryGetMany(t *testing.T) {
+	t.Run("Should return the buyers found among the ids with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(buyer.GetManyQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "card_number_id", "first_name", "last_name"}).
+			AddRow(2, "402323", "Jhon", "Doe").
+			AddRow(5, "402324", "Jane", "Doe")
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := buyer.NewRepository(db)
+		result := repository.GetMany([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := buyer.NewRepository(db)
+		result := repository.GetMany(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(buyer.GetManyQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := buyer.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetMany([]int{2}) })
+	})
+}
+
+func TestRepositoryExisting(t *testing.T) {
+	t.Run("Should return the card numbers already taken with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(buyer.ExistingQuery, []string{"402323", "402324"})
+		rows := sqlmock.NewRows([]string{"card_number_id"}).AddRow("402324")
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("402323", "402324").WillReturnRows(rows)
+
+		repository := buyer.NewRepository(db)
+		result := repository.Existing([]string{"402323", "402324"})
+
+		assert.Equal(t, []string{"402324"}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no card numbers", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := buyer.NewRepository(db)
+		result := repository.Existing(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(buyer.ExistingQuery, []string{"402323"})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("402323").WillReturnError(sql.ErrConnDone)
+
+		repository := buyer.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Existing([]string{"402323"}) })
 	})
 }
 
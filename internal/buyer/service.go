@@ -1,23 +1,30 @@
 package buyer
 
 import (
+	"errors"
+
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	ResourceNotFound      = "comprador não encontrado com o id %d"
-	ResourceAlreadyExists = "um comprador com o número de cartão '%s' já existe"
+	ResourceNotFound      = "buyer.not_found"
+	ResourceAlreadyExists = "buyer.already_exists"
+	ResourceModified      = "buyer.modified"
 )
 
 type Service interface {
 	GetAll() []domain.Buyer
+	GetMany(ids []int) []domain.Buyer
 	Get(id int) (*domain.Buyer, error)
 	Create(b domain.Buyer) (*domain.Buyer, error)
-	Update(id int, b domain.UpdateBuyer) (*domain.Buyer, error)
+	Import(buyers []domain.Buyer, options domain.ImportOptions) ([]int, []error)
+	Update(current domain.Buyer, b domain.Buyer) (*domain.Buyer, error)
 	Delete(id int) error
-	CountPurchasesByAllBuyers() []domain.PurchasesByBuyerReport
-	CountPurchasesByBuyer(id int) (*domain.PurchasesByBuyerReport, error)
+	CountPurchasesByAllBuyers(dates domain.DateRange, top int) []domain.PurchasesByBuyerReport
+	CountPurchasesByBuyer(id int, dates domain.DateRange) (*domain.PurchasesByBuyerReport, error)
+	CountPurchasesByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error)
 }
 
 type service struct {
@@ -30,24 +37,42 @@ func NewService(r Repository) Service {
 	}
 }
 
-func (s *service) CountPurchasesByAllBuyers() []domain.PurchasesByBuyerReport {
-	return s.repository.CountPurchasesByAllBuyers()
+// CountPurchasesByAllBuyers counts the purchase orders of every buyer. When
+// top is positive, only the top buyers with the most purchase orders are
+// returned.
+func (s *service) CountPurchasesByAllBuyers(dates domain.DateRange, top int) []domain.PurchasesByBuyerReport {
+	report := s.repository.CountPurchasesByAllBuyers(dates)
+
+	return helpers.Top(report, top, func(r domain.PurchasesByBuyerReport) int { return r.PurchasesCount })
 }
 
-func (s *service) CountPurchasesByBuyer(id int) (*domain.PurchasesByBuyerReport, error) {
+func (s *service) CountPurchasesByBuyer(id int, dates domain.DateRange) (*domain.PurchasesByBuyerReport, error) {
 	buyer := s.repository.Get(id)
 
 	if buyer == nil {
 		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
 	}
 
-	return s.repository.CountPurchasesByBuyer(id), nil
+	return s.repository.CountPurchasesByBuyer(id, dates), nil
+}
+
+func (s *service) CountPurchasesByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error) {
+	if id != nil && s.repository.Get(*id) == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, *id)
+	}
+
+	return s.repository.CountPurchasesByPeriod(id, period, dates), nil
 }
 
 func (s *service) GetAll() []domain.Buyer {
 	return s.repository.GetAll()
 }
 
+// GetMany returns the buyers found among ids, leaving out the missing ones.
+func (s *service) GetMany(ids []int) []domain.Buyer {
+	return s.repository.GetMany(ids)
+}
+
 func (s *service) Get(id int) (*domain.Buyer, error) {
 	buyer := s.repository.Get(id)
 
@@ -69,26 +94,41 @@ func (s *service) Create(b domain.Buyer) (*domain.Buyer, error) {
 	return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, b.CardNumberID)
 }
 
-func (s *service) Update(id int, buyer domain.UpdateBuyer) (*domain.Buyer, error) {
-	buyerFound := s.repository.Get(id)
+// Import creates the buyers in bulk, rejecting the card numbers that already
+// exist or are repeated in the import. The card numbers of the whole import
+// are looked up at once.
+func (s *service) Import(buyers []domain.Buyer, options domain.ImportOptions) ([]int, []error) {
+	taken := helpers.Found(buyers, func(b domain.Buyer) string { return b.CardNumberID }, s.repository.Existing, helpers.Identity[string])
+	cardNumbers := make(map[string]bool)
+	check := func(b domain.Buyer) error {
+		if cardNumbers[b.CardNumberID] || taken(b.CardNumberID) {
+			return apperr.NewResourceAlreadyExists(ResourceAlreadyExists, b.CardNumberID)
+		}
+		cardNumbers[b.CardNumberID] = true
 
-	if buyerFound == nil {
-		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+		return nil
 	}
 
-	if buyer.CardNumberID != nil {
-		cardNumberID := *buyer.CardNumberID
-		cardNumberIDExists := s.repository.Exists(cardNumberID)
+	return helpers.Import(buyers, options.DryRun, options.Mode != domain.ImportBestEffort, check, s.repository.SaveAll, options.Progress)
+}
 
-		if cardNumberIDExists && cardNumberID != buyerFound.CardNumberID {
-			return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, cardNumberID)
-		}
+// Update replaces current, the buyer as the caller read it, with buyer. It
+// fails when the buyer was changed since it was read.
+func (s *service) Update(current domain.Buyer, buyer domain.Buyer) (*domain.Buyer, error) {
+	cardNumberID := buyer.CardNumberID
+	cardNumberIDExists := s.repository.Exists(cardNumberID)
+
+	if cardNumberIDExists && cardNumberID != current.CardNumberID {
+		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, cardNumberID)
 	}
 
-	buyerFound.Overlap(buyer)
+	buyer.ID = current.ID
+	buyer.Version = current.Version
 
-	s.repository.Update(*buyerFound)
-	updated := s.repository.Get(id)
+	if err := s.repository.Update(buyer); errors.Is(err, ErrModified) {
+		return nil, apperr.NewIncompatibleResource(ResourceModified, buyer.ID)
+	}
+	updated := s.repository.Get(buyer.ID)
 
 	return updated, nil
 }
@@ -2,6 +2,7 @@ package buyer_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/buyer/mocks"
@@ -77,59 +78,43 @@ func TestServiceGet(t *testing.T) {
 }
 
 func TestServiceUpdate(t *testing.T) {
-	t.Run("Should return a not found error", func(t *testing.T) {
+	t.Run("Should return a conflict error", func(t *testing.T) {
 		service, repository := CreateService(t)
-		id := 2
-		cardNumberId := "12"
-		updateBuyer := domain.UpdateBuyer{
-			ID:           &id,
-			CardNumberID: &cardNumberId,
-		}
-		var repositoryResult *domain.Buyer
-		repository.On("Get", id).Return(repositoryResult)
-		result, err := service.Update(id, updateBuyer)
+		updateBuyer := mockedBuyer
+		updateBuyer.CardNumberID = "12"
+		repository.On("Exists", updateBuyer.CardNumberID).Return(true)
+		result, err := service.Update(mockedBuyer, updateBuyer)
 		assert.Error(t, err)
 		assert.Nil(t, result)
-		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+		assert.True(t, apperr.Is[*apperr.ResourceAlreadyExists](err))
 	})
 
-	t.Run("Should return a conflict error", func(t *testing.T) {
+	t.Run("Should return a conflict error when the buyer was modified since it was read", func(t *testing.T) {
 		service, repository := CreateService(t)
-		id := 1
-		cardNumberId := "12"
-		updateBuyer := domain.UpdateBuyer{
-			ID:           &id,
-			CardNumberID: &cardNumberId,
-		}
-		repository.On("Get", id).Return(&mockedBuyer)
-		repository.On("Exists", cardNumberId).Return(true)
-		result, err := service.Update(id, updateBuyer)
-		assert.Error(t, err)
+		currentBuyer := mockedBuyer
+		currentBuyer.Version = 3
+		updatedBuyer := currentBuyer
+		updatedBuyer.FirstName = ""
+
+		repository.On("Exists", updatedBuyer.CardNumberID).Return(true)
+		repository.On("Update", updatedBuyer).Return(buyer.ErrModified)
+		result, err := service.Update(currentBuyer, updatedBuyer)
 		assert.Nil(t, result)
-		assert.True(t, apperr.Is[*apperr.ResourceAlreadyExists](err))
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
 	})
 
 	t.Run("Should return an updated product", func(t *testing.T) {
 		service, repository := CreateService(t)
-		id := 1
-		cardNumberId := "123"
-		updateBuyer := domain.UpdateBuyer{
-			ID:           &id,
-			CardNumberID: &cardNumberId,
-		}
-
 		updatedBuyer := mockedBuyer
+		updatedBuyer.FirstName = ""
 
-		updatedBuyer.Overlap(updateBuyer)
-
-		repository.On("Get", id).Return(&mockedBuyer)
-		repository.On("Exists", cardNumberId).Return(true)
-		repository.On("Update", updatedBuyer).Return(updatedBuyer)
-		repository.On("Get", id).Return(&updatedBuyer)
-		result, err := service.Update(id, updateBuyer)
+		repository.On("Exists", updatedBuyer.CardNumberID).Return(true)
+		repository.On("Update", updatedBuyer).Return(nil)
+		repository.On("Get", mockedBuyer.ID).Return(&updatedBuyer)
+		result, err := service.Update(mockedBuyer, updatedBuyer)
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, cardNumberId, result.CardNumberID)
+		assert.Equal(t, updatedBuyer.CardNumberID, result.CardNumberID)
 
 	})
 }
@@ -169,9 +154,9 @@ func TestServiceCountPurchasesByAllBuyers(t *testing.T) {
 
 		mockedPurchasesByAllBuyerReport := []domain.PurchasesByBuyerReport{mockedPurchasesByBuyerReport}
 
-		repository.On("CountPurchasesByAllBuyers").Return(mockedPurchasesByAllBuyerReport)
+		repository.On("CountPurchasesByAllBuyers", domain.DateRange{}).Return(mockedPurchasesByAllBuyerReport)
 
-		result := service.CountPurchasesByAllBuyers()
+		result := service.CountPurchasesByAllBuyers(domain.DateRange{}, 0)
 
 		assert.Equal(t, 1, len(result))
 		assert.Equal(t, result[0], mockedPurchasesByBuyerReport)
@@ -194,9 +179,9 @@ func TestServiceCountPurchasesByBuyer(t *testing.T) {
 		}
 
 		repository.On("Get", buyerID).Return(&buyer)
-		repository.On("CountPurchasesByBuyer", buyerID).Return(&mockedPurchasesByBuyerReport)
+		repository.On("CountPurchasesByBuyer", buyerID, domain.DateRange{}).Return(&mockedPurchasesByBuyerReport)
 
-		result, err := service.CountPurchasesByBuyer(buyerID)
+		result, err := service.CountPurchasesByBuyer(buyerID, domain.DateRange{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -211,7 +196,7 @@ func TestServiceCountPurchasesByBuyer(t *testing.T) {
 		var buyerRepositoryGetResult *domain.Buyer
 		repository.On("Get", buyerID).Return(buyerRepositoryGetResult)
 
-		result, err := service.CountPurchasesByBuyer(buyerID)
+		result, err := service.CountPurchasesByBuyer(buyerID, domain.DateRange{})
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -219,6 +204,33 @@ func TestServiceCountPurchasesByBuyer(t *testing.T) {
 	})
 }
 
+func TestServiceCountPurchasesByPeriod(t *testing.T) {
+	t.Run("Should return the count of each period", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		periods := []domain.ReportPeriod{{Period: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), Count: 3}}
+		repository.On("CountPurchasesByPeriod", (*int)(nil), domain.PeriodMonth, domain.DateRange{}).Return(periods)
+
+		result, err := service.CountPurchasesByPeriod(nil, domain.PeriodMonth, domain.DateRange{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, periods, result)
+	})
+
+	t.Run("Should return not found error when the id does not exist", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		id := 1
+		var found *domain.Buyer
+		repository.On("Get", id).Return(found)
+
+		result, err := service.CountPurchasesByPeriod(&id, domain.PeriodMonth, domain.DateRange{})
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
 func CreateService(t *testing.T) (buyer.Service, *mocks.Repository) {
 	t.Helper()
 	repository := new(mocks.Repository)
@@ -23,3 +23,8 @@ func (r *Repository) Get(id int) *domain.Carrier {
 	args := r.Called(id)
 	return args.Get(0).(*domain.Carrier)
 }
+
+func (r *Repository) GetMany(ids []int) []domain.Carrier {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.Carrier)
+}
@@ -4,16 +4,19 @@ import (
 	"database/sql"
 	"errors"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetQuery    = "SELECT * FROM carriers WHERE id=?"
-	ExistsQuery = "SELECT cid FROM carriers WHERE cid=?"
-	InsertQuery = "INSERT INTO carriers(cid,company_name,address,telephone,locality_id) VALUES (?,?,?,?,?)"
+	GetQuery     = "SELECT * FROM carriers WHERE id=?"
+	GetManyQuery = "SELECT * FROM carriers WHERE id IN (%s)"
+	ExistsQuery  = "SELECT cid FROM carriers WHERE cid=?"
+	InsertQuery  = "INSERT INTO carriers(cid,company_name,address,telephone,locality_id) VALUES (?,?,?,?,?)"
 )
 
 type Repository interface {
 	Get(id int) *domain.Carrier
+	GetMany(ids []int) []domain.Carrier
 	Save(c domain.Carrier) int
 	Exists(cid string) bool
 }
@@ -43,6 +46,30 @@ func (r *repository) Get(id int) *domain.Carrier {
 	return &c
 }
 
+// GetMany returns the carriers found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.Carrier {
+	carriers := make([]domain.Carrier, 0, len(ids))
+	if len(ids) == 0 {
+		return carriers
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c := domain.Carrier{}
+		if err := rows.Scan(&c.ID, &c.CID, &c.CompanyName, &c.Address, &c.Telephone, &c.LocalityID); err != nil {
+			panic(err)
+		}
+		carriers = append(carriers, c)
+	}
+	return carriers
+}
+
 func (r *repository) Save(c domain.Carrier) int {
 	stmt, err := r.db.Prepare(InsertQuery)
 	if err != nil {
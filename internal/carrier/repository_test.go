@@ -5,6 +5,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/carrier"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 	"github.com/stretchr/testify/assert"
 	"regexp"
 	"testing"
@@ -193,6 +194,46 @@ func TestRepositorySave(t *testing.T) {
 	})
 }
 
+func TestRepositoryGetMany(t *testing.T) {
+	t.Run("Should return the carriers found among the ids with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(carrier.GetManyQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "cid", "company_name", "address", "telephone", "locality_id"}).
+			AddRow(2, "CID-2", "Meli", "Street 1", "111", 1).
+			AddRow(5, "CID-5", "Meli", "Street 2", "222", 1)
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := carrier.NewRepository(db)
+		result := repository.GetMany([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := carrier.NewRepository(db)
+		result := repository.GetMany(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(carrier.GetManyQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := carrier.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetMany([]int{2}) })
+	})
+}
+
 func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
 	t.Helper()
 
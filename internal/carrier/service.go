@@ -7,9 +7,9 @@ import (
 )
 
 const (
-	ResourceNotFound      = "transportadora não encontrada com o id %d"
-	LocalityNotFound      = "localidade não encontrada com o id %d"
-	ResourceAlreadyExists = "uma transportadora com cid '%s' já existe"
+	ResourceNotFound      = "carrier.not_found"
+	LocalityNotFound      = "locality.not_found"
+	ResourceAlreadyExists = "carrier.already_exists"
 )
 
 type Service interface {
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Repository struct {
+	mock.Mock
+}
+
+func (r *Repository) Create(sectionID int, employeeID *int) int {
+	args := r.Called(sectionID, employeeID)
+	return args.Int(0)
+}
+
+func (r *Repository) Get(id int) *domain.CycleCount {
+	args := r.Called(id)
+	return args.Get(0).(*domain.CycleCount)
+}
+
+func (r *Repository) Assign(id int, employeeID int) bool {
+	args := r.Called(id, employeeID)
+	return args.Bool(0)
+}
+
+func (r *Repository) Submit(id int, entries []domain.CycleCountEntry) bool {
+	args := r.Called(id, entries)
+	return args.Bool(0)
+}
+
+func (r *Repository) Approve(id int, reviewerID int) error {
+	args := r.Called(id, reviewerID)
+	return args.Error(0)
+}
+
+func (r *Repository) Reject(id int, reviewerID int) error {
+	args := r.Called(id, reviewerID)
+	return args.Error(0)
+}
+
+func (r *Repository) VarianceReport(warehouseID *int) []domain.CycleCountVariance {
+	args := r.Called(warehouseID)
+	return args.Get(0).([]domain.CycleCountVariance)
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Service struct {
+	mock.Mock
+}
+
+func (s *Service) Create(sectionID int, employeeID *int) (*domain.CycleCount, error) {
+	args := s.Called(sectionID, employeeID)
+	return args.Get(0).(*domain.CycleCount), args.Error(1)
+}
+
+func (s *Service) Get(id int) (*domain.CycleCount, error) {
+	args := s.Called(id)
+	return args.Get(0).(*domain.CycleCount), args.Error(1)
+}
+
+func (s *Service) Assign(id int, employeeID int) (*domain.CycleCount, error) {
+	args := s.Called(id, employeeID)
+	return args.Get(0).(*domain.CycleCount), args.Error(1)
+}
+
+func (s *Service) Submit(id int, employeeID int, entries []domain.CycleCountEntry) (*domain.CycleCount, error) {
+	args := s.Called(id, employeeID, entries)
+	return args.Get(0).(*domain.CycleCount), args.Error(1)
+}
+
+func (s *Service) Approve(id int, reviewerID int) (*domain.CycleCount, error) {
+	args := s.Called(id, reviewerID)
+	return args.Get(0).(*domain.CycleCount), args.Error(1)
+}
+
+func (s *Service) Reject(id int, reviewerID int) (*domain.CycleCount, error) {
+	args := s.Called(id, reviewerID)
+	return args.Get(0).(*domain.CycleCount), args.Error(1)
+}
+
+func (s *Service) VarianceReport(warehouseID *int) []domain.CycleCountVariance {
+	args := s.Called(warehouseID)
+	return args.Get(0).([]domain.CycleCountVariance)
+}
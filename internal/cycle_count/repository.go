@@ -0,0 +1,374 @@
+package cycle_count
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+)
+
+const (
+	InsertQuery          = "INSERT INTO cycle_counts (section_id, employee_id, status, created_at) VALUES (?, ?, ?, ?)"
+	SectionBatchesQuery  = "SELECT id, current_quantity FROM product_batches WHERE section_id = ? AND status <> ? ORDER BY id"
+	InsertLineQuery      = "INSERT INTO cycle_count_lines (cycle_count_id, product_batch_id, expected_quantity) VALUES (?, ?, ?)"
+	GetQuery             = "SELECT id, section_id, employee_id, status, reviewed_by, created_at, submitted_at, reviewed_at FROM cycle_counts WHERE id = ?"
+	GetLinesQuery        = "SELECT id, product_batch_id, expected_quantity, counted_quantity FROM cycle_count_lines WHERE cycle_count_id = ? ORDER BY product_batch_id"
+	AssignQuery          = "UPDATE cycle_counts SET employee_id = ? WHERE id = ? AND status = ?"
+	CountLineQuery       = "UPDATE cycle_count_lines SET counted_quantity = ? WHERE cycle_count_id = ? AND product_batch_id = ?"
+	LockLineBatchesQuery = "SELECT pb.id, pb.current_quantity FROM product_batches pb INNER JOIN cycle_count_lines ccl ON ccl.product_batch_id = pb.id WHERE ccl.cycle_count_id = ? ORDER BY pb.id FOR UPDATE"
+	ExpectLineQuery      = "UPDATE cycle_count_lines SET expected_quantity = ? WHERE cycle_count_id = ? AND product_batch_id = ?"
+	SubmitQuery          = "UPDATE cycle_counts SET status = ?, submitted_at = ? WHERE id = ? AND status = ?"
+	LockQuery            = "SELECT status FROM cycle_counts WHERE id = ? FOR UPDATE"
+	VarianceLinesQuery   = "SELECT product_batch_id, counted_quantity - expected_quantity FROM cycle_count_lines WHERE cycle_count_id = ? AND counted_quantity <> expected_quantity ORDER BY product_batch_id"
+	AdjustBatchQuery     = "UPDATE product_batches SET current_quantity = current_quantity + ? WHERE id = ?"
+	ReviewQuery          = "UPDATE cycle_counts SET status = ?, reviewed_by = ?, reviewed_at = ? WHERE id = ?"
+	VarianceReportQuery  = `SELECT s.warehouse_id, COUNT(DISTINCT cc.id), COUNT(ccl.id),
+	COALESCE(SUM(ccl.expected_quantity), 0), COALESCE(SUM(ccl.counted_quantity), 0),
+	COALESCE(SUM(ccl.counted_quantity - ccl.expected_quantity), 0), COALESCE(SUM(ABS(ccl.counted_quantity - ccl.expected_quantity)), 0),
+	COALESCE(SUM(CASE WHEN ccl.counted_quantity = ccl.expected_quantity THEN 1 ELSE 0 END), 0)
+	FROM cycle_counts cc
+	INNER JOIN sections s ON s.id = cc.section_id
+	INNER JOIN cycle_count_lines ccl ON ccl.cycle_count_id = cc.id
+	WHERE cc.status = ? AND (? IS NULL OR s.warehouse_id = ?)
+	GROUP BY s.warehouse_id
+	ORDER BY s.warehouse_id`
+)
+
+var (
+	// ErrInvalidStatus is returned when the count is not in the status the
+	// operation expects.
+	ErrInvalidStatus = errors.New("invalid cycle count status")
+	// ErrNegativeStock is returned when approving a count would leave a batch
+	// with a negative quantity.
+	ErrNegativeStock = errors.New("adjustment leaves negative stock")
+	// ErrReservedStock is returned when approving a count would leave a batch
+	// with less stock than is reserved for purchase orders.
+	ErrReservedStock = errors.New("adjustment leaves reserved stock uncovered")
+	// ErrSectionFull is returned when approving a count would store more
+	// units in a section than it can hold.
+	ErrSectionFull = errors.New("adjustment exceeds section capacity")
+)
+
+// adjustment is the variance of a counted batch.
+type adjustment struct {
+	batchID  int
+	variance int
+}
+
+type Repository interface {
+	Create(sectionID int, employeeID *int) int
+	Get(id int) *domain.CycleCount
+	Assign(id int, employeeID int) bool
+	Submit(id int, entries []domain.CycleCountEntry) bool
+	Approve(id int, reviewerID int) error
+	Reject(id int, reviewerID int) error
+	VarianceReport(warehouseID *int) []domain.CycleCountVariance
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// Create generates a pending count for the section with one line per batch
+// stored in it, expecting its current quantity. The expected quantities are
+// taken again when the count is submitted.
+func (r *repository) Create(sectionID int, employeeID *int) int {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(InsertQuery, sectionID, employeeID, domain.CycleCountPending, helpers.ToFormattedDateTime(time.Now().UTC()))
+	if err != nil {
+		panic(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		panic(err)
+	}
+
+	rows, err := tx.Query(SectionBatchesQuery, sectionID, domain.BatchDisposed)
+	if err != nil {
+		panic(err)
+	}
+	expected := make(map[int]int)
+	batchIDs := make([]int, 0)
+	for rows.Next() {
+		var batchID, quantity int
+		_ = rows.Scan(&batchID, &quantity)
+		expected[batchID] = quantity
+		batchIDs = append(batchIDs, batchID)
+	}
+	rows.Close()
+
+	for _, batchID := range batchIDs {
+		if _, err := tx.Exec(InsertLineQuery, id, batchID, expected[batchID]); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return int(id)
+}
+
+func (r *repository) Get(id int) *domain.CycleCount {
+	cc := domain.CycleCount{Lines: make([]domain.CycleCountLine, 0)}
+	var createdAt string
+	var submittedAt, reviewedAt *string
+
+	err := r.db.QueryRow(GetQuery, id).Scan(&cc.ID, &cc.SectionID, &cc.EmployeeID, &cc.Status, &cc.ReviewedBy, &createdAt, &submittedAt, &reviewedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		panic(err)
+	}
+	cc.CreatedAt = helpers.ToDateTime(createdAt)
+	cc.SubmittedAt = toOptionalDateTime(submittedAt)
+	cc.ReviewedAt = toOptionalDateTime(reviewedAt)
+
+	rows, err := r.db.Query(GetLinesQuery, id)
+	if err != nil {
+		panic(err)
+	}
+
+	for rows.Next() {
+		l := domain.CycleCountLine{}
+		_ = rows.Scan(&l.ID, &l.ProductBatchID, &l.ExpectedQuantity, &l.CountedQuantity)
+		if l.CountedQuantity != nil {
+			variance := *l.CountedQuantity - l.ExpectedQuantity
+			l.Variance = &variance
+		}
+		cc.Lines = append(cc.Lines, l)
+	}
+	return &cc
+}
+
+// Assign gives a pending count to the employee. It returns false when the
+// count is no longer pending.
+func (r *repository) Assign(id int, employeeID int) bool {
+	res, err := r.db.Exec(AssignQuery, employeeID, id, domain.CycleCountPending)
+	if err != nil {
+		panic(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	return affected > 0
+}
+
+// Submit stores the counted quantities and marks the count as submitted. The
+// batches are locked and their current quantities stored as expected, so the
+// variances leave out the movements made while the count was pending. It
+// returns false when the count is no longer pending.
+func (r *repository) Submit(id int, entries []domain.CycleCountEntry) bool {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(SubmitQuery, domain.CycleCountSubmitted, helpers.ToFormattedDateTime(time.Now().UTC()), id, domain.CycleCountPending)
+	if err != nil {
+		panic(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	if affected == 0 {
+		return false
+	}
+
+	rows, err := tx.Query(LockLineBatchesQuery, id)
+	if err != nil {
+		panic(err)
+	}
+	expected := make(map[int]int)
+	batchIDs := make([]int, 0)
+	for rows.Next() {
+		var batchID, quantity int
+		_ = rows.Scan(&batchID, &quantity)
+		expected[batchID] = quantity
+		batchIDs = append(batchIDs, batchID)
+	}
+	rows.Close()
+
+	for _, batchID := range batchIDs {
+		if _, err := tx.Exec(ExpectLineQuery, expected[batchID], id, batchID); err != nil {
+			panic(err)
+		}
+	}
+	for _, e := range entries {
+		if _, err := tx.Exec(CountLineQuery, e.CountedQuantity, id, e.ProductBatchID); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return true
+}
+
+// Approve posts the variance of every counted line to its batch as an
+// adjustment in the stock ledger, refreshes the capacity of the sections
+// involved and marks the count as approved, all in the same transaction.
+// Variances are measured against the quantities locked on submission, so
+// adding them to the current quantity keeps the movements made since. Units
+// added by a count must fit in the section.
+func (r *repository) Approve(id int, reviewerID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	if err := lockSubmitted(tx, id); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(VarianceLinesQuery, id)
+	if err != nil {
+		panic(err)
+	}
+	adjustments := make([]adjustment, 0)
+	for rows.Next() {
+		var a adjustment
+		_ = rows.Scan(&a.batchID, &a.variance)
+		adjustments = append(adjustments, a)
+	}
+	rows.Close()
+
+	now := helpers.ToFormattedDateTime(time.Now().UTC())
+	sections := make([]int, 0)
+	for _, a := range adjustments {
+		batchID, variance := a.batchID, a.variance
+
+		var currentQuantity, sectionID int
+		if err := tx.QueryRow(product_batch.LockBatchQuery, batchID).Scan(&currentQuantity, &sectionID); err != nil {
+			panic(err)
+		}
+		if currentQuantity+variance < 0 {
+			return ErrNegativeStock
+		}
+		if currentQuantity+variance < product_batch.LockReserved(tx, batchID) {
+			return ErrReservedStock
+		}
+		if variance > 0 {
+			if err := product_batch.ReserveCapacity(tx, sectionID, variance); err != nil {
+				return ErrSectionFull
+			}
+		}
+		if _, err := tx.Exec(AdjustBatchQuery, variance, batchID); err != nil {
+			panic(err)
+		}
+
+		var fromSectionID, toSectionID *int
+		quantity := variance
+		if variance < 0 {
+			fromSectionID, quantity = &sectionID, -variance
+		} else {
+			toSectionID = &sectionID
+		}
+		if _, err := tx.Exec(product_batch.InsertMovementQuery, batchID, domain.MovementAdjustment, fromSectionID, toSectionID, quantity, reviewerID, nil, now); err != nil {
+			panic(err)
+		}
+		sections = appendUnique(sections, sectionID)
+	}
+
+	for _, sectionID := range sections {
+		product_batch.SyncSectionCapacity(tx, sectionID)
+	}
+	if _, err := tx.Exec(ReviewQuery, domain.CycleCountApproved, reviewerID, now, id); err != nil {
+		panic(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return nil
+}
+
+// Reject marks a submitted count as rejected without touching the stock.
+func (r *repository) Reject(id int, reviewerID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	if err := lockSubmitted(tx, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ReviewQuery, domain.CycleCountRejected, reviewerID, helpers.ToFormattedDateTime(time.Now().UTC()), id); err != nil {
+		panic(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return nil
+}
+
+func (r *repository) VarianceReport(warehouseID *int) []domain.CycleCountVariance {
+	rows, err := r.db.Query(VarianceReportQuery, domain.CycleCountApproved, warehouseID, warehouseID)
+	if err != nil {
+		panic(err)
+	}
+	report := make([]domain.CycleCountVariance, 0)
+
+	for rows.Next() {
+		v := domain.CycleCountVariance{}
+		var matched int
+		_ = rows.Scan(&v.WarehouseID, &v.Counts, &v.Lines, &v.ExpectedQuantity, &v.CountedQuantity, &v.NetVariance, &v.AbsoluteVariance, &matched)
+		if v.Lines > 0 {
+			v.Accuracy = math.Round(float64(matched)/float64(v.Lines)*10000) / 100
+		}
+		report = append(report, v)
+	}
+	return report
+}
+
+// lockSubmitted locks the count row and tells whether it awaits review.
+func lockSubmitted(tx *sql.Tx, id int) error {
+	var status string
+	if err := tx.QueryRow(LockQuery, id).Scan(&status); err != nil {
+		panic(err)
+	}
+	if status != domain.CycleCountSubmitted {
+		return ErrInvalidStatus
+	}
+	return nil
+}
+
+func toOptionalDateTime(value *string) *time.Time {
+	if value == nil {
+		return nil
+	}
+	t := helpers.ToDateTime(*value)
+	return &t
+}
+
+func appendUnique(ids []int, id int) []int {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
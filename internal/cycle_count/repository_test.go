@@ -0,0 +1,309 @@
+package cycle_count_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/cycle_count"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	countColumns = []string{"id", "section_id", "employee_id", "status", "reviewed_by", "created_at", "submitted_at", "reviewed_at"}
+	lineColumns  = []string{"id", "product_batch_id", "expected_quantity", "counted_quantity"}
+)
+
+func TestRepositoryCreate(t *testing.T) {
+	t.Run("Should create a line for each batch of the section", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		employeeID := 7
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.InsertQuery)).
+			WithArgs(1, &employeeID, domain.CycleCountPending, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.SectionBatchesQuery)).
+			WithArgs(1, domain.BatchDisposed).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "current_quantity"}).AddRow(1, 10).AddRow(2, 5))
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.InsertLineQuery)).WithArgs(3, 1, 10).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.InsertLineQuery)).WithArgs(3, 2, 5).WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.Equal(t, 3, repository.Create(1, &employeeID))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.InsertQuery)).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Create(1, nil) })
+	})
+}
+
+func TestRepositoryGet(t *testing.T) {
+	t.Run("Should return the count with the variance of the counted lines", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.GetQuery)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(countColumns).AddRow(3, 1, 7, domain.CycleCountSubmitted, nil, "2023-07-10 08:00:00.000000", "2023-07-10 09:00:00.000000", nil))
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.GetLinesQuery)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(lineColumns).AddRow(1, 1, 10, 8).AddRow(2, 2, 5, nil))
+
+		repository := cycle_count.NewRepository(db)
+		result := repository.Get(3)
+
+		counted, variance := 8, -2
+		assert.Equal(t, domain.CycleCountSubmitted, result.Status)
+		assert.Equal(t, 7, *result.EmployeeID)
+		assert.NotNil(t, result.SubmittedAt)
+		assert.Nil(t, result.ReviewedAt)
+		assert.Equal(t, []domain.CycleCountLine{
+			{ID: 1, ProductBatchID: 1, ExpectedQuantity: 10, CountedQuantity: &counted, Variance: &variance},
+			{ID: 2, ProductBatchID: 2, ExpectedQuantity: 5},
+		}, result.Lines)
+	})
+	t.Run("Should return nil when the count does not exist", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.GetQuery)).WithArgs(3).WillReturnError(sql.ErrNoRows)
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.Nil(t, repository.Get(3))
+	})
+}
+
+func TestRepositorySubmit(t *testing.T) {
+	entries := []domain.CycleCountEntry{{ProductBatchID: 1, CountedQuantity: 8}}
+
+	t.Run("Should store the counted quantities against the locked current ones", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.SubmitQuery)).
+			WithArgs(domain.CycleCountSubmitted, sqlmock.AnyArg(), 3, domain.CycleCountPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.LockLineBatchesQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "current_quantity"}).AddRow(1, 7))
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.ExpectLineQuery)).WithArgs(7, 3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.CountLineQuery)).WithArgs(8, 3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.True(t, repository.Submit(3, entries))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return false when the count is no longer pending", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.SubmitQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.False(t, repository.Submit(3, entries))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryApprove(t *testing.T) {
+	t.Run("Should post the variances as adjustments", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		sectionID := 1
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.LockQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(domain.CycleCountSubmitted))
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.VarianceLinesQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"product_batch_id", "variance"}).AddRow(1, -2).AddRow(2, 4))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(10, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockReservedQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"reserved"}).AddRow(8))
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.AdjustBatchQuery)).WithArgs(-2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertMovementQuery)).
+			WithArgs(1, domain.MovementAdjustment, &sectionID, nil, 2, 9, nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchQuery)).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(5, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockReservedQuery)).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"reserved"}).AddRow(0))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockSectionQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"maximum_capacity"}).AddRow(100))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.SectionUsageQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"used"}).AddRow(13))
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.AdjustBatchQuery)).WithArgs(4, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertMovementQuery)).
+			WithArgs(2, domain.MovementAdjustment, nil, &sectionID, 4, 9, nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.SyncSectionCapacityQuery)).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.SectionCapacityQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "current_capacity", "maximum_capacity"}).AddRow(1, 12, 100))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.InsertQuery)).
+			WithArgs(domain.EventSectionCapacityChanged, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.ReviewQuery)).
+			WithArgs(domain.CycleCountApproved, 9, sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.NoError(t, repository.Approve(3, 9))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return error when the count is not submitted", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.LockQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(domain.CycleCountApproved))
+		mock.ExpectRollback()
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.ErrorIs(t, repository.Approve(3, 9), cycle_count.ErrInvalidStatus)
+	})
+	t.Run("Should return error when the adjustment leaves negative stock", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.LockQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(domain.CycleCountSubmitted))
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.VarianceLinesQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"product_batch_id", "variance"}).AddRow(1, -6))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(4, 1))
+		mock.ExpectRollback()
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.ErrorIs(t, repository.Approve(3, 9), cycle_count.ErrNegativeStock)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return error when the adjustment takes reserved stock", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.LockQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(domain.CycleCountSubmitted))
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.VarianceLinesQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"product_batch_id", "variance"}).AddRow(1, -3))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(10, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockReservedQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"reserved"}).AddRow(8))
+		mock.ExpectRollback()
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.ErrorIs(t, repository.Approve(3, 9), cycle_count.ErrReservedStock)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return error when the adjustment does not fit in the section", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.LockQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(domain.CycleCountSubmitted))
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.VarianceLinesQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"product_batch_id", "variance"}).AddRow(1, 5))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(10, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockReservedQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"reserved"}).AddRow(0))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockSectionQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"maximum_capacity"}).AddRow(100))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.SectionUsageQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"used"}).AddRow(98))
+		mock.ExpectRollback()
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.ErrorIs(t, repository.Approve(3, 9), cycle_count.ErrSectionFull)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryReject(t *testing.T) {
+	t.Run("Should mark the count as rejected", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.LockQuery)).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(domain.CycleCountSubmitted))
+		mock.ExpectExec(regexp.QuoteMeta(cycle_count.ReviewQuery)).
+			WithArgs(domain.CycleCountRejected, 9, sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.NoError(t, repository.Reject(3, 9))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryVarianceReport(t *testing.T) {
+	t.Run("Should return the variance and accuracy by warehouse", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		var warehouseID *int
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.VarianceReportQuery)).
+			WithArgs(domain.CycleCountApproved, warehouseID, warehouseID).
+			WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "counts", "lines", "expected", "counted", "net", "absolute", "matched"}).
+				AddRow(1, 2, 3, 30, 28, -2, 6, 1))
+
+		repository := cycle_count.NewRepository(db)
+
+		assert.Equal(t, []domain.CycleCountVariance{
+			{WarehouseID: 1, Counts: 2, Lines: 3, ExpectedQuantity: 30, CountedQuantity: 28, NetVariance: -2, AbsoluteVariance: 6, Accuracy: 33.33},
+		}, repository.VarianceReport(warehouseID))
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(cycle_count.VarianceReportQuery)).WillReturnError(sql.ErrConnDone)
+		repository := cycle_count.NewRepository(db)
+
+		assert.Panics(t, func() { repository.VarianceReport(nil) })
+	})
+}
+
+func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	return db, mock
+}
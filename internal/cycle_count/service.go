@@ -0,0 +1,217 @@
+package cycle_count
+
+import (
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+)
+
+const (
+	ResourceNotFound       = "cycle_count.not_found"
+	SectionNotFound        = "section.not_found"
+	EmployeeNotFound       = "employee.not_found"
+	EmployeeOtherWarehouse = "cycle_count.employee_other_warehouse"
+	InvalidStatus          = "cycle_count.invalid_status"
+	NotAssignedTo          = "cycle_count.not_assigned_to"
+	UnknownBatch           = "cycle_count.unknown_batch"
+	IncompleteCount        = "cycle_count.incomplete"
+	SelfReview             = "cycle_count.self_review"
+	NotSupervisor          = "cycle_count.not_supervisor"
+	NegativeStock          = "cycle_count.negative_stock"
+	ReservedStock          = "cycle_count.reserved_stock"
+	SectionFull            = "cycle_count.section_full"
+)
+
+type Service interface {
+	Create(sectionID int, employeeID *int) (*domain.CycleCount, error)
+	Get(id int) (*domain.CycleCount, error)
+	Assign(id int, employeeID int) (*domain.CycleCount, error)
+	Submit(id int, employeeID int, entries []domain.CycleCountEntry) (*domain.CycleCount, error)
+	Approve(id int, reviewerID int) (*domain.CycleCount, error)
+	Reject(id int, reviewerID int) (*domain.CycleCount, error)
+	VarianceReport(warehouseID *int) []domain.CycleCountVariance
+}
+
+type service struct {
+	repository         Repository
+	sectionRepository  section.Repository
+	employeeRepository employee.Repository
+}
+
+func NewService(repository Repository, sectionRepository section.Repository, employeeRepository employee.Repository) Service {
+	return &service{
+		repository,
+		sectionRepository,
+		employeeRepository,
+	}
+}
+
+// Create generates a count of the section, optionally assigned to an
+// employee of its warehouse.
+func (s *service) Create(sectionID int, employeeID *int) (*domain.CycleCount, error) {
+	sectionFound := s.sectionRepository.Get(sectionID)
+
+	if sectionFound == nil {
+		return nil, apperr.NewResourceNotFound(SectionNotFound, sectionID)
+	}
+
+	if employeeID != nil {
+		if _, err := s.checkEmployee(*employeeID, *sectionFound); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repository.Get(s.repository.Create(sectionID, employeeID)), nil
+}
+
+func (s *service) Get(id int) (*domain.CycleCount, error) {
+	countFound := s.repository.Get(id)
+
+	if countFound == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	}
+
+	return countFound, nil
+}
+
+func (s *service) Assign(id int, employeeID int) (*domain.CycleCount, error) {
+	countFound, err := s.getWithStatus(id, domain.CycleCountPending)
+	if err != nil {
+		return nil, err
+	}
+
+	sectionFound := s.sectionRepository.Get(countFound.SectionID)
+	if sectionFound == nil {
+		return nil, apperr.NewDependentResourceNotFound(SectionNotFound, countFound.SectionID)
+	}
+
+	if _, err := s.checkEmployee(employeeID, *sectionFound); err != nil {
+		return nil, err
+	}
+
+	if !s.repository.Assign(id, employeeID) {
+		return nil, apperr.NewIncompatibleResource(InvalidStatus, id, countFound.Status)
+	}
+
+	return s.repository.Get(id), nil
+}
+
+// Submit stores the quantities counted by the assigned employee. Every batch
+// of the count must be counted once.
+func (s *service) Submit(id int, employeeID int, entries []domain.CycleCountEntry) (*domain.CycleCount, error) {
+	countFound, err := s.getWithStatus(id, domain.CycleCountPending)
+	if err != nil {
+		return nil, err
+	}
+
+	if countFound.EmployeeID == nil || *countFound.EmployeeID != employeeID {
+		return nil, apperr.NewIncompatibleResource(NotAssignedTo, id, employeeID)
+	}
+
+	expected := make(map[int]bool, len(countFound.Lines))
+	for _, l := range countFound.Lines {
+		expected[l.ProductBatchID] = true
+	}
+	counted := make(map[int]bool, len(entries))
+	for _, e := range entries {
+		if !expected[e.ProductBatchID] || counted[e.ProductBatchID] {
+			return nil, apperr.NewIncompatibleResource(UnknownBatch, id, e.ProductBatchID)
+		}
+		counted[e.ProductBatchID] = true
+	}
+	if len(counted) != len(expected) {
+		return nil, apperr.NewIncompatibleResource(IncompleteCount, id, len(expected)-len(counted))
+	}
+
+	if !s.repository.Submit(id, entries) {
+		return nil, apperr.NewIncompatibleResource(InvalidStatus, id, countFound.Status)
+	}
+
+	return s.repository.Get(id), nil
+}
+
+// Approve posts the variances of a submitted count to the stock ledger. The
+// reviewer must be a supervisor of the warehouse of the section other than
+// the employee who counted.
+func (s *service) Approve(id int, reviewerID int) (*domain.CycleCount, error) {
+	return s.review(id, reviewerID, s.repository.Approve)
+}
+
+func (s *service) Reject(id int, reviewerID int) (*domain.CycleCount, error) {
+	return s.review(id, reviewerID, s.repository.Reject)
+}
+
+func (s *service) VarianceReport(warehouseID *int) []domain.CycleCountVariance {
+	return s.repository.VarianceReport(warehouseID)
+}
+
+func (s *service) review(id int, reviewerID int, apply func(id int, reviewerID int) error) (*domain.CycleCount, error) {
+	countFound, err := s.getWithStatus(id, domain.CycleCountSubmitted)
+	if err != nil {
+		return nil, err
+	}
+
+	sectionFound := s.sectionRepository.Get(countFound.SectionID)
+	if sectionFound == nil {
+		return nil, apperr.NewDependentResourceNotFound(SectionNotFound, countFound.SectionID)
+	}
+
+	reviewerFound, err := s.checkEmployee(reviewerID, *sectionFound)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reviewerFound.IsSupervisor {
+		return nil, apperr.NewIncompatibleResource(NotSupervisor, id, reviewerID)
+	}
+
+	if countFound.EmployeeID != nil && *countFound.EmployeeID == reviewerID {
+		return nil, apperr.NewIncompatibleResource(SelfReview, id, reviewerID)
+	}
+
+	err = apply(id, reviewerID)
+	if errors.Is(err, ErrInvalidStatus) {
+		return nil, apperr.NewIncompatibleResource(InvalidStatus, id, countFound.Status)
+	}
+	if errors.Is(err, ErrNegativeStock) {
+		return nil, apperr.NewIncompatibleResource(NegativeStock, id)
+	}
+	if errors.Is(err, ErrReservedStock) {
+		return nil, apperr.NewIncompatibleResource(ReservedStock, id)
+	}
+	if errors.Is(err, ErrSectionFull) {
+		return nil, apperr.NewIncompatibleResource(SectionFull, id, countFound.SectionID)
+	}
+
+	return s.repository.Get(id), nil
+}
+
+func (s *service) getWithStatus(id int, status string) (*domain.CycleCount, error) {
+	countFound := s.repository.Get(id)
+
+	if countFound == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	}
+
+	if countFound.Status != status {
+		return nil, apperr.NewIncompatibleResource(InvalidStatus, id, countFound.Status)
+	}
+
+	return countFound, nil
+}
+
+func (s *service) checkEmployee(employeeID int, sectionFound domain.Section) (*domain.Employee, error) {
+	employeeFound := s.employeeRepository.Get(employeeID)
+
+	if employeeFound == nil {
+		return nil, apperr.NewDependentResourceNotFound(EmployeeNotFound, employeeID)
+	}
+
+	if employeeFound.WarehouseID != sectionFound.WarehouseID {
+		return nil, apperr.NewIncompatibleResource(EmployeeOtherWarehouse, employeeID, employeeFound.WarehouseID, sectionFound.ID, sectionFound.WarehouseID)
+	}
+	return employeeFound, nil
+}
@@ -0,0 +1,271 @@
+package cycle_count_test
+
+import (
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/cycle_count"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/cycle_count/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	employee_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee/mocks"
+	section_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	countedSection = domain.Section{ID: 1, WarehouseID: 2}
+	counter        = domain.Employee{ID: 7, WarehouseID: 2}
+	supervisor     = domain.Employee{ID: 9, WarehouseID: 2, IsSupervisor: true}
+)
+
+func pendingCount() *domain.CycleCount {
+	employeeID := 7
+	return &domain.CycleCount{
+		ID:         3,
+		SectionID:  1,
+		EmployeeID: &employeeID,
+		Status:     domain.CycleCountPending,
+		Lines: []domain.CycleCountLine{
+			{ID: 1, ProductBatchID: 1, ExpectedQuantity: 10},
+			{ID: 2, ProductBatchID: 2, ExpectedQuantity: 5},
+		},
+	}
+}
+
+func submittedCount() *domain.CycleCount {
+	count := pendingCount()
+	count.Status = domain.CycleCountSubmitted
+	return count
+}
+
+func TestServiceCreate(t *testing.T) {
+	t.Run("Should create the count assigned to the employee", func(t *testing.T) {
+		service, repository, sectionRepository, employeeRepository := CreateService(t)
+
+		employeeID := 7
+		sectionRepository.On("Get", 1).Return(&countedSection)
+		employeeRepository.On("Get", 7).Return(&counter)
+		repository.On("Create", 1, &employeeID).Return(3)
+		repository.On("Get", 3).Return(pendingCount())
+
+		result, err := service.Create(1, &employeeID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, pendingCount(), result)
+	})
+	t.Run("Should return not found error when the section does not exist", func(t *testing.T) {
+		service, _, sectionRepository, _ := CreateService(t)
+
+		var sectionFound *domain.Section
+		sectionRepository.On("Get", 1).Return(sectionFound)
+
+		result, err := service.Create(1, nil)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+	t.Run("Should return conflict error when the employee works in another warehouse", func(t *testing.T) {
+		service, _, sectionRepository, employeeRepository := CreateService(t)
+
+		employeeID := 8
+		sectionRepository.On("Get", 1).Return(&countedSection)
+		employeeRepository.On("Get", 8).Return(&domain.Employee{ID: 8, WarehouseID: 5})
+
+		result, err := service.Create(1, &employeeID)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+}
+
+func TestServiceSubmit(t *testing.T) {
+	entries := []domain.CycleCountEntry{{ProductBatchID: 1, CountedQuantity: 8}, {ProductBatchID: 2, CountedQuantity: 5}}
+
+	t.Run("Should store the counted quantities", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		repository.On("Get", 3).Return(pendingCount()).Once()
+		repository.On("Submit", 3, entries).Return(true)
+		repository.On("Get", 3).Return(submittedCount())
+
+		result, err := service.Submit(3, 7, entries)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CycleCountSubmitted, result.Status)
+	})
+	t.Run("Should return conflict error when the count is assigned to another employee", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		repository.On("Get", 3).Return(pendingCount())
+
+		result, err := service.Submit(3, 8, entries)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return conflict error when a batch is not part of the count", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		repository.On("Get", 3).Return(pendingCount())
+
+		result, err := service.Submit(3, 7, []domain.CycleCountEntry{{ProductBatchID: 1}, {ProductBatchID: 9}})
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return conflict error when a batch is missing", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		repository.On("Get", 3).Return(pendingCount())
+
+		result, err := service.Submit(3, 7, entries[:1])
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		repository.AssertNotCalled(t, "Submit", 3, entries[:1])
+	})
+	t.Run("Should return not found error when the count does not exist", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		var countFound *domain.CycleCount
+		repository.On("Get", 3).Return(countFound)
+
+		result, err := service.Submit(3, 7, entries)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func TestServiceApprove(t *testing.T) {
+	t.Run("Should approve the count", func(t *testing.T) {
+		service, repository, sectionRepository, employeeRepository := CreateService(t)
+
+		approved := submittedCount()
+		approved.Status = domain.CycleCountApproved
+		repository.On("Get", 3).Return(submittedCount()).Once()
+		sectionRepository.On("Get", 1).Return(&countedSection)
+		employeeRepository.On("Get", 9).Return(&supervisor)
+		repository.On("Approve", 3, 9).Return(nil)
+		repository.On("Get", 3).Return(approved)
+
+		result, err := service.Approve(3, 9)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CycleCountApproved, result.Status)
+	})
+	t.Run("Should return conflict error when the counter reviews the count", func(t *testing.T) {
+		service, repository, sectionRepository, employeeRepository := CreateService(t)
+
+		counterSupervisor := counter
+		counterSupervisor.IsSupervisor = true
+		repository.On("Get", 3).Return(submittedCount())
+		sectionRepository.On("Get", 1).Return(&countedSection)
+		employeeRepository.On("Get", 7).Return(&counterSupervisor)
+
+		result, err := service.Approve(3, 7)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return conflict error when the reviewer is not a supervisor", func(t *testing.T) {
+		service, repository, sectionRepository, employeeRepository := CreateService(t)
+
+		repository.On("Get", 3).Return(submittedCount())
+		sectionRepository.On("Get", 1).Return(&countedSection)
+		employeeRepository.On("Get", 9).Return(&domain.Employee{ID: 9, WarehouseID: 2})
+
+		result, err := service.Approve(3, 9)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		repository.AssertNotCalled(t, "Approve", 3, 9)
+	})
+	t.Run("Should return conflict error when the reviewer works in another warehouse", func(t *testing.T) {
+		service, repository, sectionRepository, employeeRepository := CreateService(t)
+
+		otherSupervisor := supervisor
+		otherSupervisor.WarehouseID = 5
+		repository.On("Get", 3).Return(submittedCount())
+		sectionRepository.On("Get", 1).Return(&countedSection)
+		employeeRepository.On("Get", 9).Return(&otherSupervisor)
+
+		result, err := service.Approve(3, 9)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		repository.AssertNotCalled(t, "Approve", 3, 9)
+	})
+	t.Run("Should return conflict error when the count is not submitted", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		repository.On("Get", 3).Return(pendingCount())
+
+		result, err := service.Approve(3, 9)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return conflict error when the reviewer does not exist", func(t *testing.T) {
+		service, repository, sectionRepository, employeeRepository := CreateService(t)
+
+		var employeeFound *domain.Employee
+		repository.On("Get", 3).Return(submittedCount())
+		sectionRepository.On("Get", 1).Return(&countedSection)
+		employeeRepository.On("Get", 9).Return(employeeFound)
+
+		result, err := service.Approve(3, 9)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
+	})
+	t.Run("Should return conflict error when the adjustment leaves negative stock", func(t *testing.T) {
+		service, repository, sectionRepository, employeeRepository := CreateService(t)
+
+		repository.On("Get", 3).Return(submittedCount())
+		sectionRepository.On("Get", 1).Return(&countedSection)
+		employeeRepository.On("Get", 9).Return(&supervisor)
+		repository.On("Approve", 3, 9).Return(cycle_count.ErrNegativeStock)
+
+		result, err := service.Approve(3, 9)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return conflict error when the adjustment takes reserved stock", func(t *testing.T) {
+		service, repository, sectionRepository, employeeRepository := CreateService(t)
+
+		repository.On("Get", 3).Return(submittedCount())
+		sectionRepository.On("Get", 1).Return(&countedSection)
+		employeeRepository.On("Get", 9).Return(&supervisor)
+		repository.On("Approve", 3, 9).Return(cycle_count.ErrReservedStock)
+
+		result, err := service.Approve(3, 9)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return conflict error when the adjustment does not fit in the section", func(t *testing.T) {
+		service, repository, sectionRepository, employeeRepository := CreateService(t)
+
+		repository.On("Get", 3).Return(submittedCount())
+		sectionRepository.On("Get", 1).Return(&countedSection)
+		employeeRepository.On("Get", 9).Return(&supervisor)
+		repository.On("Approve", 3, 9).Return(cycle_count.ErrSectionFull)
+
+		result, err := service.Approve(3, 9)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+}
+
+func CreateService(t *testing.T) (cycle_count.Service, *mocks.Repository, *section_mocks.Repository, *employee_mocks.Repository) {
+	t.Helper()
+	repository := new(mocks.Repository)
+	sectionRepository := new(section_mocks.Repository)
+	employeeRepository := new(employee_mocks.Repository)
+	service := cycle_count.NewService(repository, sectionRepository, employeeRepository)
+
+	return service, repository, sectionRepository, employeeRepository
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// StockReservation holds quantity of a batch for a purchase order until it is
+// picked or the order is cancelled. Picked reservations are kept, with the
+// date of the pick, so the order can still be traced to the batch.
+type StockReservation struct {
+	ID              int `json:"id"`
+	PurchaseOrderID int `json:"purchase_order_id"`
+	ProductBatchID  int `json:"product_batch_id"`
+	Quantity        int `json:"quantity"`
+}
+
+// AllocationCandidate is a batch that can serve an order together with the
+// quantity not yet reserved by other orders.
+type AllocationCandidate struct {
+	ProductBatchID int
+	Available      int
+}
+
+type PickListLine struct {
+	ProductBatchID int        `json:"product_batch_id"`
+	BatchNumber    int        `json:"batch_number"`
+	SectionID      int        `json:"section_id"`
+	SectionNumber  int        `json:"section_number"`
+	DueDate        time.Time  `json:"due_date"`
+	Quantity       int        `json:"quantity"`
+	PickedAt       *time.Time `json:"picked_at"`
+}
+
+// PickList tells where to pick the quantity reserved for a purchase order.
+// Short is the quantity that could not be reserved.
+type PickList struct {
+	PurchaseOrderID int            `json:"purchase_order_id"`
+	Requested       int            `json:"requested"`
+	Reserved        int            `json:"reserved"`
+	Short           int            `json:"short"`
+	Lines           []PickListLine `json:"lines"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// Statuses of a cycle count. A count is pending until the assigned employee
+// submits the counted quantities, then a supervisor approves it, posting the
+// variances to the stock ledger, or rejects it.
+const (
+	CycleCountPending   = "pending"
+	CycleCountSubmitted = "submitted"
+	CycleCountApproved  = "approved"
+	CycleCountRejected  = "rejected"
+)
+
+type CycleCount struct {
+	ID          int              `json:"id"`
+	SectionID   int              `json:"section_id"`
+	EmployeeID  *int             `json:"employee_id"`
+	Status      string           `json:"status"`
+	ReviewedBy  *int             `json:"reviewed_by"`
+	CreatedAt   time.Time        `json:"created_at"`
+	SubmittedAt *time.Time       `json:"submitted_at"`
+	ReviewedAt  *time.Time       `json:"reviewed_at"`
+	Lines       []CycleCountLine `json:"lines"`
+}
+
+// CycleCountLine holds the quantity of a batch expected when the count was
+// generated and, once submitted, the quantity counted and their difference.
+type CycleCountLine struct {
+	ID               int  `json:"id"`
+	ProductBatchID   int  `json:"product_batch_id"`
+	ExpectedQuantity int  `json:"expected_quantity"`
+	CountedQuantity  *int `json:"counted_quantity"`
+	Variance         *int `json:"variance"`
+}
+
+type CycleCountEntry struct {
+	ProductBatchID  int
+	CountedQuantity int
+}
+
+// CycleCountVariance sums the approved counts of a warehouse. Accuracy is the
+// percentage of counted lines without variance.
+type CycleCountVariance struct {
+	WarehouseID      int     `json:"warehouse_id"`
+	Counts           int     `json:"counts"`
+	Lines            int     `json:"lines"`
+	ExpectedQuantity int     `json:"expected_quantity"`
+	CountedQuantity  int     `json:"counted_quantity"`
+	NetVariance      int     `json:"net_variance"`
+	AbsoluteVariance int     `json:"absolute_variance"`
+	Accuracy         float64 `json:"accuracy"`
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types of the domain events written to the outbox. The aggregate of each
+// event is the entity named first: the order, the batch or the section.
+const (
+	EventPurchaseOrderCreated       = "PurchaseOrderCreated"
+	EventPurchaseOrderStatusChanged = "PurchaseOrderStatusChanged"
+	EventInboundOrderReceived       = "InboundOrderReceived"
+	EventProductBatchCreated        = "ProductBatchCreated"
+	EventSectionTemperatureBreached = "SectionTemperatureBreached"
+	EventSectionCapacityChanged     = "SectionCapacityChanged"
+)
+
+// EventTypes lists every type of domain event.
+var EventTypes = []string{
+	EventPurchaseOrderCreated,
+	EventPurchaseOrderStatusChanged,
+	EventInboundOrderReceived,
+	EventProductBatchCreated,
+	EventSectionTemperatureBreached,
+	EventSectionCapacityChanged,
+}
+
+// Event is a change other systems can react to. Its payload is the JSON of
+// the entity as it was saved.
+type Event struct {
+	ID          int             `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID int             `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// PurchaseOrderStatusChange is the payload of PurchaseOrderStatusChanged.
+type PurchaseOrderStatusChange struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+// SectionCapacityChange is the payload of SectionCapacityChanged.
+type SectionCapacityChange struct {
+	SectionID       int `json:"section_id"`
+	WarehouseID     int `json:"warehouse_id"`
+	CurrentCapacity int `json:"current_capacity"`
+	MaximumCapacity int `json:"maximum_capacity"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// IdempotencyKey is the response stored for a key. A key is reserved before
+// its request is handled, and stays pending, without a response status, until
+// the response is stored.
+type IdempotencyKey struct {
+	Key                 string    `json:"key"`
+	RequestHash         string    `json:"request_hash"`
+	ResponseStatus      int       `json:"response_status"`
+	ResponseContentType string    `json:"response_content_type"`
+	ResponseBody        string    `json:"response_body"`
+	ExpiresAt           time.Time `json:"expires_at"`
+}
+
+// Pending reports whether the request of the key is still being handled.
+func (i IdempotencyKey) Pending() bool {
+	return i.ResponseStatus == 0
+}
@@ -0,0 +1,37 @@
+package domain
+
+// Modes of a bulk import. An all or nothing import saves its rows only when
+// every one of them is valid, a best effort import saves the valid ones.
+const (
+	ImportAllOrNothing = "all_or_nothing"
+	ImportBestEffort   = "best_effort"
+)
+
+// ImportOptions tells how to run an import. Progress, when given, is called
+// with the number of rows checked so far; the import stops without saving any
+// row as soon as it returns false.
+type ImportOptions struct {
+	DryRun   bool                   `json:"dry_run"`
+	Mode     string                 `json:"mode"`
+	Progress func(checked int) bool `json:"-"`
+}
+
+// ImportReport tells how many rows of a bulk import were imported, or would
+// be on a dry run, and why the others were rejected.
+type ImportReport struct {
+	Mode     string           `json:"mode"`
+	DryRun   bool             `json:"dry_run"`
+	Rows     int              `json:"rows"`
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	IDs      []int            `json:"ids"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// ImportRowError is a reason a row was rejected, with the line of the file it
+// was read from and the field at fault, if any.
+type ImportRowError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// Statuses of a job. A job waits in the queue until a worker runs it and then
+// succeeds or fails. Cancelling a queued job drops it at once, a running one
+// stops at its next progress report.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCanceled  = "canceled"
+)
+
+type Job struct {
+	ID              int        `json:"id"`
+	Kind            string     `json:"kind"`
+	Status          string     `json:"status"`
+	Progress        int        `json:"progress"`
+	CancelRequested bool       `json:"cancel_requested"`
+	Error           *string    `json:"error"`
+	ResultURL       *string    `json:"result_url"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+	ClaimedAt       time.Time  `json:"-"`
+	Payload         []byte     `json:"-"`
+}
+
+// Finished tells whether the job can no longer change.
+func (j Job) Finished() bool {
+	return j.Status == JobSucceeded || j.Status == JobFailed || j.Status == JobCanceled
+}
+
+// JobResult is the file produced by a succeeded job.
+type JobResult struct {
+	ContentType string
+	Content     []byte
+}
@@ -0,0 +1,104 @@
+package domain
+
+import "time"
+
+// LotTrace follows a batch from its supplier to the buyers that ordered it.
+// The lot is the origin batch the batch was split from, or the batch itself,
+// plus every batch split from it by partial transfers, listed in LotBatchIDs.
+// Downstream holds the stock of the lot picked for purchase orders,
+// Reservations the stock reserved and not picked yet and Unallocated the
+// orders of its product that reserve no stock, which may have been filled
+// from the lot.
+type LotTrace struct {
+	ProductBatchID int           `json:"product_batch_id"`
+	BatchNumber    int           `json:"batch_number"`
+	Status         string        `json:"status"`
+	LotBatchIDs    []int         `json:"lot_batch_ids"`
+	Upstream       LotUpstream   `json:"upstream"`
+	Downstream     []LotShipment `json:"downstream"`
+	Reservations   []LotShipment `json:"reservations"`
+	Unallocated    []LotOrder    `json:"unallocated"`
+}
+
+// LotUpstream is the supply chain of the lot, resolved from its origin batch.
+type LotUpstream struct {
+	OriginBatchID     int          `json:"origin_batch_id"`
+	ProductID         int          `json:"product_id"`
+	ProductCode       string       `json:"product_code"`
+	SellerID          int          `json:"seller_id"`
+	SellerCompanyName string       `json:"seller_company_name"`
+	Receipts          []LotReceipt `json:"receipts"`
+}
+
+// LotReceipt is an inbound order that brought the batch into a warehouse,
+// with the employee who received it.
+type LotReceipt struct {
+	InboundOrderID    int       `json:"inbound_order_id"`
+	OrderNumber       string    `json:"order_number"`
+	OrderDate         time.Time `json:"order_date"`
+	WarehouseID       int       `json:"warehouse_id"`
+	EmployeeID        int       `json:"employee_id"`
+	EmployeeFirstName string    `json:"employee_first_name"`
+	EmployeeLastName  string    `json:"employee_last_name"`
+}
+
+// LotShipment is a purchase order holding stock of a batch of the lot. The
+// pick time is missing while the stock is only reserved.
+type LotShipment struct {
+	PurchaseOrderID    int        `json:"purchase_order_id"`
+	OrderNumber        string     `json:"order_number"`
+	OrderDate          time.Time  `json:"order_date"`
+	OrderStatus        string     `json:"order_status"`
+	TrackingCode       string     `json:"tracking_code"`
+	ProductBatchID     int        `json:"product_batch_id"`
+	Quantity           int        `json:"quantity"`
+	BuyerID            int        `json:"buyer_id"`
+	BuyerCardNumberID  string     `json:"buyer_card_number_id"`
+	BuyerFirstName     string     `json:"buyer_first_name"`
+	BuyerLastName      string     `json:"buyer_last_name"`
+	CarrierID          *int       `json:"carrier_id"`
+	CarrierCompanyName *string    `json:"carrier_company_name"`
+	PickedAt           *time.Time `json:"picked_at"`
+}
+
+// LotOrder is a purchase order of the product of the lot that reserves no
+// stock, as the ones placed before stock was reserved, so the batch it was
+// filled from is unknown.
+type LotOrder struct {
+	PurchaseOrderID    int       `json:"purchase_order_id"`
+	OrderNumber        string    `json:"order_number"`
+	OrderDate          time.Time `json:"order_date"`
+	OrderStatus        string    `json:"order_status"`
+	TrackingCode       string    `json:"tracking_code"`
+	Quantity           int       `json:"quantity"`
+	BuyerID            int       `json:"buyer_id"`
+	BuyerCardNumberID  string    `json:"buyer_card_number_id"`
+	BuyerFirstName     string    `json:"buyer_first_name"`
+	BuyerLastName      string    `json:"buyer_last_name"`
+	CarrierID          *int      `json:"carrier_id"`
+	CarrierCompanyName *string   `json:"carrier_company_name"`
+}
+
+// Recall quarantines every lot of the recalled batches. ProductBatchIDs holds
+// the batches of those lots.
+type Recall struct {
+	ID              int           `json:"id"`
+	Reason          string        `json:"reason"`
+	EmployeeID      int           `json:"employee_id"`
+	CreatedAt       time.Time     `json:"created_at"`
+	ProductBatchIDs []int         `json:"product_batch_ids"`
+	AffectedBuyers  []RecallBuyer `json:"affected_buyers"`
+}
+
+// RecallBuyer is a buyer with an order holding stock of a recalled batch.
+type RecallBuyer struct {
+	BuyerID         int    `json:"buyer_id"`
+	CardNumberID    string `json:"card_number_id"`
+	FirstName       string `json:"first_name"`
+	LastName        string `json:"last_name"`
+	PurchaseOrderID int    `json:"purchase_order_id"`
+	OrderNumber     string `json:"order_number"`
+	TrackingCode    string `json:"tracking_code"`
+	ProductBatchID  int    `json:"product_batch_id"`
+	Quantity        int    `json:"quantity"`
+}
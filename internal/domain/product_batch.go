@@ -2,6 +2,15 @@ package domain
 
 import "time"
 
+// Statuses of a product batch. Quarantined batches stay in their section but
+// are left out of allocation and stock totals until released or disposed.
+const (
+	BatchAvailable   = "available"
+	BatchQuarantined = "quarantined"
+	BatchReleased    = "released"
+	BatchDisposed    = "disposed"
+)
+
 type ProductBatch struct {
 	ID                 int       `json:"id"`
 	BatchNumber        int       `json:"batch_number"`
@@ -14,6 +23,7 @@ type ProductBatch struct {
 	MinimumTemperature float32   `json:"minimum_temperature"`
 	ProductID          int       `json:"product_id"`
 	SectionID          int       `json:"section_id"`
+	Status             string    `json:"status"`
 }
 
 type CountProductBatchesBySection struct {
@@ -21,3 +31,24 @@ type CountProductBatchesBySection struct {
 	SectionNumber int `json:"section_number"`
 	ProductsCount int `json:"products_count"`
 }
+
+type PutawaySuggestion struct {
+	SectionID       int     `json:"section_id"`
+	SectionNumber   int     `json:"section_number"`
+	FreeCapacity    int     `json:"free_capacity"`
+	ProductQuantity int     `json:"product_quantity"`
+	FillPercent     float64 `json:"fill_percent"`
+	Score           float64 `json:"score"`
+}
+
+// BatchStatusChange records who moved a batch from one status to another and
+// why.
+type BatchStatusChange struct {
+	ID             int       `json:"id"`
+	ProductBatchID int       `json:"product_batch_id"`
+	FromStatus     string    `json:"from_status"`
+	ToStatus       string    `json:"to_status"`
+	Reason         string    `json:"reason"`
+	EmployeeID     int       `json:"employee_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
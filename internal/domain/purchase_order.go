@@ -12,4 +12,5 @@ type PurchaseOrder struct {
 	ProductRecordID int       `json:"product_record_id"`
 	OrderStatusID   int       `json:"order_status_id"`
 	WarehouseID     int       `json:"warehouse_id"`
+	Quantity        int       `json:"quantity"`
 }
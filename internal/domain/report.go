@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Periods a count report can be grouped by. Weeks start on Monday.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// DateRange restricts a count report to the rows dated from From, inclusive,
+// to To, exclusive. A nil bound leaves the range open on that side.
+type DateRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// ReportPeriod counts the rows of a report dated within the period starting
+// on Period.
+type ReportPeriod struct {
+	Period time.Time `json:"period"`
+	Count  int       `json:"count"`
+}
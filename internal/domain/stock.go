@@ -0,0 +1,24 @@
+package domain
+
+// StockReport is the quantity on hand of a product in a section. The quantity
+// is broken down by how soon the batches are due.
+type StockReport struct {
+	ProductID     int    `json:"product_id"`
+	ProductCode   string `json:"product_code"`
+	WarehouseID   int    `json:"warehouse_id"`
+	SectionID     int    `json:"section_id"`
+	SectionNumber int    `json:"section_number"`
+	Quantity      int    `json:"quantity"`
+	Expired       int    `json:"expired"`
+	DueIn7Days    int    `json:"due_in_7_days"`
+	DueIn30Days   int    `json:"due_in_30_days"`
+	DueLater      int    `json:"due_later"`
+}
+
+// StockFilter narrows a stock report. Nil fields are not filtered.
+type StockFilter struct {
+	ProductID     *int
+	SellerID      *int
+	ProductTypeID *int
+	WarehouseID   *int
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// Kinds of entry recorded in the stock movement ledger.
+const (
+	MovementReceipt    = "receipt"
+	MovementTransfer   = "transfer"
+	MovementPick       = "pick"
+	MovementAdjustment = "adjustment"
+	MovementDisposal   = "disposal"
+)
+
+// StockMovement is an entry of the stock ledger. Every change to the current
+// quantity or the section of a batch is recorded as one movement. Transfers
+// that split a batch point to the batch created in the destination section
+// through TargetBatchID.
+type StockMovement struct {
+	ID             int       `json:"id"`
+	ProductBatchID int       `json:"product_batch_id"`
+	Type           string    `json:"type"`
+	FromSectionID  *int      `json:"from_section_id"`
+	ToSectionID    *int      `json:"to_section_id"`
+	Quantity       int       `json:"quantity"`
+	EmployeeID     *int      `json:"employee_id"`
+	TargetBatchID  *int      `json:"target_batch_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// StockTransfer asks to move part or all of a batch to another section.
+type StockTransfer struct {
+	ProductBatchID int
+	ToSectionID    int
+	Quantity       int
+	EmployeeID     int
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+type TemperatureReading struct {
+	ID          int       `json:"id"`
+	SectionID   int       `json:"section_id"`
+	Temperature float32   `json:"temperature"`
+	RecordedAt  time.Time `json:"recorded_at"`
+}
+
+// TemperatureBreach records a reading below the minimum temperature of a
+// section, or of a batch stored in it when ProductBatchID is set.
+type TemperatureBreach struct {
+	ID                 int       `json:"id"`
+	SectionID          int       `json:"section_id"`
+	ProductBatchID     *int      `json:"product_batch_id"`
+	Temperature        float32   `json:"temperature"`
+	MinimumTemperature float32   `json:"minimum_temperature"`
+	RecordedAt         time.Time `json:"recorded_at"`
+}
+
+// TemperatureSample summarizes the readings recorded from From during one
+// downsampling interval.
+type TemperatureSample struct {
+	From    time.Time `json:"from"`
+	Average float64   `json:"average"`
+	Minimum float32   `json:"minimum"`
+	Maximum float32   `json:"maximum"`
+	Count   int       `json:"count"`
+}
+
+type TemperatureIngestion struct {
+	Recorded int                 `json:"recorded"`
+	Breaches []TemperatureBreach `json:"breaches"`
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Warehouse struct {
 	ID                 int    `json:"id"`
@@ -10,24 +13,15 @@ type Warehouse struct {
 	MinimumCapacity    int    `json:"minimum_capacity"`
 	MinimumTemperature int    `json:"minimum_temperature"`
 	LocalityID         int    `json:"locality_id"`
+	Version            int    `json:"-"`
 }
 
-func (w *Warehouse) Overlap(updateWarehouse UpdateWarehouse) {
-	w.ID = helpers.Fill(updateWarehouse.ID, w.ID).(int)
-	w.Address = helpers.Fill(updateWarehouse.Address, w.Address).(string)
-	w.Telephone = helpers.Fill(updateWarehouse.Telephone, w.Telephone).(string)
-	w.WarehouseCode = helpers.Fill(updateWarehouse.WarehouseCode, w.WarehouseCode).(string)
-	w.MinimumCapacity = helpers.Fill(updateWarehouse.MinimumCapacity, w.MinimumCapacity).(int)
-	w.MinimumTemperature = helpers.Fill(updateWarehouse.MinimumTemperature, w.MinimumTemperature).(int)
-	w.LocalityID = helpers.Fill(updateWarehouse.LocalityID, w.LocalityID).(int)
-}
-
-type UpdateWarehouse struct {
-	ID                 *int    `json:"id"`
-	Address            *string `json:"address"`
-	Telephone          *string `json:"telephone"`
-	WarehouseCode      *string `json:"warehouse_code"`
-	MinimumCapacity    *int    `json:"minimum_capacity"`
-	MinimumTemperature *int    `json:"minimum_temperature"`
-	LocalityID         *int    `json:"locality_id"`
+// WarehouseActivity is a domain event that happened on the floor of a
+// warehouse, identified by the id of the event in the outbox.
+type WarehouseActivity struct {
+	ID          int             `json:"id"`
+	WarehouseID int             `json:"warehouse_id"`
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data"`
+	CreatedAt   time.Time       `json:"created_at"`
 }
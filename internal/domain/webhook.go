@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// Webhook is a subscription of a partner to some types of domain events,
+// which are posted to its URL signed with its secret.
+type Webhook struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	Version    int       `json:"-"`
+}
+
+// Subscribes tells whether the webhook receives the events of the type.
+func (w Webhook) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Statuses of a webhook delivery. Pending deliveries are sent when their next
+// attempt is due, until they succeed or fail for good.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is the posting of an event to a webhook, with the outcome of
+// its last attempt. The status code is missing when the request got no
+// response, and the next attempt when the delivery is not pending.
+type WebhookDelivery struct {
+	ID            int        `json:"id"`
+	WebhookID     int        `json:"webhook_id"`
+	EventID       int        `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	Status        string     `json:"status"`
+	Attempt       int        `json:"attempt"`
+	StatusCode    *int       `json:"status_code"`
+	LatencyMs     int64      `json:"latency_ms"`
+	Error         *string    `json:"error"`
+	Succeeded     bool       `json:"succeeded"`
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
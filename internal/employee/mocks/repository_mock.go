@@ -19,30 +19,51 @@ func (r *Repository) Get(id int) *domain.Employee {
 	return args.Get(0).(*domain.Employee)
 }
 
+func (r *Repository) GetMany(ids []int) []domain.Employee {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.Employee)
+}
+
 func (r *Repository) Exists(CardNumberID string) bool {
 	args := r.Called(CardNumberID)
 	return args.Get(0).(bool)
 }
 
+func (r *Repository) Existing(cardNumberIDs []string) []string {
+	args := r.Called(cardNumberIDs)
+	return args.Get(0).([]string)
+}
+
 func (r *Repository) Save(employee domain.Employee) int {
 	args := r.Called(employee)
 	return args.Get(0).(int)
 }
 
-func (r *Repository) Update(employee domain.Employee) {
-	r.Called(employee)
+func (r *Repository) SaveAll(employees []domain.Employee) []int {
+	args := r.Called(employees)
+	return args.Get(0).([]int)
+}
+
+func (r *Repository) Update(employee domain.Employee) error {
+	args := r.Called(employee)
+	return args.Error(0)
 }
 
 func (r *Repository) Delete(id int) {
 	r.Called(id)
 }
 
-func (r *Repository) CountInboundOrdersByAllEmployees() []domain.InboundOrdersByEmployee {
-	args := r.Called()
+func (r *Repository) CountInboundOrdersByAllEmployees(dates domain.DateRange) []domain.InboundOrdersByEmployee {
+	args := r.Called(dates)
 	return args.Get(0).([]domain.InboundOrdersByEmployee)
 }
 
-func (r *Repository) CountInboundOrdersByEmployee(id int) *domain.InboundOrdersByEmployee {
-	args := r.Called(id)
+func (r *Repository) CountInboundOrdersByEmployee(id int, dates domain.DateRange) *domain.InboundOrdersByEmployee {
+	args := r.Called(id, dates)
 	return args.Get(0).(*domain.InboundOrdersByEmployee)
-}
\ No newline at end of file
+}
+
+func (r *Repository) CountInboundOrdersByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod {
+	args := r.Called(id, period, dates)
+	return args.Get(0).([]domain.ReportPeriod)
+}
@@ -14,6 +14,11 @@ func (s *Service) GetAll() []domain.Employee {
 	return args.Get(0).([]domain.Employee)
 }
 
+func (s *Service) GetMany(ids []int) []domain.Employee {
+	args := s.Called(ids)
+	return args.Get(0).([]domain.Employee)
+}
+
 func (s *Service) Get(id int) (*domain.Employee, error) {
 	args := s.Called(id)
 	return args.Get(0).(*domain.Employee), args.Error(1)
@@ -24,8 +29,13 @@ func (s *Service) Create(p domain.Employee) (*domain.Employee, error) {
 	return args.Get(0).(*domain.Employee), args.Error(1)
 }
 
-func (s *Service) Update(id int, p domain.UpdateEmployee) (*domain.Employee, error) {
-	args := s.Called(id, p)
+func (s *Service) Import(employees []domain.Employee, options domain.ImportOptions) ([]int, []error) {
+	args := s.Called(employees, options)
+	return args.Get(0).([]int), args.Get(1).([]error)
+}
+
+func (s *Service) Update(current domain.Employee, p domain.Employee) (*domain.Employee, error) {
+	args := s.Called(current, p)
 	return args.Get(0).(*domain.Employee), args.Error(1)
 }
 
@@ -34,12 +44,17 @@ func (s *Service) Delete(id int) error {
 	return args.Error(0)
 }
 
-func (s *Service) CountInboundOrdersByAllEmployees() []domain.InboundOrdersByEmployee {
-	args := s.Called()
+func (s *Service) CountInboundOrdersByAllEmployees(dates domain.DateRange, top int) []domain.InboundOrdersByEmployee {
+	args := s.Called(dates, top)
 	return args.Get(0).([]domain.InboundOrdersByEmployee)
 }
 
-func (s *Service) CountInboundOrdersByEmployee(id int) (*domain.InboundOrdersByEmployee, error) {
-	args := s.Called(id)
+func (s *Service) CountInboundOrdersByEmployee(id int, dates domain.DateRange) (*domain.InboundOrdersByEmployee, error) {
+	args := s.Called(id, dates)
 	return args.Get(0).(*domain.InboundOrdersByEmployee), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (s *Service) CountInboundOrdersByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error) {
+	args := s.Called(id, period, dates)
+	return args.Get(0).([]domain.ReportPeriod), args.Error(1)
+}
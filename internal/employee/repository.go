@@ -5,38 +5,60 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetAllQuery = "SELECT id, card_number_id, first_name, last_name, warehouse_id FROM employees;"
-	GetQuery = "SELECT id, card_number_id, first_name, last_name, warehouse_id FROM employees WHERE id=?;"
+	GetAllQuery = "SELECT id, card_number_id, first_name, last_name, warehouse_id, is_supervisor FROM employees;"
+	GetQuery = "SELECT id, card_number_id, first_name, last_name, warehouse_id, is_supervisor, version FROM employees WHERE id=?;"
+	GetManyQuery = "SELECT id, card_number_id, first_name, last_name, warehouse_id, is_supervisor FROM employees WHERE id IN (%s);"
 	ExistsQuery = "SELECT card_number_id FROM employees WHERE card_number_id=?;"
-	SaveQuery = "INSERT INTO employees(card_number_id,first_name,last_name,warehouse_id) VALUES (?,?,?,?)"
-	UpdateQuery = "UPDATE employees SET card_number_id=?, first_name=?, last_name=?, warehouse_id=?  WHERE id=?"
+	ExistingQuery = "SELECT card_number_id FROM employees WHERE card_number_id IN (%s);"
+	SaveQuery = "INSERT INTO employees(card_number_id,first_name,last_name,warehouse_id,is_supervisor) VALUES (?,?,?,?,?)"
+	UpdateQuery = "UPDATE employees SET card_number_id=?, first_name=?, last_name=?, warehouse_id=?, is_supervisor=?, version=version+1 WHERE id=? AND version=?"
 	DeleteQuery = "DELETE FROM employees WHERE id=?"
 
 	CountInboundOrdersByAllEmployeesQuery = `SELECT e.id, e.card_number_id, e.first_name, e.last_name, e.warehouse_id, count(i.id) "inbound_orders_count"
 	FROM employees e
 	LEFT JOIN inbound_orders i ON e.id = i.employee_id
+	AND (? IS NULL OR i.order_date >= ?) AND (? IS NULL OR i.order_date < ?)
 	GROUP BY e.id`
 	CountInboundOrdersByEmployeeQuery = `SELECT e.id, e.card_number_id, e.first_name, e.last_name, e.warehouse_id, count(i.id) "inbound_orders_count"
 	FROM employees e
 	LEFT JOIN inbound_orders i ON e.id = i.employee_id
+	AND (? IS NULL OR i.order_date >= ?) AND (? IS NULL OR i.order_date < ?)
 	WHERE e.id=?
 	GROUP BY e.id`
+
+	CountInboundOrdersByPeriodQuery = `SELECT CASE ?
+	WHEN 'month' THEN DATE_FORMAT(i.order_date, '%Y-%m-01 00:00:00')
+	WHEN 'week' THEN DATE_FORMAT(i.order_date - INTERVAL WEEKDAY(i.order_date) DAY, '%Y-%m-%d 00:00:00')
+	ELSE DATE_FORMAT(i.order_date, '%Y-%m-%d 00:00:00') END "period", count(i.id) "inbound_orders_count"
+	FROM inbound_orders i
+	WHERE (? IS NULL OR i.employee_id = ?) AND (? IS NULL OR i.order_date >= ?) AND (? IS NULL OR i.order_date < ?)
+	GROUP BY 1
+	ORDER BY 1`
 )
 
 type Repository interface {
 	GetAll() []domain.Employee
 	Get(id int) *domain.Employee
+	GetMany(ids []int) []domain.Employee
 	Exists(cardNumberID string) bool
+	Existing(cardNumberIDs []string) []string
 	Save(p domain.Employee) int
-	Update(p domain.Employee)
+	SaveAll(employees []domain.Employee) []int
+	Update(p domain.Employee) error
 	Delete(id int)
-	CountInboundOrdersByAllEmployees() []domain.InboundOrdersByEmployee
-	CountInboundOrdersByEmployee(id int) *domain.InboundOrdersByEmployee
+	CountInboundOrdersByAllEmployees(dates domain.DateRange) []domain.InboundOrdersByEmployee
+	CountInboundOrdersByEmployee(id int, dates domain.DateRange) *domain.InboundOrdersByEmployee
+	CountInboundOrdersByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod
 }
 
+// ErrModified is returned by Update when the employee was changed or deleted
+// after the version being updated was read.
+var ErrModified = errors.New("employee modified since read")
+
 type repository struct {
 	db *sql.DB
 }
@@ -57,7 +79,7 @@ func (r *repository) GetAll() []domain.Employee{
 
 	for rows.Next() {
 		e := domain.Employee{}
-		_ = rows.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID)
+		_ = rows.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID, &e.IsSupervisor)
 		employees = append(employees, e)
 	}
 
@@ -67,7 +89,7 @@ func (r *repository) GetAll() []domain.Employee{
 func (r *repository) Get(id int) *domain.Employee {
 	row := r.db.QueryRow(GetQuery, id)
 	e := domain.Employee{}
-	err := row.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID)
+	err := row.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID, &e.IsSupervisor, &e.Version)
 	
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -79,6 +101,30 @@ func (r *repository) Get(id int) *domain.Employee {
 	return &e
 }
 
+// GetMany returns the employees found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.Employee {
+	employees := make([]domain.Employee, 0, len(ids))
+	if len(ids) == 0 {
+		return employees
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := domain.Employee{}
+		if err := rows.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID, &e.IsSupervisor); err != nil {
+			panic(err)
+		}
+		employees = append(employees, e)
+	}
+	return employees
+}
+
 func (r *repository) Exists(cardNumberID string) bool {
 	row := r.db.QueryRow(ExistsQuery, cardNumberID)
 	err := row.Scan(&cardNumberID)
@@ -86,13 +132,38 @@ func (r *repository) Exists(cardNumberID string) bool {
 	return err == nil
 }
 
+// Existing returns which of the given card numbers are already taken, looking
+// them up with a single query.
+func (r *repository) Existing(cardNumberIDs []string) []string {
+	existing := make([]string, 0, len(cardNumberIDs))
+	if len(cardNumberIDs) == 0 {
+		return existing
+	}
+
+	query, args := helpers.ExpandIn(ExistingQuery, cardNumberIDs)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cardNumberID string
+		if err := rows.Scan(&cardNumberID); err != nil {
+			panic(err)
+		}
+		existing = append(existing, cardNumberID)
+	}
+	return existing
+}
+
 func (r *repository) Save(e domain.Employee) int {
 	stmt, err := r.db.Prepare(SaveQuery)
 	if err != nil {
 		panic(err)
 	}
 
-	res, err := stmt.Exec(e.CardNumberID, e.FirstName, e.LastName, e.WarehouseID)
+	res, err := stmt.Exec(e.CardNumberID, e.FirstName, e.LastName, e.WarehouseID, e.IsSupervisor)
 	if err != nil {
 		panic(err)
 	}
@@ -105,16 +176,60 @@ func (r *repository) Save(e domain.Employee) int {
 	return int(id)
 }
 
-func (r *repository) Update(e domain.Employee) {
+// SaveAll inserts the employees in a single transaction and returns their ids
+// in the same order.
+func (r *repository) SaveAll(employees []domain.Employee) []int {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(SaveQuery)
+	if err != nil {
+		panic(err)
+	}
+	defer stmt.Close()
+
+	ids := make([]int, 0, len(employees))
+	for _, e := range employees {
+		res, err := stmt.Exec(e.CardNumberID, e.FirstName, e.LastName, e.WarehouseID, e.IsSupervisor)
+		if err != nil {
+			panic(err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			panic(err)
+		}
+		ids = append(ids, int(id))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+func (r *repository) Update(e domain.Employee) error {
 	stmt, err := r.db.Prepare(UpdateQuery)
 	if err != nil {
 		panic(err)
 	}
 
-	_, err = stmt.Exec(&e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID, &e.ID)
+	res, err := stmt.Exec(&e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID, &e.IsSupervisor, &e.ID, &e.Version)
+	if err != nil {
+		panic(err)
+	}
+
+	affected, err := res.RowsAffected()
 	if err != nil {
 		panic(err)
 	}
+	if affected == 0 {
+		return ErrModified
+	}
+	return nil
 }
 
 func (r *repository) Delete(id int) {
@@ -129,8 +244,9 @@ func (r *repository) Delete(id int) {
 	}
 }
 
-func (r *repository) CountInboundOrdersByAllEmployees() []domain.InboundOrdersByEmployee {
-	rows, err := r.db.Query(CountInboundOrdersByAllEmployeesQuery)
+func (r *repository) CountInboundOrdersByAllEmployees(dates domain.DateRange) []domain.InboundOrdersByEmployee {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows, err := r.db.Query(CountInboundOrdersByAllEmployeesQuery, from, from, to, to)
 	if err != nil {
 		panic(err)
 	}
@@ -146,8 +262,9 @@ func (r *repository) CountInboundOrdersByAllEmployees() []domain.InboundOrdersBy
 	return inboundOrdersByEmployees
 }
 
-func (r *repository) CountInboundOrdersByEmployee(id int) *domain.InboundOrdersByEmployee {
-	rows := r.db.QueryRow(CountInboundOrdersByEmployeeQuery, id)
+func (r *repository) CountInboundOrdersByEmployee(id int, dates domain.DateRange) *domain.InboundOrdersByEmployee {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows := r.db.QueryRow(CountInboundOrdersByEmployeeQuery, from, from, to, to, id)
 	e := domain.InboundOrdersByEmployee{}
 	err := rows.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID, &e.InboundOrdersCount)
 	if err != nil {
@@ -158,4 +275,26 @@ func (r *repository) CountInboundOrdersByEmployee(id int) *domain.InboundOrdersB
 	}
 
 	return &e
-}
\ No newline at end of file
+}
+
+// CountInboundOrdersByPeriod counts the inbound orders of the employee, or of
+// every employee when id is nil, by day, week or month of their order date.
+func (r *repository) CountInboundOrdersByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows, err := r.db.Query(CountInboundOrdersByPeriodQuery, period, id, id, from, from, to, to)
+	if err != nil {
+		panic(err)
+	}
+
+	periods := make([]domain.ReportPeriod, 0)
+
+	for rows.Next() {
+		rp := domain.ReportPeriod{}
+		var start string
+		_ = rows.Scan(&start, &rp.Count)
+		rp.Period = helpers.ToDateTime(start)
+		periods = append(periods, rp)
+	}
+
+	return periods
+}
@@ -4,10 +4,12 @@ import (
 	"database/sql"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -35,10 +37,10 @@ func TestRepositoryGetAll(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"id", "card_number_id", "first_name", "last_name", "warehouse_id"}
+		columns := []string{"id", "card_number_id", "first_name", "last_name", "warehouse_id", "is_supervisor"}
 		rows := sqlmock.NewRows(columns)
 		employeeId := 1
-		rows.AddRow(employeeId, "", "", "", 1)
+		rows.AddRow(employeeId, "", "", "", 1, false)
 
 		mock.ExpectQuery(employee.GetAllQuery).WillReturnRows(rows)
 
@@ -66,10 +68,10 @@ func TestRepositoryGet(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"id", "card_number_id", "first_name", "last_name", "warehouse_id"}
+		columns := []string{"id", "card_number_id", "first_name", "last_name", "warehouse_id", "is_supervisor", "version"}
 		rows := sqlmock.NewRows(columns)
 		employeeId := 1
-		rows.AddRow(employeeId, "", "", "", 1)
+		rows.AddRow(employeeId, "", "", "", 1, false, 2)
 
 		mock.ExpectQuery(allDataQuery).WithArgs(employeeId).WillReturnRows(rows)
 
@@ -78,6 +80,7 @@ func TestRepositoryGet(t *testing.T) {
 		result := repository.Get(employeeId)
 
 		assert.NotNil(t, result)
+		assert.Equal(t, 2, result.Version)
 	})
 
 	t.Run("Should not return a employee", func(t *testing.T) {
@@ -174,7 +177,7 @@ func TestRepositorySave(t *testing.T) {
 		mockedEmployee := mockedEmployeeTemplate
 		mock.ExpectPrepare(regexp.QuoteMeta(employee.SaveQuery))
 		mock.ExpectExec(regexp.QuoteMeta(employee.SaveQuery)).
-			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID).
+			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID, mockedEmployee.IsSupervisor).
 			WillReturnResult(sqlmock.NewResult(int64(lastInsertId), 1))
 
 		repository := employee.NewRepository(db)
@@ -203,7 +206,7 @@ func TestRepositorySave(t *testing.T) {
 		mockedEmployee := mockedEmployeeTemplate
 		mock.ExpectPrepare(regexp.QuoteMeta(employee.SaveQuery))
 		mock.ExpectExec(regexp.QuoteMeta(employee.SaveQuery)).
-			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID).
+			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID, mockedEmployee.IsSupervisor).
 			WillReturnError(sql.ErrConnDone)
 
 		repository := employee.NewRepository(db)
@@ -218,7 +221,7 @@ func TestRepositorySave(t *testing.T) {
 		mockedEmployee := mockedEmployeeTemplate
 		mock.ExpectPrepare(regexp.QuoteMeta(employee.SaveQuery))
 		mock.ExpectExec(regexp.QuoteMeta(employee.SaveQuery)).
-			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID).
+			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID, mockedEmployee.IsSupervisor).
 			WillReturnResult(sqlmock.NewErrorResult(sql.ErrConnDone))
 
 		repository := employee.NewRepository(db)
@@ -235,12 +238,27 @@ func TestRepositoryUpdate(t *testing.T) {
 		mockedEmployee := mockedEmployeeTemplate
 		mock.ExpectPrepare(regexp.QuoteMeta(employee.UpdateQuery))
 		mock.ExpectExec(regexp.QuoteMeta(employee.UpdateQuery)).
-			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID, mockedEmployee.ID).
+			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID, mockedEmployee.IsSupervisor, mockedEmployee.ID, mockedEmployee.Version).
 			WillReturnResult(sqlmock.NewResult(int64(mockedEmployee.ID), 1))
 
 		repository := employee.NewRepository(db)
 
-		assert.NotPanics(t, func() { repository.Update(mockedEmployee) })
+		assert.NoError(t, repository.Update(mockedEmployee))
+	})
+
+	t.Run("Should not update the employee when its version changed", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedEmployee := mockedEmployeeTemplate
+		mock.ExpectPrepare(regexp.QuoteMeta(employee.UpdateQuery))
+		mock.ExpectExec(regexp.QuoteMeta(employee.UpdateQuery)).
+			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID, mockedEmployee.IsSupervisor, mockedEmployee.ID, mockedEmployee.Version).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := employee.NewRepository(db)
+
+		assert.ErrorIs(t, repository.Update(mockedEmployee), employee.ErrModified)
 	})
 
 	t.Run("Should throw panic when expected prepare fails", func(t *testing.T) {
@@ -262,7 +280,7 @@ func TestRepositoryUpdate(t *testing.T) {
 		mockedEmployee := mockedEmployeeTemplate
 		mock.ExpectPrepare(regexp.QuoteMeta(employee.UpdateQuery))
 		mock.ExpectExec(regexp.QuoteMeta(employee.UpdateQuery)).
-			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID, mockedEmployee.ID).
+			WithArgs(mockedEmployee.CardNumberID, mockedEmployee.FirstName, mockedEmployee.LastName, mockedEmployee.WarehouseID, mockedEmployee.IsSupervisor, mockedEmployee.ID, mockedEmployee.Version).
 			WillReturnError(sql.ErrConnDone)
 
 		repository := employee.NewRepository(db)
@@ -330,7 +348,7 @@ func TestRepositoryCountInboundOrdersByAllEmployees(t *testing.T) {
 		WillReturnRows(rows)
 
 		repository := employee.NewRepository(db)
-		result := repository.CountInboundOrdersByAllEmployees()
+		result := repository.CountInboundOrdersByAllEmployees(domain.DateRange{})
 
 		assert.NotNil(t, result)
 	})
@@ -344,7 +362,7 @@ func TestRepositoryCountInboundOrdersByAllEmployees(t *testing.T) {
 
 		repository := employee.NewRepository(db)
 
-		assert.Panics(t, func() { repository.CountInboundOrdersByAllEmployees() })
+		assert.Panics(t, func() { repository.CountInboundOrdersByAllEmployees(domain.DateRange{}) })
 	})
 }
 
@@ -360,11 +378,11 @@ func TestRepositoryCountInboundOrdersByEmployee(t *testing.T) {
 		rows.AddRow(employeeId, "", "", "", 1, 1)
 
 		mock.ExpectQuery(regexp.QuoteMeta(employee.CountInboundOrdersByEmployeeQuery)).
-		WithArgs(employeeId).
+		WithArgs(nil, nil, nil, nil, employeeId).
 		WillReturnRows(rows)
 
 		repository := employee.NewRepository(db)
-		result := repository.CountInboundOrdersByEmployee(employeeId)
+		result := repository.CountInboundOrdersByEmployee(employeeId, domain.DateRange{})
 
 		assert.NotNil(t, result)
 	})
@@ -376,11 +394,11 @@ func TestRepositoryCountInboundOrdersByEmployee(t *testing.T) {
 		employeeId := 1
 
 		mock.ExpectQuery(regexp.QuoteMeta(employee.CountInboundOrdersByEmployeeQuery)).
-		WithArgs(employeeId).
+		WithArgs(nil, nil, nil, nil, employeeId).
 		WillReturnError(sql.ErrNoRows)
 
 		repository := employee.NewRepository(db)
-		result := repository.CountInboundOrdersByEmployee(employeeId)
+		result := repository.CountInboundOrdersByEmployee(employeeId, domain.DateRange{})
 
 		assert.Nil(t, result)
 	})
@@ -392,12 +410,116 @@ func TestRepositoryCountInboundOrdersByEmployee(t *testing.T) {
 		employeeId := 1
 
 		mock.ExpectQuery(regexp.QuoteMeta(employee.CountInboundOrdersByEmployeeQuery)).
-		WithArgs(employeeId).
+		WithArgs(nil, nil, nil, nil, employeeId).
 		WillReturnError(sql.ErrConnDone)
 
 		repository := employee.NewRepository(db)
 
-		assert.Panics(t, func() { repository.CountInboundOrdersByEmployee(employeeId) })
+		assert.Panics(t, func() { repository.CountInboundOrdersByEmployee(employeeId, domain.DateRange{}) })
+	})
+}
+
+func TestRepositoryCountInboundOrdersByPeriod(t *testing.T) {
+	t.Run("Should return the count of each period within the dates", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		id := 1
+		from := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows([]string{"period", "inbound_orders_count"}).
+			AddRow("2023-07-03 00:00:00", 2).
+			AddRow("2023-07-10 00:00:00", 1)
+
+		mock.ExpectQuery(regexp.QuoteMeta(employee.CountInboundOrdersByPeriodQuery)).
+			WithArgs(domain.PeriodWeek, &id, &id, "2023-07-01 00:00:00", "2023-07-01 00:00:00", nil, nil).
+			WillReturnRows(rows)
+
+		repository := employee.NewRepository(db)
+
+		result := repository.CountInboundOrdersByPeriod(&id, domain.PeriodWeek, domain.DateRange{From: &from})
+
+		assert.Equal(t, []domain.ReportPeriod{
+			{Period: time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC), Count: 2},
+			{Period: time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC), Count: 1},
+		}, result)
+	})
+}
+
+func TestRepositoryGetMany(t *testing.T) {
+	t.Run("Should return the employees found among the ids with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(employee.GetManyQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "card_number_id", "first_name", "last_name", "warehouse_id", "is_supervisor"}).
+			AddRow(2, "123", "Jhon", "Doe", 1, false).
+			AddRow(5, "124", "Jane", "Doe", 1, true)
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := employee.NewRepository(db)
+		result := repository.GetMany([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := employee.NewRepository(db)
+		result := repository.GetMany(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(employee.GetManyQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := employee.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetMany([]int{2}) })
+	})
+}
+
+func TestRepositoryExisting(t *testing.T) {
+	t.Run("Should return the card numbers already taken with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(employee.ExistingQuery, []string{"402323", "402324"})
+		rows := sqlmock.NewRows([]string{"card_number_id"}).AddRow("402324")
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("402323", "402324").WillReturnRows(rows)
+
+		repository := employee.NewRepository(db)
+		result := repository.Existing([]string{"402323", "402324"})
+
+		assert.Equal(t, []string{"402324"}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no card numbers", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := employee.NewRepository(db)
+		result := repository.Existing(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(employee.ExistingQuery, []string{"402323"})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("402323").WillReturnError(sql.ErrConnDone)
+
+		repository := employee.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Existing([]string{"402323"}) })
 	})
 }
 
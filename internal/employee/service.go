@@ -1,35 +1,42 @@
 package employee
 
 import (
+	"errors"
+
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	ResourceNotFound = "empregado não encontrado com o id %d"
-	WarehouseNotFound = "armazém não encontrado com o id '%d'"
-	ResourceAlreadyExists = "um empregado com card number ID '%s' já existe"
+	ResourceNotFound      = "employee.not_found"
+	WarehouseNotFound     = "warehouse.not_found"
+	ResourceAlreadyExists = "employee.already_exists"
+	ResourceModified      = "employee.modified"
 )
 
 type Service interface {
 	GetAll() []domain.Employee
+	GetMany(ids []int) []domain.Employee
 	Get(int) (*domain.Employee, error)
 	Create(domain.Employee) (*domain.Employee, error)
-	Update(int, domain.UpdateEmployee) (*domain.Employee, error)
+	Import(employees []domain.Employee, options domain.ImportOptions) ([]int, []error)
+	Update(domain.Employee, domain.Employee) (*domain.Employee, error)
 	Delete(int) error
-	CountInboundOrdersByAllEmployees() []domain.InboundOrdersByEmployee
-	CountInboundOrdersByEmployee(id int) (*domain.InboundOrdersByEmployee, error)
+	CountInboundOrdersByAllEmployees(dates domain.DateRange, top int) []domain.InboundOrdersByEmployee
+	CountInboundOrdersByEmployee(id int, dates domain.DateRange) (*domain.InboundOrdersByEmployee, error)
+	CountInboundOrdersByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error)
 }
 
 type service struct {
-	repository Repository
+	repository          Repository
 	warehouseRepository warehouse.Repository
 }
 
 func NewService(r Repository, w warehouse.Repository) Service {
 	return &service{
-		repository: r,
+		repository:          r,
 		warehouseRepository: w,
 	}
 }
@@ -38,6 +45,11 @@ func (s *service) GetAll() []domain.Employee {
 	return s.repository.GetAll()
 }
 
+// GetMany returns the employees found among ids, leaving out the missing ones.
+func (s *service) GetMany(ids []int) []domain.Employee {
+	return s.repository.GetMany(ids)
+}
+
 func (s *service) Get(id int) (*domain.Employee, error) {
 	employee := s.repository.Get(id)
 
@@ -49,14 +61,8 @@ func (s *service) Get(id int) (*domain.Employee, error) {
 }
 
 func (s *service) Create(employee domain.Employee) (*domain.Employee, error) {
-	if s.repository.Exists(employee.CardNumberID) {
-		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, employee.CardNumberID)
-	}
-
-	w := s.warehouseRepository.Get(employee.WarehouseID)
-
-	if w == nil {
-		return nil, apperr.NewDependentResourceNotFound(WarehouseNotFound, employee.WarehouseID)
+	if err := s.checkNew(employee, s.repository.Exists, s.warehouseExists); err != nil {
+		return nil, err
 	}
 
 	id := s.repository.Save(employee)
@@ -65,32 +71,64 @@ func (s *service) Create(employee domain.Employee) (*domain.Employee, error) {
 	return created, nil
 }
 
-func (s *service) Update(id int, employee domain.UpdateEmployee) (*domain.Employee, error) {
-	employeeFound := s.repository.Get(id)
+// Import creates the employees in bulk with the same checks as Create. A card
+// number repeated in the import is rejected as already existing. The card
+// numbers and warehouses of the whole import are looked up at once.
+func (s *service) Import(employees []domain.Employee, options domain.ImportOptions) ([]int, []error) {
+	taken := helpers.Found(employees, func(e domain.Employee) string { return e.CardNumberID }, s.repository.Existing, helpers.Identity[string])
+	warehouseFound := helpers.Found(employees, func(e domain.Employee) int { return e.WarehouseID }, s.warehouseRepository.GetMany, func(w domain.Warehouse) int { return w.ID })
+	cardNumbers := make(map[string]bool)
+	check := func(employee domain.Employee) error {
+		if cardNumbers[employee.CardNumberID] {
+			return apperr.NewResourceAlreadyExists(ResourceAlreadyExists, employee.CardNumberID)
+		}
+		cardNumbers[employee.CardNumberID] = true
 
-	if employeeFound == nil {
-		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+		return s.checkNew(employee, taken, warehouseFound)
 	}
-	
-	if employee.CardNumberID != nil {
-		employeeCardNumber := *employee.CardNumberID 
-		employeeCardNumberExists := s.repository.Exists(employeeCardNumber)
 
-		if employeeCardNumberExists && employeeCardNumber != employeeFound.CardNumberID {
-			return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, employeeCardNumber)
-		}
+	return helpers.Import(employees, options.DryRun, options.Mode != domain.ImportBestEffort, check, s.repository.SaveAll, options.Progress)
+}
+
+func (s *service) checkNew(employee domain.Employee, taken func(cardNumberID string) bool, warehouseFound func(id int) bool) error {
+	if taken(employee.CardNumberID) {
+		return apperr.NewResourceAlreadyExists(ResourceAlreadyExists, employee.CardNumberID)
+	}
+
+	if !warehouseFound(employee.WarehouseID) {
+		return apperr.NewDependentResourceNotFound(WarehouseNotFound, employee.WarehouseID)
 	}
 
-	employeeFound.Overlap(employee)
+	return nil
+}
+
+func (s *service) warehouseExists(id int) bool {
+	return s.warehouseRepository.Get(id) != nil
+}
+
+// Update replaces current, the employee as the caller read it, with employee.
+// It fails when the employee was changed since it was read.
+func (s *service) Update(current domain.Employee, employee domain.Employee) (*domain.Employee, error) {
+	employeeCardNumber := employee.CardNumberID
+	employeeCardNumberExists := s.repository.Exists(employeeCardNumber)
+
+	if employeeCardNumberExists && employeeCardNumber != current.CardNumberID {
+		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, employeeCardNumber)
+	}
+
+	employee.ID = current.ID
+	employee.Version = current.Version
 
-	w := s.warehouseRepository.Get(employeeFound.WarehouseID)
+	w := s.warehouseRepository.Get(employee.WarehouseID)
 
 	if w == nil {
-		return nil, apperr.NewDependentResourceNotFound(WarehouseNotFound, employeeFound.WarehouseID)
+		return nil, apperr.NewDependentResourceNotFound(WarehouseNotFound, employee.WarehouseID)
 	}
 
-	s.repository.Update(*employeeFound)
-	return s.repository.Get(id), nil
+	if err := s.repository.Update(employee); errors.Is(err, ErrModified) {
+		return nil, apperr.NewIncompatibleResource(ResourceModified, employee.ID)
+	}
+	return s.repository.Get(employee.ID), nil
 
 }
 
@@ -105,16 +143,29 @@ func (s *service) Delete(id int) error {
 	return nil
 }
 
-func (s *service) CountInboundOrdersByAllEmployees() []domain.InboundOrdersByEmployee {
-	return s.repository.CountInboundOrdersByAllEmployees()
+// CountInboundOrdersByAllEmployees counts the inbound orders of every
+// employee. When top is positive, only the top employees with the most inbound
+// orders are returned.
+func (s *service) CountInboundOrdersByAllEmployees(dates domain.DateRange, top int) []domain.InboundOrdersByEmployee {
+	report := s.repository.CountInboundOrdersByAllEmployees(dates)
+
+	return helpers.Top(report, top, func(r domain.InboundOrdersByEmployee) int { return r.InboundOrdersCount })
 }
 
-func (s *service) CountInboundOrdersByEmployee(id int) (*domain.InboundOrdersByEmployee, error) {
+func (s *service) CountInboundOrdersByEmployee(id int, dates domain.DateRange) (*domain.InboundOrdersByEmployee, error) {
 	employee := s.repository.Get(id)
 
 	if employee == nil {
 		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
 	}
 
-	return s.repository.CountInboundOrdersByEmployee(id), nil
+	return s.repository.CountInboundOrdersByEmployee(id, dates), nil
+}
+
+func (s *service) CountInboundOrdersByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error) {
+	if id != nil && s.repository.Get(*id) == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, *id)
+	}
+
+	return s.repository.CountInboundOrdersByPeriod(id, period, dates), nil
 }
@@ -2,6 +2,7 @@ package employee_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee"
@@ -131,42 +132,17 @@ func TestServiceGet(t *testing.T) {
 }
 
 func TestServiceUpdate(t *testing.T) {
-	t.Run("Should return a not found error", func(t *testing.T) {
-		service, repository, _ := CreateService(t)
-
-		id := 99
-		cardNumberID := "444555"
-		updateEmployee := domain.UpdateEmployee {
-			ID: &id,
-			CardNumberID: &cardNumberID,
-		}
-
-		var emptyEmployee *domain.Employee
-
-		repository.On("Get", id).Return(emptyEmployee)
-		result, err := service.Update(id, updateEmployee)
-
-		assert.Nil(t, result)
-		assert.Error(t, err)
-		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
-	})
-
 	t.Run("Should return a conflict error if card number id already exists", func(t *testing.T) {
 		service, repository, _ := CreateService(t)
 		
 		mockedEmployee := mockedEmployeeTemplate
 
-		id := 1
-		cardNumberID := "555555"
-		updateEmployee := domain.UpdateEmployee{
-			ID: &id,
-			CardNumberID: &cardNumberID,
-		}
+		updateEmployee := mockedEmployee
+		updateEmployee.CardNumberID = "555555"
 
-		repository.On("Get", id).Return(&mockedEmployee)
-		repository.On("Exists", cardNumberID).Return(true)
+		repository.On("Exists", updateEmployee.CardNumberID).Return(true)
 
-		result, err := service.Update(id, updateEmployee)
+		result, err := service.Update(mockedEmployee, updateEmployee)
 
 		assert.Nil(t, result)
 		assert.Error(t, err)
@@ -178,54 +154,59 @@ func TestServiceUpdate(t *testing.T) {
 
 		mockedEmployee := mockedEmployeeTemplate
 
-		id := 1
-		cardNumberID := "555555"
-		updateEmployee := domain.UpdateEmployee{
-			ID: &id,
-			CardNumberID: &cardNumberID,
-		}
+		updateEmployee := mockedEmployee
+		updateEmployee.CardNumberID = "555555"
+		updateEmployee.WarehouseID = 99
 
 		var emptyWarehouse *domain.Warehouse 
 
-		repository.On("Get", id).Return(&mockedEmployee)
-		repository.On("Exists", cardNumberID).Return(false)
-		wRepository.On("Get", mockedEmployee.WarehouseID).Return(emptyWarehouse)
+		repository.On("Exists", updateEmployee.CardNumberID).Return(false)
+		wRepository.On("Get", updateEmployee.WarehouseID).Return(emptyWarehouse)
 
-		result, err := service.Update(id, updateEmployee)
+		result, err := service.Update(mockedEmployee, updateEmployee)
 
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
 	})
 
-	t.Run("Should return an updated employee", func(t *testing.T) {
+	t.Run("Should return a conflict error if the employee was modified since it was read", func(t *testing.T) {
 		service, repository, wRepository := CreateService(t)
 
 		mockedEmployee := mockedEmployeeTemplate
+		mockedEmployee.Version = 3
 
-		id := 1
-		cardNumberID := "123456"
-		firstName := "Cleber"
-		updateEmployee := domain.UpdateEmployee{
-			ID: &id,
-			CardNumberID: &cardNumberID,
-			FirstName: &firstName,
-		}
+		updatedEmployee := mockedEmployee
+		updatedEmployee.FirstName = "Cleber"
+
+		repository.On("Exists", updatedEmployee.CardNumberID).Return(true)
+		wRepository.On("Get", updatedEmployee.WarehouseID).Return(&mockedWarehouse)
+		repository.On("Update", updatedEmployee).Return(employee.ErrModified)
+
+		result, err := service.Update(mockedEmployee, updatedEmployee)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+
+	t.Run("Should return an updated employee", func(t *testing.T) {
+		service, repository, wRepository := CreateService(t)
+
+		mockedEmployee := mockedEmployeeTemplate
 
 		updatedEmployee := mockedEmployee
-		
-		repository.On("Get", id).Return(&mockedEmployee)
-		repository.On("Exists", cardNumberID).Return(true)
-		wRepository.On("Get", mockedEmployee.WarehouseID).Return(&mockedWarehouse)
-		updatedEmployee.Overlap(updateEmployee)
-		repository.On("Update", updatedEmployee)
-		repository.On("Get", id).Return(&updatedEmployee)
+		updatedEmployee.FirstName = "Cleber"
 
-		result, err := service.Update(id, updateEmployee)
+		repository.On("Exists", updatedEmployee.CardNumberID).Return(true)
+		wRepository.On("Get", updatedEmployee.WarehouseID).Return(&mockedWarehouse)
+		repository.On("Update", updatedEmployee).Return(nil)
+		repository.On("Get", mockedEmployee.ID).Return(&updatedEmployee)
+
+		result, err := service.Update(mockedEmployee, updatedEmployee)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, firstName, result.FirstName)
+		assert.Equal(t, updatedEmployee.FirstName, result.FirstName)
 	})
 }
 
@@ -264,9 +245,9 @@ func TestServiceCountInboundOrdersByAllEmployees(t *testing.T) {
 		
 		expectedResult := []domain.InboundOrdersByEmployee{employeeInboundOrders}
 		
-		repository.On("CountInboundOrdersByAllEmployees").Return(expectedResult)
+		repository.On("CountInboundOrdersByAllEmployees", domain.DateRange{}).Return(expectedResult)
 
-		result := service.CountInboundOrdersByAllEmployees()
+		result := service.CountInboundOrdersByAllEmployees(domain.DateRange{}, 0)
 
 		assert.NotEmpty(t, result)
 		assert.Equal(t, result[0].ID, employeeInboundOrders.ID)
@@ -290,9 +271,9 @@ func TestServiceCountInboundOrdersByEmployee(t *testing.T) {
 		expectedResult := employeeInboundOrders
 
 		repository.On("Get", id).Return(&mockedEmployee)
-		repository.On("CountInboundOrdersByEmployee", id).Return(&expectedResult)
+		repository.On("CountInboundOrdersByEmployee", id, domain.DateRange{}).Return(&expectedResult)
 
-		result, err := service.CountInboundOrdersByEmployee(id)
+		result, err := service.CountInboundOrdersByEmployee(id, domain.DateRange{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -305,7 +286,7 @@ func TestServiceCountInboundOrdersByEmployee(t *testing.T) {
 		var emptyEmployee *domain.Employee
 
 		repository.On("Get", id).Return(emptyEmployee)
-		result, err := service.CountInboundOrdersByEmployee(id)
+		result, err := service.CountInboundOrdersByEmployee(id, domain.DateRange{})
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -313,6 +294,33 @@ func TestServiceCountInboundOrdersByEmployee(t *testing.T) {
 	})
 }
 
+func TestServiceCountInboundOrdersByPeriod(t *testing.T) {
+	t.Run("Should return the count of each period", func(t *testing.T) {
+		service, repository, _ := CreateService(t)
+
+		periods := []domain.ReportPeriod{{Period: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), Count: 3}}
+		repository.On("CountInboundOrdersByPeriod", (*int)(nil), domain.PeriodMonth, domain.DateRange{}).Return(periods)
+
+		result, err := service.CountInboundOrdersByPeriod(nil, domain.PeriodMonth, domain.DateRange{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, periods, result)
+	})
+
+	t.Run("Should return not found error when the id does not exist", func(t *testing.T) {
+		service, repository, _ := CreateService(t)
+
+		id := 1
+		var found *domain.Employee
+		repository.On("Get", id).Return(found)
+
+		result, err := service.CountInboundOrdersByPeriod(&id, domain.PeriodMonth, domain.DateRange{})
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
 func CreateService(t *testing.T) (employee.Service, *mocks.Repository, *warehouseMock.Repository) {
 	t.Helper()
 	repository := new(mocks.Repository)
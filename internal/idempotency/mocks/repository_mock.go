@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Repository struct {
+	mock.Mock
+}
+
+func (r *Repository) Get(key string) *domain.IdempotencyKey {
+	args := r.Called(key)
+	return args.Get(0).(*domain.IdempotencyKey)
+}
+
+func (r *Repository) Reserve(i domain.IdempotencyKey) bool {
+	args := r.Called(i)
+	return args.Bool(0)
+}
+
+func (r *Repository) Save(i domain.IdempotencyKey) {
+	r.Called(i)
+}
+
+func (r *Repository) Release(key string) {
+	r.Called(key)
+}
@@ -0,0 +1,95 @@
+package idempotency
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+)
+
+const (
+	GetQuery           = "SELECT idempotency_key, request_hash, response_status, response_content_type, response_body, expires_at FROM idempotency_keys WHERE idempotency_key=?"
+	DeleteExpiredQuery = "DELETE FROM idempotency_keys WHERE idempotency_key=? AND expires_at < ?"
+	ReserveQuery       = "INSERT IGNORE INTO idempotency_keys (idempotency_key, request_hash, expires_at) VALUES (?, ?, ?)"
+	SaveQuery          = "UPDATE idempotency_keys SET response_status=?, response_content_type=?, response_body=?, expires_at=? WHERE idempotency_key=?"
+	ReleaseQuery       = "DELETE FROM idempotency_keys WHERE idempotency_key=?"
+)
+
+type Repository interface {
+	Get(key string) *domain.IdempotencyKey
+	Reserve(i domain.IdempotencyKey) bool
+	Save(i domain.IdempotencyKey)
+	Release(key string)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Get(key string) *domain.IdempotencyKey {
+	row := r.db.QueryRow(GetQuery, key)
+	i := domain.IdempotencyKey{}
+	var expiresAt string
+
+	err := row.Scan(&i.Key, &i.RequestHash, &i.ResponseStatus, &i.ResponseContentType, &i.ResponseBody, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		panic(err)
+	}
+
+	i.ExpiresAt = helpers.ToDateTime(expiresAt)
+
+	return &i
+}
+
+// Reserve stores the key as pending, replacing it when it is expired. It
+// returns false when the key is already taken, so that only one of the
+// concurrent requests with the same key is handled.
+func (r *repository) Reserve(i domain.IdempotencyKey) bool {
+	expiresAt := helpers.ToFormattedDateTime(i.ExpiresAt)
+	if _, err := r.db.Exec(DeleteExpiredQuery, i.Key, helpers.ToFormattedDateTime(time.Now().UTC())); err != nil {
+		panic(err)
+	}
+
+	result, err := r.db.Exec(ReserveQuery, i.Key, i.RequestHash, expiresAt)
+	if err != nil {
+		panic(err)
+	}
+
+	reserved, err := result.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	return reserved == 1
+}
+
+// Save stores the response of a reserved key.
+func (r *repository) Save(i domain.IdempotencyKey) {
+	stmt, err := r.db.Prepare(SaveQuery)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = stmt.Exec(i.ResponseStatus, i.ResponseContentType, i.ResponseBody, helpers.ToFormattedDateTime(i.ExpiresAt), i.Key)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Release frees a reserved key whose request failed, so that it can be
+// retried.
+func (r *repository) Release(key string) {
+	if _, err := r.db.Exec(ReleaseQuery, key); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,195 @@
+package idempotency_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/idempotency"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	expiresAt                    = "2023-07-01 10:00:00"
+	mockedIdempotencyKeyTemplate = domain.IdempotencyKey{
+		Key:                 "c6a7b2f0",
+		RequestHash:         "hash",
+		ResponseStatus:      201,
+		ResponseContentType: "application/json; charset=utf-8",
+		ResponseBody:        `{"data":{"id":1}}`,
+		ExpiresAt:           helpers.ToDateTime(expiresAt),
+	}
+)
+
+func TestRepositoryGet(t *testing.T) {
+	t.Run("Should return an idempotency key by specified key", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedKey := mockedIdempotencyKeyTemplate
+		columns := []string{"idempotency_key", "request_hash", "response_status", "response_content_type", "response_body", "expires_at"}
+		rows := sqlmock.NewRows(columns)
+		rows.AddRow(mockedKey.Key, mockedKey.RequestHash, mockedKey.ResponseStatus, mockedKey.ResponseContentType, mockedKey.ResponseBody, expiresAt)
+
+		mock.ExpectQuery(regexp.QuoteMeta(idempotency.GetQuery)).WithArgs(mockedKey.Key).WillReturnRows(rows)
+
+		repository := idempotency.NewRepository(db)
+
+		result := repository.Get(mockedKey.Key)
+
+		assert.Equal(t, &mockedKey, result)
+	})
+
+	t.Run("Should not return an idempotency key", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		key := "c6a7b2f0"
+
+		mock.ExpectQuery(regexp.QuoteMeta(idempotency.GetQuery)).WithArgs(key).WillReturnError(sql.ErrNoRows)
+
+		repository := idempotency.NewRepository(db)
+
+		result := repository.Get(key)
+
+		assert.Nil(t, result)
+	})
+
+	t.Run("Should throw panic", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		key := "c6a7b2f0"
+
+		mock.ExpectQuery(regexp.QuoteMeta(idempotency.GetQuery)).WithArgs(key).WillReturnError(sql.ErrConnDone)
+
+		repository := idempotency.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Get(key) })
+	})
+}
+
+func TestRepositoryReserve(t *testing.T) {
+	t.Run("Should reserve a key that is free or expired", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedKey := mockedIdempotencyKeyTemplate
+		mock.ExpectExec(regexp.QuoteMeta(idempotency.DeleteExpiredQuery)).WithArgs(mockedKey.Key, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(idempotency.ReserveQuery)).
+			WithArgs(mockedKey.Key, mockedKey.RequestHash, expiresAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := idempotency.NewRepository(db)
+
+		assert.True(t, repository.Reserve(mockedKey))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should not reserve a key that is taken", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedKey := mockedIdempotencyKeyTemplate
+		mock.ExpectExec(regexp.QuoteMeta(idempotency.DeleteExpiredQuery)).WithArgs(mockedKey.Key, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(idempotency.ReserveQuery)).
+			WithArgs(mockedKey.Key, mockedKey.RequestHash, expiresAt).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := idempotency.NewRepository(db)
+
+		assert.False(t, repository.Reserve(mockedKey))
+	})
+
+	t.Run("Should throw panic", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedKey := mockedIdempotencyKeyTemplate
+		mock.ExpectExec(regexp.QuoteMeta(idempotency.DeleteExpiredQuery)).WillReturnError(sql.ErrConnDone)
+
+		repository := idempotency.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Reserve(mockedKey) })
+	})
+}
+
+func TestRepositoryRelease(t *testing.T) {
+	t.Run("Should delete the key", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(idempotency.ReleaseQuery)).WithArgs("c6a7b2f0").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := idempotency.NewRepository(db)
+
+		assert.NotPanics(t, func() { repository.Release("c6a7b2f0") })
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should throw panic", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(idempotency.ReleaseQuery)).WithArgs("c6a7b2f0").WillReturnError(sql.ErrConnDone)
+
+		repository := idempotency.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Release("c6a7b2f0") })
+	})
+}
+
+func TestRepositorySave(t *testing.T) {
+	t.Run("Should save the idempotency key", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedKey := mockedIdempotencyKeyTemplate
+		mock.ExpectPrepare(regexp.QuoteMeta(idempotency.SaveQuery))
+		mock.ExpectExec(regexp.QuoteMeta(idempotency.SaveQuery)).
+			WithArgs(mockedKey.ResponseStatus, mockedKey.ResponseContentType, mockedKey.ResponseBody, expiresAt, mockedKey.Key).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := idempotency.NewRepository(db)
+
+		assert.NotPanics(t, func() { repository.Save(mockedKey) })
+	})
+
+	t.Run("Should throw panic when expected prepare fails", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedKey := mockedIdempotencyKeyTemplate
+		mock.ExpectPrepare(regexp.QuoteMeta(idempotency.SaveQuery)).WillReturnError(sql.ErrConnDone)
+
+		repository := idempotency.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Save(mockedKey) })
+	})
+
+	t.Run("Should throw panic when expected exec fails", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedKey := mockedIdempotencyKeyTemplate
+		mock.ExpectPrepare(regexp.QuoteMeta(idempotency.SaveQuery))
+		mock.ExpectExec(regexp.QuoteMeta(idempotency.SaveQuery)).
+			WithArgs(mockedKey.ResponseStatus, mockedKey.ResponseContentType, mockedKey.ResponseBody, expiresAt, mockedKey.Key).
+			WillReturnError(sql.ErrConnDone)
+
+		repository := idempotency.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Save(mockedKey) })
+	})
+}
+
+func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	return db, mock
+}
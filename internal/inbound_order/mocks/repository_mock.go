@@ -14,6 +14,11 @@ func (r *Repository) Get(id int) *domain.InboundOrder {
 	return args.Get(0).(*domain.InboundOrder)
 }
 
+func (r *Repository) GetMany(ids []int) []domain.InboundOrder {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.InboundOrder)
+}
+
 func (r *Repository) Save(i domain.InboundOrder) int {
 	args := r.Called(i)
 	return args.Get(0).(int)
@@ -22,4 +27,4 @@ func (r *Repository) Save(i domain.InboundOrder) int {
 func (r *Repository) Exists(orderNumber string) bool {
 	args := r.Called(orderNumber)
 	return args.Get(0).(bool)
-}
\ No newline at end of file
+}
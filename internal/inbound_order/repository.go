@@ -5,17 +5,20 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	InsertQuery = "INSERT INTO inbound_orders (order_date, order_number, employee_id, product_batch_id, warehouse_id) VALUES (?, ?, ?, ?, ?)"
-	GetQuery    = "SELECT id, order_date, order_number, employee_id, product_batch_id, warehouse_id FROM inbound_orders WHERE id=?"
-	ExistsQuery = "SELECT order_number FROM inbound_orders WHERE order_number=?"
+	InsertQuery  = "INSERT INTO inbound_orders (order_date, order_number, employee_id, product_batch_id, warehouse_id) VALUES (?, ?, ?, ?, ?)"
+	GetQuery     = "SELECT id, order_date, order_number, employee_id, product_batch_id, warehouse_id FROM inbound_orders WHERE id=?"
+	GetManyQuery = "SELECT id, order_date, order_number, employee_id, product_batch_id, warehouse_id FROM inbound_orders WHERE id IN (%s)"
+	ExistsQuery  = "SELECT order_number FROM inbound_orders WHERE order_number=?"
 )
 
 type Repository interface {
 	Get(id int) *domain.InboundOrder
+	GetMany(ids []int) []domain.InboundOrder
 	Save(i domain.InboundOrder) int
 	Exists(orderNumber string) bool
 }
@@ -48,11 +51,46 @@ func (r *repository) Get(id int) *domain.InboundOrder {
 	return &i
 }
 
+// GetMany returns the inbound orders found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.InboundOrder {
+	inboundOrders := make([]domain.InboundOrder, 0, len(ids))
+	if len(ids) == 0 {
+		return inboundOrders
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i := domain.InboundOrder{}
+		var orderDate string
+		if err := rows.Scan(&i.ID, &orderDate, &i.OrderNumber, &i.EmployeeId, &i.ProductBatchId, &i.WarehouseId); err != nil {
+			panic(err)
+		}
+		i.OrderDate = helpers.ToDateTime(orderDate)
+		inboundOrders = append(inboundOrders, i)
+	}
+	return inboundOrders
+}
+
+// Save stores the order and its InboundOrderReceived event in the same
+// transaction.
 func (r *repository) Save(i domain.InboundOrder) int {
-	stmt, err := r.db.Prepare(InsertQuery)
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(InsertQuery)
 	if err != nil {
 		panic(err)
 	}
+	defer stmt.Close()
 
 	res, err := stmt.Exec(i.OrderDate, i.OrderNumber, i.EmployeeId, i.ProductBatchId, i.WarehouseId)
 
@@ -65,7 +103,13 @@ func (r *repository) Save(i domain.InboundOrder) int {
 		panic(err)
 	}
 
-	return int(id)
+	i.ID = int(id)
+	outbox.Record(tx, domain.EventInboundOrderReceived, i.ID, i)
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return i.ID
 }
 
 func (r *repository) Exists(orderNumber string) bool {
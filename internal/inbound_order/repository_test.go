@@ -8,6 +8,8 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/inbound_order"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -135,16 +137,22 @@ func TestRepositorySave(t *testing.T) {
 
 		lastInsertId := 1
 		mockedInboundOrder := mockedInboundOrderTemplate
+		mock.ExpectBegin()
 		mock.ExpectPrepare(allDataInsertQuery)
 		mock.ExpectExec(allDataInsertQuery).
 			WithArgs(mockedInboundOrder.OrderDate, mockedInboundOrder.OrderNumber, mockedInboundOrder.EmployeeId, mockedInboundOrder.ProductBatchId, mockedInboundOrder.WarehouseId).
 			WillReturnResult(sqlmock.NewResult(int64(lastInsertId), 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.InsertQuery)).
+			WithArgs(domain.EventInboundOrderReceived, lastInsertId, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		repository := inbound_order.NewRepository(db)
 
 		result := repository.Save(mockedInboundOrder)
 
 		assert.Equal(t, lastInsertId, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Should throw panic when expect prepare fails", func(t *testing.T) {
@@ -152,6 +160,7 @@ func TestRepositorySave(t *testing.T) {
 		defer db.Close()
 
 		mockedInboundOrder := mockedInboundOrderTemplate
+		mock.ExpectBegin()
 		mock.ExpectPrepare(allDataInsertQuery).WillReturnError(sql.ErrConnDone)
 
 		repository := inbound_order.NewRepository(db)
@@ -164,6 +173,7 @@ func TestRepositorySave(t *testing.T) {
 		defer db.Close()
 
 		mockedInboundOrder := mockedInboundOrderTemplate
+		mock.ExpectBegin()
 		mock.ExpectPrepare(allDataInsertQuery)
 		mock.ExpectExec(allDataInsertQuery).
 			WithArgs(mockedInboundOrder.OrderDate, mockedInboundOrder.OrderNumber, mockedInboundOrder.EmployeeId, mockedInboundOrder.ProductBatchId, mockedInboundOrder.WarehouseId).
@@ -179,6 +189,7 @@ func TestRepositorySave(t *testing.T) {
 		defer db.Close()
 
 		mockedInboundOrder := mockedInboundOrderTemplate
+		mock.ExpectBegin()
 		mock.ExpectPrepare(allDataInsertQuery)
 		mock.ExpectExec(allDataInsertQuery).
 			WithArgs(mockedInboundOrder.OrderDate, mockedInboundOrder.OrderNumber, mockedInboundOrder.EmployeeId, mockedInboundOrder.ProductBatchId, mockedInboundOrder.WarehouseId).
@@ -190,6 +201,46 @@ func TestRepositorySave(t *testing.T) {
 	})
 }
 
+func TestRepositoryGetMany(t *testing.T) {
+	t.Run("Should return the inbound orders found among the ids with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(inbound_order.GetManyQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "order_date", "order_number", "employee_id", "product_batch_id", "warehouse_id"}).
+			AddRow(2, "2023-01-02 10:00:00", "ORD-2", 1, 1, 1).
+			AddRow(5, "2023-01-02 10:00:00", "ORD-5", 1, 1, 1)
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := inbound_order.NewRepository(db)
+		result := repository.GetMany([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := inbound_order.NewRepository(db)
+		result := repository.GetMany(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(inbound_order.GetManyQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := inbound_order.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetMany([]int{2}) })
+	})
+}
+
 func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
 	t.Helper()
 
@@ -9,10 +9,10 @@ import (
 )
 
 const (
-	EmployeeNotFound      = "funcionário não encontrado com o id %d"
-	ProductBatchNotFound  = "lote de produto não encontrado com o id %d"
-	WarehouseNotFound     = "armazém não encontrado com o id %d"
-	ResourceAlreadyExists = "ordem de entrada com o numero '%s' já existe"
+	EmployeeNotFound      = "employee.not_found"
+	ProductBatchNotFound  = "product_batch.not_found"
+	WarehouseNotFound     = "warehouse.not_found"
+	ResourceAlreadyExists = "inbound_order.already_exists"
 )
 
 type Service interface {
@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Repository struct {
+	mock.Mock
+}
+
+func (r *Repository) Create(kind string, payload []byte) int {
+	args := r.Called(kind, payload)
+	return args.Int(0)
+}
+
+func (r *Repository) Get(id int) *domain.Job {
+	args := r.Called(id)
+	return args.Get(0).(*domain.Job)
+}
+
+func (r *Repository) Claim() *domain.Job {
+	args := r.Called()
+	return args.Get(0).(*domain.Job)
+}
+
+func (r *Repository) Renew(id int, claimedAt time.Time) bool {
+	args := r.Called(id, claimedAt)
+	return args.Bool(0)
+}
+
+func (r *Repository) Progress(id int, claimedAt time.Time, progress int) bool {
+	args := r.Called(id, claimedAt, progress)
+	return args.Bool(0)
+}
+
+func (r *Repository) CancelRequested(id int) bool {
+	args := r.Called(id)
+	return args.Bool(0)
+}
+
+func (r *Repository) Succeed(id int, claimedAt time.Time, result domain.JobResult) bool {
+	args := r.Called(id, claimedAt, result)
+	return args.Bool(0)
+}
+
+func (r *Repository) Finish(id int, claimedAt time.Time, status string, reason *string) bool {
+	args := r.Called(id, claimedAt, status, reason)
+	return args.Bool(0)
+}
+
+func (r *Repository) Cancel(id int) bool {
+	args := r.Called(id)
+	return args.Bool(0)
+}
+
+func (r *Repository) Result(id int) *domain.JobResult {
+	args := r.Called(id)
+	return args.Get(0).(*domain.JobResult)
+}
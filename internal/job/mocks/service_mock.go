@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Service struct {
+	mock.Mock
+}
+
+func (s *Service) Enqueue(kind string, payload interface{}) *domain.Job {
+	args := s.Called(kind, payload)
+	return args.Get(0).(*domain.Job)
+}
+
+func (s *Service) Get(id int) (*domain.Job, error) {
+	args := s.Called(id)
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (s *Service) Cancel(id int) (*domain.Job, error) {
+	args := s.Called(id)
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (s *Service) Result(id int) (*domain.JobResult, error) {
+	args := s.Called(id)
+	return args.Get(0).(*domain.JobResult), args.Error(1)
+}
@@ -0,0 +1,205 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+)
+
+// Kinds of the jobs run by the workers.
+const (
+	KindProductImport  = "product_import"
+	KindSellerImport   = "seller_import"
+	KindBuyerImport    = "buyer_import"
+	KindEmployeeImport = "employee_import"
+	KindProductReport  = "product_report"
+	KindSectionReport  = "section_report"
+)
+
+var (
+	// ErrUnknownKind fails the jobs no runner was registered for.
+	ErrUnknownKind = errors.New("no runner registered for the job kind")
+)
+
+// Runner executes a job from its payload and returns the file it produces.
+// It reports how much of the work is done through progress, and must stop
+// when ctx is done, which happens when the job is canceled.
+type Runner func(ctx context.Context, payload []byte, progress func(done, total int)) (*domain.JobResult, error)
+
+// Pool runs the queued jobs with a fixed number of workers, each one polling
+// the queue at the given interval while it is empty.
+type Pool struct {
+	repository Repository
+	runners    map[string]Runner
+	workers    int
+	interval   time.Duration
+}
+
+func NewPool(repository Repository, workers int, interval time.Duration) *Pool {
+	return &Pool{
+		repository: repository,
+		runners:    make(map[string]Runner),
+		workers:    workers,
+		interval:   interval,
+	}
+}
+
+// Register sets the runner of the jobs of the kind. Runners must be
+// registered before the pool is started.
+func (p *Pool) Register(kind string, runner Runner) {
+	p.runners[kind] = runner
+}
+
+// Start launches the workers, which run until ctx is done.
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.workers; i++ {
+		go p.work(ctx)
+	}
+}
+
+func (p *Pool) work(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		for p.runSafely(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runSafely keeps a worker alive when the queue cannot be reached, waiting for
+// the next tick to try again.
+func (p *Pool) runSafely(ctx context.Context) (ran bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("job worker: %v", r)
+			ran = false
+		}
+	}()
+
+	return ctx.Err() == nil && p.RunNext(ctx)
+}
+
+// RunNext claims the oldest queued job and runs it to the end. It returns
+// false when there is no job to run. The job is stopped, and its outcome
+// dropped, as soon as another worker claims it again or ctx is done; in the
+// latter case it is claimed again once its lease expires.
+func (p *Pool) RunNext(ctx context.Context) bool {
+	j := p.repository.Claim()
+	if j == nil {
+		return false
+	}
+
+	runner, ok := p.runners[j.Kind]
+	if !ok {
+		reason := ErrUnknownKind.Error()
+		p.repository.Finish(j.ID, j.ClaimedAt, domain.JobFailed, &reason)
+		return true
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var lost atomic.Bool
+	lose := func() {
+		lost.Store(true)
+		cancel()
+	}
+	go p.keepLease(jobCtx, *j, lose)
+
+	last, canceled := -1, false
+	progress := func(done, total int) {
+		if total <= 0 {
+			return
+		}
+		percent := done * 100 / total
+		if percent <= last || percent >= 100 {
+			return
+		}
+		last = percent
+		if !p.repository.Progress(j.ID, j.ClaimedAt, percent) {
+			lose()
+			return
+		}
+		if p.repository.CancelRequested(j.ID) {
+			canceled = true
+			cancel()
+		}
+	}
+
+	result, err := run(jobCtx, runner, j.Payload, progress)
+	switch {
+	case lost.Load():
+		log.Printf("job worker: job %d was claimed again by another worker", j.ID)
+	case ctx.Err() != nil:
+		log.Printf("job worker: job %d stopped with the pool, it will be claimed again", j.ID)
+	case canceled:
+		p.repository.Finish(j.ID, j.ClaimedAt, domain.JobCanceled, nil)
+	case err != nil:
+		reason := err.Error()
+		p.repository.Finish(j.ID, j.ClaimedAt, domain.JobFailed, &reason)
+	default:
+		p.repository.Succeed(j.ID, j.ClaimedAt, *result)
+	}
+	return true
+}
+
+// run calls the runner, turning a panic into the error that fails the job.
+func run(ctx context.Context, runner Runner, payload []byte, progress func(done, total int)) (result *domain.JobResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+
+	result, err = runner(ctx, payload, progress)
+	if err == nil && result == nil {
+		result = &domain.JobResult{}
+	}
+	return result, err
+}
+
+// keepLease renews the lease of the job until ctx is done, so that no other
+// worker claims it while it runs. It calls lose when the job was claimed
+// again.
+func (p *Pool) keepLease(ctx context.Context, j domain.Job, lose func()) {
+	ticker := time.NewTicker(LeaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !p.renewSafely(j) {
+				lose()
+				return
+			}
+		}
+	}
+}
+
+// renewSafely keeps the job running when its lease cannot be renewed, trying
+// again on the next tick. It returns false only when the job was claimed
+// again.
+func (p *Pool) renewSafely(j domain.Job) (held bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("job lease: %v", r)
+			held = true
+		}
+	}()
+
+	return p.repository.Renew(j.ID, j.ClaimedAt)
+}
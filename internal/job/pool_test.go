@@ -0,0 +1,124 @@
+package job_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/job"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/job/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPoolRunNext(t *testing.T) {
+	claimedAt := time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC)
+
+	t.Run("Should store the result of the job with its progress", func(t *testing.T) {
+		pool, repository := CreatePool(t)
+
+		result := domain.JobResult{ContentType: "application/json", Content: []byte(`[]`)}
+		pool.Register(job.KindProductReport, func(ctx context.Context, payload []byte, progress func(done, total int)) (*domain.JobResult, error) {
+			progress(1, 2)
+			progress(2, 2)
+			return &result, nil
+		})
+		repository.On("Claim").Return(&domain.Job{ID: 4, Kind: job.KindProductReport, ClaimedAt: claimedAt})
+		repository.On("Progress", 4, claimedAt, 50).Return(true)
+		repository.On("CancelRequested", 4).Return(false)
+		repository.On("Succeed", 4, claimedAt, result).Return(true)
+
+		assert.True(t, pool.RunNext(context.Background()))
+		repository.AssertExpectations(t)
+		repository.AssertNumberOfCalls(t, "Progress", 1)
+	})
+	t.Run("Should stop the job when its cancellation is asked for", func(t *testing.T) {
+		pool, repository := CreatePool(t)
+
+		pool.Register(job.KindProductReport, func(ctx context.Context, payload []byte, progress func(done, total int)) (*domain.JobResult, error) {
+			progress(0, 2)
+			return nil, ctx.Err()
+		})
+		var reason *string
+		repository.On("Claim").Return(&domain.Job{ID: 4, Kind: job.KindProductReport, ClaimedAt: claimedAt})
+		repository.On("Progress", 4, claimedAt, 0).Return(true)
+		repository.On("CancelRequested", 4).Return(true)
+		repository.On("Finish", 4, claimedAt, domain.JobCanceled, reason).Return(true)
+
+		assert.True(t, pool.RunNext(context.Background()))
+		repository.AssertExpectations(t)
+	})
+	t.Run("Should fail the job with the error or panic of its runner", func(t *testing.T) {
+		pool, repository := CreatePool(t)
+
+		pool.Register(job.KindProductReport, func(ctx context.Context, payload []byte, progress func(done, total int)) (*domain.JobResult, error) {
+			return nil, errors.New("broken payload")
+		})
+		pool.Register(job.KindSectionReport, func(ctx context.Context, payload []byte, progress func(done, total int)) (*domain.JobResult, error) {
+			panic("out of range")
+		})
+		repository.On("Claim").Return(&domain.Job{ID: 4, Kind: job.KindProductReport, ClaimedAt: claimedAt}).Once()
+		repository.On("Claim").Return(&domain.Job{ID: 5, Kind: job.KindSectionReport, ClaimedAt: claimedAt}).Once()
+		repository.On("Finish", mock.Anything, claimedAt, domain.JobFailed, mock.Anything).Return(true)
+
+		assert.True(t, pool.RunNext(context.Background()))
+		assert.True(t, pool.RunNext(context.Background()))
+		repository.AssertCalled(t, "Finish", 4, claimedAt, domain.JobFailed, mock.MatchedBy(func(reason *string) bool { return *reason == "broken payload" }))
+		repository.AssertCalled(t, "Finish", 5, claimedAt, domain.JobFailed, mock.MatchedBy(func(reason *string) bool { return *reason == "out of range" }))
+	})
+	t.Run("Should fail the jobs of an unknown kind", func(t *testing.T) {
+		pool, repository := CreatePool(t)
+
+		repository.On("Claim").Return(&domain.Job{ID: 4, Kind: "unknown", ClaimedAt: claimedAt})
+		repository.On("Finish", 4, claimedAt, domain.JobFailed, mock.Anything).Return(true)
+
+		assert.True(t, pool.RunNext(context.Background()))
+		repository.AssertExpectations(t)
+	})
+	t.Run("Should stop the job and drop its outcome once it was claimed again", func(t *testing.T) {
+		pool, repository := CreatePool(t)
+
+		pool.Register(job.KindProductReport, func(ctx context.Context, payload []byte, progress func(done, total int)) (*domain.JobResult, error) {
+			progress(1, 2)
+			return nil, ctx.Err()
+		})
+		repository.On("Claim").Return(&domain.Job{ID: 4, Kind: job.KindProductReport, ClaimedAt: claimedAt})
+		repository.On("Progress", 4, claimedAt, 50).Return(false)
+
+		assert.True(t, pool.RunNext(context.Background()))
+		repository.AssertNotCalled(t, "CancelRequested", 4)
+		repository.AssertNotCalled(t, "Finish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		repository.AssertNotCalled(t, "Succeed", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Should leave the job to be claimed again when the pool stops", func(t *testing.T) {
+		pool, repository := CreatePool(t)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		pool.Register(job.KindProductReport, func(jobCtx context.Context, payload []byte, progress func(done, total int)) (*domain.JobResult, error) {
+			cancel()
+			return nil, jobCtx.Err()
+		})
+		repository.On("Claim").Return(&domain.Job{ID: 4, Kind: job.KindProductReport, ClaimedAt: claimedAt})
+
+		assert.True(t, pool.RunNext(ctx))
+		repository.AssertNotCalled(t, "Finish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Should return false when the queue is empty", func(t *testing.T) {
+		pool, repository := CreatePool(t)
+
+		var queued *domain.Job
+		repository.On("Claim").Return(queued)
+
+		assert.False(t, pool.RunNext(context.Background()))
+	})
+}
+
+func CreatePool(t *testing.T) (*job.Pool, *mocks.Repository) {
+	t.Helper()
+	repository := new(mocks.Repository)
+	pool := job.NewPool(repository, 1, time.Second)
+
+	return pool, repository
+}
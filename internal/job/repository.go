@@ -0,0 +1,214 @@
+package job
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+)
+
+const (
+	InsertQuery          = "INSERT INTO jobs (kind, status, payload, created_at) VALUES (?, ?, ?, ?)"
+	GetQuery             = "SELECT id, kind, status, progress, cancel_requested, error, created_at, started_at, finished_at FROM jobs WHERE id = ?"
+	NextQueuedQuery      = "SELECT id, kind, payload, created_at FROM jobs WHERE status = ? OR (status = ? AND lease_until < ?) ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED"
+	StartQuery           = "UPDATE jobs SET status = ?, progress = 0, started_at = ?, claimed_at = ?, lease_until = ? WHERE id = ?"
+	RenewQuery           = "UPDATE jobs SET lease_until = ? WHERE id = ? AND status = ? AND claimed_at = ?"
+	ProgressQuery        = "UPDATE jobs SET progress = ? WHERE id = ? AND status = ? AND claimed_at = ?"
+	CancelRequestedQuery = "SELECT cancel_requested FROM jobs WHERE id = ?"
+	SucceedQuery         = "UPDATE jobs SET status = ?, progress = 100, result = ?, result_content_type = ?, finished_at = ? WHERE id = ? AND status = ? AND claimed_at = ?"
+	FinishQuery          = "UPDATE jobs SET status = ?, error = ?, finished_at = ? WHERE id = ? AND status = ? AND claimed_at = ?"
+	CancelQueuedQuery    = "UPDATE jobs SET status = ?, finished_at = ? WHERE id = ? AND status = ?"
+	RequestCancelQuery   = "UPDATE jobs SET cancel_requested = 1 WHERE id = ? AND status = ?"
+	ResultQuery          = "SELECT result_content_type, result FROM jobs WHERE id = ? AND status = ?"
+)
+
+// LeaseDuration is how long a claimed job belongs to its worker. A running
+// job whose lease is not renewed in time is claimed again by another worker.
+// The writes of a worker are fenced with the time it claimed the job, so that
+// they are dropped once the job was claimed again.
+const LeaseDuration = 5 * time.Minute
+
+type Repository interface {
+	Create(kind string, payload []byte) int
+	Get(id int) *domain.Job
+	Claim() *domain.Job
+	Renew(id int, claimedAt time.Time) bool
+	Progress(id int, claimedAt time.Time, progress int) bool
+	CancelRequested(id int) bool
+	Succeed(id int, claimedAt time.Time, result domain.JobResult) bool
+	Finish(id int, claimedAt time.Time, status string, reason *string) bool
+	Cancel(id int) bool
+	Result(id int) *domain.JobResult
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Create(kind string, payload []byte) int {
+	res, err := r.db.Exec(InsertQuery, kind, domain.JobQueued, payload, helpers.ToFormattedDateTime(time.Now().UTC()))
+	if err != nil {
+		panic(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		panic(err)
+	}
+	return int(id)
+}
+
+func (r *repository) Get(id int) *domain.Job {
+	j := domain.Job{}
+	var createdAt string
+	var startedAt, finishedAt *string
+
+	err := r.db.QueryRow(GetQuery, id).Scan(&j.ID, &j.Kind, &j.Status, &j.Progress, &j.CancelRequested, &j.Error, &createdAt, &startedAt, &finishedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		panic(err)
+	}
+	j.CreatedAt = helpers.ToDateTime(createdAt)
+	j.StartedAt = toOptionalDateTime(startedAt)
+	j.FinishedAt = toOptionalDateTime(finishedAt)
+
+	return &j
+}
+
+// Claim marks the oldest queued job as running, leasing it for LeaseDuration,
+// and returns it with its payload, or nil when the queue is empty. Running
+// jobs whose lease expired, because their worker died, are claimed again from
+// the start. Rows locked by other workers are skipped so that each job is
+// claimed once.
+func (r *repository) Claim() *domain.Job {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	j := domain.Job{Status: domain.JobRunning}
+	var createdAt string
+	err = tx.QueryRow(NextQueuedQuery, domain.JobQueued, domain.JobRunning, helpers.ToFormattedDateTime(now)).Scan(&j.ID, &j.Kind, &j.Payload, &createdAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		panic(err)
+	}
+	j.CreatedAt = helpers.ToDateTime(createdAt)
+
+	started := helpers.ToFormattedDateTime(now)
+	if _, err := tx.Exec(StartQuery, domain.JobRunning, started, started, helpers.ToFormattedDateTime(now.Add(LeaseDuration)), j.ID); err != nil {
+		panic(err)
+	}
+	j.StartedAt = &now
+	j.ClaimedAt = now
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return &j
+}
+
+// Renew extends the lease of a running job by LeaseDuration from now. It
+// returns false when the job is no longer held by the claim.
+func (r *repository) Renew(id int, claimedAt time.Time) bool {
+	return r.fenced(RenewQuery, claimedAt, helpers.ToFormattedDateTime(time.Now().UTC().Add(LeaseDuration)), id)
+}
+
+// Progress stores how much of the job is done. It returns false when the job
+// is no longer held by the claim.
+func (r *repository) Progress(id int, claimedAt time.Time, progress int) bool {
+	return r.fenced(ProgressQuery, claimedAt, progress, id)
+}
+
+func (r *repository) CancelRequested(id int) bool {
+	var requested bool
+	if err := r.db.QueryRow(CancelRequestedQuery, id).Scan(&requested); err != nil {
+		panic(err)
+	}
+	return requested
+}
+
+// Succeed stores the result of the job. It returns false when the job is no
+// longer held by the claim, leaving it untouched.
+func (r *repository) Succeed(id int, claimedAt time.Time, result domain.JobResult) bool {
+	return r.fenced(SucceedQuery, claimedAt, domain.JobSucceeded, result.Content, result.ContentType, helpers.ToFormattedDateTime(time.Now().UTC()), id)
+}
+
+// Finish ends a running job as failed or canceled, keeping its progress. It
+// returns false when the job is no longer held by the claim, leaving it
+// untouched.
+func (r *repository) Finish(id int, claimedAt time.Time, status string, reason *string) bool {
+	return r.fenced(FinishQuery, claimedAt, status, reason, helpers.ToFormattedDateTime(time.Now().UTC()), id)
+}
+
+// fenced runs an update of a running job, whose last arguments are the job
+// status and claim, and tells whether it changed the job.
+func (r *repository) fenced(query string, claimedAt time.Time, args ...interface{}) bool {
+	args = append(args, domain.JobRunning, helpers.ToFormattedDateTime(claimedAt))
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	return affected > 0
+}
+
+// Cancel drops a queued job or asks a running one to stop. It returns false
+// when the job had already finished.
+func (r *repository) Cancel(id int) bool {
+	res, err := r.db.Exec(CancelQueuedQuery, domain.JobCanceled, helpers.ToFormattedDateTime(time.Now().UTC()), id, domain.JobQueued)
+	if err != nil {
+		panic(err)
+	}
+	if affected, _ := res.RowsAffected(); affected > 0 {
+		return true
+	}
+
+	res, err = r.db.Exec(RequestCancelQuery, id, domain.JobRunning)
+	if err != nil {
+		panic(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	return affected > 0
+}
+
+// Result returns the file produced by the job, or nil when it has not
+// succeeded.
+func (r *repository) Result(id int) *domain.JobResult {
+	result := domain.JobResult{}
+	err := r.db.QueryRow(ResultQuery, id, domain.JobSucceeded).Scan(&result.ContentType, &result.Content)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		panic(err)
+	}
+	return &result
+}
+
+func toOptionalDateTime(value *string) *time.Time {
+	if value == nil {
+		return nil
+	}
+	t := helpers.ToDateTime(*value)
+	return &t
+}
@@ -0,0 +1,231 @@
+package job_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/job"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	jobColumns = []string{"id", "kind", "status", "progress", "cancel_requested", "error", "created_at", "started_at", "finished_at"}
+)
+
+func TestRepositoryCreate(t *testing.T) {
+	t.Run("Should queue the job", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(job.InsertQuery)).
+			WithArgs(job.KindProductImport, domain.JobQueued, []byte(`{}`), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(4, 1))
+
+		repository := job.NewRepository(db)
+
+		assert.Equal(t, 4, repository.Create(job.KindProductImport, []byte(`{}`)))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryGet(t *testing.T) {
+	t.Run("Should return the job", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(job.GetQuery)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows(jobColumns).AddRow(4, job.KindProductImport, domain.JobRunning, 40, false, nil, "2023-07-10 08:00:00.000000", "2023-07-10 08:00:01.000000", nil))
+
+		repository := job.NewRepository(db)
+		result := repository.Get(4)
+
+		assert.Equal(t, 4, result.ID)
+		assert.Equal(t, domain.JobRunning, result.Status)
+		assert.Equal(t, 40, result.Progress)
+		assert.NotNil(t, result.StartedAt)
+		assert.Nil(t, result.FinishedAt)
+	})
+	t.Run("Should return nil when the job does not exist", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(job.GetQuery)).WithArgs(4).WillReturnError(sql.ErrNoRows)
+
+		repository := job.NewRepository(db)
+
+		assert.Nil(t, repository.Get(4))
+	})
+}
+
+func TestRepositoryClaim(t *testing.T) {
+	t.Run("Should start the oldest queued or abandoned job", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(job.NextQueuedQuery)).
+			WithArgs(domain.JobQueued, domain.JobRunning, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "payload", "created_at"}).AddRow(4, job.KindProductImport, []byte(`{}`), "2023-07-10 08:00:00.000000"))
+		mock.ExpectExec(regexp.QuoteMeta(job.StartQuery)).
+			WithArgs(domain.JobRunning, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repository := job.NewRepository(db)
+		result := repository.Claim()
+
+		assert.Equal(t, 4, result.ID)
+		assert.Equal(t, domain.JobRunning, result.Status)
+		assert.Equal(t, []byte(`{}`), result.Payload)
+		assert.Equal(t, *result.StartedAt, result.ClaimedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nil when the queue is empty", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(job.NextQueuedQuery)).WithArgs(domain.JobQueued, domain.JobRunning, sqlmock.AnyArg()).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		repository := job.NewRepository(db)
+
+		assert.Nil(t, repository.Claim())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryRenew(t *testing.T) {
+	claimedAt := time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC)
+
+	t.Run("Should extend the lease of the job held by the claim", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(job.RenewQuery)).
+			WithArgs(sqlmock.AnyArg(), 4, domain.JobRunning, "2023-07-10 08:00:00").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := job.NewRepository(db)
+
+		assert.True(t, repository.Renew(4, claimedAt))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return false when the job was claimed again", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(job.RenewQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := job.NewRepository(db)
+
+		assert.False(t, repository.Renew(4, claimedAt))
+	})
+}
+
+func TestRepositorySucceed(t *testing.T) {
+	claimedAt := time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC)
+
+	t.Run("Should store the result of the job held by the claim", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(job.SucceedQuery)).
+			WithArgs(domain.JobSucceeded, []byte("[]"), "application/json", sqlmock.AnyArg(), 4, domain.JobRunning, "2023-07-10 08:00:00").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := job.NewRepository(db)
+
+		assert.True(t, repository.Succeed(4, claimedAt, domain.JobResult{ContentType: "application/json", Content: []byte("[]")}))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should leave the job untouched when it was claimed again", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(job.SucceedQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := job.NewRepository(db)
+
+		assert.False(t, repository.Succeed(4, claimedAt, domain.JobResult{}))
+	})
+}
+
+func TestRepositoryCancel(t *testing.T) {
+	t.Run("Should drop a queued job", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(job.CancelQueuedQuery)).
+			WithArgs(domain.JobCanceled, sqlmock.AnyArg(), 4, domain.JobQueued).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := job.NewRepository(db)
+
+		assert.True(t, repository.Cancel(4))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should ask a running job to stop", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(job.CancelQueuedQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(job.RequestCancelQuery)).
+			WithArgs(4, domain.JobRunning).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := job.NewRepository(db)
+
+		assert.True(t, repository.Cancel(4))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return false when the job already finished", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(job.CancelQueuedQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(job.RequestCancelQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := job.NewRepository(db)
+
+		assert.False(t, repository.Cancel(4))
+	})
+}
+
+func TestRepositoryResult(t *testing.T) {
+	t.Run("Should return the result of a succeeded job", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(job.ResultQuery)).
+			WithArgs(4, domain.JobSucceeded).
+			WillReturnRows(sqlmock.NewRows([]string{"result_content_type", "result"}).AddRow("text/csv; charset=utf-8", []byte("id\n1\n")))
+
+		repository := job.NewRepository(db)
+
+		assert.Equal(t, &domain.JobResult{ContentType: "text/csv; charset=utf-8", Content: []byte("id\n1\n")}, repository.Result(4))
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(job.ResultQuery)).WillReturnError(sql.ErrConnDone)
+
+		repository := job.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Result(4) })
+	})
+}
+
+func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	return db, mock
+}
@@ -0,0 +1,83 @@
+package job
+
+import (
+	"encoding/json"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+)
+
+const (
+	ResourceNotFound = "job.not_found"
+	AlreadyFinished  = "job.already_finished"
+	NotSucceeded     = "job.not_succeeded"
+)
+
+type Service interface {
+	Enqueue(kind string, payload interface{}) *domain.Job
+	Get(id int) (*domain.Job, error)
+	Cancel(id int) (*domain.Job, error)
+	Result(id int) (*domain.JobResult, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &service{repository}
+}
+
+// Enqueue stores the payload as JSON and queues a job of the kind to be run
+// by the workers.
+func (s *service) Enqueue(kind string, payload interface{}) *domain.Job {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		panic(err)
+	}
+
+	return s.repository.Get(s.repository.Create(kind, encoded))
+}
+
+func (s *service) Get(id int) (*domain.Job, error) {
+	jobFound := s.repository.Get(id)
+
+	if jobFound == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	}
+
+	return jobFound, nil
+}
+
+// Cancel drops a queued job at once and asks a running one to stop at its
+// next progress report.
+func (s *service) Cancel(id int) (*domain.Job, error) {
+	jobFound, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if jobFound.CancelRequested {
+		return jobFound, nil
+	}
+
+	if jobFound.Finished() || !s.repository.Cancel(id) {
+		return nil, apperr.NewIncompatibleResource(AlreadyFinished, id, jobFound.Status)
+	}
+
+	return s.repository.Get(id), nil
+}
+
+func (s *service) Result(id int) (*domain.JobResult, error) {
+	jobFound, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := s.repository.Result(id)
+	if result == nil {
+		return nil, apperr.NewIncompatibleResource(NotSucceeded, id, jobFound.Status)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,82 @@
+package job_test
+
+import (
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/job"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/job/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceEnqueue(t *testing.T) {
+	t.Run("Should queue the payload as JSON", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		queued := &domain.Job{ID: 4, Kind: job.KindProductReport, Status: domain.JobQueued}
+		repository.On("Create", job.KindProductReport, []byte(`{"top":3}`)).Return(4)
+		repository.On("Get", 4).Return(queued)
+
+		result := service.Enqueue(job.KindProductReport, map[string]int{"top": 3})
+
+		assert.Equal(t, queued, result)
+	})
+}
+
+func TestServiceCancel(t *testing.T) {
+	t.Run("Should cancel a queued job", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		repository.On("Get", 4).Return(&domain.Job{ID: 4, Status: domain.JobQueued}).Once()
+		repository.On("Cancel", 4).Return(true)
+		repository.On("Get", 4).Return(&domain.Job{ID: 4, Status: domain.JobCanceled})
+
+		result, err := service.Cancel(4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.JobCanceled, result.Status)
+	})
+	t.Run("Should return incompatible resource error when the job already finished", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		repository.On("Get", 4).Return(&domain.Job{ID: 4, Status: domain.JobSucceeded})
+
+		_, err := service.Cancel(4)
+
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		repository.AssertNotCalled(t, "Cancel", 4)
+	})
+	t.Run("Should return not found error when the job does not exist", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		var jobFound *domain.Job
+		repository.On("Get", 4).Return(jobFound)
+
+		_, err := service.Cancel(4)
+
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func TestServiceResult(t *testing.T) {
+	t.Run("Should return incompatible resource error when the job has not succeeded", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		var result *domain.JobResult
+		repository.On("Get", 4).Return(&domain.Job{ID: 4, Status: domain.JobRunning})
+		repository.On("Result", 4).Return(result)
+
+		_, err := service.Result(4)
+
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+}
+
+func CreateService(t *testing.T) (job.Service, *mocks.Repository) {
+	t.Helper()
+	repository := new(mocks.Repository)
+	service := job.NewService(repository)
+
+	return service, repository
+}
@@ -39,3 +39,8 @@ func (r *Repository) CountSellersByLocality(id int) *domain.SellersByLocalityRep
 	args := r.Called(id)
 	return args.Get(0).(*domain.SellersByLocalityReport)
 }
+
+func (r *Repository) GetMany(ids []int) []domain.Locality {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.Locality)
+}
@@ -14,8 +14,8 @@ func (s *Service) Create(p domain.Locality) (*domain.Locality, error) {
 	return args.Get(0).(*domain.Locality), args.Error(1)
 }
 
-func (s *Service) CountSellersByAllLocalities() []domain.SellersByLocalityReport {
-	args := s.Called()
+func (s *Service) CountSellersByAllLocalities(top int) []domain.SellersByLocalityReport {
+	args := s.Called(top)
 	return args.Get(0).([]domain.SellersByLocalityReport)
 }
 
@@ -24,8 +24,8 @@ func (s *Service) CountSellersByLocality(id int) (*domain.SellersByLocalityRepor
 	return args.Get(0).(*domain.SellersByLocalityReport), args.Error(1)
 }
 
-func (s *Service) CountCarriersByAllLocalities() []domain.CarriersByLocalityReport {
-	args := s.Called()
+func (s *Service) CountCarriersByAllLocalities(top int) []domain.CarriersByLocalityReport {
+	args := s.Called(top)
 	return args.Get(0).([]domain.CarriersByLocalityReport)
 }
 
@@ -33,3 +33,8 @@ func (s *Service) CountCarriersByLocality(id int) (*domain.CarriersByLocalityRep
 	args := s.Called(id)
 	return args.Get(0).(*domain.CarriersByLocalityReport), args.Error(1)
 }
+
+func (s *Service) GetMany(ids []int) []domain.Locality {
+	args := s.Called(ids)
+	return args.Get(0).([]domain.Locality)
+}
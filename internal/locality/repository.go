@@ -5,12 +5,14 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetQuery    = "SELECT id, locality_name, province_id FROM localities WHERE id=?"
-	ExistsQuery = "SELECT locality_name FROM localities WHERE locality_name=?"
-	InsertQuery = "INSERT INTO localities (locality_name, province_id) VALUES (?, ?)"
+	GetQuery     = "SELECT id, locality_name, province_id FROM localities WHERE id=?"
+	GetManyQuery = "SELECT id, locality_name, province_id FROM localities WHERE id IN (%s)"
+	ExistsQuery  = "SELECT locality_name FROM localities WHERE locality_name=?"
+	InsertQuery  = "INSERT INTO localities (locality_name, province_id) VALUES (?, ?)"
 
 	CountSellersByAllLocalitiesQuery = `SELECT l.id "locality_id", l.locality_name, count(s.id) "sellers_count"
 		FROM localities l
@@ -38,6 +40,7 @@ const (
 // Repository encapsulates the storage of a Locality.
 type Repository interface {
 	Get(id int) *domain.Locality
+	GetMany(ids []int) []domain.Locality
 	Exists(localityName string) bool
 	Save(locality domain.Locality) int
 	CountSellersByAllLocalities() []domain.SellersByLocalityReport
@@ -70,6 +73,30 @@ func (r *repository) Get(id int) *domain.Locality {
 	return &l
 }
 
+// GetMany returns the localities found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.Locality {
+	localities := make([]domain.Locality, 0, len(ids))
+	if len(ids) == 0 {
+		return localities
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		l := domain.Locality{}
+		if err := rows.Scan(&l.ID, &l.LocalityName, &l.ProvinceID); err != nil {
+			panic(err)
+		}
+		localities = append(localities, l)
+	}
+	return localities
+}
+
 func (r *repository) Exists(name string) bool {
 	row := r.db.QueryRow(ExistsQuery, name)
 	err := row.Scan(&name)
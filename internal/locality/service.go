@@ -4,18 +4,20 @@ import (
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/province"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	LocalityNotFound      = "localização não encontrada com o id %d"
-	ProvinceNotFound      = "estado não encontrado com o id %d"
-	ResourceAlreadyExists = "uma localização com o nome '%s' já existe"
+	LocalityNotFound      = "locality.not_found"
+	ProvinceNotFound      = "province.not_found"
+	ResourceAlreadyExists = "locality.already_exists"
 )
 
 type Service interface {
-	CountSellersByAllLocalities() []domain.SellersByLocalityReport
+	GetMany(ids []int) []domain.Locality
+	CountSellersByAllLocalities(top int) []domain.SellersByLocalityReport
 	CountSellersByLocality(id int) (*domain.SellersByLocalityReport, error)
-	CountCarriersByAllLocalities() []domain.CarriersByLocalityReport
+	CountCarriersByAllLocalities(top int) []domain.CarriersByLocalityReport
 	CountCarriersByLocality(id int) (*domain.CarriersByLocalityReport, error)
 	Create(locality domain.Locality) (*domain.Locality, error)
 }
@@ -29,8 +31,17 @@ func NewService(repository Repository, provinceRepository province.Repository) S
 	return &service{repository, provinceRepository}
 }
 
-func (s *service) CountSellersByAllLocalities() []domain.SellersByLocalityReport {
-	return s.repository.CountSellersByAllLocalities()
+// GetMany returns the localities found among ids, leaving out the missing ones.
+func (s *service) GetMany(ids []int) []domain.Locality {
+	return s.repository.GetMany(ids)
+}
+
+// CountSellersByAllLocalities counts the sellers of every locality. When top
+// is positive, only the top localities with the most sellers are returned.
+func (s *service) CountSellersByAllLocalities(top int) []domain.SellersByLocalityReport {
+	report := s.repository.CountSellersByAllLocalities()
+
+	return helpers.Top(report, top, func(r domain.SellersByLocalityReport) int { return r.SellersCount })
 }
 
 func (s *service) CountSellersByLocality(id int) (*domain.SellersByLocalityReport, error) {
@@ -58,8 +69,13 @@ func (s *service) Create(locality domain.Locality) (*domain.Locality, error) {
 	return s.repository.Get(id), nil
 }
 
-func (s *service) CountCarriersByAllLocalities() []domain.CarriersByLocalityReport {
-	return s.repository.CountCarriersByAllLocalities()
+// CountCarriersByAllLocalities counts the carriers of every locality. When
+// top is positive, only the top localities with the most carriers are
+// returned.
+func (s *service) CountCarriersByAllLocalities(top int) []domain.CarriersByLocalityReport {
+	report := s.repository.CountCarriersByAllLocalities()
+
+	return helpers.Top(report, top, func(r domain.CarriersByLocalityReport) int { return r.CarriersCount })
 }
 
 func (s *service) CountCarriersByLocality(id int) (*domain.CarriersByLocalityReport, error) {
@@ -79,7 +79,7 @@ func TestServiceCountSellersByAllLocalities(t *testing.T) {
 
 		repository.On("CountSellersByAllLocalities").Return(mockedSellersByLocalitiesReport)
 
-		result := service.CountSellersByAllLocalities()
+		result := service.CountSellersByAllLocalities(0)
 
 		assert.Equal(t, 1, len(result))
 		assert.Equal(t, result[0], mockedSellersByLocalityReport)
@@ -137,7 +137,7 @@ func TestServiceCountCarriersByAllLocalities(t *testing.T) {
 
 		repository.On("CountCarriersByAllLocalities").Return(mockedSellersByLocalitiesReport)
 
-		result := service.CountCarriersByAllLocalities()
+		result := service.CountCarriersByAllLocalities(0)
 
 		assert.Equal(t, 1, len(result))
 		assert.Equal(t, result[0], mockedCarriersByLocalityReport)
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Repository struct {
+	mock.Mock
+}
+
+func (r *Repository) Lot(batchID int) []int {
+	args := r.Called(batchID)
+	return args.Get(0).([]int)
+}
+
+func (r *Repository) Upstream(batchID int) domain.LotUpstream {
+	args := r.Called(batchID)
+	return args.Get(0).(domain.LotUpstream)
+}
+
+func (r *Repository) Shipments(batchID int) []domain.LotShipment {
+	args := r.Called(batchID)
+	return args.Get(0).([]domain.LotShipment)
+}
+
+func (r *Repository) Reservations(batchID int) []domain.LotShipment {
+	args := r.Called(batchID)
+	return args.Get(0).([]domain.LotShipment)
+}
+
+func (r *Repository) Unallocated(batchID int) []domain.LotOrder {
+	args := r.Called(batchID)
+	return args.Get(0).([]domain.LotOrder)
+}
+
+func (r *Repository) SaveRecall(rc domain.Recall) int {
+	args := r.Called(rc)
+	return args.Int(0)
+}
+
+func (r *Repository) GetRecall(id int) *domain.Recall {
+	args := r.Called(id)
+	return args.Get(0).(*domain.Recall)
+}
+
+func (r *Repository) RecallBuyers(recallID int) []domain.RecallBuyer {
+	args := r.Called(recallID)
+	return args.Get(0).([]domain.RecallBuyer)
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Service struct {
+	mock.Mock
+}
+
+func (s *Service) Trace(batchID int) (*domain.LotTrace, error) {
+	args := s.Called(batchID)
+	return args.Get(0).(*domain.LotTrace), args.Error(1)
+}
+
+func (s *Service) Recall(rc domain.Recall) (*domain.Recall, error) {
+	args := s.Called(rc)
+	return args.Get(0).(*domain.Recall), args.Error(1)
+}
+
+func (s *Service) GetRecall(id int) (*domain.Recall, error) {
+	args := s.Called(id)
+	return args.Get(0).(*domain.Recall), args.Error(1)
+}
@@ -0,0 +1,331 @@
+package lot
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+)
+
+// ancestorsCTE selects the batch and, recursively, every batch it was split
+// from.
+const ancestorsCTE = `WITH RECURSIVE ancestors (id) AS (
+	SELECT CAST(? AS SIGNED)
+	UNION
+	SELECT sm.product_batch_id FROM stock_movements sm INNER JOIN ancestors ON sm.target_batch_id = ancestors.id WHERE sm.target_batch_id <> sm.product_batch_id
+	)`
+
+// originSelect selects the ancestor that was not split from another batch.
+const originSelect = `SELECT a.id FROM ancestors a
+	WHERE NOT EXISTS (SELECT 1 FROM stock_movements sm WHERE sm.target_batch_id = a.id AND sm.target_batch_id <> sm.product_batch_id)`
+
+// lotCTE selects the origin batch of the lot and, recursively, every batch
+// split from it, so parent and sibling batches of a split batch are included.
+const lotCTE = ancestorsCTE + `, lot (id) AS (
+	` + originSelect + `
+	UNION
+	SELECT sm.target_batch_id FROM stock_movements sm INNER JOIN lot ON sm.product_batch_id = lot.id WHERE sm.target_batch_id <> sm.product_batch_id
+	)`
+
+const (
+	LotQuery      = lotCTE + " SELECT id FROM lot ORDER BY id"
+	OriginQuery   = ancestorsCTE + " " + originSelect
+	UpstreamQuery = `SELECT p.id, p.product_code, COALESCE(s.id, 0), COALESCE(s.company_name, '')
+	FROM product_batches pb
+	INNER JOIN products p ON p.id = pb.product_id
+	LEFT JOIN sellers s ON s.id = p.id_seller
+	WHERE pb.id = ?`
+	ReceiptsQuery = `SELECT io.id, io.order_number, io.order_date, io.warehouse_id, e.id, e.first_name, e.last_name
+	FROM inbound_orders io
+	INNER JOIN employees e ON e.id = io.employee_id
+	WHERE io.product_batch_id = ?
+	ORDER BY io.order_date, io.id`
+	ShipmentsQuery = lotCTE + ` SELECT po.id, po.order_number, po.order_date, os.description, po.tracking_code, sm.product_batch_id, sm.quantity,
+	b.id, b.card_number_id, b.first_name, b.last_name, c.id, c.company_name, sm.created_at
+	FROM lot
+	INNER JOIN stock_movements sm ON sm.product_batch_id = lot.id AND sm.movement_type = ?
+	INNER JOIN purchase_orders po ON po.id = sm.purchase_order_id
+	INNER JOIN order_status os ON os.id = po.order_status_id
+	INNER JOIN buyers b ON b.id = po.buyer_id
+	LEFT JOIN carriers c ON c.id = po.carrier_id
+	ORDER BY sm.created_at, sm.id`
+	ReservationsQuery = lotCTE + ` SELECT po.id, po.order_number, po.order_date, os.description, po.tracking_code, sr.product_batch_id, sr.quantity,
+	b.id, b.card_number_id, b.first_name, b.last_name, c.id, c.company_name, sr.picked_at
+	FROM lot
+	INNER JOIN stock_reservations sr ON sr.product_batch_id = lot.id AND sr.picked_at IS NULL
+	INNER JOIN purchase_orders po ON po.id = sr.purchase_order_id
+	INNER JOIN order_status os ON os.id = po.order_status_id
+	INNER JOIN buyers b ON b.id = po.buyer_id
+	LEFT JOIN carriers c ON c.id = po.carrier_id
+	ORDER BY po.order_date, po.id, sr.product_batch_id`
+	UnallocatedQuery = `SELECT po.id, po.order_number, po.order_date, os.description, po.tracking_code, po.quantity,
+	b.id, b.card_number_id, b.first_name, b.last_name, c.id, c.company_name
+	FROM product_batches pb
+	INNER JOIN purchase_orders po ON po.order_date >= pb.manufacturing_date
+	INNER JOIN order_status os ON os.id = po.order_status_id
+	INNER JOIN buyers b ON b.id = po.buyer_id
+	LEFT JOIN carriers c ON c.id = po.carrier_id
+	WHERE pb.id = ? AND os.description <> 'Cancelled'
+	AND (EXISTS (SELECT 1 FROM product_records pr WHERE pr.id = po.product_record_id AND pr.product_id = pb.product_id)
+		OR EXISTS (SELECT 1 FROM order_details od INNER JOIN product_records pr ON pr.id = od.product_record_id WHERE od.purchase_order_id = po.id AND pr.product_id = pb.product_id))
+	AND NOT EXISTS (SELECT 1 FROM stock_reservations sr WHERE sr.purchase_order_id = po.id)
+	ORDER BY po.order_date, po.id`
+
+	InsertRecallQuery      = "INSERT INTO recalls (reason, employee_id, created_at) VALUES (?, ?, ?)"
+	InsertRecallBatchQuery = "INSERT INTO recall_batches (recall_id, product_batch_id) VALUES (?, ?)"
+	GetRecallQuery         = "SELECT id, reason, employee_id, created_at FROM recalls WHERE id = ?"
+	GetRecallBatchesQuery  = "SELECT product_batch_id FROM recall_batches WHERE recall_id = ? ORDER BY product_batch_id"
+	RecallBuyersQuery      = `SELECT b.id, b.card_number_id, b.first_name, b.last_name, po.id, po.order_number, po.tracking_code, sr.product_batch_id, sr.quantity
+	FROM recall_batches rb
+	INNER JOIN stock_reservations sr ON sr.product_batch_id = rb.product_batch_id
+	INNER JOIN purchase_orders po ON po.id = sr.purchase_order_id
+	INNER JOIN buyers b ON b.id = po.buyer_id
+	WHERE rb.recall_id = ?
+	ORDER BY b.id, po.id, sr.product_batch_id`
+)
+
+type Repository interface {
+	Lot(batchID int) []int
+	Upstream(batchID int) domain.LotUpstream
+	Shipments(batchID int) []domain.LotShipment
+	Reservations(batchID int) []domain.LotShipment
+	Unallocated(batchID int) []domain.LotOrder
+	SaveRecall(r domain.Recall) int
+	GetRecall(id int) *domain.Recall
+	RecallBuyers(recallID int) []domain.RecallBuyer
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// Lot returns the ids of every batch of the lot the batch belongs to: its
+// origin batch and every batch split from it.
+func (r *repository) Lot(batchID int) []int {
+	rows, err := r.db.Query(LotQuery, batchID)
+	if err != nil {
+		panic(err)
+	}
+	return scanIDs(rows)
+}
+
+// Upstream returns the product and seller of the batch along with the
+// inbound orders that received it. Split batches are never received, so the
+// chain is resolved from the origin batch of the lot.
+func (r *repository) Upstream(batchID int) domain.LotUpstream {
+	upstream := domain.LotUpstream{OriginBatchID: batchID, Receipts: make([]domain.LotReceipt, 0)}
+
+	if err := r.db.QueryRow(OriginQuery, batchID).Scan(&upstream.OriginBatchID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+
+	row := r.db.QueryRow(UpstreamQuery, upstream.OriginBatchID)
+	if err := row.Scan(&upstream.ProductID, &upstream.ProductCode, &upstream.SellerID, &upstream.SellerCompanyName); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+
+	rows, err := r.db.Query(ReceiptsQuery, upstream.OriginBatchID)
+	if err != nil {
+		panic(err)
+	}
+
+	for rows.Next() {
+		lr := domain.LotReceipt{}
+		var orderDate string
+		_ = rows.Scan(&lr.InboundOrderID, &lr.OrderNumber, &orderDate, &lr.WarehouseID, &lr.EmployeeID, &lr.EmployeeFirstName, &lr.EmployeeLastName)
+		lr.OrderDate = helpers.ToDateTime(orderDate)
+		upstream.Receipts = append(upstream.Receipts, lr)
+	}
+	return upstream
+}
+
+// Shipments returns the stock of any batch of the lot picked for purchase
+// orders, in the order it was picked, traced through the pick movements of
+// the stock ledger.
+func (r *repository) Shipments(batchID int) []domain.LotShipment {
+	rows, err := r.db.Query(ShipmentsQuery, batchID, domain.MovementPick)
+	if err != nil {
+		panic(err)
+	}
+	return scanShipments(rows)
+}
+
+// Reservations returns the stock of any batch of the lot reserved for
+// purchase orders and not picked yet.
+func (r *repository) Reservations(batchID int) []domain.LotShipment {
+	rows, err := r.db.Query(ReservationsQuery, batchID)
+	if err != nil {
+		panic(err)
+	}
+	return scanShipments(rows)
+}
+
+// Unallocated returns the purchase orders of the product of the lot, through
+// their product record or their order details, that reserve no stock and were
+// placed since the lot was manufactured. They are matched against the origin
+// batch of the lot.
+func (r *repository) Unallocated(batchID int) []domain.LotOrder {
+	originID := batchID
+	if err := r.db.QueryRow(OriginQuery, batchID).Scan(&originID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+
+	rows, err := r.db.Query(UnallocatedQuery, originID)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+	orders := make([]domain.LotOrder, 0)
+
+	for rows.Next() {
+		lo := domain.LotOrder{}
+		var orderDate string
+		_ = rows.Scan(&lo.PurchaseOrderID, &lo.OrderNumber, &orderDate, &lo.OrderStatus, &lo.TrackingCode, &lo.Quantity,
+			&lo.BuyerID, &lo.BuyerCardNumberID, &lo.BuyerFirstName, &lo.BuyerLastName, &lo.CarrierID, &lo.CarrierCompanyName)
+		lo.OrderDate = helpers.ToDateTime(orderDate)
+		orders = append(orders, lo)
+	}
+	return orders
+}
+
+// SaveRecall stores a recall covering every batch of the lots of the given
+// batches and quarantines the covered batches that are not quarantined or
+// disposed yet, all in the same transaction. It returns the id of the recall.
+func (r *repository) SaveRecall(rc domain.Recall) int {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	covered := make(map[int]bool)
+	for _, batchID := range rc.ProductBatchIDs {
+		rows, err := tx.Query(LotQuery, batchID)
+		if err != nil {
+			panic(err)
+		}
+		for _, id := range scanIDs(rows) {
+			covered[id] = true
+		}
+	}
+	batchIDs := make([]int, 0, len(covered))
+	for id := range covered {
+		batchIDs = append(batchIDs, id)
+	}
+	sort.Ints(batchIDs)
+
+	res, err := tx.Exec(InsertRecallQuery, rc.Reason, rc.EmployeeID, helpers.ToFormattedDateTime(time.Now().UTC()))
+	if err != nil {
+		panic(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, batchID := range batchIDs {
+		if _, err := tx.Exec(InsertRecallBatchQuery, id, batchID); err != nil {
+			panic(err)
+		}
+
+		_, err := product_batch.ApplyStatusChange(tx, domain.BatchStatusChange{
+			ProductBatchID: batchID,
+			ToStatus:       domain.BatchQuarantined,
+			Reason:         rc.Reason,
+			EmployeeID:     rc.EmployeeID,
+		})
+		if err != nil && !errors.Is(err, product_batch.ErrInvalidTransition) {
+			panic(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return int(id)
+}
+
+func (r *repository) GetRecall(id int) *domain.Recall {
+	rc := domain.Recall{ProductBatchIDs: make([]int, 0)}
+	var createdAt string
+
+	err := r.db.QueryRow(GetRecallQuery, id).Scan(&rc.ID, &rc.Reason, &rc.EmployeeID, &createdAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		panic(err)
+	}
+	rc.CreatedAt = helpers.ToDateTime(createdAt)
+
+	rows, err := r.db.Query(GetRecallBatchesQuery, id)
+	if err != nil {
+		panic(err)
+	}
+
+	for rows.Next() {
+		var batchID int
+		_ = rows.Scan(&batchID)
+		rc.ProductBatchIDs = append(rc.ProductBatchIDs, batchID)
+	}
+	return &rc
+}
+
+// RecallBuyers returns one line per buyer order holding stock of a batch
+// covered by the recall.
+func (r *repository) RecallBuyers(recallID int) []domain.RecallBuyer {
+	rows, err := r.db.Query(RecallBuyersQuery, recallID)
+	if err != nil {
+		panic(err)
+	}
+	buyers := make([]domain.RecallBuyer, 0)
+
+	for rows.Next() {
+		rb := domain.RecallBuyer{}
+		_ = rows.Scan(&rb.BuyerID, &rb.CardNumberID, &rb.FirstName, &rb.LastName, &rb.PurchaseOrderID, &rb.OrderNumber, &rb.TrackingCode, &rb.ProductBatchID, &rb.Quantity)
+		buyers = append(buyers, rb)
+	}
+	return buyers
+}
+
+func scanShipments(rows *sql.Rows) []domain.LotShipment {
+	defer rows.Close()
+	shipments := make([]domain.LotShipment, 0)
+
+	for rows.Next() {
+		ls := domain.LotShipment{}
+		var orderDate string
+		var pickedAt *string
+		_ = rows.Scan(&ls.PurchaseOrderID, &ls.OrderNumber, &orderDate, &ls.OrderStatus, &ls.TrackingCode, &ls.ProductBatchID, &ls.Quantity,
+			&ls.BuyerID, &ls.BuyerCardNumberID, &ls.BuyerFirstName, &ls.BuyerLastName, &ls.CarrierID, &ls.CarrierCompanyName, &pickedAt)
+		ls.OrderDate = helpers.ToDateTime(orderDate)
+		if pickedAt != nil {
+			t := helpers.ToDateTime(*pickedAt)
+			ls.PickedAt = &t
+		}
+		shipments = append(shipments, ls)
+	}
+	return shipments
+}
+
+func scanIDs(rows *sql.Rows) []int {
+	defer rows.Close()
+	ids := make([]int, 0)
+
+	for rows.Next() {
+		var id int
+		_ = rows.Scan(&id)
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,275 @@
+package lot_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/lot"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryLot(t *testing.T) {
+	t.Run("Should return the origin batch and the batches split from it", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(4).AddRow(9)
+		mock.ExpectQuery(regexp.QuoteMeta(lot.LotQuery)).WithArgs(4).WillReturnRows(rows)
+
+		repository := lot.NewRepository(db)
+
+		assert.Equal(t, []int{1, 4, 9}, repository.Lot(4))
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(lot.LotQuery)).WillReturnError(sql.ErrConnDone)
+		repository := lot.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Lot(1) })
+	})
+}
+
+func TestRepositoryUpstream(t *testing.T) {
+	t.Run("Should return the product, seller and receipts of the origin batch", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(lot.OriginQuery)).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta(lot.UpstreamQuery)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_code", "seller_id", "company_name"}).AddRow(2, "P002", 3, "Frigorifico"))
+		mock.ExpectQuery(regexp.QuoteMeta(lot.ReceiptsQuery)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_number", "order_date", "warehouse_id", "employee_id", "first_name", "last_name"}).
+				AddRow(5, "IN-5", "2023-07-10 08:00:00.000000", 1, 7, "Ana", "Souza"))
+
+		repository := lot.NewRepository(db)
+		result := repository.Upstream(4)
+
+		assert.Equal(t, domain.LotUpstream{
+			OriginBatchID:     1,
+			ProductID:         2,
+			ProductCode:       "P002",
+			SellerID:          3,
+			SellerCompanyName: "Frigorifico",
+			Receipts: []domain.LotReceipt{
+				{InboundOrderID: 5, OrderNumber: "IN-5", OrderDate: time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC), WarehouseID: 1, EmployeeID: 7, EmployeeFirstName: "Ana", EmployeeLastName: "Souza"},
+			},
+		}, result)
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(lot.OriginQuery)).WillReturnError(sql.ErrConnDone)
+		repository := lot.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Upstream(1) })
+	})
+}
+
+func TestRepositoryShipments(t *testing.T) {
+	t.Run("Should return the picks of the lot with their orders", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		columns := []string{"id", "order_number", "order_date", "description", "tracking_code", "product_batch_id", "quantity", "buyer_id", "card_number_id", "first_name", "last_name", "carrier_id", "company_name", "created_at"}
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, "order#1", "2023-07-11 00:00:00", "Pending", "TRACK1", 4, 3, 2, "B-2", "Joao", "Silva", nil, nil, "2023-07-12 09:30:00")
+		mock.ExpectQuery(regexp.QuoteMeta(lot.ShipmentsQuery)).WithArgs(1, domain.MovementPick).WillReturnRows(rows)
+
+		repository := lot.NewRepository(db)
+		result := repository.Shipments(1)
+
+		pickedAt := time.Date(2023, 7, 12, 9, 30, 0, 0, time.UTC)
+		assert.Equal(t, []domain.LotShipment{{
+			PurchaseOrderID:   1,
+			OrderNumber:       "order#1",
+			OrderDate:         time.Date(2023, 7, 11, 0, 0, 0, 0, time.UTC),
+			OrderStatus:       "Pending",
+			TrackingCode:      "TRACK1",
+			ProductBatchID:    4,
+			Quantity:          3,
+			BuyerID:           2,
+			BuyerCardNumberID: "B-2",
+			BuyerFirstName:    "Joao",
+			BuyerLastName:     "Silva",
+			PickedAt:          &pickedAt,
+		}}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryReservations(t *testing.T) {
+	t.Run("Should return the stock of the lot reserved and not picked yet", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		columns := []string{"id", "order_number", "order_date", "description", "tracking_code", "product_batch_id", "quantity", "buyer_id", "card_number_id", "first_name", "last_name", "carrier_id", "company_name", "picked_at"}
+		rows := sqlmock.NewRows(columns).
+			AddRow(2, "order#2", "2023-07-13 00:00:00", "Pending", "TRACK2", 4, 5, 2, "B-2", "Joao", "Silva", nil, nil, nil)
+		mock.ExpectQuery(regexp.QuoteMeta(lot.ReservationsQuery)).WithArgs(1).WillReturnRows(rows)
+
+		repository := lot.NewRepository(db)
+		result := repository.Reservations(1)
+
+		assert.Len(t, result, 1)
+		assert.Equal(t, 2, result[0].PurchaseOrderID)
+		assert.Equal(t, 5, result[0].Quantity)
+		assert.Nil(t, result[0].PickedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryUnallocated(t *testing.T) {
+	t.Run("Should return the orders of the product with no reservation, matched against the origin batch", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(lot.OriginQuery)).WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		columns := []string{"id", "order_number", "order_date", "description", "tracking_code", "quantity", "buyer_id", "card_number_id", "first_name", "last_name", "carrier_id", "company_name"}
+		rows := sqlmock.NewRows(columns).
+			AddRow(3, "order#3", "2023-07-02 00:00:00", "Processing", "TRACK3", 1, 2, "B-2", "Joao", "Silva", 1, "Fast Carrier")
+		mock.ExpectQuery(regexp.QuoteMeta(lot.UnallocatedQuery)).WithArgs(1).WillReturnRows(rows)
+
+		repository := lot.NewRepository(db)
+		result := repository.Unallocated(4)
+
+		carrierID := 1
+		carrier := "Fast Carrier"
+		assert.Equal(t, []domain.LotOrder{{
+			PurchaseOrderID:    3,
+			OrderNumber:        "order#3",
+			OrderDate:          time.Date(2023, 7, 2, 0, 0, 0, 0, time.UTC),
+			OrderStatus:        "Processing",
+			TrackingCode:       "TRACK3",
+			Quantity:           1,
+			BuyerID:            2,
+			BuyerCardNumberID:  "B-2",
+			BuyerFirstName:     "Joao",
+			BuyerLastName:      "Silva",
+			CarrierID:          &carrierID,
+			CarrierCompanyName: &carrier,
+		}}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositorySaveRecall(t *testing.T) {
+	recall := domain.Recall{Reason: "supplier recall", EmployeeID: 7, ProductBatchIDs: []int{4}}
+
+	t.Run("Should store the recall of the lot and quarantine its batches", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(lot.LotQuery)).WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(4))
+		mock.ExpectExec(regexp.QuoteMeta(lot.InsertRecallQuery)).
+			WithArgs(recall.Reason, recall.EmployeeID, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec(regexp.QuoteMeta(lot.InsertRecallBatchQuery)).WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchStatusQuery)).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "current_quantity", "section_id"}).AddRow(domain.BatchAvailable, 10, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.UpdateStatusQuery)).
+			WithArgs(domain.BatchQuarantined, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertStatusChangeQuery)).
+			WithArgs(1, domain.BatchAvailable, domain.BatchQuarantined, recall.Reason, recall.EmployeeID, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(lot.InsertRecallBatchQuery)).WithArgs(3, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchStatusQuery)).WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "current_quantity", "section_id"}).AddRow(domain.BatchQuarantined, 5, 2))
+		mock.ExpectCommit()
+
+		repository := lot.NewRepository(db)
+
+		assert.Equal(t, 3, repository.SaveRecall(recall))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic and rollback when a batch can not be stored", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(lot.LotQuery)).WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectExec(regexp.QuoteMeta(lot.InsertRecallQuery)).WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec(regexp.QuoteMeta(lot.InsertRecallBatchQuery)).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		repository := lot.NewRepository(db)
+
+		assert.Panics(t, func() { repository.SaveRecall(recall) })
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryGetRecall(t *testing.T) {
+	t.Run("Should return the recall with its batches", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(lot.GetRecallQuery)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "reason", "employee_id", "created_at"}).AddRow(3, "supplier recall", 7, "2023-07-12 09:00:00"))
+		mock.ExpectQuery(regexp.QuoteMeta(lot.GetRecallBatchesQuery)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"product_batch_id"}).AddRow(1).AddRow(4))
+
+		repository := lot.NewRepository(db)
+		result := repository.GetRecall(3)
+
+		assert.Equal(t, &domain.Recall{
+			ID:              3,
+			Reason:          "supplier recall",
+			EmployeeID:      7,
+			CreatedAt:       time.Date(2023, 7, 12, 9, 0, 0, 0, time.UTC),
+			ProductBatchIDs: []int{1, 4},
+		}, result)
+	})
+	t.Run("Should return nil when the recall does not exist", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(lot.GetRecallQuery)).WithArgs(3).WillReturnError(sql.ErrNoRows)
+		repository := lot.NewRepository(db)
+
+		assert.Nil(t, repository.GetRecall(3))
+	})
+}
+
+func TestRepositoryRecallBuyers(t *testing.T) {
+	t.Run("Should return the buyers affected by the recall", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		columns := []string{"buyer_id", "card_number_id", "first_name", "last_name", "purchase_order_id", "order_number", "tracking_code", "product_batch_id", "quantity"}
+		rows := sqlmock.NewRows(columns).AddRow(2, "B-2", "Joao", "Silva", 1, "order#1", "TRACK1", 4, 3)
+		mock.ExpectQuery(regexp.QuoteMeta(lot.RecallBuyersQuery)).WithArgs(3).WillReturnRows(rows)
+
+		repository := lot.NewRepository(db)
+
+		assert.Equal(t, []domain.RecallBuyer{
+			{BuyerID: 2, CardNumberID: "B-2", FirstName: "Joao", LastName: "Silva", PurchaseOrderID: 1, OrderNumber: "order#1", TrackingCode: "TRACK1", ProductBatchID: 4, Quantity: 3},
+		}, repository.RecallBuyers(3))
+	})
+}
+
+func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	return db, mock
+}
@@ -0,0 +1,84 @@
+package lot
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+)
+
+const (
+	ProductBatchNotFound = "product_batch.not_found"
+	EmployeeNotFound     = "employee.not_found"
+	RecallNotFound       = "recall.not_found"
+)
+
+type Service interface {
+	Trace(batchID int) (*domain.LotTrace, error)
+	Recall(rc domain.Recall) (*domain.Recall, error)
+	GetRecall(id int) (*domain.Recall, error)
+}
+
+type service struct {
+	repository             Repository
+	productBatchRepository product_batch.Repository
+	employeeRepository     employee.Repository
+}
+
+func NewService(repository Repository, productBatchRepository product_batch.Repository, employeeRepository employee.Repository) Service {
+	return &service{
+		repository,
+		productBatchRepository,
+		employeeRepository,
+	}
+}
+
+func (s *service) Trace(batchID int) (*domain.LotTrace, error) {
+	batchFound := s.productBatchRepository.Get(batchID)
+
+	if batchFound == nil {
+		return nil, apperr.NewResourceNotFound(ProductBatchNotFound, batchID)
+	}
+
+	return &domain.LotTrace{
+		ProductBatchID: batchFound.ID,
+		BatchNumber:    batchFound.BatchNumber,
+		Status:         batchFound.Status,
+		LotBatchIDs:    s.repository.Lot(batchID),
+		Upstream:       s.repository.Upstream(batchID),
+		Downstream:     s.repository.Shipments(batchID),
+		Reservations:   s.repository.Reservations(batchID),
+		Unallocated:    s.repository.Unallocated(batchID),
+	}, nil
+}
+
+// Recall records a recall of the given batches and quarantines every batch of
+// their lots that is not quarantined or disposed yet. The returned recall
+// lists the buyers with orders holding stock of those batches.
+func (s *service) Recall(rc domain.Recall) (*domain.Recall, error) {
+	if s.employeeRepository.Get(rc.EmployeeID) == nil {
+		return nil, apperr.NewDependentResourceNotFound(EmployeeNotFound, rc.EmployeeID)
+	}
+
+	found := helpers.ByID(s.productBatchRepository.GetMany(rc.ProductBatchIDs), func(pb domain.ProductBatch) int { return pb.ID })
+	for _, batchID := range rc.ProductBatchIDs {
+		if _, ok := found[batchID]; !ok {
+			return nil, apperr.NewDependentResourceNotFound(ProductBatchNotFound, batchID)
+		}
+	}
+
+	id := s.repository.SaveRecall(rc)
+	return s.GetRecall(id)
+}
+
+func (s *service) GetRecall(id int) (*domain.Recall, error) {
+	recallFound := s.repository.GetRecall(id)
+
+	if recallFound == nil {
+		return nil, apperr.NewResourceNotFound(RecallNotFound, id)
+	}
+
+	recallFound.AffectedBuyers = s.repository.RecallBuyers(id)
+	return recallFound, nil
+}
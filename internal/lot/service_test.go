@@ -0,0 +1,144 @@
+package lot_test
+
+import (
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	employee_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/lot"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/lot/mocks"
+	product_batch_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	recalledBatch  = domain.ProductBatch{ID: 1, BatchNumber: 10, ProductID: 2, SectionID: 1, Status: domain.BatchAvailable}
+	affectedBuyers = []domain.RecallBuyer{
+		{BuyerID: 2, CardNumberID: "B-2", FirstName: "Joao", LastName: "Silva", PurchaseOrderID: 1, OrderNumber: "order#1", TrackingCode: "TRACK1", ProductBatchID: 4, Quantity: 3},
+	}
+)
+
+func TestServiceTrace(t *testing.T) {
+	t.Run("Should return the upstream and downstream chains of the lot", func(t *testing.T) {
+		service, repository, productBatchRepository, _ := CreateService(t)
+
+		upstream := domain.LotUpstream{ProductID: 2, ProductCode: "P002", SellerID: 3, Receipts: []domain.LotReceipt{}}
+		shipments := []domain.LotShipment{{PurchaseOrderID: 1, ProductBatchID: 4, Quantity: 3, BuyerID: 2}}
+		reservations := []domain.LotShipment{{PurchaseOrderID: 2, ProductBatchID: 1, Quantity: 5, BuyerID: 2}}
+		unallocated := []domain.LotOrder{{PurchaseOrderID: 3, Quantity: 1, BuyerID: 2}}
+		productBatchRepository.On("Get", 1).Return(&recalledBatch)
+		repository.On("Lot", 1).Return([]int{1, 4})
+		repository.On("Upstream", 1).Return(upstream)
+		repository.On("Shipments", 1).Return(shipments)
+		repository.On("Reservations", 1).Return(reservations)
+		repository.On("Unallocated", 1).Return(unallocated)
+
+		result, err := service.Trace(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.LotTrace{
+			ProductBatchID: 1,
+			BatchNumber:    10,
+			Status:         domain.BatchAvailable,
+			LotBatchIDs:    []int{1, 4},
+			Upstream:       upstream,
+			Downstream:     shipments,
+			Reservations:   reservations,
+			Unallocated:    unallocated,
+		}, result)
+	})
+	t.Run("Should return not found error when the batch does not exist", func(t *testing.T) {
+		service, _, productBatchRepository, _ := CreateService(t)
+
+		var batchFound *domain.ProductBatch
+		productBatchRepository.On("Get", 1).Return(batchFound)
+
+		result, err := service.Trace(1)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func TestServiceRecall(t *testing.T) {
+	request := domain.Recall{Reason: "supplier recall", EmployeeID: 7, ProductBatchIDs: []int{1}}
+
+	t.Run("Should quarantine the lots and return the affected buyers", func(t *testing.T) {
+		service, repository, productBatchRepository, employeeRepository := CreateService(t)
+
+		employeeRepository.On("Get", 7).Return(&domain.Employee{ID: 7})
+		productBatchRepository.On("GetMany", []int{1}).Return([]domain.ProductBatch{recalledBatch})
+		repository.On("SaveRecall", request).Return(3)
+		recall := &domain.Recall{ID: 3, Reason: request.Reason, EmployeeID: 7, ProductBatchIDs: []int{1, 4}}
+		repository.On("GetRecall", 3).Return(recall)
+		repository.On("RecallBuyers", 3).Return(affectedBuyers)
+
+		result, err := service.Recall(request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.ID)
+		assert.Equal(t, affectedBuyers, result.AffectedBuyers)
+		productBatchRepository.AssertNotCalled(t, "ChangeStatus", mock.Anything)
+	})
+	t.Run("Should return dependent not found error when the employee does not exist", func(t *testing.T) {
+		service, repository, _, employeeRepository := CreateService(t)
+
+		var employeeFound *domain.Employee
+		employeeRepository.On("Get", 7).Return(employeeFound)
+
+		result, err := service.Recall(request)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
+		repository.AssertNotCalled(t, "SaveRecall")
+	})
+	t.Run("Should return dependent not found error when a batch does not exist", func(t *testing.T) {
+		service, repository, productBatchRepository, employeeRepository := CreateService(t)
+
+		employeeRepository.On("Get", 7).Return(&domain.Employee{ID: 7})
+		productBatchRepository.On("GetMany", []int{1}).Return([]domain.ProductBatch{})
+
+		result, err := service.Recall(request)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
+		repository.AssertNotCalled(t, "SaveRecall")
+	})
+}
+
+func TestServiceGetRecall(t *testing.T) {
+	t.Run("Should return the recall with its affected buyers", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		repository.On("GetRecall", 3).Return(&domain.Recall{ID: 3, ProductBatchIDs: []int{1}})
+		repository.On("RecallBuyers", 3).Return(affectedBuyers)
+
+		result, err := service.GetRecall(3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.Recall{ID: 3, ProductBatchIDs: []int{1}, AffectedBuyers: affectedBuyers}, result)
+	})
+	t.Run("Should return not found error when the recall does not exist", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		var recallFound *domain.Recall
+		repository.On("GetRecall", 3).Return(recallFound)
+
+		result, err := service.GetRecall(3)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func CreateService(t *testing.T) (lot.Service, *mocks.Repository, *product_batch_mocks.Repository, *employee_mocks.Repository) {
+	t.Helper()
+	repository := new(mocks.Repository)
+	productBatchRepository := new(product_batch_mocks.Repository)
+	employeeRepository := new(employee_mocks.Repository)
+	service := lot.NewService(repository, productBatchRepository, employeeRepository)
+
+	return service, repository, productBatchRepository, employeeRepository
+}
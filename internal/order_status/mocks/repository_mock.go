@@ -12,4 +12,9 @@ type Repository struct {
 func (r *Repository) Get(id int) *domain.OrderStatus {
 	args := r.Called(id)
 	return args.Get(0).(*domain.OrderStatus)
-}
\ No newline at end of file
+}
+
+func (r *Repository) GetMany(ids []int) []domain.OrderStatus {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.OrderStatus)
+}
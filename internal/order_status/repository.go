@@ -5,14 +5,17 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetQuery = "SELECT id FROM order_status WHERE id=?"
+	GetQuery     = "SELECT id FROM order_status WHERE id=?"
+	GetManyQuery = "SELECT id FROM order_status WHERE id IN (%s)"
 )
 
 type Repository interface {
 	Get(id int) *domain.OrderStatus
+	GetMany(ids []int) []domain.OrderStatus
 }
 
 type repository struct {
@@ -38,3 +41,27 @@ func (r *repository) Get(id int) *domain.OrderStatus {
 
 	return &order
 }
+
+// GetMany returns the order statuses found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.OrderStatus {
+	statuses := make([]domain.OrderStatus, 0, len(ids))
+	if len(ids) == 0 {
+		return statuses
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		order := domain.OrderStatus{}
+		if err := rows.Scan(&order.ID); err != nil {
+			panic(err)
+		}
+		statuses = append(statuses, order)
+	}
+	return statuses
+}
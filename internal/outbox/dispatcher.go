@@ -0,0 +1,108 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+)
+
+// DefaultBatchSize is the number of events the dispatcher loads at a time.
+const DefaultBatchSize = 100
+
+// Retries of the events the sinks reject. The wait before each retry doubles
+// from RetryBackoff up to MaxRetryBackoff, so an event is tried for about four
+// hours before MaxAttempts parks it as failed.
+const (
+	MaxAttempts     = 15
+	RetryBackoff    = time.Second
+	MaxRetryBackoff = time.Hour
+)
+
+// Dispatcher delivers the events of the outbox to every sink at least once,
+// in the order they were recorded unless a sink rejects one.
+type Dispatcher struct {
+	repository Repository
+	sinks      []Sink
+	interval   time.Duration
+}
+
+func NewDispatcher(repository Repository, interval time.Duration, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{
+		repository: repository,
+		sinks:      sinks,
+		interval:   interval,
+	}
+}
+
+// Start delivers the pending events at the given interval until ctx is done.
+func (d *Dispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			d.dispatchSafely()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// dispatchSafely keeps the dispatcher alive when the outbox cannot be
+// reached, waiting for the next tick to try again.
+func (d *Dispatcher) dispatchSafely() {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("outbox dispatcher: %v", r)
+		}
+	}()
+
+	for d.Dispatch() == DefaultBatchSize {
+	}
+}
+
+// Dispatch claims the next batch of pending events, publishes each one to
+// every sink and returns how many were claimed. An event a sink rejects is
+// retried after a backoff, publishing it again to the sinks that had accepted
+// it, while the events after it go on; once rejected MaxAttempts times it is
+// parked as failed.
+func (d *Dispatcher) Dispatch() int {
+	events := d.repository.Claim(DefaultBatchSize)
+	for _, event := range events {
+		err := d.publish(event)
+		switch {
+		case err == nil:
+			d.repository.Dispatched(event.ID)
+		case event.Attempts+1 >= MaxAttempts:
+			d.repository.Park(event.ID, err.Error())
+		default:
+			d.repository.Failed(event.ID, err.Error(), time.Now().UTC().Add(Backoff(event.Attempts+1)))
+		}
+	}
+	return len(events)
+}
+
+// Backoff returns how long to wait before retrying an event after its given
+// number of failed attempts.
+func Backoff(attempts int) time.Duration {
+	wait := RetryBackoff << (attempts - 1)
+	if wait <= 0 || wait > MaxRetryBackoff {
+		return MaxRetryBackoff
+	}
+	return wait
+}
+
+func (d *Dispatcher) publish(event domain.Event) error {
+	for _, sink := range d.sinks {
+		if err := sink.Publish(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
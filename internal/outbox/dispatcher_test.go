@@ -0,0 +1,91 @@
+package outbox_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	pendingEvents = []domain.Event{
+		{ID: 1, Type: domain.EventPurchaseOrderCreated, AggregateID: 4},
+		{ID: 2, Type: domain.EventInboundOrderReceived, AggregateID: 5},
+	}
+)
+
+type failingSink struct {
+	failOn int
+}
+
+func (s failingSink) Publish(event domain.Event) error {
+	if event.ID == s.failOn {
+		return errors.New("sink unavailable")
+	}
+	return nil
+}
+
+func TestDispatcherDispatch(t *testing.T) {
+	t.Run("Should deliver the pending events to every sink", func(t *testing.T) {
+		repository := new(mocks.Repository)
+		first, second := outbox.NewMemorySink(), outbox.NewMemorySink()
+		dispatcher := outbox.NewDispatcher(repository, time.Second, first, second)
+
+		repository.On("Claim", outbox.DefaultBatchSize).Return(pendingEvents)
+		repository.On("Dispatched", 1).Return()
+		repository.On("Dispatched", 2).Return()
+
+		assert.Equal(t, 2, dispatcher.Dispatch())
+		assert.Equal(t, pendingEvents, first.Events())
+		assert.Equal(t, pendingEvents, second.Events())
+		repository.AssertExpectations(t)
+	})
+	t.Run("Should retry the rejected event after a backoff and deliver the next ones", func(t *testing.T) {
+		repository := new(mocks.Repository)
+		sink := outbox.NewMemorySink()
+		dispatcher := outbox.NewDispatcher(repository, time.Second, sink, failingSink{failOn: 1})
+
+		repository.On("Claim", outbox.DefaultBatchSize).Return(pendingEvents)
+		repository.On("Failed", 1, "sink unavailable", mock.Anything).Return()
+		repository.On("Dispatched", 2).Return()
+
+		before := time.Now().UTC()
+		assert.Equal(t, 2, dispatcher.Dispatch())
+		assert.Len(t, sink.Events(), 2)
+		repository.AssertNotCalled(t, "Dispatched", 1)
+		repository.AssertCalled(t, "Failed", 1, "sink unavailable", mock.MatchedBy(func(next time.Time) bool {
+			return !next.Before(before.Add(outbox.RetryBackoff))
+		}))
+		repository.AssertExpectations(t)
+	})
+	t.Run("Should park the event rejected too many times and deliver the next ones", func(t *testing.T) {
+		repository := new(mocks.Repository)
+		sink := outbox.NewMemorySink()
+		dispatcher := outbox.NewDispatcher(repository, time.Second, sink, failingSink{failOn: 1})
+		poisoned := []domain.Event{
+			{ID: 1, Type: domain.EventPurchaseOrderCreated, AggregateID: 4, Attempts: outbox.MaxAttempts - 1},
+			pendingEvents[1],
+		}
+		repository.On("Claim", outbox.DefaultBatchSize).Return(poisoned)
+		repository.On("Park", 1, "sink unavailable").Return()
+		repository.On("Dispatched", 2).Return()
+
+		assert.Equal(t, 2, dispatcher.Dispatch())
+		repository.AssertNotCalled(t, "Failed", 1, "sink unavailable", mock.Anything)
+		repository.AssertNotCalled(t, "Dispatched", 1)
+		repository.AssertExpectations(t)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Run("Should double the wait after each attempt up to the maximum", func(t *testing.T) {
+		assert.Equal(t, outbox.RetryBackoff, outbox.Backoff(1))
+		assert.Equal(t, 4*outbox.RetryBackoff, outbox.Backoff(3))
+		assert.Equal(t, outbox.MaxRetryBackoff, outbox.Backoff(outbox.MaxAttempts))
+	})
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Repository struct {
+	mock.Mock
+}
+
+func (r *Repository) Get(id int) *domain.Event {
+	args := r.Called(id)
+	return args.Get(0).(*domain.Event)
+}
+
+func (r *Repository) Claim(limit int) []domain.Event {
+	args := r.Called(limit)
+	return args.Get(0).([]domain.Event)
+}
+
+func (r *Repository) Dispatched(id int) {
+	r.Called(id)
+}
+
+func (r *Repository) Failed(id int, reason string, nextAttemptAt time.Time) {
+	r.Called(id, reason, nextAttemptAt)
+}
+
+func (r *Repository) Park(id int, reason string) {
+	r.Called(id, reason)
+}
@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+)
+
+const (
+	InsertQuery     = "INSERT INTO outbox (event_type, aggregate_id, payload, created_at) VALUES (?, ?, ?, ?)"
+	GetQuery        = "SELECT id, event_type, aggregate_id, payload, attempts, created_at FROM outbox WHERE id = ?"
+	PendingQuery    = "SELECT id, event_type, aggregate_id, payload, attempts, created_at FROM outbox WHERE dispatched_at IS NULL AND failed_at IS NULL AND (next_attempt_at IS NULL OR next_attempt_at <= ?) ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED"
+	LeaseQuery      = "UPDATE outbox SET next_attempt_at = ? WHERE id = ?"
+	DispatchedQuery = "UPDATE outbox SET dispatched_at = ?, last_error = NULL WHERE id = ?"
+	FailedQuery     = "UPDATE outbox SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?"
+	ParkQuery       = "UPDATE outbox SET attempts = attempts + 1, last_error = ?, failed_at = ? WHERE id = ?"
+)
+
+// Record writes the event in the outbox within tx, so that it is stored only
+// when the change it describes is committed.
+func Record(tx *sql.Tx, eventType string, aggregateID int, payload interface{}) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		panic(err)
+	}
+
+	if _, err := tx.Exec(InsertQuery, eventType, aggregateID, encoded, helpers.ToFormattedDateTime(time.Now().UTC())); err != nil {
+		panic(err)
+	}
+}
+
+// ClaimLease is how long the events claimed by a dispatcher are kept from the
+// others while they are being delivered.
+const ClaimLease = time.Minute
+
+// rowScanner is the part of sql.Row and sql.Rows the rows are read with.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type Repository interface {
+	Get(id int) *domain.Event
+	Claim(limit int) []domain.Event
+	Dispatched(id int)
+	Failed(id int, reason string, nextAttemptAt time.Time)
+	Park(id int, reason string)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Get(id int) *domain.Event {
+	e, err := scanEvent(r.db.QueryRow(GetQuery, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		panic(err)
+	}
+	return &e
+}
+
+// Claim returns the oldest events not delivered yet whose next attempt is
+// due, in the order they were recorded, and postpones them by ClaimLease so
+// that no other dispatcher delivers them meanwhile. Rows locked by other
+// dispatchers are skipped.
+func (r *repository) Claim(limit int) []domain.Event {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	rows, err := tx.Query(PendingQuery, helpers.ToFormattedDateTime(now), limit)
+	if err != nil {
+		panic(err)
+	}
+	events := make([]domain.Event, 0)
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			panic(err)
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+
+	lease := helpers.ToFormattedDateTime(now.Add(ClaimLease))
+	for _, e := range events {
+		if _, err := tx.Exec(LeaseQuery, lease, e.ID); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return events
+}
+
+func (r *repository) Dispatched(id int) {
+	if _, err := r.db.Exec(DispatchedQuery, helpers.ToFormattedDateTime(time.Now().UTC()), id); err != nil {
+		panic(err)
+	}
+}
+
+// Failed counts a failed delivery of the event, which stays pending until
+// its next attempt.
+func (r *repository) Failed(id int, reason string, nextAttemptAt time.Time) {
+	if _, err := r.db.Exec(FailedQuery, reason, helpers.ToFormattedDateTime(nextAttemptAt), id); err != nil {
+		panic(err)
+	}
+}
+
+// Park counts the last failed delivery of the event and marks it as failed, so
+// that it is no longer pending and stays in the outbox as a dead letter.
+func (r *repository) Park(id int, reason string) {
+	if _, err := r.db.Exec(ParkQuery, reason, helpers.ToFormattedDateTime(time.Now().UTC()), id); err != nil {
+		panic(err)
+	}
+}
+
+func scanEvent(row rowScanner) (domain.Event, error) {
+	e := domain.Event{}
+	var payload []byte
+	var createdAt string
+	if err := row.Scan(&e.ID, &e.Type, &e.AggregateID, &payload, &e.Attempts, &createdAt); err != nil {
+		return e, err
+	}
+	e.Payload = payload
+	e.CreatedAt = helpers.ToDateTime(createdAt)
+	return e, nil
+}
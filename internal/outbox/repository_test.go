@@ -0,0 +1,110 @@
+package outbox_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	t.Run("Should write the event within the transaction", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(outbox.InsertQuery)).
+			WithArgs(domain.EventInboundOrderReceived, 3, []byte(`{"id":3}`), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		outbox.Record(tx, domain.EventInboundOrderReceived, 3, map[string]int{"id": 3})
+		assert.NoError(t, tx.Commit())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(outbox.InsertQuery)).WillReturnError(sql.ErrConnDone)
+
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		assert.Panics(t, func() { outbox.Record(tx, domain.EventInboundOrderReceived, 3, nil) })
+	})
+}
+
+func TestRepositoryClaim(t *testing.T) {
+	t.Run("Should return the due events not delivered yet and lease them", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(outbox.PendingQuery)).
+			WithArgs(sqlmock.AnyArg(), 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "payload", "attempts", "created_at"}).
+				AddRow(1, domain.EventPurchaseOrderCreated, 4, []byte(`{"id":4}`), 0, "2023-07-10 08:00:00.000000").
+				AddRow(2, domain.EventProductBatchCreated, 7, []byte(`{"id":7}`), 2, "2023-07-10 08:00:01.000000"))
+
+		mock.ExpectExec(regexp.QuoteMeta(outbox.LeaseQuery)).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.LeaseQuery)).WithArgs(sqlmock.AnyArg(), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repository := outbox.NewRepository(db)
+		result := repository.Claim(10)
+
+		assert.Len(t, result, 2)
+		assert.Equal(t, domain.EventPurchaseOrderCreated, result[0].Type)
+		assert.JSONEq(t, `{"id":7}`, string(result[1].Payload))
+		assert.Equal(t, 2, result[1].Attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryFailed(t *testing.T) {
+	t.Run("Should count the failed delivery and schedule the next one", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(outbox.FailedQuery)).
+			WithArgs("timeout", "2023-07-10 08:00:02", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := outbox.NewRepository(db)
+		repository.Failed(1, "timeout", time.Date(2023, 7, 10, 8, 0, 2, 0, time.UTC))
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryPark(t *testing.T) {
+	t.Run("Should count the failed delivery and mark the event as failed", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(outbox.ParkQuery)).
+			WithArgs("timeout", sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := outbox.NewRepository(db)
+		repository.Park(1, "timeout")
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	return db, mock
+}
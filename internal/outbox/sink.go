@@ -0,0 +1,49 @@
+package outbox
+
+import (
+	"log"
+	"sync"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+)
+
+// Sink delivers the events to their consumers. An error leaves the event
+// pending, so a sink may receive the same event more than once and its
+// consumers must be idempotent.
+type Sink interface {
+	Publish(event domain.Event) error
+}
+
+// MemorySink keeps the events it receives, for tests.
+type MemorySink struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{events: make([]domain.Event, 0)}
+}
+
+func (s *MemorySink) Publish(event domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+	return nil
+}
+
+// Events returns a copy of the events received so far.
+func (s *MemorySink) Events() []domain.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]domain.Event(nil), s.events...)
+}
+
+// LogSink writes the events to the standard logger.
+type LogSink struct{}
+
+func (LogSink) Publish(event domain.Event) error {
+	log.Printf("event %d %s of %d: %s", event.ID, event.Type, event.AggregateID, event.Payload)
+	return nil
+}
@@ -14,6 +14,13 @@ func (r *Repository) GetAll() []domain.Product {
 	return args.Get(0).([]domain.Product)
 }
 
+func (r *Repository) StreamAll(yield func(domain.Product)) {
+	args := r.Called()
+	for _, p := range args.Get(0).([]domain.Product) {
+		yield(p)
+	}
+}
+
 func (r *Repository) Get(id int) *domain.Product {
 	args := r.Called(id)
 	return args.Get(0).(*domain.Product)
@@ -24,24 +31,44 @@ func (r *Repository) Exists(productCode string) bool {
 	return args.Get(0).(bool)
 }
 
+func (r *Repository) Existing(productCodes []string) []string {
+	args := r.Called(productCodes)
+	return args.Get(0).([]string)
+}
+
 func (r *Repository) Save(product domain.Product) int {
 	args := r.Called(product)
 	return args.Get(0).(int)
 }
 
-func (r *Repository) Update(product domain.Product) {
-	r.Called(product)
+func (r *Repository) SaveAll(products []domain.Product) []int {
+	args := r.Called(products)
+	return args.Get(0).([]int)
+}
+
+func (r *Repository) Update(product domain.Product) error {
+	args := r.Called(product)
+	return args.Error(0)
 }
 
 func (r *Repository) Delete(id int) {
 	r.Called(id)
 }
 
-func (r *Repository) CountRecordsByAllProducts() []domain.RecordsByProductReport {
-	args := r.Called()
+func (r *Repository) CountRecordsByAllProducts(dates domain.DateRange) []domain.RecordsByProductReport {
+	args := r.Called(dates)
 	return args.Get(0).([]domain.RecordsByProductReport)
 }
-func (r *Repository) CountRecordsByProduct(id int) *domain.RecordsByProductReport {
-	args := r.Called(id)
+func (r *Repository) CountRecordsByProduct(id int, dates domain.DateRange) *domain.RecordsByProductReport {
+	args := r.Called(id, dates)
 	return args.Get(0).(*domain.RecordsByProductReport)
 }
+func (r *Repository) CountRecordsByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod {
+	args := r.Called(id, period, dates)
+	return args.Get(0).([]domain.ReportPeriod)
+}
+
+func (r *Repository) GetMany(ids []int) []domain.Product {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.Product)
+}
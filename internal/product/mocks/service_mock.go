@@ -14,6 +14,13 @@ func (s *Service) GetAll() []domain.Product {
 	return args.Get(0).([]domain.Product)
 }
 
+func (s *Service) StreamAll(yield func(domain.Product)) {
+	args := s.Called()
+	for _, p := range args.Get(0).([]domain.Product) {
+		yield(p)
+	}
+}
+
 func (s *Service) Get(id int) (*domain.Product, error) {
 	args := s.Called(id)
 	return args.Get(0).(*domain.Product), args.Error(1)
@@ -24,8 +31,13 @@ func (s *Service) Create(p domain.Product) (*domain.Product, error) {
 	return args.Get(0).(*domain.Product), args.Error(1)
 }
 
-func (s *Service) Update(id int, p domain.UpdateProduct) (*domain.Product, error) {
-	args := s.Called(id, p)
+func (s *Service) Import(products []domain.Product, options domain.ImportOptions) ([]int, []error) {
+	args := s.Called(products, options)
+	return args.Get(0).([]int), args.Get(1).([]error)
+}
+
+func (s *Service) Update(current domain.Product, p domain.Product) (*domain.Product, error) {
+	args := s.Called(current, p)
 	return args.Get(0).(*domain.Product), args.Error(1)
 }
 
@@ -34,12 +46,22 @@ func (s *Service) Delete(id int) error {
 	return args.Error(0)
 }
 
-func (s *Service) CountRecordsByAllProducts() []domain.RecordsByProductReport {
-	args := s.Called()
+func (s *Service) CountRecordsByAllProducts(dates domain.DateRange, top int) []domain.RecordsByProductReport {
+	args := s.Called(dates, top)
 	return args.Get(0).([]domain.RecordsByProductReport)
 }
 
-func (s *Service) CountRecordsByProduct(id int) (*domain.RecordsByProductReport, error) {
-	args := s.Called(id)
+func (s *Service) CountRecordsByProduct(id int, dates domain.DateRange) (*domain.RecordsByProductReport, error) {
+	args := s.Called(id, dates)
 	return args.Get(0).(*domain.RecordsByProductReport), args.Error(1)
 }
+
+func (s *Service) CountRecordsByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error) {
+	args := s.Called(id, period, dates)
+	return args.Get(0).([]domain.ReportPeriod), args.Error(1)
+}
+
+func (s *Service) GetMany(ids []int) []domain.Product {
+	args := s.Called(ids)
+	return args.Get(0).([]domain.Product)
+}
@@ -5,39 +5,62 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetAllQuery = "SELECT id, description, expiration_rate, freezing_rate, height, lenght, netweight, product_code, recommended_freezing_temperature, width, id_product_type, id_seller FROM products;"
-	GetQuery    = "SELECT id, description, expiration_rate, freezing_rate, height, lenght, netweight, product_code, recommended_freezing_temperature, width, id_product_type, id_seller FROM products WHERE id=?;"
-	ExistsQuery = "SELECT product_code FROM products WHERE product_code=?;"
-	InsertQuery = "INSERT INTO products(description,expiration_rate,freezing_rate,height,lenght,netweight,product_code,recommended_freezing_temperature,width,id_product_type,id_seller) VALUES (?,?,?,?,?,?,?,?,?,?,?)"
-	UpdateQuery = "UPDATE products SET description=?, expiration_rate=?, freezing_rate=?, height=?, lenght=?, netweight=?, product_code=?, recommended_freezing_temperature=?, width=?, id_product_type=?, id_seller=?  WHERE id=?"
-	DeleteQuery = "DELETE FROM products WHERE id=?"
+	GetAllQuery   = "SELECT id, description, expiration_rate, freezing_rate, height, lenght, netweight, product_code, recommended_freezing_temperature, width, id_product_type, id_seller FROM products;"
+	GetQuery      = "SELECT id, description, expiration_rate, freezing_rate, height, lenght, netweight, product_code, recommended_freezing_temperature, width, id_product_type, id_seller, version FROM products WHERE id=?;"
+	GetManyQuery  = "SELECT id, description, expiration_rate, freezing_rate, height, lenght, netweight, product_code, recommended_freezing_temperature, width, id_product_type, id_seller FROM products WHERE id IN (%s);"
+	ExistsQuery   = "SELECT product_code FROM products WHERE product_code=?;"
+	ExistingQuery = "SELECT product_code FROM products WHERE product_code IN (%s);"
+	InsertQuery   = "INSERT INTO products(description,expiration_rate,freezing_rate,height,lenght,netweight,product_code,recommended_freezing_temperature,width,id_product_type,id_seller) VALUES (?,?,?,?,?,?,?,?,?,?,?)"
+	UpdateQuery   = "UPDATE products SET description=?, expiration_rate=?, freezing_rate=?, height=?, lenght=?, netweight=?, product_code=?, recommended_freezing_temperature=?, width=?, id_product_type=?, id_seller=?, version=version+1 WHERE id=? AND version=?"
+	DeleteQuery   = "DELETE FROM products WHERE id=?"
 
 	CountRecordsByAllProductsQuery = `SELECT p.id "product_id", p.description, count(pr.id) "records_count"
 		FROM products p
 		LEFT JOIN product_records pr ON p.id = pr.product_id
+		AND (? IS NULL OR pr.last_update_date >= ?) AND (? IS NULL OR pr.last_update_date < ?)
 		GROUP BY p.id`
 
 	CountRecordsByProductQuery = `SELECT p.id "product_id", p.description, count(pr.id) "records_count"
 		FROM products p
 		LEFT JOIN product_records pr ON p.id = pr.product_id
+		AND (? IS NULL OR pr.last_update_date >= ?) AND (? IS NULL OR pr.last_update_date < ?)
 		WHERE p.id=?
 		GROUP BY p.id`
+
+	CountRecordsByPeriodQuery = `SELECT CASE ?
+		WHEN 'month' THEN DATE_FORMAT(pr.last_update_date, '%Y-%m-01 00:00:00')
+		WHEN 'week' THEN DATE_FORMAT(pr.last_update_date - INTERVAL WEEKDAY(pr.last_update_date) DAY, '%Y-%m-%d 00:00:00')
+		ELSE DATE_FORMAT(pr.last_update_date, '%Y-%m-%d 00:00:00') END "period", count(pr.id) "records_count"
+		FROM product_records pr
+		WHERE (? IS NULL OR pr.product_id = ?) AND (? IS NULL OR pr.last_update_date >= ?) AND (? IS NULL OR pr.last_update_date < ?)
+		GROUP BY 1
+		ORDER BY 1`
 )
 
 type Repository interface {
 	GetAll() []domain.Product
+	StreamAll(yield func(domain.Product))
 	Get(id int) *domain.Product
+	GetMany(ids []int) []domain.Product
 	Exists(productCode string) bool
+	Existing(productCodes []string) []string
 	Save(p domain.Product) int
-	Update(p domain.Product)
+	SaveAll(products []domain.Product) []int
+	Update(p domain.Product) error
 	Delete(id int)
-	CountRecordsByAllProducts() []domain.RecordsByProductReport
-	CountRecordsByProduct(id int) *domain.RecordsByProductReport
+	CountRecordsByAllProducts(dates domain.DateRange) []domain.RecordsByProductReport
+	CountRecordsByProduct(id int, dates domain.DateRange) *domain.RecordsByProductReport
+	CountRecordsByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod
 }
 
+// ErrModified is returned by Update when the product was changed or deleted
+// after the version being updated was read.
+var ErrModified = errors.New("product modified since read")
+
 type repository struct {
 	db *sql.DB
 }
@@ -49,26 +72,35 @@ func NewRepository(db *sql.DB) Repository {
 }
 
 func (r *repository) GetAll() []domain.Product {
+	products := make([]domain.Product, 0)
+
+	r.StreamAll(func(p domain.Product) {
+		products = append(products, p)
+	})
+
+	return products
+}
+
+// StreamAll yields the products one by one as they are read, without loading
+// them all in memory.
+func (r *repository) StreamAll(yield func(domain.Product)) {
 	rows, err := r.db.Query(GetAllQuery)
 	if err != nil {
 		panic(err)
 	}
-
-	products := make([]domain.Product, 0)
+	defer rows.Close()
 
 	for rows.Next() {
 		p := domain.Product{}
 		_ = rows.Scan(&p.ID, &p.Description, &p.ExpirationRate, &p.FreezingRate, &p.Height, &p.Length, &p.Netweight, &p.ProductCode, &p.RecomFreezTemp, &p.Width, &p.ProductTypeID, &p.SellerID)
-		products = append(products, p)
+		yield(p)
 	}
-
-	return products
 }
 
 func (r *repository) Get(id int) *domain.Product {
 	row := r.db.QueryRow(GetQuery, id)
 	p := domain.Product{}
-	err := row.Scan(&p.ID, &p.Description, &p.ExpirationRate, &p.FreezingRate, &p.Height, &p.Length, &p.Netweight, &p.ProductCode, &p.RecomFreezTemp, &p.Width, &p.ProductTypeID, &p.SellerID)
+	err := row.Scan(&p.ID, &p.Description, &p.ExpirationRate, &p.FreezingRate, &p.Height, &p.Length, &p.Netweight, &p.ProductCode, &p.RecomFreezTemp, &p.Width, &p.ProductTypeID, &p.SellerID, &p.Version)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -80,12 +112,61 @@ func (r *repository) Get(id int) *domain.Product {
 	return &p
 }
 
+// GetMany returns the products found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.Product {
+	products := make([]domain.Product, 0, len(ids))
+	if len(ids) == 0 {
+		return products
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := domain.Product{}
+		if err := rows.Scan(&p.ID, &p.Description, &p.ExpirationRate, &p.FreezingRate, &p.Height, &p.Length, &p.Netweight, &p.ProductCode, &p.RecomFreezTemp, &p.Width, &p.ProductTypeID, &p.SellerID); err != nil {
+			panic(err)
+		}
+		products = append(products, p)
+	}
+	return products
+}
+
 func (r *repository) Exists(productCode string) bool {
 	row := r.db.QueryRow(ExistsQuery, productCode)
 	err := row.Scan(&productCode)
 	return err == nil
 }
 
+// Existing returns which of the given product codes are already taken, looking
+// them up with a single query.
+func (r *repository) Existing(productCodes []string) []string {
+	existing := make([]string, 0, len(productCodes))
+	if len(productCodes) == 0 {
+		return existing
+	}
+
+	query, args := helpers.ExpandIn(ExistingQuery, productCodes)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productCode string
+		if err := rows.Scan(&productCode); err != nil {
+			panic(err)
+		}
+		existing = append(existing, productCode)
+	}
+	return existing
+}
+
 func (r *repository) Save(p domain.Product) int {
 	stmt, err := r.db.Prepare(InsertQuery)
 	if err != nil {
@@ -105,16 +186,60 @@ func (r *repository) Save(p domain.Product) int {
 	return int(id)
 }
 
-func (r *repository) Update(p domain.Product) {
+// SaveAll inserts the products in a single transaction and returns their ids
+// in the same order.
+func (r *repository) SaveAll(products []domain.Product) []int {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(InsertQuery)
+	if err != nil {
+		panic(err)
+	}
+	defer stmt.Close()
+
+	ids := make([]int, 0, len(products))
+	for _, p := range products {
+		res, err := stmt.Exec(p.Description, p.ExpirationRate, p.FreezingRate, p.Height, p.Length, p.Netweight, p.ProductCode, p.RecomFreezTemp, p.Width, p.ProductTypeID, p.SellerID)
+		if err != nil {
+			panic(err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			panic(err)
+		}
+		ids = append(ids, int(id))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+func (r *repository) Update(p domain.Product) error {
 	stmt, err := r.db.Prepare(UpdateQuery)
 	if err != nil {
 		panic(err)
 	}
 
-	_, err = stmt.Exec(p.Description, p.ExpirationRate, p.FreezingRate, p.Height, p.Length, p.Netweight, p.ProductCode, p.RecomFreezTemp, p.Width, p.ProductTypeID, p.SellerID, p.ID)
+	res, err := stmt.Exec(p.Description, p.ExpirationRate, p.FreezingRate, p.Height, p.Length, p.Netweight, p.ProductCode, p.RecomFreezTemp, p.Width, p.ProductTypeID, p.SellerID, p.ID, p.Version)
 	if err != nil {
 		panic(err)
 	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	if affected == 0 {
+		return ErrModified
+	}
+	return nil
 }
 
 func (r *repository) Delete(id int) {
@@ -129,8 +254,9 @@ func (r *repository) Delete(id int) {
 	}
 }
 
-func (r *repository) CountRecordsByAllProducts() []domain.RecordsByProductReport {
-	rows, err := r.db.Query(CountRecordsByAllProductsQuery)
+func (r *repository) CountRecordsByAllProducts(dates domain.DateRange) []domain.RecordsByProductReport {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows, err := r.db.Query(CountRecordsByAllProductsQuery, from, from, to, to)
 	if err != nil {
 		panic(err)
 	}
@@ -146,8 +272,9 @@ func (r *repository) CountRecordsByAllProducts() []domain.RecordsByProductReport
 	return recordsByProducts
 }
 
-func (r *repository) CountRecordsByProduct(id int) *domain.RecordsByProductReport {
-	rows := r.db.QueryRow(CountRecordsByProductQuery, id)
+func (r *repository) CountRecordsByProduct(id int, dates domain.DateRange) *domain.RecordsByProductReport {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows := r.db.QueryRow(CountRecordsByProductQuery, from, from, to, to, id)
 	record := domain.RecordsByProductReport{}
 	err := rows.Scan(&record.ProductID, &record.Description, &record.RecordsCount)
 	if err != nil {
@@ -159,3 +286,25 @@ func (r *repository) CountRecordsByProduct(id int) *domain.RecordsByProductRepor
 
 	return &record
 }
+
+// CountRecordsByPeriod counts the records of the product, or of every product
+// when id is nil, by day, week or month of their last update.
+func (r *repository) CountRecordsByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows, err := r.db.Query(CountRecordsByPeriodQuery, period, id, id, from, from, to, to)
+	if err != nil {
+		panic(err)
+	}
+
+	periods := make([]domain.ReportPeriod, 0)
+
+	for rows.Next() {
+		rp := domain.ReportPeriod{}
+		var start string
+		_ = rows.Scan(&start, &rp.Count)
+		rp.Period = helpers.ToDateTime(start)
+		periods = append(periods, rp)
+	}
+
+	return periods
+}
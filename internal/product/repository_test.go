@@ -4,9 +4,12 @@ import (
 	"database/sql"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,15 +44,75 @@ func TestRepositoryGetAll(t *testing.T) {
 	})
 }
 
+func TestRepositoryStreamAll(t *testing.T) {
+	t.Run("Should yield every product as it is read", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		columns := []string{"id", "description", "expiration_rate", "freezing_rate", "height", "lenght", "netweight", "product_code", "recommended_freezing_temperature", "width", "id_product_type", "id_seller"}
+		rows := sqlmock.NewRows(columns)
+		rows.AddRow(1, "", 1, 1, 1, 1, 1, "ABC", 1, 1, 1, 1)
+		rows.AddRow(2, "", 1, 1, 1, 1, 1, "DEF", 1, 1, 1, 1)
+
+		mock.ExpectQuery(regexp.QuoteMeta(product.GetAllQuery)).WillReturnRows(rows).RowsWillBeClosed()
+
+		repository := product.NewRepository(db)
+
+		codes := make([]string, 0)
+		repository.StreamAll(func(p domain.Product) {
+			codes = append(codes, p.ProductCode)
+		})
+
+		assert.Equal(t, []string{"ABC", "DEF"}, codes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositorySaveAll(t *testing.T) {
+	products := []domain.Product{{ProductCode: "ABC"}, {ProductCode: "DEF"}}
+
+	t.Run("Should insert the products in a transaction", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		prepare := mock.ExpectPrepare(regexp.QuoteMeta(product.InsertQuery))
+		prepare.ExpectExec().WithArgs("", float32(0), float32(0), float32(0), float32(0), float32(0), "ABC", float32(0), float32(0), 0, 0).WillReturnResult(sqlmock.NewResult(4, 1))
+		prepare.ExpectExec().WithArgs("", float32(0), float32(0), float32(0), float32(0), float32(0), "DEF", float32(0), float32(0), 0, 0).WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectCommit()
+
+		repository := product.NewRepository(db)
+
+		assert.Equal(t, []int{4, 5}, repository.SaveAll(products))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should roll back and throw panic when an insert fails", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		prepare := mock.ExpectPrepare(regexp.QuoteMeta(product.InsertQuery))
+		prepare.ExpectExec().WillReturnResult(sqlmock.NewResult(4, 1))
+		prepare.ExpectExec().WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		repository := product.NewRepository(db)
+
+		assert.Panics(t, func() { repository.SaveAll(products) })
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestRepositoryGet(t *testing.T) {
 	t.Run("Should return a product by specified id", func(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"id", "description", "expiration_rate", "freezing_rate", "height", "lenght", "netweight", "product_code", "recommended_freezing_temperature", "width", "id_product_type", "id_seller"}
+		columns := []string{"id", "description", "expiration_rate", "freezing_rate", "height", "lenght", "netweight", "product_code", "recommended_freezing_temperature", "width", "id_product_type", "id_seller", "version"}
 		rows := sqlmock.NewRows(columns)
 		productId := 1
-		rows.AddRow(productId, "", 1, 1, 1, 1, 1, "ABC", 1, 1, 1, 1)
+		rows.AddRow(productId, "", 1, 1, 1, 1, 1, "ABC", 1, 1, 1, 1, 2)
 
 		mock.ExpectQuery(regexp.QuoteMeta(product.GetQuery)).WithArgs(productId).WillReturnRows(rows)
 
@@ -58,6 +121,7 @@ func TestRepositoryGet(t *testing.T) {
 		result := repository.Get(productId)
 
 		assert.NotNil(t, result)
+		assert.Equal(t, 2, result.Version)
 	})
 
 	t.Run("Should not return a product", func(t *testing.T) {
@@ -260,12 +324,42 @@ func TestRepositoryUpdate(t *testing.T) {
 				mockedProduct.ProductTypeID,
 				mockedProduct.SellerID,
 				mockedProduct.ID,
+				mockedProduct.Version,
 			).
 			WillReturnResult(sqlmock.NewResult(int64(mockedProduct.ID), 1))
 
 		repository := product.NewRepository(db)
 
-		assert.NotPanics(t, func() { repository.Update(mockedProduct) })
+		assert.NoError(t, repository.Update(mockedProduct))
+	})
+
+	t.Run("Should not update the product when its version changed", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedProduct := mockedProductTemplate
+		mock.ExpectPrepare(regexp.QuoteMeta(product.UpdateQuery))
+		mock.ExpectExec(regexp.QuoteMeta(product.UpdateQuery)).
+			WithArgs(
+				mockedProduct.Description,
+				mockedProduct.ExpirationRate,
+				mockedProduct.FreezingRate,
+				mockedProduct.Height,
+				mockedProduct.Length,
+				mockedProduct.Netweight,
+				mockedProduct.ProductCode,
+				mockedProduct.RecomFreezTemp,
+				mockedProduct.Width,
+				mockedProduct.ProductTypeID,
+				mockedProduct.SellerID,
+				mockedProduct.ID,
+				mockedProduct.Version,
+			).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := product.NewRepository(db)
+
+		assert.ErrorIs(t, repository.Update(mockedProduct), product.ErrModified)
 	})
 
 	t.Run("Should throw panic when expected prepare fails", func(t *testing.T) {
@@ -300,6 +394,7 @@ func TestRepositoryUpdate(t *testing.T) {
 				mockedProduct.ProductTypeID,
 				mockedProduct.SellerID,
 				mockedProduct.ID,
+				mockedProduct.Version,
 			).
 			WillReturnError(sql.ErrConnDone)
 
@@ -366,7 +461,7 @@ func TestRepositoryCountRecordsByAllProducts(t *testing.T) {
 
 		repository := product.NewRepository(db)
 
-		result := repository.CountRecordsByAllProducts()
+		result := repository.CountRecordsByAllProducts(domain.DateRange{})
 
 		assert.Equal(t, len(result), 1)
 	})
@@ -379,7 +474,7 @@ func TestRepositoryCountRecordsByAllProducts(t *testing.T) {
 
 		repository := product.NewRepository(db)
 
-		assert.Panics(t, func() { repository.CountRecordsByAllProducts() })
+		assert.Panics(t, func() { repository.CountRecordsByAllProducts(domain.DateRange{}) })
 	})
 }
 
@@ -394,12 +489,12 @@ func TestRepositoryCountRecordsByProduct(t *testing.T) {
 		rows.AddRow(1, "", 1)
 
 		mock.ExpectQuery(regexp.QuoteMeta(product.CountRecordsByProductQuery)).
-			WithArgs(productId).
+			WithArgs(nil, nil, nil, nil, productId).
 			WillReturnRows(rows)
 
 		repository := product.NewRepository(db)
 
-		result := repository.CountRecordsByProduct(productId)
+		result := repository.CountRecordsByProduct(productId, domain.DateRange{})
 
 		assert.NotNil(t, result)
 	})
@@ -413,7 +508,7 @@ func TestRepositoryCountRecordsByProduct(t *testing.T) {
 
 		repository := product.NewRepository(db)
 
-		result := repository.CountRecordsByProduct(recordId)
+		result := repository.CountRecordsByProduct(recordId, domain.DateRange{})
 
 		assert.Nil(t, result)
 	})
@@ -427,7 +522,122 @@ func TestRepositoryCountRecordsByProduct(t *testing.T) {
 
 		repository := product.NewRepository(db)
 
-		assert.Panics(t, func() { repository.CountRecordsByProduct(recordId) })
+		assert.Panics(t, func() { repository.CountRecordsByProduct(recordId, domain.DateRange{}) })
+	})
+}
+
+func TestRepositoryCountRecordsByPeriod(t *testing.T) {
+	t.Run("Should return the count of each period within the dates", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		id := 1
+		from := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows([]string{"period", "records_count"}).
+			AddRow("2023-07-03 00:00:00", 2).
+			AddRow("2023-07-10 00:00:00", 1)
+
+		mock.ExpectQuery(regexp.QuoteMeta(product.CountRecordsByPeriodQuery)).
+			WithArgs(domain.PeriodWeek, &id, &id, "2023-07-01 00:00:00", "2023-07-01 00:00:00", nil, nil).
+			WillReturnRows(rows)
+
+		repository := product.NewRepository(db)
+
+		result := repository.CountRecordsByPeriod(&id, domain.PeriodWeek, domain.DateRange{From: &from})
+
+		assert.Equal(t, []domain.ReportPeriod{
+			{Period: time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC), Count: 2},
+			{Period: time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC), Count: 1},
+		}, result)
+	})
+
+	t.Run("Should throw panic when query execution fails", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(product.CountRecordsByPeriodQuery)).WillReturnError(sql.ErrConnDone)
+
+		repository := product.NewRepository(db)
+
+		assert.Panics(t, func() { repository.CountRecordsByPeriod(nil, domain.PeriodDay, domain.DateRange{}) })
+	})
+}
+
+func TestRepositoryGetMany(t *testing.T) {
+	t.Run("Should return the products found among the ids with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(product.GetManyQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "description", "expiration_rate", "freezing_rate", "height", "lenght", "netweight", "product_code", "recommended_freezing_temperature", "width", "id_product_type", "id_seller"}).
+			AddRow(2, "Milk", 1, 2, 3, 4, 5, "MILK1", -5, 6, 1, 1).
+			AddRow(5, "Cheese", 1, 2, 3, 4, 5, "CHEESE1", -5, 6, 1, 1)
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := product.NewRepository(db)
+		result := repository.GetMany([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := product.NewRepository(db)
+		result := repository.GetMany(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(product.GetManyQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := product.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetMany([]int{2}) })
+	})
+}
+
+func TestRepositoryExisting(t *testing.T) {
+	t.Run("Should return the product codes already taken with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(product.ExistingQuery, []string{"123", "456"})
+		rows := sqlmock.NewRows([]string{"product_code"}).AddRow("456")
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("123", "456").WillReturnRows(rows)
+
+		repository := product.NewRepository(db)
+		result := repository.Existing([]string{"123", "456"})
+
+		assert.Equal(t, []string{"456"}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no product codes", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := product.NewRepository(db)
+		result := repository.Existing(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(product.ExistingQuery, []string{"123"})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs("123").WillReturnError(sql.ErrConnDone)
+
+		repository := product.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Existing([]string{"123"}) })
 	})
 }
 
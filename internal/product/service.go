@@ -1,27 +1,35 @@
 package product
 
 import (
+	"errors"
+
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_type"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/seller"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	ResourceNotFound      = "produto não encontrado com o id %d"
-	ResourceAlreadyExists = "um produto com o código '%s' já existe"
-	ProductTypeNotFound   = "tipo de produto não encontrado com o id %d"
-	SellerNotFound        = "vendedor não encontrado com o id %d"
+	ResourceNotFound      = "product.not_found"
+	ResourceAlreadyExists = "product.already_exists"
+	ResourceModified      = "product.modified"
+	ProductTypeNotFound   = "product_type.not_found"
+	SellerNotFound        = "seller.not_found"
 )
 
 type Service interface {
 	GetAll() []domain.Product
+	StreamAll(yield func(domain.Product))
 	Get(int) (*domain.Product, error)
+	GetMany(ids []int) []domain.Product
 	Create(domain.Product) (*domain.Product, error)
-	Update(int, domain.UpdateProduct) (*domain.Product, error)
+	Import(products []domain.Product, options domain.ImportOptions) ([]int, []error)
+	Update(domain.Product, domain.Product) (*domain.Product, error)
 	Delete(int) error
-	CountRecordsByAllProducts() []domain.RecordsByProductReport
-	CountRecordsByProduct(id int) (*domain.RecordsByProductReport, error)
+	CountRecordsByAllProducts(dates domain.DateRange, top int) []domain.RecordsByProductReport
+	CountRecordsByProduct(id int, dates domain.DateRange) (*domain.RecordsByProductReport, error)
+	CountRecordsByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error)
 }
 
 type service struct {
@@ -38,6 +46,10 @@ func (s *service) GetAll() []domain.Product {
 	return s.repository.GetAll()
 }
 
+func (s *service) StreamAll(yield func(domain.Product)) {
+	s.repository.StreamAll(yield)
+}
+
 func (s *service) Get(id int) (*domain.Product, error) {
 	product := s.repository.Get(id)
 
@@ -48,59 +60,94 @@ func (s *service) Get(id int) (*domain.Product, error) {
 	return product, nil
 }
 
+// GetMany returns the products found among ids, leaving out the missing ones.
+func (s *service) GetMany(ids []int) []domain.Product {
+	return s.repository.GetMany(ids)
+}
+
 func (s *service) Create(product domain.Product) (*domain.Product, error) {
-	if s.repository.Exists(product.ProductCode) {
-		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, product.ProductCode)
+	if err := s.checkNew(product, s.repository.Exists, s.productTypeExists, s.sellerExists); err != nil {
+		return nil, err
 	}
 
-	productTypeFound := s.productTypeRepository.Get(product.ProductTypeID)
+	id := s.repository.Save(product)
+	return s.repository.Get(id), nil
+}
 
-	if productTypeFound == nil {
-		return nil, apperr.NewDependentResourceNotFound(ProductTypeNotFound, product.ProductTypeID)
+// Import creates the products in bulk with the same checks as Create. A
+// product code repeated in the import is rejected as already existing. The
+// product codes, product types and sellers of the whole import are looked up
+// at once.
+func (s *service) Import(products []domain.Product, options domain.ImportOptions) ([]int, []error) {
+	taken := helpers.Found(products, func(p domain.Product) string { return p.ProductCode }, s.repository.Existing, helpers.Identity[string])
+	productTypeFound := helpers.Found(products, func(p domain.Product) int { return p.ProductTypeID }, s.productTypeRepository.GetMany, func(pt domain.ProductType) int { return pt.ID })
+	sellerFound := helpers.Found(products, func(p domain.Product) int { return p.SellerID }, s.sellerRepository.GetMany, func(sl domain.Seller) int { return sl.ID })
+	codes := make(map[string]bool)
+	check := func(product domain.Product) error {
+		if codes[product.ProductCode] {
+			return apperr.NewResourceAlreadyExists(ResourceAlreadyExists, product.ProductCode)
+		}
+		codes[product.ProductCode] = true
+
+		return s.checkNew(product, taken, productTypeFound, sellerFound)
 	}
 
-	sellerFound := s.sellerRepository.Get(product.SellerID)
+	return helpers.Import(products, options.DryRun, options.Mode != domain.ImportBestEffort, check, s.repository.SaveAll, options.Progress)
+}
 
-	if sellerFound == nil {
-		return nil, apperr.NewDependentResourceNotFound(SellerNotFound, product.SellerID)
+func (s *service) checkNew(product domain.Product, taken func(productCode string) bool, productTypeFound, sellerFound func(id int) bool) error {
+	if taken(product.ProductCode) {
+		return apperr.NewResourceAlreadyExists(ResourceAlreadyExists, product.ProductCode)
 	}
 
-	id := s.repository.Save(product)
-	return s.repository.Get(id), nil
+	if !productTypeFound(product.ProductTypeID) {
+		return apperr.NewDependentResourceNotFound(ProductTypeNotFound, product.ProductTypeID)
+	}
+
+	if !sellerFound(product.SellerID) {
+		return apperr.NewDependentResourceNotFound(SellerNotFound, product.SellerID)
+	}
+
+	return nil
 }
 
-func (s *service) Update(id int, product domain.UpdateProduct) (*domain.Product, error) {
-	productFound := s.repository.Get(id)
+func (s *service) productTypeExists(id int) bool {
+	return s.productTypeRepository.Get(id) != nil
+}
 
-	if productFound == nil {
-		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
-	}
+func (s *service) sellerExists(id int) bool {
+	return s.sellerRepository.Get(id) != nil
+}
 
-	if product.ProductCode != nil {
-		productCode := *product.ProductCode
-		productCodeExists := s.repository.Exists(productCode)
+// Update replaces current, the product as the caller read it, with product. It
+// fails when the product was changed since it was read.
+func (s *service) Update(current domain.Product, product domain.Product) (*domain.Product, error) {
+	productCode := product.ProductCode
+	productCodeExists := s.repository.Exists(productCode)
 
-		if productCodeExists && productCode != productFound.ProductCode {
-			return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, productCode)
-		}
+	if productCodeExists && productCode != current.ProductCode {
+		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, productCode)
 	}
 
-	productFound.Overlap(product)
+	product.ID = current.ID
+	product.Version = current.Version
 
-	productTypeFound := s.productTypeRepository.Get(productFound.ProductTypeID)
+	productTypeFound := s.productTypeRepository.Get(product.ProductTypeID)
 
 	if productTypeFound == nil {
-		return nil, apperr.NewDependentResourceNotFound(ProductTypeNotFound, productFound.ProductTypeID)
+		return nil, apperr.NewDependentResourceNotFound(ProductTypeNotFound, product.ProductTypeID)
 	}
 
-	sellerFound := s.sellerRepository.Get(productFound.SellerID)
+	sellerFound := s.sellerRepository.Get(product.SellerID)
 
 	if sellerFound == nil {
-		return nil, apperr.NewDependentResourceNotFound(SellerNotFound, productFound.SellerID)
+		return nil, apperr.NewDependentResourceNotFound(SellerNotFound, product.SellerID)
 	}
 
-	s.repository.Update(*productFound)
-	return s.repository.Get(id), nil
+	if err := s.repository.Update(product); errors.Is(err, ErrModified) {
+		return nil, apperr.NewIncompatibleResource(ResourceModified, product.ID)
+	}
+	return s.repository.Get(product.ID), nil
 }
 
 func (s *service) Delete(id int) error {
@@ -114,16 +161,28 @@ func (s *service) Delete(id int) error {
 	return nil
 }
 
-func (s *service) CountRecordsByAllProducts() []domain.RecordsByProductReport {
-	return s.repository.CountRecordsByAllProducts()
+// CountRecordsByAllProducts counts the records of every product. When top is
+// positive, only the top products with the most records are returned.
+func (s *service) CountRecordsByAllProducts(dates domain.DateRange, top int) []domain.RecordsByProductReport {
+	report := s.repository.CountRecordsByAllProducts(dates)
+
+	return helpers.Top(report, top, func(r domain.RecordsByProductReport) int { return r.RecordsCount })
 }
 
-func (s *service) CountRecordsByProduct(id int) (*domain.RecordsByProductReport, error) {
+func (s *service) CountRecordsByProduct(id int, dates domain.DateRange) (*domain.RecordsByProductReport, error) {
 	productFound := s.repository.Get(id)
 
 	if productFound == nil {
 		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
 	}
 
-	return s.repository.CountRecordsByProduct(id), nil
+	return s.repository.CountRecordsByProduct(id, dates), nil
+}
+
+func (s *service) CountRecordsByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error) {
+	if id != nil && s.repository.Get(*id) == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, *id)
+	}
+
+	return s.repository.CountRecordsByPeriod(id, period, dates), nil
 }
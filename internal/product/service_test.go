@@ -2,6 +2,7 @@ package product_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product"
@@ -10,6 +11,7 @@ import (
 	seller_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/seller/mocks"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var (
@@ -137,42 +139,75 @@ func TestServiceGet(t *testing.T) {
 	})
 }
 
-func TestServiceUpdate(t *testing.T) {
-	t.Run("Should return a not found error", func(t *testing.T) {
-		service, repository, _, _ := CreateService(t)
+func TestServiceImport(t *testing.T) {
+	second := mockedProductTemplate
+	second.ProductCode = "456"
+	repeated := mockedProductTemplate
+	repeated.Description = "Repeated"
 
-		id := 2
-		productCode := "123"
-		updateProduct := domain.UpdateProduct{
-			ID:          &id,
-			ProductCode: &productCode,
-		}
+	t.Run("Should save the valid products and reject a product code repeated in the import", func(t *testing.T) {
+		service, repository, productTypeRepository, sellerRepository := CreateService(t)
 
-		var respositoryResult *domain.Product
+		repository.On("Existing", []string{"123", "456"}).Return([]string{}).Once()
+		productTypeRepository.On("GetMany", []int{1}).Return([]domain.ProductType{{ID: 1}}).Once()
+		sellerRepository.On("GetMany", []int{1}).Return([]domain.Seller{{ID: 1}}).Once()
+		repository.On("SaveAll", []domain.Product{mockedProductTemplate, second}).Return([]int{7, 8})
 
-		repository.On("Get", id).Return(respositoryResult)
-		result, err := service.Update(id, updateProduct)
+		ids, errs := service.Import([]domain.Product{mockedProductTemplate, repeated, second}, domain.ImportOptions{Mode: domain.ImportBestEffort})
 
-		assert.Error(t, err)
-		assert.Nil(t, result)
-		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+		assert.Equal(t, []int{7, 0, 8}, ids)
+		assert.NoError(t, errs[0])
+		assert.True(t, apperr.Is[*apperr.ResourceAlreadyExists](errs[1]))
+		assert.NoError(t, errs[2])
+		productTypeRepository.AssertNotCalled(t, "Get", mock.Anything)
+		sellerRepository.AssertNotCalled(t, "Get", mock.Anything)
+		repository.AssertNotCalled(t, "Exists", mock.Anything)
+	})
+
+	t.Run("Should reject the product codes that already exist", func(t *testing.T) {
+		service, repository, productTypeRepository, sellerRepository := CreateService(t)
+
+		repository.On("Existing", []string{"123", "456"}).Return([]string{"456"})
+		productTypeRepository.On("GetMany", []int{1}).Return([]domain.ProductType{{ID: 1}})
+		sellerRepository.On("GetMany", []int{1}).Return([]domain.Seller{{ID: 1}})
+		repository.On("SaveAll", []domain.Product{mockedProductTemplate}).Return([]int{7})
+
+		ids, errs := service.Import([]domain.Product{mockedProductTemplate, second}, domain.ImportOptions{Mode: domain.ImportBestEffort})
+
+		assert.Equal(t, []int{7, 0}, ids)
+		assert.NoError(t, errs[0])
+		assert.True(t, apperr.Is[*apperr.ResourceAlreadyExists](errs[1]))
+		repository.AssertNotCalled(t, "Exists", mock.Anything)
+	})
+
+	t.Run("Should save nothing when a product of an all or nothing import is rejected", func(t *testing.T) {
+		service, repository, productTypeRepository, sellerRepository := CreateService(t)
+		unknownType := second
+		unknownType.ProductTypeID = 2
+
+		repository.On("Existing", []string{"123", "456"}).Return([]string{})
+		productTypeRepository.On("GetMany", []int{1, 2}).Return([]domain.ProductType{{ID: 1}})
+		sellerRepository.On("GetMany", []int{1}).Return([]domain.Seller{{ID: 1}})
+
+		ids, errs := service.Import([]domain.Product{mockedProductTemplate, unknownType}, domain.ImportOptions{Mode: domain.ImportAllOrNothing})
+
+		assert.Equal(t, []int{0, 0}, ids)
+		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](errs[1]))
+		repository.AssertNotCalled(t, "SaveAll", mock.Anything)
 	})
+}
 
+func TestServiceUpdate(t *testing.T) {
 	t.Run("Should return a conflict error", func(t *testing.T) {
 		service, repository, _, _ := CreateService(t)
 
 		mockedProduct := mockedProductTemplate
 
-		id := 1
-		productCode := "456"
-		updateProduct := domain.UpdateProduct{
-			ID:          &id,
-			ProductCode: &productCode,
-		}
+		updateProduct := mockedProduct
+		updateProduct.ProductCode = "456"
 
-		repository.On("Get", id).Return(&mockedProduct)
-		repository.On("Exists", productCode).Return(true)
-		result, err := service.Update(id, updateProduct)
+		repository.On("Exists", updateProduct.ProductCode).Return(true)
+		result, err := service.Update(mockedProduct, updateProduct)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -183,19 +218,14 @@ func TestServiceUpdate(t *testing.T) {
 		service, repository, productTypeRepository, _ := CreateService(t)
 
 		mockedProduct := mockedProductTemplate
-		id := 1
-		productCode := "456"
-		updateProduct := domain.UpdateProduct{
-			ID:          &id,
-			ProductCode: &productCode,
-		}
+		updateProduct := mockedProduct
+		updateProduct.ProductCode = "456"
 
 		var productTypeRepositoryGetResult *domain.ProductType
 
-		repository.On("Get", id).Return(&mockedProduct)
-		repository.On("Exists", productCode).Return(false)
+		repository.On("Exists", updateProduct.ProductCode).Return(false)
 		productTypeRepository.On("Get", mockedProduct.ProductTypeID).Return(productTypeRepositoryGetResult)
-		result, err := service.Update(id, updateProduct)
+		result, err := service.Update(mockedProduct, updateProduct)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -206,52 +236,56 @@ func TestServiceUpdate(t *testing.T) {
 		service, repository, productTypeRepository, sellerRepository := CreateService(t)
 
 		mockedProduct := mockedProductTemplate
-		id := 1
-		productCode := "456"
-		updateProduct := domain.UpdateProduct{
-			ID:          &id,
-			ProductCode: &productCode,
-		}
+		updateProduct := mockedProduct
+		updateProduct.ProductCode = "456"
 
 		var sellerRepositoryGetResult *domain.Seller
 
-		repository.On("Get", id).Return(&mockedProduct)
-		repository.On("Exists", productCode).Return(false)
+		repository.On("Exists", updateProduct.ProductCode).Return(false)
 		productTypeRepository.On("Get", mockedProduct.ProductTypeID).Return(&domain.ProductType{})
 		sellerRepository.On("Get", mockedProduct.SellerID).Return(sellerRepositoryGetResult)
-		result, err := service.Update(id, updateProduct)
+		result, err := service.Update(mockedProduct, updateProduct)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
 	})
 
+	t.Run("Should return a conflict error when the product was modified since it was read", func(t *testing.T) {
+		service, repository, productTypeRepository, sellerRepository := CreateService(t)
+
+		mockedProduct := mockedProductTemplate
+		mockedProduct.Version = 3
+		updatedProduct := mockedProduct
+		updatedProduct.Description = "Description 2"
+
+		repository.On("Exists", updatedProduct.ProductCode).Return(true)
+		productTypeRepository.On("Get", updatedProduct.ProductTypeID).Return(&domain.ProductType{})
+		sellerRepository.On("Get", updatedProduct.SellerID).Return(&domain.Seller{})
+		repository.On("Update", updatedProduct).Return(product.ErrModified)
+		result, err := service.Update(mockedProduct, updatedProduct)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+
 	t.Run("Should return an updated product", func(t *testing.T) {
 		service, repository, productTypeRepository, sellerRepository := CreateService(t)
 
 		mockedProduct := mockedProductTemplate
-		id := 1
-		productCode := "123"
-		description := "Description 2"
-		updateProduct := domain.UpdateProduct{
-			ID:          &id,
-			Description: &description,
-			ProductCode: &productCode,
-		}
 		updatedProduct := mockedProduct
-		updatedProduct.Overlap(updateProduct)
+		updatedProduct.Description = "Description 2"
 
-		repository.On("Get", id).Return(&mockedProduct)
-		repository.On("Exists", productCode).Return(true)
-		productTypeRepository.On("Get", mockedProduct.ProductTypeID).Return(&domain.ProductType{})
-		sellerRepository.On("Get", mockedProduct.SellerID).Return(&domain.Seller{})
-		repository.On("Update", updatedProduct)
-		repository.On("Get", id).Return(&updatedProduct)
-		result, err := service.Update(id, updateProduct)
+		repository.On("Exists", updatedProduct.ProductCode).Return(true)
+		productTypeRepository.On("Get", updatedProduct.ProductTypeID).Return(&domain.ProductType{})
+		sellerRepository.On("Get", updatedProduct.SellerID).Return(&domain.Seller{})
+		repository.On("Update", updatedProduct).Return(nil)
+		repository.On("Get", mockedProduct.ID).Return(&updatedProduct)
+		result, err := service.Update(mockedProduct, updatedProduct)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, description, result.Description)
+		assert.Equal(t, updatedProduct.Description, result.Description)
 	})
 }
 
@@ -294,13 +328,28 @@ func TestServiceCountRecordsByAllProducts(t *testing.T) {
 		}
 		mockedRecordsByProductsReport := []domain.RecordsByProductReport{mockedRecordsByProductReport}
 
-		repository.On("CountRecordsByAllProducts").Return(mockedRecordsByProductsReport)
+		repository.On("CountRecordsByAllProducts", domain.DateRange{}).Return(mockedRecordsByProductsReport)
 
-		result := service.CountRecordsByAllProducts()
+		result := service.CountRecordsByAllProducts(domain.DateRange{}, 0)
 
 		assert.Equal(t, 1, len(result))
 		assert.Equal(t, result[0], mockedRecordsByProductReport)
 	})
+
+	t.Run("Should return only the products with the most records", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		report := []domain.RecordsByProductReport{
+			{ProductID: 1, RecordsCount: 1},
+			{ProductID: 2, RecordsCount: 5},
+			{ProductID: 3, RecordsCount: 3},
+		}
+		repository.On("CountRecordsByAllProducts", domain.DateRange{}).Return(report)
+
+		result := service.CountRecordsByAllProducts(domain.DateRange{}, 2)
+
+		assert.Equal(t, []domain.RecordsByProductReport{{ProductID: 2, RecordsCount: 5}, {ProductID: 3, RecordsCount: 3}}, result)
+	})
 }
 
 func TestServiceCountRecordsByProduct(t *testing.T) {
@@ -316,9 +365,9 @@ func TestServiceCountRecordsByProduct(t *testing.T) {
 		}
 
 		repository.On("Get", recordId).Return(&mockedProduct)
-		repository.On("CountRecordsByProduct", recordId).Return(&mockedRecordsByProductReport)
+		repository.On("CountRecordsByProduct", recordId, domain.DateRange{}).Return(&mockedRecordsByProductReport)
 
-		result, err := service.CountRecordsByProduct(recordId)
+		result, err := service.CountRecordsByProduct(recordId, domain.DateRange{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -333,7 +382,7 @@ func TestServiceCountRecordsByProduct(t *testing.T) {
 		var productRepositoryGetResult *domain.Product
 		repository.On("Get", recordId).Return(productRepositoryGetResult)
 
-		result, err := service.CountRecordsByProduct(recordId)
+		result, err := service.CountRecordsByProduct(recordId, domain.DateRange{})
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -341,6 +390,33 @@ func TestServiceCountRecordsByProduct(t *testing.T) {
 	})
 }
 
+func TestServiceCountRecordsByPeriod(t *testing.T) {
+	t.Run("Should return the count of each period", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		periods := []domain.ReportPeriod{{Period: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), Count: 3}}
+		repository.On("CountRecordsByPeriod", (*int)(nil), domain.PeriodMonth, domain.DateRange{}).Return(periods)
+
+		result, err := service.CountRecordsByPeriod(nil, domain.PeriodMonth, domain.DateRange{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, periods, result)
+	})
+
+	t.Run("Should return not found error when the id does not exist", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		id := 1
+		var found *domain.Product
+		repository.On("Get", id).Return(found)
+
+		result, err := service.CountRecordsByPeriod(&id, domain.PeriodMonth, domain.DateRange{})
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
 func CreateService(t *testing.T) (product.Service, *mocks.Repository, *product_type_mocks.Repository, *seller_mocks.Repository) {
 	t.Helper()
 	repository := new(mocks.Repository)
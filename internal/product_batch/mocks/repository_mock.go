@@ -13,11 +13,41 @@ func (r *Repository) Exists(batchNumber int) bool {
 	args := r.Called(batchNumber)
 	return args.Bool(0)
 }
-func (r *Repository) Save(pb domain.ProductBatch) int {
+func (r *Repository) Save(pb domain.ProductBatch) (int, error) {
 	args := r.Called(pb)
-	return args.Int(0)
+	return args.Int(0), args.Error(1)
 }
 func (r *Repository) Get(id int) *domain.ProductBatch {
 	args := r.Called(id)
 	return args.Get(0).(*domain.ProductBatch)
 }
+
+func (r *Repository) GetMany(ids []int) []domain.ProductBatch {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.ProductBatch)
+}
+func (r *Repository) ProductQuantityBySection(productID int) map[int]int {
+	args := r.Called(productID)
+	return args.Get(0).(map[int]int)
+}
+func (r *Repository) Transfer(t domain.StockTransfer) (*domain.StockMovement, error) {
+	args := r.Called(t)
+	return args.Get(0).(*domain.StockMovement), args.Error(1)
+}
+func (r *Repository) Movements(batchID int) []domain.StockMovement {
+	args := r.Called(batchID)
+	return args.Get(0).([]domain.StockMovement)
+}
+func (r *Repository) ChangeStatus(c domain.BatchStatusChange) (*domain.BatchStatusChange, error) {
+	args := r.Called(c)
+	return args.Get(0).(*domain.BatchStatusChange), args.Error(1)
+}
+func (r *Repository) StatusChanges(batchID int) []domain.BatchStatusChange {
+	args := r.Called(batchID)
+	return args.Get(0).([]domain.BatchStatusChange)
+}
+
+func (r *Repository) GetBySections(sectionIDs []int) []domain.ProductBatch {
+	args := r.Called(sectionIDs)
+	return args.Get(0).([]domain.ProductBatch)
+}
@@ -13,3 +13,33 @@ func (s *Service) Create(pb domain.ProductBatch) (*domain.ProductBatch, error) {
 	args := s.Called(pb)
 	return args.Get(0).(*domain.ProductBatch), args.Error(1)
 }
+
+func (s *Service) SuggestPutaway(productID int, quantity int, warehouseID int) ([]domain.PutawaySuggestion, error) {
+	args := s.Called(productID, quantity, warehouseID)
+	return args.Get(0).([]domain.PutawaySuggestion), args.Error(1)
+}
+
+func (s *Service) Transfer(t domain.StockTransfer) (*domain.StockMovement, error) {
+	args := s.Called(t)
+	return args.Get(0).(*domain.StockMovement), args.Error(1)
+}
+
+func (s *Service) Movements(id int) ([]domain.StockMovement, error) {
+	args := s.Called(id)
+	return args.Get(0).([]domain.StockMovement), args.Error(1)
+}
+
+func (s *Service) ChangeStatus(c domain.BatchStatusChange) (*domain.BatchStatusChange, error) {
+	args := s.Called(c)
+	return args.Get(0).(*domain.BatchStatusChange), args.Error(1)
+}
+
+func (s *Service) StatusChanges(id int) ([]domain.BatchStatusChange, error) {
+	args := s.Called(id)
+	return args.Get(0).([]domain.BatchStatusChange), args.Error(1)
+}
+
+func (s *Service) GetBySections(sectionIDs []int) []domain.ProductBatch {
+	args := s.Called(sectionIDs)
+	return args.Get(0).([]domain.ProductBatch)
+}
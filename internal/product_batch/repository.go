@@ -3,21 +3,79 @@ package product_batch
 import (
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+	"github.com/go-sql-driver/mysql"
 )
 
 var (
-	InsertQuery = "INSERT INTO product_batches (batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
-	ExistsQuery = "SELECT id FROM product_batches WHERE batch_number = ?"
-	GetQuery    = "SELECT id, batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id FROM product_batches WHERE id = ?"
+	InsertQuery        = "INSERT INTO product_batches (batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	ExistsQuery        = "SELECT id FROM product_batches WHERE batch_number = ?"
+	GetQuery           = "SELECT id, batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id, status FROM product_batches WHERE id = ?"
+	GetManyQuery       = "SELECT id, batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id, status FROM product_batches WHERE id IN (%s)"
+	GetBySectionsQuery = "SELECT id, batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id, status FROM product_batches WHERE section_id IN (%s)"
+
+	ProductQuantityBySectionQuery = "SELECT section_id, COALESCE(SUM(current_quantity), 0) FROM product_batches WHERE product_id = ? GROUP BY section_id"
+
+	LockSectionQuery         = "SELECT maximum_capacity FROM sections WHERE id = ? FOR UPDATE"
+	SectionUsageQuery        = "SELECT COALESCE(SUM(current_quantity), 0) FROM product_batches WHERE section_id = ?"
+	SyncSectionCapacityQuery = "UPDATE sections SET current_capacity = (SELECT COALESCE(SUM(current_quantity), 0) FROM product_batches WHERE section_id = ?) WHERE id = ?"
+	SectionCapacityQuery     = "SELECT warehouse_id, current_capacity, maximum_capacity FROM sections WHERE id = ?"
+
+	LockBatchQuery           = "SELECT current_quantity, section_id FROM product_batches WHERE id = ? FOR UPDATE"
+	LockReservedQuery        = "SELECT COALESCE(SUM(quantity), 0) FROM stock_reservations WHERE product_batch_id = ? AND picked_at IS NULL FOR UPDATE"
+	MoveBatchQuery           = "UPDATE product_batches SET section_id = ? WHERE id = ?"
+	DecreaseQuantityQuery    = "UPDATE product_batches SET current_quantity = current_quantity - ? WHERE id = ?"
+	NextBatchNumberQuery     = "SELECT COALESCE(MAX(batch_number), 0) + 1 FROM product_batches FOR UPDATE"
+	SplitBatchQuery          = "INSERT INTO product_batches (batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id, status, origin_batch_id) SELECT ?, ?, current_temperature, due_date, ?, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, ?, status, COALESCE(origin_batch_id, id) FROM product_batches WHERE id = ?"
+	InsertMovementQuery      = "INSERT INTO stock_movements (product_batch_id, movement_type, from_section_id, to_section_id, quantity, employee_id, target_batch_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	GetMovementsByBatchQuery = "SELECT id, product_batch_id, movement_type, from_section_id, to_section_id, quantity, employee_id, target_batch_id, created_at FROM stock_movements WHERE product_batch_id = ? ORDER BY created_at, id"
+
+	LockBatchStatusQuery    = "SELECT status, current_quantity, section_id FROM product_batches WHERE id = ? FOR UPDATE"
+	UpdateStatusQuery       = "UPDATE product_batches SET status = ? WHERE id = ?"
+	WriteOffQuery           = "UPDATE product_batches SET current_quantity = 0 WHERE id = ?"
+	DeleteReservationsQuery = "DELETE FROM stock_reservations WHERE product_batch_id = ? AND picked_at IS NULL"
+	InsertStatusChangeQuery = "INSERT INTO batch_status_changes (product_batch_id, from_status, to_status, reason, employee_id, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+	GetStatusChangesQuery   = "SELECT id, product_batch_id, from_status, to_status, reason, employee_id, created_at FROM batch_status_changes WHERE product_batch_id = ? ORDER BY created_at, id"
 )
 
+var (
+	// ErrSectionFull is returned when storing a batch would exceed the maximum
+	// capacity of its section.
+	ErrSectionFull = errors.New("section capacity exceeded")
+	// ErrInsufficientQuantity is returned when a batch does not hold the
+	// quantity asked to be moved out of it.
+	ErrInsufficientQuantity = errors.New("insufficient batch quantity")
+	// ErrReservedQuantity is returned when moving the quantity out of a batch
+	// would take units reserved for purchase orders.
+	ErrReservedQuantity = errors.New("batch quantity reserved")
+	// ErrInvalidTransition is returned when a batch can not move from its
+	// current status to the one asked.
+	ErrInvalidTransition = errors.New("invalid batch status transition")
+)
+
+// transitions lists the statuses a batch can move to from each status.
+// Disposed batches are written off and can not change anymore.
+var transitions = map[string][]string{
+	domain.BatchAvailable:   {domain.BatchQuarantined, domain.BatchDisposed},
+	domain.BatchQuarantined: {domain.BatchReleased, domain.BatchDisposed},
+	domain.BatchReleased:    {domain.BatchQuarantined, domain.BatchDisposed},
+}
+
 type Repository interface {
 	Exists(batchNumber int) bool
-	Save(pb domain.ProductBatch) int
+	Save(pb domain.ProductBatch) (int, error)
 	Get(id int) *domain.ProductBatch
+	GetMany(ids []int) []domain.ProductBatch
+	GetBySections(sectionIDs []int) []domain.ProductBatch
+	ProductQuantityBySection(productID int) map[int]int
+	Transfer(t domain.StockTransfer) (*domain.StockMovement, error)
+	Movements(batchID int) []domain.StockMovement
+	ChangeStatus(c domain.BatchStatusChange) (*domain.BatchStatusChange, error)
+	StatusChanges(batchID int) []domain.BatchStatusChange
 }
 
 type repository struct {
@@ -36,8 +94,22 @@ func (r *repository) Exists(batchNumber int) bool {
 	return err == nil
 }
 
-func (r *repository) Save(pb domain.ProductBatch) int {
-	stmt, err := r.db.Prepare(InsertQuery)
+// Save stores the batch, records its receipt in the stock ledger and its
+// ProductBatchCreated event, and refreshes the current capacity of its section
+// in the same transaction. The section row is locked while its usage is
+// checked.
+func (r *repository) Save(pb domain.ProductBatch) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	if err := ReserveCapacity(tx, pb.SectionID, pb.CurrentQuantity); err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.Prepare(InsertQuery)
 	if err != nil {
 		panic(err)
 	}
@@ -51,8 +123,24 @@ func (r *repository) Save(pb domain.ProductBatch) int {
 	if err != nil {
 		panic(err)
 	}
-	return int(id)
+
+	recordMovement(tx, &domain.StockMovement{
+		ProductBatchID: int(id),
+		Type:           domain.MovementReceipt,
+		ToSectionID:    &pb.SectionID,
+		Quantity:       pb.CurrentQuantity,
+	})
+	SyncSectionCapacity(tx, pb.SectionID)
+
+	pb.ID = int(id)
+	outbox.Record(tx, domain.EventProductBatchCreated, pb.ID, pb)
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return pb.ID, nil
 }
+
 func (r *repository) Get(id int) *domain.ProductBatch {
 	row := r.db.QueryRow(GetQuery, id)
 	var pb domain.ProductBatch
@@ -60,7 +148,7 @@ func (r *repository) Get(id int) *domain.ProductBatch {
 	var dueDate string
 	var manufacturingDate string
 
-	err := row.Scan(&pb.ID, &pb.BatchNumber, &pb.CurrentQuantity, &pb.CurrentTemperature, &dueDate, &pb.InitialQuantity, &manufacturingDate, &pb.ManufacturingHour, &pb.MinimumTemperature, &pb.ProductID, &pb.SectionID)
+	err := row.Scan(&pb.ID, &pb.BatchNumber, &pb.CurrentQuantity, &pb.CurrentTemperature, &dueDate, &pb.InitialQuantity, &manufacturingDate, &pb.ManufacturingHour, &pb.MinimumTemperature, &pb.ProductID, &pb.SectionID, &pb.Status)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil
@@ -73,3 +161,345 @@ func (r *repository) Get(id int) *domain.ProductBatch {
 
 	return &pb
 }
+
+// GetMany returns the batches found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.ProductBatch {
+	batches := make([]domain.ProductBatch, 0, len(ids))
+	if len(ids) == 0 {
+		return batches
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		pb := domain.ProductBatch{}
+		var dueDate, manufacturingDate string
+		if err := rows.Scan(&pb.ID, &pb.BatchNumber, &pb.CurrentQuantity, &pb.CurrentTemperature, &dueDate, &pb.InitialQuantity, &manufacturingDate, &pb.ManufacturingHour, &pb.MinimumTemperature, &pb.ProductID, &pb.SectionID, &pb.Status); err != nil {
+			panic(err)
+		}
+		pb.DueDate = helpers.ToDateTime(dueDate)
+		pb.ManufacturingDate = helpers.ToDateTime(manufacturingDate)
+		batches = append(batches, pb)
+	}
+	return batches
+}
+
+// GetBySections returns the batches stored in every section of sectionIDs.
+func (r *repository) GetBySections(sectionIDs []int) []domain.ProductBatch {
+	batches := make([]domain.ProductBatch, 0, len(sectionIDs))
+	if len(sectionIDs) == 0 {
+		return batches
+	}
+
+	query, args := helpers.ExpandIn(GetBySectionsQuery, sectionIDs)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		pb := domain.ProductBatch{}
+		var dueDate, manufacturingDate string
+		if err := rows.Scan(&pb.ID, &pb.BatchNumber, &pb.CurrentQuantity, &pb.CurrentTemperature, &dueDate, &pb.InitialQuantity, &manufacturingDate, &pb.ManufacturingHour, &pb.MinimumTemperature, &pb.ProductID, &pb.SectionID, &pb.Status); err != nil {
+			panic(err)
+		}
+		pb.DueDate = helpers.ToDateTime(dueDate)
+		pb.ManufacturingDate = helpers.ToDateTime(manufacturingDate)
+		batches = append(batches, pb)
+	}
+	return batches
+}
+
+// ProductQuantityBySection returns the quantity of the product currently
+// stored in each section, keyed by section id.
+func (r *repository) ProductQuantityBySection(productID int) map[int]int {
+	rows, err := r.db.Query(ProductQuantityBySectionQuery, productID)
+	if err != nil {
+		panic(err)
+	}
+	quantities := make(map[int]int)
+
+	for rows.Next() {
+		var sectionID, quantity int
+		_ = rows.Scan(&sectionID, &quantity)
+		quantities[sectionID] = quantity
+	}
+	return quantities
+}
+
+// Transfer moves quantity of a batch to another section. Moving the whole
+// batch only changes its section, while a partial move splits it into a new
+// batch in the destination section. The transfer is recorded in the stock
+// ledger and the capacity of both sections is refreshed.
+func (r *repository) Transfer(t domain.StockTransfer) (*domain.StockMovement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	var currentQuantity, fromSectionID int
+	if err := tx.QueryRow(LockBatchQuery, t.ProductBatchID).Scan(&currentQuantity, &fromSectionID); err != nil {
+		panic(err)
+	}
+	if t.Quantity > currentQuantity {
+		return nil, ErrInsufficientQuantity
+	}
+	if t.Quantity > currentQuantity-LockReserved(tx, t.ProductBatchID) {
+		return nil, ErrReservedQuantity
+	}
+	if err := ReserveCapacity(tx, t.ToSectionID, t.Quantity); err != nil {
+		return nil, err
+	}
+
+	targetBatchID := t.ProductBatchID
+	if t.Quantity == currentQuantity {
+		if _, err := tx.Exec(MoveBatchQuery, t.ToSectionID, t.ProductBatchID); err != nil {
+			panic(err)
+		}
+	} else {
+		targetBatchID = splitBatch(tx, t)
+	}
+
+	movement := &domain.StockMovement{
+		ProductBatchID: t.ProductBatchID,
+		Type:           domain.MovementTransfer,
+		FromSectionID:  &fromSectionID,
+		ToSectionID:    &t.ToSectionID,
+		Quantity:       t.Quantity,
+		EmployeeID:     &t.EmployeeID,
+		TargetBatchID:  &targetBatchID,
+	}
+	recordMovement(tx, movement)
+	SyncSectionCapacity(tx, fromSectionID)
+	SyncSectionCapacity(tx, t.ToSectionID)
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return movement, nil
+}
+
+func (r *repository) Movements(batchID int) []domain.StockMovement {
+	rows, err := r.db.Query(GetMovementsByBatchQuery, batchID)
+	if err != nil {
+		panic(err)
+	}
+	movements := make([]domain.StockMovement, 0)
+
+	for rows.Next() {
+		m := domain.StockMovement{}
+		var createdAt string
+		_ = rows.Scan(&m.ID, &m.ProductBatchID, &m.Type, &m.FromSectionID, &m.ToSectionID, &m.Quantity, &m.EmployeeID, &m.TargetBatchID, &createdAt)
+		m.CreatedAt = helpers.ToDateTime(createdAt)
+		movements = append(movements, m)
+	}
+	return movements
+}
+
+// ChangeStatus moves the batch to c.ToStatus and records the change.
+func (r *repository) ChangeStatus(c domain.BatchStatusChange) (*domain.BatchStatusChange, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	change, err := ApplyStatusChange(tx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return change, nil
+}
+
+// ApplyStatusChange locks the batch, moves it to c.ToStatus and records the
+// change in the given transaction. Disposing a batch writes off its current
+// quantity through the stock ledger, drops its reservations not picked yet and
+// refreshes the capacity of its section.
+func ApplyStatusChange(tx *sql.Tx, c domain.BatchStatusChange) (*domain.BatchStatusChange, error) {
+	var currentQuantity, sectionID int
+	if err := tx.QueryRow(LockBatchStatusQuery, c.ProductBatchID).Scan(&c.FromStatus, &currentQuantity, &sectionID); err != nil {
+		panic(err)
+	}
+	if !CanChangeStatus(c.FromStatus, c.ToStatus) {
+		return nil, ErrInvalidTransition
+	}
+
+	if _, err := tx.Exec(UpdateStatusQuery, c.ToStatus, c.ProductBatchID); err != nil {
+		panic(err)
+	}
+
+	if c.ToStatus == domain.BatchDisposed {
+		recordMovement(tx, &domain.StockMovement{
+			ProductBatchID: c.ProductBatchID,
+			Type:           domain.MovementDisposal,
+			FromSectionID:  &sectionID,
+			Quantity:       currentQuantity,
+			EmployeeID:     &c.EmployeeID,
+		})
+		if _, err := tx.Exec(WriteOffQuery, c.ProductBatchID); err != nil {
+			panic(err)
+		}
+		if _, err := tx.Exec(DeleteReservationsQuery, c.ProductBatchID); err != nil {
+			panic(err)
+		}
+		SyncSectionCapacity(tx, sectionID)
+	}
+
+	c.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	res, err := tx.Exec(InsertStatusChangeQuery, c.ProductBatchID, c.FromStatus, c.ToStatus, c.Reason, c.EmployeeID, helpers.ToFormattedDateTime(c.CreatedAt))
+	if err != nil {
+		panic(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		panic(err)
+	}
+	c.ID = int(id)
+	return &c, nil
+}
+
+func (r *repository) StatusChanges(batchID int) []domain.BatchStatusChange {
+	rows, err := r.db.Query(GetStatusChangesQuery, batchID)
+	if err != nil {
+		panic(err)
+	}
+	changes := make([]domain.BatchStatusChange, 0)
+
+	for rows.Next() {
+		c := domain.BatchStatusChange{}
+		var createdAt string
+		_ = rows.Scan(&c.ID, &c.ProductBatchID, &c.FromStatus, &c.ToStatus, &c.Reason, &c.EmployeeID, &createdAt)
+		c.CreatedAt = helpers.ToDateTime(createdAt)
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// CanChangeStatus tells whether a batch in status from can move to status to.
+func CanChangeStatus(from string, to string) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// reserveCapacity locks the section row and tells whether it can hold the
+// quantity on top of the batches already stored in it.
+func ReserveCapacity(tx *sql.Tx, sectionID int, quantity int) error {
+	var maximumCapacity, used int
+	if err := tx.QueryRow(LockSectionQuery, sectionID).Scan(&maximumCapacity); err != nil {
+		panic(err)
+	}
+	if err := tx.QueryRow(SectionUsageQuery, sectionID).Scan(&used); err != nil {
+		panic(err)
+	}
+	if used+quantity > maximumCapacity {
+		return ErrSectionFull
+	}
+	return nil
+}
+
+// LockReserved locks the reservations of the batch and returns the quantity
+// they hold, so stock moved out of the batch in the same transaction can not
+// take reserved units.
+func LockReserved(tx *sql.Tx, batchID int) int {
+	var reserved int
+	if err := tx.QueryRow(LockReservedQuery, batchID).Scan(&reserved); err != nil {
+		panic(err)
+	}
+	return reserved
+}
+
+// splitAttempts is how many batch numbers a split tries when another
+// transaction takes the one it read.
+const splitAttempts = 3
+
+// duplicateKeyError is the MySQL error number for a unique key violation.
+const duplicateKeyError = 1062
+
+// splitBatch takes the quantity out of the batch and stores it as a new batch
+// in the destination section, returning the id of the new batch. The next
+// batch number is read with a locking read, which makes concurrent splits wait
+// for each other; a number taken in between anyway, by a batch created with
+// it, fails on the unique key and the split moves on to the next one.
+func splitBatch(tx *sql.Tx, t domain.StockTransfer) int {
+	if _, err := tx.Exec(DecreaseQuantityQuery, t.Quantity, t.ProductBatchID); err != nil {
+		panic(err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		var batchNumber int
+		if err := tx.QueryRow(NextBatchNumberQuery).Scan(&batchNumber); err != nil {
+			panic(err)
+		}
+
+		res, err := tx.Exec(SplitBatchQuery, batchNumber, t.Quantity, t.Quantity, t.ToSectionID, t.ProductBatchID)
+		if err != nil {
+			if isDuplicateKey(err) && attempt < splitAttempts {
+				continue
+			}
+			panic(err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			panic(err)
+		}
+		return int(id)
+	}
+}
+
+// isDuplicateKey tells whether the statement failed on a unique key.
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == duplicateKeyError
+}
+
+func recordMovement(tx *sql.Tx, m *domain.StockMovement) {
+	m.CreatedAt = time.Now().UTC().Truncate(time.Second)
+
+	res, err := tx.Exec(InsertMovementQuery, m.ProductBatchID, m.Type, m.FromSectionID, m.ToSectionID, m.Quantity, m.EmployeeID, m.TargetBatchID, helpers.ToFormattedDateTime(m.CreatedAt))
+	if err != nil {
+		panic(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		panic(err)
+	}
+	m.ID = int(id)
+}
+
+// SyncSectionCapacity sets the current capacity of the section to the
+// quantity its batches hold, recording the change when there is one.
+func SyncSectionCapacity(tx *sql.Tx, sectionID int) {
+	res, err := tx.Exec(SyncSectionCapacityQuery, sectionID, sectionID)
+	if err != nil {
+		panic(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	if affected == 0 {
+		return
+	}
+
+	change := domain.SectionCapacityChange{SectionID: sectionID}
+	err = tx.QueryRow(SectionCapacityQuery, sectionID).Scan(&change.WarehouseID, &change.CurrentCapacity, &change.MaximumCapacity)
+	if err != nil {
+		panic(err)
+	}
+	outbox.Record(tx, domain.EventSectionCapacityChanged, sectionID, change)
+}
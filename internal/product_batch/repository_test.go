@@ -8,7 +8,10 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -65,11 +68,11 @@ func TestRepositoryGet(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"id", "batch_number", "current_quantity", "current_temperature", "due_date", "initial_quantity", "manufacturing_date", "manufacturing_hour", "minimum_temperature", "product_id", "section_id"}
+		columns := []string{"id", "batch_number", "current_quantity", "current_temperature", "due_date", "initial_quantity", "manufacturing_date", "manufacturing_hour", "minimum_temperature", "product_id", "section_id", "status"}
 		rows := sqlmock.NewRows(columns)
 		batchNumber := 1
 
-		rows.AddRow(batchNumber, 1, 1, 2, "2021-01-01 10:00:00", 10, "2021-01-01 10:00:00", 10, 0, 1, 1)
+		rows.AddRow(batchNumber, 1, 1, 2, "2021-01-01 10:00:00", 10, "2021-01-01 10:00:00", 10, 0, 1, 1, domain.BatchAvailable)
 
 		mock.ExpectQuery(regexp.QuoteMeta(product_batch.GetQuery)).
 			WithArgs(batchNumber).
@@ -85,10 +88,10 @@ func TestRepositoryGet(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"id", "batch_number", "current_quantity", "current_temperature", "due_date", "initial_quantity", "manufacturing_date", "manufacturing_hour", "minimum_temperature", "product_id", "section_id"}
+		columns := []string{"id", "batch_number", "current_quantity", "current_temperature", "due_date", "initial_quantity", "manufacturing_date", "manufacturing_hour", "minimum_temperature", "product_id", "section_id", "status"}
 		rows := sqlmock.NewRows(columns)
 		batchNumber := 1
-		rows.AddRow(batchNumber, 1, 1, 2, "2021-01-01 10:00:00", 10, "2021-01-01 10:00:00", 10, 0, 1, 1)
+		rows.AddRow(batchNumber, 1, 1, 2, "2021-01-01 10:00:00", 10, "2021-01-01 10:00:00", 10, 0, 1, 1, domain.BatchAvailable)
 
 		mock.ExpectQuery(product_batch.GetQuery).WithArgs(batchNumber).WillReturnError(sql.ErrNoRows)
 
@@ -116,35 +119,57 @@ func TestRepositoryGet(t *testing.T) {
 }
 
 func TestRepositorySave(t *testing.T) {
-	t.Run("Should insert the product batches", func(t *testing.T) {
+	t.Run("Should insert the product batches and sync the section capacity", func(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"batch_number", "current_quantity", "current_temperature", "due_date", "initial_quantity", "manufacturing_date", "manufacturing_hour", "minimum_temperature", "product_id", "section_id"}
-		rows := sqlmock.NewRows(columns)
-		rows.AddRow(pb.BatchNumber, pb.CurrentQuantity, pb.CurrentTemperature, pb.DueDate, pb.InitialQuantity, pb.ManufacturingDate, pb.ManufacturingHour, pb.MinimumTemperature, pb.ProductID, pb.SectionID)
-
 		LastInsertId := 1
 		mockedProductBatches := pb
+		expectSectionUsage(mock, mockedProductBatches.SectionID, 10, 5)
 		mock.ExpectPrepare(regexp.QuoteMeta(product_batch.InsertQuery))
 		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertQuery)).
 			WithArgs(mockedProductBatches.BatchNumber, mockedProductBatches.CurrentQuantity, mockedProductBatches.CurrentTemperature, mockedProductBatches.DueDate, mockedProductBatches.InitialQuantity, mockedProductBatches.ManufacturingDate, mockedProductBatches.ManufacturingHour, mockedProductBatches.MinimumTemperature, mockedProductBatches.ProductID, mockedProductBatches.SectionID).
 			WillReturnResult(sqlmock.NewResult(int64(LastInsertId), 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertMovementQuery)).
+			WithArgs(LastInsertId, domain.MovementReceipt, nil, mockedProductBatches.SectionID, mockedProductBatches.CurrentQuantity, nil, nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		expectSync(mock, mockedProductBatches.SectionID)
+		mock.ExpectExec(regexp.QuoteMeta(outbox.InsertQuery)).
+			WithArgs(domain.EventProductBatchCreated, LastInsertId, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		repository := product_batch.NewRepository(db)
-		result := repository.Save(mockedProductBatches)
+		result, err := repository.Save(mockedProductBatches)
+		assert.NoError(t, err)
 		assert.Equal(t, LastInsertId, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return section full error when capacity would overflow", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedProductBatches := pb
+		expectSectionUsage(mock, mockedProductBatches.SectionID, 10, 10)
+		mock.ExpectRollback()
+
+		repository := product_batch.NewRepository(db)
+		result, err := repository.Save(mockedProductBatches)
+		assert.ErrorIs(t, err, product_batch.ErrSectionFull)
+		assert.Zero(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("Should throw panic when expected prepare fails", func(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
 		mockedProductBatches := pb
+		expectSectionUsage(mock, mockedProductBatches.SectionID, 10, 0)
 		mock.ExpectPrepare(regexp.QuoteMeta(product_batch.InsertQuery)).WillReturnError(sql.ErrNoRows)
 
 		repository := product_batch.NewRepository(db)
 		assert.Panics(t, func() {
-			repository.Save(mockedProductBatches)
+			_, _ = repository.Save(mockedProductBatches)
 		})
 	})
 	t.Run("Should throw panic when expected exec fails", func(t *testing.T) {
@@ -152,14 +177,15 @@ func TestRepositorySave(t *testing.T) {
 		defer db.Close()
 
 		mockedProductBatches := pb
+		expectSectionUsage(mock, mockedProductBatches.SectionID, 10, 0)
 		mock.ExpectPrepare(regexp.QuoteMeta(product_batch.InsertQuery))
-		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertQuery)).WillReturnError(sql.ErrNoRows).
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertQuery)).
 			WithArgs(mockedProductBatches.BatchNumber, mockedProductBatches.CurrentQuantity, mockedProductBatches.CurrentTemperature, mockedProductBatches.DueDate, mockedProductBatches.InitialQuantity, mockedProductBatches.ManufacturingDate, mockedProductBatches.ManufacturingHour, mockedProductBatches.MinimumTemperature, mockedProductBatches.ProductID, mockedProductBatches.SectionID).
 			WillReturnError(sql.ErrConnDone)
 
 		repository := product_batch.NewRepository(db)
 		assert.Panics(t, func() {
-			repository.Save(mockedProductBatches)
+			_, _ = repository.Save(mockedProductBatches)
 		})
 	})
 	t.Run("Should throw panic when sql has error", func(t *testing.T) {
@@ -167,6 +193,7 @@ func TestRepositorySave(t *testing.T) {
 		defer db.Close()
 
 		mockedProductBatches := pb
+		expectSectionUsage(mock, mockedProductBatches.SectionID, 10, 0)
 		mock.ExpectPrepare(regexp.QuoteMeta(product_batch.InsertQuery))
 		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertQuery)).
 			WithArgs(mockedProductBatches.BatchNumber, mockedProductBatches.CurrentQuantity, mockedProductBatches.CurrentTemperature, mockedProductBatches.DueDate, mockedProductBatches.InitialQuantity, mockedProductBatches.ManufacturingDate, mockedProductBatches.ManufacturingHour, mockedProductBatches.MinimumTemperature, mockedProductBatches.ProductID, mockedProductBatches.SectionID).
@@ -174,11 +201,488 @@ func TestRepositorySave(t *testing.T) {
 
 		repository := product_batch.NewRepository(db)
 		assert.Panics(t, func() {
-			repository.Save(mockedProductBatches)
+			_, _ = repository.Save(mockedProductBatches)
+		})
+	})
+	t.Run("Should throw panic when section does not exist", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedProductBatches := pb
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockSectionQuery)).
+			WithArgs(mockedProductBatches.SectionID).
+			WillReturnError(sql.ErrNoRows)
+
+		repository := product_batch.NewRepository(db)
+		assert.Panics(t, func() {
+			_, _ = repository.Save(mockedProductBatches)
+		})
+	})
+}
+
+func TestRepositoryTransfer(t *testing.T) {
+	transfer := domain.StockTransfer{ProductBatchID: 1, ToSectionID: 2, Quantity: 10, EmployeeID: 3}
+
+	t.Run("Should move the whole batch to the destination section", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatch(mock, transfer.ProductBatchID, 10, 1)
+		expectLockReserved(mock, transfer.ProductBatchID, 0)
+		expectSectionCapacity(mock, transfer.ToSectionID, 100, 0)
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.MoveBatchQuery)).
+			WithArgs(transfer.ToSectionID, transfer.ProductBatchID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertMovementQuery)).
+			WithArgs(transfer.ProductBatchID, domain.MovementTransfer, 1, transfer.ToSectionID, transfer.Quantity, transfer.EmployeeID, transfer.ProductBatchID, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		expectSync(mock, 1)
+		expectSync(mock, transfer.ToSectionID)
+		mock.ExpectCommit()
+
+		repository := product_batch.NewRepository(db)
+		result, err := repository.Transfer(transfer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, result.ID)
+		assert.Equal(t, transfer.ProductBatchID, *result.TargetBatchID)
+		assert.Equal(t, 1, *result.FromSectionID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should split the batch when moving part of it", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatch(mock, transfer.ProductBatchID, 25, 1)
+		expectLockReserved(mock, transfer.ProductBatchID, 15)
+		expectSectionCapacity(mock, transfer.ToSectionID, 100, 0)
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.DecreaseQuantityQuery)).
+			WithArgs(transfer.Quantity, transfer.ProductBatchID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.NextBatchNumberQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"batch_number"}).AddRow(42))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.SplitBatchQuery)).
+			WithArgs(42, transfer.Quantity, transfer.Quantity, transfer.ToSectionID, transfer.ProductBatchID).
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertMovementQuery)).
+			WithArgs(transfer.ProductBatchID, domain.MovementTransfer, 1, transfer.ToSectionID, transfer.Quantity, transfer.EmployeeID, 9, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(8, 1))
+		expectSync(mock, 1)
+		expectSync(mock, transfer.ToSectionID)
+		mock.ExpectCommit()
+
+		repository := product_batch.NewRepository(db)
+		result, err := repository.Transfer(transfer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 9, *result.TargetBatchID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should retry the split with the next batch number when the one read was taken", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatch(mock, transfer.ProductBatchID, 25, 1)
+		expectLockReserved(mock, transfer.ProductBatchID, 15)
+		expectSectionCapacity(mock, transfer.ToSectionID, 100, 0)
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.DecreaseQuantityQuery)).
+			WithArgs(transfer.Quantity, transfer.ProductBatchID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.NextBatchNumberQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"batch_number"}).AddRow(42))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.SplitBatchQuery)).
+			WithArgs(42, transfer.Quantity, transfer.Quantity, transfer.ToSectionID, transfer.ProductBatchID).
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '42' for key 'batch_number'"})
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.NextBatchNumberQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"batch_number"}).AddRow(43))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.SplitBatchQuery)).
+			WithArgs(43, transfer.Quantity, transfer.Quantity, transfer.ToSectionID, transfer.ProductBatchID).
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertMovementQuery)).
+			WithArgs(transfer.ProductBatchID, domain.MovementTransfer, 1, transfer.ToSectionID, transfer.Quantity, transfer.EmployeeID, 9, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(8, 1))
+		expectSync(mock, 1)
+		expectSync(mock, transfer.ToSectionID)
+		mock.ExpectCommit()
+
+		repository := product_batch.NewRepository(db)
+		result, err := repository.Transfer(transfer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 9, *result.TargetBatchID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return insufficient quantity error when the batch holds less", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatch(mock, transfer.ProductBatchID, 5, 1)
+		mock.ExpectRollback()
+
+		repository := product_batch.NewRepository(db)
+		result, err := repository.Transfer(transfer)
+
+		assert.ErrorIs(t, err, product_batch.ErrInsufficientQuantity)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return reserved quantity error when the move takes reserved units", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatch(mock, transfer.ProductBatchID, 25, 1)
+		expectLockReserved(mock, transfer.ProductBatchID, 16)
+		mock.ExpectRollback()
+
+		repository := product_batch.NewRepository(db)
+		result, err := repository.Transfer(transfer)
+
+		assert.ErrorIs(t, err, product_batch.ErrReservedQuantity)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return section full error when the destination is full", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatch(mock, transfer.ProductBatchID, 10, 1)
+		expectLockReserved(mock, transfer.ProductBatchID, 0)
+		expectSectionCapacity(mock, transfer.ToSectionID, 100, 95)
+		mock.ExpectRollback()
+
+		repository := product_batch.NewRepository(db)
+		result, err := repository.Transfer(transfer)
+
+		assert.ErrorIs(t, err, product_batch.ErrSectionFull)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when the batch does not exist", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchQuery)).
+			WithArgs(transfer.ProductBatchID).
+			WillReturnError(sql.ErrNoRows)
+
+		repository := product_batch.NewRepository(db)
+		assert.Panics(t, func() {
+			_, _ = repository.Transfer(transfer)
+		})
+	})
+}
+
+func TestRepositoryMovements(t *testing.T) {
+	t.Run("Should return the movements of the batch", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		columns := []string{"id", "product_batch_id", "movement_type", "from_section_id", "to_section_id", "quantity", "employee_id", "target_batch_id", "created_at"}
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, 1, domain.MovementReceipt, nil, 1, 10, nil, nil, "2021-01-01 10:00:00").
+			AddRow(2, 1, domain.MovementTransfer, 1, 2, 4, 3, 5, "2021-01-02 10:00:00")
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.GetMovementsByBatchQuery)).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		repository := product_batch.NewRepository(db)
+		result := repository.Movements(1)
+
+		from, to, employee, target := 1, 2, 3, 5
+		receiptTo := 1
+		assert.Equal(t, []domain.StockMovement{
+			{ID: 1, ProductBatchID: 1, Type: domain.MovementReceipt, ToSectionID: &receiptTo, Quantity: 10, CreatedAt: time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)},
+			{ID: 2, ProductBatchID: 1, Type: domain.MovementTransfer, FromSectionID: &from, ToSectionID: &to, Quantity: 4, EmployeeID: &employee, TargetBatchID: &target, CreatedAt: time.Date(2021, 1, 2, 10, 0, 0, 0, time.UTC)},
+		}, result)
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.GetMovementsByBatchQuery)).
+			WithArgs(1).
+			WillReturnError(sql.ErrConnDone)
+
+		repository := product_batch.NewRepository(db)
+		assert.Panics(t, func() {
+			repository.Movements(1)
+		})
+	})
+}
+
+func TestRepositoryProductQuantityBySection(t *testing.T) {
+	t.Run("Should return the quantity of the product by section", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"section_id", "quantity"}).
+			AddRow(1, 20).
+			AddRow(2, 5)
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.ProductQuantityBySectionQuery)).
+			WithArgs(pb.ProductID).
+			WillReturnRows(rows)
+
+		repository := product_batch.NewRepository(db)
+		result := repository.ProductQuantityBySection(pb.ProductID)
+
+		assert.Equal(t, map[int]int{1: 20, 2: 5}, result)
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.ProductQuantityBySectionQuery)).
+			WithArgs(pb.ProductID).
+			WillReturnError(sql.ErrConnDone)
+
+		repository := product_batch.NewRepository(db)
+		assert.Panics(t, func() {
+			repository.ProductQuantityBySection(pb.ProductID)
 		})
 	})
 }
 
+func TestRepositoryChangeStatus(t *testing.T) {
+	quarantine := domain.BatchStatusChange{ProductBatchID: 1, ToStatus: domain.BatchQuarantined, Reason: "temperature breach", EmployeeID: 3}
+	dispose := domain.BatchStatusChange{ProductBatchID: 1, ToStatus: domain.BatchDisposed, Reason: "spoiled", EmployeeID: 3}
+
+	t.Run("Should quarantine the batch and record the change", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatchStatus(mock, 1, domain.BatchAvailable, 10, 2)
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.UpdateStatusQuery)).
+			WithArgs(domain.BatchQuarantined, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertStatusChangeQuery)).
+			WithArgs(1, domain.BatchAvailable, domain.BatchQuarantined, quarantine.Reason, quarantine.EmployeeID, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectCommit()
+
+		repository := product_batch.NewRepository(db)
+		result, err := repository.ChangeStatus(quarantine)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, result.ID)
+		assert.Equal(t, domain.BatchAvailable, result.FromStatus)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should write off the batch when disposing of it", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatchStatus(mock, 1, domain.BatchQuarantined, 10, 2)
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.UpdateStatusQuery)).
+			WithArgs(domain.BatchDisposed, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertMovementQuery)).
+			WithArgs(1, domain.MovementDisposal, 2, nil, 10, dispose.EmployeeID, nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.WriteOffQuery)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.DeleteReservationsQuery)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		expectSync(mock, 2)
+		mock.ExpectExec(regexp.QuoteMeta(product_batch.InsertStatusChangeQuery)).
+			WithArgs(1, domain.BatchQuarantined, domain.BatchDisposed, dispose.Reason, dispose.EmployeeID, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectCommit()
+
+		repository := product_batch.NewRepository(db)
+		result, err := repository.ChangeStatus(dispose)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, result.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return invalid transition error when the batch is already disposed", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		expectLockBatchStatus(mock, 1, domain.BatchDisposed, 0, 2)
+		mock.ExpectRollback()
+
+		repository := product_batch.NewRepository(db)
+		result, err := repository.ChangeStatus(dispose)
+
+		assert.ErrorIs(t, err, product_batch.ErrInvalidTransition)
+		assert.Nil(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryStatusChanges(t *testing.T) {
+	t.Run("Should return the status changes of the batch", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		columns := []string{"id", "product_batch_id", "from_status", "to_status", "reason", "employee_id", "created_at"}
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, 1, domain.BatchAvailable, domain.BatchQuarantined, "temperature breach", 3, "2021-01-01 10:00:00")
+		mock.ExpectQuery(regexp.QuoteMeta(product_batch.GetStatusChangesQuery)).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		repository := product_batch.NewRepository(db)
+		result := repository.StatusChanges(1)
+
+		assert.Equal(t, []domain.BatchStatusChange{{
+			ID:             1,
+			ProductBatchID: 1,
+			FromStatus:     domain.BatchAvailable,
+			ToStatus:       domain.BatchQuarantined,
+			Reason:         "temperature breach",
+			EmployeeID:     3,
+			CreatedAt:      time.Date(2021, 01, 01, 10, 0, 0, 0, time.UTC),
+		}}, result)
+	})
+}
+
+func TestCanChangeStatus(t *testing.T) {
+	assert.True(t, product_batch.CanChangeStatus(domain.BatchAvailable, domain.BatchQuarantined))
+	assert.True(t, product_batch.CanChangeStatus(domain.BatchQuarantined, domain.BatchReleased))
+	assert.True(t, product_batch.CanChangeStatus(domain.BatchReleased, domain.BatchDisposed))
+	assert.False(t, product_batch.CanChangeStatus(domain.BatchAvailable, domain.BatchReleased))
+	assert.False(t, product_batch.CanChangeStatus(domain.BatchDisposed, domain.BatchQuarantined))
+}
+
+func expectSectionUsage(mock sqlmock.Sqlmock, sectionID int, maximumCapacity int, used int) {
+	mock.ExpectBegin()
+	expectSectionCapacity(mock, sectionID, maximumCapacity, used)
+}
+
+func expectSectionCapacity(mock sqlmock.Sqlmock, sectionID int, maximumCapacity int, used int) {
+	mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockSectionQuery)).
+		WithArgs(sectionID).
+		WillReturnRows(sqlmock.NewRows([]string{"maximum_capacity"}).AddRow(maximumCapacity))
+	mock.ExpectQuery(regexp.QuoteMeta(product_batch.SectionUsageQuery)).
+		WithArgs(sectionID).
+		WillReturnRows(sqlmock.NewRows([]string{"used"}).AddRow(used))
+}
+
+func expectLockBatch(mock sqlmock.Sqlmock, batchID int, currentQuantity int, sectionID int) {
+	mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchQuery)).
+		WithArgs(batchID).
+		WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}).AddRow(currentQuantity, sectionID))
+}
+
+func expectLockReserved(mock sqlmock.Sqlmock, batchID int, reserved int) {
+	mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockReservedQuery)).
+		WithArgs(batchID).
+		WillReturnRows(sqlmock.NewRows([]string{"reserved"}).AddRow(reserved))
+}
+
+func expectLockBatchStatus(mock sqlmock.Sqlmock, batchID int, status string, currentQuantity int, sectionID int) {
+	mock.ExpectQuery(regexp.QuoteMeta(product_batch.LockBatchStatusQuery)).
+		WithArgs(batchID).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "current_quantity", "section_id"}).AddRow(status, currentQuantity, sectionID))
+}
+
+func expectSync(mock sqlmock.Sqlmock, sectionID int) {
+	mock.ExpectExec(regexp.QuoteMeta(product_batch.SyncSectionCapacityQuery)).
+		WithArgs(sectionID, sectionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(product_batch.SectionCapacityQuery)).
+		WithArgs(sectionID).
+		WillReturnRows(sqlmock.NewRows([]string{"warehouse_id", "current_capacity", "maximum_capacity"}).AddRow(1, 15, 100))
+	mock.ExpectExec(regexp.QuoteMeta(outbox.InsertQuery)).
+		WithArgs(domain.EventSectionCapacityChanged, sectionID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestRepositoryGetBySections(t *testing.T) {
+	t.Run("Should return the batches of the sections with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(product_batch.GetBySectionsQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "batch_number", "current_quantity", "current_temperature", "due_date", "initial_quantity", "manufacturing_date", "manufacturing_hour", "minimum_temperature", "product_id", "section_id", "status"}).
+			AddRow(1, 100, 10, 2, "2024-01-10 00:00:00", 10, "2023-12-01 00:00:00", 8, 1, 1, 2, "available").
+			AddRow(4, 101, 5, 2, "2024-01-10 00:00:00", 5, "2023-12-01 00:00:00", 8, 1, 2, 5, "available")
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := product_batch.NewRepository(db)
+		result := repository.GetBySections([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := product_batch.NewRepository(db)
+		result := repository.GetBySections(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(product_batch.GetBySectionsQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := product_batch.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetBySections([]int{2}) })
+	})
+}
+
+func TestRepositoryGetMany(t *testing.T) {
+	t.Run("Should return the batches found among the ids with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(product_batch.GetManyQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "batch_number", "current_quantity", "current_temperature", "due_date", "initial_quantity", "manufacturing_date", "manufacturing_hour", "minimum_temperature", "product_id", "section_id", "status"}).
+			AddRow(2, 100, 10, 2, "2024-01-10 00:00:00", 10, "2023-12-01 00:00:00", 8, 1, 1, 2, "available").
+			AddRow(5, 101, 5, 2, "2024-01-10 00:00:00", 5, "2023-12-01 00:00:00", 8, 1, 2, 5, "available")
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := product_batch.NewRepository(db)
+		result := repository.GetMany([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := product_batch.NewRepository(db)
+		result := repository.GetMany(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(product_batch.GetManyQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := product_batch.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetMany([]int{2}) })
+	})
+}
+
 func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
 	t.Helper()
 
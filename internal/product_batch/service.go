@@ -1,36 +1,75 @@
 package product_batch
 
 import (
+	"errors"
+	"math"
+	"sort"
+
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
 
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse"
 )
 
 const (
-	ResourceAlreadyExists = "batch number %d already exists"
-	ProductNotFound       = "product not found with id %d"
-	SectionNotFound       = "section not found with id %d"
+	ResourceAlreadyExists = "product_batch.already_exists"
+	ProductNotFound       = "product.not_found"
+	SectionNotFound       = "section.not_found"
+	IncompatibleType      = "product_batch.incompatible_product_type"
+	IncompatibleTemp      = "product_batch.incompatible_temperature"
+	SectionFull           = "section.capacity_exceeded"
+	WarehouseNotFound     = "warehouse.not_found"
+	EmployeeNotFound      = "employee.not_found"
+	ResourceNotFound      = "product_batch.not_found"
+	InsufficientQuantity  = "product_batch.insufficient_quantity"
+	ReservedQuantity      = "product_batch.reserved_quantity"
+	SameSection           = "product_batch.same_section"
+	InvalidTransition     = "product_batch.invalid_status_transition"
+)
+
+// Weights used to rank putaway suggestions. Fill level dominates so batches
+// are packed into the sections that are already fuller, and grouping breaks
+// ties in favour of sections that already hold the product.
+const (
+	fillWeight     = 0.7
+	groupingWeight = 0.3
 )
 
 type Service interface {
+	GetBySections(sectionIDs []int) []domain.ProductBatch
 	Create(pb domain.ProductBatch) (*domain.ProductBatch, error)
+	SuggestPutaway(productID int, quantity int, warehouseID int) ([]domain.PutawaySuggestion, error)
+	Transfer(t domain.StockTransfer) (*domain.StockMovement, error)
+	Movements(id int) ([]domain.StockMovement, error)
+	ChangeStatus(c domain.BatchStatusChange) (*domain.BatchStatusChange, error)
+	StatusChanges(id int) ([]domain.BatchStatusChange, error)
 }
 type service struct {
-	repository        Repository
-	productRepository product.Repository
-	sectionRepository section.Repository
+	repository          Repository
+	productRepository   product.Repository
+	sectionRepository   section.Repository
+	warehouseRepository warehouse.Repository
+	employeeRepository  employee.Repository
 }
 
-func NewService(repository Repository, productRepository product.Repository, sectionRepository section.Repository) Service {
+func NewService(repository Repository, productRepository product.Repository, sectionRepository section.Repository, warehouseRepository warehouse.Repository, employeeRepository employee.Repository) Service {
 	return &service{
 		repository,
 		productRepository,
 		sectionRepository,
+		warehouseRepository,
+		employeeRepository,
 	}
 }
 
+// GetBySections returns the batches stored in every section of sectionIDs.
+func (s *service) GetBySections(sectionIDs []int) []domain.ProductBatch {
+	return s.repository.GetBySections(sectionIDs)
+}
+
 func (s *service) Create(pb domain.ProductBatch) (*domain.ProductBatch, error) {
 	if s.repository.Exists(pb.BatchNumber) {
 		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, pb.BatchNumber)
@@ -47,7 +86,189 @@ func (s *service) Create(pb domain.ProductBatch) (*domain.ProductBatch, error) {
 	if sectionFound == nil {
 		return nil, apperr.NewDependentResourceNotFound(SectionNotFound, pb.SectionID)
 	}
-	id := s.repository.Save(pb)
+
+	if err := checkPlacement(pb, *productFound, *sectionFound); err != nil {
+		return nil, err
+	}
+
+	id, err := s.repository.Save(pb)
+	if errors.Is(err, ErrSectionFull) {
+		free := sectionFound.MaximumCapacity - sectionFound.CurrentCapacity
+		return nil, apperr.NewIncompatibleResource(SectionFull, sectionFound.ID, pb.CurrentQuantity, free)
+	}
 
 	return s.repository.Get(id), nil
 }
+
+// Transfer moves quantity of a batch to another section, possibly in another
+// warehouse. The destination section must accept the batch and have enough
+// free capacity.
+func (s *service) Transfer(t domain.StockTransfer) (*domain.StockMovement, error) {
+	batchFound := s.repository.Get(t.ProductBatchID)
+
+	if batchFound == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, t.ProductBatchID)
+	}
+
+	if t.Quantity > batchFound.CurrentQuantity {
+		return nil, apperr.NewIncompatibleResource(InsufficientQuantity, batchFound.ID, batchFound.CurrentQuantity, t.Quantity)
+	}
+
+	if t.ToSectionID == batchFound.SectionID {
+		return nil, apperr.NewIncompatibleResource(SameSection, batchFound.ID, t.ToSectionID)
+	}
+
+	sectionFound := s.sectionRepository.Get(t.ToSectionID)
+
+	if sectionFound == nil {
+		return nil, apperr.NewDependentResourceNotFound(SectionNotFound, t.ToSectionID)
+	}
+
+	if s.employeeRepository.Get(t.EmployeeID) == nil {
+		return nil, apperr.NewDependentResourceNotFound(EmployeeNotFound, t.EmployeeID)
+	}
+
+	productFound := s.productRepository.Get(batchFound.ProductID)
+
+	if productFound == nil {
+		return nil, apperr.NewDependentResourceNotFound(ProductNotFound, batchFound.ProductID)
+	}
+
+	if err := checkPlacement(*batchFound, *productFound, *sectionFound); err != nil {
+		return nil, err
+	}
+
+	movement, err := s.repository.Transfer(t)
+	if errors.Is(err, ErrSectionFull) {
+		free := sectionFound.MaximumCapacity - sectionFound.CurrentCapacity
+		return nil, apperr.NewIncompatibleResource(SectionFull, sectionFound.ID, t.Quantity, free)
+	}
+	if errors.Is(err, ErrInsufficientQuantity) {
+		return nil, apperr.NewIncompatibleResource(InsufficientQuantity, batchFound.ID, batchFound.CurrentQuantity, t.Quantity)
+	}
+	if errors.Is(err, ErrReservedQuantity) {
+		return nil, apperr.NewIncompatibleResource(ReservedQuantity, batchFound.ID, t.Quantity)
+	}
+
+	return movement, nil
+}
+
+func (s *service) Movements(id int) ([]domain.StockMovement, error) {
+	if s.repository.Get(id) == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	}
+
+	return s.repository.Movements(id), nil
+}
+
+// ChangeStatus quarantines, releases or disposes of a batch on behalf of an
+// employee. The move must be allowed from the current status of the batch.
+func (s *service) ChangeStatus(c domain.BatchStatusChange) (*domain.BatchStatusChange, error) {
+	batchFound := s.repository.Get(c.ProductBatchID)
+
+	if batchFound == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, c.ProductBatchID)
+	}
+
+	if !CanChangeStatus(batchFound.Status, c.ToStatus) {
+		return nil, apperr.NewIncompatibleResource(InvalidTransition, batchFound.ID, batchFound.Status, c.ToStatus)
+	}
+
+	if s.employeeRepository.Get(c.EmployeeID) == nil {
+		return nil, apperr.NewDependentResourceNotFound(EmployeeNotFound, c.EmployeeID)
+	}
+
+	change, err := s.repository.ChangeStatus(c)
+	if errors.Is(err, ErrInvalidTransition) {
+		return nil, apperr.NewIncompatibleResource(InvalidTransition, batchFound.ID, batchFound.Status, c.ToStatus)
+	}
+
+	return change, nil
+}
+
+func (s *service) StatusChanges(id int) ([]domain.BatchStatusChange, error) {
+	if s.repository.Get(id) == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	}
+
+	return s.repository.StatusChanges(id), nil
+}
+
+// SuggestPutaway ranks the sections of the warehouse that can receive the
+// given quantity of the product. Sections are kept when checkPlacement accepts
+// them, using the product recommended freezing temperature, and when they have
+// enough free capacity. The best candidates come first.
+func (s *service) SuggestPutaway(productID int, quantity int, warehouseID int) ([]domain.PutawaySuggestion, error) {
+	productFound := s.productRepository.Get(productID)
+
+	if productFound == nil {
+		return nil, apperr.NewDependentResourceNotFound(ProductNotFound, productID)
+	}
+
+	if s.warehouseRepository.Get(warehouseID) == nil {
+		return nil, apperr.NewDependentResourceNotFound(WarehouseNotFound, warehouseID)
+	}
+
+	pb := domain.ProductBatch{
+		CurrentQuantity:    quantity,
+		MinimumTemperature: productFound.RecomFreezTemp,
+		ProductID:          productID,
+	}
+	stored := s.repository.ProductQuantityBySection(productID)
+	suggestions := make([]domain.PutawaySuggestion, 0)
+
+	for _, sc := range s.sectionRepository.GetByWarehouse(warehouseID) {
+		free := sc.MaximumCapacity - sc.CurrentCapacity
+		if free < quantity || checkPlacement(pb, *productFound, sc) != nil {
+			continue
+		}
+		suggestions = append(suggestions, scoreSection(sc, quantity, stored[sc.ID]))
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].SectionID < suggestions[j].SectionID
+	})
+
+	return suggestions, nil
+}
+
+// scoreSection rates a candidate section from 0 to 100. The fill level is the
+// share of the capacity used once the batch is stored, and grouping is the
+// share of the current stock that already belongs to the same product.
+func scoreSection(sc domain.Section, quantity int, productQuantity int) domain.PutawaySuggestion {
+	var fill, grouping float64
+	if sc.MaximumCapacity > 0 {
+		fill = float64(sc.CurrentCapacity+quantity) / float64(sc.MaximumCapacity)
+	}
+	if sc.CurrentCapacity > 0 {
+		grouping = float64(productQuantity) / float64(sc.CurrentCapacity)
+	}
+
+	return domain.PutawaySuggestion{
+		SectionID:       sc.ID,
+		SectionNumber:   sc.SectionNumber,
+		FreeCapacity:    sc.MaximumCapacity - sc.CurrentCapacity,
+		ProductQuantity: productQuantity,
+		FillPercent:     math.Round(fill*10000) / 100,
+		Score:           math.Round((fill*fillWeight+grouping*groupingWeight)*10000) / 100,
+	}
+}
+
+// checkPlacement tells whether a batch of the given product can be stored in
+// the section. The section must store the same product type, and the batch
+// minimum temperature must lie between the section minimum and current
+// temperatures.
+func checkPlacement(pb domain.ProductBatch, p domain.Product, sc domain.Section) error {
+	if p.ProductTypeID != sc.ProductTypeID {
+		return apperr.NewIncompatibleResource(IncompatibleType, p.ID, p.ProductTypeID, sc.ID, sc.ProductTypeID)
+	}
+
+	if pb.MinimumTemperature < sc.MinimumTemperature || pb.MinimumTemperature > sc.CurrentTemperature {
+		return apperr.NewIncompatibleResource(IncompatibleTemp, pb.MinimumTemperature, sc.ID, sc.MinimumTemperature, sc.CurrentTemperature)
+	}
+
+	return nil
+}
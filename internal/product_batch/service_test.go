@@ -5,10 +5,12 @@ import (
 	"time"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	employee_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee/mocks"
 	product_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product/mocks"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_batch/mocks"
 	section_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section/mocks"
+	warehouse_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse/mocks"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
 	"github.com/stretchr/testify/assert"
 )
@@ -26,15 +28,16 @@ var (
 		MinimumTemperature: 0,
 		ProductID:          1,
 		SectionID:          1,
+		Status:             domain.BatchAvailable,
 	}
 )
 
 func TestServiceCreate(t *testing.T) {
 	t.Run("Should return a created product batches", func(t *testing.T) {
-		service, repository, productRepository, sectionRepository := CreateService(t)
+		service, repository, productRepository, sectionRepository, _, _ := CreateService(t)
 
 		id := 1
-		repository.On("Save", productBatch).Return(id)
+		repository.On("Save", productBatch).Return(id, nil)
 		repository.On("Get", id).Return(&productBatch)
 		repository.On("Exists", productBatch.BatchNumber).Return(false)
 		productRepository.On("Get", productBatch.ProductID).Return(&domain.Product{})
@@ -46,7 +49,7 @@ func TestServiceCreate(t *testing.T) {
 		assert.Equal(t, productBatch, *result)
 	})
 	t.Run("Should return a conflict error when product not found", func(t *testing.T) {
-		service, repository, productRepository, _ := CreateService(t)
+		service, repository, productRepository, _, _, _ := CreateService(t)
 
 		mockedProductBatch := productBatch
 		var productGetResult *domain.Product
@@ -60,7 +63,7 @@ func TestServiceCreate(t *testing.T) {
 		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
 	})
 	t.Run("Should return a conflict error when section not found", func(t *testing.T) {
-		service, repository, productRepository, sectionRepository := CreateService(t)
+		service, repository, productRepository, sectionRepository, _, _ := CreateService(t)
 
 		mockedProductBatch := productBatch
 		var sectionGetResult *domain.Section
@@ -74,8 +77,54 @@ func TestServiceCreate(t *testing.T) {
 		assert.Nil(t, result)
 		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
 	})
+	t.Run("Should return a conflict error when product type differs from section", func(t *testing.T) {
+		service, repository, productRepository, sectionRepository, _, _ := CreateService(t)
+
+		mockedProductBatch := productBatch
+
+		repository.On("Exists", mockedProductBatch.BatchNumber).Return(false)
+		productRepository.On("Get", mockedProductBatch.ProductID).Return(&domain.Product{ID: 1, ProductTypeID: 1})
+		sectionRepository.On("Get", mockedProductBatch.SectionID).Return(&domain.Section{ID: 1, ProductTypeID: 2})
+		result, err := service.Create(mockedProductBatch)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		assert.Equal(t, "o produto 1 é do tipo 1, mas a seção 1 armazena produtos do tipo 2", err.Error())
+	})
+	t.Run("Should return a conflict error when minimum temperature is outside section range", func(t *testing.T) {
+		service, repository, productRepository, sectionRepository, _, _ := CreateService(t)
+
+		mockedProductBatch := productBatch
+		mockedProductBatch.MinimumTemperature = -18
+
+		repository.On("Exists", mockedProductBatch.BatchNumber).Return(false)
+		productRepository.On("Get", mockedProductBatch.ProductID).Return(&domain.Product{ID: 1, ProductTypeID: 1})
+		sectionRepository.On("Get", mockedProductBatch.SectionID).Return(&domain.Section{ID: 1, ProductTypeID: 1, MinimumTemperature: 0, CurrentTemperature: 4})
+		result, err := service.Create(mockedProductBatch)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return a conflict error when section is full", func(t *testing.T) {
+		service, repository, productRepository, sectionRepository, _, _ := CreateService(t)
+
+		mockedProductBatch := productBatch
+
+		repository.On("Exists", mockedProductBatch.BatchNumber).Return(false)
+		repository.On("Save", mockedProductBatch).Return(0, product_batch.ErrSectionFull)
+		productRepository.On("Get", mockedProductBatch.ProductID).Return(&domain.Product{})
+		sectionRepository.On("Get", mockedProductBatch.SectionID).Return(&domain.Section{ID: 1, CurrentCapacity: 10, MaximumCapacity: 10})
+		result, err := service.Create(mockedProductBatch)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		assert.Equal(t, "a seção 1 não comporta mais 1 unidades, restam apenas 0 livres", err.Error())
+	})
 	t.Run("Should return a conflict error when batch number already exists", func(t *testing.T) {
-		service, repository, _, _ := CreateService(t)
+		service, repository, _, _, _, _ := CreateService(t)
 
 		mockedProductBatch := productBatch
 
@@ -89,12 +138,311 @@ func TestServiceCreate(t *testing.T) {
 	})
 }
 
-func CreateService(t *testing.T) (product_batch.Service, *mocks.Repository, *product_mocks.Repository, *section_mocks.Repository) {
+func TestServiceSuggestPutaway(t *testing.T) {
+	product := domain.Product{ID: 1, ProductTypeID: 1, RecomFreezTemp: -18}
+
+	t.Run("Should rank the compatible sections by fill level and grouping", func(t *testing.T) {
+		service, repository, productRepository, sectionRepository, warehouseRepository, _ := CreateService(t)
+
+		sections := []domain.Section{
+			{ID: 1, SectionNumber: 10, MinimumTemperature: -20, CurrentTemperature: -15, CurrentCapacity: 20, MaximumCapacity: 100, ProductTypeID: 1},
+			{ID: 2, SectionNumber: 20, MinimumTemperature: -20, CurrentTemperature: -15, CurrentCapacity: 60, MaximumCapacity: 100, ProductTypeID: 1},
+			{ID: 3, SectionNumber: 30, MinimumTemperature: -20, CurrentTemperature: -15, CurrentCapacity: 20, MaximumCapacity: 100, ProductTypeID: 2},
+			{ID: 4, SectionNumber: 40, MinimumTemperature: -10, CurrentTemperature: 0, CurrentCapacity: 20, MaximumCapacity: 100, ProductTypeID: 1},
+			{ID: 5, SectionNumber: 50, MinimumTemperature: -20, CurrentTemperature: -15, CurrentCapacity: 95, MaximumCapacity: 100, ProductTypeID: 1},
+		}
+		productRepository.On("Get", product.ID).Return(&product)
+		warehouseRepository.On("Get", 1).Return(&domain.Warehouse{ID: 1})
+		sectionRepository.On("GetByWarehouse", 1).Return(sections)
+		repository.On("ProductQuantityBySection", product.ID).Return(map[int]int{1: 20})
+
+		result, err := service.SuggestPutaway(product.ID, 10, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.PutawaySuggestion{
+			{SectionID: 1, SectionNumber: 10, FreeCapacity: 80, ProductQuantity: 20, FillPercent: 30, Score: 51},
+			{SectionID: 2, SectionNumber: 20, FreeCapacity: 40, ProductQuantity: 0, FillPercent: 70, Score: 49},
+		}, result)
+	})
+	t.Run("Should return an empty list when no section fits", func(t *testing.T) {
+		service, repository, productRepository, sectionRepository, warehouseRepository, _ := CreateService(t)
+
+		productRepository.On("Get", product.ID).Return(&product)
+		warehouseRepository.On("Get", 1).Return(&domain.Warehouse{ID: 1})
+		sectionRepository.On("GetByWarehouse", 1).Return([]domain.Section{})
+		repository.On("ProductQuantityBySection", product.ID).Return(map[int]int{})
+
+		result, err := service.SuggestPutaway(product.ID, 10, 1)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+	t.Run("Should return a conflict error when the product does not exist", func(t *testing.T) {
+		service, _, productRepository, _, _, _ := CreateService(t)
+
+		var productFound *domain.Product
+		productRepository.On("Get", product.ID).Return(productFound)
+
+		result, err := service.SuggestPutaway(product.ID, 10, 1)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
+	})
+	t.Run("Should return a conflict error when the warehouse does not exist", func(t *testing.T) {
+		service, _, productRepository, _, warehouseRepository, _ := CreateService(t)
+
+		var warehouseFound *domain.Warehouse
+		productRepository.On("Get", product.ID).Return(&product)
+		warehouseRepository.On("Get", 1).Return(warehouseFound)
+
+		result, err := service.SuggestPutaway(product.ID, 10, 1)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
+	})
+}
+
+func TestServiceTransfer(t *testing.T) {
+	transfer := domain.StockTransfer{ProductBatchID: 1, ToSectionID: 2, Quantity: 1, EmployeeID: 3}
+	destination := domain.Section{ID: 2, ProductTypeID: 1, MinimumTemperature: -5, CurrentTemperature: 5, CurrentCapacity: 0, MaximumCapacity: 10}
+
+	t.Run("Should transfer the batch and return the movement", func(t *testing.T) {
+		service, repository, productRepository, sectionRepository, _, employeeRepository := CreateService(t)
+
+		movement := &domain.StockMovement{ID: 1, ProductBatchID: 1, Type: domain.MovementTransfer, Quantity: 1}
+		repository.On("Get", transfer.ProductBatchID).Return(&productBatch)
+		sectionRepository.On("Get", transfer.ToSectionID).Return(&destination)
+		employeeRepository.On("Get", transfer.EmployeeID).Return(&domain.Employee{ID: 3})
+		productRepository.On("Get", productBatch.ProductID).Return(&domain.Product{ID: 1, ProductTypeID: 1})
+		repository.On("Transfer", transfer).Return(movement, nil)
+
+		result, err := service.Transfer(transfer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, movement, result)
+	})
+	t.Run("Should return not found error when the batch does not exist", func(t *testing.T) {
+		service, repository, _, _, _, _ := CreateService(t)
+
+		var batchFound *domain.ProductBatch
+		repository.On("Get", transfer.ProductBatchID).Return(batchFound)
+
+		result, err := service.Transfer(transfer)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+	t.Run("Should return a conflict error when the batch holds less than asked", func(t *testing.T) {
+		service, repository, _, _, _, _ := CreateService(t)
+
+		repository.On("Get", transfer.ProductBatchID).Return(&productBatch)
+		tooMuch := transfer
+		tooMuch.Quantity = 5
+
+		result, err := service.Transfer(tooMuch)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		assert.Equal(t, "o lote 1 possui apenas 1 unidades, foram solicitadas 5", err.Error())
+	})
+	t.Run("Should return a conflict error when the batch is already in the section", func(t *testing.T) {
+		service, repository, _, _, _, _ := CreateService(t)
+
+		repository.On("Get", transfer.ProductBatchID).Return(&productBatch)
+		sameSection := transfer
+		sameSection.ToSectionID = productBatch.SectionID
+
+		result, err := service.Transfer(sameSection)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return a conflict error when the employee does not exist", func(t *testing.T) {
+		service, repository, _, sectionRepository, _, employeeRepository := CreateService(t)
+
+		var employeeFound *domain.Employee
+		repository.On("Get", transfer.ProductBatchID).Return(&productBatch)
+		sectionRepository.On("Get", transfer.ToSectionID).Return(&destination)
+		employeeRepository.On("Get", transfer.EmployeeID).Return(employeeFound)
+
+		result, err := service.Transfer(transfer)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
+	})
+	t.Run("Should return a conflict error when the destination does not accept the batch", func(t *testing.T) {
+		service, repository, productRepository, sectionRepository, _, employeeRepository := CreateService(t)
+
+		repository.On("Get", transfer.ProductBatchID).Return(&productBatch)
+		sectionRepository.On("Get", transfer.ToSectionID).Return(&destination)
+		employeeRepository.On("Get", transfer.EmployeeID).Return(&domain.Employee{ID: 3})
+		productRepository.On("Get", productBatch.ProductID).Return(&domain.Product{ID: 1, ProductTypeID: 2})
+
+		result, err := service.Transfer(transfer)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		repository.AssertNotCalled(t, "Transfer", transfer)
+	})
+	t.Run("Should return a conflict error when the destination is full", func(t *testing.T) {
+		service, repository, productRepository, sectionRepository, _, employeeRepository := CreateService(t)
+
+		var movement *domain.StockMovement
+		repository.On("Get", transfer.ProductBatchID).Return(&productBatch)
+		sectionRepository.On("Get", transfer.ToSectionID).Return(&destination)
+		employeeRepository.On("Get", transfer.EmployeeID).Return(&domain.Employee{ID: 3})
+		productRepository.On("Get", productBatch.ProductID).Return(&domain.Product{ID: 1, ProductTypeID: 1})
+		repository.On("Transfer", transfer).Return(movement, product_batch.ErrSectionFull)
+
+		result, err := service.Transfer(transfer)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return a conflict error when the move takes reserved units", func(t *testing.T) {
+		service, repository, productRepository, sectionRepository, _, employeeRepository := CreateService(t)
+
+		var movement *domain.StockMovement
+		repository.On("Get", transfer.ProductBatchID).Return(&productBatch)
+		sectionRepository.On("Get", transfer.ToSectionID).Return(&destination)
+		employeeRepository.On("Get", transfer.EmployeeID).Return(&domain.Employee{ID: 3})
+		productRepository.On("Get", productBatch.ProductID).Return(&domain.Product{ID: 1, ProductTypeID: 1})
+		repository.On("Transfer", transfer).Return(movement, product_batch.ErrReservedQuantity)
+
+		result, err := service.Transfer(transfer)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+}
+
+func TestServiceMovements(t *testing.T) {
+	t.Run("Should return the movements of the batch", func(t *testing.T) {
+		service, repository, _, _, _, _ := CreateService(t)
+
+		movements := []domain.StockMovement{{ID: 1, ProductBatchID: 1, Type: domain.MovementReceipt, Quantity: 1}}
+		repository.On("Get", productBatch.ID).Return(&productBatch)
+		repository.On("Movements", productBatch.ID).Return(movements)
+
+		result, err := service.Movements(productBatch.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, movements, result)
+	})
+	t.Run("Should return not found error when the batch does not exist", func(t *testing.T) {
+		service, repository, _, _, _, _ := CreateService(t)
+
+		var batchFound *domain.ProductBatch
+		repository.On("Get", productBatch.ID).Return(batchFound)
+
+		result, err := service.Movements(productBatch.ID)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func TestServiceChangeStatus(t *testing.T) {
+	change := domain.BatchStatusChange{ProductBatchID: productBatch.ID, ToStatus: domain.BatchQuarantined, Reason: "temperature breach", EmployeeID: 3}
+
+	t.Run("Should return the recorded status change", func(t *testing.T) {
+		service, repository, _, _, _, employeeRepository := CreateService(t)
+
+		recorded := &domain.BatchStatusChange{ID: 1, ProductBatchID: productBatch.ID, FromStatus: domain.BatchAvailable, ToStatus: domain.BatchQuarantined, Reason: change.Reason, EmployeeID: 3}
+		repository.On("Get", productBatch.ID).Return(&productBatch)
+		employeeRepository.On("Get", change.EmployeeID).Return(&domain.Employee{ID: 3})
+		repository.On("ChangeStatus", change).Return(recorded, nil)
+
+		result, err := service.ChangeStatus(change)
+
+		assert.NoError(t, err)
+		assert.Equal(t, recorded, result)
+	})
+	t.Run("Should return not found error when the batch does not exist", func(t *testing.T) {
+		service, repository, _, _, _, _ := CreateService(t)
+
+		var batchFound *domain.ProductBatch
+		repository.On("Get", productBatch.ID).Return(batchFound)
+
+		result, err := service.ChangeStatus(change)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+	t.Run("Should return incompatible error when the transition is not allowed", func(t *testing.T) {
+		service, repository, _, _, _, _ := CreateService(t)
+
+		disposed := productBatch
+		disposed.Status = domain.BatchDisposed
+		repository.On("Get", productBatch.ID).Return(&disposed)
+
+		result, err := service.ChangeStatus(change)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		repository.AssertNotCalled(t, "ChangeStatus", change)
+	})
+	t.Run("Should return dependent not found error when the employee does not exist", func(t *testing.T) {
+		service, repository, _, _, _, employeeRepository := CreateService(t)
+
+		var employeeFound *domain.Employee
+		repository.On("Get", productBatch.ID).Return(&productBatch)
+		employeeRepository.On("Get", change.EmployeeID).Return(employeeFound)
+
+		result, err := service.ChangeStatus(change)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
+	})
+	t.Run("Should return incompatible error when the status changed meanwhile", func(t *testing.T) {
+		service, repository, _, _, _, employeeRepository := CreateService(t)
+
+		var recorded *domain.BatchStatusChange
+		repository.On("Get", productBatch.ID).Return(&productBatch)
+		employeeRepository.On("Get", change.EmployeeID).Return(&domain.Employee{ID: 3})
+		repository.On("ChangeStatus", change).Return(recorded, product_batch.ErrInvalidTransition)
+
+		result, err := service.ChangeStatus(change)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+}
+
+func TestServiceStatusChanges(t *testing.T) {
+	t.Run("Should return the status changes of the batch", func(t *testing.T) {
+		service, repository, _, _, _, _ := CreateService(t)
+
+		changes := []domain.BatchStatusChange{{ID: 1, ProductBatchID: 1, FromStatus: domain.BatchAvailable, ToStatus: domain.BatchQuarantined}}
+		repository.On("Get", productBatch.ID).Return(&productBatch)
+		repository.On("StatusChanges", productBatch.ID).Return(changes)
+
+		result, err := service.StatusChanges(productBatch.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, changes, result)
+	})
+	t.Run("Should return not found error when the batch does not exist", func(t *testing.T) {
+		service, repository, _, _, _, _ := CreateService(t)
+
+		var batchFound *domain.ProductBatch
+		repository.On("Get", productBatch.ID).Return(batchFound)
+
+		result, err := service.StatusChanges(productBatch.ID)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func CreateService(t *testing.T) (product_batch.Service, *mocks.Repository, *product_mocks.Repository, *section_mocks.Repository, *warehouse_mocks.Repository, *employee_mocks.Repository) {
 	t.Helper()
 	repository := new(mocks.Repository)
 	productRepository := new(product_mocks.Repository)
 	sectionRepository := new(section_mocks.Repository)
-	service := product_batch.NewService(repository, productRepository, sectionRepository)
+	warehouseRepository := new(warehouse_mocks.Repository)
+	employeeRepository := new(employee_mocks.Repository)
+	service := product_batch.NewService(repository, productRepository, sectionRepository, warehouseRepository, employeeRepository)
 
-	return service, repository, productRepository, sectionRepository
+	return service, repository, productRepository, sectionRepository, warehouseRepository, employeeRepository
 }
@@ -15,6 +15,11 @@ func (r *Repository) Get(id int) *domain.ProductRecord {
 	args := r.Called(id)
 	return args.Get(0).(*domain.ProductRecord)
 }
+
+func (r *Repository) GetMany(ids []int) []domain.ProductRecord {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.ProductRecord)
+}
 func (r *Repository) Exists(productId int, lastUpdateDate time.Time) bool {
 	args := r.Called(productId, lastUpdateDate)
 	return args.Get(0).(bool)
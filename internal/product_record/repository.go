@@ -10,15 +10,17 @@ import (
 )
 
 const (
-	InsertQuery = "INSERT INTO product_records (last_update_date, purchase_price, sale_price, product_id) VALUES (?, ?, ?, ?)"
-	ExistsQuery = "SELECT product_id, last_update_date FROM product_records WHERE product_id=? AND last_update_date=?"
-	GetQuery    = "SELECT id, last_update_date, purchase_price, sale_price, product_id FROM product_records WHERE id=?"
+	InsertQuery  = "INSERT INTO product_records (last_update_date, purchase_price, sale_price, product_id) VALUES (?, ?, ?, ?)"
+	ExistsQuery  = "SELECT product_id, last_update_date FROM product_records WHERE product_id=? AND last_update_date=?"
+	GetQuery     = "SELECT id, last_update_date, purchase_price, sale_price, product_id FROM product_records WHERE id=?"
+	GetManyQuery = "SELECT id, last_update_date, purchase_price, sale_price, product_id FROM product_records WHERE id IN (%s)"
 )
 
 type Repository interface {
 	Save(productRecord domain.ProductRecord) int
 	Exists(productId int, lastUpdateDate time.Time) bool
 	Get(id int) *domain.ProductRecord
+	GetMany(ids []int) []domain.ProductRecord
 }
 
 type repository struct {
@@ -75,3 +77,29 @@ func (r *repository) Get(id int) *domain.ProductRecord {
 
 	return &productRecord
 }
+
+// GetMany returns the product records found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.ProductRecord {
+	records := make([]domain.ProductRecord, 0, len(ids))
+	if len(ids) == 0 {
+		return records
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		productRecord := domain.ProductRecord{}
+		var lastUpdateDate string
+		if err := rows.Scan(&productRecord.ID, &lastUpdateDate, &productRecord.PurchasePrice, &productRecord.SalePrice, &productRecord.ProductID); err != nil {
+			panic(err)
+		}
+		productRecord.LastUpdateDate = helpers.ToDateTime(lastUpdateDate)
+		records = append(records, productRecord)
+	}
+	return records
+}
@@ -8,8 +8,8 @@ import (
 )
 
 const (
-	ResourceNotFound      = "produto não encontrado com o id %d"
-	ResourceAlreadyExists = "um registro de produto com o id de produto '%d' e última data de atualização `%s` já existe"
+	ResourceNotFound      = "product.not_found"
+	ResourceAlreadyExists = "product_record.already_exists"
 )
 
 type Service interface {
@@ -13,3 +13,8 @@ func (r *Repository) Get(id int) *domain.ProductType {
 	args := r.Called(id)
 	return args.Get(0).(*domain.ProductType)
 }
+
+func (r *Repository) GetMany(ids []int) []domain.ProductType {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.ProductType)
+}
@@ -5,13 +5,16 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 type Repository interface {
 	Get(id int) *domain.ProductType
+	GetMany(ids []int) []domain.ProductType
 }
 
 const GetQuery = "SELECT id FROM product_types WHERE id=?"
+const GetManyQuery = "SELECT id FROM product_types WHERE id IN (%s)"
 
 type repository struct {
 	db *sql.DB
@@ -36,3 +39,27 @@ func (r repository) Get(id int) *domain.ProductType {
 
 	return &pt
 }
+
+// GetMany returns the product types found among ids, in no particular order.
+func (r repository) GetMany(ids []int) []domain.ProductType {
+	productTypes := make([]domain.ProductType, 0, len(ids))
+	if len(ids) == 0 {
+		return productTypes
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		pt := domain.ProductType{}
+		if err := rows.Scan(&pt.ID); err != nil {
+			panic(err)
+		}
+		productTypes = append(productTypes, pt)
+	}
+	return productTypes
+}
@@ -13,3 +13,8 @@ func (r *Repository) Get(id int) *domain.Province {
 	args := r.Called(id)
 	return args.Get(0).(*domain.Province)
 }
+
+func (r *Repository) GetMany(ids []int) []domain.Province {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.Province)
+}
@@ -5,15 +5,18 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetQuery = "SELECT id FROM provinces WHERE id=?"
+	GetQuery     = "SELECT id FROM provinces WHERE id=?"
+	GetManyQuery = "SELECT id FROM provinces WHERE id IN (%s)"
 )
 
 // Repository encapsulates the storage of a Province.
 type Repository interface {
 	Get(id int) *domain.Province
+	GetMany(ids []int) []domain.Province
 }
 
 type repository struct {
@@ -39,3 +42,27 @@ func (r *repository) Get(id int) *domain.Province {
 
 	return &s
 }
+
+// GetMany returns the provinces found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.Province {
+	provinces := make([]domain.Province, 0, len(ids))
+	if len(ids) == 0 {
+		return provinces
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := domain.Province{}
+		if err := rows.Scan(&p.ID); err != nil {
+			panic(err)
+		}
+		provinces = append(provinces, p)
+	}
+	return provinces
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/stretchr/testify/mock"
 )
@@ -13,11 +15,20 @@ func (r *Repository) Get(id int) *domain.PurchaseOrder {
 	args := r.Called(id)
 	return args.Get(0).(*domain.PurchaseOrder)
 }
+
+func (r *Repository) GetMany(ids []int) []domain.PurchaseOrder {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.PurchaseOrder)
+}
 func (r *Repository) Exists(orderNumber string) bool {
 	args := r.Called(orderNumber)
 	return args.Get(0).(bool)
 }
-func (r *Repository) Save(purchaseOrder domain.PurchaseOrder) int {
-	args := r.Called(purchaseOrder)
+func (r *Repository) Save(purchaseOrder domain.PurchaseOrder, productID int, minimumDueDate time.Time) int {
+	args := r.Called(purchaseOrder, productID, minimumDueDate)
 	return args.Get(0).(int)
 }
+func (r *Repository) Cancel(id int) bool {
+	args := r.Called(id)
+	return args.Bool(0)
+}
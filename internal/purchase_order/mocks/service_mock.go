@@ -13,3 +13,18 @@ func (s *Service) Create(po domain.PurchaseOrder) (*domain.PurchaseOrder, error)
 	args := s.Called(po)
 	return args.Get(0).(*domain.PurchaseOrder), args.Error(1)
 }
+
+func (s *Service) PickList(id int) (*domain.PickList, error) {
+	args := s.Called(id)
+	return args.Get(0).(*domain.PickList), args.Error(1)
+}
+
+func (s *Service) Cancel(id int) (*domain.PurchaseOrder, error) {
+	args := s.Called(id)
+	return args.Get(0).(*domain.PurchaseOrder), args.Error(1)
+}
+
+func (s *Service) Pick(id int, batchID int, employeeID int) (*domain.StockMovement, error) {
+	args := s.Called(id, batchID, employeeID)
+	return args.Get(0).(*domain.StockMovement), args.Error(1)
+}
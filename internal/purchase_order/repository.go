@@ -3,21 +3,32 @@ package purchase_order
 import (
 	"database/sql"
 	"errors"
+	"time"
 
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/allocation"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetQuery    = "SELECT id, order_number, order_date, tracking_code, buyer_id, carrier_id, product_record_id, order_status_id, warehouse_id FROM purchase_orders WHERE id=?"
-	ExistsQuery = "SELECT order_number FROM purchase_orders WHERE order_number=?"
-	InsertQuery = "INSERT INTO purchase_orders (order_number, order_date, tracking_code, buyer_id, carrier_id, product_record_id, order_status_id, warehouse_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	GetQuery     = "SELECT id, order_number, order_date, tracking_code, buyer_id, carrier_id, product_record_id, order_status_id, warehouse_id, quantity FROM purchase_orders WHERE id=?"
+	GetManyQuery = "SELECT id, order_number, order_date, tracking_code, buyer_id, carrier_id, product_record_id, order_status_id, warehouse_id, quantity FROM purchase_orders WHERE id IN (%s)"
+	ExistsQuery  = "SELECT order_number FROM purchase_orders WHERE order_number=?"
+	InsertQuery  = "INSERT INTO purchase_orders (order_number, order_date, tracking_code, buyer_id, carrier_id, product_record_id, order_status_id, warehouse_id, quantity) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	CancelQuery  = "UPDATE purchase_orders SET order_status_id = (SELECT id FROM order_status WHERE description = ?) WHERE id = ? AND order_status_id <> (SELECT id FROM order_status WHERE description = ?)"
 )
 
+// CancelledStatus is the description of the order status given to cancelled
+// purchase orders.
+const CancelledStatus = "Cancelled"
+
 type Repository interface {
 	Get(id int) *domain.PurchaseOrder
+	GetMany(ids []int) []domain.PurchaseOrder
 	Exists(orderNumber string) bool
-	Save(purchaseOrder domain.PurchaseOrder) int
+	Save(purchaseOrder domain.PurchaseOrder, productID int, minimumDueDate time.Time) int
+	Cancel(id int) bool
 }
 
 type repository struct {
@@ -35,7 +46,7 @@ func (r *repository) Get(id int) *domain.PurchaseOrder {
 	po := domain.PurchaseOrder{}
 	var orderDate string
 
-	err := row.Scan(&po.ID, &po.OrderNumber, &orderDate, &po.TrackingCode, &po.BuyerID, &po.CarrierID, &po.ProductRecordID, &po.OrderStatusID, &po.WarehouseID)
+	err := row.Scan(&po.ID, &po.OrderNumber, &orderDate, &po.TrackingCode, &po.BuyerID, &po.CarrierID, &po.ProductRecordID, &po.OrderStatusID, &po.WarehouseID, &po.Quantity)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil
@@ -48,19 +59,55 @@ func (r *repository) Get(id int) *domain.PurchaseOrder {
 	return &po
 }
 
+// GetMany returns the purchase orders found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.PurchaseOrder {
+	purchaseOrders := make([]domain.PurchaseOrder, 0, len(ids))
+	if len(ids) == 0 {
+		return purchaseOrders
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		po := domain.PurchaseOrder{}
+		var orderDate string
+		if err := rows.Scan(&po.ID, &po.OrderNumber, &orderDate, &po.TrackingCode, &po.BuyerID, &po.CarrierID, &po.ProductRecordID, &po.OrderStatusID, &po.WarehouseID, &po.Quantity); err != nil {
+			panic(err)
+		}
+		po.OrderDate = helpers.ToDateTime(orderDate)
+		purchaseOrders = append(purchaseOrders, po)
+	}
+	return purchaseOrders
+}
+
 func (r *repository) Exists(orderNumber string) bool {
 	row := r.db.QueryRow(ExistsQuery, orderNumber)
 	err := row.Scan(&orderNumber)
 	return err == nil
 }
 
-func (r *repository) Save(po domain.PurchaseOrder) int {
-	stmt, err := r.db.Prepare(InsertQuery)
+// Save stores the order, reserves the stock of the product for it from the
+// batches not due before minimumDueDate and records its PurchaseOrderCreated
+// event in the same transaction.
+func (r *repository) Save(po domain.PurchaseOrder, productID int, minimumDueDate time.Time) int {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(InsertQuery)
 	if err != nil {
 		panic(err)
 	}
+	defer stmt.Close()
 
-	res, err := stmt.Exec(po.OrderNumber, po.OrderDate, po.TrackingCode, po.BuyerID, po.CarrierID, po.ProductRecordID, po.OrderStatusID, po.WarehouseID)
+	res, err := stmt.Exec(po.OrderNumber, po.OrderDate, po.TrackingCode, po.BuyerID, po.CarrierID, po.ProductRecordID, po.OrderStatusID, po.WarehouseID, po.Quantity)
 	if err != nil {
 		panic(err)
 	}
@@ -70,5 +117,50 @@ func (r *repository) Save(po domain.PurchaseOrder) int {
 		panic(err)
 	}
 
-	return int(id)
+	po.ID = int(id)
+	allocation.Reserve(tx, po.ID, productID, po.WarehouseID, po.Quantity, minimumDueDate)
+	outbox.Record(tx, domain.EventPurchaseOrderCreated, po.ID, po)
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return po.ID
+}
+
+// Cancel moves the order to the cancelled status, releases its reservations
+// and records its PurchaseOrderStatusChanged event in the same transaction. It
+// returns false when the order was already cancelled.
+func (r *repository) Cancel(id int) bool {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(CancelQuery)
+	if err != nil {
+		panic(err)
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(CancelledStatus, id, CancelledStatus)
+	if err != nil {
+		panic(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	if affected == 0 {
+		return false
+	}
+
+	allocation.Release(tx, id)
+	outbox.Record(tx, domain.EventPurchaseOrderStatusChanged, id, domain.PurchaseOrderStatusChange{ID: id, Status: CancelledStatus})
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return true
 }
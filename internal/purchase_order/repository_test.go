@@ -7,8 +7,11 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/allocation"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/purchase_order"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,7 +26,9 @@ var (
 		ProductRecordID: 1,
 		OrderStatusID:   1,
 		WarehouseID:     1,
+		Quantity:        1,
 	}
+	minimumDueDate = time.Date(2023, 7, 13, 0, 0, 0, 0, time.UTC)
 )
 
 func TestRepositoryGet(t *testing.T) {
@@ -31,10 +36,10 @@ func TestRepositoryGet(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"id", "order_number", "order_date", "tracking_code", "buyer_id", "carrier_id", "product_record_id", "order_status_id", "warehouse_id"}
+		columns := []string{"id", "order_number", "order_date", "tracking_code", "buyer_id", "carrier_id", "product_record_id", "order_status_id", "warehouse_id", "quantity"}
 		rows := sqlmock.NewRows(columns)
 		purchaseOrderID := 1
-		rows.AddRow(purchaseOrderID, "order123", "2023-01-01 00:00:00", "tr123", 1, 1, 1, 1, 1)
+		rows.AddRow(purchaseOrderID, "order123", "2023-01-01 00:00:00", "tr123", 1, 1, 1, 1, 1, 1)
 
 		mock.ExpectQuery(regexp.QuoteMeta(purchase_order.GetQuery)).
 			WithArgs(purchaseOrderID).
@@ -133,7 +138,7 @@ func TestRepositoryExists(t *testing.T) {
 }
 
 func TestRepositorySave(t *testing.T) {
-	t.Run("Should insert the purchase order and return the purchase order id", func(t *testing.T) {
+	t.Run("Should insert the purchase order with its reservations and return the purchase order id", func(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
@@ -145,16 +150,28 @@ func TestRepositorySave(t *testing.T) {
 		lastInsertId := 1
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
 
+		mock.ExpectBegin()
 		mock.ExpectPrepare(regexp.QuoteMeta(purchase_order.InsertQuery))
 		mock.ExpectExec(regexp.QuoteMeta(purchase_order.InsertQuery)).
-			WithArgs(mockedPurchaseOrder.OrderNumber, mockedPurchaseOrder.OrderDate, mockedPurchaseOrder.TrackingCode, mockedPurchaseOrder.BuyerID, mockedPurchaseOrder.CarrierID, mockedPurchaseOrder.ProductRecordID, mockedPurchaseOrder.OrderStatusID, mockedPurchaseOrder.WarehouseID).
+			WithArgs(mockedPurchaseOrder.OrderNumber, mockedPurchaseOrder.OrderDate, mockedPurchaseOrder.TrackingCode, mockedPurchaseOrder.BuyerID, mockedPurchaseOrder.CarrierID, mockedPurchaseOrder.ProductRecordID, mockedPurchaseOrder.OrderStatusID, mockedPurchaseOrder.WarehouseID, mockedPurchaseOrder.Quantity).
 			WillReturnResult(sqlmock.NewResult(int64(lastInsertId), 1))
+		mock.ExpectQuery(regexp.QuoteMeta(allocation.CandidatesQuery)).
+			WithArgs(2, mockedPurchaseOrder.WarehouseID, "2023-07-13 00:00:00").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "available"}).AddRow(5, 10))
+		mock.ExpectExec(regexp.QuoteMeta(allocation.InsertReservationQuery)).
+			WithArgs(lastInsertId, 5, mockedPurchaseOrder.Quantity).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.InsertQuery)).
+			WithArgs(domain.EventPurchaseOrderCreated, lastInsertId, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
 		repository := purchase_order.NewRepository(db)
 
-		result := repository.Save(mockedPurchaseOrder)
+		result := repository.Save(mockedPurchaseOrder, 2, minimumDueDate)
 
 		assert.Equal(t, lastInsertId, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Should throw panic when expected prepare fails", func(t *testing.T) {
@@ -162,11 +179,12 @@ func TestRepositorySave(t *testing.T) {
 		defer db.Close()
 
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
+		mock.ExpectBegin()
 		mock.ExpectPrepare(regexp.QuoteMeta(purchase_order.InsertQuery)).WillReturnError(sql.ErrConnDone)
 
 		repository := purchase_order.NewRepository(db)
 
-		assert.Panics(t, func() { repository.Save(mockedPurchaseOrder) })
+		assert.Panics(t, func() { repository.Save(mockedPurchaseOrder, 2, minimumDueDate) })
 	})
 
 	t.Run("Should throw panic when expected exec fails", func(t *testing.T) {
@@ -175,14 +193,15 @@ func TestRepositorySave(t *testing.T) {
 
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
 
+		mock.ExpectBegin()
 		mock.ExpectPrepare(regexp.QuoteMeta(purchase_order.InsertQuery))
 		mock.ExpectExec(regexp.QuoteMeta(purchase_order.InsertQuery)).
-			WithArgs(mockedPurchaseOrder.OrderNumber, mockedPurchaseOrder.OrderDate, mockedPurchaseOrder.TrackingCode, mockedPurchaseOrder.BuyerID, mockedPurchaseOrder.CarrierID, mockedPurchaseOrder.ProductRecordID, mockedPurchaseOrder.OrderStatusID, mockedPurchaseOrder.WarehouseID).
+			WithArgs(mockedPurchaseOrder.OrderNumber, mockedPurchaseOrder.OrderDate, mockedPurchaseOrder.TrackingCode, mockedPurchaseOrder.BuyerID, mockedPurchaseOrder.CarrierID, mockedPurchaseOrder.ProductRecordID, mockedPurchaseOrder.OrderStatusID, mockedPurchaseOrder.WarehouseID, mockedPurchaseOrder.Quantity).
 			WillReturnError(sql.ErrConnDone)
 
 		repository := purchase_order.NewRepository(db)
 
-		assert.Panics(t, func() { repository.Save(mockedPurchaseOrder) })
+		assert.Panics(t, func() { repository.Save(mockedPurchaseOrder, 2, minimumDueDate) })
 	})
 
 	t.Run("Should throw panic when sql has error", func(t *testing.T) {
@@ -191,14 +210,107 @@ func TestRepositorySave(t *testing.T) {
 
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
 
+		mock.ExpectBegin()
 		mock.ExpectPrepare(regexp.QuoteMeta(purchase_order.InsertQuery))
 		mock.ExpectExec(regexp.QuoteMeta(purchase_order.InsertQuery)).
-			WithArgs(mockedPurchaseOrder.OrderNumber, mockedPurchaseOrder.OrderDate, mockedPurchaseOrder.TrackingCode, mockedPurchaseOrder.BuyerID, mockedPurchaseOrder.CarrierID, mockedPurchaseOrder.ProductRecordID, mockedPurchaseOrder.OrderStatusID, mockedPurchaseOrder.WarehouseID).
+			WithArgs(mockedPurchaseOrder.OrderNumber, mockedPurchaseOrder.OrderDate, mockedPurchaseOrder.TrackingCode, mockedPurchaseOrder.BuyerID, mockedPurchaseOrder.CarrierID, mockedPurchaseOrder.ProductRecordID, mockedPurchaseOrder.OrderStatusID, mockedPurchaseOrder.WarehouseID, mockedPurchaseOrder.Quantity).
 			WillReturnResult(sqlmock.NewErrorResult(sql.ErrConnDone))
 
 		repository := purchase_order.NewRepository(db)
 
-		assert.Panics(t, func() { repository.Save(mockedPurchaseOrder) })
+		assert.Panics(t, func() { repository.Save(mockedPurchaseOrder, 2, minimumDueDate) })
+	})
+}
+
+func TestRepositoryCancel(t *testing.T) {
+	t.Run("Should cancel the purchase order and release its reservations", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectPrepare(regexp.QuoteMeta(purchase_order.CancelQuery))
+		mock.ExpectExec(regexp.QuoteMeta(purchase_order.CancelQuery)).
+			WithArgs(purchase_order.CancelledStatus, 1, purchase_order.CancelledStatus).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(allocation.ReleaseQuery)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.InsertQuery)).
+			WithArgs(domain.EventPurchaseOrderStatusChanged, 1, []byte(`{"id":1,"status":"Cancelled"}`), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		repository := purchase_order.NewRepository(db)
+
+		assert.True(t, repository.Cancel(1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return false when the purchase order is already cancelled", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectPrepare(regexp.QuoteMeta(purchase_order.CancelQuery))
+		mock.ExpectExec(regexp.QuoteMeta(purchase_order.CancelQuery)).
+			WithArgs(purchase_order.CancelledStatus, 1, purchase_order.CancelledStatus).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := purchase_order.NewRepository(db)
+
+		assert.False(t, repository.Cancel(1))
+	})
+	t.Run("Should throw a panic when exec fails", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectPrepare(regexp.QuoteMeta(purchase_order.CancelQuery))
+		mock.ExpectExec(regexp.QuoteMeta(purchase_order.CancelQuery)).
+			WillReturnError(sql.ErrConnDone)
+
+		repository := purchase_order.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Cancel(1) })
+	})
+}
+
+func TestRepositoryGetMany(t *testing.T) {
+	t.Run("Should return the purchase orders found among the ids with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(purchase_order.GetManyQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "order_number", "order_date", "tracking_code", "buyer_id", "carrier_id", "product_record_id", "order_status_id", "warehouse_id", "quantity"}).
+			AddRow(2, "ORD-2", "2023-01-02 10:00:00", "TRK", 1, 1, 1, 1, 1, 1).
+			AddRow(5, "ORD-5", "2023-01-02 10:00:00", "TRK", 1, 1, 1, 1, 1, 1)
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := purchase_order.NewRepository(db)
+		result := repository.GetMany([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := purchase_order.NewRepository(db)
+		result := repository.GetMany(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(purchase_order.GetManyQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := purchase_order.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetMany([]int{2}) })
 	})
 }
 
@@ -1,26 +1,40 @@
 package purchase_order
 
 import (
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/allocation"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/buyer"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/carrier"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/order_status"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_record"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	BuyerNotFound         = "comprador não encontrado com o id %d"
-	OrderStatusNotFound   = "status da ordem não encontrado com o id %d"
-	WarehouseNotFound     = "armazém não encontrado com o id %d"
-	CarrierNotFound       = "transportadora não encontrada com o id %d"
-	ProductRecordNotFound = "registro de produto não encontrado com o id %d"
-	ResourceAlreadyExists = "uma ordem de compra com o número '%s' já existe"
+	BuyerNotFound         = "buyer.not_found"
+	OrderStatusNotFound   = "order_status.not_found"
+	WarehouseNotFound     = "warehouse.not_found"
+	CarrierNotFound       = "carrier.not_found"
+	ProductRecordNotFound = "product_record.not_found"
+	ResourceAlreadyExists = "purchase_order.already_exists"
+	ResourceNotFound      = "purchase_order.not_found"
+	AlreadyCancelled      = "purchase_order.already_cancelled"
+	CreatedCancelled      = "purchase_order.created_cancelled"
+	EmployeeNotFound      = "employee.not_found"
+	NotReserved           = "purchase_order.batch_not_reserved"
+	BatchUnavailable      = "purchase_order.batch_unavailable"
 )
 
 type Service interface {
 	Create(locality domain.PurchaseOrder) (*domain.PurchaseOrder, error)
+	PickList(id int) (*domain.PickList, error)
+	Cancel(id int) (*domain.PurchaseOrder, error)
+	Pick(id int, batchID int, employeeID int) (*domain.StockMovement, error)
 }
 
 type service struct {
@@ -30,43 +44,97 @@ type service struct {
 	warehouseRepository     warehouse.Repository
 	carrierRepository       carrier.Repository
 	productRecordRepository product_record.Repository
+	allocationService       allocation.Service
+	employeeRepository      employee.Repository
 }
 
-func NewService(repository Repository, buyerRepository buyer.Repository, orderStatusrepository order_status.Repository, warehouseRepository warehouse.Repository, carrierRepository carrier.Repository, productRecordRepository product_record.Repository) Service {
+func NewService(repository Repository, buyerRepository buyer.Repository, orderStatusrepository order_status.Repository, warehouseRepository warehouse.Repository, carrierRepository carrier.Repository, productRecordRepository product_record.Repository, allocationService allocation.Service, employeeRepository employee.Repository) Service {
 
-	return &service{repository, buyerRepository, orderStatusrepository, warehouseRepository, carrierRepository, productRecordRepository}
+	return &service{repository, buyerRepository, orderStatusrepository, warehouseRepository, carrierRepository, productRecordRepository, allocationService, employeeRepository}
 }
 
+// Create stores the order after checking its references, each looked up
+// through the GetMany of its table.
 func (s *service) Create(po domain.PurchaseOrder) (*domain.PurchaseOrder, error) {
 	if s.repository.Exists(po.OrderNumber) {
 		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, po.OrderNumber)
 	}
 
-	buyerFound := s.buyerRepository.Get(po.BuyerID)
+	buyerFound := helpers.First(s.buyerRepository.GetMany([]int{po.BuyerID}))
 	if buyerFound == nil {
 		return nil, apperr.NewDependentResourceNotFound(BuyerNotFound, po.BuyerID)
 	}
 
-	orderStatusFound := s.orderStatusrepository.Get(po.OrderStatusID)
+	orderStatusFound := helpers.First(s.orderStatusrepository.GetMany([]int{po.OrderStatusID}))
 	if orderStatusFound == nil {
 		return nil, apperr.NewDependentResourceNotFound(OrderStatusNotFound, po.OrderStatusID)
 	}
 
-	warehouseFound := s.warehouseRepository.Get(po.WarehouseID)
+	// A cancelled order would reserve stock that nothing releases.
+	if orderStatusFound.Description == CancelledStatus {
+		return nil, apperr.NewIncompatibleResource(CreatedCancelled, po.OrderNumber)
+	}
+
+	warehouseFound := helpers.First(s.warehouseRepository.GetMany([]int{po.WarehouseID}))
 	if warehouseFound == nil {
 		return nil, apperr.NewDependentResourceNotFound(WarehouseNotFound, po.WarehouseID)
 	}
 
-	productRecordFound := s.productRecordRepository.Get(po.ProductRecordID)
+	productRecordFound := helpers.First(s.productRecordRepository.GetMany([]int{po.ProductRecordID}))
 	if productRecordFound == nil {
 		return nil, apperr.NewDependentResourceNotFound(ProductRecordNotFound, po.ProductRecordID)
 	}
 
-	carrierFound := s.carrierRepository.Get(po.CarrierID)
+	carrierFound := helpers.First(s.carrierRepository.GetMany([]int{po.CarrierID}))
 	if carrierFound == nil {
 		return nil, apperr.NewDependentResourceNotFound(CarrierNotFound, po.CarrierID)
 	}
 
-	id := s.repository.Save(po)
+	id := s.repository.Save(po, productRecordFound.ProductID, s.allocationService.MinimumDueDate())
 	return s.repository.Get(id), nil
 }
+
+func (s *service) PickList(id int) (*domain.PickList, error) {
+	po := s.repository.Get(id)
+	if po == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	}
+
+	pickList := s.allocationService.PickList(*po)
+	return &pickList, nil
+}
+
+// Cancel cancels the order and releases the stock reserved for it.
+func (s *service) Cancel(id int) (*domain.PurchaseOrder, error) {
+	if s.repository.Get(id) == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	}
+
+	if !s.repository.Cancel(id) {
+		return nil, apperr.NewIncompatibleResource(AlreadyCancelled, id)
+	}
+
+	return s.repository.Get(id), nil
+}
+
+// Pick confirms the pick of the quantity of a batch reserved for the order,
+// taking it out of the batch.
+func (s *service) Pick(id int, batchID int, employeeID int) (*domain.StockMovement, error) {
+	if s.repository.Get(id) == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	}
+
+	if s.employeeRepository.Get(employeeID) == nil {
+		return nil, apperr.NewDependentResourceNotFound(EmployeeNotFound, employeeID)
+	}
+
+	movement, err := s.allocationService.Pick(id, batchID, employeeID)
+	if errors.Is(err, allocation.ErrNotReserved) {
+		return nil, apperr.NewIncompatibleResource(NotReserved, batchID, id)
+	}
+	if errors.Is(err, allocation.ErrBatchUnavailable) {
+		return nil, apperr.NewIncompatibleResource(BatchUnavailable, batchID)
+	}
+
+	return movement, nil
+}
@@ -2,10 +2,14 @@ package purchase_order_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/allocation"
+	allocationMocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/allocation/mocks"
 	buyerMocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/buyer/mocks"
 	carrierMocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/carrier/mocks"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	employeeMocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/employee/mocks"
 	orderStatusMocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/order_status/mocks"
 	productRecordMocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_record/mocks"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/purchase_order"
@@ -13,6 +17,7 @@ import (
 	warehouseMocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse/mocks"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var (
@@ -35,7 +40,7 @@ var (
 
 func TestServiceCreate(t *testing.T) {
 	t.Run("Should return a created purchase order", func(t *testing.T) {
-		service, repository, buyerRepo, orderStatusRepo, warehouseRepo, carrierRepo, productRecordRepo := CreateService(t)
+		service, repository, buyerRepo, orderStatusRepo, warehouseRepo, carrierRepo, productRecordRepo, allocationService, _ := CreateService(t)
 
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
 		mockedBuyer := mockedBuyerTemplate
@@ -47,12 +52,14 @@ func TestServiceCreate(t *testing.T) {
 		purchaseOrderID := 1
 
 		repository.On("Exists", mockedPurchaseOrder.OrderNumber).Return(false)
-		buyerRepo.On("Get", mockedBuyer.ID).Return(&mockedBuyer)
-		orderStatusRepo.On("Get", mockedOrderStatus.ID).Return(&mockedOrderStatus)
-		warehouseRepo.On("Get", mockedWarehouse.ID).Return(&mockedWarehouse)
-		productRecordRepo.On("Get", mockedProductRecord.ID).Return(&mockedProductRecord)
-		carrierRepo.On("Get", mockedCarrier.ID).Return(&mockedCarrier)
-		repository.On("Save", mockedPurchaseOrder).Return(purchaseOrderID)
+		buyerRepo.On("GetMany", []int{mockedBuyer.ID}).Return([]domain.Buyer{mockedBuyer})
+		orderStatusRepo.On("GetMany", []int{mockedOrderStatus.ID}).Return([]domain.OrderStatus{mockedOrderStatus})
+		warehouseRepo.On("GetMany", []int{mockedWarehouse.ID}).Return([]domain.Warehouse{mockedWarehouse})
+		productRecordRepo.On("GetMany", []int{mockedProductRecord.ID}).Return([]domain.ProductRecord{mockedProductRecord})
+		carrierRepo.On("GetMany", []int{mockedCarrier.ID}).Return([]domain.Carrier{mockedCarrier})
+		minimumDueDate := time.Date(2023, 7, 13, 0, 0, 0, 0, time.UTC)
+		allocationService.On("MinimumDueDate").Return(minimumDueDate)
+		repository.On("Save", mockedPurchaseOrder, mockedProductRecord.ProductID, minimumDueDate).Return(purchaseOrderID)
 		repository.On("Get", purchaseOrderID).Return(&mockedPurchaseOrder)
 
 		result, err := service.Create(mockedPurchaseOrder)
@@ -60,10 +67,11 @@ func TestServiceCreate(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, mockedPurchaseOrder, *result)
+		repository.AssertExpectations(t)
 	})
 
 	t.Run("Should return a conflict error when order number already exists", func(t *testing.T) {
-		service, repository, _, _, _, _, _ := CreateService(t)
+		service, repository, _, _, _, _, _, _, _ := CreateService(t)
 
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
 		repository.On("Exists", mockedPurchaseOrder.OrderNumber).Return(true)
@@ -75,15 +83,32 @@ func TestServiceCreate(t *testing.T) {
 		assert.True(t, apperr.Is[*apperr.ResourceAlreadyExists](err))
 	})
 
+	t.Run("Should return incompatible resource error when the order is created cancelled", func(t *testing.T) {
+		service, repository, buyerRepo, orderStatusRepo, _, _, _, _, _ := CreateService(t)
+
+		mockedPurchaseOrder := mockedPurchaseOrderTemplate
+		mockedBuyer := mockedBuyerTemplate
+		cancelled := domain.OrderStatus{ID: mockedPurchaseOrder.OrderStatusID, Description: purchase_order.CancelledStatus}
+
+		repository.On("Exists", mockedPurchaseOrder.OrderNumber).Return(false)
+		buyerRepo.On("GetMany", []int{mockedBuyer.ID}).Return([]domain.Buyer{mockedBuyer})
+		orderStatusRepo.On("GetMany", []int{cancelled.ID}).Return([]domain.OrderStatus{cancelled})
+
+		result, err := service.Create(mockedPurchaseOrder)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 	t.Run("Should return a conflict error when buyer id does not exist", func(t *testing.T) {
-		service, repository, buyerRepo, _, _, _, _ := CreateService(t)
+		service, repository, buyerRepo, _, _, _, _, _, _ := CreateService(t)
 
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
 		mockedBuyer := mockedBuyerTemplate
-		var buyerRepositoryGetResult *domain.Buyer
 
 		repository.On("Exists", mockedPurchaseOrder.OrderNumber).Return(false)
-		buyerRepo.On("Get", mockedBuyer.ID).Return(buyerRepositoryGetResult)
+		buyerRepo.On("GetMany", []int{mockedBuyer.ID}).Return([]domain.Buyer{})
 
 		result, err := service.Create(mockedPurchaseOrder)
 
@@ -93,16 +118,15 @@ func TestServiceCreate(t *testing.T) {
 	})
 
 	t.Run("Should return a conflict error when order status id does not exist", func(t *testing.T) {
-		service, repository, buyerRepo, orderStatusRepo, _, _, _ := CreateService(t)
+		service, repository, buyerRepo, orderStatusRepo, _, _, _, _, _ := CreateService(t)
 
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
 		mockedBuyer := mockedBuyerTemplate
 		mockedOrderStatus := mockedOrderStatusTemplate
-		var orderStatusRepositoryGetResult *domain.OrderStatus
 
 		repository.On("Exists", mockedPurchaseOrder.OrderNumber).Return(false)
-		buyerRepo.On("Get", mockedBuyer.ID).Return(&mockedBuyer)
-		orderStatusRepo.On("Get", mockedOrderStatus.ID).Return(orderStatusRepositoryGetResult)
+		buyerRepo.On("GetMany", []int{mockedBuyer.ID}).Return([]domain.Buyer{mockedBuyer})
+		orderStatusRepo.On("GetMany", []int{mockedOrderStatus.ID}).Return([]domain.OrderStatus{})
 
 		result, err := service.Create(mockedPurchaseOrder)
 
@@ -112,18 +136,17 @@ func TestServiceCreate(t *testing.T) {
 	})
 
 	t.Run("Should return a conflict error when warehouse id does not exist", func(t *testing.T) {
-		service, repository, buyerRepo, orderStatusRepo, warehouseRepo, _, _ := CreateService(t)
+		service, repository, buyerRepo, orderStatusRepo, warehouseRepo, _, _, _, _ := CreateService(t)
 
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
 		mockedBuyer := mockedBuyerTemplate
 		mockedOrderStatus := mockedOrderStatusTemplate
 		mockedWarehouse := mockedWarehouseTemplate
-		var warehouseRepositoryGetResult *domain.Warehouse
 
 		repository.On("Exists", mockedPurchaseOrder.OrderNumber).Return(false)
-		buyerRepo.On("Get", mockedBuyer.ID).Return(&mockedBuyer)
-		orderStatusRepo.On("Get", mockedOrderStatus.ID).Return(&mockedOrderStatus)
-		warehouseRepo.On("Get", mockedWarehouse.ID).Return(warehouseRepositoryGetResult)
+		buyerRepo.On("GetMany", []int{mockedBuyer.ID}).Return([]domain.Buyer{mockedBuyer})
+		orderStatusRepo.On("GetMany", []int{mockedOrderStatus.ID}).Return([]domain.OrderStatus{mockedOrderStatus})
+		warehouseRepo.On("GetMany", []int{mockedWarehouse.ID}).Return([]domain.Warehouse{})
 
 		result, err := service.Create(mockedPurchaseOrder)
 
@@ -132,20 +155,19 @@ func TestServiceCreate(t *testing.T) {
 		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
 	})
 	t.Run("Should return a conflict error when product record id does not exist", func(t *testing.T) {
-		service, repository, buyerRepo, orderStatusRepo, warehouseRepo, _, productRecordRepo := CreateService(t)
+		service, repository, buyerRepo, orderStatusRepo, warehouseRepo, _, productRecordRepo, _, _ := CreateService(t)
 
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
 		mockedBuyer := mockedBuyerTemplate
 		mockedOrderStatus := mockedOrderStatusTemplate
 		mockedWarehouse := mockedWarehouseTemplate
 		mockedProductRecord := mockedProductRecordTemplate
-		var productRecordRepositoryGetResult *domain.ProductRecord
 
 		repository.On("Exists", mockedPurchaseOrder.OrderNumber).Return(false)
-		buyerRepo.On("Get", mockedBuyer.ID).Return(&mockedBuyer)
-		orderStatusRepo.On("Get", mockedOrderStatus.ID).Return(&mockedOrderStatus)
-		warehouseRepo.On("Get", mockedWarehouse.ID).Return(&mockedWarehouse)
-		productRecordRepo.On("Get", mockedProductRecord.ID).Return(productRecordRepositoryGetResult)
+		buyerRepo.On("GetMany", []int{mockedBuyer.ID}).Return([]domain.Buyer{mockedBuyer})
+		orderStatusRepo.On("GetMany", []int{mockedOrderStatus.ID}).Return([]domain.OrderStatus{mockedOrderStatus})
+		warehouseRepo.On("GetMany", []int{mockedWarehouse.ID}).Return([]domain.Warehouse{mockedWarehouse})
+		productRecordRepo.On("GetMany", []int{mockedProductRecord.ID}).Return([]domain.ProductRecord{})
 
 		result, err := service.Create(mockedPurchaseOrder)
 
@@ -155,7 +177,7 @@ func TestServiceCreate(t *testing.T) {
 	})
 
 	t.Run("Should return a conflict error when carrier id does not exist", func(t *testing.T) {
-		service, repository, buyerRepo, orderStatusRepo, warehouseRepo, carrierRepo, productRecordRepo := CreateService(t)
+		service, repository, buyerRepo, orderStatusRepo, warehouseRepo, carrierRepo, productRecordRepo, _, _ := CreateService(t)
 
 		mockedPurchaseOrder := mockedPurchaseOrderTemplate
 		mockedBuyer := mockedBuyerTemplate
@@ -163,14 +185,13 @@ func TestServiceCreate(t *testing.T) {
 		mockedWarehouse := mockedWarehouseTemplate
 		mockedProductRecord := mockedProductRecordTemplate
 		mockedCarrier := mockedCarrierTemplate
-		var carrierRepositoryGetResult *domain.Carrier
 
 		repository.On("Exists", mockedPurchaseOrder.OrderNumber).Return(false)
-		buyerRepo.On("Get", mockedBuyer.ID).Return(&mockedBuyer)
-		orderStatusRepo.On("Get", mockedOrderStatus.ID).Return(&mockedOrderStatus)
-		warehouseRepo.On("Get", mockedWarehouse.ID).Return(&mockedWarehouse)
-		productRecordRepo.On("Get", mockedProductRecord.ID).Return(&mockedProductRecord)
-		carrierRepo.On("Get", mockedCarrier.ID).Return(carrierRepositoryGetResult)
+		buyerRepo.On("GetMany", []int{mockedBuyer.ID}).Return([]domain.Buyer{mockedBuyer})
+		orderStatusRepo.On("GetMany", []int{mockedOrderStatus.ID}).Return([]domain.OrderStatus{mockedOrderStatus})
+		warehouseRepo.On("GetMany", []int{mockedWarehouse.ID}).Return([]domain.Warehouse{mockedWarehouse})
+		productRecordRepo.On("GetMany", []int{mockedProductRecord.ID}).Return([]domain.ProductRecord{mockedProductRecord})
+		carrierRepo.On("GetMany", []int{mockedCarrier.ID}).Return([]domain.Carrier{})
 
 		result, err := service.Create(mockedPurchaseOrder)
 
@@ -180,7 +201,143 @@ func TestServiceCreate(t *testing.T) {
 	})
 }
 
-func CreateService(t *testing.T) (purchase_order.Service, *mocks.Repository, *buyerMocks.Repository, *orderStatusMocks.Repository, *warehouseMocks.Repository, *carrierMocks.Repository, *productRecordMocks.Repository) {
+func TestServicePickList(t *testing.T) {
+	t.Run("Should return the pick list of the order", func(t *testing.T) {
+		service, repository, _, _, _, _, _, allocationService, _ := CreateService(t)
+
+		mockedPurchaseOrder := mockedPurchaseOrderTemplate
+		pickList := domain.PickList{PurchaseOrderID: mockedPurchaseOrder.ID, Requested: 5, Reserved: 5, Lines: []domain.PickListLine{{ProductBatchID: 1, Quantity: 5}}}
+		repository.On("Get", mockedPurchaseOrder.ID).Return(&mockedPurchaseOrder)
+		allocationService.On("PickList", mockedPurchaseOrder).Return(pickList)
+
+		result, err := service.PickList(mockedPurchaseOrder.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, pickList, *result)
+	})
+	t.Run("Should return not found error when the order does not exist", func(t *testing.T) {
+		service, repository, _, _, _, _, _, _, _ := CreateService(t)
+
+		var purchaseOrderFound *domain.PurchaseOrder
+		repository.On("Get", 1).Return(purchaseOrderFound)
+
+		result, err := service.PickList(1)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func TestServiceCancel(t *testing.T) {
+	t.Run("Should cancel the order and release its reservations", func(t *testing.T) {
+		service, repository, _, _, _, _, _, _, _ := CreateService(t)
+
+		mockedPurchaseOrder := mockedPurchaseOrderTemplate
+		repository.On("Get", mockedPurchaseOrder.ID).Return(&mockedPurchaseOrder)
+		repository.On("Cancel", mockedPurchaseOrder.ID).Return(true)
+
+		result, err := service.Cancel(mockedPurchaseOrder.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockedPurchaseOrder, *result)
+		repository.AssertExpectations(t)
+	})
+	t.Run("Should return a conflict error when the order is already cancelled", func(t *testing.T) {
+		service, repository, _, _, _, _, _, _, _ := CreateService(t)
+
+		mockedPurchaseOrder := mockedPurchaseOrderTemplate
+		repository.On("Get", mockedPurchaseOrder.ID).Return(&mockedPurchaseOrder)
+		repository.On("Cancel", mockedPurchaseOrder.ID).Return(false)
+
+		result, err := service.Cancel(mockedPurchaseOrder.ID)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return not found error when the order does not exist", func(t *testing.T) {
+		service, repository, _, _, _, _, _, _, _ := CreateService(t)
+
+		var purchaseOrderFound *domain.PurchaseOrder
+		repository.On("Get", 1).Return(purchaseOrderFound)
+
+		result, err := service.Cancel(1)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func TestServicePick(t *testing.T) {
+	t.Run("Should pick the reserved quantity of the batch", func(t *testing.T) {
+		service, repository, _, _, _, _, _, allocationService, employeeRepo := CreateService(t)
+
+		mockedPurchaseOrder := mockedPurchaseOrderTemplate
+		movement := &domain.StockMovement{ID: 1, ProductBatchID: 3, Type: domain.MovementPick, Quantity: 1}
+		repository.On("Get", mockedPurchaseOrder.ID).Return(&mockedPurchaseOrder)
+		employeeRepo.On("Get", 4).Return(&domain.Employee{ID: 4})
+		allocationService.On("Pick", mockedPurchaseOrder.ID, 3, 4).Return(movement, nil)
+
+		result, err := service.Pick(mockedPurchaseOrder.ID, 3, 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, movement, result)
+	})
+	t.Run("Should return not found error when the order does not exist", func(t *testing.T) {
+		service, repository, _, _, _, _, _, _, _ := CreateService(t)
+
+		var purchaseOrderFound *domain.PurchaseOrder
+		repository.On("Get", 1).Return(purchaseOrderFound)
+
+		result, err := service.Pick(1, 3, 4)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+	t.Run("Should return dependent not found error when the employee does not exist", func(t *testing.T) {
+		service, repository, _, _, _, _, _, allocationService, employeeRepo := CreateService(t)
+
+		mockedPurchaseOrder := mockedPurchaseOrderTemplate
+		var employeeFound *domain.Employee
+		repository.On("Get", mockedPurchaseOrder.ID).Return(&mockedPurchaseOrder)
+		employeeRepo.On("Get", 4).Return(employeeFound)
+
+		result, err := service.Pick(mockedPurchaseOrder.ID, 3, 4)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
+		allocationService.AssertNotCalled(t, "Pick", mockedPurchaseOrder.ID, 3, 4)
+	})
+	t.Run("Should return conflict error when the batch is not reserved for the order", func(t *testing.T) {
+		service, repository, _, _, _, _, _, allocationService, employeeRepo := CreateService(t)
+
+		mockedPurchaseOrder := mockedPurchaseOrderTemplate
+		var movement *domain.StockMovement
+		repository.On("Get", mockedPurchaseOrder.ID).Return(&mockedPurchaseOrder)
+		employeeRepo.On("Get", 4).Return(&domain.Employee{ID: 4})
+		allocationService.On("Pick", mockedPurchaseOrder.ID, 3, 4).Return(movement, allocation.ErrNotReserved)
+
+		result, err := service.Pick(mockedPurchaseOrder.ID, 3, 4)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+	t.Run("Should return conflict error when the batch is quarantined", func(t *testing.T) {
+		service, repository, _, _, _, _, _, allocationService, employeeRepo := CreateService(t)
+
+		mockedPurchaseOrder := mockedPurchaseOrderTemplate
+		var movement *domain.StockMovement
+		repository.On("Get", mockedPurchaseOrder.ID).Return(&mockedPurchaseOrder)
+		employeeRepo.On("Get", 4).Return(&domain.Employee{ID: 4})
+		allocationService.On("Pick", mockedPurchaseOrder.ID, 3, 4).Return(movement, allocation.ErrBatchUnavailable)
+
+		result, err := service.Pick(mockedPurchaseOrder.ID, 3, 4)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+}
+
+func CreateService(t *testing.T) (purchase_order.Service, *mocks.Repository, *buyerMocks.Repository, *orderStatusMocks.Repository, *warehouseMocks.Repository, *carrierMocks.Repository, *productRecordMocks.Repository, *allocationMocks.Service, *employeeMocks.Repository) {
 	t.Helper()
 	repository := new(mocks.Repository)
 	buyerRepo := new(buyerMocks.Repository)
@@ -188,7 +345,9 @@ func CreateService(t *testing.T) (purchase_order.Service, *mocks.Repository, *bu
 	warehouseRepo := new(warehouseMocks.Repository)
 	carrierRepo := new(carrierMocks.Repository)
 	productRecordRepo := new(productRecordMocks.Repository)
-	service := purchase_order.NewService(repository, buyerRepo, orderStatusRepo, warehouseRepo, carrierRepo, productRecordRepo)
+	allocationService := new(allocationMocks.Service)
+	employeeRepo := new(employeeMocks.Repository)
+	service := purchase_order.NewService(repository, buyerRepo, orderStatusRepo, warehouseRepo, carrierRepo, productRecordRepo, allocationService, employeeRepo)
 
-	return service, repository, buyerRepo, orderStatusRepo, warehouseRepo, carrierRepo, productRecordRepo
+	return service, repository, buyerRepo, orderStatusRepo, warehouseRepo, carrierRepo, productRecordRepo, allocationService, employeeRepo
 }
@@ -19,6 +19,16 @@ func (r *Repository) Get(id int) *domain.Section {
 	return args.Get(0).(*domain.Section)
 }
 
+func (r *Repository) GetMany(ids []int) []domain.Section {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.Section)
+}
+
+func (r *Repository) GetByWarehouse(warehouseID int) []domain.Section {
+	args := r.Called(warehouseID)
+	return args.Get(0).([]domain.Section)
+}
+
 func (r *Repository) Exists(sectionNumber int) bool {
 	args := r.Called(sectionNumber)
 	return args.Get(0).(bool)
@@ -29,18 +39,39 @@ func (r *Repository) Save(section domain.Section) int {
 	return args.Get(0).(int)
 }
 
-func (r *Repository) Update(section domain.Section) {
-	r.Called(section)
+func (r *Repository) Update(section domain.Section) (int, error) {
+	args := r.Called(section)
+	return args.Int(0), args.Error(1)
 }
 
 func (r *Repository) Delete(id int) {
 	r.Called(id)
 }
-func (r *Repository) CountProductsByAllSections() []domain.ProductsBySectionReport {
-	args := r.Called()
+func (r *Repository) CountProductsByAllSections(dates domain.DateRange) []domain.ProductsBySectionReport {
+	args := r.Called(dates)
 	return args.Get(0).([]domain.ProductsBySectionReport)
 }
-func (r *Repository) CountProductsBySection(id int) *domain.ProductsBySectionReport {
-	args := r.Called(id)
+func (r *Repository) StreamProductsByAllSections(dates domain.DateRange, yield func(domain.ProductsBySectionReport)) {
+	args := r.Called(dates)
+	for _, report := range args.Get(0).([]domain.ProductsBySectionReport) {
+		yield(report)
+	}
+}
+func (r *Repository) CountProductsBySection(id int, dates domain.DateRange) *domain.ProductsBySectionReport {
+	args := r.Called(id, dates)
 	return args.Get(0).(*domain.ProductsBySectionReport)
 }
+
+func (r *Repository) CountProductsByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod {
+	args := r.Called(id, period, dates)
+	return args.Get(0).([]domain.ReportPeriod)
+}
+func (r *Repository) Occupancy(id int) *domain.SectionOccupancy {
+	args := r.Called(id)
+	return args.Get(0).(*domain.SectionOccupancy)
+}
+
+func (r *Repository) GetByWarehouses(warehouseIDs []int) []domain.Section {
+	args := r.Called(warehouseIDs)
+	return args.Get(0).([]domain.Section)
+}
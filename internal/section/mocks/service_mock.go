@@ -14,6 +14,11 @@ func (s *Service) GetAll() []domain.Section {
 	return args.Get(0).([]domain.Section)
 }
 
+func (s *Service) GetMany(ids []int) []domain.Section {
+	args := s.Called(ids)
+	return args.Get(0).([]domain.Section)
+}
+
 func (s *Service) Get(id int) (*domain.Section, error) {
 	args := s.Called(id)
 	return args.Get(0).(*domain.Section), args.Error(1)
@@ -24,8 +29,8 @@ func (s *Service) Create(sc domain.Section) (*domain.Section, error) {
 	return args.Get(0).(*domain.Section), args.Error(1)
 }
 
-func (s *Service) Update(id int, sc domain.UpdateSection) (*domain.Section, error) {
-	args := s.Called(id, sc)
+func (s *Service) Update(current domain.Section, sc domain.Section) (*domain.Section, error) {
+	args := s.Called(current, sc)
 	return args.Get(0).(*domain.Section), args.Error(1)
 }
 
@@ -33,11 +38,31 @@ func (s *Service) Delete(id int) error {
 	args := s.Called(id)
 	return args.Error(0)
 }
-func (s *Service) CountProductsByAllSections() []domain.ProductsBySectionReport {
-	args := s.Called()
+func (s *Service) CountProductsByAllSections(dates domain.DateRange, top int) []domain.ProductsBySectionReport {
+	args := s.Called(dates, top)
 	return args.Get(0).([]domain.ProductsBySectionReport)
 }
-func (s *Service) CountProductsBySection(id int) (*domain.ProductsBySectionReport, error) {
-	args := s.Called(id)
+func (s *Service) StreamProductsByAllSections(dates domain.DateRange, yield func(domain.ProductsBySectionReport)) {
+	args := s.Called(dates)
+	for _, report := range args.Get(0).([]domain.ProductsBySectionReport) {
+		yield(report)
+	}
+}
+func (s *Service) CountProductsBySection(id int, dates domain.DateRange) (*domain.ProductsBySectionReport, error) {
+	args := s.Called(id, dates)
 	return args.Get(0).(*domain.ProductsBySectionReport), args.Error(1)
 }
+
+func (s *Service) CountProductsByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error) {
+	args := s.Called(id, period, dates)
+	return args.Get(0).([]domain.ReportPeriod), args.Error(1)
+}
+func (s *Service) Occupancy(id int) (*domain.SectionOccupancy, error) {
+	args := s.Called(id)
+	return args.Get(0).(*domain.SectionOccupancy), args.Error(1)
+}
+
+func (s *Service) GetByWarehouses(warehouseIDs []int) []domain.Section {
+	args := s.Called(warehouseIDs)
+	return args.Get(0).([]domain.Section)
+}
@@ -5,30 +5,52 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetAllQuery                     = "SELECT * FROM sections;"
-	GetQuery                        = "SELECT * FROM sections WHERE id=?;"
+	GetAllQuery                     = "SELECT id, section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, id_product_type FROM sections;"
+	GetQuery                        = "SELECT id, section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, id_product_type, version FROM sections WHERE id=?;"
+	GetManyQuery                    = "SELECT id, section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, id_product_type FROM sections WHERE id IN (%s);"
+	GetByWarehouseQuery             = "SELECT id, section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, id_product_type FROM sections WHERE warehouse_id=?;"
+	GetByWarehousesQuery            = "SELECT id, section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, id_product_type FROM sections WHERE warehouse_id IN (%s);"
 	ExistsQuery                     = "SELECT section_number FROM sections WHERE section_number=?;"
 	InsertQuery                     = "INSERT INTO sections(section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, id_product_type) VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
-	UpdateQuery                     = "UPDATE sections SET section_number=?, current_temperature=?, minimum_temperature=?, current_capacity=?, minimum_capacity=?, maximum_capacity=?, warehouse_id=?, id_product_type=? WHERE id=?;"
+	UpdateQuery                     = "UPDATE sections SET section_number=?, current_temperature=?, minimum_temperature=?, minimum_capacity=?, maximum_capacity=?, warehouse_id=?, id_product_type=?, version=version+1 WHERE id=?;"
+	LockQuery                       = "SELECT id FROM sections WHERE id=? AND version=? FOR UPDATE;"
+	UsageQuery                      = "SELECT COALESCE(SUM(current_quantity), 0) FROM product_batches WHERE section_id=?;"
 	DeleteQuery                     = "DELETE FROM sections WHERE id=?"
-	CountProductsByAllSectionsQuery = `SELECT s.id "section_id", s.section_number, COUNT(pb.product_id) "product_count" FROM sections s LEFT JOIN product_batches pb ON s.id = pb.section_id GROUP BY s.id`
-	CountProductsBySectionQuery     = `SELECT s.id "section_id", s.section_number, COUNT(pb.product_id) "product_count" FROM sections s LEFT JOIN product_batches pb ON s.id = pb.section_id WHERE s.id=? GROUP BY s.id`
+	CountProductsByAllSectionsQuery = `SELECT s.id "section_id", s.section_number, COUNT(DISTINCT COALESCE(pb.origin_batch_id, pb.id)) "product_count" FROM sections s LEFT JOIN product_batches pb ON s.id = pb.section_id AND pb.status <> 'disposed' AND (? IS NULL OR pb.manufacturing_date >= ?) AND (? IS NULL OR pb.manufacturing_date < ?) GROUP BY s.id`
+	CountProductsBySectionQuery     = `SELECT s.id "section_id", s.section_number, COUNT(DISTINCT COALESCE(pb.origin_batch_id, pb.id)) "product_count" FROM sections s LEFT JOIN product_batches pb ON s.id = pb.section_id AND pb.status <> 'disposed' AND (? IS NULL OR pb.manufacturing_date >= ?) AND (? IS NULL OR pb.manufacturing_date < ?) WHERE s.id=? GROUP BY s.id`
+	CountProductsByPeriodQuery      = `SELECT CASE ? WHEN 'month' THEN DATE_FORMAT(pb.manufacturing_date, '%Y-%m-01 00:00:00') WHEN 'week' THEN DATE_FORMAT(pb.manufacturing_date - INTERVAL WEEKDAY(pb.manufacturing_date) DAY, '%Y-%m-%d 00:00:00') ELSE DATE_FORMAT(pb.manufacturing_date, '%Y-%m-%d 00:00:00') END "period", COUNT(DISTINCT COALESCE(pb.origin_batch_id, pb.id)) "product_count" FROM product_batches pb WHERE pb.status <> 'disposed' AND (? IS NULL OR pb.section_id = ?) AND (? IS NULL OR pb.manufacturing_date >= ?) AND (? IS NULL OR pb.manufacturing_date < ?) GROUP BY 1 ORDER BY 1`
+	OccupancyQuery                  = `SELECT s.id, s.maximum_capacity, COALESCE(SUM(pb.current_quantity), 0) "used" FROM sections s LEFT JOIN product_batches pb ON s.id = pb.section_id WHERE s.id=? GROUP BY s.id`
 )
 
 type Repository interface {
 	GetAll() []domain.Section
 	Get(id int) *domain.Section
+	GetMany(ids []int) []domain.Section
+	GetByWarehouse(warehouseID int) []domain.Section
+	GetByWarehouses(warehouseIDs []int) []domain.Section
 	Exists(sectionNumber int) bool
 	Save(sc domain.Section) int
-	Update(s domain.Section)
+	Update(s domain.Section) (int, error)
 	Delete(id int)
-	CountProductsByAllSections() []domain.ProductsBySectionReport
-	CountProductsBySection(id int) *domain.ProductsBySectionReport
+	CountProductsByAllSections(dates domain.DateRange) []domain.ProductsBySectionReport
+	StreamProductsByAllSections(dates domain.DateRange, yield func(domain.ProductsBySectionReport))
+	CountProductsBySection(id int, dates domain.DateRange) *domain.ProductsBySectionReport
+	CountProductsByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod
+	Occupancy(id int) *domain.SectionOccupancy
 }
 
+// ErrCapacityBelowUsage is returned when the maximum capacity of an update is
+// below the quantity the batches of the section hold.
+var ErrCapacityBelowUsage = errors.New("section capacity below usage")
+
+// ErrModified is returned by Update when the section was changed or deleted
+// after the version being updated was read.
+var ErrModified = errors.New("section modified since read")
+
 type repository struct {
 	db *sql.DB
 }
@@ -57,7 +79,7 @@ func (r *repository) GetAll() []domain.Section {
 func (r *repository) Get(id int) *domain.Section {
 	row := r.db.QueryRow(GetQuery, id)
 	s := domain.Section{}
-	err := row.Scan(&s.ID, &s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID)
+	err := row.Scan(&s.ID, &s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID, &s.Version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil
@@ -67,6 +89,69 @@ func (r *repository) Get(id int) *domain.Section {
 	return &s
 }
 
+// GetMany returns the sections found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.Section {
+	sections := make([]domain.Section, 0, len(ids))
+	if len(ids) == 0 {
+		return sections
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s := domain.Section{}
+		if err := rows.Scan(&s.ID, &s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID); err != nil {
+			panic(err)
+		}
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+func (r *repository) GetByWarehouse(warehouseID int) []domain.Section {
+	rows, err := r.db.Query(GetByWarehouseQuery, warehouseID)
+	if err != nil {
+		panic(err)
+	}
+	sections := make([]domain.Section, 0)
+
+	for rows.Next() {
+		s := domain.Section{}
+		_ = rows.Scan(&s.ID, &s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID)
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+// GetByWarehouses returns the sections of every warehouse of warehouseIDs.
+func (r *repository) GetByWarehouses(warehouseIDs []int) []domain.Section {
+	sections := make([]domain.Section, 0, len(warehouseIDs))
+	if len(warehouseIDs) == 0 {
+		return sections
+	}
+
+	query, args := helpers.ExpandIn(GetByWarehousesQuery, warehouseIDs)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s := domain.Section{}
+		if err := rows.Scan(&s.ID, &s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID); err != nil {
+			panic(err)
+		}
+		sections = append(sections, s)
+	}
+	return sections
+}
+
 func (r *repository) Exists(sectionNumber int) bool {
 	row := r.db.QueryRow(ExistsQuery, sectionNumber)
 	err := row.Scan(&sectionNumber)
@@ -90,16 +175,46 @@ func (r *repository) Save(sc domain.Section) int {
 	return int(id)
 }
 
-func (r *repository) Update(s domain.Section) {
-	stmt, err := r.db.Prepare(UpdateQuery)
+// Update stores the section, leaving its current capacity to the batch writes
+// that keep it in sync. The maximum capacity is checked against the quantity
+// the batches hold while the section row is locked, as those writes do, and
+// that usage is returned. The row is only locked while it is still at the
+// version of s.
+func (r *repository) Update(s domain.Section) (int, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
 		panic(err)
 	}
+	defer tx.Rollback()
 
-	_, err = stmt.Exec(&s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID, &s.ID)
+	var used int
+	if err := tx.QueryRow(LockQuery, s.ID, s.Version).Scan(&s.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrModified
+		}
+		panic(err)
+	}
+	if err := tx.QueryRow(UsageQuery, s.ID).Scan(&used); err != nil {
+		panic(err)
+	}
+	if s.MaximumCapacity < used {
+		return used, ErrCapacityBelowUsage
+	}
+
+	stmt, err := tx.Prepare(UpdateQuery)
 	if err != nil {
 		panic(err)
 	}
+
+	_, err = stmt.Exec(&s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID, &s.ID)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return used, nil
 }
 
 func (r *repository) Delete(id int) {
@@ -113,22 +228,37 @@ func (r *repository) Delete(id int) {
 	}
 }
 
-func (r *repository) CountProductsByAllSections() []domain.ProductsBySectionReport {
-	rows, err := r.db.Query(CountProductsByAllSectionsQuery)
+// CountProductsByAllSections counts the product batches of every section,
+// leaving out disposed ones and counting the batches of a lot split by
+// transfers once per section, by their origin batch.
+func (r *repository) CountProductsByAllSections(dates domain.DateRange) []domain.ProductsBySectionReport {
+	productBatches := make([]domain.ProductsBySectionReport, 0)
+	r.StreamProductsByAllSections(dates, func(pb domain.ProductsBySectionReport) {
+		productBatches = append(productBatches, pb)
+	})
+	return productBatches
+}
+
+// StreamProductsByAllSections yields the report of every section as it is
+// read, without loading the whole report in memory.
+func (r *repository) StreamProductsByAllSections(dates domain.DateRange, yield func(domain.ProductsBySectionReport)) {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows, err := r.db.Query(CountProductsByAllSectionsQuery, from, from, to, to)
 	if err != nil {
 		panic(err)
 	}
-	productBatches := make([]domain.ProductsBySectionReport, 0)
+	defer rows.Close()
+
 	for rows.Next() {
 		pb := domain.ProductsBySectionReport{}
 		_ = rows.Scan(&pb.SectionID, &pb.SectionNumber, &pb.ProductsCount)
-		productBatches = append(productBatches, pb)
+		yield(pb)
 	}
-	return productBatches
 }
 
-func (r *repository) CountProductsBySection(id int) *domain.ProductsBySectionReport {
-	rows := r.db.QueryRow(CountProductsBySectionQuery, id)
+func (r *repository) CountProductsBySection(id int, dates domain.DateRange) *domain.ProductsBySectionReport {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows := r.db.QueryRow(CountProductsBySectionQuery, from, from, to, to, id)
 	pb := domain.ProductsBySectionReport{}
 	err := rows.Scan(&pb.SectionID, &pb.SectionNumber, &pb.ProductsCount)
 	if err != nil {
@@ -139,3 +269,40 @@ func (r *repository) CountProductsBySection(id int) *domain.ProductsBySectionRep
 	}
 	return &pb
 }
+
+// CountProductsByPeriod counts the product batches of the section, or of every
+// section when id is nil, by day, week or month of their manufacturing date.
+// Disposed batches are left out and batches split by transfers are counted
+// once, by their origin batch.
+func (r *repository) CountProductsByPeriod(id *int, period string, dates domain.DateRange) []domain.ReportPeriod {
+	from, to := helpers.ToOptionalFormattedDateTime(dates.From), helpers.ToOptionalFormattedDateTime(dates.To)
+	rows, err := r.db.Query(CountProductsByPeriodQuery, period, id, id, from, from, to, to)
+	if err != nil {
+		panic(err)
+	}
+
+	periods := make([]domain.ReportPeriod, 0)
+
+	for rows.Next() {
+		rp := domain.ReportPeriod{}
+		var start string
+		_ = rows.Scan(&start, &rp.Count)
+		rp.Period = helpers.ToDateTime(start)
+		periods = append(periods, rp)
+	}
+
+	return periods
+}
+
+func (r *repository) Occupancy(id int) *domain.SectionOccupancy {
+	row := r.db.QueryRow(OccupancyQuery, id)
+	o := domain.SectionOccupancy{}
+	err := row.Scan(&o.SectionID, &o.MaximumCapacity, &o.Used)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		panic(err)
+	}
+	return &o
+}
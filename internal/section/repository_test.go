@@ -4,10 +4,12 @@ import (
 	"database/sql"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -54,16 +56,45 @@ func TestRepositoryGetAll(t *testing.T) {
 	})
 }
 
+func TestRepositoryGetByWarehouse(t *testing.T) {
+	t.Run("Should return the sections of the warehouse", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		columns := []string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id"}
+		rows := sqlmock.NewRows(columns)
+		rows.AddRow(1, 1, 1, 1, 1, 1, 1, 1, 1)
+
+		mock.ExpectQuery(regexp.QuoteMeta(section.GetByWarehouseQuery)).
+			WithArgs(mockedSectionTemplate.WarehouseID).
+			WillReturnRows(rows)
+
+		repository := section.NewRepository(db)
+		result := repository.GetByWarehouse(mockedSectionTemplate.WarehouseID)
+
+		assert.Equal(t, []domain.Section{mockedSectionTemplate}, result)
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(section.GetByWarehouseQuery)).WillReturnError(sql.ErrConnDone)
+		repository := section.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetByWarehouse(1) })
+	})
+}
+
 func TestRepositoryGet(t *testing.T) {
 	t.Run("Should return a section by specified id", func(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id"}
+		columns := []string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id", "version"}
 		rows := sqlmock.NewRows(columns)
 		sectionId := 1
 
-		rows.AddRow(sectionId, 1, 1, 1, 1, 1, 1, 1, 1)
+		rows.AddRow(sectionId, 1, 1, 1, 1, 1, 1, 1, 1, 2)
 
 		mock.ExpectQuery(regexp.QuoteMeta(section.GetQuery)).
 			WithArgs(sectionId).
@@ -74,6 +105,7 @@ func TestRepositoryGet(t *testing.T) {
 		result := repository.Get(sectionId)
 
 		assert.NotNil(t, result)
+		assert.Equal(t, 2, result.Version)
 	})
 
 	t.Run("Should not return a section", func(t *testing.T) {
@@ -220,31 +252,60 @@ func TestRepositorySave(t *testing.T) {
 }
 
 func TestRepositoryUpdate(t *testing.T) {
-	t.Run("Should update the section", func(t *testing.T) {
+	t.Run("Should update the section without its current capacity", func(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
 		mockedSection := mockedSectionTemplate
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(section.LockQuery)).WithArgs(mockedSection.ID, mockedSection.Version).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(mockedSection.ID))
+		mock.ExpectQuery(regexp.QuoteMeta(section.UsageQuery)).WithArgs(mockedSection.ID).WillReturnRows(sqlmock.NewRows([]string{"used"}).AddRow(mockedSection.MaximumCapacity))
 		mock.ExpectPrepare(regexp.QuoteMeta(section.UpdateQuery))
 		mock.ExpectExec(regexp.QuoteMeta(section.UpdateQuery)).
-			WithArgs(mockedSection.SectionNumber, mockedSection.CurrentTemperature, mockedSection.MinimumTemperature, mockedSection.CurrentCapacity, mockedSection.MinimumCapacity, mockedSection.MaximumCapacity, mockedSection.WarehouseID, mockedSection.ProductTypeID, mockedSection.ID).
+			WithArgs(mockedSection.SectionNumber, mockedSection.CurrentTemperature, mockedSection.MinimumTemperature, mockedSection.MinimumCapacity, mockedSection.MaximumCapacity, mockedSection.WarehouseID, mockedSection.ProductTypeID, mockedSection.ID).
 			WillReturnResult(sqlmock.NewResult(int64(mockedSection.ID), 1))
+		mock.ExpectCommit()
 
 		repository := section.NewRepository(db)
+		used, err := repository.Update(mockedSection)
 
-		assert.NotPanics(t, func() { repository.Update(mockedSection) })
+		assert.NoError(t, err)
+		assert.Equal(t, mockedSection.MaximumCapacity, used)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("Should throw panic when expected prepare fails", func(t *testing.T) {
+	t.Run("Should return capacity below usage error when the batches hold more than the maximum", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedSection := mockedSectionTemplate
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(section.LockQuery)).WithArgs(mockedSection.ID, mockedSection.Version).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(mockedSection.ID))
+		mock.ExpectQuery(regexp.QuoteMeta(section.UsageQuery)).WithArgs(mockedSection.ID).WillReturnRows(sqlmock.NewRows([]string{"used"}).AddRow(mockedSection.MaximumCapacity + 1))
+		mock.ExpectRollback()
+
+		repository := section.NewRepository(db)
+		used, err := repository.Update(mockedSection)
+
+		assert.ErrorIs(t, err, section.ErrCapacityBelowUsage)
+		assert.Equal(t, mockedSection.MaximumCapacity+1, used)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Should not update the section when its version changed", func(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		mockedSection4 := mockedSectionTemplate
-		mock.ExpectPrepare(regexp.QuoteMeta(section.UpdateQuery)).WillReturnError(sql.ErrConnDone)
+		mockedSection := mockedSectionTemplate
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(section.LockQuery)).WithArgs(mockedSection.ID, mockedSection.Version).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
 
 		repository := section.NewRepository(db)
+		_, err := repository.Update(mockedSection)
 
-		assert.Panics(t, func() { repository.Update(mockedSection4) })
+		assert.ErrorIs(t, err, section.ErrModified)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("Should throw panic when expected exec fails", func(t *testing.T) {
@@ -252,10 +313,11 @@ func TestRepositoryUpdate(t *testing.T) {
 		defer db.Close()
 
 		mockedSection := mockedSectionTemplate
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(section.LockQuery)).WithArgs(mockedSection.ID, mockedSection.Version).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(mockedSection.ID))
+		mock.ExpectQuery(regexp.QuoteMeta(section.UsageQuery)).WithArgs(mockedSection.ID).WillReturnRows(sqlmock.NewRows([]string{"used"}).AddRow(0))
 		mock.ExpectPrepare(regexp.QuoteMeta(section.UpdateQuery))
-		mock.ExpectExec(regexp.QuoteMeta(section.UpdateQuery)).
-			WithArgs(mockedSection.ID, mockedSection.SectionNumber, mockedSection.CurrentTemperature, mockedSection.MinimumTemperature, mockedSection.CurrentCapacity, mockedSection.MinimumCapacity, mockedSection.MaximumCapacity, mockedSection.WarehouseID, mockedSection.ProductTypeID).
-			WillReturnError(sql.ErrConnDone)
+		mock.ExpectExec(regexp.QuoteMeta(section.UpdateQuery)).WillReturnError(sql.ErrConnDone)
 
 		repository := section.NewRepository(db)
 
@@ -322,7 +384,7 @@ func TestRepositoryCountProductsByAllSections(t *testing.T) {
 
 		repository := section.NewRepository(db)
 
-		result := repository.CountProductsByAllSections()
+		result := repository.CountProductsByAllSections(domain.DateRange{})
 
 		assert.Equal(t, len(result), 1)
 	})
@@ -335,7 +397,7 @@ func TestRepositoryCountProductsByAllSections(t *testing.T) {
 
 		repository := section.NewRepository(db)
 
-		assert.Panics(t, func() { repository.CountProductsByAllSections() })
+		assert.Panics(t, func() { repository.CountProductsByAllSections(domain.DateRange{}) })
 	})
 }
 
@@ -350,12 +412,12 @@ func TestRepositoryCountProductsBySection(t *testing.T) {
 		rows.AddRow(id, 1, 1)
 
 		mock.ExpectQuery(regexp.QuoteMeta(section.CountProductsBySectionQuery)).
-			WithArgs(id).
+			WithArgs(nil, nil, nil, nil, id).
 			WillReturnRows(rows)
 
 		repository := section.NewRepository(db)
 
-		result := repository.CountProductsBySection(id)
+		result := repository.CountProductsBySection(id, domain.DateRange{})
 
 		assert.NotNil(t, result)
 	})
@@ -368,7 +430,7 @@ func TestRepositoryCountProductsBySection(t *testing.T) {
 			WillReturnError(sql.ErrNoRows)
 
 		repository := section.NewRepository(db)
-		result := repository.CountProductsBySection(id)
+		result := repository.CountProductsBySection(id, domain.DateRange{})
 
 		assert.Nil(t, result)
 	})
@@ -378,12 +440,164 @@ func TestRepositoryCountProductsBySection(t *testing.T) {
 
 		id := 1
 		mock.ExpectQuery(regexp.QuoteMeta(section.CountProductsBySectionQuery)).
-			WithArgs(id).
+			WithArgs(nil, nil, nil, nil, id).
 			WillReturnError(sql.ErrConnDone)
 
 		repository := section.NewRepository(db)
 
-		assert.Panics(t, func() { repository.CountProductsBySection(id) })
+		assert.Panics(t, func() { repository.CountProductsBySection(id, domain.DateRange{}) })
+	})
+}
+
+func TestRepositoryOccupancy(t *testing.T) {
+	t.Run("Should return the section occupancy", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		sectionId := 1
+		rows := sqlmock.NewRows([]string{"id", "maximum_capacity", "used"}).AddRow(sectionId, 30, 10)
+
+		mock.ExpectQuery(regexp.QuoteMeta(section.OccupancyQuery)).
+			WithArgs(sectionId).
+			WillReturnRows(rows)
+
+		repository := section.NewRepository(db)
+
+		result := repository.Occupancy(sectionId)
+
+		assert.Equal(t, &domain.SectionOccupancy{SectionID: sectionId, MaximumCapacity: 30, Used: 10}, result)
+	})
+
+	t.Run("Should not return an occupancy", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		sectionId := 1
+
+		mock.ExpectQuery(regexp.QuoteMeta(section.OccupancyQuery)).WithArgs(sectionId).WillReturnError(sql.ErrNoRows)
+
+		repository := section.NewRepository(db)
+
+		assert.Nil(t, repository.Occupancy(sectionId))
+	})
+
+	t.Run("Should throw panic", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		sectionId := 1
+
+		mock.ExpectQuery(regexp.QuoteMeta(section.OccupancyQuery)).WithArgs(sectionId).WillReturnError(sql.ErrConnDone)
+
+		repository := section.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Occupancy(sectionId) })
+	})
+}
+
+func TestRepositoryCountProductsByPeriod(t *testing.T) {
+	t.Run("Should return the count of each period within the dates", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		id := 1
+		from := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows([]string{"period", "product_count"}).
+			AddRow("2023-07-03 00:00:00", 2).
+			AddRow("2023-07-10 00:00:00", 1)
+
+		mock.ExpectQuery(regexp.QuoteMeta(section.CountProductsByPeriodQuery)).
+			WithArgs(domain.PeriodWeek, &id, &id, "2023-07-01 00:00:00", "2023-07-01 00:00:00", nil, nil).
+			WillReturnRows(rows)
+
+		repository := section.NewRepository(db)
+
+		result := repository.CountProductsByPeriod(&id, domain.PeriodWeek, domain.DateRange{From: &from})
+
+		assert.Equal(t, []domain.ReportPeriod{
+			{Period: time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC), Count: 2},
+			{Period: time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC), Count: 1},
+		}, result)
+	})
+}
+
+func TestRepositoryGetByWarehouses(t *testing.T) {
+	t.Run("Should return the sections of the warehouses with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(section.GetByWarehousesQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "id_product_type"}).
+			AddRow(1, 10, 2, 1, 5, 1, 50, 2, 1).
+			AddRow(3, 11, 2, 1, 5, 1, 50, 5, 1)
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := section.NewRepository(db)
+		result := repository.GetByWarehouses([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := section.NewRepository(db)
+		result := repository.GetByWarehouses(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(section.GetByWarehousesQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := section.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetByWarehouses([]int{2}) })
+	})
+}
+
+func TestRepositoryGetMany(t *testing.T) {
+	t.Run("Should return the sections found among the ids with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(section.GetManyQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id"}).
+			AddRow(2, 1, 2, 0, 10, 1, 50, 1, 1).
+			AddRow(5, 2, 2, 0, 10, 1, 50, 1, 1)
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := section.NewRepository(db)
+		result := repository.GetMany([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := section.NewRepository(db)
+		result := repository.GetMany(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(section.GetManyQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := section.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetMany([]int{2}) })
 	})
 }
 
@@ -1,27 +1,38 @@
 package section
 
 import (
+	"errors"
+	"math"
+
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_type"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	ResourceNotFound      = "seção não encontrada com o id %d"
-	ResourceAlreadyExists = "uma seção com o código '%d' já existe"
-	WarehouseNotFound     = "armazem não encontrado com o id %d"
-	ProductTypeNotFound   = "tipo do produto não encontrado com o id %d"
+	ResourceNotFound      = "section.not_found"
+	ResourceAlreadyExists = "section.already_exists"
+	WarehouseNotFound     = "warehouse.not_found"
+	ProductTypeNotFound   = "product_type.not_found"
+	CapacityBelowUsage    = "section.capacity_below_usage"
+	ResourceModified      = "section.modified"
 )
 
 type Service interface {
 	GetAll() []domain.Section
+	GetMany(ids []int) []domain.Section
 	Get(int) (*domain.Section, error)
+	GetByWarehouses(warehouseIDs []int) []domain.Section
 	Create(sc domain.Section) (*domain.Section, error)
-	Update(int, domain.UpdateSection) (*domain.Section, error)
+	Update(domain.Section, domain.Section) (*domain.Section, error)
 	Delete(int) error
-	CountProductsByAllSections() []domain.ProductsBySectionReport
-	CountProductsBySection(id int) (*domain.ProductsBySectionReport, error)
+	CountProductsByAllSections(dates domain.DateRange, top int) []domain.ProductsBySectionReport
+	StreamProductsByAllSections(dates domain.DateRange, yield func(domain.ProductsBySectionReport))
+	CountProductsBySection(id int, dates domain.DateRange) (*domain.ProductsBySectionReport, error)
+	CountProductsByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error)
+	Occupancy(id int) (*domain.SectionOccupancy, error)
 }
 type service struct {
 	repository            Repository
@@ -39,6 +50,11 @@ func (s *service) GetAll() []domain.Section {
 	return s.repository.GetAll()
 }
 
+// GetMany returns the sections found among ids, leaving out the missing ones.
+func (s *service) GetMany(ids []int) []domain.Section {
+	return s.repository.GetMany(ids)
+}
+
 func (s *service) Get(id int) (*domain.Section, error) {
 	section := s.repository.Get(id)
 
@@ -49,6 +65,11 @@ func (s *service) Get(id int) (*domain.Section, error) {
 	return section, nil
 }
 
+// GetByWarehouses returns the sections of every warehouse of warehouseIDs.
+func (s *service) GetByWarehouses(warehouseIDs []int) []domain.Section {
+	return s.repository.GetByWarehouses(warehouseIDs)
+}
+
 func (s *service) Create(sc domain.Section) (*domain.Section, error) {
 	if s.repository.Exists(sc.SectionNumber) {
 		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, sc.SectionNumber)
@@ -66,43 +87,45 @@ func (s *service) Create(sc domain.Section) (*domain.Section, error) {
 		return nil, apperr.NewDependentResourceNotFound(WarehouseNotFound, sc.WarehouseID)
 	}
 
+	sc.CurrentCapacity = 0
 	id := s.repository.Save(sc)
 
 	return s.repository.Get(id), nil
 }
 
-func (s *service) Update(id int, section domain.UpdateSection) (*domain.Section, error) {
-	sectionFound := s.repository.Get(id)
+// Update replaces current, the section as the caller read it, with section.
+// It fails when the section was changed since it was read.
+func (s *service) Update(current domain.Section, section domain.Section) (*domain.Section, error) {
+	sectionNumber := section.SectionNumber
+	sectionNumberExists := s.repository.Exists(sectionNumber)
 
-	if sectionFound == nil {
-		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	if sectionNumberExists && sectionNumber != current.SectionNumber {
+		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, sectionNumber)
 	}
 
-	if section.SectionNumber != nil {
-		sectionNumber := *section.SectionNumber
-		sectionNumberExists := s.repository.Exists(sectionNumber)
-
-		if sectionNumberExists && sectionNumber != sectionFound.SectionNumber {
-			return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, sectionNumber)
-		}
-	}
+	section.ID = current.ID
+	section.Version = current.Version
 
-	sectionFound.Overlap(section)
-
-	productTypeById := s.productTypeRepository.Get(sectionFound.ProductTypeID)
+	productTypeById := s.productTypeRepository.Get(section.ProductTypeID)
 
 	if productTypeById == nil {
-		return nil, apperr.NewDependentResourceNotFound(ProductTypeNotFound, sectionFound.ProductTypeID)
+		return nil, apperr.NewDependentResourceNotFound(ProductTypeNotFound, section.ProductTypeID)
 	}
 
-	warehouseById := s.warehouseRepository.Get(sectionFound.WarehouseID)
+	warehouseById := s.warehouseRepository.Get(section.WarehouseID)
 
 	if warehouseById == nil {
-		return nil, apperr.NewDependentResourceNotFound(WarehouseNotFound, sectionFound.WarehouseID)
+		return nil, apperr.NewDependentResourceNotFound(WarehouseNotFound, section.WarehouseID)
 	}
 
-	s.repository.Update(*sectionFound)
-	return s.repository.Get(id), nil
+	used, err := s.repository.Update(section)
+	if errors.Is(err, ErrCapacityBelowUsage) {
+		return nil, apperr.NewIncompatibleResource(CapacityBelowUsage, section.MaximumCapacity, used)
+	}
+	if errors.Is(err, ErrModified) {
+		return nil, apperr.NewIncompatibleResource(ResourceModified, section.ID)
+	}
+	return s.repository.Get(section.ID), nil
 }
 
 func (s *service) Delete(id int) error {
@@ -115,14 +138,53 @@ func (s *service) Delete(id int) error {
 	s.repository.Delete(id)
 	return nil
 }
-func (s *service) CountProductsByAllSections() []domain.ProductsBySectionReport {
-	return s.repository.CountProductsByAllSections()
+
+// CountProductsByAllSections counts the product batches of every section. When
+// top is positive, only the top sections with the most product batches are
+// returned.
+func (s *service) CountProductsByAllSections(dates domain.DateRange, top int) []domain.ProductsBySectionReport {
+	report := s.repository.CountProductsByAllSections(dates)
+
+	return helpers.Top(report, top, func(r domain.ProductsBySectionReport) int { return r.ProductsCount })
 }
 
-func (s *service) CountProductsBySection(id int) (*domain.ProductsBySectionReport, error) {
-	productsBatch := s.repository.CountProductsBySection(id)
+func (s *service) StreamProductsByAllSections(dates domain.DateRange, yield func(domain.ProductsBySectionReport)) {
+	s.repository.StreamProductsByAllSections(dates, yield)
+}
+
+func (s *service) CountProductsBySection(id int, dates domain.DateRange) (*domain.ProductsBySectionReport, error) {
+	productsBatch := s.repository.CountProductsBySection(id, dates)
 	if productsBatch == nil {
 		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
 	}
 	return productsBatch, nil
 }
+
+func (s *service) CountProductsByPeriod(id *int, period string, dates domain.DateRange) ([]domain.ReportPeriod, error) {
+	if id != nil && s.repository.Get(*id) == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, *id)
+	}
+
+	return s.repository.CountProductsByPeriod(id, period, dates), nil
+}
+
+// Occupancy derives the section usage from the current quantity of the
+// batches stored in it.
+func (s *service) Occupancy(id int) (*domain.SectionOccupancy, error) {
+	occupancy := s.repository.Occupancy(id)
+
+	if occupancy == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	}
+
+	occupancy.Free = occupancy.MaximumCapacity - occupancy.Used
+	if occupancy.Free < 0 {
+		occupancy.Free = 0
+	}
+	if occupancy.MaximumCapacity > 0 {
+		percent := float64(occupancy.Used) / float64(occupancy.MaximumCapacity) * 100
+		occupancy.Percent = math.Round(percent*100) / 100
+	}
+
+	return occupancy, nil
+}
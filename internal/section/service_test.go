@@ -2,6 +2,7 @@ package section_test
 
 import (
 	"testing"
+	"time"
 
 	product_type_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product_type/mocks"
 	warehouse_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse/mocks"
@@ -38,7 +39,9 @@ func TestServiceCreate(t *testing.T) {
 		service, repository, warehouseRepository, productTypeRepository := CreateService(t)
 
 		id := 1
-		repository.On("Save", mockedSection).Return(id)
+		savedSection := mockedSection
+		savedSection.CurrentCapacity = 0
+		repository.On("Save", savedSection).Return(id)
 		repository.On("Get", id).Return(&mockedSection)
 		repository.On("Exists", mockedSection.SectionNumber).Return(false)
 		productTypeRepository.On("Get", mockedSection.ProductTypeID).Return(&domain.ProductType{})
@@ -134,39 +137,14 @@ func TestServiceGet(t *testing.T) {
 }
 
 func TestServiceUpdate(t *testing.T) {
-	t.Run("Should return a not found error", func(t *testing.T) {
-		service, repository, _, _ := CreateService(t)
-
-		id := 2
-		sectionNumber := 123
-		UpdateSection := domain.UpdateSection{
-			ID:            &id,
-			SectionNumber: &sectionNumber,
-		}
-
-		var respositoryResult *domain.Section
-
-		repository.On("Get", id).Return(respositoryResult)
-		result, err := service.Update(id, UpdateSection)
-
-		assert.Error(t, err)
-		assert.Nil(t, result)
-		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
-	})
-
 	t.Run("Should return a conflict error", func(t *testing.T) {
 		service, repository, _, _ := CreateService(t)
 
-		id := 1
-		sectionNumber := 456
-		UpdateSection := domain.UpdateSection{
-			ID:            &id,
-			SectionNumber: &sectionNumber,
-		}
+		updateSection := mockedSection
+		updateSection.SectionNumber = 456
 
-		repository.On("Get", id).Return(&mockedSection)
-		repository.On("Exists", sectionNumber).Return(true)
-		result, err := service.Update(id, UpdateSection)
+		repository.On("Exists", updateSection.SectionNumber).Return(true)
+		result, err := service.Update(mockedSection, updateSection)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -176,18 +154,13 @@ func TestServiceUpdate(t *testing.T) {
 	t.Run("Should return a product type dependent resource not found error", func(t *testing.T) {
 		service, repository, _, productTypeRepository := CreateService(t)
 
-		id := 1
-		sectionNumber := 456
-		UpdateSection := domain.UpdateSection{
-			ID:            &id,
-			SectionNumber: &sectionNumber,
-		}
+		updateSection := mockedSection
+		updateSection.SectionNumber = 456
 		var productTypeResult *domain.ProductType
 
-		repository.On("Get", id).Return(&mockedSection)
-		repository.On("Exists", sectionNumber).Return(false)
+		repository.On("Exists", updateSection.SectionNumber).Return(false)
 		productTypeRepository.On("Get", mockedSection.ProductTypeID).Return(productTypeResult)
-		result, err := service.Update(id, UpdateSection)
+		result, err := service.Update(mockedSection, updateSection)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -197,50 +170,71 @@ func TestServiceUpdate(t *testing.T) {
 	t.Run("Should return a warehouse dependent resource not found error", func(t *testing.T) {
 		service, repository, warehouseRepository, productTypeRepository := CreateService(t)
 
-		id := 1
-		sectionNumber := 456
-		UpdateSection := domain.UpdateSection{
-			ID:            &id,
-			SectionNumber: &sectionNumber,
-		}
+		updateSection := mockedSection
+		updateSection.SectionNumber = 456
 		var warehouseResult *domain.Warehouse
 
-		repository.On("Get", id).Return(&mockedSection)
-		repository.On("Exists", sectionNumber).Return(false)
+		repository.On("Exists", updateSection.SectionNumber).Return(false)
 		productTypeRepository.On("Get", mockedSection.ProductTypeID).Return(&domain.ProductType{})
 		warehouseRepository.On("Get", mockedSection.WarehouseID).Return(warehouseResult)
-		result, err := service.Update(id, UpdateSection)
+		result, err := service.Update(mockedSection, updateSection)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
 	})
 
+	t.Run("Should return a conflict error when maximum capacity is below usage", func(t *testing.T) {
+		service, repository, warehouseRepository, productTypeRepository := CreateService(t)
+
+		updateSection := mockedSection
+		updateSection.MaximumCapacity = 5
+
+		repository.On("Exists", updateSection.SectionNumber).Return(true)
+		productTypeRepository.On("Get", updateSection.ProductTypeID).Return(&domain.ProductType{})
+		warehouseRepository.On("Get", updateSection.WarehouseID).Return(&domain.Warehouse{})
+		repository.On("Update", updateSection).Return(10, section.ErrCapacityBelowUsage)
+		result, err := service.Update(mockedSection, updateSection)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+
+	t.Run("Should return a conflict error when the section was modified since it was read", func(t *testing.T) {
+		service, repository, warehouseRepository, productTypeRepository := CreateService(t)
+
+		currentSection := mockedSection
+		currentSection.Version = 3
+		updatedSection := currentSection
+		updatedSection.CurrentTemperature = 0
+
+		repository.On("Exists", updatedSection.SectionNumber).Return(true)
+		productTypeRepository.On("Get", updatedSection.ProductTypeID).Return(&domain.ProductType{})
+		warehouseRepository.On("Get", updatedSection.WarehouseID).Return(&domain.Warehouse{})
+		repository.On("Update", updatedSection).Return(0, section.ErrModified)
+		result, err := service.Update(currentSection, updatedSection)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+
 	t.Run("Should return an updated section", func(t *testing.T) {
 		service, repository, warehouseRepository, productTypeRepository := CreateService(t)
 
-		id := 1
-		sectionNumber := 123
-		currentTemperature := float32(2)
-		UpdateSection := domain.UpdateSection{
-			ID:                 &id,
-			CurrentTemperature: &currentTemperature,
-			SectionNumber:      &sectionNumber,
-		}
 		updatedSection := mockedSection
-		updatedSection.Overlap(UpdateSection)
+		updatedSection.CurrentTemperature = 0
 
-		repository.On("Get", id).Return(&mockedSection)
-		repository.On("Exists", sectionNumber).Return(false)
-		productTypeRepository.On("Get", mockedSection.ProductTypeID).Return(&domain.ProductType{})
-		warehouseRepository.On("Get", mockedSection.WarehouseID).Return(&domain.Warehouse{})
-		repository.On("Update", updatedSection)
-		repository.On("Get", id).Return(&updatedSection)
-		result, err := service.Update(id, UpdateSection)
+		repository.On("Exists", updatedSection.SectionNumber).Return(true)
+		productTypeRepository.On("Get", updatedSection.ProductTypeID).Return(&domain.ProductType{})
+		warehouseRepository.On("Get", updatedSection.WarehouseID).Return(&domain.Warehouse{})
+		repository.On("Update", updatedSection).Return(0, nil)
+		repository.On("Get", mockedSection.ID).Return(&updatedSection)
+		result, err := service.Update(mockedSection, updatedSection)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, currentTemperature, result.CurrentTemperature)
+		assert.Equal(t, updatedSection.CurrentTemperature, result.CurrentTemperature)
 	})
 }
 
@@ -278,8 +272,8 @@ func TestCountProductsByAllSections(t *testing.T) {
 
 		expected := []domain.ProductsBySectionReport{mockedProductsBySection}
 
-		repository.On("CountProductsByAllSections").Return(expected)
-		result := service.CountProductsByAllSections()
+		repository.On("CountProductsByAllSections", domain.DateRange{}).Return(expected)
+		result := service.CountProductsByAllSections(domain.DateRange{}, 0)
 
 		assert.NotEmpty(t, result)
 		assert.True(t, len(result) == 1)
@@ -295,8 +289,8 @@ func TestCountProductsBySection(t *testing.T) {
 		mockedProductsBySec := mockedProductsBySection
 		id := 1
 
-		repository.On("CountProductsBySection", id).Return(&mockedProductsBySec)
-		result, err := service.CountProductsBySection(id)
+		repository.On("CountProductsBySection", id, domain.DateRange{}).Return(&mockedProductsBySec)
+		result, err := service.CountProductsBySection(id, domain.DateRange{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -308,8 +302,8 @@ func TestCountProductsBySection(t *testing.T) {
 		id := 1
 		var respositoryResult *domain.ProductsBySectionReport
 
-		repository.On("CountProductsBySection", id).Return(respositoryResult)
-		result, err := service.CountProductsBySection(id)
+		repository.On("CountProductsBySection", id, domain.DateRange{}).Return(respositoryResult)
+		result, err := service.CountProductsBySection(id, domain.DateRange{})
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -317,6 +311,72 @@ func TestCountProductsBySection(t *testing.T) {
 	})
 }
 
+func TestServiceOccupancy(t *testing.T) {
+	t.Run("Should return a not found error", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		id := 1
+		var repositoryResult *domain.SectionOccupancy
+
+		repository.On("Occupancy", id).Return(repositoryResult)
+		result, err := service.Occupancy(id)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+
+	t.Run("Should return the section occupancy", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		id := 1
+		repository.On("Occupancy", id).Return(&domain.SectionOccupancy{SectionID: id, MaximumCapacity: 30, Used: 10})
+		result, err := service.Occupancy(id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SectionOccupancy{SectionID: id, MaximumCapacity: 30, Used: 10, Free: 20, Percent: 33.33}, *result)
+	})
+
+	t.Run("Should not return negative free capacity", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		id := 1
+		repository.On("Occupancy", id).Return(&domain.SectionOccupancy{SectionID: id, MaximumCapacity: 0, Used: 10})
+		result, err := service.Occupancy(id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Free)
+		assert.Equal(t, float64(0), result.Percent)
+	})
+}
+
+func TestServiceCountProductsByPeriod(t *testing.T) {
+	t.Run("Should return the count of each period", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		periods := []domain.ReportPeriod{{Period: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), Count: 3}}
+		repository.On("CountProductsByPeriod", (*int)(nil), domain.PeriodMonth, domain.DateRange{}).Return(periods)
+
+		result, err := service.CountProductsByPeriod(nil, domain.PeriodMonth, domain.DateRange{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, periods, result)
+	})
+
+	t.Run("Should return not found error when the id does not exist", func(t *testing.T) {
+		service, repository, _, _ := CreateService(t)
+
+		id := 1
+		var found *domain.Section
+		repository.On("Get", id).Return(found)
+
+		result, err := service.CountProductsByPeriod(&id, domain.PeriodMonth, domain.DateRange{})
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
 func CreateService(t *testing.T) (section.Service, *mocks.Repository, *warehouse_mocks.Repository, *product_type_mocks.Repository) {
 	t.Helper()
 	repository := new(mocks.Repository)
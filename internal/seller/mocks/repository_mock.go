@@ -24,15 +24,31 @@ func (r *Repository) Exists(cid int) bool {
 	return args.Get(0).(bool)
 }
 
+func (r *Repository) Existing(cids []int) []int {
+	args := r.Called(cids)
+	return args.Get(0).([]int)
+}
+
 func (r *Repository) Save(seller domain.Seller) int {
 	args := r.Called(seller)
 	return args.Get(0).(int)
 }
 
-func (r *Repository) Update(seller domain.Seller) {
-	r.Called(seller)
+func (r *Repository) SaveAll(sellers []domain.Seller) []int {
+	args := r.Called(sellers)
+	return args.Get(0).([]int)
+}
+
+func (r *Repository) Update(seller domain.Seller) error {
+	args := r.Called(seller)
+	return args.Error(0)
 }
 
 func (r *Repository) Delete(id int) {
 	r.Called(id)
 }
+
+func (r *Repository) GetMany(ids []int) []domain.Seller {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.Seller)
+}
@@ -24,8 +24,13 @@ func (s *Service) Create(p domain.Seller) (*domain.Seller, error) {
 	return args.Get(0).(*domain.Seller), args.Error(1)
 }
 
-func (s *Service) Update(id int, p domain.UpdateSeller) (*domain.Seller, error) {
-	args := s.Called(id, p)
+func (s *Service) Import(sellers []domain.Seller, options domain.ImportOptions) ([]int, []error) {
+	args := s.Called(sellers, options)
+	return args.Get(0).([]int), args.Get(1).([]error)
+}
+
+func (s *Service) Update(current domain.Seller, p domain.Seller) (*domain.Seller, error) {
+	args := s.Called(current, p)
 	return args.Get(0).(*domain.Seller), args.Error(1)
 }
 
@@ -33,3 +38,8 @@ func (s *Service) Delete(id int) error {
 	args := s.Called(id)
 	return args.Error(0)
 }
+
+func (s *Service) GetMany(ids []int) []domain.Seller {
+	args := s.Called(ids)
+	return args.Get(0).([]domain.Seller)
+}
@@ -5,26 +5,36 @@ import (
 	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetAllQuery = "SELECT id, cid, company_name, address, telephone, locality_id FROM sellers"
-	GetQuery    = "SELECT id, cid, company_name, address, telephone, locality_id FROM sellers WHERE id=?"
-	ExistsQuery = "SELECT cid FROM sellers WHERE cid=?"
-	InsertQuery = "INSERT INTO sellers (cid, company_name, address, telephone, locality_id) VALUES (?, ?, ?, ?, ?)"
-	UpdateQuery = "UPDATE sellers SET cid=?, company_name=?, address=?, telephone=?, locality_id=? WHERE id=?"
-	DeleteQuery = "DELETE FROM sellers WHERE id=?"
+	GetAllQuery   = "SELECT id, cid, company_name, address, telephone, locality_id FROM sellers"
+	GetQuery      = "SELECT id, cid, company_name, address, telephone, locality_id, version FROM sellers WHERE id=?"
+	GetManyQuery  = "SELECT id, cid, company_name, address, telephone, locality_id FROM sellers WHERE id IN (%s)"
+	ExistsQuery   = "SELECT cid FROM sellers WHERE cid=?"
+	ExistingQuery = "SELECT cid FROM sellers WHERE cid IN (%s)"
+	InsertQuery   = "INSERT INTO sellers (cid, company_name, address, telephone, locality_id) VALUES (?, ?, ?, ?, ?)"
+	UpdateQuery   = "UPDATE sellers SET cid=?, company_name=?, address=?, telephone=?, locality_id=?, version=version+1 WHERE id=? AND version=?"
+	DeleteQuery   = "DELETE FROM sellers WHERE id=?"
 )
 
 type Repository interface {
 	GetAll() []domain.Seller
 	Get(id int) *domain.Seller
+	GetMany(ids []int) []domain.Seller
 	Exists(cid int) bool
+	Existing(cids []int) []int
 	Save(s domain.Seller) int
-	Update(s domain.Seller)
+	SaveAll(sellers []domain.Seller) []int
+	Update(s domain.Seller) error
 	Delete(id int)
 }
 
+// ErrModified is returned by Update when the seller was changed or deleted
+// after the version being updated was read.
+var ErrModified = errors.New("seller modified since read")
+
 type repository struct {
 	db *sql.DB
 }
@@ -55,7 +65,7 @@ func (r *repository) GetAll() []domain.Seller {
 func (r *repository) Get(id int) *domain.Seller {
 	row := r.db.QueryRow(GetQuery, id)
 	s := domain.Seller{}
-	err := row.Scan(&s.ID, &s.CID, &s.CompanyName, &s.Address, &s.Telephone, &s.LocalityID)
+	err := row.Scan(&s.ID, &s.CID, &s.CompanyName, &s.Address, &s.Telephone, &s.LocalityID, &s.Version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil
@@ -66,12 +76,61 @@ func (r *repository) Get(id int) *domain.Seller {
 	return &s
 }
 
+// GetMany returns the sellers found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.Seller {
+	sellers := make([]domain.Seller, 0, len(ids))
+	if len(ids) == 0 {
+		return sellers
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s := domain.Seller{}
+		if err := rows.Scan(&s.ID, &s.CID, &s.CompanyName, &s.Address, &s.Telephone, &s.LocalityID); err != nil {
+			panic(err)
+		}
+		sellers = append(sellers, s)
+	}
+	return sellers
+}
+
 func (r *repository) Exists(cid int) bool {
 	row := r.db.QueryRow(ExistsQuery, cid)
 	err := row.Scan(&cid)
 	return err == nil
 }
 
+// Existing returns which of the given cids are already taken, looking
+// them up with a single query.
+func (r *repository) Existing(cids []int) []int {
+	existing := make([]int, 0, len(cids))
+	if len(cids) == 0 {
+		return existing
+	}
+
+	query, args := helpers.ExpandIn(ExistingQuery, cids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid int
+		if err := rows.Scan(&cid); err != nil {
+			panic(err)
+		}
+		existing = append(existing, cid)
+	}
+	return existing
+}
+
 func (r *repository) Save(s domain.Seller) int {
 	stmt, err := r.db.Prepare(InsertQuery)
 	if err != nil {
@@ -91,16 +150,60 @@ func (r *repository) Save(s domain.Seller) int {
 	return int(id)
 }
 
-func (r *repository) Update(s domain.Seller) {
+// SaveAll inserts the sellers in a single transaction and returns their ids
+// in the same order.
+func (r *repository) SaveAll(sellers []domain.Seller) []int {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(InsertQuery)
+	if err != nil {
+		panic(err)
+	}
+	defer stmt.Close()
+
+	ids := make([]int, 0, len(sellers))
+	for _, s := range sellers {
+		res, err := stmt.Exec(s.CID, s.CompanyName, s.Address, s.Telephone, s.LocalityID)
+		if err != nil {
+			panic(err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			panic(err)
+		}
+		ids = append(ids, int(id))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+func (r *repository) Update(s domain.Seller) error {
 	stmt, err := r.db.Prepare(UpdateQuery)
 	if err != nil {
 		panic(err)
 	}
 
-	_, err = stmt.Exec(s.CID, s.CompanyName, s.Address, s.Telephone, s.LocalityID, s.ID)
+	res, err := stmt.Exec(s.CID, s.CompanyName, s.Address, s.Telephone, s.LocalityID, s.ID, s.Version)
+	if err != nil {
+		panic(err)
+	}
+
+	affected, err := res.RowsAffected()
 	if err != nil {
 		panic(err)
 	}
+	if affected == 0 {
+		return ErrModified
+	}
+	return nil
 }
 
 func (r *repository) Delete(id int) {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/seller"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -46,10 +47,10 @@ func TestRepositoryGet(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"id", "cid", "company_name", "address", "telephone", "locality_id"}
+		columns := []string{"id", "cid", "company_name", "address", "telephone", "locality_id", "version"}
 		rows := sqlmock.NewRows(columns)
 		sellerId := 1
-		rows.AddRow(sellerId, 1, "", "", "", 1)
+		rows.AddRow(sellerId, 1, "", "", "", 1, 2)
 
 		mock.ExpectQuery(seller.GetQuery).WithArgs(sellerId).WillReturnRows(rows)
 
@@ -58,6 +59,7 @@ func TestRepositoryGet(t *testing.T) {
 		result := repository.Get(sellerId)
 
 		assert.NotNil(t, result)
+		assert.Equal(t, 2, result.Version)
 	})
 
 	t.Run("Should not return a seller", func(t *testing.T) {
@@ -215,12 +217,27 @@ func TestRepositoryUpdate(t *testing.T) {
 		mockedSeller := mockedSellerTemplate
 		mock.ExpectPrepare(regexp.QuoteMeta(seller.UpdateQuery))
 		mock.ExpectExec(regexp.QuoteMeta(seller.UpdateQuery)).
-			WithArgs(mockedSeller.CID, mockedSeller.CompanyName, mockedSeller.Address, mockedSeller.Telephone, mockedSeller.LocalityID, mockedSeller.ID).
+			WithArgs(mockedSeller.CID, mockedSeller.CompanyName, mockedSeller.Address, mockedSeller.Telephone, mockedSeller.LocalityID, mockedSeller.ID, mockedSeller.Version).
 			WillReturnResult(sqlmock.NewResult(int64(mockedSeller.ID), 1))
 
 		repository := seller.NewRepository(db)
 
-		assert.NotPanics(t, func() { repository.Update(mockedSeller) })
+		assert.NoError(t, repository.Update(mockedSeller))
+	})
+
+	t.Run("Should not update the seller when its version changed", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedSeller := mockedSellerTemplate
+		mock.ExpectPrepare(regexp.QuoteMeta(seller.UpdateQuery))
+		mock.ExpectExec(regexp.QuoteMeta(seller.UpdateQuery)).
+			WithArgs(mockedSeller.CID, mockedSeller.CompanyName, mockedSeller.Address, mockedSeller.Telephone, mockedSeller.LocalityID, mockedSeller.ID, mockedSeller.Version).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := seller.NewRepository(db)
+
+		assert.ErrorIs(t, repository.Update(mockedSeller), seller.ErrModified)
 	})
 
 	t.Run("Should throw panic when expected prepare fails", func(t *testing.T) {
@@ -242,7 +259,7 @@ func TestRepositoryUpdate(t *testing.T) {
 		mockedSeller := mockedSellerTemplate
 		mock.ExpectPrepare(regexp.QuoteMeta(seller.UpdateQuery))
 		mock.ExpectExec(regexp.QuoteMeta(seller.UpdateQuery)).
-			WithArgs(mockedSeller.CID, mockedSeller.CompanyName, mockedSeller.Address, mockedSeller.Telephone, mockedSeller.LocalityID, mockedSeller.ID).
+			WithArgs(mockedSeller.CID, mockedSeller.CompanyName, mockedSeller.Address, mockedSeller.Telephone, mockedSeller.LocalityID, mockedSeller.ID, mockedSeller.Version).
 			WillReturnError(sql.ErrConnDone)
 
 		repository := seller.NewRepository(db)
@@ -295,6 +312,84 @@ func TestRepositoryDelete(t *testing.T) {
 	})
 }
 
+func TestRepositoryGetMany(t *testing.T) {
+	t.Run("Should return the sellers found among the ids with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(seller.GetManyQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "cid", "company_name", "address", "telephone", "locality_id"}).
+			AddRow(2, 10, "Meli", "Street 1", "111", 1).
+			AddRow(5, 11, "Fresh", "Street 2", "222", 1)
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := seller.NewRepository(db)
+		result := repository.GetMany([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := seller.NewRepository(db)
+		result := repository.GetMany(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(seller.GetManyQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := seller.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetMany([]int{2}) })
+	})
+}
+
+func TestRepositoryExisting(t *testing.T) {
+	t.Run("Should return the cids already taken with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(seller.ExistingQuery, []int{1, 2})
+		rows := sqlmock.NewRows([]string{"cid"}).AddRow(2)
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1, 2).WillReturnRows(rows)
+
+		repository := seller.NewRepository(db)
+		result := repository.Existing([]int{1, 2})
+
+		assert.Equal(t, []int{2}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no cids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := seller.NewRepository(db)
+		result := repository.Existing(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(seller.ExistingQuery, []int{1})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(1).WillReturnError(sql.ErrConnDone)
+
+		repository := seller.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Existing([]int{1}) })
+	})
+}
+
 func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
 	t.Helper()
 
@@ -1,21 +1,27 @@
 package seller
 
 import (
+	"errors"
+
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/locality"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	ResourceNotFound      = "vendedor não encontrado com o id %d"
-	ResourceAlreadyExists = "um vendedor com o CID '%d' já existe"
+	ResourceNotFound      = "seller.not_found"
+	ResourceAlreadyExists = "seller.already_exists"
+	ResourceModified      = "seller.modified"
 )
 
 type Service interface {
 	GetAll() []domain.Seller
 	Get(id int) (*domain.Seller, error)
+	GetMany(ids []int) []domain.Seller
 	Create(seller domain.Seller) (*domain.Seller, error)
-	Update(id int, seller domain.UpdateSeller) (*domain.Seller, error)
+	Import(sellers []domain.Seller, options domain.ImportOptions) ([]int, []error)
+	Update(current domain.Seller, seller domain.Seller) (*domain.Seller, error)
 	Delete(id int) error
 }
 
@@ -42,47 +48,78 @@ func (s *service) Get(id int) (*domain.Seller, error) {
 	return seller, nil
 }
 
+// GetMany returns the sellers found among ids, leaving out the missing ones.
+func (s *service) GetMany(ids []int) []domain.Seller {
+	return s.repository.GetMany(ids)
+}
+
 func (s *service) Create(seller domain.Seller) (*domain.Seller, error) {
-	if s.repository.Exists(seller.CID) {
-		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, seller.CID)
+	if err := s.checkNew(seller, s.repository.Exists, s.localityExists); err != nil {
+		return nil, err
 	}
 
-	localityFound := s.localityRepository.Get(seller.LocalityID)
+	id := s.repository.Save(seller)
+	return s.repository.Get(id), nil
+}
 
-	if localityFound == nil {
-		return nil, apperr.NewDependentResourceNotFound(locality.LocalityNotFound, seller.LocalityID)
+// Import creates the sellers in bulk with the same checks as Create. A cid
+// repeated in the import is rejected as already existing. The cids and
+// localities of the whole import are looked up at once.
+func (s *service) Import(sellers []domain.Seller, options domain.ImportOptions) ([]int, []error) {
+	taken := helpers.Found(sellers, func(sl domain.Seller) int { return sl.CID }, s.repository.Existing, helpers.Identity[int])
+	localityFound := helpers.Found(sellers, func(sl domain.Seller) int { return sl.LocalityID }, s.localityRepository.GetMany, func(l domain.Locality) int { return l.ID })
+	cids := make(map[int]bool)
+	check := func(seller domain.Seller) error {
+		if cids[seller.CID] {
+			return apperr.NewResourceAlreadyExists(ResourceAlreadyExists, seller.CID)
+		}
+		cids[seller.CID] = true
+
+		return s.checkNew(seller, taken, localityFound)
 	}
 
-	id := s.repository.Save(seller)
-	return s.repository.Get(id), nil
+	return helpers.Import(sellers, options.DryRun, options.Mode != domain.ImportBestEffort, check, s.repository.SaveAll, options.Progress)
 }
 
-func (s *service) Update(id int, seller domain.UpdateSeller) (*domain.Seller, error) {
-	sellerFound := s.repository.Get(id)
+func (s *service) checkNew(seller domain.Seller, taken func(cid int) bool, localityFound func(id int) bool) error {
+	if taken(seller.CID) {
+		return apperr.NewResourceAlreadyExists(ResourceAlreadyExists, seller.CID)
+	}
 
-	if sellerFound == nil {
-		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	if !localityFound(seller.LocalityID) {
+		return apperr.NewDependentResourceNotFound(locality.LocalityNotFound, seller.LocalityID)
 	}
 
-	if seller.CID != nil {
-		sellerCID := *seller.CID
-		sellerCodeExists := s.repository.Exists(sellerCID)
+	return nil
+}
 
-		if sellerCodeExists && sellerCID != sellerFound.CID {
-			return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, sellerCID)
-		}
+func (s *service) localityExists(id int) bool {
+	return s.localityRepository.Get(id) != nil
+}
+
+// Update replaces current, the seller as the caller read it, with seller. It
+// fails when the seller was changed since it was read.
+func (s *service) Update(current domain.Seller, seller domain.Seller) (*domain.Seller, error) {
+	sellerCID := seller.CID
+	sellerCodeExists := s.repository.Exists(sellerCID)
+
+	if sellerCodeExists && sellerCID != current.CID {
+		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, sellerCID)
 	}
 
-	sellerFound.Overlap(seller)
+	seller.ID = current.ID
+	seller.Version = current.Version
 
-	localityFound := s.localityRepository.Get(sellerFound.LocalityID)
+	localityFound := s.localityRepository.Get(seller.LocalityID)
 
 	if localityFound == nil {
-		return nil, apperr.NewDependentResourceNotFound(locality.LocalityNotFound, sellerFound.LocalityID)
+		return nil, apperr.NewDependentResourceNotFound(locality.LocalityNotFound, seller.LocalityID)
 	}
 
-	s.repository.Update(*sellerFound)
-	return s.repository.Get(id), nil
+	if err := s.repository.Update(seller); errors.Is(err, ErrModified) {
+		return nil, apperr.NewIncompatibleResource(ResourceModified, seller.ID)
+	}
+	return s.repository.Get(seller.ID), nil
 }
 
 func (s *service) Delete(id int) error {
@@ -122,40 +122,15 @@ func TestServiceGet(t *testing.T) {
 }
 
 func TestServiceUpdate(t *testing.T) {
-	t.Run("Should return a not found error", func(t *testing.T) {
-		service, repository, _ := CreateService(t)
-
-		id := 2
-		cid := 123
-		updateSeller := domain.UpdateSeller{
-			ID:  &id,
-			CID: &cid,
-		}
-
-		var respositoryResult *domain.Seller
-
-		repository.On("Get", id).Return(respositoryResult)
-		result, err := service.Update(id, updateSeller)
-
-		assert.Error(t, err)
-		assert.Nil(t, result)
-		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
-	})
-
 	t.Run("Should return a conflict error when cid already exists", func(t *testing.T) {
 		service, repository, _ := CreateService(t)
 
 		mockedSeller := mockedSellerTemplate
-		id := 1
-		cid := 456
-		updateSeller := domain.UpdateSeller{
-			ID:  &id,
-			CID: &cid,
-		}
+		updateSeller := mockedSeller
+		updateSeller.CID = 456
 
-		repository.On("Get", id).Return(&mockedSeller)
-		repository.On("Exists", cid).Return(true)
-		result, err := service.Update(id, updateSeller)
+		repository.On("Exists", updateSeller.CID).Return(true)
+		result, err := service.Update(mockedSeller, updateSeller)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -166,55 +141,55 @@ func TestServiceUpdate(t *testing.T) {
 		service, repository, localityRepository := CreateService(t)
 
 		mockedSeller := mockedSellerTemplate
-		id := 1
-		cid := 456
-		localityId := 1
-		updateSeller := domain.UpdateSeller{
-			ID:         &id,
-			CID:        &cid,
-			LocalityID: &localityId,
-		}
+		updateSeller := mockedSeller
+		updateSeller.CID = 456
 
 		var localityRepositoryGetResult *domain.Locality
 
-		repository.On("Get", id).Return(&mockedSeller)
-		repository.On("Exists", cid).Return(false)
-		localityRepository.On("Get", localityId).Return(localityRepositoryGetResult)
-		result, err := service.Update(id, updateSeller)
+		repository.On("Exists", updateSeller.CID).Return(false)
+		localityRepository.On("Get", updateSeller.LocalityID).Return(localityRepositoryGetResult)
+		result, err := service.Update(mockedSeller, updateSeller)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
 	})
 
+	t.Run("Should return a conflict error when the seller was modified since it was read", func(t *testing.T) {
+		service, repository, localityRepository := CreateService(t)
+
+		mockedSeller := mockedSellerTemplate
+		mockedSeller.Version = 3
+		mockedLocality := mockedLocalityTemplate
+		updatedSeller := mockedSeller
+		updatedSeller.CompanyName = "Company Name 2"
+
+		repository.On("Exists", updatedSeller.CID).Return(true)
+		localityRepository.On("Get", updatedSeller.LocalityID).Return(&mockedLocality)
+		repository.On("Update", updatedSeller).Return(seller.ErrModified)
+		result, err := service.Update(mockedSeller, updatedSeller)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+
 	t.Run("Should return an updated seller", func(t *testing.T) {
 		service, repository, localityRepository := CreateService(t)
 
 		mockedSeller := mockedSellerTemplate
 		mockedLocality := mockedLocalityTemplate
-		id := 1
-		cid := 123
-		companyName := "Company Name 2"
-		localityId := 1
-		updateSeller := domain.UpdateSeller{
-			ID:          &id,
-			CID:         &cid,
-			CompanyName: &companyName,
-			LocalityID:  &localityId,
-		}
 		updatedSeller := mockedSeller
-		updatedSeller.Overlap(updateSeller)
+		updatedSeller.CompanyName = "Company Name 2"
 
-		repository.On("Get", id).Return(&mockedSeller)
-		repository.On("Exists", cid).Return(true)
-		localityRepository.On("Get", localityId).Return(&mockedLocality)
-		repository.On("Update", updatedSeller)
-		repository.On("Get", id).Return(&updatedSeller)
-		result, err := service.Update(id, updateSeller)
+		repository.On("Exists", updatedSeller.CID).Return(true)
+		localityRepository.On("Get", updatedSeller.LocalityID).Return(&mockedLocality)
+		repository.On("Update", updatedSeller).Return(nil)
+		repository.On("Get", mockedSeller.ID).Return(&updatedSeller)
+		result, err := service.Update(mockedSeller, updatedSeller)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, companyName, result.CompanyName)
+		assert.Equal(t, updatedSeller.CompanyName, result.CompanyName)
 	})
 }
 
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Repository struct {
+	mock.Mock
+}
+
+func (r *Repository) Report(filter domain.StockFilter) []domain.StockReport {
+	args := r.Called(filter)
+	return args.Get(0).([]domain.StockReport)
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Service struct {
+	mock.Mock
+}
+
+func (s *Service) Report(filter domain.StockFilter) []domain.StockReport {
+	args := s.Called(filter)
+	return args.Get(0).([]domain.StockReport)
+}
+
+func (s *Service) ReportByProduct(productID int) ([]domain.StockReport, error) {
+	args := s.Called(productID)
+	return args.Get(0).([]domain.StockReport), args.Error(1)
+}
@@ -0,0 +1,58 @@
+package stock
+
+import (
+	"database/sql"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+)
+
+const (
+	ReportQuery = `SELECT pb.product_id, p.product_code, s.warehouse_id, pb.section_id, s.section_number,
+	SUM(pb.current_quantity) "quantity",
+	SUM(CASE WHEN pb.due_date < NOW() THEN pb.current_quantity ELSE 0 END) "expired",
+	SUM(CASE WHEN pb.due_date >= NOW() AND pb.due_date < NOW() + INTERVAL 7 DAY THEN pb.current_quantity ELSE 0 END) "due_in_7_days",
+	SUM(CASE WHEN pb.due_date >= NOW() + INTERVAL 7 DAY AND pb.due_date < NOW() + INTERVAL 30 DAY THEN pb.current_quantity ELSE 0 END) "due_in_30_days",
+	SUM(CASE WHEN pb.due_date >= NOW() + INTERVAL 30 DAY THEN pb.current_quantity ELSE 0 END) "due_later"
+	FROM product_batches pb
+	INNER JOIN products p ON p.id = pb.product_id
+	INNER JOIN sections s ON s.id = pb.section_id
+	WHERE pb.status IN ('available', 'released') AND (? IS NULL OR pb.product_id = ?) AND (? IS NULL OR p.id_seller = ?) AND (? IS NULL OR p.id_product_type = ?) AND (? IS NULL OR s.warehouse_id = ?)
+	GROUP BY pb.product_id, p.product_code, s.warehouse_id, pb.section_id, s.section_number
+	HAVING SUM(pb.current_quantity) > 0
+	ORDER BY pb.product_id, s.warehouse_id, pb.section_id`
+)
+
+type Repository interface {
+	Report(filter domain.StockFilter) []domain.StockReport
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// Report sums the current quantity of the batches by product, warehouse and
+// section. Each filter is sent twice so that a nil value disables it.
+func (r *repository) Report(filter domain.StockFilter) []domain.StockReport {
+	rows, err := r.db.Query(ReportQuery,
+		filter.ProductID, filter.ProductID,
+		filter.SellerID, filter.SellerID,
+		filter.ProductTypeID, filter.ProductTypeID,
+		filter.WarehouseID, filter.WarehouseID)
+	if err != nil {
+		panic(err)
+	}
+	reports := make([]domain.StockReport, 0)
+
+	for rows.Next() {
+		sr := domain.StockReport{}
+		_ = rows.Scan(&sr.ProductID, &sr.ProductCode, &sr.WarehouseID, &sr.SectionID, &sr.SectionNumber, &sr.Quantity, &sr.Expired, &sr.DueIn7Days, &sr.DueIn30Days, &sr.DueLater)
+		reports = append(reports, sr)
+	}
+	return reports
+}
@@ -0,0 +1,54 @@
+package stock_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/stock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryReport(t *testing.T) {
+	t.Run("Should return the stock grouped by section", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		columns := []string{"product_id", "product_code", "warehouse_id", "section_id", "section_number", "quantity", "expired", "due_in_7_days", "due_in_30_days", "due_later"}
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, "P001", 1, 1, 10, 200, 0, 50, 50, 100).
+			AddRow(1, "P001", 2, 3, 30, 20, 20, 0, 0, 0)
+
+		sellerID := 2
+		mock.ExpectQuery(regexp.QuoteMeta(stock.ReportQuery)).
+			WithArgs(nil, nil, sellerID, sellerID, nil, nil, nil, nil).
+			WillReturnRows(rows)
+
+		repository := stock.NewRepository(db)
+		result := repository.Report(domain.StockFilter{SellerID: &sellerID})
+
+		assert.Equal(t, []domain.StockReport{
+			{ProductID: 1, ProductCode: "P001", WarehouseID: 1, SectionID: 1, SectionNumber: 10, Quantity: 200, DueIn7Days: 50, DueIn30Days: 50, DueLater: 100},
+			{ProductID: 1, ProductCode: "P001", WarehouseID: 2, SectionID: 3, SectionNumber: 30, Quantity: 20, Expired: 20},
+		}, result)
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(stock.ReportQuery)).WillReturnError(sql.ErrConnDone)
+		repository := stock.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Report(domain.StockFilter{}) })
+	})
+}
+
+func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	return db, mock
+}
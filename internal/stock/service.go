@@ -0,0 +1,40 @@
+package stock
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+)
+
+const (
+	ProductNotFound = "product.not_found"
+)
+
+type Service interface {
+	Report(filter domain.StockFilter) []domain.StockReport
+	ReportByProduct(productID int) ([]domain.StockReport, error)
+}
+
+type service struct {
+	repository        Repository
+	productRepository product.Repository
+}
+
+func NewService(repository Repository, productRepository product.Repository) Service {
+	return &service{
+		repository,
+		productRepository,
+	}
+}
+
+func (s *service) Report(filter domain.StockFilter) []domain.StockReport {
+	return s.repository.Report(filter)
+}
+
+func (s *service) ReportByProduct(productID int) ([]domain.StockReport, error) {
+	if s.productRepository.Get(productID) == nil {
+		return nil, apperr.NewResourceNotFound(ProductNotFound, productID)
+	}
+
+	return s.repository.Report(domain.StockFilter{ProductID: &productID}), nil
+}
@@ -0,0 +1,67 @@
+package stock_test
+
+import (
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	product_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/product/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/stock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/stock/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	stockReports = []domain.StockReport{
+		{ProductID: 1, ProductCode: "P001", WarehouseID: 1, SectionID: 1, SectionNumber: 10, Quantity: 200, DueLater: 200},
+	}
+)
+
+func TestServiceReport(t *testing.T) {
+	t.Run("Should return the stock for the filter", func(t *testing.T) {
+		service, repository, _ := CreateService(t)
+
+		productTypeID := 1
+		filter := domain.StockFilter{ProductTypeID: &productTypeID}
+		repository.On("Report", filter).Return(stockReports)
+
+		result := service.Report(filter)
+
+		assert.Equal(t, stockReports, result)
+	})
+}
+
+func TestServiceReportByProduct(t *testing.T) {
+	t.Run("Should return the stock of the product", func(t *testing.T) {
+		service, repository, productRepository := CreateService(t)
+
+		productID := 1
+		productRepository.On("Get", productID).Return(&domain.Product{ID: productID})
+		repository.On("Report", domain.StockFilter{ProductID: &productID}).Return(stockReports)
+
+		result, err := service.ReportByProduct(productID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, stockReports, result)
+	})
+	t.Run("Should return not found error when the product does not exist", func(t *testing.T) {
+		service, _, productRepository := CreateService(t)
+
+		var productFound *domain.Product
+		productRepository.On("Get", 1).Return(productFound)
+
+		result, err := service.ReportByProduct(1)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func CreateService(t *testing.T) (stock.Service, *mocks.Repository, *product_mocks.Repository) {
+	t.Helper()
+	repository := new(mocks.Repository)
+	productRepository := new(product_mocks.Repository)
+	service := stock.NewService(repository, productRepository)
+
+	return service, repository, productRepository
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Repository struct {
+	mock.Mock
+}
+
+func (r *Repository) BatchMinimums(sectionID int) []domain.ProductBatch {
+	args := r.Called(sectionID)
+	return args.Get(0).([]domain.ProductBatch)
+}
+
+func (r *Repository) Save(sectionID int, readings []domain.TemperatureReading, breaches []domain.TemperatureBreach) []domain.TemperatureBreach {
+	args := r.Called(sectionID, readings, breaches)
+	return args.Get(0).([]domain.TemperatureBreach)
+}
+
+func (r *Repository) Readings(sectionID int, from time.Time, to time.Time) []domain.TemperatureReading {
+	args := r.Called(sectionID, from, to)
+	return args.Get(0).([]domain.TemperatureReading)
+}
+
+func (r *Repository) Breaches(sectionID int) []domain.TemperatureBreach {
+	args := r.Called(sectionID)
+	return args.Get(0).([]domain.TemperatureBreach)
+}
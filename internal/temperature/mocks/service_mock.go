@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Service struct {
+	mock.Mock
+}
+
+func (s *Service) Record(sectionID int, readings []domain.TemperatureReading) (*domain.TemperatureIngestion, error) {
+	args := s.Called(sectionID, readings)
+	return args.Get(0).(*domain.TemperatureIngestion), args.Error(1)
+}
+
+func (s *Service) Samples(sectionID int, from time.Time, to time.Time, interval time.Duration) ([]domain.TemperatureSample, error) {
+	args := s.Called(sectionID, from, to, interval)
+	return args.Get(0).([]domain.TemperatureSample), args.Error(1)
+}
+
+func (s *Service) Breaches(sectionID int) ([]domain.TemperatureBreach, error) {
+	args := s.Called(sectionID)
+	return args.Get(0).([]domain.TemperatureBreach), args.Error(1)
+}
@@ -0,0 +1,135 @@
+package temperature
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+)
+
+const (
+	BatchMinimumsQuery   = "SELECT id, minimum_temperature FROM product_batches WHERE section_id = ? AND status <> ? AND current_quantity > 0 ORDER BY id"
+	InsertReadingQuery   = "INSERT INTO temperature_readings (section_id, temperature, recorded_at) VALUES (?, ?, ?)"
+	InsertBreachQuery    = "INSERT INTO temperature_breaches (section_id, product_batch_id, temperature, minimum_temperature, recorded_at) VALUES (?, ?, ?, ?, ?)"
+	SyncSectionTempQuery = "UPDATE sections SET last_temperature = (SELECT temperature FROM temperature_readings WHERE section_id = ? ORDER BY recorded_at DESC, id DESC LIMIT 1) WHERE id = ?"
+	SyncBatchesTempQuery = "UPDATE product_batches SET current_temperature = (SELECT temperature FROM temperature_readings WHERE section_id = ? ORDER BY recorded_at DESC, id DESC LIMIT 1) WHERE section_id = ?"
+	GetReadingsQuery     = "SELECT id, section_id, temperature, recorded_at FROM temperature_readings WHERE section_id = ? AND recorded_at >= ? AND recorded_at < ? ORDER BY recorded_at, id"
+	GetBreachesQuery     = "SELECT id, section_id, product_batch_id, temperature, minimum_temperature, recorded_at FROM temperature_breaches WHERE section_id = ? ORDER BY recorded_at, id"
+)
+
+type Repository interface {
+	BatchMinimums(sectionID int) []domain.ProductBatch
+	Save(sectionID int, readings []domain.TemperatureReading, breaches []domain.TemperatureBreach) []domain.TemperatureBreach
+	Readings(sectionID int, from time.Time, to time.Time) []domain.TemperatureReading
+	Breaches(sectionID int) []domain.TemperatureBreach
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// BatchMinimums returns the batches stored in the section with only their id
+// and minimum temperature filled. Disposed and empty batches are left out, as
+// there is no stock left in them to breach.
+func (r *repository) BatchMinimums(sectionID int) []domain.ProductBatch {
+	rows, err := r.db.Query(BatchMinimumsQuery, sectionID, domain.BatchDisposed)
+	if err != nil {
+		panic(err)
+	}
+	batches := make([]domain.ProductBatch, 0)
+
+	for rows.Next() {
+		pb := domain.ProductBatch{SectionID: sectionID}
+		_ = rows.Scan(&pb.ID, &pb.MinimumTemperature)
+		batches = append(batches, pb)
+	}
+	return batches
+}
+
+// Save stores the readings and breaches of the section, with a
+// SectionTemperatureBreached event for each breach, then sets the last
+// temperature of the section and the current temperature of its batches to the
+// latest reading, all in the same transaction. The current temperature of the
+// section is left as configured, since placement is checked against it. It returns the breaches with their ids.
+func (r *repository) Save(sectionID int, readings []domain.TemperatureReading, breaches []domain.TemperatureBreach) []domain.TemperatureBreach {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	for _, reading := range readings {
+		if _, err := tx.Exec(InsertReadingQuery, sectionID, reading.Temperature, helpers.ToFormattedDateTime(reading.RecordedAt)); err != nil {
+			panic(err)
+		}
+	}
+
+	saved := make([]domain.TemperatureBreach, 0, len(breaches))
+	for _, b := range breaches {
+		res, err := tx.Exec(InsertBreachQuery, sectionID, b.ProductBatchID, b.Temperature, b.MinimumTemperature, helpers.ToFormattedDateTime(b.RecordedAt))
+		if err != nil {
+			panic(err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			panic(err)
+		}
+		b.ID = int(id)
+		outbox.Record(tx, domain.EventSectionTemperatureBreached, sectionID, b)
+		saved = append(saved, b)
+	}
+
+	if _, err := tx.Exec(SyncSectionTempQuery, sectionID, sectionID); err != nil {
+		panic(err)
+	}
+	if _, err := tx.Exec(SyncBatchesTempQuery, sectionID, sectionID); err != nil {
+		panic(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return saved
+}
+
+func (r *repository) Readings(sectionID int, from time.Time, to time.Time) []domain.TemperatureReading {
+	rows, err := r.db.Query(GetReadingsQuery, sectionID, helpers.ToFormattedDateTime(from), helpers.ToFormattedDateTime(to))
+	if err != nil {
+		panic(err)
+	}
+	readings := make([]domain.TemperatureReading, 0)
+
+	for rows.Next() {
+		tr := domain.TemperatureReading{}
+		var recordedAt string
+		_ = rows.Scan(&tr.ID, &tr.SectionID, &tr.Temperature, &recordedAt)
+		tr.RecordedAt = helpers.ToDateTime(recordedAt)
+		readings = append(readings, tr)
+	}
+	return readings
+}
+
+func (r *repository) Breaches(sectionID int) []domain.TemperatureBreach {
+	rows, err := r.db.Query(GetBreachesQuery, sectionID)
+	if err != nil {
+		panic(err)
+	}
+	breaches := make([]domain.TemperatureBreach, 0)
+
+	for rows.Next() {
+		b := domain.TemperatureBreach{}
+		var recordedAt string
+		_ = rows.Scan(&b.ID, &b.SectionID, &b.ProductBatchID, &b.Temperature, &b.MinimumTemperature, &recordedAt)
+		b.RecordedAt = helpers.ToDateTime(recordedAt)
+		breaches = append(breaches, b)
+	}
+	return breaches
+}
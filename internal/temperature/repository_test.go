@@ -0,0 +1,167 @@
+package temperature_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/outbox"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/temperature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryBatchMinimums(t *testing.T) {
+	t.Run("Should return the minimum temperature of the batches with stock in the section", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "minimum_temperature"}).
+			AddRow(1, -25).
+			AddRow(2, -15)
+		mock.ExpectQuery(regexp.QuoteMeta(temperature.BatchMinimumsQuery)).WithArgs(1, domain.BatchDisposed).WillReturnRows(rows)
+
+		repository := temperature.NewRepository(db)
+		result := repository.BatchMinimums(1)
+
+		assert.Equal(t, sectionBatches, result)
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(temperature.BatchMinimumsQuery)).WillReturnError(sql.ErrConnDone)
+		repository := temperature.NewRepository(db)
+
+		assert.Panics(t, func() { repository.BatchMinimums(1) })
+	})
+}
+
+func TestRepositorySave(t *testing.T) {
+	readings := []domain.TemperatureReading{{SectionID: 1, Temperature: -22, RecordedAt: recordedAt}}
+	batchID := 2
+	breaches := []domain.TemperatureBreach{
+		{SectionID: 1, Temperature: -22, MinimumTemperature: -20, RecordedAt: recordedAt},
+		{SectionID: 1, ProductBatchID: &batchID, Temperature: -22, MinimumTemperature: -15, RecordedAt: recordedAt},
+	}
+
+	t.Run("Should store the readings and breaches and sync the last temperature", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(temperature.InsertReadingQuery)).
+			WithArgs(1, float32(-22), "2023-07-10 08:00:00").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(temperature.InsertBreachQuery)).
+			WithArgs(1, nil, float32(-22), float32(-20), "2023-07-10 08:00:00").
+			WillReturnResult(sqlmock.NewResult(5, 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.InsertQuery)).
+			WithArgs(domain.EventSectionTemperatureBreached, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(temperature.InsertBreachQuery)).
+			WithArgs(1, batchID, float32(-22), float32(-15), "2023-07-10 08:00:00").
+			WillReturnResult(sqlmock.NewResult(6, 1))
+		mock.ExpectExec(regexp.QuoteMeta(outbox.InsertQuery)).
+			WithArgs(domain.EventSectionTemperatureBreached, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(regexp.QuoteMeta(temperature.SyncSectionTempQuery)).
+			WithArgs(1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(temperature.SyncBatchesTempQuery)).
+			WithArgs(1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		repository := temperature.NewRepository(db)
+		result := repository.Save(1, readings, breaches)
+
+		assert.Len(t, result, 2)
+		assert.Equal(t, 5, result[0].ID)
+		assert.Equal(t, 6, result[1].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic and rollback when a reading can not be stored", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(temperature.InsertReadingQuery)).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		repository := temperature.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Save(1, readings, breaches) })
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryReadings(t *testing.T) {
+	from := recordedAt
+	to := recordedAt.Add(time.Hour)
+
+	t.Run("Should return the readings of the period", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "section_id", "temperature", "recorded_at"}).
+			AddRow(1, 1, -10, "2023-07-10 08:01:00")
+		mock.ExpectQuery(regexp.QuoteMeta(temperature.GetReadingsQuery)).
+			WithArgs(1, "2023-07-10 08:00:00", "2023-07-10 09:00:00").
+			WillReturnRows(rows)
+
+		repository := temperature.NewRepository(db)
+		result := repository.Readings(1, from, to)
+
+		assert.Equal(t, []domain.TemperatureReading{{ID: 1, SectionID: 1, Temperature: -10, RecordedAt: from.Add(time.Minute)}}, result)
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(temperature.GetReadingsQuery)).WillReturnError(sql.ErrConnDone)
+		repository := temperature.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Readings(1, from, to) })
+	})
+}
+
+func TestRepositoryBreaches(t *testing.T) {
+	t.Run("Should return the breaches of the section", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		batchID := 2
+		rows := sqlmock.NewRows([]string{"id", "section_id", "product_batch_id", "temperature", "minimum_temperature", "recorded_at"}).
+			AddRow(1, 1, nil, -22, -20, "2023-07-10 08:00:00").
+			AddRow(2, 1, batchID, -22, -15, "2023-07-10 08:00:00")
+		mock.ExpectQuery(regexp.QuoteMeta(temperature.GetBreachesQuery)).WithArgs(1).WillReturnRows(rows)
+
+		repository := temperature.NewRepository(db)
+		result := repository.Breaches(1)
+
+		assert.Equal(t, []domain.TemperatureBreach{
+			{ID: 1, SectionID: 1, Temperature: -22, MinimumTemperature: -20, RecordedAt: recordedAt},
+			{ID: 2, SectionID: 1, ProductBatchID: &batchID, Temperature: -22, MinimumTemperature: -15, RecordedAt: recordedAt},
+		}, result)
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(temperature.GetBreachesQuery)).WillReturnError(sql.ErrConnDone)
+		repository := temperature.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Breaches(1) })
+	})
+}
+
+func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	return db, mock
+}
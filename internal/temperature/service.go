@@ -0,0 +1,122 @@
+package temperature
+
+import (
+	"math"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+)
+
+const (
+	SectionNotFound = "section.not_found"
+)
+
+type Service interface {
+	Record(sectionID int, readings []domain.TemperatureReading) (*domain.TemperatureIngestion, error)
+	Samples(sectionID int, from time.Time, to time.Time, interval time.Duration) ([]domain.TemperatureSample, error)
+	Breaches(sectionID int) ([]domain.TemperatureBreach, error)
+}
+
+type service struct {
+	repository        Repository
+	sectionRepository section.Repository
+}
+
+func NewService(repository Repository, sectionRepository section.Repository) Service {
+	return &service{
+		repository,
+		sectionRepository,
+	}
+}
+
+// Record stores the readings of a section and raises a breach for each
+// reading below the minimum temperature of the section or of one of its
+// batches.
+func (s *service) Record(sectionID int, readings []domain.TemperatureReading) (*domain.TemperatureIngestion, error) {
+	sectionFound := s.sectionRepository.Get(sectionID)
+
+	if sectionFound == nil {
+		return nil, apperr.NewResourceNotFound(SectionNotFound, sectionID)
+	}
+
+	batches := s.repository.BatchMinimums(sectionID)
+	breaches := make([]domain.TemperatureBreach, 0)
+
+	for _, reading := range readings {
+		if reading.Temperature < sectionFound.MinimumTemperature {
+			breaches = append(breaches, domain.TemperatureBreach{
+				SectionID:          sectionID,
+				Temperature:        reading.Temperature,
+				MinimumTemperature: sectionFound.MinimumTemperature,
+				RecordedAt:         reading.RecordedAt,
+			})
+		}
+		for _, pb := range batches {
+			if reading.Temperature < pb.MinimumTemperature {
+				batchID := pb.ID
+				breaches = append(breaches, domain.TemperatureBreach{
+					SectionID:          sectionID,
+					ProductBatchID:     &batchID,
+					Temperature:        reading.Temperature,
+					MinimumTemperature: pb.MinimumTemperature,
+					RecordedAt:         reading.RecordedAt,
+				})
+			}
+		}
+	}
+
+	return &domain.TemperatureIngestion{
+		Recorded: len(readings),
+		Breaches: s.repository.Save(sectionID, readings, breaches),
+	}, nil
+}
+
+// Samples returns the readings of the section between from and to. When
+// interval is positive, readings are downsampled into one sample per
+// interval, aligned on from; otherwise each reading is its own sample.
+func (s *service) Samples(sectionID int, from time.Time, to time.Time, interval time.Duration) ([]domain.TemperatureSample, error) {
+	if s.sectionRepository.Get(sectionID) == nil {
+		return nil, apperr.NewResourceNotFound(SectionNotFound, sectionID)
+	}
+
+	samples := make([]domain.TemperatureSample, 0)
+	sums := make([]float64, 0)
+
+	for _, reading := range s.repository.Readings(sectionID, from, to) {
+		start := reading.RecordedAt
+		if interval > 0 {
+			start = from.Add(reading.RecordedAt.Sub(from) / interval * interval)
+		}
+
+		if n := len(samples); n == 0 || !samples[n-1].From.Equal(start) {
+			samples = append(samples, domain.TemperatureSample{From: start, Minimum: reading.Temperature, Maximum: reading.Temperature})
+			sums = append(sums, 0)
+		}
+
+		i := len(samples) - 1
+		samples[i].Count++
+		sums[i] += float64(reading.Temperature)
+		if reading.Temperature < samples[i].Minimum {
+			samples[i].Minimum = reading.Temperature
+		}
+		if reading.Temperature > samples[i].Maximum {
+			samples[i].Maximum = reading.Temperature
+		}
+	}
+
+	for i := range samples {
+		samples[i].Average = math.Round(sums[i]/float64(samples[i].Count)*100) / 100
+	}
+
+	return samples, nil
+}
+
+func (s *service) Breaches(sectionID int) ([]domain.TemperatureBreach, error) {
+	if s.sectionRepository.Get(sectionID) == nil {
+		return nil, apperr.NewResourceNotFound(SectionNotFound, sectionID)
+	}
+
+	return s.repository.Breaches(sectionID), nil
+}
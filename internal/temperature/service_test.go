@@ -0,0 +1,163 @@
+package temperature_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	section_mocks "github.com/extmatperez/meli_bootcamp_go_w2-1/internal/section/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/temperature"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/temperature/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	recordedAt     = time.Date(2023, 7, 10, 8, 0, 0, 0, time.UTC)
+	coldSection    = &domain.Section{ID: 1, SectionNumber: 10, CurrentTemperature: -10, MinimumTemperature: -20}
+	sectionBatches = []domain.ProductBatch{
+		{ID: 1, SectionID: 1, MinimumTemperature: -25},
+		{ID: 2, SectionID: 1, MinimumTemperature: -15},
+	}
+)
+
+func TestServiceRecord(t *testing.T) {
+	t.Run("Should record the readings without breaches", func(t *testing.T) {
+		service, repository, sectionRepository := CreateService(t)
+
+		readings := []domain.TemperatureReading{{SectionID: 1, Temperature: -12, RecordedAt: recordedAt}}
+		sectionRepository.On("Get", 1).Return(coldSection)
+		repository.On("BatchMinimums", 1).Return(sectionBatches)
+		repository.On("Save", 1, readings, []domain.TemperatureBreach{}).Return([]domain.TemperatureBreach{})
+
+		result, err := service.Record(1, readings)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.TemperatureIngestion{Recorded: 1, Breaches: []domain.TemperatureBreach{}}, result)
+	})
+	t.Run("Should raise breaches for the section and its batches", func(t *testing.T) {
+		service, repository, sectionRepository := CreateService(t)
+
+		readings := []domain.TemperatureReading{
+			{SectionID: 1, Temperature: -18, RecordedAt: recordedAt},
+			{SectionID: 1, Temperature: -22, RecordedAt: recordedAt.Add(time.Minute)},
+		}
+		secondBatch := 2
+		breaches := []domain.TemperatureBreach{
+			{SectionID: 1, ProductBatchID: &secondBatch, Temperature: -18, MinimumTemperature: -15, RecordedAt: recordedAt},
+			{SectionID: 1, Temperature: -22, MinimumTemperature: -20, RecordedAt: recordedAt.Add(time.Minute)},
+			{SectionID: 1, ProductBatchID: &secondBatch, Temperature: -22, MinimumTemperature: -15, RecordedAt: recordedAt.Add(time.Minute)},
+		}
+		saved := []domain.TemperatureBreach{
+			{ID: 1, SectionID: 1, ProductBatchID: &secondBatch, Temperature: -18, MinimumTemperature: -15, RecordedAt: recordedAt},
+			{ID: 2, SectionID: 1, Temperature: -22, MinimumTemperature: -20, RecordedAt: recordedAt.Add(time.Minute)},
+			{ID: 3, SectionID: 1, ProductBatchID: &secondBatch, Temperature: -22, MinimumTemperature: -15, RecordedAt: recordedAt.Add(time.Minute)},
+		}
+		sectionRepository.On("Get", 1).Return(coldSection)
+		repository.On("BatchMinimums", 1).Return(sectionBatches)
+		repository.On("Save", 1, readings, breaches).Return(saved)
+
+		result, err := service.Record(1, readings)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Recorded)
+		assert.Equal(t, saved, result.Breaches)
+	})
+	t.Run("Should return not found error when the section does not exist", func(t *testing.T) {
+		service, repository, sectionRepository := CreateService(t)
+
+		var sectionFound *domain.Section
+		sectionRepository.On("Get", 1).Return(sectionFound)
+
+		result, err := service.Record(1, []domain.TemperatureReading{{SectionID: 1, Temperature: -18, RecordedAt: recordedAt}})
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+		repository.AssertNotCalled(t, "Save")
+	})
+}
+
+func TestServiceSamples(t *testing.T) {
+	from := recordedAt
+	to := recordedAt.Add(time.Hour)
+	readings := []domain.TemperatureReading{
+		{ID: 1, SectionID: 1, Temperature: -10, RecordedAt: from.Add(1 * time.Minute)},
+		{ID: 2, SectionID: 1, Temperature: -12, RecordedAt: from.Add(5 * time.Minute)},
+		{ID: 3, SectionID: 1, Temperature: -11, RecordedAt: from.Add(14 * time.Minute)},
+		{ID: 4, SectionID: 1, Temperature: -15, RecordedAt: from.Add(31 * time.Minute)},
+	}
+
+	t.Run("Should downsample the readings by interval", func(t *testing.T) {
+		service, repository, sectionRepository := CreateService(t)
+
+		sectionRepository.On("Get", 1).Return(coldSection)
+		repository.On("Readings", 1, from, to).Return(readings)
+
+		result, err := service.Samples(1, from, to, 15*time.Minute)
+
+		expected := []domain.TemperatureSample{
+			{From: from, Average: -11, Minimum: -12, Maximum: -10, Count: 3},
+			{From: from.Add(30 * time.Minute), Average: -15, Minimum: -15, Maximum: -15, Count: 1},
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+	t.Run("Should return one sample per reading without interval", func(t *testing.T) {
+		service, repository, sectionRepository := CreateService(t)
+
+		sectionRepository.On("Get", 1).Return(coldSection)
+		repository.On("Readings", 1, from, to).Return(readings)
+
+		result, err := service.Samples(1, from, to, 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, len(readings))
+		assert.Equal(t, domain.TemperatureSample{From: readings[1].RecordedAt, Average: -12, Minimum: -12, Maximum: -12, Count: 1}, result[1])
+	})
+	t.Run("Should return not found error when the section does not exist", func(t *testing.T) {
+		service, _, sectionRepository := CreateService(t)
+
+		var sectionFound *domain.Section
+		sectionRepository.On("Get", 1).Return(sectionFound)
+
+		result, err := service.Samples(1, from, to, 0)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func TestServiceBreaches(t *testing.T) {
+	t.Run("Should return the breaches of the section", func(t *testing.T) {
+		service, repository, sectionRepository := CreateService(t)
+
+		breaches := []domain.TemperatureBreach{{ID: 1, SectionID: 1, Temperature: -22, MinimumTemperature: -20, RecordedAt: recordedAt}}
+		sectionRepository.On("Get", 1).Return(coldSection)
+		repository.On("Breaches", 1).Return(breaches)
+
+		result, err := service.Breaches(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, breaches, result)
+	})
+	t.Run("Should return not found error when the section does not exist", func(t *testing.T) {
+		service, _, sectionRepository := CreateService(t)
+
+		var sectionFound *domain.Section
+		sectionRepository.On("Get", 1).Return(sectionFound)
+
+		result, err := service.Breaches(1)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+	})
+}
+
+func CreateService(t *testing.T) (temperature.Service, *mocks.Repository, *section_mocks.Repository) {
+	t.Helper()
+	repository := new(mocks.Repository)
+	sectionRepository := new(section_mocks.Repository)
+	service := temperature.NewService(repository, sectionRepository)
+
+	return service, repository, sectionRepository
+}
@@ -29,10 +29,16 @@ func (r *Repository) Save(warehouse domain.Warehouse) int {
 	return args.Get(0).(int)
 }
 
-func (r *Repository) Update(warehouse domain.Warehouse) {
-	r.Called(warehouse)
+func (r *Repository) Update(warehouse domain.Warehouse) error {
+	args := r.Called(warehouse)
+	return args.Error(0)
 }
 
 func (r *Repository) Delete(id int) {
 	r.Called(id)
 }
+
+func (r *Repository) GetMany(ids []int) []domain.Warehouse {
+	args := r.Called(ids)
+	return args.Get(0).([]domain.Warehouse)
+}
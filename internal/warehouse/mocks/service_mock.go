@@ -24,8 +24,8 @@ func (s *Service) Create(w domain.Warehouse) (*domain.Warehouse, error) {
 	return args.Get(0).(*domain.Warehouse), args.Error(1)
 }
 
-func (s *Service) Update(id int, w domain.UpdateWarehouse) (*domain.Warehouse, error) {
-	args := s.Called(id, w)
+func (s *Service) Update(current domain.Warehouse, w domain.Warehouse) (*domain.Warehouse, error) {
+	args := s.Called(current, w)
 	return args.Get(0).(*domain.Warehouse), args.Error(1)
 }
 
@@ -33,3 +33,8 @@ func (s *Service) Delete(id int) error {
 	args := s.Called(id)
 	return args.Error(0)
 }
+
+func (s *Service) GetMany(ids []int) []domain.Warehouse {
+	args := s.Called(ids)
+	return args.Get(0).([]domain.Warehouse)
+}
@@ -2,28 +2,36 @@ package warehouse
 
 import (
 	"database/sql"
+	"errors"
 
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 )
 
 const (
-	GetAllQuery = "SELECT id, address, telephone, warehouse_code, minimum_capacity, minimum_temperature, locality_id FROM warehouses"
-	GetQuery    = "SELECT id, address, telephone, warehouse_code, minimum_capacity, minimum_temperature, locality_id FROM warehouses WHERE id=?"
-	ExistsQuery = "SELECT warehouse_code FROM warehouses WHERE warehouse_code=?"
-	InsertQuery = "INSERT INTO warehouses (address, telephone, warehouse_code, minimum_capacity, minimum_temperature, locality_id) VALUES (?, ?, ?, ?, ?,?)"
-	UpdateQuery = "UPDATE warehouses SET address=?, telephone=?, warehouse_code=?, minimum_capacity=?, minimum_temperature=?, locality_id=? WHERE id=?"
-	DeleteQuery = "DELETE FROM warehouses WHERE id=?"
+	GetAllQuery  = "SELECT id, address, telephone, warehouse_code, minimum_capacity, minimum_temperature, locality_id FROM warehouses"
+	GetQuery     = "SELECT id, address, telephone, warehouse_code, minimum_capacity, minimum_temperature, locality_id, version FROM warehouses WHERE id=?"
+	GetManyQuery = "SELECT id, address, telephone, warehouse_code, minimum_capacity, minimum_temperature, locality_id FROM warehouses WHERE id IN (%s)"
+	ExistsQuery  = "SELECT warehouse_code FROM warehouses WHERE warehouse_code=?"
+	InsertQuery  = "INSERT INTO warehouses (address, telephone, warehouse_code, minimum_capacity, minimum_temperature, locality_id) VALUES (?, ?, ?, ?, ?,?)"
+	UpdateQuery  = "UPDATE warehouses SET address=?, telephone=?, warehouse_code=?, minimum_capacity=?, minimum_temperature=?, locality_id=?, version=version+1 WHERE id=? AND version=?"
+	DeleteQuery  = "DELETE FROM warehouses WHERE id=?"
 )
 
 type Repository interface {
 	GetAll() []domain.Warehouse
 	Get(id int) *domain.Warehouse
+	GetMany(ids []int) []domain.Warehouse
 	Exists(warehouseCode string) bool
 	Save(w domain.Warehouse) int
-	Update(w domain.Warehouse)
+	Update(w domain.Warehouse) error
 	Delete(id int)
 }
 
+// ErrModified is returned by Update when the warehouse was changed or deleted
+// after the version being updated was read.
+var ErrModified = errors.New("warehouse modified since read")
+
 type repository struct {
 	db *sql.DB
 }
@@ -54,7 +62,7 @@ func (r *repository) GetAll() []domain.Warehouse {
 func (r *repository) Get(id int) *domain.Warehouse {
 	row := r.db.QueryRow(GetQuery, id)
 	w := domain.Warehouse{}
-	err := row.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityID)
+	err := row.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityID, &w.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil
@@ -65,6 +73,30 @@ func (r *repository) Get(id int) *domain.Warehouse {
 	return &w
 }
 
+// GetMany returns the warehouses found among ids, in no particular order.
+func (r *repository) GetMany(ids []int) []domain.Warehouse {
+	warehouses := make([]domain.Warehouse, 0, len(ids))
+	if len(ids) == 0 {
+		return warehouses
+	}
+
+	query, args := helpers.ExpandIn(GetManyQuery, ids)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		w := domain.Warehouse{}
+		if err := rows.Scan(&w.ID, &w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityID); err != nil {
+			panic(err)
+		}
+		warehouses = append(warehouses, w)
+	}
+	return warehouses
+}
+
 func (r *repository) Exists(warehouseCode string) bool {
 	row := r.db.QueryRow(ExistsQuery, warehouseCode)
 	err := row.Scan(&warehouseCode)
@@ -90,16 +122,25 @@ func (r *repository) Save(w domain.Warehouse) int {
 	return int(id)
 }
 
-func (r *repository) Update(w domain.Warehouse) {
+func (r *repository) Update(w domain.Warehouse) error {
 	stmt, err := r.db.Prepare(UpdateQuery)
 	if err != nil {
 		panic(err)
 	}
 
-	_, err = stmt.Exec(&w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityID, &w.ID)
+	res, err := stmt.Exec(&w.Address, &w.Telephone, &w.WarehouseCode, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityID, &w.ID, &w.Version)
 	if err != nil {
 		panic(err)
 	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	if affected == 0 {
+		return ErrModified
+	}
+	return nil
 }
 
 func (r *repository) Delete(id int) {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/warehouse"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -42,10 +43,10 @@ func TestRepositoryGet(t *testing.T) {
 		db, mock := SetupMock(t)
 		defer db.Close()
 
-		columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "locality_id"}
+		columns := []string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "locality_id", "version"}
 		rows := sqlmock.NewRows(columns)
 		warehouseId := 1
-		rows.AddRow(warehouseId, "address", "telephone", "warehouse_code", 1, 1, 1)
+		rows.AddRow(warehouseId, "address", "telephone", "warehouse_code", 1, 1, 1, 2)
 
 		mock.ExpectQuery(warehouse.GetQuery).WithArgs(warehouseId).WillReturnRows(rows)
 
@@ -54,6 +55,7 @@ func TestRepositoryGet(t *testing.T) {
 		result := repository.Get(warehouseId)
 
 		assert.NotNil(t, result)
+		assert.Equal(t, 2, result.Version)
 	})
 
 	t.Run("Should not return a warehouse", func(t *testing.T) {
@@ -211,12 +213,27 @@ func TestRepositoryUpdate(t *testing.T) {
 		mockedWarehouse := mockedWarehouseTemplate
 		mock.ExpectPrepare(regexp.QuoteMeta(warehouse.UpdateQuery))
 		mock.ExpectExec(regexp.QuoteMeta(warehouse.UpdateQuery)).
-			WithArgs(mockedWarehouse.Address, mockedWarehouse.Telephone, mockedWarehouse.WarehouseCode, mockedWarehouse.MinimumCapacity, mockedWarehouse.MinimumTemperature, mockedWarehouse.LocalityID, mockedWarehouse.ID).
+			WithArgs(mockedWarehouse.Address, mockedWarehouse.Telephone, mockedWarehouse.WarehouseCode, mockedWarehouse.MinimumCapacity, mockedWarehouse.MinimumTemperature, mockedWarehouse.LocalityID, mockedWarehouse.ID, mockedWarehouse.Version).
 			WillReturnResult(sqlmock.NewResult(int64(mockedWarehouse.ID), 1))
 
 		repository := warehouse.NewRepository(db)
 
-		assert.NotPanics(t, func() { repository.Update(mockedWarehouse) })
+		assert.NoError(t, repository.Update(mockedWarehouse))
+	})
+
+	t.Run("Should not update the warehouse when its version changed", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mockedWarehouse := mockedWarehouseTemplate
+		mock.ExpectPrepare(regexp.QuoteMeta(warehouse.UpdateQuery))
+		mock.ExpectExec(regexp.QuoteMeta(warehouse.UpdateQuery)).
+			WithArgs(mockedWarehouse.Address, mockedWarehouse.Telephone, mockedWarehouse.WarehouseCode, mockedWarehouse.MinimumCapacity, mockedWarehouse.MinimumTemperature, mockedWarehouse.LocalityID, mockedWarehouse.ID, mockedWarehouse.Version).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := warehouse.NewRepository(db)
+
+		assert.ErrorIs(t, repository.Update(mockedWarehouse), warehouse.ErrModified)
 	})
 
 	t.Run("Should throw panic when expected prepare fails", func(t *testing.T) {
@@ -291,6 +308,46 @@ func TestRepositoryDelete(t *testing.T) {
 	})
 }
 
+func TestRepositoryGetMany(t *testing.T) {
+	t.Run("Should return the warehouses found among the ids with a single query", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(warehouse.GetManyQuery, []int{2, 5})
+		rows := sqlmock.NewRows([]string{"id", "address", "telephone", "warehouse_code", "minimum_capacity", "minimum_temperature", "locality_id"}).
+			AddRow(2, "Street 1", "111", "W-2", 10, 5, 1).
+			AddRow(5, "Street 2", "222", "W-5", 10, 5, 1)
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2, 5).WillReturnRows(rows)
+
+		repository := warehouse.NewRepository(db)
+		result := repository.GetMany([]int{2, 5})
+
+		assert.Len(t, result, 2)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should return nothing without querying when there are no ids", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		repository := warehouse.NewRepository(db)
+		result := repository.GetMany(nil)
+
+		assert.Empty(t, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		query, _ := helpers.ExpandIn(warehouse.GetManyQuery, []int{2})
+		mock.ExpectQuery(regexp.QuoteMeta(query)).WithArgs(2).WillReturnError(sql.ErrConnDone)
+
+		repository := warehouse.NewRepository(db)
+
+		assert.Panics(t, func() { repository.GetMany([]int{2}) })
+	})
+}
+
 func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
 	t.Helper()
 
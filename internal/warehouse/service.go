@@ -1,22 +1,26 @@
 package warehouse
 
 import (
+	"errors"
+
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/locality"
 	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
 )
 
 const (
-	ResourceNotFound      = "armazém não encontrado com o id '%d'"
-	ResourceAlreadyExists = "já existe um armazém com o código '%s'"
-	LocalityNotFound      = "localidade não encontrada com o id '%d'"
+	ResourceNotFound      = "warehouse.not_found"
+	ResourceAlreadyExists = "warehouse.already_exists"
+	ResourceModified      = "warehouse.modified"
+	LocalityNotFound      = "locality.not_found"
 )
 
 type Service interface {
 	GetAll() []domain.Warehouse
 	Get(id int) (*domain.Warehouse, error)
+	GetMany(ids []int) []domain.Warehouse
 	Create(warehouse domain.Warehouse) (*domain.Warehouse, error)
-	Update(id int, warehouse domain.UpdateWarehouse) (*domain.Warehouse, error)
+	Update(current domain.Warehouse, warehouse domain.Warehouse) (*domain.Warehouse, error)
 	Delete(id int) error
 }
 
@@ -43,6 +47,11 @@ func (s *service) Get(id int) (*domain.Warehouse, error) {
 	return warehouse, nil
 }
 
+// GetMany returns the warehouses found among ids, leaving out the missing ones.
+func (s *service) GetMany(ids []int) []domain.Warehouse {
+	return s.repository.GetMany(ids)
+}
+
 func (s *service) Create(warehouse domain.Warehouse) (*domain.Warehouse, error) {
 	if s.repository.Exists(warehouse.WarehouseCode) {
 		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, warehouse.WarehouseCode)
@@ -58,33 +67,30 @@ func (s *service) Create(warehouse domain.Warehouse) (*domain.Warehouse, error)
 	return s.repository.Get(warehouseId), nil
 }
 
-func (s *service) Update(id int, warehouse domain.UpdateWarehouse) (*domain.Warehouse, error) {
-	warehouseFound := s.repository.Get(id)
-
-	if warehouseFound == nil {
-		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
-	}
-
-	if warehouse.WarehouseCode != nil {
-		warehouseCode := *warehouse.WarehouseCode
-		warehouseExists := s.repository.Exists(warehouseCode)
+// Update replaces current, the warehouse as the caller read it, with
+// warehouse. It fails when the warehouse was changed since it was read.
+func (s *service) Update(current domain.Warehouse, warehouse domain.Warehouse) (*domain.Warehouse, error) {
+	warehouseCode := warehouse.WarehouseCode
+	warehouseExists := s.repository.Exists(warehouseCode)
 
-		if warehouseExists && warehouseCode != warehouseFound.WarehouseCode {
-			return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, warehouseCode)
-		}
+	if warehouseExists && warehouseCode != current.WarehouseCode {
+		return nil, apperr.NewResourceAlreadyExists(ResourceAlreadyExists, warehouseCode)
 	}
 
-	warehouseFound.Overlap(warehouse)
+	warehouse.ID = current.ID
+	warehouse.Version = current.Version
 
-	locality := s.localityRepository.Get(warehouseFound.LocalityID)
+	locality := s.localityRepository.Get(warehouse.LocalityID)
 
 	if locality == nil {
-		return nil, apperr.NewDependentResourceNotFound(LocalityNotFound, warehouseFound.LocalityID)
+		return nil, apperr.NewDependentResourceNotFound(LocalityNotFound, warehouse.LocalityID)
 	}
 
-	s.repository.Update(*warehouseFound)
+	if err := s.repository.Update(warehouse); errors.Is(err, ErrModified) {
+		return nil, apperr.NewIncompatibleResource(ResourceModified, warehouse.ID)
+	}
 
-	updated := s.repository.Get(id)
+	updated := s.repository.Get(warehouse.ID)
 	return updated, nil
 }
 
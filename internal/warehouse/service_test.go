@@ -121,39 +121,15 @@ func TestServiceGet(t *testing.T) {
 }
 
 func TestServiceUpdate(t *testing.T) {
-	t.Run("Should return a not found error", func(t *testing.T) {
-		service, repository, _ := CreateService(t)
-
-		id := 2
-		warehouseCode := "153"
-		updateWarehouse := domain.UpdateWarehouse{
-			ID:            &id,
-			WarehouseCode: &warehouseCode,
-		}
-		var respositoryResult *domain.Warehouse
-
-		repository.On("Get", id).Return(respositoryResult)
-		result, err := service.Update(id, updateWarehouse)
-
-		assert.Error(t, err)
-		assert.Nil(t, result)
-		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
-	})
-
 	t.Run("Should return a conflict error", func(t *testing.T) {
 		service, repository, _ := CreateService(t)
 
-		id := 1
-		warehouseCode := "496"
-		updateWarehouse := domain.UpdateWarehouse{
-			ID:            &id,
-			WarehouseCode: &warehouseCode,
-		}
 		mockedWarehouse := mockedWarehouseTemplate
+		updateWarehouse := mockedWarehouse
+		updateWarehouse.WarehouseCode = "496"
 
-		repository.On("Get", id).Return(&mockedWarehouse)
-		repository.On("Exists", warehouseCode).Return(true)
-		result, err := service.Update(id, updateWarehouse)
+		repository.On("Exists", updateWarehouse.WarehouseCode).Return(true)
+		result, err := service.Update(mockedWarehouse, updateWarehouse)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -163,50 +139,53 @@ func TestServiceUpdate(t *testing.T) {
 	t.Run("Should return a locality dependent not found error", func(t *testing.T) {
 		service, repository, localityRepository := CreateService(t)
 
-		id := 1
-		warehouseCode := "496"
-		updateWarehouse := domain.UpdateWarehouse{
-			ID:            &id,
-			WarehouseCode: &warehouseCode,
-		}
 		mockedWarehouse := mockedWarehouseTemplate
+		updateWarehouse := mockedWarehouse
+		updateWarehouse.WarehouseCode = "496"
 		var emptyLocality *domain.Locality
 
-		repository.On("Get", id).Return(&mockedWarehouse)
-		repository.On("Exists", warehouseCode).Return(false)
+		repository.On("Exists", updateWarehouse.WarehouseCode).Return(false)
 		localityRepository.On("Get", mockedWarehouse.LocalityID).Return(emptyLocality)
-		result, err := service.Update(id, updateWarehouse)
+		result, err := service.Update(mockedWarehouse, updateWarehouse)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.True(t, apperr.Is[*apperr.DependentResourceNotFound](err))
 	})
 
+	t.Run("Should return a conflict error when the warehouse was modified since it was read", func(t *testing.T) {
+		service, repository, localityRepository := CreateService(t)
+
+		mockedWarehouse := mockedWarehouseTemplate
+		mockedWarehouse.Version = 3
+		updatedWarehouse := mockedWarehouse
+		updatedWarehouse.Address = "Address 3"
+
+		repository.On("Exists", updatedWarehouse.WarehouseCode).Return(true)
+		localityRepository.On("Get", updatedWarehouse.LocalityID).Return(&domain.Locality{})
+		repository.On("Update", updatedWarehouse).Return(warehouse.ErrModified)
+		result, err := service.Update(mockedWarehouse, updatedWarehouse)
+
+		assert.Nil(t, result)
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+	})
+
 	t.Run("Should return an updated warehouse", func(t *testing.T) {
 		service, repository, localityRepository := CreateService(t)
 
-		id := 1
-		warehouseCode := "123"
-		address := "Address 3"
-		updateWarehouse := domain.UpdateWarehouse{
-			ID:            &id,
-			Address:       &address,
-			WarehouseCode: &warehouseCode,
-		}
 		mockedWarehouse := mockedWarehouseTemplate
 		updatedWarehouse := mockedWarehouse
-		updatedWarehouse.Overlap(updateWarehouse)
+		updatedWarehouse.Address = "Address 3"
 
-		repository.On("Get", id).Return(&mockedWarehouse)
-		repository.On("Exists", warehouseCode).Return(true)
-		localityRepository.On("Get", mockedWarehouse.LocalityID).Return(&domain.Locality{})
-		repository.On("Update", updatedWarehouse)
-		repository.On("Get", id).Return(&updatedWarehouse)
-		result, err := service.Update(id, updateWarehouse)
+		repository.On("Exists", updatedWarehouse.WarehouseCode).Return(true)
+		localityRepository.On("Get", updatedWarehouse.LocalityID).Return(&domain.Locality{})
+		repository.On("Update", updatedWarehouse).Return(nil)
+		repository.On("Get", mockedWarehouse.ID).Return(&updatedWarehouse)
+		result, err := service.Update(mockedWarehouse, updatedWarehouse)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, address, result.Address)
+		assert.Equal(t, updatedWarehouse.Address, result.Address)
 	})
 }
 
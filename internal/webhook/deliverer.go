@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventTypeHeader = "X-Webhook-Event"
+	EventIDHeader   = "X-Webhook-Event-Id"
+	SignaturePrefix = "sha256="
+)
+
+// DefaultBatchSize is the number of due deliveries the deliverer claims at a
+// time.
+const DefaultBatchSize = 10
+
+// Deliverer posts the domain events to the webhooks subscribed to them. It is
+// the outbox sink of the webhooks: publishing an event only queues its
+// deliveries, which are sent by the deliverer once started.
+type Deliverer struct {
+	repository Repository
+	client     *http.Client
+	attempts   int
+	backoff    time.Duration
+	interval   time.Duration
+}
+
+// NewDeliverer returns a deliverer that polls the due deliveries at the given
+// interval and tries each one up to attempts times, waiting backoff before the
+// first retry and doubling it after each one.
+func NewDeliverer(repository Repository, client *http.Client, attempts int, backoff time.Duration, interval time.Duration) *Deliverer {
+	return &Deliverer{
+		repository: repository,
+		client:     client,
+		attempts:   attempts,
+		backoff:    backoff,
+		interval:   interval,
+	}
+}
+
+// Publish queues a delivery of the event to every webhook subscribed to its
+// type. No webhook is called, so that a partner that is down does not hold
+// back the outbox.
+func (d *Deliverer) Publish(event domain.Event) error {
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for _, w := range d.repository.GetAll() {
+		if w.Subscribes(event.Type) {
+			deliveries = append(deliveries, domain.WebhookDelivery{WebhookID: w.ID, EventID: event.ID, EventType: event.Type})
+		}
+	}
+	if len(deliveries) > 0 {
+		d.repository.Enqueue(deliveries, encode(event))
+	}
+	return nil
+}
+
+// Start sends the due deliveries at the given interval until ctx is done.
+func (d *Deliverer) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			d.deliverSafely()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// deliverSafely keeps the deliverer alive when the queue cannot be reached,
+// waiting for the next tick to try again.
+func (d *Deliverer) deliverSafely() {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("webhook deliverer: %v", r)
+		}
+	}()
+
+	for d.DeliverDue() == DefaultBatchSize {
+	}
+}
+
+// DeliverDue claims the next batch of due deliveries, tries each one once and
+// returns how many were tried. A failed delivery is tried again after its
+// backoff, until the attempts run out and it is marked as failed.
+func (d *Deliverer) DeliverDue() int {
+	claimed := d.repository.Claim(DefaultBatchSize)
+	for _, q := range claimed {
+		d.repository.SaveAttempt(d.attempt(q))
+	}
+	return len(claimed)
+}
+
+func (d *Deliverer) attempt(q Queued) domain.WebhookDelivery {
+	delivery := d.send(q.Webhook, q.Delivery.EventID, q.Delivery.EventType, q.Body)
+	delivery.ID = q.Delivery.ID
+	delivery.Attempt = q.Delivery.Attempt + 1
+	delivery.CreatedAt = q.Delivery.CreatedAt
+
+	switch {
+	case delivery.Succeeded:
+		delivery.Status = domain.WebhookDeliverySucceeded
+	case delivery.Attempt >= d.attempts:
+		delivery.Status = domain.WebhookDeliveryFailed
+	default:
+		next := time.Now().UTC().Add(d.backoff << (delivery.Attempt - 1))
+		delivery.Status = domain.WebhookDeliveryPending
+		delivery.NextAttemptAt = &next
+	}
+	return delivery
+}
+
+func encode(event domain.Event) []byte {
+	body, err := json.Marshal(event)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+func (d *Deliverer) send(w domain.Webhook, eventID int, eventType string, body []byte) domain.WebhookDelivery {
+	delivery := domain.WebhookDelivery{
+		WebhookID: w.ID,
+		EventID:   eventID,
+		EventType: eventType,
+	}
+
+	request, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		reason := err.Error()
+		delivery.Error = &reason
+		return delivery
+	}
+	timestamp := time.Now().UTC().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, Sign(w.Secret, timestamp, body))
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(EventTypeHeader, eventType)
+	request.Header.Set(EventIDHeader, strconv.Itoa(eventID))
+
+	start := time.Now()
+	response, err := d.client.Do(request)
+	delivery.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		reason := err.Error()
+		delivery.Error = &reason
+		return delivery
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	delivery.StatusCode = &response.StatusCode
+	delivery.Succeeded = response.StatusCode >= 200 && response.StatusCode < 300
+	if !delivery.Succeeded {
+		reason := fmt.Sprintf("unexpected status %d", response.StatusCode)
+		delivery.Error = &reason
+	}
+	return delivery
+}
+
+// Sign returns the signature header of the body sent at the given unix
+// timestamp: the hex encoded HMAC-SHA256 of the timestamp, a dot and the body,
+// keyed with the secret of the webhook. Signing the timestamp lets the partner
+// reject a captured request replayed later.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
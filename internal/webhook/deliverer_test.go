@@ -0,0 +1,159 @@
+package webhook_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/webhook"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/webhook/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	webhookSecret = "0123456789abcdef"
+)
+
+var (
+	createdEvent = domain.Event{ID: 9, Type: domain.EventPurchaseOrderCreated, AggregateID: 4, Payload: []byte(`{"id":4}`)}
+)
+
+func TestDelivererPublish(t *testing.T) {
+	t.Run("Should queue the event for the subscribed webhooks only", func(t *testing.T) {
+		repository := new(mocks.Repository)
+		deliverer := webhook.NewDeliverer(repository, http.DefaultClient, 3, time.Millisecond, time.Second)
+
+		repository.On("GetAll").Return([]domain.Webhook{
+			{ID: 1, URL: "https://partner.example/hooks", EventTypes: []string{domain.EventPurchaseOrderCreated}, Secret: webhookSecret},
+			{ID: 2, URL: "https://partner.example/other", EventTypes: []string{domain.EventInboundOrderReceived}, Secret: webhookSecret},
+		})
+		repository.On("Enqueue", []domain.WebhookDelivery{{WebhookID: 1, EventID: 9, EventType: domain.EventPurchaseOrderCreated}}, mock.MatchedBy(func(body []byte) bool {
+			return strings.Contains(string(body), `"payload":{"id":4}`)
+		})).Return([]int{1})
+
+		assert.NoError(t, deliverer.Publish(createdEvent))
+
+		repository.AssertExpectations(t)
+		repository.AssertNotCalled(t, "SaveAttempt", mock.Anything)
+	})
+	t.Run("Should queue nothing when no webhook is subscribed", func(t *testing.T) {
+		repository := new(mocks.Repository)
+		deliverer := webhook.NewDeliverer(repository, http.DefaultClient, 3, time.Millisecond, time.Second)
+
+		repository.On("GetAll").Return([]domain.Webhook{})
+
+		assert.NoError(t, deliverer.Publish(createdEvent))
+
+		repository.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDelivererDeliverDue(t *testing.T) {
+	body := []byte(`{"id":9,"type":"PurchaseOrderCreated","payload":{"id":4}}`)
+	queued := func(url string, attempt int) []webhook.Queued {
+		return []webhook.Queued{{
+			Delivery: domain.WebhookDelivery{ID: 3, WebhookID: 1, EventID: 9, EventType: domain.EventPurchaseOrderCreated, Status: domain.WebhookDeliveryPending, Attempt: attempt},
+			Webhook:  domain.Webhook{ID: 1, URL: url, Secret: webhookSecret},
+			Body:     body,
+		}}
+	}
+
+	t.Run("Should post the signed body and mark the delivery as succeeded", func(t *testing.T) {
+		var received []byte
+		var signature, timestamp, eventType, eventID string
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ = io.ReadAll(r.Body)
+			signature = r.Header.Get(webhook.SignatureHeader)
+			timestamp = r.Header.Get(webhook.TimestampHeader)
+			eventType = r.Header.Get(webhook.EventTypeHeader)
+			eventID = r.Header.Get(webhook.EventIDHeader)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		repository := new(mocks.Repository)
+		deliverer := webhook.NewDeliverer(repository, receiver.Client(), 3, time.Millisecond, time.Second)
+		repository.On("Claim", webhook.DefaultBatchSize).Return(queued(receiver.URL, 0))
+		repository.On("SaveAttempt", mock.Anything).Return()
+
+		assert.Equal(t, 1, deliverer.DeliverDue())
+
+		assert.Equal(t, body, received)
+		sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+		assert.NoError(t, err)
+		assert.Equal(t, webhook.Sign(webhookSecret, sentAt, body), signature)
+		assert.Equal(t, domain.EventPurchaseOrderCreated, eventType)
+		assert.Equal(t, "9", eventID)
+		repository.AssertCalled(t, "SaveAttempt", mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.ID == 3 && d.Attempt == 1 && d.Succeeded && d.Status == domain.WebhookDeliverySucceeded && *d.StatusCode == http.StatusNoContent && d.NextAttemptAt == nil
+		}))
+	})
+	t.Run("Should schedule the next attempt after the backoff when the webhook rejects the event", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer receiver.Close()
+
+		repository := new(mocks.Repository)
+		deliverer := webhook.NewDeliverer(repository, receiver.Client(), 5, time.Hour, time.Second)
+		repository.On("Claim", webhook.DefaultBatchSize).Return(queued(receiver.URL, 1))
+		repository.On("SaveAttempt", mock.Anything).Return()
+
+		before := time.Now().UTC()
+		deliverer.DeliverDue()
+
+		repository.AssertCalled(t, "SaveAttempt", mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.Attempt == 2 && !d.Succeeded && d.Status == domain.WebhookDeliveryPending && *d.Error == "unexpected status 503" &&
+				!d.NextAttemptAt.Before(before.Add(2*time.Hour))
+		}))
+	})
+	t.Run("Should mark the delivery as failed once the attempts run out", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer receiver.Close()
+
+		repository := new(mocks.Repository)
+		deliverer := webhook.NewDeliverer(repository, receiver.Client(), 2, time.Millisecond, time.Second)
+		repository.On("Claim", webhook.DefaultBatchSize).Return(queued(receiver.URL, 1))
+		repository.On("SaveAttempt", mock.Anything).Return()
+
+		deliverer.DeliverDue()
+
+		repository.AssertCalled(t, "SaveAttempt", mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return d.Attempt == 2 && d.Status == domain.WebhookDeliveryFailed && d.NextAttemptAt == nil
+		}))
+	})
+	t.Run("Should log the error when the webhook can not be reached", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := receiver.URL
+		receiver.Close()
+
+		repository := new(mocks.Repository)
+		deliverer := webhook.NewDeliverer(repository, http.DefaultClient, 3, time.Millisecond, time.Second)
+		repository.On("Claim", webhook.DefaultBatchSize).Return(queued(url, 0))
+		repository.On("SaveAttempt", mock.Anything).Return()
+
+		deliverer.DeliverDue()
+
+		repository.AssertCalled(t, "SaveAttempt", mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+			return !d.Succeeded && d.StatusCode == nil && d.Error != nil && d.Status == domain.WebhookDeliveryPending
+		}))
+	})
+}
+
+func TestSign(t *testing.T) {
+	t.Run("Should return the hex encoded HMAC-SHA256 of the timestamp and the body", func(t *testing.T) {
+		assert.Equal(t, "sha256=b5ce146e983fcb40307d9aca28aa944cbd6d943e6782cca2518d3e88653f4938", webhook.Sign("key", 1689000000, []byte("The quick brown fox jumps over the lazy dog")))
+	})
+	t.Run("Should change with the timestamp", func(t *testing.T) {
+		body := []byte("The quick brown fox jumps over the lazy dog")
+		assert.NotEqual(t, webhook.Sign("key", 1689000000, body), webhook.Sign("key", 1689000001, body))
+	})
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/webhook"
+	"github.com/stretchr/testify/mock"
+)
+
+type Repository struct {
+	mock.Mock
+}
+
+func (r *Repository) GetAll() []domain.Webhook {
+	args := r.Called()
+	return args.Get(0).([]domain.Webhook)
+}
+
+func (r *Repository) Get(id int) *domain.Webhook {
+	args := r.Called(id)
+	return args.Get(0).(*domain.Webhook)
+}
+
+func (r *Repository) Save(w domain.Webhook) int {
+	args := r.Called(w)
+	return args.Int(0)
+}
+
+func (r *Repository) Update(w domain.Webhook) error {
+	args := r.Called(w)
+	return args.Error(0)
+}
+
+func (r *Repository) Delete(id int) {
+	r.Called(id)
+}
+
+func (r *Repository) Enqueue(deliveries []domain.WebhookDelivery, body []byte) []int {
+	args := r.Called(deliveries, body)
+	return args.Get(0).([]int)
+}
+
+func (r *Repository) Claim(limit int) []webhook.Queued {
+	args := r.Called(limit)
+	return args.Get(0).([]webhook.Queued)
+}
+
+func (r *Repository) SaveAttempt(d domain.WebhookDelivery) {
+	r.Called(d)
+}
+
+func (r *Repository) Requeue(id int) {
+	r.Called(id)
+}
+
+func (r *Repository) GetDelivery(id int) *domain.WebhookDelivery {
+	args := r.Called(id)
+	return args.Get(0).(*domain.WebhookDelivery)
+}
+
+func (r *Repository) Deliveries(webhookID int) []domain.WebhookDelivery {
+	args := r.Called(webhookID)
+	return args.Get(0).([]domain.WebhookDelivery)
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type Service struct {
+	mock.Mock
+}
+
+func (s *Service) GetAll() []domain.Webhook {
+	args := s.Called()
+	return args.Get(0).([]domain.Webhook)
+}
+
+func (s *Service) Get(id int) (*domain.Webhook, error) {
+	args := s.Called(id)
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (s *Service) Create(w domain.Webhook) *domain.Webhook {
+	args := s.Called(w)
+	return args.Get(0).(*domain.Webhook)
+}
+
+func (s *Service) Update(current domain.Webhook, w domain.Webhook) (*domain.Webhook, error) {
+	args := s.Called(current, w)
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (s *Service) Delete(id int) error {
+	args := s.Called(id)
+	return args.Error(0)
+}
+
+func (s *Service) Deliveries(id int) ([]domain.WebhookDelivery, error) {
+	args := s.Called(id)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (s *Service) Redeliver(deliveryID int) (*domain.WebhookDelivery, error) {
+	args := s.Called(deliveryID)
+	return args.Get(0).(*domain.WebhookDelivery), args.Error(1)
+}
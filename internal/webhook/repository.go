@@ -0,0 +1,295 @@
+package webhook
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/helpers"
+)
+
+const (
+	GetAllQuery        = "SELECT id, url, event_types, secret, created_at FROM webhooks ORDER BY id"
+	GetQuery           = "SELECT id, url, event_types, secret, created_at, version FROM webhooks WHERE id = ?"
+	InsertQuery        = "INSERT INTO webhooks (url, event_types, secret, created_at) VALUES (?, ?, ?, ?)"
+	UpdateQuery        = "UPDATE webhooks SET url = ?, event_types = ?, secret = ?, version = version + 1 WHERE id = ? AND version = ?"
+	DeleteQuery        = "DELETE FROM webhooks WHERE id = ?"
+	EnqueueQuery       = "INSERT IGNORE INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	DueQuery           = "SELECT d.id, d.webhook_id, d.event_id, d.event_type, d.attempt, d.payload, d.created_at, w.url, w.secret FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id WHERE d.status = ? AND d.next_attempt_at <= ? ORDER BY d.next_attempt_at, d.id LIMIT ? FOR UPDATE OF d SKIP LOCKED"
+	ClaimQuery         = "UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ?"
+	SaveAttemptQuery   = "UPDATE webhook_deliveries SET status = ?, attempt = ?, status_code = ?, latency_ms = ?, error = ?, succeeded = ?, next_attempt_at = ? WHERE id = ?"
+	RequeueQuery       = "UPDATE webhook_deliveries SET status = ?, attempt = 0, status_code = NULL, latency_ms = 0, error = NULL, succeeded = 0, next_attempt_at = ? WHERE id = ? AND status <> ?"
+	GetDeliveryQuery   = "SELECT id, webhook_id, event_id, event_type, status, attempt, status_code, latency_ms, error, succeeded, next_attempt_at, created_at FROM webhook_deliveries WHERE id = ?"
+	GetDeliveriesQuery = "SELECT id, webhook_id, event_id, event_type, status, attempt, status_code, latency_ms, error, succeeded, next_attempt_at, created_at FROM webhook_deliveries WHERE webhook_id = ? ORDER BY id DESC"
+
+	eventTypesSeparator = ","
+)
+
+// ClaimLease is how long a claimed delivery is kept from the other deliverers
+// while it is being sent.
+const ClaimLease = 5 * time.Minute
+
+// Queued is a pending delivery claimed to be sent, with the webhook it goes to
+// and the body to post.
+type Queued struct {
+	Delivery domain.WebhookDelivery
+	Webhook  domain.Webhook
+	Body     []byte
+}
+
+// ErrModified is returned by Update when the webhook was changed or deleted
+// after the version being updated was read.
+var ErrModified = errors.New("webhook modified since read")
+
+// rowScanner is the part of sql.Row and sql.Rows the rows are read with.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type Repository interface {
+	GetAll() []domain.Webhook
+	Get(id int) *domain.Webhook
+	Save(w domain.Webhook) int
+	Update(w domain.Webhook) error
+	Delete(id int)
+	Enqueue(deliveries []domain.WebhookDelivery, body []byte) []int
+	Claim(limit int) []Queued
+	SaveAttempt(d domain.WebhookDelivery)
+	Requeue(id int)
+	GetDelivery(id int) *domain.WebhookDelivery
+	Deliveries(webhookID int) []domain.WebhookDelivery
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetAll() []domain.Webhook {
+	rows, err := r.db.Query(GetAllQuery)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]domain.Webhook, 0)
+	for rows.Next() {
+		w := domain.Webhook{}
+		var eventTypes, createdAt string
+		if err := rows.Scan(&w.ID, &w.URL, &eventTypes, &w.Secret, &createdAt); err != nil {
+			panic(err)
+		}
+		w.EventTypes = strings.Split(eventTypes, eventTypesSeparator)
+		w.CreatedAt = helpers.ToDateTime(createdAt)
+		webhooks = append(webhooks, w)
+	}
+	return webhooks
+}
+
+func (r *repository) Get(id int) *domain.Webhook {
+	w := domain.Webhook{}
+	var eventTypes, createdAt string
+
+	err := r.db.QueryRow(GetQuery, id).Scan(&w.ID, &w.URL, &eventTypes, &w.Secret, &createdAt, &w.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		panic(err)
+	}
+	w.EventTypes = strings.Split(eventTypes, eventTypesSeparator)
+	w.CreatedAt = helpers.ToDateTime(createdAt)
+
+	return &w
+}
+
+func (r *repository) Save(w domain.Webhook) int {
+	res, err := r.db.Exec(InsertQuery, w.URL, strings.Join(w.EventTypes, eventTypesSeparator), w.Secret, helpers.ToFormattedDateTime(time.Now().UTC()))
+	if err != nil {
+		panic(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		panic(err)
+	}
+	return int(id)
+}
+
+func (r *repository) Update(w domain.Webhook) error {
+	res, err := r.db.Exec(UpdateQuery, w.URL, strings.Join(w.EventTypes, eventTypesSeparator), w.Secret, w.ID, w.Version)
+	if err != nil {
+		panic(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	if affected == 0 {
+		return ErrModified
+	}
+	return nil
+}
+
+// Delete removes the webhook along with its deliveries.
+func (r *repository) Delete(id int) {
+	if _, err := r.db.Exec(DeleteQuery, id); err != nil {
+		panic(err)
+	}
+}
+
+// Enqueue stores the deliveries of the body as pending, due at once, and
+// returns the ids of the new ones. They are stored together, so that either
+// every webhook gets the event or none does. A webhook that already has a
+// delivery of the event is skipped, as the outbox may publish an event more
+// than once.
+func (r *repository) Enqueue(deliveries []domain.WebhookDelivery, body []byte) []int {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	now := helpers.ToFormattedDateTime(time.Now().UTC())
+	ids := make([]int, 0, len(deliveries))
+	for _, d := range deliveries {
+		res, err := tx.Exec(EnqueueQuery, d.WebhookID, d.EventID, d.EventType, body, domain.WebhookDeliveryPending, now, now)
+		if err != nil {
+			panic(err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			panic(err)
+		}
+		if affected == 0 {
+			continue
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			panic(err)
+		}
+		ids = append(ids, int(id))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Claim returns the pending deliveries whose next attempt is due, oldest
+// first, and postpones them by ClaimLease so that no other deliverer sends
+// them meanwhile. Rows locked by other deliverers are skipped.
+func (r *repository) Claim(limit int) []Queued {
+	tx, err := r.db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	rows, err := tx.Query(DueQuery, domain.WebhookDeliveryPending, helpers.ToFormattedDateTime(now), limit)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	claimed := make([]Queued, 0)
+	for rows.Next() {
+		q := Queued{Delivery: domain.WebhookDelivery{Status: domain.WebhookDeliveryPending}}
+		var createdAt string
+		if err := rows.Scan(&q.Delivery.ID, &q.Delivery.WebhookID, &q.Delivery.EventID, &q.Delivery.EventType, &q.Delivery.Attempt, &q.Body, &createdAt, &q.Webhook.URL, &q.Webhook.Secret); err != nil {
+			panic(err)
+		}
+		q.Delivery.CreatedAt = helpers.ToDateTime(createdAt)
+		q.Webhook.ID = q.Delivery.WebhookID
+		claimed = append(claimed, q)
+	}
+
+	lease := helpers.ToFormattedDateTime(now.Add(ClaimLease))
+	for _, q := range claimed {
+		if _, err := tx.Exec(ClaimQuery, lease, q.Delivery.ID); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(err)
+	}
+	return claimed
+}
+
+// SaveAttempt stores the outcome of the last attempt of the delivery, along
+// with its status and when it is tried next.
+func (r *repository) SaveAttempt(d domain.WebhookDelivery) {
+	var nextAttemptAt *string
+	if d.NextAttemptAt != nil {
+		formatted := helpers.ToFormattedDateTime(*d.NextAttemptAt)
+		nextAttemptAt = &formatted
+	}
+	if _, err := r.db.Exec(SaveAttemptQuery, d.Status, d.Attempt, d.StatusCode, d.LatencyMs, d.Error, d.Succeeded, nextAttemptAt, d.ID); err != nil {
+		panic(err)
+	}
+}
+
+// Requeue makes the delivery pending again, due at once and with its attempts
+// reset. A delivery still pending is left as it is, so that one being sent is
+// not claimed twice.
+func (r *repository) Requeue(id int) {
+	now := helpers.ToFormattedDateTime(time.Now().UTC())
+	if _, err := r.db.Exec(RequeueQuery, domain.WebhookDeliveryPending, now, id, domain.WebhookDeliveryPending); err != nil {
+		panic(err)
+	}
+}
+
+func (r *repository) GetDelivery(id int) *domain.WebhookDelivery {
+	d, err := scanDelivery(r.db.QueryRow(GetDeliveryQuery, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		panic(err)
+	}
+	return &d
+}
+
+// Deliveries returns the delivery log of the webhook, newest first.
+func (r *repository) Deliveries(webhookID int) []domain.WebhookDelivery {
+	rows, err := r.db.Query(GetDeliveriesQuery, webhookID)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			panic(err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries
+}
+
+func scanDelivery(row rowScanner) (domain.WebhookDelivery, error) {
+	d := domain.WebhookDelivery{}
+	var createdAt string
+	var nextAttemptAt *string
+	if err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Status, &d.Attempt, &d.StatusCode, &d.LatencyMs, &d.Error, &d.Succeeded, &nextAttemptAt, &createdAt); err != nil {
+		return d, err
+	}
+	if nextAttemptAt != nil {
+		t := helpers.ToDateTime(*nextAttemptAt)
+		d.NextAttemptAt = &t
+	}
+	d.CreatedAt = helpers.ToDateTime(createdAt)
+	return d, nil
+}
@@ -0,0 +1,211 @@
+package webhook_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryGetAll(t *testing.T) {
+	t.Run("Should return the webhooks with their event types", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta(webhook.GetAllQuery)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "url", "event_types", "secret", "created_at"}).
+				AddRow(1, "https://partner.example/hooks", "PurchaseOrderCreated,PurchaseOrderStatusChanged", webhookSecret, "2023-07-10 08:00:00.000000"))
+
+		repository := webhook.NewRepository(db)
+		result := repository.GetAll()
+
+		assert.Len(t, result, 1)
+		assert.Equal(t, []string{domain.EventPurchaseOrderCreated, domain.EventPurchaseOrderStatusChanged}, result[0].EventTypes)
+		assert.Equal(t, webhookSecret, result[0].Secret)
+	})
+}
+
+func TestRepositorySave(t *testing.T) {
+	t.Run("Should store the event types joined by commas", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(webhook.InsertQuery)).
+			WithArgs("https://partner.example/hooks", "PurchaseOrderCreated,InboundOrderReceived", webhookSecret, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		repository := webhook.NewRepository(db)
+		result := repository.Save(domain.Webhook{URL: "https://partner.example/hooks", EventTypes: []string{domain.EventPurchaseOrderCreated, domain.EventInboundOrderReceived}, Secret: webhookSecret})
+
+		assert.Equal(t, 1, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryUpdate(t *testing.T) {
+	t.Run("Should update the webhook at the version it was read", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(webhook.UpdateQuery)).
+			WithArgs("https://partner.example/hooks", "PurchaseOrderCreated", webhookSecret, 1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := webhook.NewRepository(db)
+		err := repository.Update(domain.Webhook{ID: 1, URL: "https://partner.example/hooks", EventTypes: []string{domain.EventPurchaseOrderCreated}, Secret: webhookSecret, Version: 3})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should not update the webhook when its version changed", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(webhook.UpdateQuery)).
+			WithArgs("https://partner.example/hooks", "PurchaseOrderCreated", webhookSecret, 1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		repository := webhook.NewRepository(db)
+		err := repository.Update(domain.Webhook{ID: 1, URL: "https://partner.example/hooks", EventTypes: []string{domain.EventPurchaseOrderCreated}, Secret: webhookSecret, Version: 3})
+
+		assert.ErrorIs(t, err, webhook.ErrModified)
+	})
+}
+
+func TestRepositoryEnqueue(t *testing.T) {
+	t.Run("Should store the deliveries as pending in one transaction", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		body := []byte(`{"id":9}`)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(webhook.EnqueueQuery)).
+			WithArgs(1, 9, domain.EventPurchaseOrderCreated, body, domain.WebhookDeliveryPending, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectExec(regexp.QuoteMeta(webhook.EnqueueQuery)).
+			WithArgs(2, 9, domain.EventPurchaseOrderCreated, body, domain.WebhookDeliveryPending, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectCommit()
+
+		repository := webhook.NewRepository(db)
+		result := repository.Enqueue([]domain.WebhookDelivery{
+			{WebhookID: 1, EventID: 9, EventType: domain.EventPurchaseOrderCreated},
+			{WebhookID: 2, EventID: 9, EventType: domain.EventPurchaseOrderCreated},
+		}, body)
+
+		assert.Equal(t, []int{3, 4}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should skip the webhooks that already have a delivery of the event", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		body := []byte(`{"id":9}`)
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(webhook.EnqueueQuery)).
+			WithArgs(1, 9, domain.EventPurchaseOrderCreated, body, domain.WebhookDeliveryPending, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(webhook.EnqueueQuery)).
+			WithArgs(2, 9, domain.EventPurchaseOrderCreated, body, domain.WebhookDeliveryPending, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(4, 1))
+		mock.ExpectCommit()
+
+		repository := webhook.NewRepository(db)
+		result := repository.Enqueue([]domain.WebhookDelivery{
+			{WebhookID: 1, EventID: 9, EventType: domain.EventPurchaseOrderCreated},
+			{WebhookID: 2, EventID: 9, EventType: domain.EventPurchaseOrderCreated},
+		}, body)
+
+		assert.Equal(t, []int{4}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("Should throw panic and store none when query execution fail", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(webhook.EnqueueQuery)).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		repository := webhook.NewRepository(db)
+
+		assert.Panics(t, func() { repository.Enqueue([]domain.WebhookDelivery{{}}, nil) })
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryRequeue(t *testing.T) {
+	t.Run("Should make the delivery pending again unless it still is", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(webhook.RequeueQuery)).
+			WithArgs(domain.WebhookDeliveryPending, sqlmock.AnyArg(), 3, domain.WebhookDeliveryPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := webhook.NewRepository(db)
+		repository.Requeue(3)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositoryClaim(t *testing.T) {
+	t.Run("Should return the due deliveries with their webhook and postpone them", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(webhook.DueQuery)).
+			WithArgs(domain.WebhookDeliveryPending, sqlmock.AnyArg(), 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "event_id", "event_type", "attempt", "payload", "created_at", "url", "secret"}).
+				AddRow(3, 1, 9, domain.EventPurchaseOrderCreated, 1, []byte(`{"id":9}`), "2023-07-10 08:00:00.000000", "https://partner.example/hooks", webhookSecret))
+		mock.ExpectExec(regexp.QuoteMeta(webhook.ClaimQuery)).
+			WithArgs(sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repository := webhook.NewRepository(db)
+		result := repository.Claim(10)
+
+		assert.Len(t, result, 1)
+		assert.Equal(t, 3, result[0].Delivery.ID)
+		assert.Equal(t, 1, result[0].Delivery.Attempt)
+		assert.Equal(t, 1, result[0].Webhook.ID)
+		assert.Equal(t, webhookSecret, result[0].Webhook.Secret)
+		assert.Equal(t, []byte(`{"id":9}`), result[0].Body)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepositorySaveAttempt(t *testing.T) {
+	t.Run("Should store the outcome and the next attempt of the delivery", func(t *testing.T) {
+		db, mock := SetupMock(t)
+		defer db.Close()
+
+		status := 503
+		reason := "unexpected status 503"
+		next := time.Date(2023, 7, 10, 8, 0, 2, 0, time.UTC)
+		mock.ExpectExec(regexp.QuoteMeta(webhook.SaveAttemptQuery)).
+			WithArgs(domain.WebhookDeliveryPending, 2, &status, int64(12), &reason, false, "2023-07-10 08:00:02", 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		repository := webhook.NewRepository(db)
+		repository.SaveAttempt(domain.WebhookDelivery{ID: 3, Status: domain.WebhookDeliveryPending, Attempt: 2, StatusCode: &status, LatencyMs: 12, Error: &reason, NextAttemptAt: &next})
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func SetupMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	return db, mock
+}
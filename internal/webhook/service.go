@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"errors"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+)
+
+const (
+	ResourceNotFound = "webhook.not_found"
+	DeliveryNotFound = "webhook.delivery_not_found"
+	ResourceModified = "webhook.modified"
+)
+
+type Service interface {
+	GetAll() []domain.Webhook
+	Get(id int) (*domain.Webhook, error)
+	Create(w domain.Webhook) *domain.Webhook
+	Update(current domain.Webhook, w domain.Webhook) (*domain.Webhook, error)
+	Delete(id int) error
+	Deliveries(id int) ([]domain.WebhookDelivery, error)
+	Redeliver(deliveryID int) (*domain.WebhookDelivery, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &service{repository}
+}
+
+func (s *service) GetAll() []domain.Webhook {
+	return s.repository.GetAll()
+}
+
+func (s *service) Get(id int) (*domain.Webhook, error) {
+	webhookFound := s.repository.Get(id)
+
+	if webhookFound == nil {
+		return nil, apperr.NewResourceNotFound(ResourceNotFound, id)
+	}
+
+	return webhookFound, nil
+}
+
+func (s *service) Create(w domain.Webhook) *domain.Webhook {
+	return s.repository.Get(s.repository.Save(w))
+}
+
+// Update replaces current, the webhook as the caller read it, with w. It fails
+// when the webhook was changed since it was read.
+func (s *service) Update(current domain.Webhook, w domain.Webhook) (*domain.Webhook, error) {
+	w.ID = current.ID
+	w.Version = current.Version
+
+	if err := s.repository.Update(w); errors.Is(err, ErrModified) {
+		return nil, apperr.NewIncompatibleResource(ResourceModified, w.ID)
+	}
+	return s.repository.Get(w.ID), nil
+}
+
+func (s *service) Delete(id int) error {
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+
+	s.repository.Delete(id)
+	return nil
+}
+
+func (s *service) Deliveries(id int) ([]domain.WebhookDelivery, error) {
+	if _, err := s.Get(id); err != nil {
+		return nil, err
+	}
+
+	return s.repository.Deliveries(id), nil
+}
+
+// Redeliver queues the delivery to be posted to its webhook once more, with
+// the current URL and secret of the webhook, and returns it pending. Each event
+// has a single delivery per webhook, so the one delivered before is queued
+// again rather than a new one.
+func (s *service) Redeliver(deliveryID int) (*domain.WebhookDelivery, error) {
+	if s.repository.GetDelivery(deliveryID) == nil {
+		return nil, apperr.NewResourceNotFound(DeliveryNotFound, deliveryID)
+	}
+
+	s.repository.Requeue(deliveryID)
+	return s.repository.GetDelivery(deliveryID), nil
+}
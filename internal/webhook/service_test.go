@@ -0,0 +1,76 @@
+package webhook_test
+
+import (
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/domain"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/webhook"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/internal/webhook/mocks"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServiceUpdate(t *testing.T) {
+	t.Run("Should update the webhook at the version it was read", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		current := domain.Webhook{ID: 1, URL: "https://partner.example/hooks", Version: 3}
+		updated := domain.Webhook{ID: 1, URL: "https://partner.example/v2/hooks", Version: 3}
+		repository.On("Update", updated).Return(nil)
+		repository.On("Get", 1).Return(&updated)
+
+		result, err := service.Update(current, domain.Webhook{URL: updated.URL})
+
+		assert.NoError(t, err)
+		assert.Equal(t, updated.URL, result.URL)
+	})
+	t.Run("Should return conflict error when the webhook was modified since it was read", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		current := domain.Webhook{ID: 1, URL: "https://partner.example/hooks", Version: 3}
+		repository.On("Update", domain.Webhook{ID: 1, URL: "https://partner.example/v2/hooks", Version: 3}).Return(webhook.ErrModified)
+
+		_, err := service.Update(current, domain.Webhook{URL: "https://partner.example/v2/hooks"})
+
+		assert.True(t, apperr.Is[*apperr.IncompatibleResource](err))
+		repository.AssertNotCalled(t, "Get", mock.Anything)
+	})
+}
+
+func TestServiceRedeliver(t *testing.T) {
+	t.Run("Should queue the delivery once more", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		repository.On("GetDelivery", 3).Return(&domain.WebhookDelivery{ID: 3, WebhookID: 1, EventID: 9, Status: domain.WebhookDeliveryFailed, Attempt: 5}).Once()
+		repository.On("Requeue", 3).Return()
+		repository.On("GetDelivery", 3).Return(&domain.WebhookDelivery{ID: 3, WebhookID: 1, EventID: 9, Status: domain.WebhookDeliveryPending})
+
+		result, err := service.Redeliver(3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.ID)
+		assert.Equal(t, domain.WebhookDeliveryPending, result.Status)
+		assert.Equal(t, 0, result.Attempt)
+		repository.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+	t.Run("Should return not found error when the delivery does not exist", func(t *testing.T) {
+		service, repository := CreateService(t)
+
+		var delivery *domain.WebhookDelivery
+		repository.On("GetDelivery", 3).Return(delivery)
+
+		_, err := service.Redeliver(3)
+
+		assert.True(t, apperr.Is[*apperr.ResourceNotFound](err))
+		repository.AssertNotCalled(t, "Requeue", mock.Anything)
+	})
+}
+
+func CreateService(t *testing.T) (webhook.Service, *mocks.Repository) {
+	t.Helper()
+	repository := new(mocks.Repository)
+	service := webhook.NewService(repository)
+
+	return service, repository
+}
@@ -2,45 +2,63 @@ package apperr
 
 import (
 	"errors"
-	"fmt"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/i18n"
+	"golang.org/x/text/language"
 )
 
-// Resource Not Found
-type ResourceNotFound struct {
-	message string
+// Localizable is implemented by errors whose message can be rendered in the
+// locale requested by the client.
+type Localizable interface {
+	Localize(locale language.Tag) string
 }
 
-func (e ResourceNotFound) Error() string {
-	return e.message
+type message struct {
+	key  string
+	args []interface{}
 }
 
-func NewResourceNotFound(message string, args ...interface{}) *ResourceNotFound {
-	return &ResourceNotFound{message: fmt.Sprintf(message, args...)}
+func (m message) Error() string {
+	return m.Localize(i18n.Default)
 }
 
-type DependentResourceNotFound struct {
-	message string
+func (m message) Localize(locale language.Tag) string {
+	return i18n.Translate(locale, m.key, m.args...)
 }
 
-func (e DependentResourceNotFound) Error() string {
-	return e.message
+// Resource Not Found
+type ResourceNotFound struct {
+	message
 }
 
-func NewDependentResourceNotFound(message string, args ...interface{}) *DependentResourceNotFound {
-	return &DependentResourceNotFound{message: fmt.Sprintf(message, args...)}
+func NewResourceNotFound(key string, args ...interface{}) *ResourceNotFound {
+	return &ResourceNotFound{message{key, args}}
+}
+
+type DependentResourceNotFound struct {
+	message
+}
+
+func NewDependentResourceNotFound(key string, args ...interface{}) *DependentResourceNotFound {
+	return &DependentResourceNotFound{message{key, args}}
 }
 
 // Resource Already Exists
 type ResourceAlreadyExists struct {
-	message string
+	message
+}
+
+func NewResourceAlreadyExists(key string, args ...interface{}) *ResourceAlreadyExists {
+	return &ResourceAlreadyExists{message{key, args}}
 }
 
-func (e ResourceAlreadyExists) Error() string {
-	return e.message
+// Incompatible Resource
+type IncompatibleResource struct {
+	message
 }
 
-func NewResourceAlreadyExists(message string, args ...interface{}) *ResourceAlreadyExists {
-	return &ResourceAlreadyExists{message: fmt.Sprintf(message, args...)}
+func NewIncompatibleResource(key string, args ...interface{}) *IncompatibleResource {
+	return &IncompatibleResource{message{key, args}}
 }
 
 func Is[T error](err error) bool {
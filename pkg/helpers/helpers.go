@@ -1,22 +1,15 @@
 package helpers
 
 import (
-	"reflect"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
-func Fill(first interface{}, second interface{}) interface{} {
-	valueOfFirst := reflect.ValueOf(first)
-	if valueOfFirst.IsNil() {
-		return second
-	}
-
-	return valueOfFirst.Elem().Interface()
-}
-
 func ToFormattedAddress(address string) string {
 	caser := cases.Title(language.BrazilianPortuguese)
 	return caser.String(address)
@@ -35,3 +28,124 @@ func ToDateTime(datetime string) time.Time {
 func ToFormattedDateTime(datetime time.Time) string {
 	return datetime.Format("2006-01-02 15:04:05")
 }
+
+// ToOptionalFormattedDateTime formats datetime, keeping nil so that it can be
+// sent as a NULL query argument.
+func ToOptionalFormattedDateTime(datetime *time.Time) *string {
+	if datetime == nil {
+		return nil
+	}
+
+	formatted := ToFormattedDateTime(*datetime)
+	return &formatted
+}
+
+// ExpandIn fills the IN (%s) clause of query with one placeholder per key and
+// returns the keys as the arguments of the query.
+func ExpandIn[K int | string](query string, keys []K) (string, []interface{}) {
+	args := make([]interface{}, len(keys))
+	for i, k := range keys {
+		args[i] = k
+	}
+
+	return fmt.Sprintf(query, strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")), args
+}
+
+// ByID indexes items by their id, as found by the GetMany of a repository.
+func ByID[T any, K comparable](items []T, id func(T) K) map[K]T {
+	indexed := make(map[K]T, len(items))
+	for _, item := range items {
+		indexed[id(item)] = item
+	}
+	return indexed
+}
+
+// First returns the first of items, or nil when there is none, as when the
+// GetMany of a repository looks a single id up.
+func First[T any](items []T) *T {
+	if len(items) == 0 {
+		return nil
+	}
+	return &items[0]
+}
+
+// Distinct returns the distinct keys of items, in the order they first appear.
+func Distinct[T any, K comparable](items []T, key func(T) K) []K {
+	seen := make(map[K]bool, len(items))
+	keys := make([]K, 0, len(items))
+	for _, item := range items {
+		k := key(item)
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Identity returns its argument, as the id of the keys returned by a repository
+// that looks keys up.
+func Identity[K any](k K) K {
+	return k
+}
+
+// Found looks the keys of items up with a single call to getMany and tells
+// which of them exist, so that a batch checks a reference or a unique key with
+// one query per table instead of one per item.
+func Found[T any, R any, K comparable](items []T, key func(T) K, getMany func(keys []K) []R, id func(R) K) func(K) bool {
+	found := ByID(getMany(Distinct(items, key)), id)
+	return func(k K) bool {
+		_, ok := found[k]
+		return ok
+	}
+}
+
+// Top returns the n items with the highest count, keeping their original
+// order on ties. A non-positive n returns every item unchanged.
+func Top[T any](items []T, n int, count func(T) int) []T {
+	if n <= 0 {
+		return items
+	}
+
+	ranked := make([]T, len(items))
+	copy(ranked, items)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return count(ranked[i]) > count(ranked[j])
+	})
+
+	if n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// Import checks every item and saves the valid ones with save, unless it is a
+// dry run or the import is atomic and any item is invalid. It returns, by
+// position of the item, the id it was saved with and the error that rejected
+// it. progress, when not nil, is called after each item is checked, and nothing
+// is saved once it returns false.
+func Import[T any](items []T, dryRun bool, atomic bool, check func(T) error, save func([]T) []int, progress func(checked int) bool) ([]int, []error) {
+	ids := make([]int, len(items))
+	errs := make([]error, len(items))
+
+	valid := make([]T, 0, len(items))
+	positions := make([]int, 0, len(items))
+	for i, item := range items {
+		if errs[i] = check(item); errs[i] == nil {
+			valid = append(valid, item)
+			positions = append(positions, i)
+		}
+		if progress != nil && !progress(i+1) {
+			return ids, errs
+		}
+	}
+
+	if dryRun || len(valid) == 0 || (atomic && len(valid) < len(items)) {
+		return ids, errs
+	}
+
+	for i, id := range save(valid) {
+		ids[positions[i]] = id
+	}
+	return ids, errs
+}
@@ -1,6 +1,7 @@
 package helpers_test
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -8,27 +9,6 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestFill(t *testing.T) {
-	t.Run("Should return first value when first is not nil", func(t *testing.T) {
-		first := "first"
-		firstPtr := &first
-		second := "second"
-
-		result := helpers.Fill(firstPtr, second)
-
-		assert.Equal(t, first, result)
-	})
-
-	t.Run("Should return second value when first is nil", func(t *testing.T) {
-		var firstPtr *string
-		second := "second"
-
-		result := helpers.Fill(firstPtr, second)
-
-		assert.Equal(t, second, result)
-	})
-}
-
 func TestToFormattedAddress(t *testing.T) {
 	t.Run("Should return the formatted address", func(t *testing.T) {
 		unformattedAddress := "eXaMple address"
@@ -67,3 +47,142 @@ func TestToFormattedDateTime(t *testing.T) {
 		assert.Equal(t, expectedResult, result)
 	})
 }
+
+func TestToOptionalFormattedDateTime(t *testing.T) {
+	t.Run("Should return the formatted datetime", func(t *testing.T) {
+		datetime := time.Date(2021, 1, 1, 1, 0, 0, 0, time.UTC)
+
+		result := helpers.ToOptionalFormattedDateTime(&datetime)
+
+		assert.Equal(t, "2021-01-01 01:00:00", *result)
+	})
+	t.Run("Should return nil when the datetime is nil", func(t *testing.T) {
+		assert.Nil(t, helpers.ToOptionalFormattedDateTime(nil))
+	})
+}
+
+func TestExpandIn(t *testing.T) {
+	t.Run("Should add a placeholder and an argument per id", func(t *testing.T) {
+		query, args := helpers.ExpandIn("SELECT id FROM sellers WHERE id IN (%s)", []int{4, 2, 9})
+
+		assert.Equal(t, "SELECT id FROM sellers WHERE id IN (?, ?, ?)", query)
+		assert.Equal(t, []interface{}{4, 2, 9}, args)
+	})
+}
+
+func TestExpandInStrings(t *testing.T) {
+	t.Run("Should add a placeholder and an argument per key", func(t *testing.T) {
+		query, args := helpers.ExpandIn("SELECT cid FROM carriers WHERE cid IN (%s)", []string{"CID1", "CID2"})
+
+		assert.Equal(t, "SELECT cid FROM carriers WHERE cid IN (?, ?)", query)
+		assert.Equal(t, []interface{}{"CID1", "CID2"}, args)
+	})
+}
+
+func TestFirst(t *testing.T) {
+	t.Run("Should return the first item", func(t *testing.T) {
+		assert.Equal(t, 4, *helpers.First([]int{4, 2}))
+	})
+	t.Run("Should return nil when there are no items", func(t *testing.T) {
+		assert.Nil(t, helpers.First([]int{}))
+	})
+}
+
+func TestFound(t *testing.T) {
+	t.Run("Should look the distinct keys up in a single call", func(t *testing.T) {
+		calls := make([][]int, 0)
+		getMany := func(ids []int) []int {
+			calls = append(calls, ids)
+			return []int{2}
+		}
+		identity := func(id int) int { return id }
+
+		found := helpers.Found([]int{2, 5, 2}, identity, getMany, identity)
+
+		assert.Equal(t, [][]int{{2, 5}}, calls)
+		assert.True(t, found(2))
+		assert.False(t, found(5))
+	})
+}
+
+func TestTop(t *testing.T) {
+	counts := []int{3, 7, 1, 7}
+	identity := func(count int) int { return count }
+
+	t.Run("Should return the n highest counts keeping ties in order", func(t *testing.T) {
+		assert.Equal(t, []int{7, 7, 3}, helpers.Top(counts, 3, identity))
+		assert.Equal(t, []int{3, 7, 1, 7}, counts)
+	})
+	t.Run("Should return every item when n is greater than the length", func(t *testing.T) {
+		assert.Equal(t, []int{7, 7, 3, 1}, helpers.Top(counts, 10, identity))
+	})
+	t.Run("Should return the items unchanged when n is not positive", func(t *testing.T) {
+		assert.Equal(t, counts, helpers.Top(counts, 0, identity))
+	})
+}
+
+func TestImport(t *testing.T) {
+	items := []int{1, -2, 3}
+	errNegative := errors.New("negative")
+	check := func(item int) error {
+		if item < 0 {
+			return errNegative
+		}
+		return nil
+	}
+	saved := make([]int, 0)
+	save := func(valid []int) []int {
+		saved = append(saved, valid...)
+		ids := make([]int, 0, len(valid))
+		for _, item := range valid {
+			ids = append(ids, item*10)
+		}
+		return ids
+	}
+
+	t.Run("Should save the valid items when the import is not atomic", func(t *testing.T) {
+		saved = saved[:0]
+
+		ids, errs := helpers.Import(items, false, false, check, save, nil)
+
+		assert.Equal(t, []int{10, 0, 30}, ids)
+		assert.Equal(t, []error{nil, errNegative, nil}, errs)
+		assert.Equal(t, []int{1, 3}, saved)
+	})
+	t.Run("Should save nothing when the import is atomic and an item is invalid", func(t *testing.T) {
+		saved = saved[:0]
+
+		ids, errs := helpers.Import(items, false, true, check, save, nil)
+
+		assert.Equal(t, []int{0, 0, 0}, ids)
+		assert.ErrorIs(t, errs[1], errNegative)
+		assert.Empty(t, saved)
+	})
+	t.Run("Should only check the items on a dry run", func(t *testing.T) {
+		saved = saved[:0]
+
+		checked := make([]int, 0)
+		ids, errs := helpers.Import([]int{1, 3}, true, true, check, save, func(n int) bool {
+			checked = append(checked, n)
+			return true
+		})
+
+		assert.Equal(t, []int{1, 2}, checked)
+		assert.Equal(t, []int{0, 0}, ids)
+		assert.Equal(t, []error{nil, nil}, errs)
+		assert.Empty(t, saved)
+	})
+	t.Run("Should save nothing once progress asks to stop", func(t *testing.T) {
+		saved = saved[:0]
+
+		checked := make([]int, 0)
+		ids, _ := helpers.Import([]int{1, 3}, false, false, check, save, func(n int) bool {
+			checked = append(checked, n)
+			return false
+		})
+
+		assert.Equal(t, []int{1}, checked)
+		assert.Equal(t, []int{0, 0}, ids)
+		assert.Empty(t, saved)
+	})
+}
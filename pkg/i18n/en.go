@@ -0,0 +1,121 @@
+package i18n
+
+var english = map[string]string{
+	"internal_error": "an internal error occurred",
+	"invalid_id":     "the id '%s' is invalid",
+
+	"request.cannot_be_blank":    "at least one of the following fields must be given for updates: %v",
+	"request.empty_body":         "the request body is empty and must be a valid JSON object",
+	"request.syntax_error":       "syntax error at position %d: %v",
+	"request.wrong_type":         "the field '%s' must be '%s'",
+	"request.invalid_fields":     "the request has invalid fields",
+	"request.invalid_query":      "the request parameters are invalid",
+	"request.unsupported_patch":  "the content type '%s' is not supported, use '%s' or '%s'",
+	"request.invalid_patch":      "the patch could not be applied: %v",
+	"request.patch_test_failed":  "the patch was not applied because a test operation failed: %v",
+	"request.unsupported_import": "the content type '%s' is not supported, use '%s' or '%s'",
+	"request.invalid_import":     "the file could not be read: %v",
+	"request.empty_import":       "the file has no rows to import",
+	"idempotency.key_reused":     "the idempotency key '%s' was already used with a different request",
+	"idempotency.key_pending":    "the request with the idempotency key '%s' is still being processed, retry later",
+
+	"validation.required":      "'%[1]s' is required",
+	"validation.e164":          "'%[1]s' must be in the format +<country_code><zone_code><phone_number> without spaces or special characters, for example: +15550123456",
+	"validation.datetime":      "'%[1]s' must be in the format %[2]s",
+	"validation.email":         "'%[1]s' must be a valid e-mail",
+	"validation.url":           "'%[1]s' must be a valid URL",
+	"validation.numeric":       "'%[1]s' must contain only numbers",
+	"validation.alpha":         "'%[1]s' must contain only letters",
+	"validation.alphanum":      "'%[1]s' must contain only letters and numbers",
+	"validation.oneof":         "'%[1]s' must be one of the following values: %[2]s",
+	"validation.excluded_with": "'%[1]s' cannot be used together with %[2]s",
+	"validation.eq":            "'%[1]s' must be equal to %[2]s",
+	"validation.ne":            "'%[1]s' must be different from %[2]s",
+	"validation.gt.string":     "'%[1]s' must have more than %[2]s characters",
+	"validation.gt.number":     "'%[1]s' must be greater than %[2]s",
+	"validation.gt.items":      "'%[1]s' must have more than %[2]s items",
+	"validation.gte.string":    "'%[1]s' must have at least %[2]s characters",
+	"validation.gte.number":    "'%[1]s' must be greater than or equal to %[2]s",
+	"validation.gte.items":     "'%[1]s' must have at least %[2]s items",
+	"validation.lt.string":     "'%[1]s' must have less than %[2]s characters",
+	"validation.lt.number":     "'%[1]s' must be less than %[2]s",
+	"validation.lt.items":      "'%[1]s' must have less than %[2]s items",
+	"validation.lte.string":    "'%[1]s' must have at most %[2]s characters",
+	"validation.lte.number":    "'%[1]s' must be less than or equal to %[2]s",
+	"validation.lte.items":     "'%[1]s' must have at most %[2]s items",
+	"validation.min.string":    "'%[1]s' must have at least %[2]s characters",
+	"validation.min.number":    "'%[1]s' must be at least %[2]s",
+	"validation.min.items":     "'%[1]s' must have at least %[2]s items",
+	"validation.max.string":    "'%[1]s' must have at most %[2]s characters",
+	"validation.max.number":    "'%[1]s' must be at most %[2]s",
+	"validation.max.items":     "'%[1]s' must have at most %[2]s items",
+	"validation.len.string":    "'%[1]s' must have exactly %[2]s characters",
+	"validation.len.number":    "'%[1]s' must be equal to %[2]s",
+	"validation.len.items":     "'%[1]s' must have exactly %[2]s items",
+	"validation.unknown":       "'%[1]s' does not satisfy the rule '%[3]s'",
+
+	"buyer.not_found":                         "buyer not found with id %d",
+	"buyer.modified":                          "buyer %d was modified since it was read, fetch it again and retry",
+	"buyer.already_exists":                    "a buyer with card number '%s' already exists",
+	"carrier.not_found":                       "carrier not found with id %d",
+	"carrier.already_exists":                  "a carrier with cid '%s' already exists",
+	"cycle_count.not_found":                   "cycle count not found with id %d",
+	"cycle_count.employee_other_warehouse":    "employee %d works in warehouse %d, but section %d belongs to warehouse %d",
+	"cycle_count.invalid_status":              "cycle count %d is %s",
+	"cycle_count.not_assigned_to":             "cycle count %d is not assigned to employee %d",
+	"cycle_count.unknown_batch":               "cycle count %d does not expect batch %d or it was counted twice",
+	"cycle_count.incomplete":                  "cycle count %d is missing %d batches",
+	"cycle_count.self_review":                 "cycle count %d can not be reviewed by employee %d, who counted it",
+	"cycle_count.not_supervisor":              "cycle count %d can not be reviewed by employee %d, who is not a supervisor",
+	"cycle_count.negative_stock":              "approving cycle count %d would leave a batch with negative stock",
+	"cycle_count.reserved_stock":              "approving cycle count %d would leave a batch with less stock than is reserved for orders",
+	"cycle_count.section_full":                "approving cycle count %d would exceed the capacity of section %d",
+	"job.not_found":                           "job not found with id %d",
+	"job.already_finished":                    "job %d is already %s",
+	"job.not_succeeded":                       "job %d is %s and has no result",
+	"webhook.not_found":                       "webhook not found with id %d",
+	"webhook.modified":                        "webhook %d was modified since it was read, fetch it again and retry",
+	"webhook.delivery_not_found":              "webhook delivery not found with id %d",
+	"graphql.too_deep":                        "query depth %d exceeds the maximum of %d",
+	"graphql.too_complex":                     "query complexity %d exceeds the maximum of %d",
+	"employee.not_found":                      "employee not found with id %d",
+	"employee.modified":                       "employee %d was modified since it was read, fetch it again and retry",
+	"employee.already_exists":                 "an employee with card number ID '%s' already exists",
+	"inbound_order.already_exists":            "an inbound order with number '%s' already exists",
+	"locality.not_found":                      "locality not found with id %d",
+	"locality.already_exists":                 "a locality named '%s' already exists",
+	"order_status.not_found":                  "order status not found with id %d",
+	"product.not_found":                       "product not found with id %d",
+	"product.modified":                        "product %d was modified since it was read, fetch it again and retry",
+	"product.already_exists":                  "a product with code '%s' already exists",
+	"product_batch.not_found":                 "product batch not found with id %d",
+	"product_batch.already_exists":            "batch number %d already exists",
+	"product_batch.incompatible_product_type": "product %d is of type %d, but section %d stores products of type %d",
+	"product_batch.incompatible_temperature":  "the batch minimum temperature (%.1f °C) is outside the range of section %d (%.1f °C to %.1f °C)",
+	"product_batch.insufficient_quantity":     "batch %d holds only %d units, %d were requested",
+	"product_batch.reserved_quantity":         "batch %d has units reserved for orders, %d can not be moved out of it",
+	"product_batch.same_section":              "batch %d is already stored in section %d",
+	"product_batch.invalid_status_transition": "batch %d can not move from %s to %s",
+	"recall.not_found":                        "recall not found with id %d",
+	"product_record.not_found":                "product record not found with id %d",
+	"product_record.already_exists":           "a product record with product id '%d' and last update date '%s' already exists",
+	"product_type.not_found":                  "product type not found with id %d",
+	"province.not_found":                      "province not found with id %d",
+	"purchase_order.already_exists":           "a purchase order with number '%s' already exists",
+	"purchase_order.not_found":                "purchase order not found with id %d",
+	"purchase_order.already_cancelled":        "purchase order %d is already cancelled",
+	"purchase_order.created_cancelled":        "purchase order '%s' can not be created cancelled",
+	"purchase_order.batch_not_reserved":       "batch %d has nothing left to pick for purchase order %d",
+	"purchase_order.batch_unavailable":        "batch %d is not available to pick",
+	"section.not_found":                       "section not found with id %d",
+	"section.modified":                        "section %d was modified since it was read, fetch it again and retry",
+	"section.already_exists":                  "a section with number '%d' already exists",
+	"section.capacity_below_usage":            "the maximum capacity %d is lower than the quantity already stored in the section (%d)",
+	"section.capacity_exceeded":               "section %d cannot hold %d more units, only %d are free",
+	"seller.not_found":                        "seller not found with id %d",
+	"seller.modified":                         "seller %d was modified since it was read, fetch it again and retry",
+	"seller.already_exists":                   "a seller with CID '%d' already exists",
+	"warehouse.not_found":                     "warehouse not found with id %d",
+	"warehouse.modified":                      "warehouse %d was modified since it was read, fetch it again and retry",
+	"warehouse.already_exists":                "a warehouse with code '%s' already exists",
+}
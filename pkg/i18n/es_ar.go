@@ -0,0 +1,121 @@
+package i18n
+
+var spanishAR = map[string]string{
+	"internal_error": "ocurrió un error interno",
+	"invalid_id":     "el id '%s' es inválido",
+
+	"request.cannot_be_blank":    "al menos uno de los siguientes campos debe ser informado para modificaciones: %v",
+	"request.empty_body":         "el cuerpo de la solicitud está vacío y debe ser un objeto JSON válido",
+	"request.syntax_error":       "error de sintaxis en la posición %d: %v",
+	"request.wrong_type":         "el campo '%s' debe ser '%s'",
+	"request.invalid_fields":     "la solicitud tiene campos inválidos",
+	"request.invalid_query":      "los parámetros de la solicitud son inválidos",
+	"request.unsupported_patch":  "el tipo de contenido '%s' no es soportado, utilice '%s' o '%s'",
+	"request.invalid_patch":      "no fue posible aplicar el patch: %v",
+	"request.patch_test_failed":  "el patch no fue aplicado porque una operación test falló: %v",
+	"request.unsupported_import": "el tipo de contenido '%s' no es soportado, utilice '%s' o '%s'",
+	"request.invalid_import":     "no fue posible leer el archivo: %v",
+	"request.empty_import":       "el archivo no tiene filas para importar",
+	"idempotency.key_reused":     "la clave de idempotencia '%s' ya fue utilizada con una solicitud diferente",
+	"idempotency.key_pending":    "la solicitud con la clave de idempotencia '%s' todavía se está procesando, reintente más tarde",
+
+	"validation.required":      "'%[1]s' es obligatorio",
+	"validation.e164":          "'%[1]s' debe estar en el formato +<country_code><zone_code><phone_number> sin espacios ni caracteres especiales, por ejemplo: +5491123456789",
+	"validation.datetime":      "'%[1]s' debe estar en el formato %[2]s",
+	"validation.email":         "'%[1]s' debe ser un e-mail válido",
+	"validation.url":           "'%[1]s' debe ser una URL válida",
+	"validation.numeric":       "'%[1]s' debe contener solo números",
+	"validation.alpha":         "'%[1]s' debe contener solo letras",
+	"validation.alphanum":      "'%[1]s' debe contener solo letras y números",
+	"validation.oneof":         "'%[1]s' debe ser uno de los siguientes valores: %[2]s",
+	"validation.excluded_with": "'%[1]s' no puede usarse junto con %[2]s",
+	"validation.eq":            "'%[1]s' debe ser igual a %[2]s",
+	"validation.ne":            "'%[1]s' debe ser distinto de %[2]s",
+	"validation.gt.string":     "'%[1]s' debe tener más de %[2]s caracteres",
+	"validation.gt.number":     "'%[1]s' debe ser mayor que %[2]s",
+	"validation.gt.items":      "'%[1]s' debe tener más de %[2]s elementos",
+	"validation.gte.string":    "'%[1]s' debe tener al menos %[2]s caracteres",
+	"validation.gte.number":    "'%[1]s' debe ser mayor o igual a %[2]s",
+	"validation.gte.items":     "'%[1]s' debe tener al menos %[2]s elementos",
+	"validation.lt.string":     "'%[1]s' debe tener menos de %[2]s caracteres",
+	"validation.lt.number":     "'%[1]s' debe ser menor que %[2]s",
+	"validation.lt.items":      "'%[1]s' debe tener menos de %[2]s elementos",
+	"validation.lte.string":    "'%[1]s' debe tener como máximo %[2]s caracteres",
+	"validation.lte.number":    "'%[1]s' debe ser menor o igual a %[2]s",
+	"validation.lte.items":     "'%[1]s' debe tener como máximo %[2]s elementos",
+	"validation.min.string":    "'%[1]s' debe tener al menos %[2]s caracteres",
+	"validation.min.number":    "'%[1]s' debe ser como mínimo %[2]s",
+	"validation.min.items":     "'%[1]s' debe tener al menos %[2]s elementos",
+	"validation.max.string":    "'%[1]s' debe tener como máximo %[2]s caracteres",
+	"validation.max.number":    "'%[1]s' debe ser como máximo %[2]s",
+	"validation.max.items":     "'%[1]s' debe tener como máximo %[2]s elementos",
+	"validation.len.string":    "'%[1]s' debe tener exactamente %[2]s caracteres",
+	"validation.len.number":    "'%[1]s' debe ser igual a %[2]s",
+	"validation.len.items":     "'%[1]s' debe tener exactamente %[2]s elementos",
+	"validation.unknown":       "'%[1]s' no cumple la regla '%[3]s'",
+
+	"buyer.not_found":                         "comprador no encontrado con el id %d",
+	"buyer.modified":                          "el comprador %d fue modificado desde que se leyó, vuelva a obtenerlo y reintente",
+	"buyer.already_exists":                    "ya existe un comprador con el número de tarjeta '%s'",
+	"carrier.not_found":                       "transportista no encontrado con el id %d",
+	"carrier.already_exists":                  "ya existe un transportista con cid '%s'",
+	"cycle_count.not_found":                   "conteo cíclico no encontrado con el id %d",
+	"cycle_count.employee_other_warehouse":    "el empleado %d trabaja en el depósito %d, pero la sección %d pertenece al depósito %d",
+	"cycle_count.invalid_status":              "el conteo cíclico %d está %s",
+	"cycle_count.not_assigned_to":             "el conteo cíclico %d no está asignado al empleado %d",
+	"cycle_count.unknown_batch":               "el conteo cíclico %d no espera el lote %d o fue contado dos veces",
+	"cycle_count.incomplete":                  "faltan %[2]d lotes en el conteo cíclico %[1]d",
+	"cycle_count.self_review":                 "el conteo cíclico %d no puede ser revisado por el empleado %d, que lo contó",
+	"cycle_count.not_supervisor":              "el conteo cíclico %d no puede ser revisado por el empleado %d, que no es supervisor",
+	"cycle_count.negative_stock":              "aprobar el conteo cíclico %d dejaría un lote con stock negativo",
+	"cycle_count.reserved_stock":              "aprobar el conteo cíclico %d dejaría un lote con menos stock que el reservado para órdenes",
+	"cycle_count.section_full":                "aprobar el conteo cíclico %d superaría la capacidad de la sección %d",
+	"job.not_found":                           "tarea no encontrada con el id %d",
+	"job.already_finished":                    "la tarea %d ya está %s",
+	"job.not_succeeded":                       "la tarea %d está %s y no tiene resultado",
+	"webhook.not_found":                       "webhook no encontrado con el id %d",
+	"webhook.modified":                        "el webhook %d fue modificado desde que se leyó, vuelva a obtenerlo y reintente",
+	"webhook.delivery_not_found":              "entrega de webhook no encontrada con el id %d",
+	"graphql.too_deep":                        "la profundidad de la consulta %d supera el máximo de %d",
+	"graphql.too_complex":                     "la complejidad de la consulta %d supera el máximo de %d",
+	"employee.not_found":                      "empleado no encontrado con el id %d",
+	"employee.modified":                       "el empleado %d fue modificado desde que se leyó, vuelva a obtenerlo y reintente",
+	"employee.already_exists":                 "ya existe un empleado con card number ID '%s'",
+	"inbound_order.already_exists":            "ya existe una orden de entrada con el número '%s'",
+	"locality.not_found":                      "localidad no encontrada con el id %d",
+	"locality.already_exists":                 "ya existe una localidad con el nombre '%s'",
+	"order_status.not_found":                  "estado de orden no encontrado con el id %d",
+	"product.not_found":                       "producto no encontrado con el id %d",
+	"product.modified":                        "el producto %d fue modificado desde que se leyó, vuelva a obtenerlo y reintente",
+	"product.already_exists":                  "ya existe un producto con el código '%s'",
+	"product_batch.not_found":                 "lote de producto no encontrado con el id %d",
+	"product_batch.already_exists":            "ya existe un lote con el número %d",
+	"product_batch.incompatible_product_type": "el producto %d es del tipo %d, pero la sección %d almacena productos del tipo %d",
+	"product_batch.incompatible_temperature":  "la temperatura mínima del lote (%.1f °C) está fuera del rango de la sección %d (%.1f °C a %.1f °C)",
+	"product_batch.insufficient_quantity":     "el lote %d tiene solo %d unidades, se pidieron %d",
+	"product_batch.reserved_quantity":         "el lote %d tiene unidades reservadas para órdenes, no se pueden mover %d",
+	"product_batch.same_section":              "el lote %d ya está almacenado en la sección %d",
+	"product_batch.invalid_status_transition": "el lote %d no puede pasar de %s a %s",
+	"recall.not_found":                        "retiro no encontrado con el id %d",
+	"product_record.not_found":                "registro de producto no encontrado con el id %d",
+	"product_record.already_exists":           "ya existe un registro de producto con el id de producto '%d' y fecha de última actualización '%s'",
+	"product_type.not_found":                  "tipo de producto no encontrado con el id %d",
+	"province.not_found":                      "provincia no encontrada con el id %d",
+	"purchase_order.already_exists":           "ya existe una orden de compra con el número '%s'",
+	"purchase_order.not_found":                "orden de compra no encontrada con el id %d",
+	"purchase_order.already_cancelled":        "la orden de compra %d ya está cancelada",
+	"purchase_order.created_cancelled":        "la orden de compra '%s' no puede crearse cancelada",
+	"purchase_order.batch_not_reserved":       "el lote %d no tiene nada pendiente de picking para la orden de compra %d",
+	"purchase_order.batch_unavailable":        "el lote %d no está disponible para picking",
+	"section.not_found":                       "sección no encontrada con el id %d",
+	"section.modified":                        "la sección %d fue modificada desde que se leyó, vuelva a obtenerla y reintente",
+	"section.already_exists":                  "ya existe una sección con el número '%d'",
+	"section.capacity_below_usage":            "la capacidad máxima %d es menor que la cantidad ya almacenada en la sección (%d)",
+	"section.capacity_exceeded":               "la sección %d no admite %d unidades más, quedan solo %d libres",
+	"seller.not_found":                        "vendedor no encontrado con el id %d",
+	"seller.modified":                         "el vendedor %d fue modificado desde que se leyó, vuelva a obtenerlo y reintente",
+	"seller.already_exists":                   "ya existe un vendedor con el CID '%d'",
+	"warehouse.not_found":                     "depósito no encontrado con el id %d",
+	"warehouse.modified":                      "el depósito %d fue modificado desde que se leyó, vuelva a obtenerlo y reintente",
+	"warehouse.already_exists":                "ya existe un depósito con el código '%s'",
+}
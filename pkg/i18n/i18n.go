@@ -0,0 +1,58 @@
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	PortugueseBR = language.BrazilianPortuguese
+	SpanishAR    = language.MustParse("es-AR")
+	English      = language.English
+	Default      = PortugueseBR
+
+	supported = []language.Tag{PortugueseBR, SpanishAR, English}
+	matcher   = language.NewMatcher(supported)
+	catalog   = map[language.Tag]map[string]string{
+		PortugueseBR: portugueseBR,
+		SpanishAR:    spanishAR,
+		English:      english,
+	}
+)
+
+// LocaleFrom picks the supported locale that best matches an Accept-Language
+// header, falling back to Default when nothing matches.
+func LocaleFrom(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+
+	return supported[index]
+}
+
+// Translate formats the message registered under key for the given locale.
+// Keys missing from the catalog are used as the format itself.
+func Translate(locale language.Tag, key string, args ...interface{}) string {
+	format, ok := catalog[locale][key]
+	if !ok {
+		format, ok = catalog[Default][key]
+	}
+	if !ok {
+		format = key
+	}
+
+	return fmt.Sprintf(format, args...)
+}
+
+// Has reports whether key is registered in the default catalog.
+func Has(key string) bool {
+	_, ok := catalog[Default][key]
+	return ok
+}
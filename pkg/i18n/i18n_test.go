@@ -0,0 +1,49 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestLocaleFrom(t *testing.T) {
+	t.Run("Should return the default locale when no language is given", func(t *testing.T) {
+		assert.Equal(t, Default, LocaleFrom(""))
+	})
+
+	t.Run("Should return the default locale when the language is not supported", func(t *testing.T) {
+		assert.Equal(t, Default, LocaleFrom("ja-JP"))
+	})
+
+	t.Run("Should return the best supported locale", func(t *testing.T) {
+		assert.Equal(t, SpanishAR, LocaleFrom("es"))
+		assert.Equal(t, English, LocaleFrom("en-US,pt;q=0.5"))
+		assert.Equal(t, PortugueseBR, LocaleFrom("pt-PT"))
+	})
+}
+
+func TestTranslate(t *testing.T) {
+	t.Run("Should translate the message to the given locale", func(t *testing.T) {
+		assert.Equal(t, "sección no encontrada con el id 1", Translate(SpanishAR, "section.not_found", 1))
+	})
+
+	t.Run("Should use the default locale when the locale is not in the catalog", func(t *testing.T) {
+		assert.Equal(t, "seção não encontrada com o id 1", Translate(language.Japanese, "section.not_found", 1))
+	})
+
+	t.Run("Should use the key as format when it is not in the catalog", func(t *testing.T) {
+		assert.Equal(t, "unknown 1", Translate(English, "unknown %d", 1))
+	})
+}
+
+func TestCatalog(t *testing.T) {
+	t.Run("Should have every message in every locale", func(t *testing.T) {
+		for locale, messages := range catalog {
+			assert.Len(t, messages, len(catalog[Default]), locale.String())
+			for key := range catalog[Default] {
+				assert.Contains(t, messages, key, locale.String())
+			}
+		}
+	})
+}
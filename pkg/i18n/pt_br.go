@@ -0,0 +1,121 @@
+package i18n
+
+var portugueseBR = map[string]string{
+	"internal_error": "ocorreu um erro interno",
+	"invalid_id":     "o id '%s' é inválido",
+
+	"request.cannot_be_blank":    "pelo menos um dos seguintes campos deve ser informado para modificações: %v",
+	"request.empty_body":         "o corpo da requisição está vazio e precisa ser um objeto JSON válido",
+	"request.syntax_error":       "erro de sintaxe na posição %d: %v",
+	"request.wrong_type":         "o campo '%s' deve ser '%s'",
+	"request.invalid_fields":     "a requisição possui campos inválidos",
+	"request.invalid_query":      "os parâmetros da requisição são inválidos",
+	"request.unsupported_patch":  "o tipo de conteúdo '%s' não é suportado, utilize '%s' ou '%s'",
+	"request.invalid_patch":      "não foi possível aplicar o patch: %v",
+	"request.patch_test_failed":  "o patch não foi aplicado porque uma operação test falhou: %v",
+	"request.unsupported_import": "o tipo de conteúdo '%s' não é suportado, utilize '%s' ou '%s'",
+	"request.invalid_import":     "não foi possível ler o arquivo: %v",
+	"request.empty_import":       "o arquivo não possui linhas para importar",
+	"idempotency.key_reused":     "a chave de idempotência '%s' já foi utilizada com uma requisição diferente",
+	"idempotency.key_pending":    "a requisição com a chave de idempotência '%s' ainda está sendo processada, tente novamente mais tarde",
+
+	"validation.required":      "'%[1]s' é obrigatório",
+	"validation.e164":          "'%[1]s' precisa estar no formato +<country_code><zone_code><phone_number> sem espaços ou caracteres especiais, por exemplo: +5500123456789",
+	"validation.datetime":      "'%[1]s' precisa estar no formato %[2]s",
+	"validation.email":         "'%[1]s' precisa ser um e-mail válido",
+	"validation.url":           "'%[1]s' precisa ser uma URL válida",
+	"validation.numeric":       "'%[1]s' precisa conter apenas números",
+	"validation.alpha":         "'%[1]s' precisa conter apenas letras",
+	"validation.alphanum":      "'%[1]s' precisa conter apenas letras e números",
+	"validation.oneof":         "'%[1]s' precisa ser um dos seguintes valores: %[2]s",
+	"validation.excluded_with": "'%[1]s' não pode ser usado junto com %[2]s",
+	"validation.eq":            "'%[1]s' precisa ser igual a %[2]s",
+	"validation.ne":            "'%[1]s' precisa ser diferente de %[2]s",
+	"validation.gt.string":     "'%[1]s' precisa ter mais de %[2]s caracteres",
+	"validation.gt.number":     "'%[1]s' precisa ser maior que %[2]s",
+	"validation.gt.items":      "'%[1]s' precisa ter mais de %[2]s itens",
+	"validation.gte.string":    "'%[1]s' precisa ter pelo menos %[2]s caracteres",
+	"validation.gte.number":    "'%[1]s' precisa ser maior ou igual a %[2]s",
+	"validation.gte.items":     "'%[1]s' precisa ter pelo menos %[2]s itens",
+	"validation.lt.string":     "'%[1]s' precisa ter menos de %[2]s caracteres",
+	"validation.lt.number":     "'%[1]s' precisa ser menor que %[2]s",
+	"validation.lt.items":      "'%[1]s' precisa ter menos de %[2]s itens",
+	"validation.lte.string":    "'%[1]s' precisa ter no máximo %[2]s caracteres",
+	"validation.lte.number":    "'%[1]s' precisa ser menor ou igual a %[2]s",
+	"validation.lte.items":     "'%[1]s' precisa ter no máximo %[2]s itens",
+	"validation.min.string":    "'%[1]s' precisa ter pelo menos %[2]s caracteres",
+	"validation.min.number":    "'%[1]s' precisa ser no mínimo %[2]s",
+	"validation.min.items":     "'%[1]s' precisa ter pelo menos %[2]s itens",
+	"validation.max.string":    "'%[1]s' precisa ter no máximo %[2]s caracteres",
+	"validation.max.number":    "'%[1]s' precisa ser no máximo %[2]s",
+	"validation.max.items":     "'%[1]s' precisa ter no máximo %[2]s itens",
+	"validation.len.string":    "'%[1]s' precisa ter exatamente %[2]s caracteres",
+	"validation.len.number":    "'%[1]s' precisa ser igual a %[2]s",
+	"validation.len.items":     "'%[1]s' precisa ter exatamente %[2]s itens",
+	"validation.unknown":       "'%[1]s' não atende à regra '%[3]s'",
+
+	"buyer.not_found":                         "comprador não encontrado com o id %d",
+	"buyer.modified":                          "o comprador %d foi modificado desde que foi lido, busque-o novamente e tente outra vez",
+	"buyer.already_exists":                    "um comprador com o número de cartão '%s' já existe",
+	"carrier.not_found":                       "transportadora não encontrada com o id %d",
+	"carrier.already_exists":                  "uma transportadora com cid '%s' já existe",
+	"cycle_count.not_found":                   "contagem cíclica não encontrada com o id %d",
+	"cycle_count.employee_other_warehouse":    "o funcionário %d trabalha no armazém %d, mas a seção %d pertence ao armazém %d",
+	"cycle_count.invalid_status":              "a contagem cíclica %d está %s",
+	"cycle_count.not_assigned_to":             "a contagem cíclica %d não está atribuída ao funcionário %d",
+	"cycle_count.unknown_batch":               "a contagem cíclica %d não espera o lote %d ou ele foi contado duas vezes",
+	"cycle_count.incomplete":                  "faltam %[2]d lotes na contagem cíclica %[1]d",
+	"cycle_count.self_review":                 "a contagem cíclica %d não pode ser revisada pelo funcionário %d, que a contou",
+	"cycle_count.not_supervisor":              "a contagem cíclica %d não pode ser revisada pelo funcionário %d, que não é supervisor",
+	"cycle_count.negative_stock":              "aprovar a contagem cíclica %d deixaria um lote com estoque negativo",
+	"cycle_count.reserved_stock":              "aprovar a contagem cíclica %d deixaria um lote com menos estoque do que o reservado para pedidos",
+	"cycle_count.section_full":                "aprovar a contagem cíclica %d excederia a capacidade da seção %d",
+	"job.not_found":                           "tarefa não encontrada com o id %d",
+	"job.already_finished":                    "a tarefa %d já está %s",
+	"job.not_succeeded":                       "a tarefa %d está %s e não possui resultado",
+	"webhook.not_found":                       "webhook não encontrado com o id %d",
+	"webhook.modified":                        "o webhook %d foi modificado desde que foi lido, busque-o novamente e tente outra vez",
+	"webhook.delivery_not_found":              "entrega de webhook não encontrada com o id %d",
+	"graphql.too_deep":                        "a profundidade da consulta %d excede o máximo de %d",
+	"graphql.too_complex":                     "a complexidade da consulta %d excede o máximo de %d",
+	"employee.not_found":                      "funcionário não encontrado com o id %d",
+	"employee.modified":                       "o funcionário %d foi modificado desde que foi lido, busque-o novamente e tente outra vez",
+	"employee.already_exists":                 "um funcionário com card number ID '%s' já existe",
+	"inbound_order.already_exists":            "ordem de entrada com o número '%s' já existe",
+	"locality.not_found":                      "localidade não encontrada com o id %d",
+	"locality.already_exists":                 "uma localidade com o nome '%s' já existe",
+	"order_status.not_found":                  "status da ordem não encontrado com o id %d",
+	"product.not_found":                       "produto não encontrado com o id %d",
+	"product.modified":                        "o produto %d foi modificado desde que foi lido, busque-o novamente e tente outra vez",
+	"product.already_exists":                  "um produto com o código '%s' já existe",
+	"product_batch.not_found":                 "lote de produto não encontrado com o id %d",
+	"product_batch.already_exists":            "um lote com o número %d já existe",
+	"product_batch.incompatible_product_type": "o produto %d é do tipo %d, mas a seção %d armazena produtos do tipo %d",
+	"product_batch.incompatible_temperature":  "a temperatura mínima do lote (%.1f °C) está fora da faixa da seção %d (%.1f °C a %.1f °C)",
+	"product_batch.insufficient_quantity":     "o lote %d possui apenas %d unidades, foram solicitadas %d",
+	"product_batch.reserved_quantity":         "o lote %d tem unidades reservadas para pedidos, não é possível mover %d",
+	"product_batch.same_section":              "o lote %d já está armazenado na seção %d",
+	"product_batch.invalid_status_transition": "o lote %d não pode passar de %s para %s",
+	"recall.not_found":                        "recall não encontrado com o id %d",
+	"product_record.not_found":                "registro de produto não encontrado com o id %d",
+	"product_record.already_exists":           "um registro de produto com o id de produto '%d' e última data de atualização '%s' já existe",
+	"product_type.not_found":                  "tipo de produto não encontrado com o id %d",
+	"province.not_found":                      "estado não encontrado com o id %d",
+	"purchase_order.already_exists":           "uma ordem de compra com o número '%s' já existe",
+	"purchase_order.not_found":                "pedido de compra não encontrado com o id %d",
+	"purchase_order.already_cancelled":        "o pedido de compra %d já está cancelado",
+	"purchase_order.created_cancelled":        "o pedido de compra '%s' não pode ser criado cancelado",
+	"purchase_order.batch_not_reserved":       "o lote %d não tem nada a separar para o pedido de compra %d",
+	"purchase_order.batch_unavailable":        "o lote %d não está disponível para separação",
+	"section.not_found":                       "seção não encontrada com o id %d",
+	"section.modified":                        "a seção %d foi modificada desde que foi lida, busque-a novamente e tente outra vez",
+	"section.already_exists":                  "uma seção com o número '%d' já existe",
+	"section.capacity_below_usage":            "a capacidade máxima %d é menor que a quantidade já armazenada na seção (%d)",
+	"section.capacity_exceeded":               "a seção %d não comporta mais %d unidades, restam apenas %d livres",
+	"seller.not_found":                        "vendedor não encontrado com o id %d",
+	"seller.modified":                         "o vendedor %d foi modificado desde que foi lido, busque-o novamente e tente outra vez",
+	"seller.already_exists":                   "um vendedor com o CID '%d' já existe",
+	"warehouse.not_found":                     "armazém não encontrado com o id %d",
+	"warehouse.modified":                      "o armazém %d foi modificado desde que foi lido, busque-o novamente e tente outra vez",
+	"warehouse.already_exists":                "já existe um armazém com o código '%s'",
+}
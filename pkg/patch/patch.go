@@ -0,0 +1,299 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+var (
+	ErrInvalidPatch = errors.New("invalid patch")
+	ErrTestFailed   = errors.New("test operation failed")
+)
+
+// Operation is a single RFC 6902 JSON Patch operation.
+type Operation struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from,omitempty"`
+	Value *json.RawMessage `json:"value,omitempty"`
+}
+
+// MergePatch applies a RFC 7396 JSON Merge Patch to the given document.
+func MergePatch(document, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+
+	return json.Marshal(merge(target, changes))
+}
+
+func merge(target, changes interface{}) interface{} {
+	patchObject, ok := changes.(map[string]interface{})
+	if !ok {
+		return changes
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = merge(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+// JSONPatch applies a RFC 6902 JSON Patch to the given document. Operations
+// are applied in order and the whole patch fails if any of them fails.
+func JSONPatch(document, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+
+	var operations []Operation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+
+	for i, operation := range operations {
+		result, err := apply(target, operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+		target = result
+	}
+
+	return json.Marshal(target)
+}
+
+func apply(document interface{}, operation Operation) (interface{}, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add":
+		value, err := operation.value()
+		if err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+	case "remove":
+		return remove(document, path)
+	case "replace":
+		value, err := operation.value()
+		if err != nil {
+			return nil, err
+		}
+		if document, err = remove(document, path); err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+	case "move":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(document, from)
+		if err != nil {
+			return nil, err
+		}
+		if document, err = remove(document, from); err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+	case "copy":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(document, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(document, path, value)
+	case "test":
+		value, err := operation.value()
+		if err != nil {
+			return nil, err
+		}
+		current, err := get(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, value) {
+			return nil, fmt.Errorf("%w: %s", ErrTestFailed, operation.Path)
+		}
+		return document, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, operation.Op)
+}
+
+func (o Operation) value() (interface{}, error) {
+	if o.Value == nil {
+		return nil, fmt.Errorf("%w: missing value for %q", ErrInvalidPatch, o.Op)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(*o.Value, &value); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPatch, err.Error())
+	}
+
+	return value, nil
+}
+
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: invalid pointer %q", ErrInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+func get(document interface{}, path []string) (interface{}, error) {
+	current := document
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: path %q does not exist", ErrInvalidPatch, token)
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("%w: path %q does not exist", ErrInvalidPatch, token)
+		}
+	}
+
+	return current, nil
+}
+
+func add(document interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(document, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return document, nil
+	case []interface{}:
+		index := len(node)
+		if last != "-" {
+			if index, err = arrayIndex(last, len(node)); err != nil {
+				return nil, err
+			}
+		}
+		node = append(node[:index], append([]interface{}{value}, node[index:]...)...)
+		return set(document, path[:len(path)-1], node)
+	}
+
+	return nil, fmt.Errorf("%w: cannot add to %q", ErrInvalidPatch, last)
+}
+
+func remove(document interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	parent, err := get(document, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := node[last]; !ok {
+			return nil, fmt.Errorf("%w: path %q does not exist", ErrInvalidPatch, last)
+		}
+		delete(node, last)
+		return document, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node = append(node[:index:index], node[index+1:]...)
+		return set(document, path[:len(path)-1], node)
+	}
+
+	return nil, fmt.Errorf("%w: cannot remove %q", ErrInvalidPatch, last)
+}
+
+// set replaces the value at path, which is needed when an array changes length.
+func set(document interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(document, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+	case []interface{}:
+		index, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = value
+	}
+
+	return document, nil
+}
+
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, token)
+	}
+
+	return index, nil
+}
@@ -0,0 +1,75 @@
+package patch_test
+
+import (
+	"testing"
+
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/patch"
+	"github.com/stretchr/testify/assert"
+)
+
+const document = `{"id":1,"name":"Section","capacity":10,"tags":["a","b"],"nested":{"value":1}}`
+
+func TestMergePatch(t *testing.T) {
+	t.Run("Should replace and add members", func(t *testing.T) {
+		result, err := patch.MergePatch([]byte(document), []byte(`{"capacity":0,"nested":{"other":2}}`))
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"id":1,"name":"Section","capacity":0,"tags":["a","b"],"nested":{"value":1,"other":2}}`, string(result))
+	})
+
+	t.Run("Should remove members set to null", func(t *testing.T) {
+		result, err := patch.MergePatch([]byte(document), []byte(`{"name":null,"tags":null}`))
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"id":1,"capacity":10,"nested":{"value":1}}`, string(result))
+	})
+
+	t.Run("Should return invalid patch error when patch is malformed", func(t *testing.T) {
+		_, err := patch.MergePatch([]byte(document), []byte(`{"name":`))
+
+		assert.ErrorIs(t, err, patch.ErrInvalidPatch)
+	})
+}
+
+func TestJSONPatch(t *testing.T) {
+	t.Run("Should apply every operation in order", func(t *testing.T) {
+		operations := `[
+			{"op":"test","path":"/capacity","value":10},
+			{"op":"replace","path":"/capacity","value":0},
+			{"op":"remove","path":"/name"},
+			{"op":"add","path":"/tags/1","value":"c"},
+			{"op":"add","path":"/tags/-","value":"d"},
+			{"op":"copy","from":"/nested/value","path":"/copied"},
+			{"op":"move","from":"/nested","path":"/moved"}
+		]`
+
+		result, err := patch.JSONPatch([]byte(document), []byte(operations))
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"id":1,"capacity":0,"tags":["a","c","b","d"],"copied":1,"moved":{"value":1}}`, string(result))
+	})
+
+	t.Run("Should return test failed error when values differ", func(t *testing.T) {
+		_, err := patch.JSONPatch([]byte(document), []byte(`[{"op":"test","path":"/capacity","value":5}]`))
+
+		assert.ErrorIs(t, err, patch.ErrTestFailed)
+	})
+
+	t.Run("Should return invalid patch error when path does not exist", func(t *testing.T) {
+		_, err := patch.JSONPatch([]byte(document), []byte(`[{"op":"remove","path":"/unknown"}]`))
+
+		assert.ErrorIs(t, err, patch.ErrInvalidPatch)
+	})
+
+	t.Run("Should return invalid patch error when operation is unknown", func(t *testing.T) {
+		_, err := patch.JSONPatch([]byte(document), []byte(`[{"op":"swap","path":"/name"}]`))
+
+		assert.ErrorIs(t, err, patch.ErrInvalidPatch)
+	})
+
+	t.Run("Should return invalid patch error when value is missing", func(t *testing.T) {
+		_, err := patch.JSONPatch([]byte(document), []byte(`[{"op":"add","path":"/name"}]`))
+
+		assert.ErrorIs(t, err, patch.ErrInvalidPatch)
+	})
+}
@@ -0,0 +1,240 @@
+package web
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	CSVMediaType      = "text/csv"
+	NDJSONMediaType   = "application/x-ndjson"
+	NDJSONContentType = "application/x-ndjson; charset=utf-8"
+	JSONContentType   = "application/json; charset=utf-8"
+
+	csvPlainValueField = "value"
+)
+
+// Format returns the media type the client asked for through the Accept
+// header: JSON, CSV or NDJSON. JSON is the default.
+func Format(c *gin.Context) string {
+	if c.Request == nil {
+		return binding.MIMEJSON
+	}
+
+	format := c.NegotiateFormat(binding.MIMEJSON, CSVMediaType, NDJSONMediaType)
+	if format == "" {
+		return binding.MIMEJSON
+	}
+	return format
+}
+
+// Streaming tells whether the client asked for a format that is written row by
+// row, so the handler can use Stream instead of building the whole response.
+func Streaming(c *gin.Context) bool {
+	return Format(c) != binding.MIMEJSON
+}
+
+// Stream writes the rows yielded by each as CSV or NDJSON, as they come,
+// without holding them in memory. The CSV header is taken from the JSON tags
+// of T, so it is written even when there are no rows.
+func Stream[T any](c *gin.Context, status int, each func(yield func(T))) {
+	format := Format(c)
+	c.Header("Content-Type", ContentType(format))
+	c.Status(status)
+
+	write, flush := rowWriter(c.Writer, format, reflect.TypeOf((*T)(nil)).Elem())
+	each(func(row T) {
+		write(reflect.ValueOf(row))
+	})
+	flush()
+	c.Writer.Flush()
+}
+
+// Marshal returns data, a struct or a slice of them, in the format, one of the
+// media types Format returns. JSON is written without the response envelope.
+func Marshal(format string, data interface{}) []byte {
+	if format == binding.MIMEJSON {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			panic(err)
+		}
+		return encoded
+	}
+
+	var buffer bytes.Buffer
+	encode(&buffer, format, data)
+	return buffer.Bytes()
+}
+
+// ContentType returns the Content-Type header of the format.
+func ContentType(format string) string {
+	switch format {
+	case CSVMediaType:
+		return CSVContentType
+	case NDJSONMediaType:
+		return NDJSONContentType
+	default:
+		return JSONContentType
+	}
+}
+
+// export writes data in the format the client negotiated.
+func export(c *gin.Context, status int, data interface{}) {
+	format := Format(c)
+	c.Header("Content-Type", ContentType(format))
+	c.Status(status)
+
+	encode(c.Writer, format, data)
+	c.Writer.Flush()
+}
+
+// encode writes data, a struct or a slice of them, as CSV or NDJSON rows.
+func encode(w io.Writer, format string, data interface{}) {
+	value := reflect.ValueOf(data)
+	if !value.IsValid() {
+		return
+	}
+
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		write, flush := rowWriter(w, format, value.Type())
+		write(value)
+		flush()
+		return
+	}
+
+	write, flush := rowWriter(w, format, value.Type().Elem())
+	for i := 0; i < value.Len(); i++ {
+		write(value.Index(i))
+	}
+	flush()
+}
+
+// rowWriter returns the functions that write each row of type t to w and
+// flush what is left once done.
+func rowWriter(w io.Writer, format string, t reflect.Type) (func(reflect.Value), func()) {
+	if format == NDJSONMediaType {
+		encoder := json.NewEncoder(w)
+		return func(row reflect.Value) {
+			_ = encoder.Encode(row.Interface())
+		}, func() {}
+	}
+
+	csvWriter := csv.NewWriter(w)
+	_ = csvWriter.Write(csvHeader(t))
+
+	return func(row reflect.Value) {
+		_ = csvWriter.Write(csvRecord(row))
+	}, csvWriter.Flush
+}
+
+// csvHeader returns the JSON names of the fields of t. Types other than
+// structs are written in a single column.
+func csvHeader(t reflect.Type) []string {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return []string{csvPlainValueField}
+	}
+
+	header := make([]string, 0, t.NumField())
+	for _, field := range csvFields(t) {
+		header = append(header, field.name)
+	}
+	return header
+}
+
+func csvRecord(v reflect.Value) []string {
+	v = indirectValue(v)
+	if !v.IsValid() {
+		return []string{""}
+	}
+	if v.Kind() != reflect.Struct {
+		return []string{csvCell(v)}
+	}
+
+	fields := csvFields(v.Type())
+	record := make([]string, 0, len(fields))
+	for _, field := range fields {
+		record = append(record, csvCell(v.FieldByIndex(field.index)))
+	}
+	return record
+}
+
+// csvCell formats the value as it appears in the JSON response, without the
+// quotes of strings. Nested objects and lists are kept as JSON.
+func csvCell(v reflect.Value) string {
+	v = indirectValue(v)
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+
+	encoded, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+
+	var text string
+	if json.Unmarshal(encoded, &text) == nil {
+		return text
+	}
+	return string(encoded)
+}
+
+type csvField struct {
+	name  string
+	index []int
+}
+
+// csvFields lists the exported fields of t under their JSON name, flattening
+// embedded structs as encoding/json does.
+func csvFields(t reflect.Type) []csvField {
+	fields := make([]csvField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		embedded := field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct
+		if name == "-" || (!field.IsExported() && !embedded) {
+			continue
+		}
+
+		if embedded {
+			for _, inner := range csvFields(field.Type) {
+				inner.index = append([]int{i}, inner.index...)
+				fields = append(fields, inner)
+			}
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, csvField{name: name, index: []int{i}})
+	}
+	return fields
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
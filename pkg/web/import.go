@@ -0,0 +1,14 @@
+package web
+
+// ImportRow is a row of a bulk import, with the line of the file it was read
+// from and the errors found when validating it.
+type ImportRow[T any] struct {
+	Line   int
+	Value  T
+	Errors []FieldError
+}
+
+// Valid tells whether the row passed validation.
+func (r ImportRow[T]) Valid() bool {
+	return len(r.Errors) == 0
+}
@@ -1,23 +1,56 @@
 package web
 
 import (
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/apperr"
+	"github.com/extmatperez/meli_bootcamp_go_w2-1/pkg/i18n"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+	ProblemDefaultType = "about:blank"
+	ErrorFormatHeader  = "X-Error-Format"
+	LanguageHeader     = "Accept-Language"
+	LegacyErrorFormat  = "legacy"
+	CSVContentType     = "text/csv; charset=utf-8"
 )
 
 type response struct {
 	Data interface{} `json:"data"`
 }
 
+// ErrorResponse is the legacy error shape, still sent to clients that ask for
+// it through the X-Error-Format header.
 type ErrorResponse struct {
 	Status   int      `json:"-"`
 	Code     string   `json:"code"`
 	Messages []string `json:"messages"`
 }
 
+// ProblemDetails is an RFC 7807 error response.
+type ProblemDetails struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
 type Data struct {
 	Code  string      `json:"code"`
 	Data  interface{} `json:"data,omitempty"`
@@ -28,18 +61,91 @@ func Response(c *gin.Context, status int, data interface{}) {
 	c.JSON(status, data)
 }
 
+// Success writes data wrapped in a JSON envelope, or as CSV or NDJSON rows when
+// the client asks for them through the Accept header.
 func Success(c *gin.Context, status int, data interface{}) {
+	if Streaming(c) {
+		export(c, status, data)
+		return
+	}
+
 	Response(c, status, response{Data: data})
 }
 
-func Error(c *gin.Context, status int, format string, args ...interface{}) {
-	err := ErrorResponse{
-		Code: strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"),
-		Messages: []string{
-			fmt.Sprintf(format, args...),
-		},
+// CSV writes the records as a CSV file the client downloads under filename.
+func CSV(c *gin.Context, status int, filename string, records [][]string) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", CSVContentType)
+	c.Status(status)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.WriteAll(records)
+}
+
+// Error writes an error response whose message is looked up in the catalog by
+// key, in the language asked for by the client.
+func Error(c *gin.Context, status int, key string, args ...interface{}) {
+	Errors(c, status, i18n.Translate(Locale(c), key, args...), nil)
+}
+
+// ErrorFrom writes an error response for err, localizing its message when the
+// error supports it.
+func ErrorFrom(c *gin.Context, status int, err error) {
+	Errors(c, status, Localize(Locale(c), err), nil)
+}
+
+// Localize returns the message of err in the language of the locale when the
+// error supports it.
+func Localize(locale language.Tag, err error) string {
+	var localizable apperr.Localizable
+	if errors.As(err, &localizable) {
+		return localizable.Localize(locale)
+	}
+
+	return err.Error()
+}
+
+func Locale(c *gin.Context) language.Tag {
+	return i18n.LocaleFrom(c.GetHeader(LanguageHeader))
+}
+
+// Errors writes an error response with field level details. Legacy clients
+// receive one message per field, or the detail when there are no fields.
+func Errors(c *gin.Context, status int, detail string, fields []FieldError) {
+	c.Header("Content-Language", Locale(c).String())
+
+	if c.GetHeader(ErrorFormatHeader) == LegacyErrorFormat {
+		messages := make([]string, 0)
+		for _, field := range fields {
+			messages = append(messages, field.Message)
+		}
+		if len(messages) == 0 {
+			messages = append(messages, detail)
+		}
+
+		Response(c, status, ErrorResponse{
+			Code:     codeFrom(status),
+			Messages: messages,
+			Status:   status,
+		})
+		return
+	}
+
+	problem := ProblemDetails{
+		Type:   ProblemDefaultType,
+		Title:  http.StatusText(status),
 		Status: status,
+		Detail: detail,
+		Errors: fields,
 	}
+	if c.Request != nil && c.Request.URL != nil {
+		problem.Instance = c.Request.URL.Path
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	Response(c, status, problem)
+}
 
-	Response(c, status, err)
+func codeFrom(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
 }